@@ -0,0 +1,24 @@
+// Command wellknown is the unified CLI for the wellknown toolkit.
+//
+// See pkg/cmd/root for the command tree.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joeblew999/wellknown/pkg/cmd/root"
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+func main() {
+	// Load .env < .env.local < .env.$APP_ENV (same as the PocketBase binary)
+	if _, err := env.LoadChain(env.DefaultChainFiles...); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to load .env files: %v\n", err)
+	}
+
+	// Failures of pkg/env exit with their category's code (see env.ExitCode)
+	if err := root.NewCommand().Execute(); err != nil {
+		os.Exit(env.ExitCode(err))
+	}
+}
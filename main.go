@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"log"
@@ -9,9 +12,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
-	"github.com/joho/godotenv"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/plugins/ghupdate"
 	"github.com/pocketbase/pocketbase/plugins/jsvm"
@@ -19,31 +23,25 @@ import (
 	"github.com/pocketbase/pocketbase/tools/archive"
 	"github.com/pocketbase/pocketbase/tools/osutils"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/acme/autocert"
 
+	"github.com/joeblew999/wellknown/pkg/certs"
 	_ "github.com/joeblew999/wellknown/pkg/cmd/pocketbase/pb_migrations" // Import migrations
+	certscmd "github.com/joeblew999/wellknown/pkg/cmd/certs"
+	envcmd "github.com/joeblew999/wellknown/pkg/cmd/env"
+	"github.com/joeblew999/wellknown/pkg/cmd/events"
 	"github.com/joeblew999/wellknown/pkg/cmd/mcp"
+	portcmd "github.com/joeblew999/wellknown/pkg/cmd/port"
+	schemacmd "github.com/joeblew999/wellknown/pkg/cmd/schema"
 	testdatagen "github.com/joeblew999/wellknown/pkg/cmd/testdata-gen"
 	wellknown "github.com/joeblew999/wellknown/pkg/pb"
 )
 
 func main() {
-	// Load .env.local if it exists (for local development)
-	// Silently ignore if file doesn't exist (production uses real env vars)
-	_ = godotenv.Load(".env.local")
-
-	// Check if this is a utility command that doesn't need validation
-	// (env list/validate/generate commands should work even without credentials)
-	isUtilityCommand := len(os.Args) >= 2 &&
-		(os.Args[1] == "env" || os.Args[1] == "mcp" || os.Args[1] == "testdata-gen" ||
-		 os.Args[1] == "help" || os.Args[1] == "--help" || os.Args[1] == "-h")
-
-	// Only validate for serve/migrate commands (not utility commands)
-	if !isUtilityCommand {
-		if err := wellknown.ValidateEnv(); err != nil {
-			log.Fatalf("❌ Environment validation failed: %v\n\n"+
-				"💡 Run 'go run . env list' to see all required variables\n"+
-				"💡 Run 'go run . env validate' for detailed validation", err)
-		}
+	// Load .env < .env.local < .env.$APP_ENV with provenance logging
+	// (required variables are validated when the server starts)
+	if _, err := wellknown.LoadEnvironment(); err != nil {
+		log.Printf("⚠️  %v", err)
 	}
 
 	// Load configuration
@@ -165,15 +163,23 @@ func main() {
 	}
 
 	// 4. Register custom utility commands (order-independent)
-	app.RootCmd.AddCommand(newEnvCommand(app))    // Environment variable management
+	app.RootCmd.AddCommand(envcmd.NewCommand())   // Environment variable management
 	app.RootCmd.AddCommand(mcp.NewCommand())      // MCP server for Claude Desktop
 	app.RootCmd.AddCommand(testdatagen.NewCommand()) // Test data generation
+	app.RootCmd.AddCommand(events.NewCommand(app))   // Calendar event import/export
+	app.RootCmd.AddCommand(portcmd.NewCommand())     // Port kill/wait for Makefile and e2e
+	app.RootCmd.AddCommand(certscmd.NewCommand())    // Development HTTPS certificates
+
+	// JSON Schema -> collections, and drift of the live ones (schema drift)
+	schemaCmd := schemacmd.NewCommand()
+	schemaCmd.AddCommand(schemacmd.NewDriftCommand(app))
+	app.RootCmd.AddCommand(schemaCmd)
 
-	// 5. Configure TLS if HTTPS is enabled (development only with mkcert)
-	// Production uses Fly.io's native Let's Encrypt HTTPS
+	// 5. Configure TLS if HTTPS is enabled (local CA in development, ACME when self-hosting)
+	// Fly.io deployments leave this off and use Fly's native Let's Encrypt HTTPS
 	// This registers an OnServe hook, so it must come after all command registration
 	if wellknown.EnvRegistry.ByName("HTTPS_ENABLED").GetBool() {
-		if err := configureTLS(app, cfg); err != nil {
+		if err := configureTLS(wk, cfg); err != nil {
 			log.Fatalf("Failed to configure TLS: %v", err)
 		}
 	}
@@ -185,12 +191,23 @@ func main() {
 	}
 }
 
-// configureTLS sets up HTTPS with custom certificates for development
-func configureTLS(app core.App, cfg *wellknown.Config) error {
+// certExpiryWarning is how long before expiry the TLS certificate degrades /health
+const certExpiryWarning = 14 * 24 * time.Hour
+
+// configureTLS sets up HTTPS: Let's Encrypt when ACME_DOMAINS is set,
+// otherwise custom certificates for development
+func configureTLS(wk *wellknown.Wellknown, cfg *wellknown.Config) error {
+	if domains := acmeDomains(); len(domains) > 0 {
+		return configureACME(wk, domains)
+	}
+
 	certFile := wellknown.EnvRegistry.ByName("CERT_FILE").GetString()
 	keyFile := wellknown.EnvRegistry.ByName("KEY_FILE").GetString()
 
-	// Load certificate and private key
+	// Generate (or renew) certificates from the local CA, then load them
+	if err := certs.Ensure(certFile, keyFile); err != nil {
+		return fmt.Errorf("failed to generate TLS certificates: %w", err)
+	}
 	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
 	if err != nil {
 		return fmt.Errorf("failed to load TLS certificates (run: make certs-generate): %w", err)
@@ -199,11 +216,23 @@ func configureTLS(app core.App, cfg *wellknown.Config) error {
 	log.Println("🔐 TLS Configuration:")
 	log.Printf("   • Certificate: %s", certFile)
 	log.Printf("   • Private Key: %s", keyFile)
-	log.Println("   • Mode: Development (mkcert)")
+	log.Printf("   • Mode: Development (local CA in %s)", certs.CARoot())
 	log.Println("   ⚠️  DO NOT USE IN PRODUCTION")
 
+	// A certificate about to expire degrades /health (local certs are renewed on restart)
+	wk.Health().RegisterOptional("tls_certificate", func(ctx context.Context) error {
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return err
+		}
+		if remaining := time.Until(leaf.NotAfter); remaining < certExpiryWarning {
+			return fmt.Errorf("certificate %s expires %s", certFile, leaf.NotAfter.Format(time.DateOnly))
+		}
+		return nil
+	})
+
 	// Register OnServe hook to configure TLS and show custom banner
-	app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+	wk.OnServe().BindFunc(func(e *core.ServeEvent) error {
 		// Configure TLS
 		e.Server.TLSConfig = &tls.Config{
 			Certificates: []tls.Certificate{cert},
@@ -219,6 +248,82 @@ func configureTLS(app core.App, cfg *wellknown.Config) error {
 	return nil
 }
 
+// acmeDomains returns the non-empty entries of the comma-separated ACME_DOMAINS
+func acmeDomains() []string {
+	var domains []string
+	for _, d := range strings.Split(wellknown.EnvRegistry.ByName("ACME_DOMAINS").GetString(), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// configureACME obtains and renews certificates from Let's Encrypt for
+// self-hosted deployments. Challenges are answered over TLS-ALPN-01, so the
+// server must be reachable on port 443 (serve --https=0.0.0.0:443).
+func configureACME(wk *wellknown.Wellknown, domains []string) error {
+	cacheDir := wellknown.EnvRegistry.ByName("ACME_CACHE_DIR").GetString()
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return fmt.Errorf("failed to create ACME cache directory: %w", err)
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      wellknown.EnvRegistry.ByName("ACME_EMAIL").GetString(),
+	}
+
+	log.Println("🔐 TLS Configuration:")
+	log.Printf("   • Domains: %s", strings.Join(domains, ", "))
+	log.Printf("   • Cache: %s", cacheDir)
+	log.Println("   • Mode: ACME (Let's Encrypt)")
+
+	// autocert renews 30 days ahead, so a certificate this close to expiry means renewal is failing
+	wk.Health().RegisterOptional("tls_certificate", func(ctx context.Context) error {
+		leaf, err := cachedACMELeaf(ctx, manager.Cache, domains[0])
+		if errors.Is(err, autocert.ErrCacheMiss) {
+			return fmt.Errorf("certificate for %s not issued yet", domains[0])
+		}
+		if err != nil {
+			return err
+		}
+		if remaining := time.Until(leaf.NotAfter); remaining < certExpiryWarning {
+			return fmt.Errorf("certificate for %s expires %s", domains[0], leaf.NotAfter.Format(time.DateOnly))
+		}
+		return nil
+	})
+
+	wk.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		e.Server.TLSConfig = manager.TLSConfig()
+		e.Server.TLSConfig.MinVersion = tls.VersionTLS12
+
+		showCustomBanner(e.Server.Addr, true)
+
+		return e.Next()
+	})
+
+	return nil
+}
+
+// cachedACMELeaf returns the certificate autocert serves to clients for domain
+// (the ECDSA one, cached under the bare domain) without going through
+// GetCertificate, which would start a new order on a cache miss. Cache
+// entries are the PEM private key followed by the certificate chain.
+func cachedACMELeaf(ctx context.Context, cache autocert.Cache, domain string) (*x509.Certificate, error) {
+	data, err := cache.Get(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+	return nil, fmt.Errorf("no certificate in the ACME cache entry for %s", domain)
+}
+
 // showCustomBanner displays server info with functional URLs
 func showCustomBanner(addr string, isHTTPS bool) {
 	protocol := "http"
@@ -274,246 +379,6 @@ func getLocalIP() string {
 	return "localhost"
 }
 
-// ---------------------------------------------------------------
-// Environment Variable Management Command
-// ---------------------------------------------------------------
-
-func newEnvCommand(app core.App) *cobra.Command {
-	envCmd := &cobra.Command{
-		Use:   "env",
-		Short: "Environment variable management",
-		Long:  "Manage and export environment variables for deployment",
-	}
-
-	// Sub-command: env export-secrets
-	exportCmd := &cobra.Command{
-		Use:   "export-secrets",
-		Short: "Export secrets for flyctl secrets import",
-		Long: `Export environment variables marked as secrets in NAME=VALUE format.
-This output can be piped directly to 'flyctl secrets import'.
-
-Example:
-  . ./.env && ./wellknown env export-secrets | flyctl secrets import`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			output := wellknown.ExportSecretsFormat()
-			if output == "" {
-				fmt.Fprintln(os.Stderr, "⚠️  No secrets found in environment")
-				return fmt.Errorf("no secrets found in environment")
-			}
-			fmt.Print(output)
-			return nil
-		},
-	}
-
-	// Sub-command: env list
-	listCmd := &cobra.Command{
-		Use:   "list",
-		Short: "List all environment variables and their status",
-		Long: `Display all registered environment variables with their current values.
-Secret values are masked for security.
-
-This shows the complete environment variable registry from pkg/pb/env.go.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Print(wellknown.ListEnvVars())
-			return nil
-		},
-	}
-
-	// Sub-command: env validate
-	validateCmd := &cobra.Command{
-		Use:   "validate",
-		Short: "Validate required environment variables",
-		Long: `Check if all required environment variables are set.
-Returns an error if any required variables are missing.
-
-Required variables are marked with Required: true in pkg/pb/env.go.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := wellknown.ValidateEnv(); err != nil {
-				fmt.Fprintf(os.Stderr, "❌ Validation failed: %v\n", err)
-				return err
-			}
-			fmt.Println("✅ All required environment variables are set")
-			return nil
-		},
-	}
-
-	// Sub-command: env sync-dockerfile
-	var dockerfileDryRun bool
-	syncDockerfileCmd := &cobra.Command{
-		Use:   "sync-dockerfile",
-		Short: "Sync environment variable documentation to Dockerfile",
-		Long: `Updates the Dockerfile environment variable section with current registry.
-Preserves Dockerfile structure, only updates the env vars comment block.
-
-The Dockerfile must contain the marker comments for this to work.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := wellknown.SyncDockerfileEnvDocs("Dockerfile", dockerfileDryRun); err != nil {
-				fmt.Fprintf(os.Stderr, "❌ Failed to sync Dockerfile: %v\n", err)
-				return err
-			}
-			if dockerfileDryRun {
-				fmt.Println("✅ Dry run complete (no changes made)")
-			} else {
-				fmt.Println("✅ Dockerfile environment documentation updated")
-			}
-			return nil
-		},
-	}
-	syncDockerfileCmd.Flags().BoolVarP(&dockerfileDryRun, "dry-run", "n", false, "Preview changes without writing")
-
-	// Sub-command: env sync-flytoml
-	var flytomlDryRun bool
-	syncFlyTomlCmd := &cobra.Command{
-		Use:   "sync-flytoml",
-		Short: "Sync non-secret environment variables to fly.toml",
-		Long: `Updates fly.toml [env] section with non-secret environment variables.
-Only includes variables where Secret=false.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := wellknown.SyncFlyTomlEnv("fly.toml", flytomlDryRun); err != nil {
-				fmt.Fprintf(os.Stderr, "❌ Failed to sync fly.toml: %v\n", err)
-				return err
-			}
-			if flytomlDryRun {
-				fmt.Println("✅ Dry run complete (no changes made)")
-			} else {
-				fmt.Println("✅ fly.toml [env] section updated")
-			}
-			return nil
-		},
-	}
-	syncFlyTomlCmd.Flags().BoolVarP(&flytomlDryRun, "dry-run", "n", false, "Preview changes without writing")
-
-	// Sub-command: env generate-local
-	generateLocalCmd := &cobra.Command{
-		Use:   "generate-local",
-		Short: "Generate .env.local template",
-		Long: `Generates .env.local template with development-specific defaults.
-Includes HTTPS_ENABLED=true and localhost OAuth URLs.
-
-This will overwrite any existing .env.local file.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			content := wellknown.GenerateEnvLocal()
-			if err := os.WriteFile(".env.local", []byte(content), 0644); err != nil {
-				fmt.Fprintf(os.Stderr, "❌ Failed to write .env.local: %v\n", err)
-				return err
-			}
-			fmt.Println("✅ .env.local generated")
-			fmt.Println("💡 Configure your OAuth credentials before running the server")
-			return nil
-		},
-	}
-
-	// Sub-command: env generate-production
-	generateProductionCmd := &cobra.Command{
-		Use:   "generate-production",
-		Short: "Generate .env.production template",
-		Long: `Generates .env.production template with production-specific defaults.
-Includes HTTPS_ENABLED=false (Fly.io handles TLS) and production OAuth URLs.
-
-This will overwrite any existing .env.production file.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			content := wellknown.GenerateEnvProduction()
-			if err := os.WriteFile(".env.production", []byte(content), 0644); err != nil {
-				fmt.Fprintf(os.Stderr, "❌ Failed to write .env.production: %v\n", err)
-				return err
-			}
-			fmt.Println("✅ .env.production generated")
-			fmt.Println("💡 Configure your production OAuth credentials before deploying")
-			return nil
-		},
-	}
-
-	// Sub-command: env generate-example
-	generateExampleCmd := &cobra.Command{
-		Use:   "generate-example",
-		Short: "Generate .env.example template",
-		Long: `Generates .env.example template with placeholder values (safe to commit).
-This file shows all available environment variables without real credentials.
-
-This will overwrite any existing .env.example file.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			content := wellknown.GenerateEnvExample()
-			if err := os.WriteFile(".env.example", []byte(content), 0644); err != nil {
-				fmt.Fprintf(os.Stderr, "❌ Failed to write .env.example: %v\n", err)
-				return err
-			}
-			fmt.Println("✅ .env.example generated")
-			fmt.Println("💡 This file is safe to commit to version control")
-			return nil
-		},
-	}
-
-	// Sub-command: env sync-secrets
-	syncSecretsCmd := &cobra.Command{
-		Use:   "sync-secrets",
-		Short: "Decrypt and merge .env.secrets[.age] into .env.local",
-		Long: `Merges git-tracked secrets (.env.secrets or .env.secrets.age) into .env.local for local development.
-
-This command:
-1. Reads your credentials from .env.secrets or .env.secrets.age (auto-decrypts if .age)
-2. Generates .env.local template with localhost URLs
-3. Merges your secrets into the template
-4. Writes to .env.local (ready for local development)
-
-Workflow:
-  cp .env.secrets.example .env.secrets
-  # Edit .env.secrets with real credentials
-  # Optional: Encrypt with Age: age -e -r YOUR_PUBLIC_KEY .env.secrets > .env.secrets.age
-  make env-sync-secrets
-  make run`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := wellknown.MergeSecretsIntoEnv(".env.secrets", "local", ".env.local"); err != nil {
-				return err
-			}
-			fmt.Println("✅ .env.local generated from .env.secrets")
-			fmt.Println("💡 Your local development environment is ready!")
-			fmt.Println("   Run: make run")
-			return nil
-		},
-	}
-
-	// Sub-command: env sync-secrets-production
-	syncSecretsProductionCmd := &cobra.Command{
-		Use:   "sync-secrets-production",
-		Short: "Decrypt and merge .env.secrets[.age] into .env.production",
-		Long: `Merges git-tracked secrets (.env.secrets or .env.secrets.age) into .env.production for Fly.io deployment.
-
-This command:
-1. Reads your credentials from .env.secrets or .env.secrets.age (auto-decrypts if .age)
-2. Generates .env.production template with fly.dev URLs
-3. Merges your secrets into the template
-4. Writes to .env.production (ready for Fly.io deployment)
-
-Workflow:
-  make env-sync-secrets-production
-  make fly-secrets  # Push to Fly.io
-  make fly-deploy`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := wellknown.MergeSecretsIntoEnv(".env.secrets", "production", ".env.production"); err != nil {
-				return err
-			}
-			fmt.Println("✅ .env.production generated from .env.secrets")
-			fmt.Println("💡 Ready to deploy to Fly.io!")
-			fmt.Println("   Next: make fly-secrets")
-			return nil
-		},
-	}
-
-	envCmd.AddCommand(
-		exportCmd,
-		listCmd,
-		validateCmd,
-		syncDockerfileCmd,
-		syncFlyTomlCmd,
-		generateLocalCmd,
-		generateProductionCmd,
-		generateExampleCmd,
-		syncSecretsCmd,
-		syncSecretsProductionCmd,
-	)
-	return envCmd
-}
-
 // ---------------------------------------------------------------
 // Local Update Command (for development/testing)
 // ---------------------------------------------------------------
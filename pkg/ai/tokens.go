@@ -11,7 +11,8 @@ import (
 // GoogleTokenGetter creates a function that retrieves Google OAuth tokens from PocketBase
 // This is for accessing Google APIs (Calendar, etc.) NOT for Anthropic AI.
 // Returns a function that can be called to get the current Google access token.
-func GoogleTokenGetter(app core.App, userID string) func() (string, error) {
+// Stored tokens may be encrypted; open decrypts them (pass Wellknown.OpenToken).
+func GoogleTokenGetter(app core.App, userID string, open func(string) (string, error)) func() (string, error) {
 	return func() (string, error) {
 		// Query google_tokens collection for the user's token
 		record, err := app.FindFirstRecordByFilter(
@@ -33,13 +34,16 @@ func GoogleTokenGetter(app core.App, userID string) func() (string, error) {
 
 		// Check if token is expired (with 1-minute buffer)
 		if time.Now().After(expiry.Time().Add(-1 * time.Minute)) {
-			// TODO: Implement token refresh
-			// For now, return error - refresh should be handled by OAuth flow
+			// The token refresher (pkg/pb) renews tokens before this; one that
+			// expired could not be refreshed
 			return "", fmt.Errorf("token expired at %v", expiry.Time())
 		}
 
 		// Return access token
-		accessToken := record.GetString("access_token")
+		accessToken, err := open(record.GetString("access_token"))
+		if err != nil {
+			return "", err
+		}
 		if accessToken == "" {
 			return "", fmt.Errorf("access token is empty")
 		}
@@ -79,6 +79,12 @@ func GenerateDataURI(data map[string]interface{}) (string, error) {
 // GenerateICS creates an ICS file from validated form data.
 // Returns raw ICS bytes that can be served as a download or encoded as data URI.
 func GenerateICS(data map[string]interface{}) ([]byte, error) {
+	return GenerateICSAt(data, time.Now())
+}
+
+// GenerateICSAt is GenerateICS stamped (UID, DTSTAMP) with now instead of the
+// current time, so the output is reproducible
+func GenerateICSAt(data map[string]interface{}, now time.Time) ([]byte, error) {
 	// Extract required fields from validated data
 	title, ok := data[FieldTitle].(string)
 	if !ok || title == "" {
@@ -124,8 +130,8 @@ func GenerateICS(data map[string]interface{}) ([]byte, error) {
 
 	// Event
 	buf.WriteString(ICSBeginEvent + "\r\n")
-	buf.WriteString(fmt.Sprintf("UID:%d@wellknown\r\n", time.Now().Unix()))
-	buf.WriteString(fmt.Sprintf("DTSTAMP:%s\r\n", formatICSTime(time.Now())))
+	buf.WriteString(fmt.Sprintf("UID:%d@wellknown\r\n", now.Unix()))
+	buf.WriteString(fmt.Sprintf("DTSTAMP:%s\r\n", formatICSTime(now)))
 
 	// Date/time fields
 	if allDay {
@@ -32,6 +32,8 @@ const (
 	FieldStatus     = "status"      // Event status (confirmed, tentative, cancelled)
 	FieldPriority   = "priority"    // Event priority (low, medium, high)
 	FieldURL        = "url"         // Associated URL
+
+	FieldVideoConference = "videoConference" // Video conferencing hint (e.g., "googleMeet")
 )
 
 // BasicFields lists the minimum required fields for a calendar event.
@@ -167,4 +169,11 @@ var CommonFieldMetadata = map[string]FieldMetadata{
 		Description: "Event reminders/notifications",
 		Advanced:    true,
 	},
+	FieldVideoConference: {
+		Name:        FieldVideoConference,
+		Type:        TypeString,
+		Required:    false,
+		Description: "Video conference to attach to the event",
+		Advanced:    true,
+	},
 }
@@ -49,6 +49,7 @@ type EventData struct {
 	End   time.Time
 
 	// Optional basic fields
+	UID         string // Stable identifier across imports and exports (ICS UID)
 	Location    string
 	Description string
 	AllDay      bool
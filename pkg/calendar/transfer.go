@@ -0,0 +1,337 @@
+package calendar
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ================================================================
+// CSV and ICS Import/Export
+// ================================================================
+// Bulk transfer of events, e.g. when onboarding an existing calendar. Readers
+// never stop at a bad event: each ImportedEvent carries its own parse error,
+// so callers can import the rest and report the failures by row.
+//
+// CSV files have a header row naming any of CSVColumns (in any order; title
+// and start are required). Times are RFC 3339, datetime-local
+// ("2006-01-02T15:04") or "2006-01-02 15:04"; a bare date makes an all-day
+// event, whose end is exclusive as in ICS. Times without an offset are UTC,
+// as are ICS floating times.
+
+// ICS date formats (DATE-TIME values add a Z for UTC)
+const (
+	DateFormatICS     = "20060102"
+	DateTimeFormatICS = "20060102T150405"
+)
+
+// CSVColumns are the columns ReadCSV understands and WriteCSV writes
+var CSVColumns = []string{"uid", FieldTitle, FieldStart, FieldEnd, FieldAllDay, FieldLocation, FieldDescription}
+
+// ImportedEvent is one event read from a CSV or ICS file
+type ImportedEvent struct {
+	EventData
+	Row int   // 1-based CSV data row or VEVENT number
+	Err error // Why the event could not be read (EventData is then partial)
+}
+
+// ReadCSV reads events from CSV. The error is for unreadable input or a
+// header without the required columns; bad rows are reported per event.
+func ReadCSV(r io.Reader) ([]ImportedEvent, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1 // Short rows leave trailing columns empty
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{FieldTitle, FieldStart} {
+		if _, ok := index[strings.ToLower(required)]; !ok {
+			return nil, fmt.Errorf("CSV header has no %q column (columns: %s)", required, strings.Join(CSVColumns, ", "))
+		}
+	}
+
+	var events []ImportedEvent
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if !errors.As(err, &perr) {
+				return events, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			events = append(events, ImportedEvent{Row: row, Err: err})
+			continue
+		}
+		get := func(column string) string {
+			if i, ok := index[strings.ToLower(column)]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		ev := ImportedEvent{Row: row}
+		ev.UID = get("uid")
+		ev.Title = get(FieldTitle)
+		ev.Location = get(FieldLocation)
+		ev.Description = get(FieldDescription)
+		var startDate, endDate bool
+		if ev.Start, startDate, err = parseTransferTime(get(FieldStart)); err != nil {
+			ev.Err = fmt.Errorf("start: %w", err)
+		} else if end := get(FieldEnd); end != "" {
+			if ev.End, endDate, err = parseTransferTime(end); err != nil {
+				ev.Err = fmt.Errorf("end: %w", err)
+			}
+		}
+		ev.AllDay = startDate && (endDate || get(FieldEnd) == "") || strings.EqualFold(get(FieldAllDay), "true")
+		if ev.Err == nil {
+			ev.End = defaultEnd(ev.EventData)
+		}
+		events = append(events, ev)
+	}
+}
+
+// parseTransferTime parses a CSV time; date reports a bare date
+func parseTransferTime(s string) (t time.Time, date bool, err error) {
+	if s == "" {
+		return time.Time{}, false, errors.New("missing")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, false, nil
+	}
+	for _, layout := range []string{DateTimeLocalFormat, "2006-01-02 15:04", "2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, false, nil
+		}
+	}
+	if t, err := time.Parse(DateOnlyFormat, s); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("unrecognised time %q (use RFC 3339, %s or %s)", s, DateTimeLocalFormat, DateOnlyFormat)
+}
+
+// defaultEnd returns e's end, defaulting as RFC 5545 does: one day for
+// all-day events, else the start
+func defaultEnd(e EventData) time.Time {
+	switch {
+	case !e.End.IsZero():
+		return e.End
+	case e.AllDay:
+		return e.Start.AddDate(0, 0, 1)
+	default:
+		return e.Start
+	}
+}
+
+// WriteCSV writes events with a CSVColumns header
+func WriteCSV(w io.Writer, events []EventData) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVColumns); err != nil {
+		return err
+	}
+	for _, e := range events {
+		start, end := e.Start.UTC().Format(time.RFC3339), e.End.UTC().Format(time.RFC3339)
+		if e.AllDay {
+			start, end = e.Start.Format(DateOnlyFormat), e.End.Format(DateOnlyFormat)
+		}
+		allDay := ""
+		if e.AllDay {
+			allDay = "true"
+		}
+		if err := cw.Write([]string{e.UID, e.Title, start, end, allDay, e.Location, e.Description}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadICS reads the VEVENTs of an iCalendar file (RFC 5545). Recurrence,
+// attendees and alarms are not imported.
+func ReadICS(r io.Reader) ([]ImportedEvent, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ICS: %w", err)
+	}
+
+	var events []ImportedEvent
+	var cur *ImportedEvent
+	for _, line := range lines {
+		name, params, value := parseICSLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			cur = &ImportedEvent{Row: len(events) + 1}
+		case cur == nil:
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if cur.Err == nil && cur.Start.IsZero() {
+				cur.Err = errors.New("DTSTART: missing")
+			}
+			if cur.Err == nil {
+				cur.End = defaultEnd(cur.EventData)
+			}
+			events = append(events, *cur)
+			cur = nil
+		case name == "UID":
+			cur.UID = value
+		case name == "SUMMARY":
+			cur.Title = unescapeICS(value)
+		case name == "LOCATION":
+			cur.Location = unescapeICS(value)
+		case name == "DESCRIPTION":
+			cur.Description = unescapeICS(value)
+		case name == "URL":
+			cur.URL = value
+		case name == "DTSTART" || name == "DTEND":
+			t, date, err := parseICSTime(params, value)
+			if err != nil {
+				if cur.Err == nil {
+					cur.Err = fmt.Errorf("%s: %w", name, err)
+				}
+				continue
+			}
+			if name == "DTSTART" {
+				cur.Start, cur.AllDay = t, date
+			} else {
+				cur.End = t
+			}
+		}
+	}
+	return events, nil
+}
+
+// unfoldICS splits r into logical lines, joining folded continuation lines
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, sc.Err()
+}
+
+// parseICSLine splits "NAME;PARAM=x:value" (parameter values may be quoted)
+func parseICSLine(line string) (name string, params map[string]string, value string) {
+	inQuote := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuote = !inQuote
+		} else if c == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	parts := strings.Split(line[:colon], ";")
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
+
+// parseICSTime parses a DATE or DATE-TIME value; date reports a DATE
+func parseICSTime(params map[string]string, value string) (t time.Time, date bool, err error) {
+	if params["VALUE"] == "DATE" || len(value) == len(DateFormatICS) {
+		t, err = time.Parse(DateFormatICS, value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(DateTimeFormatICS+"Z", value)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if l, lerr := time.LoadLocation(tzid); lerr == nil {
+			loc = l
+		}
+	}
+	t, err = time.ParseInLocation(DateTimeFormatICS, value, loc)
+	return t, false, err
+}
+
+// WriteICS writes events as an iCalendar file
+func WriteICS(w io.Writer, events []EventData) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format(DateTimeFormatICS + "Z")
+	writeLine := func(s string) {
+		// Fold at 75 octets (continuations start with a space) without
+		// splitting a UTF-8 sequence
+		for limit := 75; len(s) > limit; limit = 74 {
+			cut := limit
+			for cut > 0 && s[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			bw.WriteString(s[:cut] + "\r\n ")
+			s = s[cut:]
+		}
+		bw.WriteString(s + "\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//wellknown//Calendar//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	for _, e := range events {
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + e.UID)
+		writeLine("DTSTAMP:" + stamp)
+		if e.AllDay {
+			writeLine("DTSTART;VALUE=DATE:" + e.Start.Format(DateFormatICS))
+			writeLine("DTEND;VALUE=DATE:" + e.End.Format(DateFormatICS))
+		} else {
+			writeLine("DTSTART:" + e.Start.UTC().Format(DateTimeFormatICS) + "Z")
+			writeLine("DTEND:" + e.End.UTC().Format(DateTimeFormatICS) + "Z")
+		}
+		writeLine("SUMMARY:" + escapeICSText(e.Title))
+		if e.Location != "" {
+			writeLine("LOCATION:" + escapeICSText(e.Location))
+		}
+		if e.Description != "" {
+			writeLine("DESCRIPTION:" + escapeICSText(e.Description))
+		}
+		if e.URL != "" {
+			writeLine("URL:" + e.URL)
+		}
+		writeLine("END:VEVENT")
+	}
+	writeLine("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeICSText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// unescapeICS reverses escapeICSText
+func unescapeICS(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
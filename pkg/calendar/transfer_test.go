@@ -0,0 +1,134 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSV(t *testing.T) {
+	in := "Title,Start,End,Location,uid\n" +
+		"Standup,2025-12-02T09:00,2025-12-02T09:15,Room A,standup-1\n" +
+		"Offsite,2025-12-10,,,\n" +
+		"Broken,next tuesday,,,\n" +
+		"\"Planning, Q1\",2025-12-03T10:00:00+11:00,2025-12-03T11:00:00+11:00,,\n"
+	events, err := ReadCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4", len(events))
+	}
+
+	standup := events[0]
+	if standup.Err != nil || standup.UID != "standup-1" || standup.Location != "Room A" ||
+		!standup.End.Equal(time.Date(2025, 12, 2, 9, 15, 0, 0, time.UTC)) {
+		t.Errorf("standup: %+v", standup)
+	}
+	offsite := events[1]
+	if offsite.Err != nil || !offsite.AllDay || !offsite.End.Equal(offsite.Start.AddDate(0, 0, 1)) {
+		t.Errorf("a bare date is a one-day all-day event: %+v", offsite)
+	}
+	if events[2].Err == nil || events[2].Row != 3 {
+		t.Errorf("bad start must be reported on row 3: %+v", events[2])
+	}
+	if events[3].Title != "Planning, Q1" || events[3].Start.UTC().Hour() != 23 {
+		t.Errorf("quoted title / offset: %+v", events[3])
+	}
+
+	if _, err := ReadCSV(strings.NewReader("name,when\nx,y\n")); err == nil {
+		t.Error("header without title and start must fail")
+	}
+}
+
+func TestReadICS(t *testing.T) {
+	in := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:abc@example.com\r\n" +
+		"SUMMARY:Review\\, then lunch\r\n" +
+		"DESCRIPTION:Line one\\nLine two that is folded across\r\n" +
+		"  two lines\r\n" +
+		"DTSTART;TZID=\"Australia/Sydney\":20251202T090000\r\n" +
+		"DTEND:20251201T230000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Holiday\r\n" +
+		"DTSTART;VALUE=DATE:20251225\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:No start\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	events, err := ReadICS(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+
+	review := events[0]
+	if review.Err != nil || review.Title != "Review, then lunch" || review.UID != "abc@example.com" {
+		t.Errorf("review: %+v", review)
+	}
+	if review.Description != "Line one\nLine two that is folded across two lines" {
+		t.Errorf("description %q", review.Description)
+	}
+	// 09:00 in Sydney (UTC+11 in December) is 22:00 UTC the day before
+	if want := time.Date(2025, 12, 1, 22, 0, 0, 0, time.UTC); !review.Start.Equal(want) {
+		t.Errorf("start %s, want %s", review.Start.UTC(), want)
+	}
+	if holiday := events[1]; !holiday.AllDay || holiday.End.Sub(holiday.Start) != 24*time.Hour {
+		t.Errorf("holiday: %+v", holiday)
+	}
+	if events[2].Err == nil {
+		t.Error("event without DTSTART must be reported")
+	}
+}
+
+func TestTransferRoundTrip(t *testing.T) {
+	events := []EventData{
+		{UID: "a", Title: "Sync; weekly, with \\ team", Start: time.Date(2025, 12, 2, 9, 0, 0, 0, time.UTC),
+			End: time.Date(2025, 12, 2, 10, 0, 0, 0, time.UTC), Description: strings.TrimSpace(strings.Repeat("Long agenda ", 20))},
+		{UID: "b", Title: "Holiday", Start: time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC),
+			End: time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC), AllDay: true},
+	}
+
+	for name, rt := range map[string]struct {
+		write func(*bytes.Buffer, []EventData) error
+		read  func(*bytes.Buffer) ([]ImportedEvent, error)
+	}{
+		"csv": {
+			func(b *bytes.Buffer, e []EventData) error { return WriteCSV(b, e) },
+			func(b *bytes.Buffer) ([]ImportedEvent, error) { return ReadCSV(b) },
+		},
+		"ics": {
+			func(b *bytes.Buffer, e []EventData) error { return WriteICS(b, e) },
+			func(b *bytes.Buffer) ([]ImportedEvent, error) { return ReadICS(b) },
+		},
+	} {
+		var buf bytes.Buffer
+		if err := rt.write(&buf, events); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if name == "ics" {
+			for _, line := range strings.Split(buf.String(), "\r\n") {
+				if len(line) > 75 {
+					t.Errorf("ics: line not folded: %q", line)
+				}
+			}
+		}
+		got, err := rt.read(&buf)
+		if err != nil || len(got) != len(events) {
+			t.Fatalf("%s: %d events, %v", name, len(got), err)
+		}
+		for i, want := range events {
+			g := got[i]
+			if g.Err != nil || g.UID != want.UID || g.Title != want.Title || g.Description != want.Description ||
+				g.AllDay != want.AllDay || !g.Start.Equal(want.Start) || !g.End.Equal(want.End) {
+				t.Errorf("%s: event %d = %+v, want %+v", name, i, g.EventData, want)
+			}
+		}
+	}
+}
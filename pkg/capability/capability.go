@@ -0,0 +1,152 @@
+// Package capability describes which fields each platform/app builder supports.
+//
+// The deep links differ in what they can carry: a Google Calendar URL has no
+// reminders, an Apple Calendar ICS file has no video conference. The matrix
+// answers "does google/calendar support attendees?" so callers (and the AI/MCP
+// layer) only collect fields that will reach the user.
+package capability
+
+import (
+	"sort"
+
+	cal "github.com/joeblew999/wellknown/pkg/calendar"
+	googlecalendar "github.com/joeblew999/wellknown/pkg/google/calendar"
+)
+
+// Support levels
+const (
+	Yes     Support = "yes"     // The builder encodes the field
+	Partial Support = "partial" // Only some of the field, or only in another mode (see Note)
+	No      Support = "no"      // The field is dropped
+)
+
+// Support is how well a builder carries a field
+type Support string
+
+// rank orders support levels for AtLeast
+func (s Support) rank() int {
+	switch s {
+	case Yes:
+		return 2
+	case Partial:
+		return 1
+	}
+	return 0
+}
+
+// AtLeast reports whether s is min or better
+func (s Support) AtLeast(min Support) bool {
+	return s.rank() >= min.rank()
+}
+
+// FieldSupport is one cell of the matrix
+type FieldSupport struct {
+	Support Support `json:"support"`
+	Note    string  `json:"note,omitempty"`
+}
+
+// App is one row of the matrix: a platform/app builder and its fields
+type App struct {
+	Platform  string                  `json:"platform"`  // e.g., "google"
+	App       string                  `json:"app"`       // e.g., "calendar"
+	Output    string                  `json:"output"`    // What the builder produces (e.g., "URL")
+	Available bool                    `json:"available"` // False for platforms that are not built yet
+	Fields    map[string]FieldSupport `json:"fields"`
+}
+
+// Field returns the support for field; fields the app does not list are No
+func (a App) Field(field string) FieldSupport {
+	if fs, ok := a.Fields[field]; ok {
+		return fs
+	}
+	return FieldSupport{Support: No}
+}
+
+// FieldsWith returns the app's fields supported at least at min, in name order
+func (a App) FieldsWith(min Support) []string {
+	var names []string
+	for name, fs := range a.Fields {
+		if fs.Support.AtLeast(min) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Matrix is the capability matrix, one App per platform/app
+type Matrix []App
+
+// App returns the row for platform/app
+func (m Matrix) App(platform, app string) (App, bool) {
+	for _, a := range m {
+		if a.Platform == platform && a.App == app {
+			return a, true
+		}
+	}
+	return App{}, false
+}
+
+// Lookup returns the support for one field (No for unknown apps or fields)
+func (m Matrix) Lookup(platform, app, field string) FieldSupport {
+	a, ok := m.App(platform, app)
+	if !ok {
+		return FieldSupport{Support: No}
+	}
+	return a.Field(field)
+}
+
+// Filter returns the rows matching platform and app ("" matches any)
+func (m Matrix) Filter(platform, app string) Matrix {
+	var out Matrix
+	for _, a := range m {
+		if (platform == "" || a.Platform == platform) && (app == "" || a.App == app) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Default returns the matrix for the builders in this module
+func Default() Matrix {
+	return Matrix{
+		googleCalendar(),
+		appleCalendar(),
+		{Platform: "google", App: "maps", Output: "URL", Fields: map[string]FieldSupport{}},
+		{Platform: "apple", App: "maps", Output: "URL", Fields: map[string]FieldSupport{}},
+	}
+}
+
+// googleCalendar is the TEMPLATE URL built by googlecalendar.GenerateURL
+func googleCalendar() App {
+	fields := map[string]FieldSupport{
+		cal.FieldTitle:       {Support: Yes},
+		cal.FieldStart:       {Support: Yes, Note: "converted to UTC"},
+		cal.FieldEnd:         {Support: Yes, Note: "converted to UTC"},
+		cal.FieldLocation:    {Support: Yes},
+		cal.FieldDescription: {Support: Yes},
+		cal.FieldAttendees:   {Support: Partial, Note: "email addresses only - no names or optional attendees"},
+		cal.FieldAllDay:      {Support: No},
+		cal.FieldRecurrence:  {Support: No},
+	}
+	for _, field := range googlecalendar.APIOnlyFields {
+		fields[field] = FieldSupport{Support: Partial, Note: "Calendar API only - not carried by the URL"}
+	}
+	return App{Platform: "google", App: "calendar", Output: "URL", Available: true, Fields: fields}
+}
+
+// appleCalendar is the ICS file built by applecalendar.GenerateICS
+func appleCalendar() App {
+	return App{Platform: "apple", App: "calendar", Output: "ICS download", Available: true, Fields: map[string]FieldSupport{
+		cal.FieldTitle:           {Support: Yes},
+		cal.FieldStart:           {Support: Yes},
+		cal.FieldEnd:             {Support: Yes},
+		cal.FieldLocation:        {Support: Yes},
+		cal.FieldDescription:     {Support: Yes},
+		cal.FieldAllDay:          {Support: Yes},
+		cal.FieldAttendees:       {Support: Yes},
+		cal.FieldRecurrence:      {Support: Yes, Note: "RRULE frequency, interval, count and until"},
+		cal.FieldReminders:       {Support: Yes, Note: "display alarms"},
+		cal.FieldVideoConference: {Support: No},
+	}}
+}
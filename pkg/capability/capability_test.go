@@ -0,0 +1,64 @@
+package capability
+
+import (
+	"testing"
+
+	cal "github.com/joeblew999/wellknown/pkg/calendar"
+)
+
+func TestDefaultLookup(t *testing.T) {
+	m := Default()
+
+	tests := []struct {
+		platform, app, field string
+		want                 Support
+	}{
+		{"google", "calendar", cal.FieldTitle, Yes},
+		{"google", "calendar", cal.FieldAttendees, Partial},
+		{"google", "calendar", cal.FieldReminders, Partial},
+		{"google", "calendar", cal.FieldRecurrence, No},
+		{"apple", "calendar", cal.FieldRecurrence, Yes},
+		{"apple", "calendar", cal.FieldVideoConference, No},
+		{"apple", "calendar", "unknown", No},
+		{"acme", "notes", cal.FieldTitle, No},
+	}
+	for _, tt := range tests {
+		if got := m.Lookup(tt.platform, tt.app, tt.field).Support; got != tt.want {
+			t.Errorf("%s/%s %s: expected %s, got %s", tt.platform, tt.app, tt.field, tt.want, got)
+		}
+	}
+}
+
+func TestFieldsWith(t *testing.T) {
+	app, ok := Default().App("google", "calendar")
+	if !ok {
+		t.Fatal("google/calendar missing from the matrix")
+	}
+
+	full := app.FieldsWith(Yes)
+	want := []string{cal.FieldDescription, cal.FieldEnd, cal.FieldLocation, cal.FieldStart, cal.FieldTitle}
+	if len(full) != len(want) {
+		t.Fatalf("expected %v, got %v", want, full)
+	}
+	for i := range want {
+		if full[i] != want[i] {
+			t.Errorf("field %d: expected %s, got %s", i, want[i], full[i])
+		}
+	}
+	if n := len(app.FieldsWith(Partial)); n != len(want)+3 {
+		t.Errorf("expected %d fields with partial support, got %d", len(want)+3, n)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	m := Default()
+	if got := m.Filter("google", ""); len(got) != 2 {
+		t.Errorf("expected 2 google apps, got %d", len(got))
+	}
+	if got := m.Filter("", "calendar"); len(got) != 2 {
+		t.Errorf("expected 2 calendar apps, got %d", len(got))
+	}
+	if got := m.Filter("acme", ""); len(got) != 0 {
+		t.Errorf("expected no rows, got %d", len(got))
+	}
+}
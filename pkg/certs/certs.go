@@ -0,0 +1,334 @@
+// Package certs generates locally trusted HTTPS certificates for development
+// in pure Go: a local certificate authority, host certificates signed by it,
+// and installing the CA into the system trust store.
+//
+// The CA uses mkcert's file layout (rootCA.pem, rootCA-key.pem), and when
+// mkcert is installed its CAROOT is reused, so certificates issued here are
+// trusted wherever "mkcert -install" already ran.
+//
+// Usage:
+//
+//	certFile, keyFile := ".data/certs/cert.pem", ".data/certs/key.pem"
+//	if err := certs.Ensure(certFile, keyFile); err != nil { ... }
+//	http.ListenAndServeTLS(":8443", certFile, keyFile, mux)
+//
+// It is shared by the PocketBase app (HTTPS_ENABLED), "wellknown serve --tls",
+// "wellknown certs" and the env example server.
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDir is where host certificates are written
+	DefaultDir = ".data/certs"
+
+	// CertFileName and KeyFileName are the host certificate files in DefaultDir
+	CertFileName = "cert.pem"
+	KeyFileName  = "key.pem"
+
+	// RootCertFileName and RootKeyFileName are the CA files in CARoot (mkcert's names)
+	RootCertFileName = "rootCA.pem"
+	RootKeyFileName  = "rootCA-key.pem"
+)
+
+// Validity of issued certificates. Host certificates stay under Apple's
+// 825-day limit for TLS server certificates.
+const (
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 825 * 24 * time.Hour
+
+	// renewBefore is how close to expiry Ensure reissues a certificate
+	renewBefore = 30 * 24 * time.Hour
+)
+
+// DefaultFiles returns the host certificate and key paths in DefaultDir
+func DefaultFiles() (certFile, keyFile string) {
+	return filepath.Join(DefaultDir, CertFileName), filepath.Join(DefaultDir, KeyFileName)
+}
+
+// CARoot returns the directory holding the local CA: $CAROOT if set, mkcert's
+// CAROOT if mkcert is installed, else wellknown/ca in the user config directory.
+func CARoot() string {
+	if dir := os.Getenv("CAROOT"); dir != "" {
+		return dir
+	}
+	if out, err := exec.Command("mkcert", "-CAROOT").Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" {
+			return dir
+		}
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "wellknown", "ca")
+}
+
+// CA is a local certificate authority
+type CA struct {
+	Root string // Directory holding rootCA.pem and rootCA-key.pem
+	Cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// CertFile returns the path of the CA certificate (to import on devices)
+func (ca *CA) CertFile() string {
+	return filepath.Join(ca.Root, RootCertFileName)
+}
+
+// LoadOrCreateCA loads the CA in root, creating it on first use
+func LoadOrCreateCA(root string) (*CA, error) {
+	ca, err := LoadCA(root)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return ca, err
+	}
+	return createCA(root)
+}
+
+// LoadCA loads the CA in root. The error wraps os.ErrNotExist if there is none.
+func LoadCA(root string) (*CA, error) {
+	certPEM, err := os.ReadFile(filepath.Join(root, RootCertFileName))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(root, RootKeyFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := parseCertPEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate in %s: %w", root, err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("invalid CA key in %s", root)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA key in %s: %w", root, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA key type %T", key)
+	}
+	return &CA{Root: root, Cert: cert, key: signer}, nil
+}
+
+// createCA generates a new CA and writes it to root
+func createCA(root string) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	user := "unknown"
+	if u := os.Getenv("USER"); u != "" {
+		user = u
+	}
+	host, _ := os.Hostname()
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization:       []string{"wellknown development CA"},
+			OrganizationalUnit: []string{user + "@" + host},
+			CommonName:         "wellknown development CA " + user + "@" + host,
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create CA directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, RootKeyFileName), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o400); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(root, RootCertFileName), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		return nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Root: root, Cert: cert, key: key}, nil
+}
+
+// Issue creates a server certificate for hosts (DNS names and IP addresses)
+// signed by the CA, returning the PEM encoded certificate and private key
+func (ca *CA) Issue(hosts []string) (certPEM, keyPEM []byte, err error) {
+	if len(hosts) == 0 {
+		return nil, nil, errors.New("no hosts to issue a certificate for")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"wellknown development certificate"},
+			CommonName:   hosts[0],
+		},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.Cert, key.Public(), ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// LocalHosts returns the names a development server is reached by:
+// localhost, the loopback addresses and the machine's LAN IPv4 addresses
+func LocalHosts() []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return hosts
+	}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			hosts = append(hosts, ipnet.IP.String())
+		}
+	}
+	return hosts
+}
+
+// Generate issues a certificate for hosts (LocalHosts if none) from the CA in
+// CARoot and writes it to certFile and keyFile
+func Generate(certFile, keyFile string, hosts ...string) error {
+	if len(hosts) == 0 {
+		hosts = LocalHosts()
+	}
+	ca, err := LoadOrCreateCA(CARoot())
+	if err != nil {
+		return err
+	}
+	certPEM, keyPEM, err := ca.Issue(hosts)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{filepath.Dir(certFile), filepath.Dir(keyFile)} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create certs directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certFile, certPEM, 0o644)
+}
+
+// Ensure generates certFile and keyFile unless they exist, are still valid for
+// a while and cover every host in LocalHosts (the LAN IP changes between networks)
+func Ensure(certFile, keyFile string) error {
+	if _, err := os.Stat(keyFile); err == nil {
+		if cert, err := Load(certFile); err == nil && !needsRenewal(cert, LocalHosts()) {
+			return nil
+		}
+	}
+	return Generate(certFile, keyFile)
+}
+
+// Provider returns a func with the signature of server.CertProvider that
+// ensures the certificates on each call
+func Provider(certFile, keyFile string) func() (string, string, error) {
+	return func() (string, string, error) {
+		if err := Ensure(certFile, keyFile); err != nil {
+			return "", "", err
+		}
+		return certFile, keyFile, nil
+	}
+}
+
+// Load parses the first certificate in a PEM file
+func Load(certFile string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertPEM(data)
+}
+
+// needsRenewal reports whether cert expires soon or misses one of hosts
+func needsRenewal(cert *x509.Certificate, hosts []string) bool {
+	if time.Until(cert.NotAfter) < renewBefore {
+		return true
+	}
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCertPEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
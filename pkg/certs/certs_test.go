@@ -0,0 +1,136 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadOrCreateCA(t *testing.T) {
+	root := t.TempDir()
+
+	ca, err := LoadOrCreateCA(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ca.Cert.IsCA {
+		t.Error("CA certificate is not a CA")
+	}
+
+	again, err := LoadOrCreateCA(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Cert.Equal(ca.Cert) {
+		t.Error("second call created a new CA instead of loading it")
+	}
+}
+
+func TestIssue(t *testing.T) {
+	ca, err := LoadOrCreateCA(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM, keyPEM, err := ca.Issue([]string{"localhost", "127.0.0.1", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("issued pair does not load: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	for _, host := range []string{"localhost", "127.0.0.1", "::1"} {
+		if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, DNSName: host}); err != nil {
+			t.Errorf("verify %s: %v", host, err)
+		}
+	}
+	if err := leaf.VerifyHostname("example.com"); err == nil {
+		t.Error("certificate should not cover example.com")
+	}
+
+	if _, _, err := ca.Issue(nil); err == nil {
+		t.Error("Issue without hosts should fail")
+	}
+}
+
+func TestEnsure(t *testing.T) {
+	t.Setenv("CAROOT", t.TempDir())
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "certs", CertFileName), filepath.Join(dir, "certs", KeyFileName)
+
+	if err := Ensure(certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	first, err := Load(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range LocalHosts() {
+		if err := first.VerifyHostname(host); err != nil {
+			t.Errorf("certificate does not cover %s", host)
+		}
+	}
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("key file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	// A valid certificate is kept
+	if err := Ensure(certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := Load(certFile)
+	if !second.Equal(first) {
+		t.Error("Ensure reissued a valid certificate")
+	}
+
+	// A certificate for other hosts is reissued
+	if err := Generate(certFile, keyFile, "example.test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Ensure(certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	third, _ := Load(certFile)
+	if err := third.VerifyHostname("localhost"); err != nil {
+		t.Error("Ensure kept a certificate that misses localhost")
+	}
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	cert := &x509.Certificate{NotAfter: time.Now().Add(10 * 24 * time.Hour), DNSNames: []string{"localhost"}}
+	if !needsRenewal(cert, []string{"localhost"}) {
+		t.Error("certificate expiring in 10 days should be renewed")
+	}
+	cert.NotAfter = time.Now().Add(365 * 24 * time.Hour)
+	if needsRenewal(cert, []string{"localhost"}) {
+		t.Error("valid certificate should not be renewed")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	t.Setenv("CAROOT", t.TempDir())
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, CertFileName), filepath.Join(dir, KeyFileName)
+
+	gotCert, gotKey, err := Provider(certFile, keyFile)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotCert != certFile || gotKey != keyFile {
+		t.Errorf("Provider returned %s, %s", gotCert, gotKey)
+	}
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		t.Error(err)
+	}
+}
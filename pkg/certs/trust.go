@@ -0,0 +1,71 @@
+package certs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// ErrTrustUnsupported is returned by InstallTrust where the system trust store
+// cannot be updated automatically; import CA.CertFile by hand instead
+var ErrTrustUnsupported = errors.New("installing the CA is not supported on this system")
+
+// linuxTrustStores are the anchor directories and refresh commands of the
+// common distributions (Debian/Ubuntu, Fedora/RHEL, Arch)
+var linuxTrustStores = []struct {
+	dir     string
+	command []string
+}{
+	{"/usr/local/share/ca-certificates", []string{"update-ca-certificates"}},
+	{"/etc/pki/ca-trust/source/anchors", []string{"update-ca-trust", "extract"}},
+	{"/etc/ca-certificates/trust-source/anchors", []string{"trust", "extract-compat"}},
+}
+
+// InstallTrust adds the CA to the system trust store so browsers accept the
+// certificates it issues. mkcert is used when installed (it also covers the
+// Firefox and Java stores); otherwise the macOS keychain or the Linux CA
+// bundle is updated directly, which may prompt for a password or need root.
+func (ca *CA) InstallTrust() error {
+	if _, err := exec.LookPath("mkcert"); err == nil {
+		cmd := exec.Command("mkcert", "-install")
+		cmd.Env = append(os.Environ(), "CAROOT="+ca.Root)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		keychain := filepath.Join(os.Getenv("HOME"), "Library", "Keychains", "login.keychain-db")
+		return run("security", "add-trusted-cert", "-r", "trustRoot", "-k", keychain, ca.CertFile())
+	case "linux":
+		for _, store := range linuxTrustStores {
+			if _, err := os.Stat(store.dir); err != nil {
+				continue
+			}
+			data, err := os.ReadFile(ca.CertFile())
+			if err != nil {
+				return err
+			}
+			anchor := filepath.Join(store.dir, "wellknown-development-ca.crt")
+			if err := os.WriteFile(anchor, data, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s (try again with sudo): %w", anchor, err)
+			}
+			return run(store.command[0], store.command[1:]...)
+		}
+	}
+	return ErrTrustUnsupported
+}
+
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Package certs provides the "certs" command for locally trusted development
+// certificates (pkg/certs), replacing the mkcert steps of the Makefile.
+package certs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joeblew999/wellknown/pkg/certs"
+)
+
+// NewCommand creates the development certificate command
+func NewCommand() *cobra.Command {
+	certFile, keyFile := certs.DefaultFiles()
+
+	certsCmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Generate locally trusted HTTPS certificates for development",
+		Long: `Generate a local certificate authority and HTTPS certificates signed by it.
+
+The CA is shared with mkcert when it is installed ($CAROOT or "mkcert -CAROOT"),
+so existing trust is reused. Certificates cover localhost and the LAN IPs.
+
+Examples:
+  wellknown certs install              # Create the CA and trust it (once per machine)
+  wellknown certs generate             # Write .data/certs/cert.pem and key.pem
+  wellknown certs generate myapp.test  # Certificate for specific hosts
+  wellknown certs status`,
+	}
+	certsCmd.PersistentFlags().StringVar(&certFile, "cert", certFile, "Certificate file")
+	certsCmd.PersistentFlags().StringVar(&keyFile, "key", keyFile, "Private key file")
+
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Create the local CA and add it to the system trust store",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ca, err := certs.LoadOrCreateCA(certs.CARoot())
+			if err != nil {
+				return err
+			}
+			fmt.Printf("🔐 Local CA: %s\n", ca.CertFile())
+			if err := ca.InstallTrust(); err != nil {
+				if errors.Is(err, certs.ErrTrustUnsupported) {
+					fmt.Printf("⚠️  Import %s into your browser or OS trust store by hand\n", ca.CertFile())
+					return nil
+				}
+				return err
+			}
+			fmt.Println("✅ Local CA installed and trusted")
+			return nil
+		},
+	}
+
+	generateCmd := &cobra.Command{
+		Use:   "generate [host...]",
+		Short: "Generate a certificate for localhost and the LAN IPs (or the given hosts)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hosts := args
+			if len(hosts) == 0 {
+				hosts = certs.LocalHosts()
+			}
+			if err := certs.Generate(certFile, keyFile, hosts...); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Certificate for %s\n", strings.Join(hosts, ", "))
+			fmt.Printf("   • Cert: %s\n", certFile)
+			fmt.Printf("   • Key:  %s\n", keyFile)
+			return nil
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the local CA and certificate",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := certs.CARoot()
+			if ca, err := certs.LoadCA(root); err == nil {
+				fmt.Printf("Local CA:    %s (expires %s)\n", ca.CertFile(), ca.Cert.NotAfter.Format(time.DateOnly))
+			} else {
+				fmt.Printf("Local CA:    not created in %s (run: wellknown certs install)\n", root)
+			}
+
+			cert, err := certs.Load(certFile)
+			if errors.Is(err, os.ErrNotExist) {
+				fmt.Printf("Certificate: none at %s (run: wellknown certs generate)\n", certFile)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			hosts := append([]string{}, cert.DNSNames...)
+			for _, ip := range cert.IPAddresses {
+				hosts = append(hosts, ip.String())
+			}
+			fmt.Printf("Certificate: %s (expires %s)\n", certFile, cert.NotAfter.Format(time.DateOnly))
+			fmt.Printf("Valid for:   %s\n", strings.Join(hosts, ", "))
+			return nil
+		},
+	}
+
+	certsCmd.AddCommand(installCmd, generateCmd, statusCmd)
+	return certsCmd
+}
@@ -0,0 +1,745 @@
+// Package envcmd provides the "env" cobra command for environment variable management.
+//
+// It is shared by the PocketBase binary (main.go) and the unified wellknown CLI.
+package envcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/env/deploy"
+	"github.com/joeblew999/wellknown/pkg/env/tui"
+	wellknown "github.com/joeblew999/wellknown/pkg/pb"
+)
+
+// NewCommand creates the environment variable management command
+func NewCommand() *cobra.Command {
+	envCmd := &cobra.Command{
+		Use:   "env",
+		Short: "Environment variable management",
+		Long:  "Manage and export environment variables for deployment",
+	}
+
+	// Sub-command: env export-secrets
+	exportCmd := &cobra.Command{
+		Use:   "export-secrets",
+		Short: "Export secrets for flyctl secrets import",
+		Long: `Export environment variables marked as secrets in NAME=VALUE format.
+This output can be piped directly to 'flyctl secrets import'.
+
+Example:
+  . ./.env && ./wellknown env export-secrets | flyctl secrets import`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := wellknown.ExportSecretsFormat()
+			if output == "" {
+				fmt.Fprintln(os.Stderr, "⚠️  No secrets found in environment")
+				return fmt.Errorf("no secrets found in environment")
+			}
+			fmt.Print(output)
+			return nil
+		},
+	}
+
+	// Sub-command: env list
+	var listPlain bool
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all environment variables and their status",
+		Long: `Display all registered environment variables with their current values.
+Secret values are masked for security.
+
+Variables are listed by group in aligned columns with their status (set,
+default, unset, missing or invalid) and summary counts. Colors are used on a
+terminal unless NO_COLOR is set; --plain prints the plain text format.
+
+This shows the complete environment variable registry from pkg/pb/env.go.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if listPlain {
+				fmt.Print(wellknown.ListEnvVars())
+				return nil
+			}
+			fmt.Print(wellknown.EnvRegistry.FormatEnvListTerminal(env.TerminalOptions{Color: env.ColorEnabled()}))
+			return nil
+		},
+	}
+	listCmd.Flags().BoolVar(&listPlain, "plain", false, "Plain text output (no columns or colors)")
+
+	// Sub-command: env resolved
+	var resolvedFormat string
+	resolvedCmd := &cobra.Command{
+		Use:   "resolved",
+		Short: "Show the effective configuration (value, source, changed from default)",
+		Long: `Display each registered variable's effective value, where it came from
+(.env file and line, process environment, or registry default) and whether it
+differs from the default. Secret values are masked.
+
+Unlike 'env list', which shows registry metadata, this shows what the
+application will actually run with.
+
+Example:
+  ./wellknown env resolved
+  ./wellknown env resolved --format json | jq '.[] | select(.changed)'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := env.FormatResolved(wellknown.EnvRegistry.Resolve(nil), resolvedFormat)
+			if err != nil {
+				return err
+			}
+			fmt.Print(output)
+			return nil
+		},
+	}
+	resolvedCmd.Flags().StringVarP(&resolvedFormat, "format", "f", "table", "Output format: table, json or yaml")
+
+	// Sub-command: env validate
+	var strictSecrets bool
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate required environment variables",
+		Long: `Check if all required environment variables are set.
+Returns an error if any required variables are missing.
+
+Required variables are marked with Required: true in pkg/pb/env.go.
+
+Secret values are also linted (placeholders like "changeme123", keys shorter
+than 16 characters, values reused across variables, URLs with embedded
+passwords). Findings are reported without values; --strict-secrets fails on them.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := wellknown.ValidateEnv(); err != nil {
+				fmt.Print(wellknown.EnvRegistry.FormatValidationTerminal(env.TerminalOptions{Color: env.ColorEnabled()}))
+				fmt.Fprintf(os.Stderr, "❌ Validation failed: %v\n", err)
+				return err
+			}
+			fmt.Println("✅ All required environment variables are set")
+
+			findings := wellknown.EnvRegistry.LintSecrets()
+			for _, f := range findings {
+				icon := "⚠️ "
+				if f.Severity == env.SeverityError {
+					icon = "❌"
+				}
+				fmt.Printf("%s %s\n", icon, f)
+			}
+			if len(findings) > 0 && strictSecrets {
+				return fmt.Errorf("%d secrets hygiene findings", len(findings))
+			}
+			return nil
+		},
+	}
+
+	validateCmd.Flags().BoolVar(&strictSecrets, "strict-secrets", false, "Fail if the secrets linter reports findings")
+
+	// Sub-command: env sync-dockerfile
+	var dockerfileDryRun bool
+	syncDockerfileCmd := &cobra.Command{
+		Use:   "sync-dockerfile",
+		Short: "Sync environment variable documentation to Dockerfile",
+		Long: `Updates the Dockerfile environment variable section with current registry.
+Preserves Dockerfile structure, only updates the env vars comment block.
+
+The Dockerfile must contain the marker comments for this to work.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := wellknown.SyncDockerfileEnvDocs("Dockerfile", dockerfileDryRun); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to sync Dockerfile: %v\n", err)
+				return err
+			}
+			if dockerfileDryRun {
+				fmt.Println("✅ Dry run complete (no changes made)")
+			} else {
+				fmt.Println("✅ Dockerfile environment documentation updated")
+			}
+			return nil
+		},
+	}
+	syncDockerfileCmd.Flags().BoolVarP(&dockerfileDryRun, "dry-run", "n", false, "Preview changes without writing")
+
+	// Sub-command: env sync-flytoml
+	var flytomlDryRun bool
+	syncFlyTomlCmd := &cobra.Command{
+		Use:   "sync-flytoml",
+		Short: "Sync non-secret environment variables to fly.toml",
+		Long: `Updates fly.toml [env] section with non-secret environment variables.
+Only includes variables where Secret=false.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := wellknown.SyncFlyTomlEnv("fly.toml", flytomlDryRun); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to sync fly.toml: %v\n", err)
+				return err
+			}
+			if flytomlDryRun {
+				fmt.Println("✅ Dry run complete (no changes made)")
+			} else {
+				fmt.Println("✅ fly.toml [env] section updated")
+			}
+			return nil
+		},
+	}
+	syncFlyTomlCmd.Flags().BoolVarP(&flytomlDryRun, "dry-run", "n", false, "Preview changes without writing")
+
+	// Sub-command: env sync-reference
+	var referenceDryRun bool
+	syncReferenceCmd := &cobra.Command{
+		Use:   "sync-reference [file]",
+		Short: "Sync the configuration reference (" + env.DefaultReferenceFile + ")",
+		Long: `Generates the Markdown configuration reference from the registry: one
+table per group with each variable's type, default, required/secret flags and
+description. Creates the file if missing; otherwise only the section between
+the generated markers is replaced, so hand-written text around it is kept.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := env.DefaultReferenceFile
+			if len(args) == 1 {
+				path = args[0]
+			}
+			if err := wellknown.EnvRegistry.SyncMarkdownReference(path, referenceDryRun); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to sync %s: %v\n", path, err)
+				return err
+			}
+			if referenceDryRun {
+				fmt.Println("✅ Dry run complete (no changes made)")
+			} else {
+				fmt.Printf("✅ %s updated\n", path)
+			}
+			return nil
+		},
+	}
+	syncReferenceCmd.Flags().BoolVarP(&referenceDryRun, "dry-run", "n", false, "Preview changes without writing")
+
+	// Sub-command: env generate-local
+	generateLocalCmd := &cobra.Command{
+		Use:   "generate-local",
+		Short: "Generate .env.local template",
+		Long: `Generates .env.local template with development-specific defaults.
+Includes HTTPS_ENABLED=true and localhost OAuth URLs.
+
+This will overwrite any existing .env.local file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content := wellknown.GenerateEnvLocal()
+			if err := os.WriteFile(".env.local", []byte(content), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to write .env.local: %v\n", err)
+				return err
+			}
+			fmt.Println("✅ .env.local generated")
+			fmt.Println("💡 Configure your OAuth credentials before running the server")
+			return nil
+		},
+	}
+
+	// Sub-command: env generate-production
+	generateProductionCmd := &cobra.Command{
+		Use:   "generate-production",
+		Short: "Generate .env.production template",
+		Long: `Generates .env.production template with production-specific defaults.
+Includes HTTPS_ENABLED=false (Fly.io handles TLS) and production OAuth URLs.
+
+This will overwrite any existing .env.production file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content := wellknown.GenerateEnvProduction()
+			if err := os.WriteFile(".env.production", []byte(content), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to write .env.production: %v\n", err)
+				return err
+			}
+			fmt.Println("✅ .env.production generated")
+			fmt.Println("💡 Configure your production OAuth credentials before deploying")
+			return nil
+		},
+	}
+
+	// Sub-command: env generate-example
+	generateExampleCmd := &cobra.Command{
+		Use:   "generate-example",
+		Short: "Generate .env.example template",
+		Long: `Generates .env.example template with placeholder values (safe to commit).
+This file shows all available environment variables without real credentials.
+
+This will overwrite any existing .env.example file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content := wellknown.GenerateEnvExample()
+			if err := os.WriteFile(".env.example", []byte(content), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to write .env.example: %v\n", err)
+				return err
+			}
+			fmt.Println("✅ .env.example generated")
+			fmt.Println("💡 This file is safe to commit to version control")
+			return nil
+		},
+	}
+
+	// Sub-command: env sync-secrets
+	syncSecretsCmd := &cobra.Command{
+		Use:   "sync-secrets",
+		Short: "Decrypt and merge .env.secrets[.age] into .env.local",
+		Long: `Merges git-tracked secrets (.env.secrets or .env.secrets.age) into .env.local for local development.
+
+This command:
+1. Reads your credentials from .env.secrets or .env.secrets.age (auto-decrypts if .age)
+2. Generates .env.local template with localhost URLs
+3. Merges your secrets into the template
+4. Writes to .env.local (ready for local development)
+
+Workflow:
+  cp .env.secrets.example .env.secrets
+  # Edit .env.secrets with real credentials
+  # Optional: Encrypt with Age: age -e -r YOUR_PUBLIC_KEY .env.secrets > .env.secrets.age
+  make env-sync-secrets
+  make run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := wellknown.MergeSecretsIntoEnv(".env.secrets", "local", ".env.local"); err != nil {
+				return err
+			}
+			fmt.Println("✅ .env.local generated from .env.secrets")
+			fmt.Println("💡 Your local development environment is ready!")
+			fmt.Println("   Run: make run")
+			return nil
+		},
+	}
+
+	// Sub-command: env sync-secrets-production
+	syncSecretsProductionCmd := &cobra.Command{
+		Use:   "sync-secrets-production",
+		Short: "Decrypt and merge .env.secrets[.age] into .env.production",
+		Long: `Merges git-tracked secrets (.env.secrets or .env.secrets.age) into .env.production for Fly.io deployment.
+
+This command:
+1. Reads your credentials from .env.secrets or .env.secrets.age (auto-decrypts if .age)
+2. Generates .env.production template with fly.dev URLs
+3. Merges your secrets into the template
+4. Writes to .env.production (ready for Fly.io deployment)
+
+Workflow:
+  make env-sync-secrets-production
+  make fly-secrets  # Push to Fly.io
+  make fly-deploy`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := wellknown.MergeSecretsIntoEnv(".env.secrets", "production", ".env.production"); err != nil {
+				return err
+			}
+			fmt.Println("✅ .env.production generated from .env.secrets")
+			fmt.Println("💡 Ready to deploy to Fly.io!")
+			fmt.Println("   Next: make fly-secrets")
+			return nil
+		},
+	}
+
+	// Sub-command: env export-shell
+	var keychainService string
+	exportShellCmd := &cobra.Command{
+		Use:   "export-shell",
+		Short: "Generate a sourceable shell script (secrets read from the OS keychain)",
+		Long: `Generate a POSIX shell script exporting all registered variables.
+Secret values are never written to the script; they are read from the OS keychain
+(macOS security, Linux secret-tool) when it is sourced. Store them first with keychain-store.
+
+Example:
+  ./wellknown env keychain-store
+  ./wellknown env export-shell > env.sh && . ./env.sh`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Print(wellknown.EnvRegistry.GenerateShellScript(env.ShellScriptOptions{Service: keychainService}))
+			return nil
+		},
+	}
+	exportShellCmd.Flags().StringVar(&keychainService, "service", env.DefaultKeychainService, "Keychain service name")
+
+	// Sub-command: env keychain-store
+	var keychainFile string
+	keychainStoreCmd := &cobra.Command{
+		Use:   "keychain-store",
+		Short: "Store secrets from .env.secrets[.age] in the OS keychain",
+		Long: `Decrypts the secrets file (prefers the .age version) and stores every secret
+variable from the registry in the OS keychain for use by export-shell.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := env.StoreSecretsInKeychain(env.KeychainOptions{
+				Registry:    wellknown.EnvRegistry,
+				SecretsFile: keychainFile,
+				Service:     keychainService,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ Stored %d secrets in keychain (service %q)\n", len(result.Stored), keychainService)
+			for _, name := range result.Missing {
+				fmt.Printf("⚠️  %s has no value in %s\n", name, keychainFile)
+			}
+			return nil
+		},
+	}
+	keychainStoreCmd.Flags().StringVar(&keychainFile, "file", ".env.secrets", "Secrets file to read")
+	keychainStoreCmd.Flags().StringVar(&keychainService, "service", env.DefaultKeychainService, "Keychain service name")
+
+	// Sub-command: env ci-bundle
+	var ciBundlePath string
+	ciBundleCmd := &cobra.Command{
+		Use:   "ci-bundle",
+		Short: "Package the encrypted env files into a CI artifact",
+		Long: `Bundles the existing .env.*.age files, a checksum manifest and a restore.sh
+bootstrap script into one tar.gz. Plaintext files are never included.
+
+Store the Age identity (contents of .age/key.txt) as the AGE_KEY secret in CI,
+then restore with 'env ci-restore' or, without this binary, the bundled
+restore.sh (requires the age CLI).
+
+Example:
+  ./wellknown env ci-bundle -o env-ci-bundle.tar.gz`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := env.CreateCIBundle(env.CIBundleOptions{Output: ciBundlePath})
+			if err != nil {
+				return err
+			}
+			for _, f := range manifest.Files {
+				fmt.Printf("   🔒 %s\n", f.Name)
+			}
+			fmt.Printf("✅ Wrote %s (%d encrypted files)\n", ciBundlePath, len(manifest.Files))
+			return nil
+		},
+	}
+	ciBundleCmd.Flags().StringVarP(&ciBundlePath, "output", "o", env.DefaultCIBundlePath, "Bundle file to write")
+
+	// Sub-command: env ci-restore
+	var ciRestoreDir string
+	var ciRestoreGitHubEnv bool
+	ciRestoreCmd := &cobra.Command{
+		Use:   "ci-restore",
+		Short: "Decrypt a CI bundle using the AGE_KEY secret",
+		Long: `Verifies the bundle checksums and decrypts its env files using the Age
+identity in the AGE_KEY environment variable.
+
+With --github-env the values are also masked in the log and appended to
+$GITHUB_ENV, so later GitHub Actions steps see them as environment variables.
+
+Example (GitHub Actions):
+  - run: ./wellknown env ci-restore --github-env
+    env:
+      AGE_KEY: ${{ secrets.AGE_KEY }}`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := env.RestoreCIBundle(env.CIRestoreOptions{
+				Bundle:    ciBundlePath,
+				Dir:       ciRestoreDir,
+				GitHubEnv: ciRestoreGitHubEnv,
+			})
+			if err != nil {
+				return err
+			}
+			for _, e := range result.Errors {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", e)
+			}
+			fmt.Printf("✅ Restored %s\n", strings.Join(result.ProcessedFiles, ", "))
+			return nil
+		},
+	}
+	ciRestoreCmd.Flags().StringVarP(&ciBundlePath, "bundle", "b", env.DefaultCIBundlePath, "Bundle file to restore")
+	ciRestoreCmd.Flags().StringVarP(&ciRestoreDir, "dir", "d", ".", "Directory to write decrypted files to")
+	ciRestoreCmd.Flags().BoolVar(&ciRestoreGitHubEnv, "github-env", false, "Export values to $GITHUB_ENV (masked)")
+
+	// Sub-command: env secrets-wizard
+	var wizardEnv, wizardKey string
+	secretsWizardCmd := &cobra.Command{
+		Use:   "secrets-wizard",
+		Short: "Enter secrets interactively straight into the encrypted .age file",
+		Long: `Prompts for every secret in the registry with hidden input, validates each
+value, and writes .env.secrets.<env>.age directly. The plaintext secrets
+file is never written to disk. Press Enter to keep an existing value.
+
+Example:
+  ./wellknown env secrets-wizard --env production`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var target *env.Environment
+			switch wizardEnv {
+			case "local":
+				target = env.SecretsLocal
+			case "production":
+				target = env.SecretsProduction
+			default:
+				return fmt.Errorf("unknown environment %q (use local or production)", wizardEnv)
+			}
+			result, err := env.RunSecretsWizard(env.SecretsWizardOptions{
+				Registry:    wellknown.EnvRegistry,
+				Environment: target,
+				KeyPath:     wizardKey,
+				AppName:     "Wellknown",
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ Wrote %s (%d set, %d kept)\n", result.Path, len(result.Set), len(result.Kept))
+			for _, name := range result.Empty {
+				fmt.Printf("⚠️  %s has no value\n", name)
+			}
+			return nil
+		},
+	}
+	secretsWizardCmd.Flags().StringVarP(&wizardEnv, "env", "e", "local", "Secrets file to write: local or production")
+	secretsWizardCmd.Flags().StringVar(&wizardKey, "key", env.DefaultAgeKeyPath, "Age key file")
+
+	// Sub-command: env compare-remote
+	var compareFile string
+	compareRemoteCmd := &cobra.Command{
+		Use:   "compare-remote URL",
+		Short: "Compare local env file against a deployed instance",
+		Long: `Fetches /env?format=json from a deployed instance and compares which
+variables are configured there with the values set in the local file.
+Only configured status is compared; no values are sent or received.
+
+Example:
+  ./wellknown env compare-remote https://wellknown.fly.dev`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmp, err := env.CompareRemoteWithOptions(env.CompareRemoteOptions{
+				URL:       args[0],
+				Registry:  wellknown.EnvRegistry,
+				LocalFile: compareFile,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Comparing %s with %s (%s)\n\n", cmp.LocalFile, cmp.URL, cmp.Environment)
+			for _, name := range cmp.MissingRemote {
+				fmt.Printf("❌ %s is set locally but not configured remotely\n", name)
+			}
+			for _, name := range cmp.MissingLocal {
+				fmt.Printf("⚠️  %s is configured remotely but not set locally\n", name)
+			}
+			for _, name := range cmp.UnknownRemote {
+				fmt.Printf("⚠️  %s is reported remotely but not in the local registry\n", name)
+			}
+			for _, name := range cmp.NotReported {
+				fmt.Printf("⚠️  %s is not reported remotely (older deploy?)\n", name)
+			}
+			if !cmp.HasDifferences() {
+				fmt.Printf("✅ All %d variables match\n", cmp.Matching)
+				return nil
+			}
+			fmt.Printf("\n%d matching\n", cmp.Matching)
+			if len(cmp.MissingRemote) > 0 {
+				return fmt.Errorf("%d variable(s) missing remotely", len(cmp.MissingRemote))
+			}
+			return nil
+		},
+	}
+	compareRemoteCmd.Flags().StringVarP(&compareFile, "file", "f", env.Production.FileName, "Local env file to compare")
+
+	// Sub-command: env fly-report
+	var reportJSON bool
+	flyReportCmd := &cobra.Command{
+		Use:   "fly-report [app]",
+		Short: "Show the machines, volumes and estimated monthly cost of the Fly app",
+		Long: `Queries the machine sizes, volumes and regions of the Fly.io app (default:
+the app in fly.toml) and prints an estimated monthly cost breakdown at list
+prices. Bandwidth, dedicated IPv4 and plan allowances are not included.
+
+Example:
+  ./wellknown env fly-report
+  ./wellknown env fly-report wellknown-pr-42 --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var app string
+			if len(args) > 0 {
+				app = args[0]
+			} else {
+				name, _, err := deploy.ReadFlyTomlConfig()
+				if err != nil {
+					return err
+				}
+				app = name
+			}
+			report, err := deploy.FlyReport(app)
+			if err != nil {
+				return err
+			}
+			if reportJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			report.Print(os.Stdout)
+			return nil
+		},
+	}
+	flyReportCmd.Flags().BoolVar(&reportJSON, "json", false, "Print the report as JSON")
+
+	// Sub-command: env fly-secrets-export
+	var exportMap, exportKey string
+	var exportNoPrompt bool
+	flySecretsExportCmd := &cobra.Command{
+		Use:   "fly-secrets-export [app]",
+		Short: "Rebuild .env.secrets.production.age from the secrets set on the Fly app",
+		Long: `For apps deployed before adopting the registry. Fly.io only reveals secret
+names, so values come from a mapping file (KEY=value lines, e.g. an old .env
+file; a .age version is preferred) or are prompted for with hidden input.
+Only .env.secrets.production.age is written.
+
+Secrets set on the app but missing from the registry are listed so they can
+be added to the registry or unset.
+
+Example:
+  ./wellknown env fly-secrets-export
+  ./wellknown env fly-secrets-export my-app --map old.env --no-prompt`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var app string
+			if len(args) > 0 {
+				app = args[0]
+			} else {
+				name, _, err := deploy.ReadFlyTomlConfig()
+				if err != nil {
+					return err
+				}
+				app = name
+			}
+			names, err := deploy.SecretNames(app)
+			if err != nil {
+				return err
+			}
+			var values map[string]string
+			if exportMap != "" {
+				values, err = env.LoadSecrets(env.SecretsSource{FilePath: exportMap, PreferEncrypted: true})
+				if err != nil {
+					return err
+				}
+			}
+			result, err := env.ImportSecrets(env.ImportSecretsOptions{
+				Registry: wellknown.EnvRegistry,
+				Remote:   names,
+				Values:   values,
+				KeyPath:  exportKey,
+				AppName:  "Wellknown",
+				NoPrompt: exportNoPrompt,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ Wrote %s (%d mapped, %d entered, %d kept)\n",
+				result.Path, len(result.Mapped), len(result.Prompted), len(result.Kept))
+			for _, name := range result.Empty {
+				fmt.Printf("⚠️  %s is set on %s but has no local value\n", name, app)
+			}
+			for _, name := range result.NotRemote {
+				fmt.Printf("💡 %s is not set on %s\n", name, app)
+			}
+			for _, name := range result.Unregistered {
+				fmt.Printf("❓ %s is set on %s but not in the registry\n", name, app)
+			}
+			return nil
+		},
+	}
+	flySecretsExportCmd.Flags().StringVarP(&exportMap, "map", "m", "", "File with KEY=value lines for the values (optional)")
+	flySecretsExportCmd.Flags().StringVar(&exportKey, "key", env.DefaultAgeKeyPath, "Age key file")
+	flySecretsExportCmd.Flags().BoolVar(&exportNoPrompt, "no-prompt", false, "Don't prompt for values missing from the mapping")
+
+	// Sub-command: env status
+	var statusQuiet bool
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the active environment ($APP_ENV) and whether its file drifted",
+		Long: `Reports the environment selected by APP_ENV (default: local) and whether
+.env.<name> misses registry variables or has unknown ones.
+
+--quiet prints a single word plus an optional marker ("production drift"),
+as read by the shell prompt from 'env prompt'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status := wellknown.EnvRegistry.Status()
+			if statusQuiet {
+				fmt.Println(status.Quiet())
+				return nil
+			}
+			fmt.Println(status)
+			return nil
+		},
+	}
+	statusCmd.Flags().BoolVarP(&statusQuiet, "quiet", "q", false, "Print only the name and drift marker")
+
+	// Sub-command: env prompt
+	var promptCommand string
+	promptCmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print a bash/zsh prompt function showing the active environment",
+		Long: `Prints a shell function that shows the active environment in the prompt,
+red for production, yellow for staging and green otherwise, with ⚠ when its
+file has drifted - so you notice before deploying with the wrong env.
+
+Example:
+  eval "$(./wellknown env prompt)"
+  PS1='$(env_ps1) '$PS1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Print(env.GeneratePromptSnippet(env.PromptOptions{Command: promptCommand}))
+			return nil
+		},
+	}
+	promptCmd.Flags().StringVar(&promptCommand, "command", "wellknown env status --quiet", "Status command the prompt runs")
+
+	// Sub-command: env tui
+	var tuiEnv string
+	tuiCmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Browse, edit and validate variables in a terminal UI",
+		Long: `Opens an interactive terminal UI over the registry: browse groups, toggle
+booleans, edit values (secrets are masked), validate and save to the .env
+file, and run the sync workflows without leaving the terminal.
+
+Keys: ←/→ group, ↑/↓ select, enter edit, space toggle, v validate, w save,
+S sync secrets, F sync fly.toml, D sync Dockerfile, q quit.
+
+Example:
+  ./wellknown env tui
+  ./wellknown env tui --env production`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var target *env.Environment
+			switch tuiEnv {
+			case "local":
+				target = env.Local
+			case "production":
+				target = env.Production
+			default:
+				return fmt.Errorf("unknown environment %q (use local or production)", tuiEnv)
+			}
+			return tui.Run(tui.Options{
+				Registry:    wellknown.EnvRegistry,
+				Environment: target,
+				AppName:     "Wellknown",
+				Actions: []tui.Action{
+					{Key: "S", Name: "sync secrets", Run: func() error {
+						return wellknown.MergeSecretsIntoEnv(".env.secrets", tuiEnv, target.FullPath())
+					}},
+					{Key: "F", Name: "sync fly.toml", Run: func() error {
+						return wellknown.SyncFlyTomlEnv("fly.toml", false)
+					}},
+					{Key: "D", Name: "sync Dockerfile", Run: func() error {
+						return wellknown.SyncDockerfileEnvDocs("Dockerfile", false)
+					}},
+				},
+			})
+		},
+	}
+	tuiCmd.Flags().StringVarP(&tuiEnv, "env", "e", "local", "Environment file to edit: local or production")
+
+	envCmd.AddCommand(
+		ciBundleCmd,
+		ciRestoreCmd,
+		compareRemoteCmd,
+		exportCmd,
+		exportShellCmd,
+		flyReportCmd,
+		flySecretsExportCmd,
+		keychainStoreCmd,
+		listCmd,
+		promptCmd,
+		resolvedCmd,
+		secretsWizardCmd,
+		statusCmd,
+		validateCmd,
+		syncDockerfileCmd,
+		syncFlyTomlCmd,
+		syncReferenceCmd,
+		generateLocalCmd,
+		generateProductionCmd,
+		generateExampleCmd,
+		syncSecretsCmd,
+		syncSecretsProductionCmd,
+		tuiCmd,
+	)
+	return envCmd
+}
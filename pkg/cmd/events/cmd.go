@@ -0,0 +1,173 @@
+// Package events provides the "events" command for bulk importing calendar
+// events from CSV/ICS into PocketBase and exporting them back.
+package events
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	wellknown "github.com/joeblew999/wellknown/pkg/pb"
+)
+
+// NewCommand creates the calendar event import/export command
+func NewCommand(app core.App) *cobra.Command {
+	var user, tenant, format string
+
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Import and export calendar events (CSV, ICS)",
+		Long: `Bulk import calendar events from CSV or ICS files into the calendar_events
+collection, and export them back. Events are validated like events created
+through the API; invalid events are skipped and reported by row. Events with a
+UID update the event previously imported with it.
+
+CSV files need a header row with at least title and start (columns: uid, title,
+start, end, allDay, location, description).
+
+Examples:
+  wellknown events import calendar.ics --user alice@example.com
+  wellknown events import events.csv --user alice@example.com --dry-run
+  wellknown events export backup.csv --user alice@example.com`,
+	}
+	eventsCmd.PersistentFlags().StringVar(&user, "user", "", "User email or id (required)")
+	eventsCmd.PersistentFlags().StringVar(&tenant, "tenant", "", "Tenant slug (default tenant if empty)")
+	eventsCmd.PersistentFlags().StringVar(&format, "format", "", "csv or ics (default: from the file extension)")
+	eventsCmd.MarkPersistentFlagRequired("user")
+
+	var dryRun bool
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import events from a CSV or ICS file (\"-\" for stdin)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			userID, err := resolveUser(app, user)
+			if err != nil {
+				return err
+			}
+			f, err := wellknown.ParseEventFormat(format, args[0])
+			if err != nil {
+				return err
+			}
+			in, err := openInput(args[0])
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			out := cmd.OutOrStdout()
+			result, err := wellknown.ImportEvents(app, in, f, wellknown.EventImportOptions{
+				UserID: userID,
+				Tenant: tenant,
+				DryRun: dryRun,
+				Progress: func(p wellknown.EventImportProgress) {
+					if p.Error != nil {
+						fmt.Fprintf(out, "  ✗ row %d %q: %s\n", p.Error.Row, p.Error.Title, formatErrors(p.Error.Errors))
+					}
+					fmt.Fprintf(cmd.ErrOrStderr(), "\r  %d/%d", p.Row, p.Total)
+				},
+			})
+			fmt.Fprintln(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			verb := "Imported"
+			if dryRun {
+				verb = "Validated (dry run)"
+			}
+			fmt.Fprintf(out, "%s %d events: %d new, %d updated, %d skipped\n",
+				verb, result.Total, result.Imported, result.Updated, result.Skipped)
+			if result.Skipped > 0 {
+				return fmt.Errorf("%d events skipped", result.Skipped)
+			}
+			return nil
+		},
+	}
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate without saving")
+
+	exportCmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export events to a CSV or ICS file (stdout if omitted)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			userID, err := resolveUser(app, user)
+			if err != nil {
+				return err
+			}
+			path := ""
+			if len(args) == 1 {
+				path = args[0]
+			}
+			if format == "" && (path == "" || path == "-") {
+				format = "ics"
+			}
+			f, err := wellknown.ParseEventFormat(format, path)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if path != "" && path != "-" {
+				file, err := os.Create(path)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				out = file
+			}
+			n, err := wellknown.ExportEvents(app, out, f, userID, tenant)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d events\n", n)
+			return nil
+		},
+	}
+
+	eventsCmd.AddCommand(importCmd, exportCmd)
+	return eventsCmd
+}
+
+// resolveUser returns the id of the user with the given email or id
+func resolveUser(app core.App, user string) (string, error) {
+	if strings.Contains(user, "@") {
+		record, err := app.FindAuthRecordByEmail("users", user)
+		if err != nil {
+			return "", fmt.Errorf("no user with email %s", user)
+		}
+		return record.Id, nil
+	}
+	record, err := app.FindRecordById("users", user)
+	if err != nil {
+		return "", fmt.Errorf("no user with id %s", user)
+	}
+	return record.Id, nil
+}
+
+// openInput opens path, or stdin for "-"
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// formatErrors renders field errors as "field: message; ..."
+func formatErrors(errs map[string]string) string {
+	parts := make([]string, 0, len(errs))
+	for field, msg := range errs {
+		if field == "" {
+			parts = append(parts, msg)
+		} else {
+			parts = append(parts, field+": "+msg)
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,100 @@
+// Package links provides the "links" command for generating calendar deep links
+// from JSON event data, using the same generators as the web UI.
+package links
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	applecal "github.com/joeblew999/wellknown/pkg/apple/calendar"
+	googlecal "github.com/joeblew999/wellknown/pkg/google/calendar"
+)
+
+// NewCommand creates the deep link generation command
+func NewCommand() *cobra.Command {
+	linksCmd := &cobra.Command{
+		Use:   "links",
+		Short: "Generate calendar deep links from JSON event data",
+		Long: `Generate Google Calendar URLs and Apple Calendar ICS files from JSON event data.
+
+The input is a JSON object with the fields defined in the platform's schema.json
+(title, start, end, location, description, ...). Use "-" to read from stdin.
+
+Examples:
+  wellknown links google event.json
+  cat event.json | wellknown links apple - > event.ics`,
+	}
+
+	googleCmd := &cobra.Command{
+		Use:   "google [file]",
+		Short: "Generate a Google Calendar URL",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readEventData(args[0])
+			if err != nil {
+				return err
+			}
+			url, err := googlecal.GenerateURL(data)
+			if err != nil {
+				return fmt.Errorf("failed to generate URL: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), url)
+			return nil
+		},
+	}
+
+	var dataURI bool
+	appleCmd := &cobra.Command{
+		Use:   "apple [file]",
+		Short: "Generate an Apple Calendar ICS file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readEventData(args[0])
+			if err != nil {
+				return err
+			}
+			if dataURI {
+				uri, err := applecal.GenerateDataURI(data)
+				if err != nil {
+					return fmt.Errorf("failed to generate data URI: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), uri)
+				return nil
+			}
+			ics, err := applecal.GenerateICS(data)
+			if err != nil {
+				return fmt.Errorf("failed to generate ICS: %w", err)
+			}
+			_, err = cmd.OutOrStdout().Write(ics)
+			return err
+		},
+	}
+	appleCmd.Flags().BoolVar(&dataURI, "data-uri", false, "Output a data: URI instead of raw ICS")
+
+	linksCmd.AddCommand(googleCmd, appleCmd)
+	return linksCmd
+}
+
+// readEventData reads a JSON event object from a file path or stdin ("-")
+func readEventData(path string) (map[string]interface{}, error) {
+	var raw []byte
+	var err error
+	if path == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		raw, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event data: %w", err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse event data: %w", err)
+	}
+	return data, nil
+}
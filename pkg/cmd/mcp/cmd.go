@@ -15,6 +15,8 @@ The MCP server runs in stdio mode and provides:
   • Calendar API integration
   • Google OAuth token management
   • Structured data exchange with Claude Desktop
+  • Prompt templates for one-click operations (calendar link from an
+    email, fill a PDF form, ...)
 
 Configure in Claude Desktop's config file to enable this integration.`,
 		Run: func(cmd *cobra.Command, args []string) {
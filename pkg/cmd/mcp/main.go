@@ -19,8 +19,8 @@ func Main(args []string) {
 		log.Fatalf("Failed to create Wellknown: %v", err)
 	}
 
-	// Create MCP server
-	server := pbmcp.NewServer(wk.App)
+	// Create MCP server (with the env registry as configuration documentation)
+	server := pbmcp.NewServer(wk.App).WithEnvRegistry(wellknown.EnvRegistry)
 
 	// Setup context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,61 @@
+// Package pdf provides the "pdf" command, which forwards to the pdfform CLI.
+//
+// pkg/pdf is a separate Go module with its own dependencies (pdfcpu, datastar),
+// so it is not linked into this binary. Instead the command runs the pdfform
+// binary from $PATH (or $PDFFORM_BIN) and passes all arguments through unchanged.
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+)
+
+// BinaryEnvVar overrides the pdfform binary location
+const BinaryEnvVar = "PDFFORM_BIN"
+
+// NewCommand creates the PDF form command
+func NewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pdf [pdfform args...]",
+		Short: "Fill PDF forms (forwards to pdfform)",
+		Long: `Fill PDF forms using the pdfform 5-step workflow.
+
+All arguments are passed through to the pdfform binary:
+  wellknown pdf 1-browse --state VIC
+  wellknown pdf 2-download F3520
+  wellknown pdf serve --port 3000
+
+Install pdfform with:
+  go install github.com/joeblew999/wellknown/pkg/pdf/cmd/pdfform@latest
+
+Set $PDFFORM_BIN to use a binary outside $PATH.`,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bin, err := findBinary()
+			if err != nil {
+				return err
+			}
+
+			c := exec.Command(bin, args...)
+			c.Stdin = os.Stdin
+			c.Stdout = os.Stdout
+			c.Stderr = os.Stderr
+			return c.Run()
+		},
+	}
+}
+
+// findBinary locates the pdfform binary
+func findBinary() (string, error) {
+	if bin := os.Getenv(BinaryEnvVar); bin != "" {
+		return bin, nil
+	}
+	bin, err := exec.LookPath("pdfform")
+	if err != nil {
+		return "", fmt.Errorf("pdfform not found in PATH (set $%s or run: go install github.com/joeblew999/wellknown/pkg/pdf/cmd/pdfform@latest): %w", BinaryEnvVar, err)
+	}
+	return bin, nil
+}
@@ -0,0 +1,82 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Create flow collections (see pkg/flow and pkg/pb/flows.go)
+		func(txApp core.App) error {
+			// Flow definitions (steps, dependencies and schemas; saved when a flow is registered)
+			definitions := core.NewBaseCollection("flow_definitions")
+			definitions.Fields.Add(
+				&core.TextField{
+					Name:     "flow_id",
+					Required: true,
+				},
+				&core.TextField{
+					Name: "title",
+				},
+				&core.JSONField{
+					Name:     "definition",
+					Required: true,
+				},
+				&core.AutodateField{
+					Name:     "updated",
+					OnCreate: true,
+					OnUpdate: true,
+				},
+			)
+			definitions.AddIndex("idx_flow_definitions_flow_id", true, "flow_id", "")
+			if err := txApp.Save(definitions); err != nil {
+				return err
+			}
+
+			// Flow runs (status, input and output per step)
+			runs := core.NewBaseCollection("flow_runs")
+			runs.Fields.Add(
+				&core.TextField{
+					Name:     "run_id",
+					Required: true,
+				},
+				&core.TextField{
+					Name:     "flow_id",
+					Required: true,
+				},
+				&core.TextField{
+					Name:     "status",
+					Required: true, // pending, waiting, running, completed, failed
+				},
+				&core.JSONField{
+					Name:    "steps",
+					MaxSize: 5 << 20, // Step inputs/outputs can exceed the 1MB default
+				},
+				&core.DateField{
+					Name:     "started_at",
+					Required: true,
+				},
+				&core.DateField{
+					Name: "updated_at",
+				},
+			)
+			runs.AddIndex("idx_flow_runs_run_id", true, "run_id", "")
+			runs.AddIndex("idx_flow_runs_flow_id", false, "flow_id, started_at", "")
+			return txApp.Save(runs)
+		},
+
+		// Down: Remove flow collections
+		func(txApp core.App) error {
+			for _, name := range []string{"flow_runs", "flow_definitions"} {
+				collection, err := txApp.FindCollectionByNameOrId(name)
+				if err != nil {
+					continue
+				}
+				if err := txApp.Delete(collection); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
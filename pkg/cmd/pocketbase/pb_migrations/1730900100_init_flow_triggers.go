@@ -0,0 +1,53 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Create flow_triggers collection (see pkg/pb/flow_triggers.go)
+		func(txApp core.App) error {
+			triggers := core.NewBaseCollection("flow_triggers")
+			triggers.Fields.Add(
+				&core.TextField{
+					Name:     "flow_id",
+					Required: true,
+				},
+				&core.SelectField{
+					Name:      "kind",
+					Required:  true,
+					MaxSelect: 1,
+					Values:    []string{"webhook", "cron"},
+				},
+				&core.TextField{
+					Name: "schedule", // Cron expression, e.g. "0 2 * * *" (cron triggers)
+				},
+				&core.TextField{
+					Name:   "secret", // HMAC key (webhook triggers)
+					Hidden: true,
+				},
+				&core.TextField{
+					Name: "step", // Step that receives the webhook payload
+				},
+				&core.JSONField{
+					Name: "inputs", // Fixed step inputs: {"<step>": {...}}
+				},
+				&core.BoolField{
+					Name: "enabled",
+				},
+			)
+			triggers.AddIndex("idx_flow_triggers_flow_id", false, "flow_id", "")
+			return txApp.Save(triggers)
+		},
+
+		// Down: Remove flow_triggers collection
+		func(txApp core.App) error {
+			collection, err := txApp.FindCollectionByNameOrId("flow_triggers")
+			if err != nil {
+				return nil
+			}
+			return txApp.Delete(collection)
+		},
+	)
+}
@@ -0,0 +1,72 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// tenantScopedCollections get a tenant field (the tenant slug; empty for the
+// default tenant). See pkg/pb/tenant.go.
+var tenantScopedCollections = []string{"google_tokens", "flow_runs", "flow_triggers"}
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Create tenants collection and scope existing collections
+		func(txApp core.App) error {
+			tenants := core.NewBaseCollection("tenants")
+			tenants.Fields.Add(
+				&core.TextField{
+					Name:     "slug", // Subdomain / X-Tenant header value
+					Required: true,
+					Pattern:  `^[a-z0-9][a-z0-9-]*$`,
+				},
+				&core.TextField{
+					Name: "name",
+				},
+				&core.JSONField{
+					Name: "env", // Env registry overrides: {"GOOGLE_CLIENT_ID": "..."}
+				},
+				&core.BoolField{
+					Name: "active",
+				},
+			)
+			tenants.AddIndex("idx_tenants_slug", true, "slug", "")
+			if err := txApp.Save(tenants); err != nil {
+				return err
+			}
+
+			for _, name := range tenantScopedCollections {
+				collection, err := txApp.FindCollectionByNameOrId(name)
+				if err != nil {
+					return err
+				}
+				collection.Fields.Add(&core.TextField{Name: "tenant"})
+				collection.AddIndex("idx_"+name+"_tenant", false, "tenant", "")
+				if err := txApp.Save(collection); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+
+		// Down: Remove tenant fields and the tenants collection
+		func(txApp core.App) error {
+			for _, name := range tenantScopedCollections {
+				collection, err := txApp.FindCollectionByNameOrId(name)
+				if err != nil {
+					continue
+				}
+				collection.RemoveIndex("idx_" + name + "_tenant")
+				collection.Fields.RemoveByName("tenant")
+				if err := txApp.Save(collection); err != nil {
+					return err
+				}
+			}
+
+			tenants, err := txApp.FindCollectionByNameOrId("tenants")
+			if err != nil {
+				return nil
+			}
+			return txApp.Delete(tenants)
+		},
+	)
+}
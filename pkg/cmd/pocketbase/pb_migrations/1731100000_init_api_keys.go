@@ -0,0 +1,88 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Create api_keys and api_key_usage collections
+		func(txApp core.App) error {
+			keys := core.NewBaseCollection("api_keys")
+			keys.Fields.Add(
+				&core.TextField{
+					Name:     "name", // Who the key was issued to
+					Required: true,
+				},
+				&core.TextField{
+					Name:     "key_hash", // SHA-256 of the key; the key itself is shown once
+					Required: true,
+					Hidden:   true,
+				},
+				&core.TextField{
+					Name: "prefix", // First characters of the key, to recognise it
+				},
+				&core.TextField{
+					Name: "tenant",
+				},
+				&core.NumberField{
+					Name: "rate_limit", // Requests per second; 0 uses API_KEY_RATE_LIMIT_RPS
+				},
+				&core.BoolField{
+					Name: "enabled",
+				},
+				&core.AutodateField{
+					Name:     "created",
+					OnCreate: true,
+				},
+			)
+			keys.AddIndex("idx_api_keys_key_hash", true, "key_hash", "")
+			keys.AddIndex("idx_api_keys_tenant", false, "tenant", "")
+			if err := txApp.Save(keys); err != nil {
+				return err
+			}
+
+			usage := core.NewBaseCollection("api_key_usage")
+			usage.Fields.Add(
+				&core.RelationField{
+					Name:          "api_key",
+					Required:      true,
+					CollectionId:  keys.Id,
+					CascadeDelete: true,
+					MaxSelect:     1,
+				},
+				&core.TextField{
+					Name:     "day", // YYYY-MM-DD (UTC)
+					Required: true,
+				},
+				&core.NumberField{
+					Name: "requests", // Requests served
+				},
+				&core.NumberField{
+					Name: "limited", // Requests rejected by the rate limit
+				},
+				&core.AutodateField{
+					Name:     "updated",
+					OnCreate: true,
+					OnUpdate: true,
+				},
+			)
+			usage.AddIndex("idx_api_key_usage_key_day", true, "api_key, day", "")
+			return txApp.Save(usage)
+		},
+
+		// Down: Drop both collections
+		func(txApp core.App) error {
+			for _, name := range []string{"api_key_usage", "api_keys"} {
+				collection, err := txApp.FindCollectionByNameOrId(name)
+				if err != nil {
+					continue
+				}
+				if err := txApp.Delete(collection); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
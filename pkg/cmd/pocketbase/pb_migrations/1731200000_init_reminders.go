@@ -0,0 +1,135 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Create calendar_events and push_subscriptions collections
+		func(txApp core.App) error {
+			events := core.NewBaseCollection("calendar_events")
+			events.Fields.Add(
+				&core.TextField{
+					Name:     "user_id",
+					Required: true,
+				},
+				&core.TextField{
+					Name: "tenant",
+				},
+				&core.TextField{
+					Name: "google_event_id",
+				},
+				&core.TextField{
+					Name:     "title",
+					Required: true,
+				},
+				&core.TextField{
+					Name: "location",
+				},
+				&core.TextField{
+					Name: "description",
+				},
+				&core.URLField{
+					Name: "html_link", // Event page in Google Calendar
+				},
+				&core.DateField{
+					Name:     "start",
+					Required: true,
+				},
+				&core.DateField{
+					Name: "end",
+				},
+				&core.TextField{
+					Name: "timezone", // IANA zone the reminder shows times in (default UTC)
+				},
+				&core.TextField{
+					Name: "email", // Reminder recipient (default: the user's email)
+				},
+				&core.NumberField{
+					Name: "remind_before", // Minutes; 0 uses REMINDER_LEAD_MINUTES
+				},
+
+				// Reminder state, maintained by the reminder worker (pkg/pb/reminders.go)
+				&core.DateField{
+					Name: "remind_at", // start - remind_before
+				},
+				&core.SelectField{
+					Name:      "reminder_status",
+					Values:    []string{"pending", "sending", "sent", "failed"},
+					MaxSelect: 1,
+				},
+				&core.BoolField{
+					Name: "reminder_email_sent",
+				},
+				&core.BoolField{
+					Name: "reminder_push_sent",
+				},
+				&core.NumberField{
+					Name: "reminder_attempts",
+				},
+				&core.TextField{
+					Name: "reminder_error",
+				},
+				&core.DateField{
+					Name: "reminded_at",
+				},
+				&core.AutodateField{
+					Name:     "created",
+					OnCreate: true,
+				},
+			)
+			events.AddIndex("idx_calendar_events_reminder", false, "reminder_status, remind_at", "")
+			events.AddIndex("idx_calendar_events_user", false, "user_id, tenant", "")
+			if err := txApp.Save(events); err != nil {
+				return err
+			}
+
+			subscriptions := core.NewBaseCollection("push_subscriptions")
+			subscriptions.Fields.Add(
+				&core.TextField{
+					Name:     "user_id",
+					Required: true,
+				},
+				&core.TextField{
+					Name: "tenant",
+				},
+				&core.TextField{
+					Name:     "endpoint", // Push service URL
+					Required: true,
+				},
+				&core.TextField{
+					Name:     "p256dh",
+					Required: true,
+					Hidden:   true,
+				},
+				&core.TextField{
+					Name:     "auth",
+					Required: true,
+					Hidden:   true,
+				},
+				&core.AutodateField{
+					Name:     "created",
+					OnCreate: true,
+				},
+			)
+			subscriptions.AddIndex("idx_push_subscriptions_endpoint", true, "endpoint", "")
+			subscriptions.AddIndex("idx_push_subscriptions_user", false, "user_id, tenant", "")
+			return txApp.Save(subscriptions)
+		},
+
+		// Down: Drop both collections
+		func(txApp core.App) error {
+			for _, name := range []string{"push_subscriptions", "calendar_events"} {
+				collection, err := txApp.FindCollectionByNameOrId(name)
+				if err != nil {
+					continue
+				}
+				if err := txApp.Delete(collection); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,39 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Add the fields imports match and round-trip on
+		func(txApp core.App) error {
+			events, err := txApp.FindCollectionByNameOrId("calendar_events")
+			if err != nil {
+				return err
+			}
+			events.Fields.Add(
+				&core.TextField{
+					Name: "uid", // ICS UID / CSV uid; re-importing a file updates its events
+				},
+				&core.BoolField{
+					Name: "all_day",
+				},
+			)
+			events.AddIndex("idx_calendar_events_uid", false, "user_id, tenant, uid", "")
+			return txApp.Save(events)
+		},
+
+		// Down: Remove them
+		func(txApp core.App) error {
+			events, err := txApp.FindCollectionByNameOrId("calendar_events")
+			if err != nil {
+				return nil
+			}
+			events.RemoveIndex("idx_calendar_events_uid")
+			events.Fields.RemoveByName("uid")
+			events.Fields.RemoveByName("all_day")
+			return txApp.Save(events)
+		},
+	)
+}
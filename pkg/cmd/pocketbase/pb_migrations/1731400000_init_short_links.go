@@ -0,0 +1,47 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Create short_links collection (see pkg/shortlink and pkg/pb/short_links.go)
+		func(txApp core.App) error {
+			links := core.NewBaseCollection("short_links")
+			links.Fields.Add(
+				&core.TextField{
+					Name:     "code", // Path segment of /s/<code>
+					Required: true,
+				},
+				&core.TextField{
+					Name:     "target", // Generated deep link
+					Required: true,
+					Max:      16384,
+				},
+				&core.NumberField{
+					Name: "clicks",
+				},
+				&core.DateField{
+					Name: "last_click",
+				},
+				&core.AutodateField{
+					Name:     "created",
+					OnCreate: true,
+				},
+			)
+			links.AddIndex("idx_short_links_code", true, "code", "")
+			links.AddIndex("idx_short_links_target", false, "target", "")
+			return txApp.Save(links)
+		},
+
+		// Down: Remove short_links collection
+		func(txApp core.App) error {
+			collection, err := txApp.FindCollectionByNameOrId("short_links")
+			if err != nil {
+				return nil
+			}
+			return txApp.Delete(collection)
+		},
+	)
+}
@@ -0,0 +1,36 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// googleTokenSecrets are the google_tokens fields kept out of API responses
+// (they are encrypted at rest, see pkg/pb/google_tokens.go)
+var googleTokenSecrets = []string{"access_token", "refresh_token"}
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Hide the token values from the records API
+		func(txApp core.App) error {
+			return setGoogleTokensHidden(txApp, true)
+		},
+
+		// Down: Show them again
+		func(txApp core.App) error {
+			return setGoogleTokensHidden(txApp, false)
+		},
+	)
+}
+
+func setGoogleTokensHidden(txApp core.App, hidden bool) error {
+	tokens, err := txApp.FindCollectionByNameOrId("google_tokens")
+	if err != nil {
+		return err
+	}
+	for _, name := range googleTokenSecrets {
+		if field := tokens.Fields.GetByName(name); field != nil {
+			field.SetHidden(hidden)
+		}
+	}
+	return txApp.Save(tokens)
+}
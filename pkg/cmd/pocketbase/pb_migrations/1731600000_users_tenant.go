@@ -0,0 +1,32 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Bind users to the tenant they signed up under (empty for the
+		// default tenant). See pkg/pb/tenant.go.
+		func(txApp core.App) error {
+			users, err := txApp.FindCollectionByNameOrId("users")
+			if err != nil {
+				return err
+			}
+			users.Fields.Add(&core.TextField{Name: "tenant"})
+			users.AddIndex("idx_users_tenant", false, "tenant", "")
+			return txApp.Save(users)
+		},
+
+		// Down: Remove the tenant field
+		func(txApp core.App) error {
+			users, err := txApp.FindCollectionByNameOrId("users")
+			if err != nil {
+				return nil
+			}
+			users.RemoveIndex("idx_users_tenant")
+			users.Fields.RemoveByName("tenant")
+			return txApp.Save(users)
+		},
+	)
+}
@@ -0,0 +1,87 @@
+// Package port provides the "port" command for freeing, waiting on and
+// allocating local TCP ports (pkg/netutil). The Makefile and e2e harness use
+// it instead of lsof/kill and fixed sleeps so they work on every platform.
+package port
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joeblew999/wellknown/pkg/netutil"
+)
+
+// NewCommand creates the port management command
+func NewCommand() *cobra.Command {
+	portCmd := &cobra.Command{
+		Use:   "port",
+		Short: "Manage local TCP ports (kill, wait, free)",
+		Long: `Cross-platform port management for development and e2e tests.
+
+Examples:
+  wellknown port kill 8080 8090      # Kill whatever listens on 8080 and 8090
+  wellknown port wait localhost:8090 # Block until the server accepts connections
+  wellknown port free                # Print a free port`,
+	}
+
+	killCmd := &cobra.Command{
+		Use:   "kill PORT...",
+		Short: "Kill the processes listening on the given ports",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, arg := range args {
+				port, err := strconv.Atoi(arg)
+				if err != nil {
+					return fmt.Errorf("invalid port %q", arg)
+				}
+				killed, err := netutil.KillPort(port)
+				if err != nil {
+					return fmt.Errorf("port %d: %w", port, err)
+				}
+				if len(killed) == 0 {
+					fmt.Fprintf(cmd.OutOrStdout(), "Port %d is free\n", port)
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Port %d: killed PID %v\n", port, killed)
+			}
+			return nil
+		},
+	}
+
+	var timeout time.Duration
+	waitCmd := &cobra.Command{
+		Use:   "wait ADDR",
+		Short: "Wait until a server accepts connections on host:port",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+			if err := netutil.WaitForPort(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is up\n", args[0])
+			return nil
+		},
+	}
+	waitCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "How long to wait")
+
+	freeCmd := &cobra.Command{
+		Use:   "free",
+		Short: "Print a currently free port",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			port, err := netutil.FindFreePort()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), port)
+			return nil
+		},
+	}
+
+	portCmd.AddCommand(killCmd, waitCmd, freeCmd)
+	return portCmd
+}
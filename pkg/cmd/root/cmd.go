@@ -0,0 +1,65 @@
+// Package root assembles the unified "wellknown" CLI.
+//
+// Every subcommand is provided by its own package under pkg/cmd so the
+// standalone binaries (PocketBase main.go, pdfform, ...) stay thin wrappers
+// around the same code.
+package root
+
+import (
+	"github.com/spf13/cobra"
+
+	certscmd "github.com/joeblew999/wellknown/pkg/cmd/certs"
+	envcmd "github.com/joeblew999/wellknown/pkg/cmd/env"
+	"github.com/joeblew999/wellknown/pkg/cmd/links"
+	"github.com/joeblew999/wellknown/pkg/cmd/mcp"
+	"github.com/joeblew999/wellknown/pkg/cmd/pdf"
+	portcmd "github.com/joeblew999/wellknown/pkg/cmd/port"
+	schemacmd "github.com/joeblew999/wellknown/pkg/cmd/schema"
+	"github.com/joeblew999/wellknown/pkg/cmd/serve"
+	"github.com/joeblew999/wellknown/pkg/cmd/setup"
+	testdatagen "github.com/joeblew999/wellknown/pkg/cmd/testdata-gen"
+)
+
+// NewCommand creates the wellknown root command with all subcommands registered
+func NewCommand() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "wellknown",
+		Short: "Wellknown developer toolkit",
+		Long: `wellknown - deep links, environment management and PDF forms
+
+Subcommands:
+  certs     Locally trusted HTTPS certificates for development
+  env       Environment variable management (list, validate, sync, ...)
+  pdf       Fill PDF forms (forwards to pdfform)
+  links     Generate Google/Apple calendar deep links from JSON
+  mcp       Start MCP server for Claude Desktop
+  port      Kill, wait for or allocate local TCP ports
+  schema    Generate PocketBase collections from JSON Schemas
+  serve     Start the standalone deep link demo server
+  setup     Set up cloud providers (Google Cloud OAuth)
+  testdata  Generate schema-validated test data for E2E tests
+
+The PocketBase server (OAuth, calendar API, admin UI) is a separate binary:
+  go run . serve`,
+		SilenceUsage: true,
+	}
+
+	testdataCmd := testdatagen.NewCommand()
+	testdataCmd.Use = "testdata"
+	testdataCmd.Aliases = []string{"gen-testdata"}
+
+	rootCmd.AddCommand(
+		certscmd.NewCommand(),
+		envcmd.NewCommand(),
+		pdf.NewCommand(),
+		links.NewCommand(),
+		mcp.NewCommand(),
+		portcmd.NewCommand(),
+		schemacmd.NewCommand(),
+		serve.NewCommand(),
+		setup.NewCommand(),
+		testdataCmd,
+	)
+
+	return rootCmd
+}
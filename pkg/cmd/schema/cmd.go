@@ -0,0 +1,163 @@
+// Package schema provides the "schema" command, which derives database
+// definitions from the JSON Schemas that drive forms and test data
+// (pkg/schema, pkg/testgen).
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/joeblew999/wellknown/pkg/testgen"
+)
+
+// NewCommand creates the schema command
+func NewCommand() *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Generate PocketBase collections from JSON Schemas",
+	}
+
+	var root, out, migrationDir, migrationName string
+	pocketbaseCmd := &cobra.Command{
+		Use:   "pocketbase [[name=]schema.json ...]",
+		Short: "Convert JSON Schemas to PocketBase collections and a migration",
+		Long: `Converts JSON Schemas to PocketBase collection definitions: field types,
+required fields, select options from enums and relations from x-ref hints.
+
+Without arguments every schema under --root is converted (the same discovery
+as "wellknown testdata"), named after its namespace: pkg/google/calendar/
+schema.json becomes the google_calendar collection. A schema's x-collection
+keyword names it instead, and makes it canonical for "wellknown schema drift".
+Name a schema explicitly with name=path.
+
+The collections are printed as JSON (the PocketBase dashboard's import
+format) or written with --out; --migration writes a Go migration.
+
+Examples:
+  wellknown schema pocketbase                              # All schemas, JSON to stdout
+  wellknown schema pocketbase events=event.schema.json --out collections.json
+  wellknown schema pocketbase --migration pkg/cmd/pocketbase/pb_migrations`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sources, err := schemaSources(root, args)
+			if err != nil {
+				return err
+			}
+			if len(sources) == 0 {
+				return fmt.Errorf("no schemas found under %s", root)
+			}
+
+			var collections []*schema.PBCollection
+			for _, src := range sources {
+				data, err := os.ReadFile(src.path)
+				if err != nil {
+					return fmt.Errorf("failed to read schema: %w", err)
+				}
+				c, err := schema.PocketBaseCollection(src.name, data)
+				if err != nil {
+					return fmt.Errorf("%s: %w", src.path, err)
+				}
+				collections = append(collections, c)
+			}
+
+			if migrationDir != "" {
+				src, err := schema.PocketBaseMigration(collections)
+				if err != nil {
+					return err
+				}
+				path := filepath.Join(migrationDir, schema.MigrationFilename(time.Now(), migrationName))
+				if err := os.WriteFile(path, src, 0644); err != nil {
+					return fmt.Errorf("failed to write migration: %w", err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "✅ Wrote migration for %d collection(s): %s\n", len(collections), path)
+				if out == "" {
+					return nil
+				}
+			}
+
+			data, err := json.MarshalIndent(collections, "", "  ")
+			if err != nil {
+				return err
+			}
+			data = append(data, '\n')
+			if out == "" {
+				_, err = cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := os.WriteFile(out, data, 0644); err != nil {
+				return fmt.Errorf("failed to write collections: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "✅ Wrote %d collection(s): %s\n", len(collections), out)
+			return nil
+		},
+	}
+	pocketbaseCmd.Flags().StringVar(&root, "root", ".", "Module root to search for schemas when none are given")
+	pocketbaseCmd.Flags().StringVarP(&out, "out", "o", "", "Write the collections JSON to this file (default: stdout)")
+	pocketbaseCmd.Flags().StringVar(&migrationDir, "migration", "", "Write a Go migration into this directory")
+	pocketbaseCmd.Flags().StringVar(&migrationName, "migration-name", "init_schema_collections", "Migration file name, after the timestamp")
+
+	schemaCmd.AddCommand(pocketbaseCmd)
+	return schemaCmd
+}
+
+// schemaSource is a schema file and the collection it becomes
+type schemaSource struct {
+	name string
+	path string
+}
+
+// schemaSources returns the schemas named in args ([name=]path), or every
+// schema discovered under root
+func schemaSources(root string, args []string) ([]schemaSource, error) {
+	if len(args) == 0 {
+		found, err := testgen.Discover(root, testgen.DefaultSchemaGlobs)
+		if err != nil {
+			return nil, err
+		}
+		sources := make([]schemaSource, len(found))
+		for i, s := range found {
+			sources[i] = schemaSource{name: declaredCollection(s.SchemaPath, schema.CollectionName(s.Namespace)), path: s.SchemaPath}
+		}
+		return sources, nil
+	}
+
+	sources := make([]schemaSource, len(args))
+	for i, arg := range args {
+		name, path, ok := strings.Cut(arg, "=")
+		if !ok {
+			path, name = arg, declaredCollection(arg, collectionNameFor(arg))
+		}
+		sources[i] = schemaSource{name: name, path: path}
+	}
+	return sources, nil
+}
+
+// declaredCollection returns the x-collection of the schema at path, or
+// fallback when it has none (an unreadable schema is reported when it is read)
+func declaredCollection(path, fallback string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fallback
+	}
+	if name := schema.SchemaCollectionName(data); name != "" {
+		return name
+	}
+	return fallback
+}
+
+// collectionNameFor names the collection of a schema file like discovery
+// does: dir/schema.json after its directory, name.schema.json after name
+func collectionNameFor(path string) string {
+	base := filepath.Base(path)
+	if base == schema.SchemaFilename {
+		dir := filepath.ToSlash(filepath.Clean(filepath.Dir(path)))
+		return schema.CollectionName(strings.TrimPrefix(dir, "pkg/"))
+	}
+	return schema.CollectionName(strings.TrimSuffix(strings.TrimSuffix(base, ".json"), ".schema"))
+}
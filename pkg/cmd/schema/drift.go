@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	wellknown "github.com/joeblew999/wellknown/pkg/pb"
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// NewDriftCommand creates the "schema drift" command, which compares app's
+// collections with their canonical JSON Schemas
+func NewDriftCommand(app core.App) *cobra.Command {
+	var root, migrationDir, migrationName string
+	var asJSON bool
+
+	driftCmd := &cobra.Command{
+		Use:          "drift",
+		Short:        "Compare PocketBase collections with their JSON Schemas",
+		SilenceUsage: true,
+		Long: `Compares the database's collections with the JSON Schemas that define them:
+every schema under --root with an x-collection keyword is canonical for that
+collection. Missing collections and fields, type mismatches, required flags,
+select values and relation targets are reported; fields only the database has
+are not drift. The server runs the same check on start (SCHEMA_DRIFT_CHECK).
+
+--migration writes a Go migration correcting what can be corrected without
+moving data; type and relation changes become TODO comments in it. Review it
+before running "migrate up".
+
+Exits with an error when drift is found.
+
+Examples:
+  wellknown schema drift
+  wellknown schema drift --json
+  wellknown schema drift --migration pkg/cmd/pocketbase/pb_migrations`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			want, drifts, err := wellknown.CheckSchemaDrift(app, root)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+
+			if asJSON {
+				if drifts == nil {
+					drifts = []schema.PBDrift{}
+				}
+				data, err := json.MarshalIndent(drifts, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(out, string(data))
+			} else {
+				if len(want) == 0 {
+					fmt.Fprintf(out, "No schemas with %s under %s\n", schema.PBCollectionKeyword, root)
+					return nil
+				}
+				for _, c := range want {
+					clean := true
+					for _, d := range drifts {
+						if d.Collection == c.Name {
+							fmt.Fprintf(out, "❌ %s\n", d)
+							clean = false
+						}
+					}
+					if clean {
+						fmt.Fprintf(out, "✅ %s\n", c.Name)
+					}
+				}
+			}
+			if len(drifts) == 0 {
+				return nil
+			}
+
+			if migrationDir != "" {
+				src, err := schema.PocketBaseDriftMigration(want, drifts)
+				if err != nil {
+					return err
+				}
+				path := filepath.Join(migrationDir, schema.MigrationFilename(time.Now(), migrationName))
+				if err := os.WriteFile(path, src, 0644); err != nil {
+					return fmt.Errorf("failed to write migration: %w", err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "✅ Wrote corrective migration: %s\n", path)
+			}
+			return fmt.Errorf("schema drift: %d difference(s)", len(drifts))
+		},
+	}
+	driftCmd.Flags().StringVar(&root, "root", ".", "Module root to search for schemas")
+	driftCmd.Flags().StringVar(&migrationDir, "migration", "", "Write a corrective Go migration into this directory")
+	driftCmd.Flags().StringVar(&migrationName, "migration-name", "fix_schema_drift", "Migration file name, after the timestamp")
+	driftCmd.Flags().BoolVar(&asJSON, "json", false, "Print the drift as JSON")
+
+	return driftCmd
+}
@@ -0,0 +1,105 @@
+// Package serve provides the "serve" command for the standalone demo server (pkg/server).
+//
+// This is the lightweight deep link demo without PocketBase. For the full
+// application (OAuth, calendar API, admin UI) run the PocketBase binary instead.
+package serve
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joeblew999/wellknown/pkg/certs"
+	"github.com/joeblew999/wellknown/pkg/guard"
+	"github.com/joeblew999/wellknown/pkg/netutil"
+	"github.com/joeblew999/wellknown/pkg/server"
+	"github.com/joeblew999/wellknown/pkg/shortlink"
+)
+
+// NewCommand creates the demo server command
+func NewCommand() *cobra.Command {
+	var port, tlsCert, tlsKey, linksFile string
+	var gzip, localTLS bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the standalone deep link demo server",
+		Long: `Start the standalone deep link demo server (no PocketBase).
+
+Serves the Google/Apple calendar forms, examples and tools pages.
+
+Examples:
+  wellknown serve               # Start on port 8080
+  wellknown serve --port 3000   # Start on custom port
+  wellknown serve --port 0      # Start on any free port
+  wellknown serve --tls         # HTTPS with certificates from the local CA
+  wellknown serve --links .data/links.json   # Keep short links and clicks across restarts
+  wellknown serve --tls-cert .data/certs/cert.pem --tls-key .data/certs/key.pem`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			port, err := resolvePort(port)
+			if err != nil {
+				return err
+			}
+
+			middleware := []server.Middleware{server.Recovery}
+			if gzip {
+				middleware = append(middleware, server.Gzip)
+			}
+			opts := []server.Option{
+				server.WithMiddleware(middleware...),
+				server.WithAPIGuard(guard.ConfigFromRegistry(nil)), // CORS_ORIGINS, RATE_LIMIT_RPS
+			}
+			if linksFile != "" {
+				links, err := shortlink.NewFileStore(linksFile)
+				if err != nil {
+					return err
+				}
+				opts = append(opts, server.WithShortLinks(links))
+			}
+			switch {
+			case tlsCert != "" || tlsKey != "":
+				opts = append(opts, server.WithTLS(tlsCert, tlsKey))
+			case localTLS:
+				opts = append(opts, server.WithCertProvider(certs.Provider(certs.DefaultFiles())))
+			}
+
+			srv, err := server.New(port, opts...)
+			if err != nil {
+				return err
+			}
+			return srv.Start()
+		},
+	}
+	cmd.Flags().StringVarP(&port, "port", "p", "8080", "Port to run the server on")
+	cmd.Flags().BoolVar(&localTLS, "tls", false, "Serve HTTPS with locally trusted certificates (see 'wellknown certs')")
+	cmd.Flags().StringVar(&tlsCert, "tls-cert", "", "TLS certificate file (serves HTTPS; e.g. from 'wellknown certs generate')")
+	cmd.Flags().StringVar(&tlsKey, "tls-key", "", "TLS key file")
+	cmd.Flags().BoolVar(&gzip, "gzip", false, "Compress responses")
+	cmd.Flags().StringVar(&linksFile, "links", "", "JSON file to keep short links and click counts in (default: memory)")
+
+	return cmd
+}
+
+// resolvePort picks a free port for "0" and otherwise fails early when the
+// port is taken, naming the process holding it
+func resolvePort(port string) (string, error) {
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return "", fmt.Errorf("invalid port %q", port)
+	}
+	if n == 0 {
+		n, err = netutil.FindFreePort()
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(n), nil
+	}
+
+	var inUse *netutil.PortInUseError
+	if err := netutil.CheckPortFree(n); errors.As(err, &inUse) {
+		return "", fmt.Errorf("%w (free it with 'wellknown port kill %d')", err, n)
+	}
+	return port, nil
+}
@@ -0,0 +1,77 @@
+// Package setup provides the "setup" command for one-time provider setup
+// wizards (pkg/setup). Values are stored in the encrypted secrets file that
+// the "env" command manages, not in a separate .env.
+package setup
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/guard"
+	"github.com/joeblew999/wellknown/pkg/server"
+	"github.com/joeblew999/wellknown/pkg/setup/gcp"
+)
+
+// NewCommand creates the setup command
+func NewCommand() *cobra.Command {
+	setupCmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Set up cloud providers (Google Cloud OAuth)",
+	}
+
+	var web, cli bool
+	var port, envName, keyPath string
+	gcpCmd := &cobra.Command{
+		Use:   "gcp",
+		Short: "Create the Google Cloud OAuth client, in the terminal or the browser",
+		Long: `Walks through the Google Cloud project, APIs, consent screen and OAuth
+client that Google sign-in and the Calendar API need. The project ID and
+credentials are stored in .env.secrets.<env>.age, encrypted with the age key.
+
+Examples:
+  wellknown setup gcp --cli              # Prompts in the terminal (default)
+  wellknown setup gcp --web              # Wizard at http://localhost:8080/tools/gcp-setup
+  wellknown setup gcp --cli --env production`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if web && cli {
+				return errors.New("--web and --cli are mutually exclusive")
+			}
+			store := &gcp.Store{KeyPath: keyPath}
+			switch envName {
+			case "local":
+				store.Environment = env.SecretsLocal
+			case "production":
+				store.Environment = env.SecretsProduction
+			default:
+				return fmt.Errorf("unknown environment %q (use local or production)", envName)
+			}
+
+			if !web {
+				_, err := gcp.RunCLI(gcp.CLIOptions{Store: store, Out: cmd.OutOrStdout()})
+				return err
+			}
+
+			srv, err := server.New(port,
+				server.WithMiddleware(server.Recovery),
+				server.WithAPIGuard(guard.ConfigFromRegistry(nil)),
+				server.WithGCPSetup(store),
+			)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "🔗 GCP setup wizard: %s/tools/gcp-setup\n", srv.LocalURL)
+			return srv.Start()
+		},
+	}
+	gcpCmd.Flags().BoolVar(&web, "web", false, "Run the wizard in the browser")
+	gcpCmd.Flags().BoolVar(&cli, "cli", false, "Run the wizard in the terminal (default)")
+	gcpCmd.Flags().StringVarP(&port, "port", "p", "8080", "Port of the web wizard")
+	gcpCmd.Flags().StringVarP(&envName, "env", "e", "local", "Secrets file to write: local or production")
+	gcpCmd.Flags().StringVar(&keyPath, "key", env.DefaultAgeKeyPath, "Age key file")
+
+	setupCmd.AddCommand(gcpCmd)
+	return setupCmd
+}
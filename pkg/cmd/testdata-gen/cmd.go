@@ -18,6 +18,8 @@ Populates the database with realistic test data for:
   • Test collections
 
 Use this to quickly set up a development environment with data to work with.`,
+		// Flags are parsed by Main (flag package), so pass them through untouched
+		DisableFlagParsing: true,
 		Run: func(cmd *cobra.Command, args []string) {
 			Main(args)
 		},
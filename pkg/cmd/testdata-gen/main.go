@@ -7,6 +7,7 @@ package testdatagen
 import (
 	"flag"
 	"log"
+	"strings"
 
 	"github.com/joeblew999/wellknown/pkg/testgen"
 )
@@ -17,13 +18,29 @@ func Main(args []string) {
 	fs := flag.NewFlagSet("gen-testdata", flag.ExitOnError)
 	outputDir := fs.String("output", "tests/e2e/generated", "Output directory")
 	verbose := fs.Bool("v", false, "Verbose logging")
+	root := fs.String("root", ".", "Module root to search for schemas")
+	globs := fs.String("schemas", strings.Join(testgen.DefaultSchemaGlobs, ","), "Comma-separated schema globs relative to -root (** matches any dirs)")
+	records := fs.Int("records", 3, "Related records generated per schema (x-ref hints link them; 0 disables)")
+	now := fs.String("now", "", "Base time for generated timestamps and datetimes, e.g. 2025-03-30T01:30 (default: current time)")
 	fs.Parse(args)
 
+	clock := testgen.SystemClock
+	if *now != "" {
+		var err error
+		if clock, err = testgen.ParseClock(*now); err != nil {
+			log.Fatalf("❌ -now: %v", err)
+		}
+	}
+
 	log.Println("🔧 Generating schema-validated test data...")
 
 	opts := testgen.GenerateOptions{
-		OutputDir: *outputDir,
-		Verbose:   *verbose,
+		OutputDir:   *outputDir,
+		Verbose:     *verbose,
+		Root:        *root,
+		SchemaGlobs: strings.Split(*globs, ","),
+		Records:     *records,
+		Clock:       clock,
 	}
 
 	if err := testgen.Generate(opts); err != nil {
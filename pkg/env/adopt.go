@@ -0,0 +1,142 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// ================================================================
+// Adopting Existing Secrets
+// ================================================================
+// Teams adopting the registry on an app that is already deployed have their
+// secrets on the platform (e.g. Fly.io) and nowhere else. Platforms only
+// reveal secret names, so ImportSecrets rebuilds the local encrypted file
+// from those names: values come from a mapping (e.g. an old .env file or a
+// password manager export) or are prompted for, and names the registry does
+// not know are reported so they can be added (or unset remotely).
+
+// ImportSecretsOptions configures ImportSecrets
+type ImportSecretsOptions struct {
+	Registry    *Registry         // Registry defining the secrets (required)
+	Remote      []string          // Secret names set on the platform (values are unavailable)
+	Values      map[string]string // Known values, e.g. from a mapping file (optional)
+	Environment *Environment      // Secrets file to write (default: SecretsProduction; only its .age version is written)
+	KeyPath     string            // Age key used to encrypt (and read existing values) (default: DefaultAgeKeyPath)
+	AppName     string            // Application name for the file header (default: "Application")
+	NoPrompt    bool              // Leave secrets without a known value empty instead of prompting
+	Out         io.Writer         // Where prompts are written (default: os.Stdout)
+
+	// ReadSecret reads one value after the prompt has been written
+	// (default: TerminalSecretReader(os.Stdin, Out))
+	ReadSecret func() (string, error)
+}
+
+// ImportSecretsResult reports what ImportSecrets did
+type ImportSecretsResult struct {
+	Path         string   // Encrypted file written
+	Mapped       []string // Values taken from ImportSecretsOptions.Values
+	Prompted     []string // Values entered at the prompt
+	Kept         []string // Existing values in the .age file that were kept
+	Empty        []string // Remote secrets left without a value
+	Unregistered []string // Set remotely but not in the registry (not written)
+	NotRemote    []string // Registry secrets the platform does not have
+}
+
+// ImportSecrets writes the registry secrets that are set remotely to the
+// encrypted secrets file. Values come from opts.Values, else a prompt where
+// Enter keeps the value already in the .age file (with NoPrompt the existing
+// value is kept silently). Mapped and entered values must pass
+// EnvVar.ValidateValue. Secrets the platform lacks keep their existing values.
+//
+// Example:
+//
+//	names, err := deploy.SecretNames("my-app")
+//	result, err := env.ImportSecrets(env.ImportSecretsOptions{
+//	    Registry: registry,
+//	    Remote:   names,
+//	    Values:   oldValues,
+//	})
+//	for _, name := range result.Unregistered {
+//	    fmt.Println("not in registry:", name)
+//	}
+func ImportSecrets(opts ImportSecretsOptions) (*ImportSecretsResult, error) {
+	if opts.Registry == nil {
+		return nil, fmt.Errorf("registry is required")
+	}
+	if opts.Environment == nil {
+		opts.Environment = SecretsProduction
+	}
+	if opts.KeyPath == "" {
+		opts.KeyPath = DefaultAgeKeyPath
+	}
+	if opts.AppName == "" {
+		opts.AppName = "Application"
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.ReadSecret == nil {
+		opts.ReadSecret = TerminalSecretReader(os.Stdin, opts.Out)
+	}
+
+	recipient, existing, err := openSecretsFile(opts.Environment, opts.KeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportSecretsResult{Path: opts.Environment.FullEncryptedPath()}
+	remote := make(map[string]bool, len(opts.Remote))
+	for _, name := range opts.Remote {
+		remote[name] = true
+		if opts.Registry.ByName(name) == nil {
+			result.Unregistered = append(result.Unregistered, name)
+		}
+	}
+	sort.Strings(result.Unregistered)
+
+	secrets := opts.Registry.GetSecrets()
+	values := make(map[string]string, len(secrets))
+	wizard := SecretsWizardOptions{Out: opts.Out, ReadSecret: opts.ReadSecret}
+	for _, v := range secrets {
+		if !remote[v.Name] {
+			result.NotRemote = append(result.NotRemote, v.Name)
+			if existing[v.Name] != "" {
+				values[v.Name] = existing[v.Name] // Never drop what the file already has
+			}
+			continue
+		}
+
+		if value := opts.Values[v.Name]; value != "" {
+			if err := v.ValidateValue(value); err != nil {
+				return nil, fmt.Errorf("mapped value of %s: %w", v.Name, err)
+			}
+			values[v.Name] = value
+			result.Mapped = append(result.Mapped, v.Name)
+			continue
+		}
+
+		var value string
+		if !opts.NoPrompt {
+			if value, err = promptSecret(wizard, v, existing[v.Name] != ""); err != nil {
+				return nil, err
+			}
+		}
+		switch {
+		case value != "":
+			values[v.Name] = value
+			result.Prompted = append(result.Prompted, v.Name)
+		case existing[v.Name] != "":
+			values[v.Name] = existing[v.Name]
+			result.Kept = append(result.Kept, v.Name)
+		default:
+			result.Empty = append(result.Empty, v.Name)
+		}
+	}
+
+	if err := writeSecretsFile(opts.Environment, secrets, values, recipient, opts.AppName); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package env
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportSecrets(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.txt")
+	if _, err := GenerateAgeKey(KeygenOptions{KeyPath: keyPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry([]EnvVar{
+		{Name: "API_KEY", Secret: true},
+		{Name: "SMTP_PASSWORD", Secret: true},
+		{Name: "WEBHOOK_SECRET", Secret: true},
+		{Name: "S3_SECRET_KEY", Secret: true},
+		{Name: "LOG_LEVEL", Default: "info"},
+	})
+	secretsEnv := SecretsProduction.WithBaseDir(dir)
+
+	var out bytes.Buffer
+	result, err := ImportSecrets(ImportSecretsOptions{
+		Registry:    registry,
+		Remote:      []string{"WEBHOOK_SECRET", "LEGACY_TOKEN", "API_KEY", "SMTP_PASSWORD", "DATABASE_URL"},
+		Values:      map[string]string{"API_KEY": "sk_live_123"},
+		Environment: secretsEnv,
+		KeyPath:     keyPath,
+		Out:         &out,
+		ReadSecret:  scriptedReader("smtp-pass", ""), // SMTP_PASSWORD entered, WEBHOOK_SECRET skipped
+	})
+	if err != nil {
+		t.Fatalf("ImportSecrets failed: %v", err)
+	}
+
+	checks := map[string][]string{
+		"Mapped":       result.Mapped,
+		"Prompted":     result.Prompted,
+		"Empty":        result.Empty,
+		"Unregistered": result.Unregistered,
+		"NotRemote":    result.NotRemote,
+	}
+	want := map[string]string{
+		"Mapped":       "API_KEY",
+		"Prompted":     "SMTP_PASSWORD",
+		"Empty":        "WEBHOOK_SECRET",
+		"Unregistered": "DATABASE_URL,LEGACY_TOKEN",
+		"NotRemote":    "S3_SECRET_KEY",
+	}
+	for field, got := range checks {
+		if strings.Join(got, ",") != want[field] {
+			t.Errorf("%s = %v, want %s", field, got, want[field])
+		}
+	}
+	if secretsEnv.Exists() {
+		t.Error("plaintext secrets file must not be written")
+	}
+
+	values := decryptForTest(t, result.Path, keyPath)
+	if values["API_KEY"] != "sk_live_123" || values["SMTP_PASSWORD"] != "smtp-pass" {
+		t.Errorf("unexpected file values: %v", values)
+	}
+
+	// Re-running without prompts keeps what the file already has
+	result, err = ImportSecrets(ImportSecretsOptions{
+		Registry:    registry,
+		Remote:      []string{"API_KEY", "SMTP_PASSWORD"},
+		Environment: secretsEnv,
+		KeyPath:     keyPath,
+		NoPrompt:    true,
+	})
+	if err != nil {
+		t.Fatalf("second import failed: %v", err)
+	}
+	if strings.Join(result.Kept, ",") != "API_KEY,SMTP_PASSWORD" {
+		t.Errorf("Kept = %v", result.Kept)
+	}
+	if values := decryptForTest(t, result.Path, keyPath); values["SMTP_PASSWORD"] != "smtp-pass" {
+		t.Errorf("existing value lost: %v", values)
+	}
+}
+
+// decryptForTest returns the values of an encrypted secrets file
+func decryptForTest(t *testing.T, path, keyPath string) map[string]string {
+	t.Helper()
+	identities, err := loadIdentityFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := decryptWithIdentities(data, identities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ParseSecretsFile(plaintext)
+}
@@ -0,0 +1,220 @@
+// Package env provides layered .env loading with provenance tracking.
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultChainFiles is the standard override order used by LoadChain callers:
+// shared defaults, then developer overrides, then the APP_ENV-specific file.
+// Later files override earlier ones; real environment variables override all files.
+var DefaultChainFiles = []string{".env", ".env.local", ".env.$APP_ENV"}
+
+// SourceEnvironment is the provenance file name for values that were already
+// set in the process environment before the chain was loaded.
+const SourceEnvironment = "(environment)"
+
+// Provenance records where a resolved value came from.
+type Provenance struct {
+	File      string   // File that supplied the winning value (or SourceEnvironment)
+	Line      int      // 1-based line number in File (0 for SourceEnvironment)
+	Overrides []string // Earlier files whose value for the same key was shadowed
+}
+
+// ChainResult is the outcome of resolving a chain of .env files.
+type ChainResult struct {
+	Files   []string              // Files that were found and read, in load order
+	Skipped []string              // Files that didn't exist (or whose $VAR expanded to nothing)
+	Values  map[string]string     // Final resolved values
+	Sources map[string]Provenance // Where each value came from
+	Applied []string              // Keys set in the process environment (LoadChain only)
+}
+
+// Explain returns a one-line description of where name's value came from.
+func (r *ChainResult) Explain(name string) string {
+	src, ok := r.Sources[name]
+	if !ok {
+		return fmt.Sprintf("%s: not set by any file", name)
+	}
+	where := src.File
+	if src.Line > 0 {
+		where = fmt.Sprintf("%s:%d", src.File, src.Line)
+	}
+	if len(src.Overrides) > 0 {
+		return fmt.Sprintf("%s: from %s (overrides %s)", name, where, strings.Join(src.Overrides, ", "))
+	}
+	return fmt.Sprintf("%s: from %s", name, where)
+}
+
+// Keys returns the resolved keys sorted alphabetically.
+func (r *ChainResult) Keys() []string {
+	keys := make([]string, 0, len(r.Values))
+	for k := range r.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LoadChain reads the given .env files in order and applies the result to the
+// process environment. Later files override earlier ones; variables already
+// present in the process environment are never overwritten (production uses
+// real env vars). Missing files are skipped, and $VAR references in file names
+// are expanded - a name whose variable is unset (e.g., ".env.$APP_ENV" with no
+// APP_ENV) is skipped rather than loading ".env.".
+//
+// Example:
+//
+//	// .env < .env.local < .env.$APP_ENV < real environment
+//	result, err := env.LoadChain(env.DefaultChainFiles...)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(result.Explain("DATABASE_URL"))
+func LoadChain(files ...string) (*ChainResult, error) {
+	result, err := ResolveChain(files...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range result.Keys() {
+		if result.Sources[key].File == SourceEnvironment {
+			continue
+		}
+		if err := os.Setenv(key, result.Values[key]); err != nil {
+			return result, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		result.Applied = append(result.Applied, key)
+	}
+
+	loadedChain.Store(result)
+	return result, nil
+}
+
+// loadedChain is the result of the most recent LoadChain, for provenance after
+// the files have been applied to the process environment
+var loadedChain atomic.Pointer[ChainResult]
+
+// LoadedChain returns the result of the most recent LoadChain, or nil if none ran.
+// Once LoadChain has applied the files, ResolveChain reports every value as
+// coming from the environment; this keeps the original provenance.
+func LoadedChain() *ChainResult {
+	return loadedChain.Load()
+}
+
+// ResolveChain is LoadChain without touching the process environment.
+// Useful for showing provenance ("where did this value come from?").
+func ResolveChain(files ...string) (*ChainResult, error) {
+	result := &ChainResult{
+		Values:  make(map[string]string),
+		Sources: make(map[string]Provenance),
+	}
+
+	for _, name := range files {
+		path, ok := expandChainPath(name)
+		if !ok {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			result.Skipped = append(result.Skipped, path)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		result.Files = append(result.Files, path)
+
+		for _, entry := range parseEnvEntries(data) {
+			var overrides []string
+			if prev, ok := result.Sources[entry.key]; ok {
+				overrides = append(prev.Overrides, prev.File)
+			}
+			result.Values[entry.key] = entry.value
+			result.Sources[entry.key] = Provenance{File: path, Line: entry.line, Overrides: overrides}
+		}
+	}
+
+	// Real environment wins over every file
+	for key, prov := range result.Sources {
+		if value, ok := os.LookupEnv(key); ok {
+			result.Values[key] = value
+			result.Sources[key] = Provenance{
+				File:      SourceEnvironment,
+				Overrides: append(prov.Overrides, prov.File),
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// expandChainPath expands $VAR references, reporting false if any referenced variable is unset
+func expandChainPath(name string) (string, bool) {
+	ok := true
+	path := os.Expand(name, func(key string) string {
+		value := os.Getenv(key)
+		if value == "" {
+			ok = false
+		}
+		return value
+	})
+	return path, ok && path != ""
+}
+
+// envEntry is one KEY=value assignment with its line number
+type envEntry struct {
+	key   string
+	value string
+	line  int
+}
+
+// parseEnvEntries parses dotenv syntax: comments, optional "export " prefix,
+// and single- or double-quoted values (double quotes support \n escapes).
+// Lines may end in CRLF and be of any length; a leading byte order mark is
+// ignored.
+func parseEnvEntries(data []byte) []envEntry {
+	var entries []envEntry
+	for i, raw := range strings.Split(string(bytes.TrimPrefix(data, utf8BOM)), "\n") {
+		lineNum := i + 1
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if !validEnvKey(key) {
+			continue
+		}
+		entries = append(entries, envEntry{key: key, value: unquoteEnvValue(strings.TrimSpace(value)), line: lineNum})
+	}
+	return entries
+}
+
+// unquoteEnvValue strips matching quotes, or a trailing " # comment" from unquoted values
+func unquoteEnvValue(value string) string {
+	if len(value) >= 2 {
+		switch {
+		case value[0] == '"' && value[len(value)-1] == '"':
+			return strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+		case value[0] == '\'' && value[len(value)-1] == '\'':
+			return value[1 : len(value)-1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
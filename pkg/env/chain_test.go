@@ -0,0 +1,133 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeChainFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveChain_OverrideOrder(t *testing.T) {
+	dir := t.TempDir()
+	base := writeChainFile(t, dir, ".env", "TEST_CHAIN_A=base\nTEST_CHAIN_B=base\nTEST_CHAIN_C=base\n")
+	local := writeChainFile(t, dir, ".env.local", "# local overrides\nTEST_CHAIN_B=local\nexport TEST_CHAIN_C=\"local value\"\n")
+	t.Setenv("TEST_CHAIN_APP_ENV", "staging")
+	writeChainFile(t, dir, ".env.staging", "TEST_CHAIN_C='staging'\n")
+
+	result, err := ResolveChain(base, local, filepath.Join(dir, ".env.$TEST_CHAIN_APP_ENV"), filepath.Join(dir, ".env.missing"))
+	if err != nil {
+		t.Fatalf("ResolveChain failed: %v", err)
+	}
+
+	want := map[string]string{"TEST_CHAIN_A": "base", "TEST_CHAIN_B": "local", "TEST_CHAIN_C": "staging"}
+	for k, v := range want {
+		if result.Values[k] != v {
+			t.Errorf("%s = %q, want %q", k, result.Values[k], v)
+		}
+	}
+	if len(result.Files) != 3 || len(result.Skipped) != 1 {
+		t.Errorf("Files = %v, Skipped = %v", result.Files, result.Skipped)
+	}
+
+	src := result.Sources["TEST_CHAIN_C"]
+	if src.File != filepath.Join(dir, ".env.staging") || src.Line != 1 || len(src.Overrides) != 2 {
+		t.Errorf("TEST_CHAIN_C provenance = %+v", src)
+	}
+	if got := result.Sources["TEST_CHAIN_B"].Line; got != 2 {
+		t.Errorf("TEST_CHAIN_B line = %d, want 2", got)
+	}
+	if _, ok := os.LookupEnv("TEST_CHAIN_A"); ok {
+		t.Error("ResolveChain must not modify the process environment")
+	}
+}
+
+func TestResolveChain_UnsetVariableSkipped(t *testing.T) {
+	t.Setenv("TEST_CHAIN_UNSET_ENV", "")
+	result, err := ResolveChain(".env.$TEST_CHAIN_UNSET_ENV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Files) != 0 || len(result.Skipped) != 1 {
+		t.Errorf("Files = %v, Skipped = %v", result.Files, result.Skipped)
+	}
+}
+
+func TestLoadChain_EnvironmentWins(t *testing.T) {
+	dir := t.TempDir()
+	path := writeChainFile(t, dir, ".env", "TEST_CHAIN_SET=file\nTEST_CHAIN_NEW=file\n")
+	t.Setenv("TEST_CHAIN_SET", "real")
+	t.Setenv("TEST_CHAIN_NEW", "")
+	os.Unsetenv("TEST_CHAIN_NEW")
+
+	result, err := LoadChain(path)
+	if err != nil {
+		t.Fatalf("LoadChain failed: %v", err)
+	}
+
+	if got := os.Getenv("TEST_CHAIN_SET"); got != "real" {
+		t.Errorf("TEST_CHAIN_SET = %q, real environment should win", got)
+	}
+	if got := os.Getenv("TEST_CHAIN_NEW"); got != "file" {
+		t.Errorf("TEST_CHAIN_NEW = %q, want file", got)
+	}
+	if result.Sources["TEST_CHAIN_SET"].File != SourceEnvironment {
+		t.Errorf("TEST_CHAIN_SET provenance = %+v", result.Sources["TEST_CHAIN_SET"])
+	}
+	if len(result.Applied) != 1 || result.Applied[0] != "TEST_CHAIN_NEW" {
+		t.Errorf("Applied = %v", result.Applied)
+	}
+}
+
+// Test dotenv parsing past lines longer than bufio.Scanner's 64KB limit,
+// with CRLF endings and a byte order mark
+func TestParseEnvEntries_LongLinesCRLFAndBOM(t *testing.T) {
+	long := strings.Repeat("x", 1<<20)
+	data := "\ufeffFIRST=1\r\nLONG=" + long + "\r\nAFTER=\"quoted\"\r\nBAD KEY=2\r\n"
+
+	entries := parseEnvEntries([]byte(data))
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if entries[0].key != "FIRST" || entries[0].value != "1" {
+		t.Errorf("first entry = %q=%q", entries[0].key, entries[0].value)
+	}
+	if entries[1].value != long {
+		t.Errorf("long value has %d bytes, want %d", len(entries[1].value), len(long))
+	}
+	if entries[2].key != "AFTER" || entries[2].value != "quoted" || entries[2].line != 3 {
+		t.Errorf("entry after long line = %+v", entries[2])
+	}
+}
+
+// FuzzParseEnvEntries checks the chain's dotenv parser never panics and only
+// yields usable keys with increasing line numbers
+func FuzzParseEnvEntries(f *testing.F) {
+	for _, seed := range []string{
+		"A=1\nexport B=\"two\\nlines\"\nC='3' # comment\n",
+		"\ufeffA=1\r\n#x\r\n=\r\n",
+		"A=\"\nB='\n\"\"=\n",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		line := 0
+		for _, e := range parseEnvEntries(data) {
+			if !validEnvKey(e.key) {
+				t.Fatalf("invalid key %q", e.key)
+			}
+			if e.line <= line {
+				t.Fatalf("line %d after line %d", e.line, line)
+			}
+			line = e.line
+		}
+	})
+}
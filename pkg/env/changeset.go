@@ -0,0 +1,468 @@
+package env
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+)
+
+// ================================================================
+// Change Sets
+// ================================================================
+// A change set is a reviewable JSON bundle of configuration changes: which
+// variables change, in which environments, by whom and why. The author signs
+// it (ed25519), reviewers add approval signatures, and ApplyChangeSet writes
+// it to the environment files only when the signatures verify and enough
+// approvers have signed. Secret values are age-encrypted in the bundle, so
+// it can be attached to a pull request or ticket; the diff reviewers see
+// names the secret without revealing it.
+//
+//	cs, err := env.CreateChangeSet(changes, env.ChangeSetOptions{Registry: registry, Author: "alice", Key: aliceKey, Recipient: recipient})
+//	err = cs.Approve("bob", bobKey)
+//	result, err := env.ApplyChangeSet(cs, env.ApplyChangeSetOptions{Registry: registry, Trusted: keys})
+
+// Change signature roles
+const (
+	ChangeRoleAuthor   = "author"
+	ChangeRoleApprover = "approver"
+)
+
+// Change is one variable change in one environment
+type Change struct {
+	Environment string `json:"environment"`         // Environment name, e.g. "production"
+	Name        string `json:"name"`                // Variable name
+	Value       string `json:"value,omitempty"`     // New value; for secrets the base64 age ciphertext
+	Encrypted   bool   `json:"encrypted,omitempty"` // Value is encrypted (secret variables)
+	Unset       bool   `json:"unset,omitempty"`     // Clear the variable instead of setting it
+}
+
+// ChangeSignature is the author's or an approver's signature of a change set
+type ChangeSignature struct {
+	Signer    string    `json:"signer"`
+	Role      string    `json:"role"`       // ChangeRoleAuthor or ChangeRoleApprover
+	PublicKey string    `json:"public_key"` // base64 ed25519 public key
+	Signature string    `json:"signature"`  // base64 signature of ChangeSet.Digest
+	Signed    time.Time `json:"signed"`
+}
+
+// ChangeSet is a signed bundle of changes
+type ChangeSet struct {
+	ID         string            `json:"id"` // First 12 hex digits of Digest
+	Author     string            `json:"author"`
+	Reason     string            `json:"reason,omitempty"`
+	Created    time.Time         `json:"created"`
+	Changes    []Change          `json:"changes"`
+	Signatures []ChangeSignature `json:"signatures"`
+}
+
+// ChangeSetOptions configures CreateChangeSet
+type ChangeSetOptions struct {
+	Registry  *Registry          // Registry the changed variables must be in (required)
+	Author    string             // Who proposes the change (required)
+	Reason    string             // Why, shown to reviewers (optional)
+	Key       ed25519.PrivateKey // Author's signing key (required)
+	Recipient age.Recipient      // Encrypts secret values (required when a secret changes)
+}
+
+// CreateChangeSet validates changes against the registry, encrypts secret
+// values to opts.Recipient and returns the change set signed by the author.
+// Values in changes are plaintext; Encrypted is set by CreateChangeSet.
+func CreateChangeSet(changes []Change, opts ChangeSetOptions) (*ChangeSet, error) {
+	if opts.Registry == nil {
+		return nil, fmt.Errorf("registry is required")
+	}
+	if opts.Author == "" || len(opts.Key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("author and signing key are required")
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("change set is empty")
+	}
+
+	cs := &ChangeSet{
+		Author:  opts.Author,
+		Reason:  opts.Reason,
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+	for _, c := range changes {
+		v := opts.Registry.ByName(c.Name)
+		if v == nil {
+			return nil, fmt.Errorf("%s is not in the registry", c.Name)
+		}
+		if c.Environment == "" {
+			return nil, fmt.Errorf("%s: environment is required", c.Name)
+		}
+		c.Name = v.Name
+		c.Encrypted = false
+		if c.Unset {
+			c.Value = ""
+		} else if err := v.ValidateValue(c.Value); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Name, err)
+		}
+		if v.Secret && !c.Unset {
+			if opts.Recipient == nil {
+				return nil, fmt.Errorf("%s is a secret; a recipient is required to encrypt it", c.Name)
+			}
+			sealed, err := encryptValue(c.Value, opts.Recipient)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt %s: %w", c.Name, err)
+			}
+			c.Value, c.Encrypted = sealed, true
+		}
+		cs.Changes = append(cs.Changes, c)
+	}
+	sort.SliceStable(cs.Changes, func(i, j int) bool {
+		if cs.Changes[i].Environment != cs.Changes[j].Environment {
+			return cs.Changes[i].Environment < cs.Changes[j].Environment
+		}
+		return cs.Changes[i].Name < cs.Changes[j].Name
+	})
+
+	digest, err := cs.Digest()
+	if err != nil {
+		return nil, err
+	}
+	cs.ID = hex.EncodeToString(digest[:6])
+	if err := cs.sign(opts.Author, ChangeRoleAuthor, opts.Key); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// Digest is the SHA-256 of the signed content (author, reason, creation
+// time and changes); signatures and the ID are not part of it
+func (cs *ChangeSet) Digest() ([]byte, error) {
+	content, err := json.Marshal(struct {
+		Author  string    `json:"author"`
+		Reason  string    `json:"reason"`
+		Created time.Time `json:"created"`
+		Changes []Change  `json:"changes"`
+	}{cs.Author, cs.Reason, cs.Created, cs.Changes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode change set: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return sum[:], nil
+}
+
+func (cs *ChangeSet) sign(signer, role string, key ed25519.PrivateKey) error {
+	digest, err := cs.Digest()
+	if err != nil {
+		return err
+	}
+	cs.Signatures = append(cs.Signatures, ChangeSignature{
+		Signer:    signer,
+		Role:      role,
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, digest)),
+		Signed:    time.Now().UTC().Truncate(time.Second),
+	})
+	return nil
+}
+
+// Approve adds approver's signature. Authors cannot approve their own
+// change set, and each approver signs once.
+func (cs *ChangeSet) Approve(approver string, key ed25519.PrivateKey) error {
+	if approver == "" || len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("approver and signing key are required")
+	}
+	if approver == cs.Author {
+		return fmt.Errorf("%s cannot approve their own change set", approver)
+	}
+	for _, s := range cs.Signatures {
+		if s.Signer == approver {
+			return fmt.Errorf("%s has already signed change set %s", approver, cs.ID)
+		}
+	}
+	if err := cs.Verify(nil); err != nil {
+		return err
+	}
+	return cs.sign(approver, ChangeRoleApprover, key)
+}
+
+// Verify checks every signature against the content. With trusted keys, each
+// signer's key must also be one of them (so a signer cannot be impersonated
+// by someone using their name with another key).
+func (cs *ChangeSet) Verify(trusted map[string]ed25519.PublicKey) error {
+	digest, err := cs.Digest()
+	if err != nil {
+		return err
+	}
+	if id := hex.EncodeToString(digest[:6]); cs.ID != id {
+		return Classify(ErrChangeSetRejected, fmt.Errorf("change set %s was modified after it was created", cs.ID))
+	}
+	if len(cs.Signatures) == 0 || cs.Signatures[0].Role != ChangeRoleAuthor || cs.Signatures[0].Signer != cs.Author {
+		return Classify(ErrChangeSetRejected, fmt.Errorf("change set %s is not signed by its author", cs.ID))
+	}
+	for _, s := range cs.Signatures {
+		pub, err := base64.StdEncoding.DecodeString(s.PublicKey)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			return Classify(ErrChangeSetRejected, fmt.Errorf("invalid public key of %s", s.Signer))
+		}
+		sig, err := base64.StdEncoding.DecodeString(s.Signature)
+		if err != nil || !ed25519.Verify(pub, digest, sig) {
+			return Classify(ErrChangeSetRejected, fmt.Errorf("invalid signature of %s on change set %s", s.Signer, cs.ID))
+		}
+		if trusted != nil {
+			if key, ok := trusted[s.Signer]; !ok || !key.Equal(ed25519.PublicKey(pub)) {
+				return Classify(ErrChangeSetRejected, fmt.Errorf("%s is not a trusted signer", s.Signer))
+			}
+		}
+	}
+	return nil
+}
+
+// Approvers returns the names of the approvers, in signing order (each
+// once; the author never counts)
+func (cs *ChangeSet) Approvers() []string {
+	var names []string
+	seen := map[string]bool{cs.Author: true}
+	for _, s := range cs.Signatures {
+		if s.Role == ChangeRoleApprover && !seen[s.Signer] {
+			seen[s.Signer] = true
+			names = append(names, s.Signer)
+		}
+	}
+	return names
+}
+
+// Summary renders the change set for review (secret values are not shown)
+func (cs *ChangeSet) Summary() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Change set %s by %s (%s)\n", cs.ID, cs.Author, cs.Created.Format(time.RFC3339))
+	if cs.Reason != "" {
+		fmt.Fprintf(&sb, "Reason: %s\n", cs.Reason)
+	}
+	for _, c := range cs.Changes {
+		switch {
+		case c.Unset:
+			fmt.Fprintf(&sb, "  %-12s %s (unset)\n", c.Environment, c.Name)
+		case c.Encrypted:
+			fmt.Fprintf(&sb, "  %-12s %s = (secret, encrypted)\n", c.Environment, c.Name)
+		default:
+			fmt.Fprintf(&sb, "  %-12s %s = %q\n", c.Environment, c.Name, c.Value)
+		}
+	}
+	if approvers := cs.Approvers(); len(approvers) > 0 {
+		fmt.Fprintf(&sb, "Approved by: %s\n", strings.Join(approvers, ", "))
+	} else {
+		sb.WriteString("Not approved\n")
+	}
+	return sb.String()
+}
+
+// WriteChangeSet writes cs as indented JSON to path
+func WriteChangeSet(cs *ChangeSet, path string) error {
+	data, err := json.MarshalIndent(cs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode change set: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadChangeSet reads a change set written by WriteChangeSet
+func ReadChangeSet(path string) (*ChangeSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var cs ChangeSet
+	if err := json.Unmarshal(data, &cs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &cs, nil
+}
+
+// ApplyChangeSetOptions configures ApplyChangeSet
+type ApplyChangeSetOptions struct {
+	Registry          *Registry                    // Registry the changes are validated against again (required)
+	Environments      []*Environment               // Files by environment name (default: AllEnvironmentFiles)
+	KeyPath           string                       // Age key decrypting secret values (default: DefaultAgeKeyPath)
+	RequiredApprovals int                          // Approver signatures needed (default: 1)
+	Trusted           map[string]ed25519.PublicKey // Signer name -> key (required unless AllowUntrusted)
+	AllowUntrusted    bool                         // Accept any valid signature without Trusted (approvals then prove nothing)
+	AppName           string                       // Application name for newly generated files (default: "Application")
+}
+
+// ChangeSetResult reports what ApplyChangeSet wrote
+type ChangeSetResult struct {
+	Files   []string // Environment files written
+	Applied int      // Changes applied
+}
+
+// ApplyChangeSet verifies cs, checks it has enough approvals and writes the
+// changes to the plaintext environment files (run finalize afterwards to
+// re-encrypt them). Comments and layout of existing files are kept. Nothing
+// is written unless every change can be applied: all new file contents are
+// computed before the first write (should a write itself fail, the result
+// lists the files already written).
+func ApplyChangeSet(cs *ChangeSet, opts ApplyChangeSetOptions) (*ChangeSetResult, error) {
+	if opts.Registry == nil {
+		return nil, fmt.Errorf("registry is required")
+	}
+	if opts.Environments == nil {
+		opts.Environments = AllEnvironmentFiles()
+	}
+	if opts.KeyPath == "" {
+		opts.KeyPath = DefaultAgeKeyPath
+	}
+	if opts.RequiredApprovals == 0 {
+		opts.RequiredApprovals = 1
+	}
+	if opts.AppName == "" {
+		opts.AppName = "Application"
+	}
+
+	if opts.Trusted == nil && !opts.AllowUntrusted {
+		return nil, fmt.Errorf("trusted signer keys are required (set AllowUntrusted to accept any valid signature)")
+	}
+	if err := cs.Verify(opts.Trusted); err != nil {
+		return nil, err
+	}
+	if n := len(cs.Approvers()); n < opts.RequiredApprovals {
+		return nil, Classify(ErrChangeSetRejected, fmt.Errorf("change set %s has %d of %d required approvals", cs.ID, n, opts.RequiredApprovals))
+	}
+
+	byName := make(map[string]*Environment, len(opts.Environments))
+	for _, e := range opts.Environments {
+		byName[e.Name] = e
+	}
+
+	var identities []age.Identity
+	updates := make(map[*Environment]map[string]string)
+	for _, c := range cs.Changes {
+		e, ok := byName[c.Environment]
+		if !ok {
+			return nil, fmt.Errorf("unknown environment %q in change set %s", c.Environment, cs.ID)
+		}
+		v := opts.Registry.ByName(c.Name)
+		if v == nil {
+			return nil, fmt.Errorf("%s is no longer in the registry", c.Name)
+		}
+
+		value := c.Value
+		if c.Encrypted {
+			if identities == nil {
+				var err error
+				if identities, err = loadIdentityFile(opts.KeyPath); err != nil {
+					return nil, err
+				}
+			}
+			plain, err := decryptValue(value, identities)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt %s: %w", c.Name, err)
+			}
+			value = plain
+		}
+		if !c.Unset {
+			if err := v.ValidateValue(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", c.Name, err)
+			}
+		}
+		if updates[e] == nil {
+			updates[e] = make(map[string]string)
+		}
+		updates[e][v.Name] = value
+	}
+
+	// Every file's new content first, so a failure writes nothing
+	var changed []*Environment
+	contents := make(map[*Environment]string, len(updates))
+	for _, e := range opts.Environments {
+		values, ok := updates[e]
+		if !ok {
+			continue
+		}
+		content, err := updatedEnvFile(e, opts.Registry, values, opts.AppName)
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, e)
+		contents[e] = content
+	}
+
+	result := &ChangeSetResult{}
+	for _, e := range changed {
+		path := e.FullPath()
+		if err := os.WriteFile(path, []byte(contents[e]), 0600); err != nil {
+			return result, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		result.Files = append(result.Files, path)
+		result.Applied += len(updates[e])
+	}
+	return result, nil
+}
+
+// updatedEnvFile returns e's file with values set, keeping its comments and
+// layout (a missing file is generated from the registry first)
+func updatedEnvFile(e *Environment, registry *Registry, values map[string]string, appName string) (string, error) {
+	template := e.Generate(registry, appName)
+	if data, err := os.ReadFile(e.FullPath()); err == nil {
+		template = string(data)
+	}
+	return setValues(template, values)
+}
+
+// setValues merges values into content (an env file), appending the names it
+// does not have yet in sorted order
+func setValues(content string, values map[string]string) (string, error) {
+	merged, err := MergeIntoTemplate(content, values)
+	if err != nil {
+		return "", err
+	}
+	content = strings.TrimRight(merged, "\n") + "\n"
+	existing := ParseSecretsFile([]byte(content))
+	var missing []string
+	for name, value := range values {
+		if _, ok := existing[name]; !ok {
+			missing = append(missing, name+"="+value)
+		}
+	}
+	sort.Strings(missing)
+	for _, line := range missing {
+		content += line + "\n"
+	}
+	return content, nil
+}
+
+// encryptValue encrypts value to recipient as base64 age ciphertext
+func encryptValue(value string, recipient age.Recipient) (string, error) {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, value); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decryptValue reverses encryptValue
+func decryptValue(sealed string, identities []age.Identity) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	plain, err := decryptWithIdentities(data, identities)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,178 @@
+package env
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestChangeSet_CreateApproveApply(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.txt")
+	keygen, err := GenerateAgeKey(KeygenOptions{KeyPath: keyPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := age.ParseX25519Recipient(keygen.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alicePub, aliceKey, _ := ed25519.GenerateKey(nil)
+	bobPub, bobKey, _ := ed25519.GenerateKey(nil)
+
+	registry := NewRegistry([]EnvVar{
+		{Name: "LOG_LEVEL", Default: "info"},
+		{Name: "SERVER_PORT", Default: "8080"},
+		{Name: "API_KEY", Secret: true},
+	})
+	production := Production.WithBaseDir(dir)
+	os.WriteFile(production.FullPath(), []byte("# Production\nLOG_LEVEL=info\nSERVER_PORT=8080\n"), 0600)
+
+	cs, err := CreateChangeSet([]Change{
+		{Environment: "production", Name: "LOG_LEVEL", Value: "warn"},
+		{Environment: "production", Name: "API_KEY", Value: "sk_live_123"},
+	}, ChangeSetOptions{Registry: registry, Author: "alice", Reason: "Quieter logs, rotated key", Key: aliceKey, Recipient: recipient})
+	if err != nil {
+		t.Fatalf("CreateChangeSet failed: %v", err)
+	}
+
+	summary := cs.Summary()
+	if strings.Contains(summary, "sk_live_123") || !strings.Contains(summary, "API_KEY = (secret, encrypted)") || !strings.Contains(summary, "Not approved") {
+		t.Errorf("unexpected summary:\n%s", summary)
+	}
+
+	opts := ApplyChangeSetOptions{
+		Registry:     registry,
+		Environments: []*Environment{production},
+		KeyPath:      keyPath,
+		Trusted:      map[string]ed25519.PublicKey{"alice": alicePub, "bob": bobPub},
+	}
+	if _, err := ApplyChangeSet(cs, opts); err == nil || !strings.Contains(err.Error(), "0 of 1 required approvals") {
+		t.Fatalf("unapproved change set applied: %v", err)
+	}
+	if err := cs.Approve("alice", aliceKey); err == nil {
+		t.Error("authors must not approve their own change set")
+	}
+	if err := cs.Approve("bob", bobKey); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+
+	// Round trip through the file reviewers see
+	path := filepath.Join(dir, "change.json")
+	if err := WriteChangeSet(cs, path); err != nil {
+		t.Fatal(err)
+	}
+	if cs, err = ReadChangeSet(path); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ApplyChangeSet(cs, opts)
+	if err != nil {
+		t.Fatalf("ApplyChangeSet failed: %v", err)
+	}
+	if result.Applied != 2 || len(result.Files) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	data, _ := os.ReadFile(production.FullPath())
+	values := ParseSecretsFile(data)
+	if values["LOG_LEVEL"] != "warn" || values["API_KEY"] != "sk_live_123" || values["SERVER_PORT"] != "8080" {
+		t.Errorf("unexpected file values: %v", values)
+	}
+	if !strings.HasPrefix(string(data), "# Production\n") {
+		t.Errorf("comments not kept:\n%s", data)
+	}
+
+	// Signers must be trusted explicitly
+	opts.Trusted = nil
+	if _, err := ApplyChangeSet(cs, opts); err == nil || !strings.Contains(err.Error(), "AllowUntrusted") {
+		t.Errorf("applied without trusted keys: %v", err)
+	}
+	opts.AllowUntrusted = true
+	if _, err := ApplyChangeSet(cs, opts); err != nil {
+		t.Errorf("AllowUntrusted: %v", err)
+	}
+}
+
+func TestChangeSet_ApplyWritesNothingOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	alicePub, aliceKey, _ := ed25519.GenerateKey(nil)
+	bobPub, bobKey, _ := ed25519.GenerateKey(nil)
+	registry := NewRegistry([]EnvVar{{Name: "LOG_LEVEL"}, {Name: "BANNER"}})
+	production, local := Production.WithBaseDir(dir), Local.WithBaseDir(dir)
+	original := "LOG_LEVEL=info\n"
+	os.WriteFile(production.FullPath(), []byte(original), 0600)
+
+	// production is updated first; local's value can't be written
+	cs, err := CreateChangeSet([]Change{
+		{Environment: "production", Name: "LOG_LEVEL", Value: "warn"},
+		{Environment: "local", Name: "BANNER", Value: "two\nlines"},
+	}, ChangeSetOptions{Registry: registry, Author: "alice", Key: aliceKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Approve("bob", bobKey); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ApplyChangeSet(cs, ApplyChangeSetOptions{
+		Registry:     registry,
+		Environments: []*Environment{production, local},
+		Trusted:      map[string]ed25519.PublicKey{"alice": alicePub, "bob": bobPub},
+	})
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("err = %v, want ErrInvalidValue", err)
+	}
+	if data, _ := os.ReadFile(production.FullPath()); string(data) != original {
+		t.Errorf("production written despite the failure:\n%s", data)
+	}
+	if local.Exists() {
+		t.Error("local file created despite the failure")
+	}
+}
+
+func TestChangeSet_Tampering(t *testing.T) {
+	_, aliceKey, _ := ed25519.GenerateKey(nil)
+	_, bobKey, _ := ed25519.GenerateKey(nil)
+	_, malloryKey, _ := ed25519.GenerateKey(nil)
+	registry := NewRegistry([]EnvVar{{Name: "LOG_LEVEL"}, {Name: "API_KEY", Secret: true}})
+
+	newSet := func() *ChangeSet {
+		cs, err := CreateChangeSet([]Change{{Environment: "production", Name: "LOG_LEVEL", Value: "warn"}},
+			ChangeSetOptions{Registry: registry, Author: "alice", Key: aliceKey})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cs
+	}
+
+	cs := newSet()
+	cs.Approve("bob", bobKey)
+	cs.Changes[0].Value = "debug"
+	if err := cs.Verify(nil); err == nil {
+		t.Error("modified change set should not verify")
+	}
+
+	// Signed by an unknown key under a trusted name
+	cs = newSet()
+	cs.Approve("bob", malloryKey)
+	bobPub := bobKey.Public().(ed25519.PublicKey)
+	alicePub := aliceKey.Public().(ed25519.PublicKey)
+	if err := cs.Verify(map[string]ed25519.PublicKey{"alice": alicePub, "bob": bobPub}); err == nil {
+		t.Error("impersonated approver should not verify")
+	}
+
+	for _, changes := range [][]Change{
+		{{Environment: "production", Name: "UNKNOWN", Value: "x"}},
+		{{Name: "LOG_LEVEL", Value: "x"}},
+		{{Environment: "production", Name: "API_KEY", Value: "x"}}, // Secret without a recipient
+	} {
+		if _, err := CreateChangeSet(changes, ChangeSetOptions{Registry: registry, Author: "alice", Key: aliceKey}); err == nil {
+			t.Errorf("CreateChangeSet(%+v) should fail", changes)
+		}
+	}
+}
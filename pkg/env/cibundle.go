@@ -0,0 +1,300 @@
+package env
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+)
+
+// ================================================================
+// CI Bundle
+// ================================================================
+// A CI bundle is one tar.gz holding the encrypted env files, a manifest with
+// their checksums, and restore.sh - a bootstrap script that decrypts them with
+// the age CLI. CI stores the Age identity as an AGE_KEY secret and restores with
+// either the script or RestoreCIBundle (env ci-restore):
+//
+//	- run: ./wellknown env ci-restore --github-env
+//	  env:
+//	    AGE_KEY: ${{ secrets.AGE_KEY }}
+
+// CI bundle defaults
+const (
+	DefaultCIBundlePath   = "env-ci-bundle.tar.gz"
+	DefaultCIKeyEnv       = "AGE_KEY" // Secret holding the Age identity (key file contents)
+	CIBundleManifest      = "manifest.json"
+	CIBundleRestoreScript = "restore.sh"
+)
+
+// ciRestoreScript decrypts the bundle's .age files without the wellknown binary
+const ciRestoreScript = `#!/bin/sh
+# Decrypts the env files in this bundle with the AGE_KEY secret.
+# Requires the age CLI (https://age-encryption.org). Set DEST to choose the output directory.
+set -eu
+: "${AGE_KEY:?AGE_KEY secret is not set}"
+dir="$(cd "$(dirname "$0")" && pwd)"
+dest="${DEST:-.}"
+key="$(mktemp)"
+trap 'rm -f "$key"' EXIT
+printf '%s\n' "$AGE_KEY" > "$key"
+for f in "$dir"/*.age; do
+  out="$dest/$(basename "$f" .age)"
+  age -d -i "$key" -o "$out" "$f"
+  chmod 600 "$out"
+  echo "decrypted $(basename "$out")"
+done
+`
+
+// CIBundleFile is one encrypted file in a bundle manifest
+type CIBundleFile struct {
+	Name   string `json:"name"`   // Encrypted file name (e.g., ".env.production.age")
+	SHA256 string `json:"sha256"` // Checksum of the encrypted bytes
+}
+
+// CIBundleManifestData is the bundle's manifest.json
+type CIBundleManifestData struct {
+	Created time.Time      `json:"created"`
+	Files   []CIBundleFile `json:"files"`
+}
+
+// CIBundleOptions configures CreateCIBundle
+type CIBundleOptions struct {
+	Output       string         // Bundle path (default: DefaultCIBundlePath)
+	Environments []*Environment // Environments whose .age files are bundled (default: AllEnvironmentFiles)
+}
+
+// CreateCIBundle packages the existing encrypted env files, a manifest and the
+// restore.sh bootstrap script into a tar.gz. Plaintext files are never included.
+//
+// Example:
+//
+//	manifest, err := env.CreateCIBundle(env.CIBundleOptions{})
+//	fmt.Printf("Bundled %d files\n", len(manifest.Files))
+func CreateCIBundle(opts CIBundleOptions) (*CIBundleManifestData, error) {
+	if opts.Output == "" {
+		opts.Output = DefaultCIBundlePath
+	}
+	if opts.Environments == nil {
+		opts.Environments = AllEnvironmentFiles()
+	}
+
+	manifest := &CIBundleManifestData{Created: time.Now().UTC()}
+	contents := make(map[string][]byte)
+	for _, e := range opts.Environments {
+		data, err := os.ReadFile(e.FullEncryptedPath())
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", e.EncryptedFileName(), err)
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, CIBundleFile{Name: e.EncryptedFileName(), SHA256: hex.EncodeToString(sum[:])})
+		contents[e.EncryptedFileName()] = data
+	}
+	if len(manifest.Files) == 0 {
+		return nil, fmt.Errorf("no encrypted env files found; encrypt them first")
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte, mode int64) error {
+		hdr := &tar.Header{Name: name, Mode: mode, Size: int64(len(data)), ModTime: manifest.Created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := add(CIBundleManifest, manifestJSON, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := add(CIBundleRestoreScript, []byte(ciRestoreScript), 0755); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	for _, f := range manifest.Files {
+		if err := add(f.Name, contents[f.Name], 0600); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	if err := os.WriteFile(opts.Output, buf.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", opts.Output, err)
+	}
+	return manifest, nil
+}
+
+// CIRestoreOptions configures RestoreCIBundle
+type CIRestoreOptions struct {
+	Bundle    string // Bundle path (default: DefaultCIBundlePath)
+	Dir       string // Where decrypted files are written (default: ".")
+	Key       string // Age identity contents (default: $AGE_KEY)
+	GitHubEnv bool   // Also export the values to $GITHUB_ENV, masking them in the log
+	Stdout    io.Writer
+}
+
+// RestoreCIBundle verifies and decrypts a bundle created by CreateCIBundle.
+// With GitHubEnv, every decrypted value is masked (::add-mask::) and appended
+// to the file named by $GITHUB_ENV so later workflow steps see it.
+func RestoreCIBundle(opts CIRestoreOptions) (*EncryptionResult, error) {
+	if opts.Bundle == "" {
+		opts.Bundle = DefaultCIBundlePath
+	}
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.Key == "" {
+		opts.Key = os.Getenv(DefaultCIKeyEnv)
+	}
+	if opts.Key == "" {
+		return nil, fmt.Errorf("%w: set the %s secret", ErrNoIdentity, DefaultCIKeyEnv)
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+
+	identities, err := age.ParseIdentities(strings.NewReader(opts.Key))
+	if err != nil {
+		return nil, Classify(ErrNoIdentity, fmt.Errorf("failed to parse %s: %w", DefaultCIKeyEnv, err))
+	}
+
+	files, err := readCIBundle(opts.Bundle)
+	if err != nil {
+		return nil, err
+	}
+	var manifest CIBundleManifestData
+	if err := json.Unmarshal(files[CIBundleManifest], &manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+
+	var githubEnv io.WriteCloser
+	if opts.GitHubEnv {
+		path := os.Getenv("GITHUB_ENV")
+		if path == "" {
+			return nil, fmt.Errorf("GITHUB_ENV is not set (not running in GitHub Actions?)")
+		}
+		githubEnv, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open GITHUB_ENV: %w", err)
+		}
+		defer githubEnv.Close()
+	}
+
+	result := &EncryptionResult{}
+	for _, f := range manifest.Files {
+		if f.Name != filepath.Base(f.Name) || !strings.HasSuffix(f.Name, ".age") {
+			result.Errors = append(result.Errors, fmt.Errorf("refusing unsafe bundle name %q", f.Name))
+			continue
+		}
+		encrypted, ok := files[f.Name]
+		if !ok {
+			result.Errors = append(result.Errors, fmt.Errorf("%s listed in manifest but missing", f.Name))
+			continue
+		}
+		sum := sha256.Sum256(encrypted)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			result.Errors = append(result.Errors, fmt.Errorf("checksum mismatch for %s", f.Name))
+			continue
+		}
+
+		plaintext, err := decryptWithIdentities(encrypted, identities)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to decrypt %s: %w", f.Name, err))
+			continue
+		}
+
+		plainName := strings.TrimSuffix(f.Name, ".age")
+		if err := os.WriteFile(filepath.Join(opts.Dir, plainName), plaintext, 0600); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to write %s: %w", plainName, err))
+			continue
+		}
+
+		if githubEnv != nil {
+			values := ParseSecretsFile(plaintext)
+			keys := make([]string, 0, len(values))
+			for k := range values {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				v := values[k]
+				if v == "" || strings.ContainsAny(v, "\r\n") {
+					continue
+				}
+				fmt.Fprintf(opts.Stdout, "::add-mask::%s\n", v)
+				fmt.Fprintf(githubEnv, "%s=%s\n", k, v)
+			}
+		}
+
+		result.ProcessedFiles = append(result.ProcessedFiles, plainName)
+	}
+
+	if len(result.ProcessedFiles) == 0 && len(result.Errors) > 0 {
+		return result, fmt.Errorf("failed to restore any files: %w", result.Errors[0])
+	}
+	return result, nil
+}
+
+// readCIBundle reads every regular file of a bundle into memory, keyed by name
+func readCIBundle(bundlePath string) (map[string][]byte, error) {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle %s: %w", bundlePath, err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle %s: %w", bundlePath, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, 10<<20))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from bundle: %w", hdr.Name, err)
+		}
+		files[path.Clean(hdr.Name)] = data
+	}
+	if _, ok := files[CIBundleManifest]; !ok {
+		return nil, fmt.Errorf("invalid bundle %s: no %s", bundlePath, CIBundleManifest)
+	}
+	return files, nil
+}
@@ -0,0 +1,81 @@
+package env
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCIBundleRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	keyPath, envFile := setupEncryptedBundle(t, srcDir)
+
+	// Plaintext must never be bundled
+	bundlePath := filepath.Join(srcDir, DefaultCIBundlePath)
+	manifest, err := CreateCIBundle(CIBundleOptions{
+		Output:       bundlePath,
+		Environments: []*Environment{envFile, Local.WithBaseDir(srcDir)},
+	})
+	if err != nil {
+		t.Fatalf("CreateCIBundle failed: %v", err)
+	}
+	if len(manifest.Files) != 1 || manifest.Files[0].Name != ".env.production.age" {
+		t.Fatalf("manifest files = %+v", manifest.Files)
+	}
+	files, err := readCIBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := files[CIBundleRestoreScript]; !ok {
+		t.Error("bundle is missing the restore script")
+	}
+	for name, data := range files {
+		if bytes.Contains(data, []byte("secret123")) {
+			t.Errorf("%s contains plaintext", name)
+		}
+	}
+
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dstDir := t.TempDir()
+	githubEnv := filepath.Join(dstDir, "github_env")
+	t.Setenv("GITHUB_ENV", githubEnv)
+	t.Setenv(DefaultCIKeyEnv, string(key))
+
+	var stdout bytes.Buffer
+	result, err := RestoreCIBundle(CIRestoreOptions{
+		Bundle:    bundlePath,
+		Dir:       dstDir,
+		GitHubEnv: true,
+		Stdout:    &stdout,
+	})
+	if err != nil {
+		t.Fatalf("RestoreCIBundle failed: %v", err)
+	}
+	if len(result.ProcessedFiles) != 1 {
+		t.Fatalf("ProcessedFiles = %v", result.ProcessedFiles)
+	}
+
+	got, _ := os.ReadFile(filepath.Join(dstDir, ".env.production"))
+	if string(got) != "API_KEY=secret123\n" {
+		t.Errorf("restored content = %q", got)
+	}
+	exported, _ := os.ReadFile(githubEnv)
+	if string(exported) != "API_KEY=secret123\n" {
+		t.Errorf("GITHUB_ENV = %q", exported)
+	}
+	if !strings.Contains(stdout.String(), "::add-mask::secret123") {
+		t.Errorf("expected value to be masked, got %q", stdout.String())
+	}
+}
+
+func TestRestoreCIBundleRequiresKey(t *testing.T) {
+	t.Setenv(DefaultCIKeyEnv, "")
+	if _, err := RestoreCIBundle(CIRestoreOptions{Bundle: "missing.tar.gz"}); err == nil || !strings.Contains(err.Error(), DefaultCIKeyEnv) {
+		t.Errorf("expected missing %s error, got %v", DefaultCIKeyEnv, err)
+	}
+}
@@ -0,0 +1,157 @@
+package deploy
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// ================================================================
+// Multi-App (Monorepo) Support
+// ================================================================
+// A monorepo can hold several Fly.io services, each with its own config file:
+//
+//	fly.toml        -> target "default"
+//	fly.api.toml    -> target "api"
+//	fly.worker.toml -> target "worker"
+//
+// All targets share one registry; each target selects the variables it needs
+// by group and/or name, so secrets import and deploy run per app.
+
+// DefaultFlyTarget is the target name for a plain fly.toml.
+const DefaultFlyTarget = "default"
+
+// FlyApp is one deployable Fly.io service.
+type FlyApp struct {
+	Target     string   // Target name (e.g., "api" for fly.api.toml)
+	ConfigPath string   // Path to the fly config file
+	AppName    string   // Fly app name (from the config's app = '...')
+	Region     string   // Primary region (from the config)
+	Groups     []string // Registry groups this app uses (empty with Vars empty = all)
+	Vars       []string // Individual registry variables this app uses
+}
+
+// DiscoverFlyApps finds fly.toml and fly.*.toml files in dir, sorted by target name.
+func DiscoverFlyApps(dir string) ([]FlyApp, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "fly*.toml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", dir, err)
+	}
+
+	var apps []FlyApp
+	for _, path := range matches {
+		target, ok := flyTargetName(filepath.Base(path))
+		if !ok {
+			continue
+		}
+		appName, region, err := ReadFlyTomlConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, FlyApp{
+			Target:     target,
+			ConfigPath: path,
+			AppName:    appName,
+			Region:     region,
+		})
+	}
+
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Target < apps[j].Target })
+	return apps, nil
+}
+
+// flyTargetName maps "fly.toml" -> "default" and "fly.api.toml" -> "api"
+func flyTargetName(base string) (string, bool) {
+	if base == "fly.toml" {
+		return DefaultFlyTarget, true
+	}
+	if !strings.HasPrefix(base, "fly.") || !strings.HasSuffix(base, ".toml") {
+		return "", false
+	}
+	target := strings.TrimSuffix(strings.TrimPrefix(base, "fly."), ".toml")
+	if target == "" || strings.Contains(target, ".") {
+		return "", false
+	}
+	return target, true
+}
+
+// FindFlyApp returns the app for target from DiscoverFlyApps(dir).
+func FindFlyApp(dir, target string) (*FlyApp, error) {
+	apps, err := DiscoverFlyApps(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for i := range apps {
+		if apps[i].Target == target {
+			return &apps[i], nil
+		}
+		names = append(names, apps[i].Target)
+	}
+	return nil, fmt.Errorf("no fly config for target %q (found: %s)", target, strings.Join(names, ", "))
+}
+
+// ApplyFilters copies the Groups/Vars filters from filters[app.Target] onto the discovered apps.
+//
+// Example:
+//
+//	apps, _ := deploy.DiscoverFlyApps(".")
+//	apps = deploy.ApplyFilters(apps, map[string]deploy.FlyApp{
+//	    "api":    {Groups: []string{"Server", "Database"}},
+//	    "worker": {Groups: []string{"Database", "Queue"}},
+//	})
+func ApplyFilters(apps []FlyApp, filters map[string]FlyApp) []FlyApp {
+	for i := range apps {
+		if f, ok := filters[apps[i].Target]; ok {
+			apps[i].Groups = f.Groups
+			apps[i].Vars = f.Vars
+		}
+	}
+	return apps
+}
+
+// FilterRegistry returns a registry with only the variables this app uses.
+func (a FlyApp) FilterRegistry(registry *env.Registry) *env.Registry {
+	if len(a.Groups) == 0 && len(a.Vars) == 0 {
+		return registry
+	}
+
+	groups := make(map[string]bool, len(a.Groups))
+	for _, g := range a.Groups {
+		groups[g] = true
+	}
+	names := make(map[string]bool, len(a.Vars))
+	for _, n := range a.Vars {
+		names[n] = true
+	}
+
+	var vars []env.EnvVar
+	for _, v := range registry.All() {
+		if groups[v.Group] || names[v.Name] {
+			vars = append(vars, v)
+		}
+	}
+	return env.NewRegistry(vars)
+}
+
+// SecretsImportApp imports only this app's secrets into its Fly app.
+func SecretsImportApp(registry *env.Registry, envFilePath string, app FlyApp) error {
+	return SecretsImport(app.FilterRegistry(registry), envFilePath, app.AppName)
+}
+
+// DeployApp deploys one target using its config file.
+// flyctl runs in the config's directory, which is also the build context.
+func DeployApp(app FlyApp) error {
+	args := []string{"deploy", "--config", filepath.Base(app.ConfigPath)}
+	if app.AppName != "" {
+		args = append(args, "--app", app.AppName)
+	}
+
+	cmd := exec.Command("flyctl", args...)
+	cmd.Dir = filepath.Dir(app.ConfigPath)
+	return run(cmd, nil)
+}
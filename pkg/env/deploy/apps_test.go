@@ -0,0 +1,102 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+func TestFlyTargetName(t *testing.T) {
+	tests := []struct {
+		base   string
+		target string
+		ok     bool
+	}{
+		{"fly.toml", DefaultFlyTarget, true},
+		{"fly.api.toml", "api", true},
+		{"fly.worker.toml", "worker", true},
+		{"fly.a.b.toml", "", false},
+		{"fly..toml", "", false},
+		{"flyer.toml", "", false},
+		{"fly.api.yaml", "", false},
+	}
+	for _, tt := range tests {
+		target, ok := flyTargetName(tt.base)
+		if target != tt.target || ok != tt.ok {
+			t.Errorf("flyTargetName(%q) = %q, %v; want %q, %v", tt.base, target, ok, tt.target, tt.ok)
+		}
+	}
+}
+
+func TestDiscoverFlyApps(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"fly.toml":        "app = 'shop'\nprimary_region = 'syd'\n",
+		"fly.worker.toml": "app = \"shop-worker\"\n",
+		"fly.api.toml":    "app = 'shop-api'\nprimary_region = 'sjc'\n",
+		"fly.a.b.toml":    "app = 'ignored'\n",
+		"flyer.toml":      "app = 'ignored'\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	apps, err := DiscoverFlyApps(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FlyApp{
+		{Target: "api", ConfigPath: filepath.Join(dir, "fly.api.toml"), AppName: "shop-api", Region: "sjc"},
+		{Target: "default", ConfigPath: filepath.Join(dir, "fly.toml"), AppName: "shop", Region: "syd"},
+		{Target: "worker", ConfigPath: filepath.Join(dir, "fly.worker.toml"), AppName: "shop-worker"},
+	}
+	if !reflect.DeepEqual(apps, want) {
+		t.Errorf("DiscoverFlyApps() =\n%+v\nwant\n%+v", apps, want)
+	}
+
+	if app, err := FindFlyApp(dir, "worker"); err != nil || app.AppName != "shop-worker" {
+		t.Errorf("FindFlyApp(worker) = %+v, %v", app, err)
+	}
+	if _, err := FindFlyApp(dir, "cron"); err == nil {
+		t.Error("expected an error for an unknown target")
+	}
+}
+
+func TestApplyFiltersAndFilterRegistry(t *testing.T) {
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "PORT", Group: "Server"},
+		{Name: "DATABASE_URL", Group: "Database"},
+		{Name: "QUEUE_URL", Group: "Queue"},
+		{Name: "SENTRY_DSN", Group: "Monitoring"},
+	})
+	apps := ApplyFilters([]FlyApp{{Target: "api"}, {Target: "worker"}, {Target: "default"}}, map[string]FlyApp{
+		"api":    {Groups: []string{"Server", "Database"}},
+		"worker": {Groups: []string{"Queue"}, Vars: []string{"DATABASE_URL", "SENTRY_DSN"}},
+		"cron":   {Groups: []string{"Queue"}}, // No such app: ignored
+	})
+
+	names := func(r *env.Registry) []string {
+		var names []string
+		for _, v := range r.All() {
+			names = append(names, v.Name)
+		}
+		return names
+	}
+	tests := []struct {
+		app  FlyApp
+		want []string
+	}{
+		{apps[0], []string{"PORT", "DATABASE_URL"}},
+		{apps[1], []string{"DATABASE_URL", "QUEUE_URL", "SENTRY_DSN"}}, // Registry order
+		{apps[2], []string{"PORT", "DATABASE_URL", "QUEUE_URL", "SENTRY_DSN"}},
+	}
+	for _, tt := range tests {
+		if got := names(tt.app.FilterRegistry(registry)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: FilterRegistry() = %v, want %v", tt.app.Target, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package deploy
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -17,6 +18,36 @@ import (
 // Self-contained Fly.io automation that models all flyctl commands
 // Leverages registry knowledge for forward engineering (secrets, config)
 
+// ================================================================
+// Output Redaction
+// ================================================================
+
+// RedactRegistry is the registry whose secret values are masked in flyctl output.
+// Set it once at startup (e.g., deploy.RedactRegistry = AppRegistry) so secrets
+// echoed by flyctl never reach the terminal or CI logs.
+//
+// Interactive commands (login, launch, ssh, ...) keep the real terminal so
+// prompts and TTY detection still work, and are not redacted.
+var RedactRegistry *env.Registry
+
+// run executes a non-interactive command with secret values masked in its output.
+// extra holds additional NAME -> value secrets to mask (e.g., loaded from a file).
+func run(cmd *exec.Cmd, extra map[string]string) error {
+	stdout := env.RedactingWriter(os.Stdout, RedactRegistry)
+	stderr := env.RedactingWriter(os.Stderr, RedactRegistry)
+	for name, value := range extra {
+		stdout.AddSecret(name, value)
+		stderr.AddSecret(name, value)
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	stdout.Flush()
+	stderr.Flush()
+	return err
+}
+
 // ================================================================
 // Installation
 // ================================================================
@@ -41,9 +72,7 @@ func Install() error {
 // AuthWhoami checks who is currently logged in
 func AuthWhoami() error {
 	cmd := exec.Command("flyctl", "auth", "whoami")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // AuthLogin performs interactive browser login
@@ -59,9 +88,7 @@ func AuthLogin() error {
 // AuthLogout logs out the current user
 func AuthLogout() error {
 	cmd := exec.Command("flyctl", "auth", "logout")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // ================================================================
@@ -88,9 +115,7 @@ func AppsCreate(name, org string) error {
 // AppsList lists all apps
 func AppsList() error {
 	cmd := exec.Command("flyctl", "apps", "list")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // AppsDestroy destroys an app (WARNING: destructive)
@@ -109,7 +134,13 @@ func AppExists(name string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return strings.Contains(string(output), name), nil
+	// Match the NAME column exactly, so "app-pr-1" is not found in "app-pr-12"
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == name {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // ================================================================
@@ -149,9 +180,7 @@ func VolumesCreate(volumeName, app, region string, sizeGB int) error {
 	args = append(args, "--yes")
 
 	cmd := exec.Command("flyctl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // VolumesList lists volumes for an app
@@ -162,17 +191,13 @@ func VolumesList(app string) error {
 	}
 
 	cmd := exec.Command("flyctl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // VolumesDestroy destroys a volume
 func VolumesDestroy(volumeID string) error {
 	cmd := exec.Command("flyctl", "volumes", "destroy", volumeID, "--yes")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // ================================================================
@@ -227,11 +252,9 @@ func SecretsImport(registry *env.Registry, envFilePath, app string) error {
 
 	cmd := exec.Command("flyctl", args...)
 	cmd.Stdin = strings.NewReader(secretsInput)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
 	fmt.Printf("🔐 Importing %d secrets to Fly.io...\n", len(lines))
-	return cmd.Run()
+	return run(cmd, secrets)
 }
 
 // SecretsList lists all secrets for an app
@@ -242,9 +265,7 @@ func SecretsList(app string) error {
 	}
 
 	cmd := exec.Command("flyctl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // SecretsUnset removes a secret
@@ -255,9 +276,40 @@ func SecretsUnset(app, name string) error {
 	}
 
 	cmd := exec.Command("flyctl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
+}
+
+// SecretNames returns the names of the secrets set on app (flyctl never
+// reveals their values), for env.ImportSecrets
+func SecretNames(app string) ([]string, error) {
+	args := []string{"secrets", "list", "--json"}
+	if app != "" {
+		args = append(args, "--app", app)
+	}
+
+	out, err := exec.Command("flyctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	return ParseSecretNames(out)
+}
+
+// ParseSecretNames extracts the names from "flyctl secrets list --json"
+// output (older flyctl versions capitalize the keys; both are accepted)
+func ParseSecretNames(data []byte) ([]string, error) {
+	var secrets []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets list: %w", err)
+	}
+	names := make([]string, 0, len(secrets))
+	for _, s := range secrets {
+		if s.Name != "" {
+			names = append(names, s.Name)
+		}
+	}
+	return names, nil
 }
 
 // ================================================================
@@ -272,9 +324,7 @@ func Deploy(app string) error {
 	}
 
 	cmd := exec.Command("flyctl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // Status shows app status
@@ -285,9 +335,7 @@ func Status(app string) error {
 	}
 
 	cmd := exec.Command("flyctl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // Logs tails app logs
@@ -301,9 +349,7 @@ func Logs(app string, follow bool) error {
 	}
 
 	cmd := exec.Command("flyctl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // ================================================================
@@ -332,9 +378,7 @@ func Open(app string) error {
 	}
 
 	cmd := exec.Command("flyctl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return run(cmd, nil)
 }
 
 // ================================================================
@@ -343,9 +387,14 @@ func Open(app string) error {
 
 // ReadFlyTomlConfig reads fly.toml and extracts app name and region
 func ReadFlyTomlConfig() (appName, region string, err error) {
-	file, err := os.Open("fly.toml")
+	return ReadFlyTomlConfigFile("fly.toml")
+}
+
+// ReadFlyTomlConfigFile reads a fly config file (e.g., fly.api.toml) and extracts app name and region
+func ReadFlyTomlConfigFile(path string) (appName, region string, err error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to open fly.toml: %w", err)
+		return "", "", fmt.Errorf("failed to open %s: %w", path, err)
 	}
 	defer file.Close()
 
@@ -371,7 +420,7 @@ func ReadFlyTomlConfig() (appName, region string, err error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return "", "", fmt.Errorf("error reading fly.toml: %w", err)
+		return "", "", fmt.Errorf("error reading %s: %w", path, err)
 	}
 
 	return appName, region, nil
@@ -0,0 +1,78 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ================================================================
+// GitHub PR Comments
+// ================================================================
+// Posts drift reports (env.GeneratePRComment) to pull requests so CI
+// can keep deployment configs honest without a separate bot.
+
+// GitHubAPIURL is the default GitHub REST API base URL
+const GitHubAPIURL = "https://api.github.com"
+
+// PRCommentOptions configures posting a comment to a pull request
+type PRCommentOptions struct {
+	Repo     string // "owner/repo" (default: $GITHUB_REPOSITORY)
+	PRNumber int    // Pull request number
+	Token    string // API token (default: $GITHUB_TOKEN)
+	Body     string // Markdown comment body
+	APIURL   string // API base URL (default: GitHubAPIURL, override for GitHub Enterprise)
+}
+
+// PostPRComment posts a Markdown comment to a GitHub pull request
+func PostPRComment(opts PRCommentOptions) error {
+	if opts.Repo == "" {
+		opts.Repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if opts.Token == "" {
+		opts.Token = os.Getenv("GITHUB_TOKEN")
+	}
+	if opts.APIURL == "" {
+		opts.APIURL = GitHubAPIURL
+	}
+	if opts.Repo == "" || opts.PRNumber == 0 {
+		return fmt.Errorf("repo and PR number are required")
+	}
+	if opts.Token == "" {
+		return fmt.Errorf("GitHub token is required (set GITHUB_TOKEN)")
+	}
+
+	payload, err := json.Marshal(map[string]string{"body": opts.Body})
+	if err != nil {
+		return fmt.Errorf("failed to encode comment: %w", err)
+	}
+
+	// PR comments go through the issues API
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", opts.APIURL, opts.Repo, opts.PRNumber)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+opts.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post PR comment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to post PR comment: %s: %s", resp.Status, body)
+	}
+
+	fmt.Printf("✅ Posted drift report to %s#%d\n", opts.Repo, opts.PRNumber)
+	return nil
+}
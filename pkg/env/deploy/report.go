@@ -0,0 +1,203 @@
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// ================================================================
+// Cost and Resource Report
+// ================================================================
+// Lists the machines, volumes and regions of a Fly.io app (flyctl --json)
+// with an estimated monthly cost, so sizing choices made at launch stay
+// visible. Estimates use list prices (FlyPrices) and leave out bandwidth,
+// dedicated IPv4 and plan allowances - check the Fly.io dashboard for billing.
+
+// FlyPricing holds the list prices (USD per month) estimates are based on
+type FlyPricing struct {
+	SharedCPU      float64 // Per shared vCPU
+	PerformanceCPU float64 // Per performance vCPU
+	MemoryGB       float64 // Per GB of RAM
+	VolumeGB       float64 // Per GB of provisioned volume
+}
+
+// FlyPrices are the prices used by FlyReport (a shared-cpu-1x with 256MB
+// comes to $1.94, a 1GB volume to $0.15). Override when Fly.io changes them.
+var FlyPrices = FlyPricing{
+	SharedCPU:      0.69,
+	PerformanceCPU: 22.19,
+	MemoryGB:       5.00,
+	VolumeGB:       0.15,
+}
+
+// MachineReport is one machine of the app
+type MachineReport struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Region   string  `json:"region"`
+	State    string  `json:"state"`
+	CPUKind  string  `json:"cpu_kind"`
+	CPUs     int     `json:"cpus"`
+	MemoryMB int     `json:"memory_mb"`
+	Monthly  float64 `json:"monthly_usd"` // Estimated cost if running all month (0 when stopped)
+}
+
+// Size returns the Fly size name, e.g. "shared-cpu-1x 256MB" or "performance-2x 4096MB"
+func (m MachineReport) Size() string {
+	if m.CPUKind == "performance" {
+		return fmt.Sprintf("performance-%dx %dMB", m.CPUs, m.MemoryMB)
+	}
+	return fmt.Sprintf("%s-cpu-%dx %dMB", m.CPUKind, m.CPUs, m.MemoryMB)
+}
+
+// VolumeReport is one volume of the app
+type VolumeReport struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Region  string  `json:"region"`
+	SizeGB  int     `json:"size_gb"`
+	Monthly float64 `json:"monthly_usd"`
+}
+
+// CostReport describes an app's resources and estimated monthly cost
+type CostReport struct {
+	App      string          `json:"app"`
+	Machines []MachineReport `json:"machines"`
+	Volumes  []VolumeReport  `json:"volumes"`
+	Regions  []string        `json:"regions"`
+	Compute  float64         `json:"compute_usd"`
+	Storage  float64         `json:"storage_usd"`
+	Total    float64         `json:"total_usd"`
+}
+
+// FlyReport queries the machines and volumes of app and estimates their
+// monthly cost. Stopped machines are listed but not charged for compute.
+//
+// Example:
+//
+//	report, err := deploy.FlyReport("my-app")
+//	report.Print(os.Stdout)
+func FlyReport(app string) (*CostReport, error) {
+	if app == "" {
+		return nil, fmt.Errorf("app name is required")
+	}
+
+	machinesJSON, err := flyctlOutput("machines", "list", "--app", app, "--json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list machines of %s: %w", app, err)
+	}
+	volumesJSON, err := flyctlOutput("volumes", "list", "--app", app, "--json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes of %s: %w", app, err)
+	}
+	return BuildCostReport(app, machinesJSON, volumesJSON, FlyPrices)
+}
+
+// flyctlOutput runs flyctl and returns its stdout; a failure includes what
+// flyctl printed to stderr (e.g. "Could not find App")
+func flyctlOutput(args ...string) ([]byte, error) {
+	output, err := exec.Command("flyctl", args...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+			return nil, fmt.Errorf("%w: %s", err, stderr)
+		}
+	}
+	return output, err
+}
+
+// BuildCostReport builds the report from the JSON output of
+// "flyctl machines list --json" and "flyctl volumes list --json"
+func BuildCostReport(app string, machinesJSON, volumesJSON []byte, prices FlyPricing) (*CostReport, error) {
+	var machines []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Region string `json:"region"`
+		State  string `json:"state"`
+		Config struct {
+			Guest struct {
+				CPUKind  string `json:"cpu_kind"`
+				CPUs     int    `json:"cpus"`
+				MemoryMB int    `json:"memory_mb"`
+			} `json:"guest"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(machinesJSON, &machines); err != nil {
+		return nil, fmt.Errorf("failed to parse machines: %w", err)
+	}
+	var volumes []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Region string `json:"region"`
+		SizeGB int    `json:"size_gb"`
+	}
+	if err := json.Unmarshal(volumesJSON, &volumes); err != nil {
+		return nil, fmt.Errorf("failed to parse volumes: %w", err)
+	}
+
+	report := &CostReport{App: app}
+	regions := map[string]bool{}
+	for _, m := range machines {
+		guest := m.Config.Guest
+		mr := MachineReport{
+			ID: m.ID, Name: m.Name, Region: m.Region, State: m.State,
+			CPUKind: guest.CPUKind, CPUs: guest.CPUs, MemoryMB: guest.MemoryMB,
+		}
+		if mr.CPUKind == "" {
+			mr.CPUKind = "shared"
+		}
+		if m.State != "stopped" && m.State != "suspended" && m.State != "destroyed" {
+			cpu := prices.SharedCPU
+			if mr.CPUKind == "performance" {
+				cpu = prices.PerformanceCPU
+			}
+			mr.Monthly = float64(mr.CPUs)*cpu + float64(mr.MemoryMB)/1024*prices.MemoryGB
+		}
+		report.Machines = append(report.Machines, mr)
+		report.Compute += mr.Monthly
+		regions[m.Region] = true
+	}
+	for _, v := range volumes {
+		vr := VolumeReport{ID: v.ID, Name: v.Name, Region: v.Region, SizeGB: v.SizeGB, Monthly: float64(v.SizeGB) * prices.VolumeGB}
+		report.Volumes = append(report.Volumes, vr)
+		report.Storage += vr.Monthly
+		regions[v.Region] = true
+	}
+	for region := range regions {
+		if region != "" {
+			report.Regions = append(report.Regions, region)
+		}
+	}
+	sort.Strings(report.Regions)
+	report.Total = report.Compute + report.Storage
+	return report, nil
+}
+
+// Print writes the report as a table with the cost breakdown
+func (r *CostReport) Print(w io.Writer) {
+	fmt.Fprintf(w, "📊 %s: %d machine(s), %d volume(s) in %s\n\n", r.App, len(r.Machines), len(r.Volumes), strings.Join(r.Regions, ", "))
+
+	if len(r.Machines) > 0 {
+		fmt.Fprintln(w, "Machines:")
+		for _, m := range r.Machines {
+			fmt.Fprintf(w, "  %-16s %-6s %-9s %-26s $%7.2f\n", m.ID, m.Region, m.State, m.Size(), m.Monthly)
+		}
+		fmt.Fprintln(w)
+	}
+	if len(r.Volumes) > 0 {
+		fmt.Fprintln(w, "Volumes:")
+		for _, v := range r.Volumes {
+			fmt.Fprintf(w, "  %-16s %-6s %-9s %-26s $%7.2f\n", v.ID, v.Region, v.Name, fmt.Sprintf("%dGB", v.SizeGB), v.Monthly)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Compute:  $%7.2f/month\n", r.Compute)
+	fmt.Fprintf(w, "Storage:  $%7.2f/month\n", r.Storage)
+	fmt.Fprintf(w, "Total:    $%7.2f/month (estimate: list prices, excluding bandwidth, IPv4 and plan allowances)\n", r.Total)
+}
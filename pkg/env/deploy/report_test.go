@@ -0,0 +1,103 @@
+package deploy
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Trimmed output of "flyctl machines list --json" and "flyctl volumes list --json"
+const (
+	machinesFixture = `[
+		{"id": "148e1", "name": "web-syd", "region": "syd", "state": "started",
+		 "config": {"guest": {"cpu_kind": "shared", "cpus": 1, "memory_mb": 256}}},
+		{"id": "148e2", "name": "web-ams", "region": "ams", "state": "stopped",
+		 "config": {"guest": {"cpu_kind": "shared", "cpus": 2, "memory_mb": 512}}},
+		{"id": "148e3", "name": "worker", "region": "iad", "state": "started",
+		 "config": {"guest": {"cpu_kind": "performance", "cpus": 2, "memory_mb": 4096}}},
+		{"id": "148e4", "name": "old", "region": "syd", "state": "suspended",
+		 "config": {"guest": {"cpus": 1, "memory_mb": 256}}}
+	]`
+	volumesFixture = `[
+		{"id": "vol_1", "name": "data", "region": "syd", "size_gb": 10},
+		{"id": "vol_2", "name": "data", "region": "fra", "size_gb": 1}
+	]`
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+func TestBuildCostReport(t *testing.T) {
+	report, err := BuildCostReport("my-app", []byte(machinesFixture), []byte(volumesFixture), FlyPrices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		size    string
+		monthly float64
+	}{
+		"148e1": {"shared-cpu-1x 256MB", 1.94},
+		"148e2": {"shared-cpu-2x 512MB", 0}, // Stopped
+		"148e3": {"performance-2x 4096MB", 2*22.19 + 4*5.00},
+		"148e4": {"shared-cpu-1x 256MB", 0}, // Suspended, no cpu_kind
+	}
+	if len(report.Machines) != len(want) {
+		t.Fatalf("%d machines, want %d", len(report.Machines), len(want))
+	}
+	for _, m := range report.Machines {
+		if w := want[m.ID]; m.Size() != w.size || !approx(m.Monthly, w.monthly) {
+			t.Errorf("%s: %s $%.2f, want %s $%.2f", m.ID, m.Size(), m.Monthly, w.size, w.monthly)
+		}
+	}
+
+	if len(report.Volumes) != 2 || !approx(report.Volumes[0].Monthly, 1.50) || !approx(report.Volumes[1].Monthly, 0.15) {
+		t.Errorf("volumes = %+v", report.Volumes)
+	}
+	if !reflect.DeepEqual(report.Regions, []string{"ams", "fra", "iad", "syd"}) {
+		t.Errorf("regions = %v", report.Regions)
+	}
+	if !approx(report.Compute, 66.32) || !approx(report.Storage, 1.65) || !approx(report.Total, 67.97) {
+		t.Errorf("compute $%.2f, storage $%.2f, total $%.2f", report.Compute, report.Storage, report.Total)
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	for _, s := range []string{"my-app: 4 machine(s), 2 volume(s) in ams, fra, iad, syd", "performance-2x 4096MB", "$  64.38", "Total:    $  67.97/month"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("report lacks %q:\n%s", s, out.String())
+		}
+	}
+}
+
+func TestBuildCostReport_Empty(t *testing.T) {
+	report, err := BuildCostReport("idle", []byte(`[]`), []byte(`[]`), FlyPrices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 0 || len(report.Regions) != 0 {
+		t.Errorf("report = %+v, want nothing", report)
+	}
+	if _, err := BuildCostReport("bad", []byte(`{"error": "x"}`), []byte(`[]`), FlyPrices); err == nil || !strings.Contains(err.Error(), "machines") {
+		t.Errorf("err = %v, want a machines parse error", err)
+	}
+}
+
+func TestFlyReport_Stderr(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho 'Error: Could not find App \"nope\"' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(bin, "flyctl"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	_, err := FlyReport("nope")
+	if err == nil || !strings.Contains(err.Error(), "failed to list machines of nope: exit status 1: Error: Could not find App") {
+		t.Errorf("err = %v, want flyctl's stderr", err)
+	}
+}
@@ -0,0 +1,172 @@
+package deploy
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// ================================================================
+// Review Apps
+// ================================================================
+// One ephemeral Fly.io app per pull request ("<app>-pr-<number>"), created
+// and deployed when the PR opens or changes and destroyed when it closes.
+// Review apps get the non-production secrets (.env.local), never production
+// ones. Driven from CI by workflow.ReviewAppWorkflow.
+
+// ReviewAppOptions configures ReviewAppWithOptions and DestroyReviewAppWithOptions.
+type ReviewAppOptions struct {
+	PRNumber    int              // Pull request number
+	ConfigPath  string           // Fly config of the production app (default: "fly.toml")
+	BaseApp     string           // Production app name the review app is named after (default: from ConfigPath)
+	Org         string           // Fly organization to create the app in (default: flyctl's default)
+	Registry    *env.Registry    // Registry selecting the secrets to copy (nil: copy none)
+	EnvFile     string           // Non-production env file secrets are copied from (default: env.Local.FileName)
+	Volume      string           // Volume to create for a new app, if fly.toml mounts one (e.g., "pb_data")
+	SkipComment bool             // Don't post the URL to the PR
+	Comment     PRCommentOptions // Repo/Token/APIURL for the PR comment (defaults: $GITHUB_REPOSITORY, $GITHUB_TOKEN)
+}
+
+// ReviewAppResult describes a deployed review app.
+type ReviewAppResult struct {
+	App     string // Fly app name
+	URL     string // Public URL
+	Created bool   // The app was created by this run (false: redeployed)
+}
+
+// ReviewAppName returns the review app name for a PR: "<baseApp>-pr-<number>"
+func ReviewAppName(baseApp string, prNumber int) string {
+	return fmt.Sprintf("%s-pr-%d", baseApp, prNumber)
+}
+
+// ReviewApp creates (on first use) and deploys the review app for a PR,
+// copies the non-production secrets and posts its URL to the PR.
+//
+// Example (CI, on pull_request opened/synchronize):
+//
+//	result, err := deploy.ReviewApp(prNumber)
+//	fmt.Println(result.URL) // https://my-app-pr-42.fly.dev
+func ReviewApp(prNumber int) (*ReviewAppResult, error) {
+	return ReviewAppWithOptions(ReviewAppOptions{PRNumber: prNumber})
+}
+
+// ReviewAppWithOptions is ReviewApp with secrets, a volume and a custom config.
+func ReviewAppWithOptions(opts ReviewAppOptions) (*ReviewAppResult, error) {
+	opts, region, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	name := ReviewAppName(opts.BaseApp, opts.PRNumber)
+	result := &ReviewAppResult{App: name, URL: fmt.Sprintf("https://%s.fly.dev", name)}
+
+	// 1. Create the app (and its volume) on first use
+	exists, err := AppExists(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list apps: %w", err)
+	}
+	if !exists {
+		args := []string{"apps", "create", name}
+		if opts.Org != "" {
+			args = append(args, "--org", opts.Org)
+		}
+		fmt.Printf("🆕 Creating review app %s...\n", name)
+		if err := run(exec.Command("flyctl", args...), nil); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", name, err)
+		}
+		result.Created = true
+
+		if opts.Volume != "" {
+			if err := VolumesCreate(opts.Volume, name, region, 1); err != nil {
+				return result, fmt.Errorf("failed to create volume for %s: %w", name, err)
+			}
+		}
+	}
+
+	// 2. Copy non-production secrets (staged; applied by the deploy)
+	if opts.Registry != nil && len(opts.Registry.GetSecrets()) > 0 {
+		if err := SecretsImport(opts.Registry, opts.EnvFile, name); err != nil {
+			return result, fmt.Errorf("failed to copy secrets to %s: %w", name, err)
+		}
+	}
+
+	// 3. Deploy a single machine (no HA for throwaway apps)
+	args := []string{"deploy", "--config", filepath.Base(opts.ConfigPath), "--app", name, "--ha=false"}
+	cmd := exec.Command("flyctl", args...)
+	cmd.Dir = filepath.Dir(opts.ConfigPath)
+	fmt.Printf("🚀 Deploying review app %s...\n", name)
+	if err := run(cmd, nil); err != nil {
+		return result, fmt.Errorf("failed to deploy %s: %w", name, err)
+	}
+
+	// 4. Post the URL once, when the app is new
+	if result.Created && !opts.SkipComment {
+		comment := opts.Comment
+		comment.PRNumber = opts.PRNumber
+		comment.Body = fmt.Sprintf("🚀 Review app deployed: %s\n\nIt is redeployed on every push and destroyed when this PR closes.", result.URL)
+		if err := PostPRComment(comment); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// DestroyReviewApp destroys the review app of a PR (no-op if it doesn't exist).
+//
+// Example (CI, on pull_request closed):
+//
+//	err := deploy.DestroyReviewApp(prNumber)
+func DestroyReviewApp(prNumber int) error {
+	return DestroyReviewAppWithOptions(ReviewAppOptions{PRNumber: prNumber})
+}
+
+// DestroyReviewAppWithOptions is DestroyReviewApp with a custom config or base app.
+// Only the "<baseApp>-pr-<number>" app is ever destroyed.
+func DestroyReviewAppWithOptions(opts ReviewAppOptions) error {
+	opts, _, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+	name := ReviewAppName(opts.BaseApp, opts.PRNumber)
+
+	exists, err := AppExists(name)
+	if err != nil {
+		return fmt.Errorf("failed to list apps: %w", err)
+	}
+	if !exists {
+		fmt.Printf("💡 Review app %s does not exist\n", name)
+		return nil
+	}
+
+	fmt.Printf("🗑️  Destroying review app %s...\n", name)
+	if err := run(exec.Command("flyctl", "apps", "destroy", name, "--yes"), nil); err != nil {
+		return fmt.Errorf("failed to destroy %s: %w", name, err)
+	}
+	return nil
+}
+
+// withDefaults validates opts and fills in the defaults (and the config's region)
+func (opts ReviewAppOptions) withDefaults() (ReviewAppOptions, string, error) {
+	if opts.PRNumber <= 0 {
+		return opts, "", fmt.Errorf("pull request number is required")
+	}
+	if opts.ConfigPath == "" {
+		opts.ConfigPath = "fly.toml"
+	}
+	if opts.EnvFile == "" {
+		opts.EnvFile = env.Local.FileName
+	}
+
+	appName, region, err := ReadFlyTomlConfigFile(opts.ConfigPath)
+	if err != nil && opts.BaseApp == "" {
+		return opts, "", err
+	}
+	if opts.BaseApp == "" {
+		opts.BaseApp = appName
+	}
+	if opts.BaseApp == "" {
+		return opts, "", fmt.Errorf("no app name in %s", opts.ConfigPath)
+	}
+	return opts, region, nil
+}
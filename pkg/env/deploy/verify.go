@@ -0,0 +1,181 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// ================================================================
+// Post-Deploy Verification
+// ================================================================
+// Closes the loop after Deploy: waits for the new instance to report healthy
+// via the webui endpoints (/health, /env?format=json) and checks that every
+// required registry variable is configured on the deployed machine. A
+// "degraded" instance (an optional check failing, see pkg/health) passes with
+// a warning.
+
+// VerifyOptions configures VerifyDeploymentWithOptions.
+type VerifyOptions struct {
+	URL             string        // Base URL of the deployed app (e.g., "https://my-app.fly.dev")
+	Registry        *env.Registry // Registry defining the required variables
+	ExpectedVersion string        // Version /health must report (prefix match, e.g. short git SHA); empty skips the check
+	Timeout         time.Duration // How long to wait for /health to turn ok (default: 2m)
+	Interval        time.Duration // Delay between health polls (default: 5s)
+	Client          *http.Client  // HTTP client (default: 10s timeout)
+}
+
+// VerifyResult describes the deployed instance.
+type VerifyResult struct {
+	Status          string   // Status reported by /health ("ok" or "degraded")
+	Environment     string   // Environment reported by /health
+	Version         string   // Version reported by /health
+	Attempts        int      // Health polls made
+	MissingRequired []string // Required variables not configured on the instance
+	Warnings        []string // Non-fatal issues (e.g., version not reported)
+}
+
+// VerifyDeployment checks a freshly deployed app: /health must report "ok" (or
+// "degraded", which adds a warning), and /env?format=json must show every required registry variable configured.
+// Returns an error (with the partial result) if verification fails.
+//
+// Example:
+//
+//	if err := deploy.Deploy(app); err != nil { ... }
+//	if _, err := deploy.VerifyDeployment("https://"+app+".fly.dev", registry); err != nil {
+//	    log.Fatalf("deployment unhealthy: %v", err)
+//	}
+func VerifyDeployment(appURL string, registry *env.Registry) (*VerifyResult, error) {
+	return VerifyDeploymentWithOptions(VerifyOptions{
+		URL:      appURL,
+		Registry: registry,
+	})
+}
+
+// VerifyDeploymentWithOptions is VerifyDeployment with version checking and custom timing.
+func VerifyDeploymentWithOptions(opts VerifyOptions) (*VerifyResult, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("app URL is required")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+	if opts.Interval == 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	baseURL := strings.TrimSuffix(opts.URL, "/")
+
+	result := &VerifyResult{}
+
+	// 1. Wait for /health (machines may still be restarting right after deploy)
+	var health struct {
+		Status      string `json:"status"`
+		Environment string `json:"environment"`
+		Version     string `json:"version"`
+		Checks      map[string]struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"checks"`
+	}
+	deadline := time.Now().Add(opts.Timeout)
+	var lastErr error
+	for {
+		result.Attempts++
+		lastErr = getJSON(opts.Client, baseURL+"/health", &health)
+		if lastErr == nil && (health.Status == "ok" || health.Status == "degraded") {
+			break
+		}
+		if lastErr == nil {
+			lastErr = fmt.Errorf("status %q", health.Status)
+		}
+		if time.Now().Add(opts.Interval).After(deadline) {
+			return result, fmt.Errorf("health check failed after %d attempts: %w", result.Attempts, lastErr)
+		}
+		time.Sleep(opts.Interval)
+	}
+	result.Status = health.Status
+	result.Environment = health.Environment
+	result.Version = health.Version
+	if health.Status == "degraded" {
+		var failing []string
+		for name, check := range health.Checks {
+			if check.Status != "ok" {
+				failing = append(failing, name+": "+check.Error)
+			}
+		}
+		sort.Strings(failing)
+		warning := "deployed instance is degraded"
+		if len(failing) > 0 {
+			warning += " (" + strings.Join(failing, "; ") + ")"
+		}
+		result.Warnings = append(result.Warnings, warning)
+	}
+
+	// 2. Version check
+	if opts.ExpectedVersion != "" {
+		switch {
+		case result.Version == "":
+			result.Warnings = append(result.Warnings, "deployed instance does not report a version")
+		case !strings.HasPrefix(result.Version, opts.ExpectedVersion) && !strings.HasPrefix(opts.ExpectedVersion, result.Version):
+			return result, fmt.Errorf("version mismatch: expected %s, running %s", opts.ExpectedVersion, result.Version)
+		}
+	}
+
+	// 3. Required variables
+	if opts.Registry != nil {
+		var envStatus struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := getJSON(opts.Client, baseURL+"/env?format=json", &envStatus); err != nil {
+			return result, fmt.Errorf("failed to fetch /env: %w", err)
+		}
+		for _, v := range opts.Registry.GetRequired() {
+			if configured, _ := envStatus.Variables[strings.ToLower(v.Name)+"_configured"].(bool); !configured {
+				result.MissingRequired = append(result.MissingRequired, v.Name)
+			}
+		}
+		if len(result.MissingRequired) > 0 {
+			return result, fmt.Errorf("required variables not configured: %s", strings.Join(result.MissingRequired, ", "))
+		}
+	}
+
+	return result, nil
+}
+
+// LocalVersion returns the short git revision of the working tree, for use as
+// VerifyOptions.ExpectedVersion. Returns "" outside a git checkout.
+func LocalVersion() string {
+	output, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// getJSON fetches url and decodes the JSON body into v
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("GET %s: invalid JSON: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package deploy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// fakeApp serves /health and /env?format=json as the webui does. Health is
+// "starting" for the first startingPolls polls.
+func fakeApp(t *testing.T, startingPolls int, version string, configured map[string]bool) (*httptest.Server, *int) {
+	t.Helper()
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "ok"
+		if polls <= startingPolls {
+			status = "starting"
+		}
+		json.NewEncoder(w).Encode(map[string]string{"status": status, "environment": "production", "version": version})
+	})
+	mux.HandleFunc("/env", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") != "json" {
+			http.Error(w, "expected format=json", http.StatusBadRequest)
+			return
+		}
+		variables := map[string]interface{}{}
+		for name, ok := range configured {
+			variables[strings.ToLower(name)+"_configured"] = ok
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"variables": variables})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &polls
+}
+
+func verifyRegistry() *env.Registry {
+	return env.NewRegistry([]env.EnvVar{
+		{Name: "DATABASE_URL", Required: true},
+		{Name: "API_KEY", Required: true, Secret: true},
+		{Name: "LOG_LEVEL", Default: "info"},
+	})
+}
+
+func TestVerifyDeployment_Healthy(t *testing.T) {
+	server, polls := fakeApp(t, 2, "abc1234-dirty", map[string]bool{"DATABASE_URL": true, "API_KEY": true})
+
+	result, err := VerifyDeploymentWithOptions(VerifyOptions{
+		URL:             server.URL + "/",
+		Registry:        verifyRegistry(),
+		ExpectedVersion: "abc1234",
+		Timeout:         time.Second,
+		Interval:        time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != "ok" || result.Environment != "production" || result.Version != "abc1234-dirty" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Attempts != 3 || *polls != 3 {
+		t.Errorf("attempts = %d (server saw %d), want 3", result.Attempts, *polls)
+	}
+	if len(result.MissingRequired) != 0 || len(result.Warnings) != 0 {
+		t.Errorf("unexpected issues: %+v", result)
+	}
+}
+
+func TestVerifyDeployment_NeverHealthy(t *testing.T) {
+	server, _ := fakeApp(t, 1000, "", nil)
+
+	result, err := VerifyDeploymentWithOptions(VerifyOptions{
+		URL:      server.URL,
+		Timeout:  50 * time.Millisecond,
+		Interval: 10 * time.Millisecond,
+	})
+	if err == nil || !strings.Contains(err.Error(), `status "starting"`) {
+		t.Fatalf("err = %v, want the last health status", err)
+	}
+	if result.Attempts < 2 || result.Status != "" {
+		t.Errorf("result = %+v, want several polls and no status", result)
+	}
+}
+
+func TestVerifyDeployment_Degraded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "degraded",
+			"version": "abc1234",
+			"checks": map[string]interface{}{
+				"database": map[string]interface{}{"status": "ok", "critical": true},
+				"smtp":     map[string]interface{}{"status": "down", "critical": false, "error": "dial timeout"},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	result, err := VerifyDeploymentWithOptions(VerifyOptions{URL: server.URL, Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("degraded instance failed verification: %v", err)
+	}
+	if result.Status != "degraded" || result.Attempts != 1 {
+		t.Errorf("result = %+v, want degraded on the first poll", result)
+	}
+	if want := []string{"deployed instance is degraded (smtp: dial timeout)"}; !reflect.DeepEqual(result.Warnings, want) {
+		t.Errorf("warnings = %q, want %q", result.Warnings, want)
+	}
+}
+
+func TestVerifyDeployment_Version(t *testing.T) {
+	tests := []struct {
+		running, expected string
+		wantErr           bool
+		wantWarning       bool
+	}{
+		{"abc1234", "abc1234def", false, false}, // Short SHA running, full expected
+		{"abc1234", "fff0000", true, false},
+		{"", "abc1234", false, true}, // Not reported: a warning only
+	}
+	for _, tt := range tests {
+		server, _ := fakeApp(t, 0, tt.running, nil)
+		result, err := VerifyDeploymentWithOptions(VerifyOptions{URL: server.URL, ExpectedVersion: tt.expected, Interval: time.Millisecond})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("running %q, expected %q: err = %v", tt.running, tt.expected, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "version mismatch") {
+			t.Errorf("err = %v, want a version mismatch", err)
+		}
+		if (len(result.Warnings) > 0) != tt.wantWarning {
+			t.Errorf("running %q: warnings = %v", tt.running, result.Warnings)
+		}
+	}
+}
+
+func TestVerifyDeployment_MissingRequired(t *testing.T) {
+	server, _ := fakeApp(t, 0, "", map[string]bool{"DATABASE_URL": true, "API_KEY": false, "LOG_LEVEL": false})
+
+	result, err := VerifyDeploymentWithOptions(VerifyOptions{URL: server.URL, Registry: verifyRegistry(), Interval: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "required variables not configured: API_KEY") {
+		t.Fatalf("err = %v", err)
+	}
+	if !reflect.DeepEqual(result.MissingRequired, []string{"API_KEY"}) {
+		t.Errorf("MissingRequired = %v, want [API_KEY] (LOG_LEVEL is optional)", result.MissingRequired)
+	}
+}
+
+func TestVerifyDeployment_RequiresURL(t *testing.T) {
+	if _, err := VerifyDeployment("", nil); err == nil {
+		t.Error("expected an error without a URL")
+	}
+}
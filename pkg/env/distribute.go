@@ -0,0 +1,297 @@
+// Package env provides encrypted environment distribution over HTTP.
+package env
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+)
+
+// ================================================================
+// Server: ServeEncrypted
+// ================================================================
+
+// RemoteBundle describes one encrypted environment file offered by ServeEncrypted.
+type RemoteBundle struct {
+	Name        string    `json:"name"`        // Encrypted filename (e.g., ".env.production.age")
+	Environment string    `json:"environment"` // Environment name (e.g., "production")
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	Modified    time.Time `json:"modified"`
+}
+
+// ServeEncryptedOptions configures the encrypted distribution handler.
+type ServeEncryptedOptions struct {
+	Environments []*Environment // Environments to publish (default: AllEnvironmentFiles())
+	Token        string         // Optional bearer token required on every request
+}
+
+// ServeEncrypted returns an HTTP handler that publishes the .age bundles of the
+// given environments so CI jobs and new machines can fetch them with PullRemote.
+//
+// Only encrypted files are served - plaintext never leaves the machine, and
+// decryption happens on the client with its own Age identity.
+//
+// Routes (relative to where the handler is mounted):
+//
+//	GET /              JSON index of available bundles ([]RemoteBundle)
+//	GET /{name}.age    Raw encrypted bundle
+//
+// Example:
+//
+//	mux.Handle("/env/bundles/", http.StripPrefix("/env/bundles", env.ServeEncrypted(env.ServeEncryptedOptions{
+//	    Token: os.Getenv("ENV_DIST_TOKEN"),
+//	})))
+func ServeEncrypted(opts ServeEncryptedOptions) http.Handler {
+	if opts.Environments == nil {
+		opts.Environments = AllEnvironmentFiles()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if opts.Token != "" && !validBearerToken(r, opts.Token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if name == "" {
+			bundles, err := listBundles(opts.Environments)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(bundles)
+			return
+		}
+
+		// Only serve files that belong to a published environment (no path traversal)
+		for _, e := range opts.Environments {
+			if e.EncryptedFileName() != name {
+				continue
+			}
+			data, err := os.ReadFile(e.FullEncryptedPath())
+			if os.IsNotExist(err) {
+				break
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			sum := sha256.Sum256(data)
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+			w.Header().Set("Cache-Control", "no-store")
+			w.Write(data)
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+}
+
+// listBundles builds the index of encrypted files that exist on disk
+func listBundles(environments []*Environment) ([]RemoteBundle, error) {
+	bundles := []RemoteBundle{}
+	for _, e := range environments {
+		path := e.FullEncryptedPath()
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", e.EncryptedFileName(), err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", e.EncryptedFileName(), err)
+		}
+		sum := sha256.Sum256(data)
+		bundles = append(bundles, RemoteBundle{
+			Name:        e.EncryptedFileName(),
+			Environment: e.Name,
+			Size:        info.Size(),
+			SHA256:      hex.EncodeToString(sum[:]),
+			Modified:    info.ModTime().UTC(),
+		})
+	}
+	return bundles, nil
+}
+
+// validBearerToken checks the Authorization header in constant time
+func validBearerToken(r *http.Request, token string) bool {
+	got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// ================================================================
+// Client: PullRemote
+// ================================================================
+
+// PullOptions configures fetching encrypted bundles from a ServeEncrypted endpoint.
+type PullOptions struct {
+	URL           string       // Base URL of the ServeEncrypted handler
+	IdentityPath  string       // Age identity used to decrypt (default: DefaultAgeKeyPath)
+	Token         string       // Optional bearer token
+	Dir           string       // Directory to write files to (default: ".")
+	KeepEncrypted bool         // Also write the .age file next to the plaintext
+	Client        *http.Client // HTTP client (default: 30s timeout)
+}
+
+// PullRemote fetches all encrypted bundles from url and decrypts them locally
+// with the Age identity at identityPath, writing plaintext env files into the
+// current directory.
+//
+// Example:
+//
+//	result, err := env.PullRemote("https://config.internal/env/bundles", ".age/key.txt")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("Pulled %d files\n", len(result.ProcessedFiles))
+func PullRemote(url, identityPath string) (*EncryptionResult, error) {
+	return PullRemoteWithOptions(PullOptions{
+		URL:          url,
+		IdentityPath: identityPath,
+	})
+}
+
+// PullRemoteWithOptions is PullRemote with full control over token, target directory and client.
+func PullRemoteWithOptions(opts PullOptions) (*EncryptionResult, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("remote URL is required")
+	}
+	if opts.IdentityPath == "" {
+		opts.IdentityPath = DefaultAgeKeyPath
+	}
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	baseURL := strings.TrimSuffix(opts.URL, "/")
+
+	identities, err := loadIdentityFile(opts.IdentityPath)
+	if err != nil {
+		return nil, err
+	}
+
+	indexData, err := fetchRemote(opts.Client, baseURL+"/", opts.Token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bundle index: %w", err)
+	}
+	var bundles []RemoteBundle
+	if err := json.Unmarshal(indexData, &bundles); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle index: %w", err)
+	}
+
+	result := &EncryptionResult{}
+	for _, bundle := range bundles {
+		// Never trust remote names as paths
+		if bundle.Name != filepath.Base(bundle.Name) || !strings.HasSuffix(bundle.Name, ".age") {
+			result.Errors = append(result.Errors, fmt.Errorf("refusing unsafe bundle name %q", bundle.Name))
+			continue
+		}
+
+		encrypted, err := fetchRemote(opts.Client, baseURL+"/"+bundle.Name, opts.Token)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to fetch %s: %w", bundle.Name, err))
+			continue
+		}
+
+		sum := sha256.Sum256(encrypted)
+		if bundle.SHA256 != "" && hex.EncodeToString(sum[:]) != bundle.SHA256 {
+			result.Errors = append(result.Errors, fmt.Errorf("checksum mismatch for %s", bundle.Name))
+			continue
+		}
+
+		plaintext, err := decryptWithIdentities(encrypted, identities)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to decrypt %s: %w", bundle.Name, err))
+			continue
+		}
+
+		plainName := strings.TrimSuffix(bundle.Name, ".age")
+		if err := os.WriteFile(filepath.Join(opts.Dir, plainName), plaintext, 0600); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to write %s: %w", plainName, err))
+			continue
+		}
+		if opts.KeepEncrypted {
+			if err := os.WriteFile(filepath.Join(opts.Dir, bundle.Name), encrypted, 0600); err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("failed to write %s: %w", bundle.Name, err))
+				continue
+			}
+		}
+
+		result.ProcessedFiles = append(result.ProcessedFiles, plainName)
+	}
+
+	// If nothing was processed and we have errors, return error
+	if len(result.ProcessedFiles) == 0 && len(result.Errors) > 0 {
+		return result, fmt.Errorf("failed to pull any files: %w", result.Errors[0])
+	}
+
+	return result, nil
+}
+
+// fetchRemote performs an authenticated GET and returns the body
+func fetchRemote(client *http.Client, url, token string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// loadIdentityFile parses Age identities from a single key file
+func loadIdentityFile(path string) ([]age.Identity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, Classify(ErrNoIdentity, fmt.Errorf("failed to read identity from %s: %w", path, err))
+	}
+	identities, err := age.ParseIdentities(bytes.NewReader(data))
+	if err != nil {
+		return nil, Classify(ErrNoIdentity, fmt.Errorf("failed to parse identity from %s: %w", path, err))
+	}
+	return identities, nil
+}
+
+// decryptWithIdentities decrypts Age data with explicit identities
+func decryptWithIdentities(data []byte, identities []age.Identity) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(data), identities...)
+	if err != nil {
+		return nil, Classify(ErrDecryptFailed, err)
+	}
+	return io.ReadAll(r)
+}
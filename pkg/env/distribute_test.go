@@ -0,0 +1,111 @@
+package env
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupEncryptedBundle creates a key and an encrypted .env.production.age in dir
+func setupEncryptedBundle(t *testing.T, dir string) (keyPath string, envFile *Environment) {
+	t.Helper()
+
+	keyPath = filepath.Join(dir, ".age", "key.txt")
+	if _, err := GenerateAgeKey(KeygenOptions{KeyPath: keyPath}); err != nil {
+		t.Fatalf("GenerateAgeKey failed: %v", err)
+	}
+
+	envFile = Production.WithBaseDir(dir)
+	if err := os.WriteFile(envFile.FullPath(), []byte("API_KEY=secret123\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EncryptEnvironments(EncryptionOptions{
+		KeyPath:      keyPath,
+		Environments: []*Environment{envFile},
+	}); err != nil {
+		t.Fatalf("EncryptEnvironments failed: %v", err)
+	}
+	return keyPath, envFile
+}
+
+func TestServeEncrypted_PullRemote(t *testing.T) {
+	srcDir := t.TempDir()
+	keyPath, envFile := setupEncryptedBundle(t, srcDir)
+
+	srv := httptest.NewServer(ServeEncrypted(ServeEncryptedOptions{
+		Environments: []*Environment{envFile, Local.WithBaseDir(srcDir)},
+		Token:        "s3cret",
+	}))
+	defer srv.Close()
+
+	dstDir := t.TempDir()
+	result, err := PullRemoteWithOptions(PullOptions{
+		URL:          srv.URL,
+		IdentityPath: keyPath,
+		Token:        "s3cret",
+		Dir:          dstDir,
+	})
+	if err != nil {
+		t.Fatalf("PullRemote failed: %v", err)
+	}
+	if len(result.ProcessedFiles) != 1 || result.ProcessedFiles[0] != ".env.production" {
+		t.Fatalf("ProcessedFiles = %v, want [.env.production]", result.ProcessedFiles)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dstDir, ".env.production"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "API_KEY=secret123\n" {
+		t.Errorf("decrypted content = %q", got)
+	}
+}
+
+func TestServeEncrypted_Unauthorized(t *testing.T) {
+	handler := ServeEncrypted(ServeEncryptedOptions{Token: "s3cret"})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
+
+func TestServeEncrypted_OnlyPublishedFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, envFile := setupEncryptedBundle(t, dir)
+	handler := ServeEncrypted(ServeEncryptedOptions{Environments: []*Environment{envFile}})
+
+	for _, path := range []string{"/.env.production", "/../.age/key.txt", "/.age/key.txt", "/.env.local.age"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s status = %d, want 404", path, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/.env.production.age", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET bundle status = %d, want 200", rec.Code)
+	}
+}
+
+func TestPullRemote_WrongIdentity(t *testing.T) {
+	srcDir := t.TempDir()
+	_, envFile := setupEncryptedBundle(t, srcDir)
+
+	srv := httptest.NewServer(ServeEncrypted(ServeEncryptedOptions{Environments: []*Environment{envFile}}))
+	defer srv.Close()
+
+	otherKey := filepath.Join(t.TempDir(), "other.txt")
+	if _, err := GenerateAgeKey(KeygenOptions{KeyPath: otherKey}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := PullRemoteWithOptions(PullOptions{URL: srv.URL, IdentityPath: otherKey, Dir: t.TempDir()}); err == nil {
+		t.Error("expected error when decrypting with the wrong identity")
+	}
+}
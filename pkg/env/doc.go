@@ -39,6 +39,19 @@
 //	port := registry.ByName("PORT").GetInt()
 //	debug := registry.ByName("DEBUG").GetBool()
 //
+// Declare a Kind for durations, sizes, URLs, host:port pairs and paths, so
+// values are validated and read parsed (templates show the expected syntax):
+//
+//	{Name: "REQUEST_TIMEOUT", Kind: env.KindDuration, Default: "30s"}
+//	timeout := registry.ByName("REQUEST_TIMEOUT").GetDuration()
+//	limit := registry.ByName("MAX_UPLOAD_SIZE").GetBytes() // "512MB"
+//
+// Libraries can define unprefixed names and let the app namespace them; the
+// prefix is applied on lookup and in everything generated:
+//
+//	registry := env.NewRegistry(lib.EnvVars, env.Prefix("MYAPP_"))
+//	port := registry.ByName("PORT").GetInt() // reads $MYAPP_PORT
+//
 // # Environment Files
 //
 // Pre-defined environment file types:
@@ -53,6 +66,19 @@
 //	content := env.Local.Generate(registry, "My Application")
 //	os.WriteFile(env.Local.FileName, []byte(content), 0600)
 //
+// # Loading Order
+//
+// LoadChain layers .env files; later files override earlier ones, and variables
+// already set in the process environment override every file:
+//
+//	.env  <  .env.local  <  .env.$APP_ENV  <  real environment
+//
+//	result, err := env.LoadChain(env.DefaultChainFiles...)
+//	fmt.Println(result.Explain("SERVER_PORT")) // SERVER_PORT: from .env.local:3 (overrides .env)
+//
+// Missing files are skipped. Use ResolveChain to inspect provenance without
+// modifying the environment.
+//
 // # Secrets Management
 //
 // Load secrets from files (prefers encrypted .age versions):
@@ -65,9 +91,29 @@
 // Merge secrets into templates:
 //
 //	template := env.Local.Generate(registry, "My App")
-//	merged := env.MergeIntoTemplate(template, secrets)
+//	merged, err := env.MergeIntoTemplate(template, secrets)
 //	os.WriteFile(env.Local.FileName, []byte(merged), 0600)
 //
+// Tools that collect secrets themselves update the encrypted file in place,
+// keeping every other value:
+//
+//	path, err := env.SetSecrets(env.SetSecretsOptions{
+//	    Registry: registry,
+//	    Values:   map[string]string{"GOOGLE_CLIENT_ID": id},
+//	})
+//	values, err := env.ReadSecrets(env.SecretsLocal, env.DefaultAgeKeyPath)
+//
+// # Encrypted Distribution
+//
+// Publish .age bundles from a trusted internal endpoint and pull them on CI or
+// a new machine, decrypting locally with that machine's Age identity:
+//
+//	mux.Handle("/bundles/", http.StripPrefix("/bundles", env.ServeEncrypted(env.ServeEncryptedOptions{
+//	    Token: os.Getenv("ENV_DIST_TOKEN"),
+//	})))
+//
+//	result, err := env.PullRemote("https://config.internal/bundles", ".age/key.txt")
+//
 // # Validation
 //
 // Validate that all required variables are set:
@@ -76,6 +122,44 @@
 //	    log.Fatalf("Missing required variables: %v", err)
 //	}
 //
+// Failures belong to categories that survive wrapping (ErrMissingRequired,
+// ErrDecryptFailed, ErrDriftDetected, ... in errors.go), and CLIs exit with
+// ExitCode(err) so scripts can branch on the exit status:
+//
+//	if errors.Is(err, env.ErrNoIdentity) {
+//	    fmt.Println("Generate a key first: age-keygen")
+//	}
+//	os.Exit(env.ExitCode(err))
+//
+// Errors name each variable's owner when the registry has ownership rules
+// (CODEOWNERS-style globs or groups, see Owners and ParseOwners):
+//
+//	registry := env.NewRegistry(vars, env.Owners(rules...))
+//	registry.OwnerOf("GOOGLE_CLIENT_ID") // {Owner: "@auth", Contact: "#auth-oncall"}
+//
+// Policies constrain values per environment, in Go or a policy file (see
+// ParsePolicies). VerifyWorkflow checks them against each .env file and the
+// webui against the running process:
+//
+//	// production  DEBUG == false
+//	// production  HTTPS_ENABLED == true unless platform == fly
+//	policies, err := env.ParsePolicies(f)
+//	registry := env.NewRegistry(vars, env.Policies(policies...))
+//	err = registry.ValidatePolicies(env.PolicyContext{Environment: "production"})
+//
+// Validation, export and the /env view read one value per variable. For
+// registries with thousands of variables, CacheEnvironment serves those reads
+// from a snapshot of the environment (RefreshEnvironment after it changes):
+//
+//	registry.CacheEnvironment()
+//	defer registry.UncacheEnvironment()
+//
+// For people at a terminal, FormatValidationTerminal lists only the missing
+// and invalid variables, and FormatEnvListTerminal all of them, aligned by
+// group with summary counts (colored unless NO_COLOR is set):
+//
+//	fmt.Print(registry.FormatEnvListTerminal(env.TerminalOptions{Color: env.ColorEnabled()}))
+//
 // # Deployment Configuration
 //
 // Generate deployment-specific formats:
@@ -141,12 +225,29 @@
 //	    BaseDir:  "./config",
 //	}
 //
+// Template inheritance (shared header and group order, per-environment additions):
+//
+//	base := env.PartialTemplate(env.TemplateOptions{
+//	    Header:     []string{"# Managed by the platform team"},
+//	    GroupOrder: []string{"Server", "Database"},
+//	})
+//	staging := &env.Environment{Name: "staging", FileName: ".env.staging", Extends: base,
+//	    Template: &env.TemplateOptions{Header: []string{"# STAGING"}}}
+//
 // Filter variables:
 //
 //	secrets := registry.GetSecrets()
 //	required := registry.GetRequired()
 //	byGroup := registry.GetByGroup()
 //
+// Runtime registration (safe for concurrent use; readers see immutable snapshots):
+//
+//	registry.Add(env.EnvVar{Name: "PLUGIN_TOKEN", Secret: true, Group: "Plugin"})
+//	registry.Override(env.EnvVar{Name: "LOG_LEVEL", Default: "debug", Group: "Logging"})
+//	unsubscribe := registry.Subscribe(func(c env.RegistryChange) {
+//	    log.Printf("%s %s", c.Kind, c.Var.Name)
+//	})
+//
 // For complete examples and library usage patterns, see:
 //   - LIBRARY_USAGE.md: Comprehensive library documentation
 //   - example/: Working CLI implementation
@@ -157,13 +258,33 @@
 // Main files:
 //   - registry.go: Registry and EnvVar types with accessors
 //   - environment.go: Environment file abstraction
-//   - template.go: Template generation functions
+//   - template.go: Template generation functions (.env, Dockerfile, fly.toml, docker build args, OCI labels, .ko.yaml)
 //   - secrets.go: Secrets loading and encryption
+//   - distribute.go: Encrypted bundle distribution over HTTP (ServeEncrypted, PullRemote)
+//   - cibundle.go: Encrypted CI artifact with restore script (CreateCIBundle, RestoreCIBundle)
 //   - export.go: Deployment format generators (Dockerfile, TOML, YAML)
 //   - sync.go: File section synchronization
+//   - chain.go: Layered .env loading with provenance (LoadChain)
+//   - resolved.go: Effective configuration with value sources (Resolve)
+//   - terminal.go: Colored, column-aligned list and validation output (FormatEnvListTerminal)
+//   - remote.go: Configured-status comparison against a deployed instance (CompareRemote)
+//   - lint.go: Secrets hygiene linter (LintSecrets)
+//   - errors.go: Error categories and CLI exit codes (ExitCode, Classify)
+//   - owners.go: Variable ownership rules (Owners, OwnerOf, ParseOwners)
+//   - policy.go: Per-environment value policies (Policies, CheckPolicies, ParsePolicies)
+//   - wizard.go: Interactive secrets entry straight into .age files (RunSecretsWizard)
+//   - changeset.go: Signed, approvable configuration change bundles (CreateChangeSet, ApplyChangeSet)
+//   - adopt.go: Rebuilding encrypted secrets from a deployed app's secret names (ImportSecrets)
+//   - redact.go: Secret masking for subprocess output (RedactingWriter)
+//   - keychain.go: Shell export scripts reading secrets from the OS keychain
+//   - prompt.go: Active environment status and shell prompt snippet (Status, GeneratePromptSnippet)
+//   - usage.go: Runtime read counters for finding dead configuration
+//   - drift.go: Registry drift detection and PR comment generation
+//   - tasks.go: Taskfile.yml / Makefile target generation (marker-synced)
 //
 // Subpackages:
 //   - workflow/: High-level workflow orchestration functions
+//   - tui/: Interactive terminal UI for browsing, editing and validating values
 //
 // Testing:
 //   - *_test.go: Unit tests for all functions
@@ -0,0 +1,213 @@
+// Package env provides drift detection between a registry and generated files.
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ================================================================
+// Registry Diff
+// ================================================================
+
+// DefaultChange records a non-secret variable whose value in a file differs from the registry default.
+type DefaultChange struct {
+	Name            string
+	RegistryDefault string
+	FileValue       string
+}
+
+// RegistryDiff describes drift between a registry and one .env file.
+type RegistryDiff struct {
+	FilePath        string
+	Missing         []EnvVar        // In the registry but not in the file
+	Extra           []string        // In the file but not in the registry
+	ChangedDefaults []DefaultChange // Non-secret values that differ from the registry default
+}
+
+// HasDrift returns true if the file is out of sync with the registry.
+func (d *RegistryDiff) HasDrift() bool {
+	return len(d.Missing) > 0 || len(d.Extra) > 0 || len(d.ChangedDefaults) > 0
+}
+
+// DiffRegistry compares a .env file against the registry.
+//
+// Commented-out assignments (# KEY=value) count as present, since generated
+// templates comment out unset optional variables. Secret values are never
+// compared or reported.
+//
+// Example:
+//
+//	diff, err := env.DiffRegistry(registry, ".env.production")
+//	if diff.HasDrift() {
+//	    fmt.Println(env.GeneratePRComment(env.DriftReport{Diffs: []*env.RegistryDiff{diff}}))
+//	}
+func DiffRegistry(registry *Registry, filePath string) (*RegistryDiff, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	values := ParseSecretsFile(data)
+	present := make(map[string]bool, len(values))
+	for name := range values {
+		present[name] = true
+	}
+	// Generated templates comment out optional vars as "# KEY=value"
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		if name, _, ok := strings.Cut(trimmed, "="); ok && registry.ByName(strings.TrimSpace(name)) != nil {
+			present[strings.TrimSpace(name)] = true
+		}
+	}
+
+	diff := &RegistryDiff{FilePath: filePath}
+	for _, v := range registry.All() {
+		if !present[v.Name] {
+			diff.Missing = append(diff.Missing, v)
+			continue
+		}
+		value, set := values[v.Name]
+		if set && !v.Secret && v.Default != "" && value != "" && value != v.Default {
+			diff.ChangedDefaults = append(diff.ChangedDefaults, DefaultChange{
+				Name:            v.Name,
+				RegistryDefault: v.Default,
+				FileValue:       value,
+			})
+		}
+	}
+
+	for name := range values {
+		if registry.ByName(name) == nil {
+			diff.Extra = append(diff.Extra, name)
+		}
+	}
+	sort.Strings(diff.Extra)
+
+	return diff, nil
+}
+
+// ================================================================
+// Drift Report (PR comment)
+// ================================================================
+
+// DriftReport aggregates registry diffs and generated sections that need regeneration.
+type DriftReport struct {
+	Title      string          // Comment heading (default: "Environment drift")
+	Diffs      []*RegistryDiff // Per-file registry diffs
+	StaleFiles []string        // Files whose auto-generated section is out of date
+	Command    string          // Command that fixes the drift (e.g., "go run . env sync-registry")
+}
+
+// HasDrift returns true if any file or section is out of sync.
+func (r DriftReport) HasDrift() bool {
+	if len(r.StaleFiles) > 0 {
+		return true
+	}
+	for _, d := range r.Diffs {
+		if d.HasDrift() {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckStaleSections runs each sync in dry-run mode and returns the files whose section differs.
+// Files that can't be read or don't contain the markers are reported as stale too.
+func CheckStaleSections(syncs []SyncOptions) []string {
+	var stale []string
+	for _, opts := range syncs {
+		upToDate, err := SectionUpToDate(opts)
+		if err != nil || !upToDate {
+			stale = append(stale, opts.FilePath)
+		}
+	}
+	return stale
+}
+
+// GeneratePRComment renders a drift report as a GitHub-flavoured Markdown comment.
+// The output is ready to post as-is (see deploy.PostPRComment).
+func GeneratePRComment(report DriftReport) string {
+	title := report.Title
+	if title == "" {
+		title = "Environment drift"
+	}
+
+	var sb strings.Builder
+	if !report.HasDrift() {
+		sb.WriteString(fmt.Sprintf("### ✅ %s\n\n", title))
+		sb.WriteString("All environment files and generated sections match the registry.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("### ⚠️ %s\n\n", title))
+
+	for _, d := range report.Diffs {
+		if !d.HasDrift() {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("#### `%s`\n\n", d.FilePath))
+
+		if len(d.Missing) > 0 {
+			sb.WriteString("**Missing variables**\n\n")
+			sb.WriteString("| Variable | Group | Required | Secret |\n")
+			sb.WriteString("|---|---|---|---|\n")
+			for _, v := range d.Missing {
+				sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n",
+					v.Name, markdownCell(v.Group), yesNo(v.Required), yesNo(v.Secret)))
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(d.ChangedDefaults) > 0 {
+			sb.WriteString("**Changed defaults**\n\n")
+			sb.WriteString("| Variable | Registry default | File value |\n")
+			sb.WriteString("|---|---|---|\n")
+			for _, c := range d.ChangedDefaults {
+				sb.WriteString(fmt.Sprintf("| `%s` | `%s` | `%s` |\n",
+					c.Name, markdownCell(c.RegistryDefault), markdownCell(c.FileValue)))
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(d.Extra) > 0 {
+			sb.WriteString("**Not in registry**\n\n")
+			for _, name := range d.Extra {
+				sb.WriteString(fmt.Sprintf("- `%s`\n", name))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(report.StaleFiles) > 0 {
+		sb.WriteString("#### Files needing regeneration\n\n")
+		for _, f := range report.StaleFiles {
+			sb.WriteString(fmt.Sprintf("- [ ] `%s`\n", f))
+		}
+		sb.WriteString("\n")
+	}
+
+	if report.Command != "" {
+		sb.WriteString(fmt.Sprintf("💡 Fix with:\n\n```sh\n%s\n```\n", report.Command))
+	}
+
+	return sb.String()
+}
+
+// markdownCell escapes pipes so values don't break table columns
+func markdownCell(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// yesNo renders a bool for Markdown tables
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,116 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func driftTestRegistry() *Registry {
+	return NewRegistry([]EnvVar{
+		{Name: "PORT", Default: "8080", Group: "Server"},
+		{Name: "LOG_LEVEL", Default: "info", Group: "Server"},
+		{Name: "API_KEY", Secret: true, Required: true, Group: "API"},
+		{Name: "DEBUG", Group: "Server"},
+	})
+}
+
+func TestDiffRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env.production")
+	content := "PORT=9090\n# DEBUG=\nAPI_KEY=abc\nLEGACY_FLAG=1\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DiffRegistry(driftTestRegistry(), path)
+	if err != nil {
+		t.Fatalf("DiffRegistry failed: %v", err)
+	}
+
+	if len(diff.Missing) != 1 || diff.Missing[0].Name != "LOG_LEVEL" {
+		t.Errorf("Missing = %v, want [LOG_LEVEL]", diff.Missing)
+	}
+	if len(diff.Extra) != 1 || diff.Extra[0] != "LEGACY_FLAG" {
+		t.Errorf("Extra = %v, want [LEGACY_FLAG]", diff.Extra)
+	}
+	if len(diff.ChangedDefaults) != 1 || diff.ChangedDefaults[0].FileValue != "9090" {
+		t.Errorf("ChangedDefaults = %v, want PORT=9090", diff.ChangedDefaults)
+	}
+	if !diff.HasDrift() {
+		t.Error("expected drift")
+	}
+}
+
+func TestDiffRegistry_InSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "PORT=8080\nLOG_LEVEL=info\nAPI_KEY=abc\n# DEBUG=\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DiffRegistry(driftTestRegistry(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.HasDrift() {
+		t.Errorf("unexpected drift: %+v", diff)
+	}
+}
+
+func TestCheckStaleSections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	section := "# START\nENV PORT=8080\n# END"
+	if err := os.WriteFile(path, []byte("FROM scratch\n"+section+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := SyncOptions{FilePath: path, StartMarker: "# START", EndMarker: "# END", Content: section}
+	if stale := CheckStaleSections([]SyncOptions{fresh}); len(stale) != 0 {
+		t.Errorf("expected no stale files, got %v", stale)
+	}
+
+	changed := fresh
+	changed.Content = "# START\nENV PORT=9090\n# END"
+	if stale := CheckStaleSections([]SyncOptions{changed}); len(stale) != 1 {
+		t.Errorf("expected 1 stale file, got %v", stale)
+	}
+
+	// Content must be untouched by the check
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "PORT=8080") {
+		t.Error("CheckStaleSections modified the file")
+	}
+}
+
+func TestGeneratePRComment(t *testing.T) {
+	report := DriftReport{
+		Diffs: []*RegistryDiff{{
+			FilePath:        ".env.production",
+			Missing:         []EnvVar{{Name: "LOG_LEVEL", Group: "Server"}},
+			Extra:           []string{"LEGACY_FLAG"},
+			ChangedDefaults: []DefaultChange{{Name: "PORT", RegistryDefault: "8080", FileValue: "9090"}},
+		}},
+		StaleFiles: []string{"fly.toml"},
+		Command:    "go run . sync-registry",
+	}
+
+	comment := GeneratePRComment(report)
+	for _, want := range []string{
+		"Environment drift",
+		"| `LOG_LEVEL` | Server | no | no |",
+		"| `PORT` | `8080` | `9090` |",
+		"- `LEGACY_FLAG`",
+		"- [ ] `fly.toml`",
+		"go run . sync-registry",
+	} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("comment missing %q\n%s", want, comment)
+		}
+	}
+
+	clean := GeneratePRComment(DriftReport{})
+	if !strings.Contains(clean, "✅") {
+		t.Errorf("clean report should be a success message, got %q", clean)
+	}
+}
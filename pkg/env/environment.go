@@ -16,15 +16,53 @@ const (
 // Environment represents a target environment type (local, production, secrets, etc.)
 // with smart defaults that require zero configuration
 type Environment struct {
-	Name     string // Environment name: "local", "production", "secrets", etc.
-	FileName string // Target filename: ".env.local", ".env.production", etc.
-	BaseDir  string // Base directory for files (defaults to "." for backward compatibility)
+	Name     string           // Environment name: "local", "production", "secrets", etc.
+	FileName string           // Target filename: ".env.local", ".env.production", etc.
+	BaseDir  string           // Base directory for files (defaults to "." for backward compatibility)
+	Extends  *Environment     // Environment whose template this one builds on (optional)
+	Template *TemplateOptions // Per-environment additions, applied with TemplateOptions.Extend (optional)
 }
 
 // Generate generates an environment file template with smart defaults based on environment type
 // The appName is used in headers to identify the application
 func (e *Environment) Generate(registry *Registry, appName string) string {
-	return registry.GenerateTemplate(e.defaultOptions(appName))
+	return registry.GenerateTemplate(e.TemplateOptions(appName))
+}
+
+// TemplateOptions resolves the template for this environment: the Extends chain
+// (or the smart defaults for the root), with each Template applied on top.
+//
+// Example (staging and production share a header and group order):
+//
+//	base := env.PartialTemplate(env.TemplateOptions{
+//	    Header:     []string{"# Acme Corp - managed by platform team"},
+//	    GroupOrder: []string{"Server", "Database"},
+//	})
+//	staging := &env.Environment{Name: "staging", FileName: ".env.staging", Extends: base,
+//	    Template: &env.TemplateOptions{Header: []string{"# STAGING"}}}
+func (e *Environment) TemplateOptions(appName string) TemplateOptions {
+	// Walk up to the root, guarding against cycles
+	var chain []*Environment
+	seen := make(map[*Environment]bool)
+	for cur := e; cur != nil && !seen[cur]; cur = cur.Extends {
+		seen[cur] = true
+		chain = append(chain, cur)
+	}
+
+	opts := chain[len(chain)-1].defaultOptions(appName)
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].Template != nil {
+			opts = opts.Extend(*chain[i].Template)
+		}
+	}
+	return opts
+}
+
+// PartialTemplate returns a nameless Environment carrying only opts, for use as
+// Extends by environments that share headers, group ordering or overrides.
+// It has no file of its own.
+func PartialTemplate(opts TemplateOptions) *Environment {
+	return &Environment{Template: &opts}
 }
 
 // EncryptedFileName returns the encrypted version of the environment filename
@@ -59,6 +97,8 @@ func (e *Environment) WithBaseDir(dir string) *Environment {
 		Name:     e.Name,
 		FileName: e.FileName,
 		BaseDir:  dir,
+		Extends:  e.Extends,
+		Template: e.Template,
 	}
 }
 
@@ -71,6 +111,10 @@ func (e *Environment) Exists() bool {
 // defaultOptions returns smart default TemplateOptions based on environment type
 func (e *Environment) defaultOptions(appName string) TemplateOptions {
 	switch e.Name {
+	case "":
+		// Partial template: everything comes from Template
+		return TemplateOptions{}
+
 	case "local":
 		return TemplateOptions{
 			Header: e.defaultHeader(appName, "LOCAL DEVELOPMENT",
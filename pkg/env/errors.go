@@ -0,0 +1,106 @@
+package env
+
+import "errors"
+
+// ================================================================
+// Error Taxonomy and Exit Codes
+// ================================================================
+// Failures a script may want to act on belong to a category: the error
+// wraps one of the sentinels below, so errors.Is(err, env.ErrMissingRequired)
+// holds however much context callers add (and across errors.Join, as in
+// workflow results). Messages are unchanged by the category.
+//
+// CLIs exit with ExitCode(err), so scripts branch on the exit status instead
+// of grepping messages:
+//
+//	 0  ExitOK                success
+//	 1  ExitFailure           any other error
+//	 2  ExitUsage             unknown command or invalid flags
+//	 3  ExitMissingRequired   ErrMissingRequired
+//	 4  ExitInvalidValue      ErrInvalidValue
+//	 5  ExitPolicyViolation   ErrPolicyViolation
+//	 6  ExitDriftDetected     ErrDriftDetected
+//	 7  ExitNotEncrypted      ErrNotEncrypted
+//	 8  ExitNoIdentity        ErrNoIdentity
+//	 9  ExitDecryptFailed     ErrDecryptFailed
+//	10  ExitSecretsNotFound   ErrSecretsNotFound
+//	11  ExitChangeSetRejected ErrChangeSetRejected
+//
+// An error in several categories exits with the lowest code.
+
+// Error categories
+var (
+	ErrMissingRequired   = errors.New("missing required environment variables") // ValidateRequired
+	ErrInvalidValue      = errors.New("invalid environment variable values")    // ValidateTypes
+	ErrPolicyViolation   = errors.New("policy violations")                      // ValidatePolicies
+	ErrDriftDetected     = errors.New("drift detected")                         // A file is out of sync with the registry
+	ErrNotEncrypted      = errors.New("not encrypted")                          // A plaintext file has no up-to-date .age version
+	ErrNoIdentity        = errors.New("no Age identity")                        // No usable Age key to decrypt or encrypt with
+	ErrDecryptFailed     = errors.New("failed to decrypt")                      // Wrong key or corrupt .age data
+	ErrSecretsNotFound   = errors.New("secrets file not found")                 // No secrets file, plain or encrypted
+	ErrChangeSetRejected = errors.New("change set rejected")                    // Bad or untrusted signature, too few approvals
+)
+
+// CLI exit codes (see ExitCode)
+const (
+	ExitOK                = 0
+	ExitFailure           = 1
+	ExitUsage             = 2
+	ExitMissingRequired   = 3
+	ExitInvalidValue      = 4
+	ExitPolicyViolation   = 5
+	ExitDriftDetected     = 6
+	ExitNotEncrypted      = 7
+	ExitNoIdentity        = 8
+	ExitDecryptFailed     = 9
+	ExitSecretsNotFound   = 10
+	ExitChangeSetRejected = 11
+)
+
+// exitCodes maps categories to exit codes, lowest code first
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{ErrMissingRequired, ExitMissingRequired},
+	{ErrInvalidValue, ExitInvalidValue},
+	{ErrPolicyViolation, ExitPolicyViolation},
+	{ErrDriftDetected, ExitDriftDetected},
+	{ErrNotEncrypted, ExitNotEncrypted},
+	{ErrNoIdentity, ExitNoIdentity},
+	{ErrDecryptFailed, ExitDecryptFailed},
+	{ErrSecretsNotFound, ExitSecretsNotFound},
+	{ErrChangeSetRejected, ExitChangeSetRejected},
+}
+
+// ExitCode returns the exit code for err: ExitOK for nil, the code of its
+// category, or ExitFailure
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	for _, c := range exitCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ExitFailure
+}
+
+// Classify puts err in category (one of the Err* sentinels) without changing
+// its message. It returns nil for a nil err.
+func Classify(category, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classified{category: category, err: err}
+}
+
+// classified is an error with a category
+type classified struct {
+	category error
+	err      error
+}
+
+func (e *classified) Error() string   { return e.err.Error() }
+func (e *classified) Unwrap() []error { return []error{e.err, e.category} }
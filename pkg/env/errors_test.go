@@ -0,0 +1,90 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{errors.New("boom"), ExitFailure},
+		{fmt.Errorf("loading: %w", ErrDecryptFailed), ExitDecryptFailed},
+		{Classify(ErrChangeSetRejected, errors.New("bad signature")), ExitChangeSetRejected},
+		{errors.Join(Classify(ErrDriftDetected, errors.New("stale")), ErrMissingRequired), ExitMissingRequired},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	if Classify(ErrDriftDetected, nil) != nil {
+		t.Error("Classify(nil) should be nil")
+	}
+
+	cause := os.ErrNotExist
+	err := Classify(ErrNoIdentity, fmt.Errorf("failed to read identity: %w", cause))
+	if err.Error() != "failed to read identity: file does not exist" {
+		t.Errorf("message changed: %q", err)
+	}
+	if !errors.Is(err, ErrNoIdentity) || !errors.Is(err, cause) {
+		t.Error("expected both the category and the cause to match")
+	}
+}
+
+func TestValidation_ErrorCategories(t *testing.T) {
+	t.Setenv("ERRTEST_PORT", "eighty")
+	registry := NewRegistry([]EnvVar{
+		{Name: "ERRTEST_REQUIRED", Required: true},
+		{Name: "ERRTEST_PORT", Default: "8080"},
+	}, Policies(Policy{Environments: []string{"production"}, Variable: "ERRTEST_PORT", Op: PolicyEquals, Value: "443"}))
+
+	err := registry.ValidateRequired()
+	if !errors.Is(err, ErrMissingRequired) || !strings.HasPrefix(err.Error(), "missing required environment variables: ERRTEST_REQUIRED") {
+		t.Errorf("ValidateRequired: %v", err)
+	}
+	if err := registry.ValidateTypes(); !errors.Is(err, ErrInvalidValue) || ExitCode(err) != ExitInvalidValue {
+		t.Errorf("ValidateTypes: %v", err)
+	}
+	if err := registry.ValidatePolicies(PolicyContext{Environment: "production"}); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("ValidatePolicies: %v", err)
+	}
+}
+
+func TestSecrets_ErrorCategories(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	_, err := LoadSecrets(SecretsSource{FilePath: filepath.Join(dir, ".env.secrets"), PreferEncrypted: true})
+	if !errors.Is(err, ErrSecretsNotFound) {
+		t.Errorf("LoadSecrets of a missing file: %v", err)
+	}
+
+	t.Setenv("AGE_IDENTITY", filepath.Join(dir, "missing.txt"))
+	if _, err := DecryptAgeFile([]byte("x")); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("DecryptAgeFile without identities: %v", err)
+	}
+
+	// Encrypted to another key
+	mine, _ := age.GenerateX25519Identity()
+	other, _ := age.GenerateX25519Identity()
+	keyPath := filepath.Join(dir, "key.txt")
+	os.WriteFile(keyPath, []byte(mine.String()+"\n"), 0600)
+	t.Setenv("AGE_IDENTITY", keyPath)
+	_, err = DecryptAgeFile(encryptTo(t, other, "SECRET=1"))
+	if !errors.Is(err, ErrDecryptFailed) || ExitCode(err) != ExitDecryptFailed {
+		t.Errorf("DecryptAgeFile with the wrong key: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ package main
 // For high-level orchestrated workflows, see workflow.go
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/joeblew999/wellknown/pkg/env"
 	"github.com/joeblew999/wellknown/pkg/env/deploy"
 	"github.com/joeblew999/wellknown/pkg/env/scaffold"
+	"github.com/joeblew999/wellknown/pkg/env/workflow"
 )
 
 // ================================================================
@@ -27,7 +29,7 @@ func cmdValidate() {
 	if err := AppRegistry.ValidateRequired(); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Validation failed: %v\n", err)
 		fmt.Fprintln(os.Stderr, "\n💡 Tip: Set missing variables or use 'go run . list' to see all variables")
-		os.Exit(1)
+		os.Exit(env.ExitCode(err))
 	}
 	fmt.Println("✅ All required environment variables are set!")
 }
@@ -132,12 +134,12 @@ func cmdSyncSecrets() {
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to sync secrets: %v\n", err)
-		if strings.Contains(err.Error(), "no secrets file found") {
+		if errors.Is(err, env.ErrSecretsNotFound) {
 			fmt.Fprintln(os.Stderr, "\n💡 Create one of these files:")
 			fmt.Fprintf(os.Stderr, "   - %s (recommended for local development)\n", env.SecretsLocal.FileName)
 			fmt.Fprintf(os.Stderr, "   - %s (encrypted version)\n", env.SecretsLocal.EncryptedFileName())
 		}
-		os.Exit(1)
+		os.Exit(env.ExitCode(err))
 	}
 
 	// Display fallback warning if used
@@ -161,12 +163,12 @@ func cmdSyncSecretsProd() {
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to sync secrets: %v\n", err)
-		if strings.Contains(err.Error(), "no secrets file found") {
+		if errors.Is(err, env.ErrSecretsNotFound) {
 			fmt.Fprintln(os.Stderr, "\n💡 Create one of these files:")
 			fmt.Fprintf(os.Stderr, "   - %s (recommended for production)\n", env.SecretsProduction.FileName)
 			fmt.Fprintf(os.Stderr, "   - %s (encrypted version)\n", env.SecretsProduction.EncryptedFileName())
 		}
-		os.Exit(1)
+		os.Exit(env.ExitCode(err))
 	}
 
 	// Display fallback warning if used
@@ -339,12 +341,12 @@ func cmdAgeEncrypt() {
 		fmt.Fprintf(os.Stderr, "❌ Failed to encrypt: %v\n", err)
 
 		// Provide helpful guidance
-		if strings.Contains(err.Error(), "no Age key") {
+		if errors.Is(err, env.ErrNoIdentity) {
 			fmt.Fprintln(os.Stderr, "\n💡 Generate a key first:")
 			fmt.Fprintln(os.Stderr, "   go run . age-keygen")
 		}
 
-		os.Exit(1)
+		os.Exit(env.ExitCode(err))
 	}
 
 	// Display results
@@ -385,11 +387,11 @@ func cmdAgeDecrypt() {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to decrypt: %v\n", err)
 		// Provide helpful guidance
-		if strings.Contains(err.Error(), "no Age identities") {
+		if errors.Is(err, env.ErrNoIdentity) {
 			fmt.Fprintln(os.Stderr, "\n💡 Generate a key first:")
 			fmt.Fprintln(os.Stderr, "   go run . age-keygen")
 		}
-		os.Exit(1)
+		os.Exit(env.ExitCode(err))
 	}
 
 	// Display any non-fatal errors
@@ -467,9 +469,11 @@ func cmdExport() {
 		exportFormat = env.FormatSystemd
 	case "k8s", "kubernetes":
 		exportFormat = env.FormatK8s
+	case "shell", "sh":
+		exportFormat = env.FormatShell
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown format: %s\n", format)
-		fmt.Fprintln(os.Stderr, "Available formats: simple, docker, systemd, k8s")
+		fmt.Fprintln(os.Stderr, "Available formats: simple, docker, systemd, k8s, shell")
 		os.Exit(1)
 	}
 
@@ -594,13 +598,33 @@ func cmdFlyVolume() {
 	fmt.Println("💡 Next: go run . fly-secrets-import")
 }
 
-func cmdFlySecretsImport() {
-	appName, _, err := deploy.ReadFlyTomlConfig()
+// FlyAppFilters selects registry variables per Fly target in a monorepo
+// (fly.api.toml -> "api"). Targets not listed get every variable.
+var FlyAppFilters = map[string]deploy.FlyApp{}
+
+// flyTarget resolves the Fly app from the optional target argument
+// (e.g., "fly-deploy api" uses fly.api.toml; default: fly.toml)
+func flyTarget() *deploy.FlyApp {
+	target := deploy.DefaultFlyTarget
+	if len(os.Args) > 2 {
+		target = os.Args[2]
+	}
+
+	app, err := deploy.FindFlyApp(".", target)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed to read fly.toml: %v\n", err)
+		fmt.Fprintf(os.Stderr, "❌ Failed to read fly config: %v\n", err)
 		fmt.Fprintln(os.Stderr, "💡 Run 'go run . fly-launch' first")
 		os.Exit(1)
 	}
+	if f, ok := FlyAppFilters[app.Target]; ok {
+		app.Groups, app.Vars = f.Groups, f.Vars
+	}
+	return app
+}
+
+func cmdFlySecretsImport() {
+	app := flyTarget()
+	appName := app.AppName
 
 	// Prefer .env.production, fallback to .env.local
 	var envFile string
@@ -620,7 +644,7 @@ func cmdFlySecretsImport() {
 	fmt.Printf("   Source: %s (or %s.age)\n", envFile, envFile)
 	fmt.Println("   Registry: Only variables marked as Secret=true")
 
-	if err := deploy.SecretsImport(AppRegistry, envFile, appName); err != nil {
+	if err := deploy.SecretsImportApp(AppRegistry, envFile, *app); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to import secrets: %v\n", err)
 		fmt.Fprintln(os.Stderr, "\n💡 Make sure:")
 		fmt.Fprintf(os.Stderr, "   - %s exists with secret values\n", envFile)
@@ -647,22 +671,35 @@ func cmdFlySecretsList() {
 }
 
 func cmdFlyDeploy() {
-	appName, _, err := deploy.ReadFlyTomlConfig()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed to read fly.toml: %v\n", err)
-		fmt.Fprintln(os.Stderr, "💡 Run 'go run . fly-launch' first")
-		os.Exit(1)
-	}
+	app := flyTarget()
+	appName := app.AppName
 
-	fmt.Printf("🚀 Deploying to Fly.io: %s\n", appName)
+	fmt.Printf("🚀 Deploying to Fly.io: %s (%s)\n", appName, app.ConfigPath)
 
-	if err := deploy.Deploy(appName); err != nil {
+	if err := deploy.DeployApp(*app); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Deployment failed: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Println("✅ Deployed!")
-	fmt.Printf("🌐 App URL: https://%s.fly.dev\n", appName)
+	appURL := fmt.Sprintf("https://%s.fly.dev", appName)
+	fmt.Printf("🌐 App URL: %s\n", appURL)
+
+	fmt.Println("🩺 Verifying deployment...")
+	result, err := deploy.VerifyDeploymentWithOptions(deploy.VerifyOptions{
+		URL:             appURL,
+		Registry:        app.FilterRegistry(AppRegistry),
+		ExpectedVersion: deploy.LocalVersion(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Verification failed: %v\n", err)
+		fmt.Fprintln(os.Stderr, "💡 Import missing secrets: go run . fly-secrets-import")
+		os.Exit(1)
+	}
+	for _, warn := range result.Warnings {
+		fmt.Printf("⚠️  %s\n", warn)
+	}
+	fmt.Printf("✅ Healthy (%s, version %s)\n", result.Environment, result.Version)
 	fmt.Println("💡 Check status: go run . fly-status")
 	fmt.Println("💡 View logs: go run . fly-logs")
 }
@@ -752,48 +789,51 @@ func cmdInitProject(appName, packageName string, example, force bool) error {
 		templateType = "full"
 	}
 
-	// Build generator options
-	opts := scaffold.GeneratorOptions{
-		Dir:         ".", // Current directory (already changed by --dir flag if specified)
-		AppName:     appName,
-		PackageName: packageName,
-		Template:    templateType,
-		Force:       force,
-		ImportPath:  "github.com/joeblew999/wellknown",
+	// The compiled AppRegistry only matches registry.go if we keep the existing one;
+	// a freshly generated registry.go must be built before it can be synced.
+	var registry *env.Registry
+	if scaffold.RegistryExists(".") && !force {
+		registry = AppRegistry
 	}
 
-	// Check if registry already exists
-	if scaffold.RegistryExists(opts.Dir) && !opts.Force {
-		fmt.Fprintf(os.Stderr, "❌ registry.go already exists\n")
-		fmt.Fprintf(os.Stderr, "   Use --force to overwrite\n")
-		return fmt.Errorf("registry.go already exists")
-	}
-
-	// Generate registry
-	fmt.Println("📝 Step 1/2: Generating registry.go")
-	if err := scaffold.GenerateRegistry(opts); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed to generate registry: %v\n", err)
+	_, err := workflow.InitWorkflow(workflow.InitOptions{
+		Generator: scaffold.GeneratorOptions{
+			Dir:         ".", // Current directory (already changed by --dir flag if specified)
+			AppName:     appName,
+			PackageName: packageName,
+			Template:    templateType,
+			Force:       force,
+			ImportPath:  "github.com/joeblew999/wellknown",
+		},
+		Registry: registry,
+		Sync: workflow.RegistrySyncOptions{
+			AppName:            "Sample Application",
+			DeploymentConfigs:  exampleDeploymentConfigs(),
+			CreateSecretsFiles: true,
+		},
+		OutputWriter: os.Stdout,
+		OutputFormat: outputFormat,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to initialize project: %v\n", err)
 		return err
 	}
-	fmt.Printf("   ✅ Created registry.go (%s template)\n", templateType)
-	fmt.Println()
-
-	// Step 2: Run sync-registry to generate all files
-	fmt.Println("📝 Step 2/2: Running sync-registry")
 	fmt.Println()
 
-	// Note: We can't call cmdSyncRegistry() here because it expects AppRegistry to exist
-	// The user needs to build/run with the new registry.go first
-
 	fmt.Println("✅ Project initialized successfully!")
 	fmt.Println()
 	fmt.Println("📝 NEXT STEPS:")
-	fmt.Println("   1. Review and customize registry.go")
-	fmt.Println("   2. Run: go run . sync-registry")
-	fmt.Println("   3. Edit .env.secrets.local and .env.secrets.production")
-	fmt.Println("   4. Run: go run . sync-environments")
-	fmt.Println("   5. Run: go run . finalize")
+	step := 1
+	if registry == nil {
+		fmt.Printf("   %d. Review and customize registry.go\n", step)
+		fmt.Printf("   %d. Run: go run . sync-registry\n", step+1)
+		step += 2
+	}
+	fmt.Printf("   %d. Edit %s and %s\n", step, env.SecretsLocal.FileName, env.SecretsProduction.FileName)
+	fmt.Printf("   %d. Run: go run . sync-environments\n", step+1)
+	fmt.Printf("   %d. Run: go run . finalize\n", step+2)
 	fmt.Println()
+	fmt.Printf("⚠️  NEVER commit %s - store it in your password manager\n", env.DefaultAgeKeyPath)
 	fmt.Println("💡 TIP: See WORKFLOW.md for the complete workflow guide")
 
 	return nil
@@ -8,6 +8,9 @@ require github.com/joeblew999/wellknown v0.0.0
 
 require (
 	filippo.io/age v1.2.1 // indirect
+	github.com/fatih/color v1.18.0 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
 	golang.org/x/crypto v0.43.0 // indirect
 	golang.org/x/sys v0.37.0 // indirect
 )
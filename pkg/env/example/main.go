@@ -11,15 +11,23 @@ import (
 	"flag"
 	"fmt"
 	"os"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/env/deploy"
+	"github.com/joeblew999/wellknown/pkg/env/workflow"
 )
 
 const appName = "env-demo"
 const appUsage = "Environment management demo with HTTP server"
 
+// outputFormat is how workflow progress is rendered (--output / $ENV_OUTPUT)
+var outputFormat workflow.OutputFormat
+
 func main() {
 	// Parse global flags
 	workDir := flag.String("dir", "", "Change to `DIR` before running command")
 	flag.StringVar(workDir, "C", "", "Change to `DIR` before running command (shorthand)")
+	output := flag.String("output", os.Getenv("ENV_OUTPUT"), "Workflow output `FORMAT`: text, plain, verbose, quiet, json")
 
 	// Custom usage function
 	flag.Usage = printUsage
@@ -34,6 +42,13 @@ func main() {
 	}
 	command := args[0]
 
+	format, err := workflow.ParseOutputFormat(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(env.ExitUsage)
+	}
+	outputFormat = format
+
 	// Before hook: change directory if --dir specified
 	if *workDir != "" {
 		if err := os.Chdir(*workDir); err != nil {
@@ -52,23 +67,32 @@ func main() {
 		}
 	}
 
+	// Mask registry secrets in flyctl output
+	deploy.RedactRegistry = AppRegistry
+
 	// Command routing
 	switch command {
 	// HTTP Server
 	case "serve":
-		cmdServe()
+		cmdServe(args[1:])
 	case "health":
 		cmdHealth()
 	case "killport":
 		cmdKillPort()
 
 	// Workflow Automation (from workflow.go)
+	case "init":
+		cmdInit(args[1:])
 	case "sync-registry":
 		cmdSyncRegistry()
 	case "sync-environments":
 		cmdSyncEnvironments()
 	case "finalize":
 		cmdFinalize()
+	case "verify":
+		cmdVerify()
+	case "review-app":
+		cmdReviewApp()
 	case "ko-build":
 		cmdKoBuild()
 
@@ -79,7 +103,7 @@ func main() {
 	default:
 		fmt.Fprintf(os.Stderr, "Error: unknown command: %s\n", command)
 		fmt.Fprintf(os.Stderr, "Run '%s help' for usage.\n", appName)
-		os.Exit(1)
+		os.Exit(env.ExitUsage)
 	}
 }
 
@@ -91,24 +115,29 @@ func printUsage() {
 
 	fmt.Printf("GLOBAL OPTIONS:\n")
 	fmt.Printf("  --dir DIR, -C DIR  Change to DIR before running command [$ENV_WORK_DIR]\n")
+	fmt.Printf("  --output FORMAT    Workflow output: text, plain, verbose, quiet, json [$ENV_OUTPUT]\n")
 	fmt.Printf("  --help, -h         Show help\n\n")
 
 	fmt.Printf("COMMANDS:\n\n")
 
 	// HTTP Server
 	fmt.Printf("  HTTP Server:\n")
-	fmt.Printf("    serve          Start HTTP server on $SERVER_PORT (default: 8080)\n")
+	fmt.Printf("    serve          Start HTTP server on $SERVER_PORT (default: 8080; --https for TLS)\n")
 	fmt.Printf("    health         Perform CLI health check\n")
 	fmt.Printf("    killport       Kill any process using $SERVER_PORT\n\n")
 
 	// Workflow Automation
 	fmt.Printf("  Workflow Automation:\n")
+	fmt.Printf("    init               Scaffold registry.go, generate age key, install git hooks\n")
 	fmt.Printf("    sync-registry      Sync deployment configs and environment templates\n")
 	fmt.Printf("    sync-environments  Merge secrets into environments and validate\n")
 	fmt.Printf("    finalize           Encrypt files and prepare for deployment\n")
+	fmt.Printf("    verify             Check configs, drift and encryption without writing (CI)\n")
+	fmt.Printf("    review-app         Deploy/destroy the Fly.io review app of a pull request (CI)\n")
 	fmt.Printf("    ko-build           Build with ko (fast 12MB Docker image)\n\n")
 
 	fmt.Printf("WORKFLOW:\n")
+	fmt.Printf("  0. Run: %s init (once per project)\n", appName)
 	fmt.Printf("  1. Edit registry.go to define your environment variables\n")
 	fmt.Printf("  2. Run: %s sync-registry\n", appName)
 	fmt.Printf("  3. Edit .env.secrets.local and .env.secrets.production with actual values\n")
@@ -124,5 +153,10 @@ func printUsage() {
 	fmt.Printf("    GET /feature-demo   Feature flag demonstration\n")
 	fmt.Printf("    GET /database       Database connection status (JSON)\n\n")
 
+	fmt.Printf("EXIT CODES:\n")
+	fmt.Printf("  0 ok, 1 other failure, 2 usage, 3 missing required, 4 invalid value,\n")
+	fmt.Printf("  5 policy violation, 6 drift, 7 not encrypted, 8 no Age identity,\n")
+	fmt.Printf("  9 decryption failed, 10 secrets file not found, 11 change set rejected\n\n")
+
 	fmt.Printf("See WORKFLOW.md for detailed usage guide.\n")
 }
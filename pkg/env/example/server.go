@@ -3,22 +3,30 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/joeblew999/wellknown/pkg/certs"
 	"github.com/joeblew999/wellknown/pkg/env"
 	"github.com/joeblew999/wellknown/pkg/env/webui"
+	"github.com/joeblew999/wellknown/pkg/health"
+	"github.com/joeblew999/wellknown/pkg/netutil"
 )
 
-// Server runs the HTTP server demonstrating environment variable usage
-func runServer() error {
+// Server runs the HTTP server demonstrating environment variable usage.
+// With https set it serves TLS using certificates from the local CA (pkg/certs).
+func runServer(https bool) error {
+	// Record which registry variables are read (see /env/usage)
+	env.EnableUsageTracking()
+
 	// Get port from environment (uses registry default if not set)
 	port := getRegistryDefault("SERVER_PORT")
 
@@ -28,8 +36,15 @@ func runServer() error {
 	// Setup routes
 	mux := http.NewServeMux()
 
-	// Register webui routes for env management
-	webuiHandler := webui.NewHandler(AppRegistry)
+	// Register webui routes for env management (/health reports the checks below)
+	checker := health.New("env-example")
+	checker.RegisterOptional("database_url", func(ctx context.Context) error {
+		if url := os.Getenv("DATABASE_URL"); url != "" && !strings.Contains(url, "://") {
+			return fmt.Errorf("DATABASE_URL is not a URL")
+		}
+		return nil
+	})
+	webuiHandler := webui.NewHandler(AppRegistry).WithHealth(checker)
 	webuiHandler.RegisterRoutes(mux)
 
 	// App-specific routes
@@ -37,6 +52,13 @@ func runServer() error {
 	mux.HandleFunc("/feature-demo", handleFeatureDemo)
 	mux.HandleFunc("/database", handleDatabase)
 
+	// Fail fast with the owning PIDs instead of a bare "address already in use"
+	if p, err := strconv.Atoi(port); err == nil {
+		if err := netutil.CheckPortFree(p); err != nil {
+			return fmt.Errorf("%w (run 'killport' to free it)", err)
+		}
+	}
+
 	// Create server
 	server := &http.Server{
 		Addr:         ":" + port,
@@ -46,19 +68,35 @@ func runServer() error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	scheme := "http"
+	certFile, keyFile := certs.DefaultFiles()
+	if https {
+		if err := certs.Ensure(certFile, keyFile); err != nil {
+			return fmt.Errorf("failed to generate TLS certificates: %w", err)
+		}
+		scheme = "https"
+	}
+
 	// Start server in goroutine
 	go func() {
-		baseURL := fmt.Sprintf("http://localhost:%s", port)
+		baseURL := fmt.Sprintf("%s://localhost:%s", scheme, port)
 		log.Printf("🚀 Server starting at %s (log level: %s)\n", baseURL, logLevel)
 		log.Printf("📍 Endpoints:\n")
 		log.Printf("   GET %s/          - Homepage\n", baseURL)
 		log.Printf("   GET %s/env       - Environment variables (webui)\n", baseURL)
 		log.Printf("   GET %s/health    - Health check (webui)\n", baseURL)
+		log.Printf("   GET %s/env/usage - Variable read counts (webui)\n", baseURL)
 		log.Printf("   GET %s/feature-demo - Feature flag demo\n", baseURL)
 		log.Printf("   GET %s/database  - Database status\n", baseURL)
 		log.Println()
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if https {
+			err = server.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("❌ Server failed: %v\n", err)
 		}
 	}()
@@ -286,7 +324,11 @@ func getRegistryDefault(key string) string {
 }
 
 // cmdServe starts the HTTP server
-func cmdServe() {
+func cmdServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	https := fs.Bool("https", false, "Serve HTTPS with locally trusted certificates in .data/certs")
+	fs.Parse(args)
+
 	// Load environment variables from .env files if they exist
 	if env.Local.Exists() {
 		if err := loadEnvFile(env.Local.FullPath()); err != nil {
@@ -298,7 +340,7 @@ func cmdServe() {
 		}
 	}
 
-	if err := runServer(); err != nil {
+	if err := runServer(*https); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Server error: %v\n", err)
 		os.Exit(1)
 	}
@@ -324,41 +366,42 @@ func cmdHealth() {
 		os.Exit(1)
 	}
 
-	var result map[string]interface{}
+	var result health.Report
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Invalid health response: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Println("✅ Health check passed")
-	fmt.Printf("   Status: %v\n", result["status"])
-	fmt.Printf("   Environment: %v\n", result["environment"])
+	fmt.Printf("   Status: %v\n", result.Status)
+	fmt.Printf("   Environment: %v\n", result.Environment)
+	for name, check := range result.Checks {
+		fmt.Printf("   %s: %s %s\n", name, check.Status, check.Error)
+	}
 }
 
 // cmdKillPort kills any process using the configured SERVER_PORT
 func cmdKillPort() {
-	port := getRegistryDefault("SERVER_PORT")
-
-	fmt.Printf("🔍 Checking port %s...\n", port)
+	port, err := strconv.Atoi(getRegistryDefault("SERVER_PORT"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Invalid SERVER_PORT: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Find process using lsof
-	findCmd := fmt.Sprintf("lsof -ti:%s", port)
-	out, err := exec.Command("sh", "-c", findCmd).Output()
+	fmt.Printf("🔍 Checking port %d...\n", port)
 
-	if err != nil || len(out) == 0 {
-		fmt.Printf("✅ No process found using port %s\n", port)
+	killed, err := netutil.KillPort(port)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to free port %d: %v\n", port, err)
+		os.Exit(1)
+	}
+	if len(killed) == 0 {
+		fmt.Printf("✅ No process found using port %d\n", port)
 		return
 	}
 
-	pid := strings.TrimSpace(string(out))
-	fmt.Printf("✅ Found process using port %s: PID %s\n", port, pid)
-	fmt.Printf("🔪 Killing process %s...\n", pid)
-
-	// Kill the process
-	killCmd := fmt.Sprintf("kill -9 %s", pid)
-	exec.Command("sh", "-c", killCmd).Run()
-
-	fmt.Printf("✅ Port %s is now free\n", port)
+	fmt.Printf("🔪 Killed PID %v\n", killed)
+	fmt.Printf("✅ Port %d is now free\n", port)
 }
 
 // loadEnvFile loads environment variables from a file
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
@@ -17,14 +18,9 @@ import (
 // These commands combine multiple steps to simplify common workflows.
 // They clearly separate USER ACTIONS (editing) from SYSTEM ACTIONS (automation).
 
-// cmdSyncRegistry syncs all configs after editing registry.go
-// Phase 1: USER edits registry.go → run this → edits secrets
-func cmdSyncRegistry() {
-	fmt.Println("🔄 Syncing from registry...")
-	fmt.Println()
-
-	// Build deployment configs for this example
-	deploymentConfigs := []workflow.DeploymentConfig{
+// exampleDeploymentConfigs lists the deployment files kept in sync with the registry
+func exampleDeploymentConfigs() []workflow.DeploymentConfig {
+	return []workflow.DeploymentConfig{
 		{
 			FilePath:    "Dockerfile",
 			StartMarker: "# === AUTO-GENERATED ENVIRONMENT (do not edit between markers) ===",
@@ -54,34 +50,74 @@ func cmdSyncRegistry() {
 				return "# === AUTO-GENERATED ENVIRONMENT (do not edit between markers) ===\n" + content + "    # === END AUTO-GENERATED ===", nil
 			},
 		},
+		{
+			FilePath:    ".ko.yaml",
+			StartMarker: "# === AUTO-GENERATED ENVIRONMENT (do not edit between markers) ===",
+			EndMarker:   "# === END AUTO-GENERATED ===",
+			Generator: func(r *env.Registry) (string, error) {
+				content := r.GenerateKoEnv([]string{"CGO_ENABLED=0", "GOWORK=off"})
+				// Add markers back since SyncFileSection replaces them
+				return "# === AUTO-GENERATED ENVIRONMENT (do not edit between markers) ===\n" + content + "    # === END AUTO-GENERATED ===", nil
+			},
+		},
+		workflow.MarkdownReferenceConfig(env.DefaultReferenceFile),
+	}
+}
+
+// cmdInit sets up a new project: registry.go, age key, git hooks, initial sync
+// Phase 0: run once → USER edits registry.go
+func cmdInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	appName := fs.String("app", "My Application", "Application name for generated headers")
+	packageName := fs.String("package", "main", "Go package name for registry.go")
+	example := fs.Bool("example", false, "Use the full example registry template")
+	force := fs.Bool("force", false, "Overwrite an existing registry.go (a backup is kept)")
+	fs.Parse(args)
+
+	if err := cmdInitProject(*appName, *packageName, *example, *force); err != nil {
+		os.Exit(1)
 	}
+}
+
+// cmdSyncRegistry syncs all configs after editing registry.go
+// Phase 1: USER edits registry.go → run this → edits secrets
+func cmdSyncRegistry() {
+	fmt.Println("🔄 Syncing from registry...")
+	fmt.Println()
 
 	// Call workflow function
 	result, err := workflow.SyncRegistryWorkflow(workflow.RegistrySyncOptions{
 		Registry:           AppRegistry,
 		AppName:            "Sample Application",
-		DeploymentConfigs:  deploymentConfigs,
+		DeploymentConfigs:  exampleDeploymentConfigs(),
 		CreateSecretsFiles: true,
 		OutputWriter:       nil, // Use default (discard)
 	})
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to sync registry: %v\n", err)
-		os.Exit(1)
+		os.Exit(env.ExitCode(err))
 	}
 
 	// CLI-specific output formatting
 	fmt.Println("📝 Step 1/5: Syncing deployment configs")
 	for _, file := range result.UpdatedFiles {
-		if strings.Contains(file, "Dockerfile") || strings.Contains(file, "fly.toml") || strings.Contains(file, "docker-compose.yml") {
+		if strings.Contains(file, "Dockerfile") || strings.Contains(file, "fly.toml") || strings.Contains(file, "docker-compose.yml") || strings.Contains(file, ".ko.yaml") {
 			fmt.Printf("   ✅ Synced %s\n", file)
 		}
 	}
 	for _, warn := range result.Warnings {
-		if strings.Contains(warn, "Dockerfile") || strings.Contains(warn, "fly.toml") || strings.Contains(warn, "docker-compose.yml") {
+		if strings.Contains(warn, "Dockerfile") || strings.Contains(warn, "fly.toml") || strings.Contains(warn, "docker-compose.yml") || strings.Contains(warn, ".ko.yaml") {
 			fmt.Printf("   ⚠️  %s\n", warn)
 		}
 	}
+	if created, err := env.SyncTaskfile("Taskfile.yml", env.TasksOptions{CLI: "go run ."}); err != nil {
+		fmt.Printf("   ⚠️  Failed to sync Taskfile.yml: %v\n", err)
+	} else if created {
+		fmt.Println("   ✅ Created Taskfile.yml")
+	} else {
+		fmt.Println("   ✅ Synced Taskfile.yml")
+	}
 	fmt.Println()
 
 	fmt.Println("📝 Step 2/5: Updating .env.local template")
@@ -137,7 +173,7 @@ func cmdSyncEnvironments() {
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to sync environments: %v\n", err)
-		os.Exit(1)
+		os.Exit(env.ExitCode(err))
 	}
 
 	// CLI-specific output formatting
@@ -219,11 +255,12 @@ func cmdFinalize() {
 		EncryptionKeyPath: keyPath,
 		GitAdd:            true,
 		OutputWriter:      nil, // Use default (discard)
+		Registry:          AppRegistry,
 	})
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to finalize: %v\n", err)
-		os.Exit(1)
+		os.Exit(env.ExitCode(err))
 	}
 
 	// CLI-specific output formatting
@@ -258,6 +295,56 @@ func cmdFinalize() {
 	fmt.Printf("   - NEVER commit %s\n", env.DefaultAgeKeyPath)
 }
 
+// cmdVerify checks that everything is in sync without writing files
+// CI: run this on every push - exits non-zero on any failure, with the
+// code of the failure's category (env.ExitCode: 3 missing required, 6 drift, ...)
+func cmdVerify() {
+	// JSON output stays pure JSON lines; the exit code carries pass/fail
+	banner := outputFormat != workflow.OutputJSON && outputFormat != workflow.OutputQuiet
+	if banner {
+		fmt.Println("🔍 Verifying environment setup...")
+		fmt.Println()
+	}
+
+	result, err := workflow.VerifyWorkflow(workflow.VerifyOptions{
+		Registry:          AppRegistry,
+		DeploymentConfigs: exampleDeploymentConfigs(),
+		ValidateRequired:  os.Getenv("CI") != "", // Locally, required values live in .env files
+		OutputWriter:      os.Stdout,
+		OutputFormat:      outputFormat,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to verify: %v\n", err)
+		os.Exit(env.ExitCode(err))
+	}
+
+	if result.HasErrors() {
+		fmt.Fprintf(os.Stderr, "\n❌ Verification failed (%d problem(s))\n", len(result.Errors))
+		os.Exit(env.ExitCode(result.Err()))
+	}
+	if banner {
+		fmt.Println()
+		fmt.Println("✅ Verification passed")
+	}
+}
+
+// cmdReviewApp deploys or destroys the pull request's review app
+// CI: run this on pull_request events (opened, reopened, synchronize, closed)
+func cmdReviewApp() {
+	result, err := workflow.ReviewAppWorkflow(workflow.ReviewAppOptions{
+		Registry:     AppRegistry,
+		OutputWriter: os.Stdout,
+		OutputFormat: outputFormat,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to run review app workflow: %v\n", err)
+		os.Exit(env.ExitCode(err))
+	}
+	if result.HasErrors() {
+		os.Exit(env.ExitCode(result.Err()))
+	}
+}
+
 // ================================================================
 // Helper Functions
 // ================================================================
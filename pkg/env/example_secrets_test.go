@@ -102,7 +102,11 @@ API_KEY=placeholder
 		"API_KEY":     "real_api_key",
 	}
 
-	result := env.MergeIntoTemplate(template, secrets)
+	result, err := env.MergeIntoTemplate(template, secrets)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
 	fmt.Print(result)
 
 	// Output:
@@ -132,7 +136,11 @@ VAR3=keep_this
 		"VAR1": "new_value",
 	}
 
-	result := env.MergeIntoTemplate(template, secrets)
+	result, err := env.MergeIntoTemplate(template, secrets)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
 	fmt.Print(result)
 
 	// Output:
@@ -223,7 +231,11 @@ KEY3=
 		"KEY3": "also_filled",
 	}
 
-	result := env.MergeIntoTemplate(template, secrets)
+	result, err := env.MergeIntoTemplate(template, secrets)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
 	fmt.Print(result)
 
 	// Output:
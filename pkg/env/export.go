@@ -3,7 +3,6 @@ package env
 
 import (
 	"fmt"
-	"os"
 	"strings"
 )
 
@@ -15,6 +14,7 @@ const (
 	FormatDocker  ExportFormat = "docker"  // Same as simple (for backward compatibility)
 	FormatSystemd ExportFormat = "systemd" // Environment="KEY=VALUE"
 	FormatK8s     ExportFormat = "k8s"     // - name: KEY\n  value: VALUE
+	FormatShell   ExportFormat = "shell"   // export KEY='VALUE' (secrets read from the OS keychain)
 )
 
 // ExportOptions controls environment variable export behavior.
@@ -30,7 +30,7 @@ type ExportOptions struct {
 // Export formats environment variables according to options.
 //
 // This is the main export function that applies filtering and formatting.
-// It reads actual values from the environment (os.Getenv).
+// It reads actual values from the environment (Registry.Getenv).
 //
 // Example:
 //
@@ -43,7 +43,7 @@ func (r *Registry) Export(opts ExportOptions) string {
 	// Get variables to export based on filters
 	var varsToExport []EnvVar
 
-	for _, v := range r.vars {
+	for _, v := range r.All() {
 		// Apply filters
 		if opts.SecretsOnly && !v.Secret {
 			continue
@@ -53,10 +53,11 @@ func (r *Registry) Export(opts ExportOptions) string {
 		}
 
 		// Get actual value from environment
-		value := os.Getenv(v.Name)
+		value := r.Getenv(v.Name)
 
 		// Skip empty values unless explicitly included
-		if !opts.IncludeEmpty && value == "" {
+		// (shell scripts read secrets from the keychain, so their env value doesn't matter)
+		if !opts.IncludeEmpty && value == "" && !(opts.Format == FormatShell && v.Secret) {
 			continue
 		}
 
@@ -64,15 +65,18 @@ func (r *Registry) Export(opts ExportOptions) string {
 	}
 
 	// Format output
-	return formatVars(varsToExport, opts)
+	if opts.Format == FormatShell {
+		return generateShellScript(varsToExport, ShellScriptOptions{IncludeEmpty: true})
+	}
+	return formatVars(varsToExport, opts, r.Getenv)
 }
 
 // formatVars formats a list of variables according to the specified format.
-func formatVars(vars []EnvVar, opts ExportOptions) string {
+func formatVars(vars []EnvVar, opts ExportOptions, getenv func(string) string) string {
 	var lines []string
 
 	for _, v := range vars {
-		value := os.Getenv(v.Name)
+		value := getenv(v.Name)
 
 		// Mask secrets if requested
 		if opts.MaskSecrets && v.Secret && value != "" {
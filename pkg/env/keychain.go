@@ -0,0 +1,184 @@
+// Package env provides shell export scripts backed by the OS keychain.
+package env
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// DefaultKeychainService is the keychain service name secrets are stored under.
+const DefaultKeychainService = "wellknown-env"
+
+// ================================================================
+// Shell Export Script
+// ================================================================
+
+// ShellScriptOptions configures GenerateShellScript.
+type ShellScriptOptions struct {
+	Service      string // Keychain service name (default: DefaultKeychainService)
+	IncludeEmpty bool   // Export non-secret vars that have no value
+}
+
+// GenerateShellScript generates a sourceable POSIX shell script that exports
+// all registry variables. Non-secret values are inlined (current value, else
+// default); secret values are never written to the script - they are read from
+// the OS keychain when the script is sourced (macOS "security", Linux "secret-tool").
+//
+// Example:
+//
+//	os.WriteFile("env.sh", []byte(registry.GenerateShellScript(env.ShellScriptOptions{})), 0644)
+//	// then: . ./env.sh
+func (r *Registry) GenerateShellScript(opts ShellScriptOptions) string {
+	return generateShellScript(r.All(), opts)
+}
+
+// generateShellScript renders the export script for the given variables
+func generateShellScript(vars []EnvVar, opts ShellScriptOptions) string {
+	if opts.Service == "" {
+		opts.Service = DefaultKeychainService
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Generated environment export script - source with: . ./env.sh\n")
+	sb.WriteString("# Secrets are read from the OS keychain, never stored in this file.\n")
+	sb.WriteString(fmt.Sprintf("# Store them with the keychain-store command (service %q).\n\n", opts.Service))
+
+	sb.WriteString("_env_keychain() {\n")
+	sb.WriteString("  case \"$(uname -s)\" in\n")
+	sb.WriteString(fmt.Sprintf("    Darwin) security find-generic-password -s %s -a \"$1\" -w 2>/dev/null ;;\n", shellQuote(opts.Service)))
+	sb.WriteString(fmt.Sprintf("    *) secret-tool lookup service %s account \"$1\" 2>/dev/null ;;\n", shellQuote(opts.Service)))
+	sb.WriteString("  esac\n")
+	sb.WriteString("}\n\n")
+
+	for _, v := range vars {
+		if v.Secret {
+			sb.WriteString(fmt.Sprintf("export %s=\"$(_env_keychain %s)\"\n", v.Name, v.Name))
+			continue
+		}
+		value := v.GetString()
+		if value == "" && !opts.IncludeEmpty {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("export %s=%s\n", v.Name, shellQuote(value)))
+	}
+
+	sb.WriteString("\nunset -f _env_keychain\n")
+	return sb.String()
+}
+
+// shellQuote single-quotes a value for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ================================================================
+// Keychain Storage
+// ================================================================
+
+// KeychainOptions configures StoreSecretsInKeychain.
+type KeychainOptions struct {
+	Registry    *Registry // Registry defining which variables are secrets
+	SecretsFile string    // Secrets file to read (default: SecretsLocal; .age version preferred)
+	Service     string    // Keychain service name (default: DefaultKeychainService)
+}
+
+// KeychainResult reports which secrets were stored.
+type KeychainResult struct {
+	Stored  []string // Variable names written to the keychain
+	Missing []string // Registry secrets with no value in the secrets file
+}
+
+// StoreSecretsInKeychain decrypts the secrets file and stores each registry
+// secret in the OS keychain, so GenerateShellScript output can read them.
+//
+// Example:
+//
+//	result, err := env.StoreSecretsInKeychain(env.KeychainOptions{Registry: registry})
+//	fmt.Printf("Stored %d secrets\n", len(result.Stored))
+func StoreSecretsInKeychain(opts KeychainOptions) (*KeychainResult, error) {
+	if opts.Registry == nil {
+		return nil, fmt.Errorf("registry is required")
+	}
+	if opts.SecretsFile == "" {
+		opts.SecretsFile = SecretsLocal.FileName
+	}
+	if opts.Service == "" {
+		opts.Service = DefaultKeychainService
+	}
+
+	secrets, err := LoadSecrets(SecretsSource{
+		FilePath:        opts.SecretsFile,
+		PreferEncrypted: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", opts.SecretsFile, err)
+	}
+
+	result := &KeychainResult{}
+	for _, v := range opts.Registry.GetSecrets() {
+		value := secrets[v.Name]
+		if value == "" {
+			result.Missing = append(result.Missing, v.Name)
+			continue
+		}
+		if err := StoreInKeychain(opts.Service, v.Name, value); err != nil {
+			return result, err
+		}
+		result.Stored = append(result.Stored, v.Name)
+	}
+	return result, nil
+}
+
+// StoreInKeychain stores (or updates) one value in the OS keychain.
+//
+// On macOS the value is passed to "security" as an argument (the tool has no
+// stdin mode), so it is briefly visible to other local users via ps.
+// On Linux it is piped to "secret-tool" on stdin.
+func StoreInKeychain(service, name, value string) error {
+	cmd, err := keychainStoreCommand(runtime.GOOS, service, name, value)
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to store %s in keychain: %w", name, err)
+	}
+	return nil
+}
+
+// LookupKeychain reads one value from the OS keychain.
+func LookupKeychain(service, name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", name, "-w")
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", name)
+	default:
+		return "", fmt.Errorf("keychain not supported on %s", runtime.GOOS)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from keychain: %w", name, err)
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// keychainStoreCommand builds the platform-specific store command
+func keychainStoreCommand(goos, service, name, value string) (*exec.Cmd, error) {
+	switch goos {
+	case "darwin":
+		// -U updates an existing item instead of failing
+		return exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", name, "-w", value), nil
+	case "linux", "freebsd", "openbsd":
+		cmd := exec.Command("secret-tool", "store", "--label", service+": "+name, "service", service, "account", name)
+		cmd.Stdin = strings.NewReader(value)
+		return cmd, nil
+	default:
+		return nil, fmt.Errorf("keychain not supported on %s", goos)
+	}
+}
@@ -0,0 +1,74 @@
+package env
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_GenerateShellScript(t *testing.T) {
+	t.Setenv("TEST_SHELL_TOKEN", "must-not-appear")
+	registry := NewRegistry([]EnvVar{
+		{Name: "TEST_SHELL_PORT", Default: "8080"},
+		{Name: "TEST_SHELL_NAME", Default: "it's"},
+		{Name: "TEST_SHELL_TOKEN", Secret: true},
+		{Name: "TEST_SHELL_UNSET"},
+	})
+
+	script := registry.GenerateShellScript(ShellScriptOptions{Service: "my-app"})
+
+	expected := []string{
+		"export TEST_SHELL_PORT='8080'",
+		`export TEST_SHELL_NAME='it'\''s'`,
+		`export TEST_SHELL_TOKEN="$(_env_keychain TEST_SHELL_TOKEN)"`,
+		"security find-generic-password -s 'my-app'",
+		"secret-tool lookup service 'my-app'",
+		"unset -f _env_keychain",
+	}
+	for _, needle := range expected {
+		if !strings.Contains(script, needle) {
+			t.Errorf("Expected script to contain %q.\nScript:\n%s", needle, script)
+		}
+	}
+	if strings.Contains(script, "must-not-appear") {
+		t.Error("Secret value was inlined in the script")
+	}
+	if strings.Contains(script, "TEST_SHELL_UNSET") {
+		t.Error("Empty non-secret should be skipped")
+	}
+}
+
+func TestRegistry_Export_Shell(t *testing.T) {
+	registry := NewRegistry([]EnvVar{{Name: "TEST_EXPORT_SHELL_KEY", Secret: true}})
+
+	output := registry.Export(ExportOptions{Format: FormatShell})
+	if !strings.Contains(output, "export TEST_EXPORT_SHELL_KEY=") {
+		t.Errorf("Expected unset secret to be exported from keychain.\nOutput:\n%s", output)
+	}
+}
+
+func TestKeychainStoreCommand(t *testing.T) {
+	cmd, err := keychainStoreCommand("linux", "svc", "API_KEY", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(strings.Join(cmd.Args, " "), "s3cret") {
+		t.Error("Linux store must pass the value on stdin, not argv")
+	}
+	stdin, _ := io.ReadAll(cmd.Stdin)
+	if string(stdin) != "s3cret" {
+		t.Errorf("stdin = %q", stdin)
+	}
+
+	cmd, err = keychainStoreCommand("darwin", "svc", "API_KEY", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(cmd.Args, " "); got != "security add-generic-password -U -s svc -a API_KEY -w s3cret" {
+		t.Errorf("darwin args = %q", got)
+	}
+
+	if _, err := keychainStoreCommand("windows", "svc", "API_KEY", "x"); err == nil {
+		t.Error("expected error on unsupported platform")
+	}
+}
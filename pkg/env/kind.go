@@ -0,0 +1,206 @@
+package env
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ================================================================
+// Value Kinds
+// ================================================================
+// EnvVar.Kind declares a value's syntax beyond the string/int/bool implied
+// by Default: ValidateValue parses values of that kind, the typed accessors
+// (GetDuration, GetBytes, GetURL, GetHostPort) return them parsed, and templates show the
+// expected syntax above the variable.
+//
+//	{Name: "REQUEST_TIMEOUT", Kind: env.KindDuration, Default: "30s"}
+//	{Name: "MAX_UPLOAD_SIZE", Kind: env.KindBytes, Default: "512MB"}
+
+// Kind is the syntax of a variable's value (empty: plain string, or the
+// int/bool implied by Default)
+type Kind string
+
+// Supported kinds
+const (
+	KindDuration Kind = "duration" // Go duration: "30s", "5m", "1h30m"
+	KindBytes    Kind = "bytes"    // Size with optional unit: "512MB", "1.5GiB", "4096"
+	KindURL      Kind = "url"      // Absolute URL with scheme and host
+	KindHostPort Kind = "hostport" // "host:port", ":8080", "[::1]:443"
+	KindPath     Kind = "path"     // Filesystem path
+)
+
+// kindSyntax describes the expected syntax of each kind
+var kindSyntax = map[Kind]string{
+	KindDuration: "duration, e.g. 30s, 5m, 1h30m (units: ns, us, ms, s, m, h)",
+	KindBytes:    "size, e.g. 512MB, 1.5GiB, 4096 (units: B, KB, MB, GB, TB; 1KB = 1024B)",
+	KindURL:      "URL, e.g. https://example.com/path",
+	KindHostPort: "host:port, e.g. localhost:8080, :8080, [::1]:443",
+	KindPath:     "path, e.g. ./data or /var/lib/app",
+}
+
+// Syntax returns a human-readable description of the kind's syntax, with
+// examples ("" for no kind)
+func (k Kind) Syntax() string {
+	return kindSyntax[k]
+}
+
+// Validate checks that value is valid for the kind. Empty values are not
+// checked; an unknown kind is an error.
+func (k Kind) Validate(value string) error {
+	if value == "" || k == "" {
+		return nil
+	}
+	switch k {
+	case KindDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("expected %s", k.Syntax())
+		}
+	case KindBytes:
+		if _, err := ParseBytes(value); err != nil {
+			return err
+		}
+	case KindURL:
+		if _, err := parseURL(value); err != nil {
+			return err
+		}
+	case KindHostPort:
+		if _, _, err := splitHostPort(value); err != nil {
+			return err
+		}
+	case KindPath:
+		if strings.ContainsRune(value, 0) {
+			return fmt.Errorf("path contains a NUL byte")
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", k)
+	}
+	return nil
+}
+
+// byteUnits are the multipliers ParseBytes accepts (lower case)
+var byteUnits = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1 << 10, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+	"t": 1 << 40, "tb": 1 << 40, "tib": 1 << 40,
+}
+
+// ParseBytes parses a size such as "512MB", "1.5GiB", "64 kb" or "4096"
+// (bytes). Units are case-insensitive and binary: KB, KiB and K all mean 1024.
+func ParseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	number, unit := s, ""
+	if i >= 0 {
+		number, unit = s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	}
+
+	multiplier, ok := byteUnits[unit]
+	n, err := strconv.ParseFloat(number, 64)
+	if !ok || err != nil || number == "" {
+		return 0, fmt.Errorf("expected %s", KindBytes.Syntax())
+	}
+	size := n * multiplier
+	if size > math.MaxInt64 {
+		return 0, fmt.Errorf("size %s is too large", s)
+	}
+	return int64(size), nil
+}
+
+// parseURL parses an absolute URL
+func parseURL(value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("expected %s", KindURL.Syntax())
+	}
+	return u, nil
+}
+
+// splitHostPort splits "host:port", requiring a numeric port
+func splitHostPort(value string) (host string, port int, err error) {
+	host, portStr, err := net.SplitHostPort(value)
+	if err == nil {
+		port, err = strconv.Atoi(portStr)
+	}
+	if err != nil || port < 0 || port > 65535 {
+		return "", 0, fmt.Errorf("expected %s", KindHostPort.Syntax())
+	}
+	return host, port, nil
+}
+
+// GetDuration returns the value of a KindDuration variable.
+// If the variable is not set or cannot be parsed, returns the default value
+// parsed; if that cannot be parsed either, returns 0.
+func (e *EnvVar) GetDuration() time.Duration {
+	recordUsage(e.Name)
+	return e.durationFrom(os.Getenv(e.Name))
+}
+
+// GetBytes returns the value of a KindBytes variable in bytes, falling back
+// like GetDuration
+func (e *EnvVar) GetBytes() int64 {
+	recordUsage(e.Name)
+	return e.bytesFrom(os.Getenv(e.Name))
+}
+
+// GetURL returns the value of a KindURL variable parsed, falling back like
+// GetDuration (nil when neither the value nor the default is a valid URL)
+func (e *EnvVar) GetURL() *url.URL {
+	recordUsage(e.Name)
+	return e.urlFrom(os.Getenv(e.Name))
+}
+
+// GetHostPort returns the host and port of a KindHostPort variable, falling
+// back like GetDuration ("" and 0 when neither is valid)
+func (e *EnvVar) GetHostPort() (string, int) {
+	recordUsage(e.Name)
+	return e.hostPortFrom(os.Getenv(e.Name))
+}
+
+// durationFrom parses value as GetDuration does
+func (e *EnvVar) durationFrom(value string) time.Duration {
+	for _, s := range []string{value, e.Default} {
+		if d, err := time.ParseDuration(s); err == nil {
+			return d
+		}
+	}
+	return 0
+}
+
+// bytesFrom parses value as GetBytes does
+func (e *EnvVar) bytesFrom(value string) int64 {
+	for _, s := range []string{value, e.Default} {
+		if n, err := ParseBytes(s); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// urlFrom parses value as GetURL does
+func (e *EnvVar) urlFrom(value string) *url.URL {
+	for _, s := range []string{value, e.Default} {
+		if u, err := parseURL(s); err == nil {
+			return u
+		}
+	}
+	return nil
+}
+
+// hostPortFrom parses value as GetHostPort does
+func (e *EnvVar) hostPortFrom(value string) (string, int) {
+	for _, s := range []string{value, e.Default} {
+		if host, port, err := splitHostPort(s); err == nil {
+			return host, port
+		}
+	}
+	return "", 0
+}
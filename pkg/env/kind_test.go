@@ -0,0 +1,133 @@
+package env
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"4096", 4096, false},
+		{"512MB", 512 << 20, false},
+		{"512mb", 512 << 20, false},
+		{"1.5GiB", 3 << 29, false},
+		{"64 kb", 64 << 10, false},
+		{"2T", 2 << 40, false},
+		{"10B", 10, false},
+		{"", 0, true},
+		{"MB", 0, true},
+		{"-1MB", 0, true},
+		{"12 parsecs", 0, true},
+		{"99999999999TB", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBytes(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBytes(%q) = %d, %v; want %d (error: %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestKind_Validate(t *testing.T) {
+	tests := []struct {
+		kind  Kind
+		value string
+		valid bool
+	}{
+		{KindDuration, "30s", true},
+		{KindDuration, "1h30m", true},
+		{KindDuration, "30", false},
+		{KindBytes, "512MB", true},
+		{KindBytes, "lots", false},
+		{KindURL, "https://example.com/path", true},
+		{KindURL, "example.com", false},
+		{KindURL, "/relative", false},
+		{KindHostPort, "localhost:8080", true},
+		{KindHostPort, ":8080", true},
+		{KindHostPort, "[::1]:443", true},
+		{KindHostPort, "localhost", false},
+		{KindHostPort, "localhost:http", false},
+		{KindHostPort, "localhost:70000", false},
+		{KindPath, "./data", true},
+		{KindPath, "bad\x00path", false},
+		{Kind("color"), "red", false},
+		{KindDuration, "", true}, // Empty values are not checked
+	}
+
+	for _, tt := range tests {
+		err := tt.kind.Validate(tt.value)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s.Validate(%q) = %v, want valid=%v", tt.kind, tt.value, err, tt.valid)
+		}
+	}
+}
+
+func TestEnvVar_KindAccessors(t *testing.T) {
+	timeout := EnvVar{Name: "TEST_KIND_TIMEOUT", Kind: KindDuration, Default: "30s"}
+	size := EnvVar{Name: "TEST_KIND_SIZE", Kind: KindBytes, Default: "1MB"}
+	endpoint := EnvVar{Name: "TEST_KIND_URL", Kind: KindURL, Default: "https://example.com"}
+	listen := EnvVar{Name: "TEST_KIND_LISTEN", Kind: KindHostPort, Default: ":8080"}
+
+	// Defaults
+	if got := timeout.GetDuration(); got != 30*time.Second {
+		t.Errorf("GetDuration() = %v, want default 30s", got)
+	}
+	if got := size.GetBytes(); got != 1<<20 {
+		t.Errorf("GetBytes() = %d, want default 1MB", got)
+	}
+	if got := endpoint.GetURL(); got == nil || got.Host != "example.com" {
+		t.Errorf("GetURL() = %v, want default", got)
+	}
+	if host, port := listen.GetHostPort(); host != "" || port != 8080 {
+		t.Errorf("GetHostPort() = %q, %d, want default :8080", host, port)
+	}
+
+	// Set values win; invalid values fall back to the default
+	t.Setenv("TEST_KIND_TIMEOUT", "2m")
+	t.Setenv("TEST_KIND_SIZE", "not a size")
+	t.Setenv("TEST_KIND_LISTEN", "0.0.0.0:9000")
+	if got := timeout.GetDuration(); got != 2*time.Minute {
+		t.Errorf("GetDuration() = %v, want 2m", got)
+	}
+	if got := size.GetBytes(); got != 1<<20 {
+		t.Errorf("GetBytes() = %d, want the default for an invalid value", got)
+	}
+	if host, port := listen.GetHostPort(); host != "0.0.0.0" || port != 9000 {
+		t.Errorf("GetHostPort() = %q, %d, want 0.0.0.0:9000", host, port)
+	}
+
+	// ValidateValue checks the kind instead of the type implied by the default
+	if err := size.ValidateValue("512MB"); err != nil {
+		t.Errorf("ValidateValue(512MB) = %v", err)
+	}
+	if err := size.ValidateValue("not a size"); err == nil {
+		t.Error("ValidateValue should reject an invalid size")
+	}
+	if err := (EnvVar{Name: "N", Kind: KindBytes, Default: "1024"}).ValidateValue("2KB"); err != nil {
+		t.Errorf("an integer default must not force integer values on a bytes kind: %v", err)
+	}
+}
+
+func TestGenerateTemplate_KindSyntax(t *testing.T) {
+	r := NewRegistry([]EnvVar{
+		{Name: "TIMEOUT", Description: "Request timeout", Kind: KindDuration, Default: "30s"},
+		{Name: "PLAIN", Description: "Plain string"},
+	})
+
+	out := r.GenerateTemplate(TemplateOptions{IncludeComments: true})
+	if !strings.Contains(out, "# Request timeout\n# Format: "+KindDuration.Syntax()+"\nTIMEOUT=30s\n") {
+		t.Errorf("expected the duration syntax above TIMEOUT:\n%s", out)
+	}
+	if strings.Count(out, "# Format:") != 1 {
+		t.Errorf("only kinded variables get a format line:\n%s", out)
+	}
+	if !strings.Contains(r.GenerateMarkdownReferenceSection(), "| `TIMEOUT` | duration |") {
+		t.Error("the reference should show the kind as the type")
+	}
+}
@@ -0,0 +1,152 @@
+package env
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ================================================================
+// Secrets Hygiene Linter
+// ================================================================
+// LintSecrets inspects loaded values for common weaknesses. Findings never
+// include the values themselves, so they are safe to print and serve.
+
+// MinSecretLength is the shortest secret value that does not trigger RuleTooShort
+const MinSecretLength = 16
+
+// Lint rules
+const (
+	RulePlaceholder    = "placeholder"     // Secret still holds a template/example value
+	RuleTooShort       = "too-short"       // Secret shorter than MinSecretLength
+	RuleReused         = "reused"          // Same secret value in several variables
+	RuleURLCredentials = "url-credentials" // URL with an embedded password
+)
+
+// Finding severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// placeholderValues are common template values (compared case-insensitively,
+// ignoring "-" and "_")
+var placeholderValues = []string{
+	"changeme", "changeme123", "change", "password", "password123", "secret",
+	"test", "testing", "example", "placeholder", "todo", "xxx", "xxxx",
+	"12345", "123456", "12345678", "admin", "default", "none", "null",
+}
+
+// placeholderPrefixes mark values copied from documentation (e.g., "your-api-key")
+var placeholderPrefixes = []string{"your", "replace", "insert", "enter", "<", "xxx"}
+
+// SecretFinding is one linter result
+type SecretFinding struct {
+	Name     string `json:"name"`     // Variable name
+	Rule     string `json:"rule"`     // One of the Rule* constants
+	Severity string `json:"severity"` // SeverityError or SeverityWarning
+	Message  string `json:"message"`  // Human-readable explanation (never contains the value)
+}
+
+func (f SecretFinding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Name, f.Message, f.Rule)
+}
+
+// LintSecrets checks the current values of the registry's variables: secrets for
+// placeholders, short values and reuse; every variable for URLs with embedded
+// passwords. Unset variables are skipped. Findings are sorted by name.
+//
+// Example:
+//
+//	for _, f := range registry.LintSecrets() {
+//	    fmt.Println("⚠️ ", f)
+//	}
+func (r *Registry) LintSecrets() []SecretFinding {
+	return lintSecrets(r.All(), r.Getenv)
+}
+
+// lintSecrets runs the rules with values from lookup
+func lintSecrets(vars []EnvVar, lookup func(string) string) []SecretFinding {
+	var findings []SecretFinding
+	byValue := make(map[string][]string)
+
+	for _, v := range vars {
+		value := lookup(v.Name)
+		if value == "" {
+			continue
+		}
+
+		if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.User != nil {
+			if _, hasPassword := u.User.Password(); hasPassword {
+				severity := SeverityWarning
+				message := "URL contains an embedded password; keep credentials in separate secret variables"
+				if !v.Secret {
+					severity = SeverityError
+					message = "URL contains an embedded password but the variable is not marked secret"
+				}
+				findings = append(findings, SecretFinding{Name: v.Name, Rule: RuleURLCredentials, Severity: severity, Message: message})
+			}
+		}
+
+		if !v.Secret {
+			continue
+		}
+
+		if isPlaceholder(value) {
+			findings = append(findings, SecretFinding{
+				Name: v.Name, Rule: RulePlaceholder, Severity: SeverityError,
+				Message: "value looks like a placeholder; set a real secret",
+			})
+		} else if len(value) < MinSecretLength {
+			findings = append(findings, SecretFinding{
+				Name: v.Name, Rule: RuleTooShort, Severity: SeverityWarning,
+				Message: fmt.Sprintf("value is %d characters; use at least %d", len(value), MinSecretLength),
+			})
+		}
+		byValue[value] = append(byValue[value], v.Name)
+	}
+
+	for _, names := range byValue {
+		if len(names) < 2 {
+			continue
+		}
+		for _, name := range names {
+			others := make([]string, 0, len(names)-1)
+			for _, other := range names {
+				if other != name {
+					others = append(others, other)
+				}
+			}
+			findings = append(findings, SecretFinding{
+				Name: name, Rule: RuleReused, Severity: SeverityWarning,
+				Message: "same value as " + strings.Join(others, ", "),
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Name != findings[j].Name {
+			return findings[i].Name < findings[j].Name
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
+
+// isPlaceholder reports whether value looks like a template value
+func isPlaceholder(value string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(value))
+	for _, p := range placeholderValues {
+		if normalized == p {
+			return true
+		}
+	}
+	lower := strings.ToLower(value)
+	for _, p := range placeholderPrefixes {
+		if strings.HasPrefix(lower, p) {
+			return true
+		}
+	}
+	return strings.Contains(normalized, "changeme")
+}
@@ -0,0 +1,68 @@
+package env
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintSecrets(t *testing.T) {
+	vars := []EnvVar{
+		{Name: "API_KEY", Secret: true},
+		{Name: "DB_PASSWORD", Secret: true},
+		{Name: "JWT_SECRET", Secret: true},
+		{Name: "SESSION_SECRET", Secret: true},
+		{Name: "STRONG_KEY", Secret: true},
+		{Name: "DATABASE_URL"},
+		{Name: "UNSET_SECRET", Secret: true},
+	}
+	values := map[string]string{
+		"API_KEY":        "changeme123",
+		"DB_PASSWORD":    "short",
+		"JWT_SECRET":     "shared-value-0123456789",
+		"SESSION_SECRET": "shared-value-0123456789",
+		"STRONG_KEY":     "k8Jd93nfLq0Zx7Vb2Rt5",
+		"DATABASE_URL":   "postgres://app:hunter2@db:5432/app",
+	}
+
+	findings := lintSecrets(vars, func(name string) string { return values[name] })
+
+	got := make(map[string]string)
+	for _, f := range findings {
+		got[f.Name+"/"+f.Rule] = f.Severity
+		for _, v := range values {
+			if strings.Contains(f.Message, v) {
+				t.Errorf("finding for %s leaks a value: %s", f.Name, f.Message)
+			}
+		}
+	}
+
+	want := map[string]string{
+		"API_KEY/" + RulePlaceholder:         SeverityError,
+		"DB_PASSWORD/" + RuleTooShort:        SeverityWarning,
+		"JWT_SECRET/" + RuleReused:           SeverityWarning,
+		"SESSION_SECRET/" + RuleReused:       SeverityWarning,
+		"DATABASE_URL/" + RuleURLCredentials: SeverityError,
+	}
+	for key, severity := range want {
+		if got[key] != severity {
+			t.Errorf("expected %s %s, got %q", key, severity, got[key])
+		}
+	}
+	if len(findings) != len(want) {
+		t.Errorf("expected %d findings, got %d: %v", len(want), len(findings), findings)
+	}
+}
+
+func TestIsPlaceholder(t *testing.T) {
+	for value, want := range map[string]bool{
+		"your-api-key-here":    true,
+		"CHANGE_ME":            true,
+		"<secret>":             true,
+		"password":             true,
+		"sk-live-9f8d7c6b5a4e": false,
+	} {
+		if got := isPlaceholder(value); got != want {
+			t.Errorf("isPlaceholder(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package env
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Overlay layers a set of values over the process environment, for
+// configuration that varies per scope within one process - e.g. each tenant
+// of a multi-tenant deployment overriding GOOGLE_CLIENT_ID. A variable set in
+// the overlay wins over os.Getenv; anything else falls through to it.
+//
+//	acme, err := registry.NewOverlay(map[string]string{"GOOGLE_CLIENT_ID": "acme-client"})
+//	acme.GetString("GOOGLE_CLIENT_ID") // "acme-client"
+//	acme.GetInt("SERVER_PORT")         // $SERVER_PORT, else the registry default
+type Overlay struct {
+	registry *Registry
+	values   map[string]string
+}
+
+// NewOverlay creates an overlay of values (may be nil). Every name must be
+// registered and every value must pass EnvVar.ValidateValue, so a bad override
+// is reported when it is loaded rather than silently falling back.
+func (r *Registry) NewOverlay(values map[string]string) (*Overlay, error) {
+	copied := make(map[string]string, len(values))
+	var invalid []string
+	for _, name := range sortedKeys(values) {
+		value := values[name]
+		v := r.ByName(name)
+		if v == nil {
+			invalid = append(invalid, fmt.Sprintf("%s (not registered)", name))
+			continue
+		}
+		if err := v.ValidateValue(value); err != nil {
+			if v.Secret {
+				invalid = append(invalid, fmt.Sprintf("%s (%v)", name, err))
+			} else {
+				invalid = append(invalid, fmt.Sprintf("%s=%q (%v)", name, value, err))
+			}
+			continue
+		}
+		copied[v.Name] = value
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid overrides: %s", strings.Join(invalid, ", "))
+	}
+	return &Overlay{registry: r, values: copied}, nil
+}
+
+// Lookup returns the overlay's own value for name, if set
+func (o *Overlay) Lookup(name string) (string, bool) {
+	value, ok := o.values[o.registry.EnvName(name)]
+	return value, ok
+}
+
+// Overrides returns the names the overlay sets, sorted
+func (o *Overlay) Overrides() []string {
+	return sortedKeys(o.values)
+}
+
+// value returns the overlay value, else the process environment value
+func (o *Overlay) value(name string) string {
+	name = o.registry.EnvName(name)
+	if value, ok := o.values[name]; ok && value != "" {
+		return value
+	}
+	return os.Getenv(name)
+}
+
+// envVar returns the registered variable (a bare one for unregistered names)
+func (o *Overlay) envVar(name string) *EnvVar {
+	name = o.registry.EnvName(name)
+	recordUsage(name)
+	if v := o.registry.ByName(name); v != nil {
+		return v
+	}
+	return &EnvVar{Name: name}
+}
+
+// GetString is EnvVar.GetString with the overlay applied
+func (o *Overlay) GetString(name string) string {
+	return o.envVar(name).stringFrom(o.value(name))
+}
+
+// GetInt is EnvVar.GetInt with the overlay applied
+func (o *Overlay) GetInt(name string) int {
+	return o.envVar(name).intFrom(o.value(name))
+}
+
+// GetBool is EnvVar.GetBool with the overlay applied
+func (o *Overlay) GetBool(name string) bool {
+	return o.envVar(name).boolFrom(o.value(name))
+}
+
+// GetDuration is EnvVar.GetDuration with the overlay applied
+func (o *Overlay) GetDuration(name string) time.Duration {
+	return o.envVar(name).durationFrom(o.value(name))
+}
+
+// GetBytes is EnvVar.GetBytes with the overlay applied
+func (o *Overlay) GetBytes(name string) int64 {
+	return o.envVar(name).bytesFrom(o.value(name))
+}
+
+// GetURL is EnvVar.GetURL with the overlay applied
+func (o *Overlay) GetURL(name string) *url.URL {
+	return o.envVar(name).urlFrom(o.value(name))
+}
+
+// GetHostPort is EnvVar.GetHostPort with the overlay applied
+func (o *Overlay) GetHostPort(name string) (string, int) {
+	return o.envVar(name).hostPortFrom(o.value(name))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
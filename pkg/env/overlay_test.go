@@ -0,0 +1,64 @@
+package env
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "OVERLAY_CLIENT_ID", Secret: true},
+		{Name: "OVERLAY_PORT", Default: "8090"},
+		{Name: "OVERLAY_DEBUG", Default: "false"},
+	})
+	t.Setenv("OVERLAY_CLIENT_ID", "base-client")
+	t.Setenv("OVERLAY_PORT", "9000")
+
+	overlay, err := registry.NewOverlay(map[string]string{
+		"OVERLAY_CLIENT_ID": "acme-client",
+		"OVERLAY_DEBUG":     "yes",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := overlay.GetString("OVERLAY_CLIENT_ID"); got != "acme-client" {
+		t.Errorf("overridden value = %q, want acme-client", got)
+	}
+	if got := overlay.GetInt("OVERLAY_PORT"); got != 9000 {
+		t.Errorf("process env should show through: got %d", got)
+	}
+	if !overlay.GetBool("OVERLAY_DEBUG") {
+		t.Error("OVERLAY_DEBUG should be true")
+	}
+	if got := registry.ByName("OVERLAY_CLIENT_ID").GetString(); got != "base-client" {
+		t.Errorf("registry itself must be unaffected, got %q", got)
+	}
+	if got := strings.Join(overlay.Overrides(), ","); got != "OVERLAY_CLIENT_ID,OVERLAY_DEBUG" {
+		t.Errorf("Overrides() = %s", got)
+	}
+}
+
+func TestNewOverlay_Invalid(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "OVERLAY_PORT", Default: "8090"},
+		{Name: "OVERLAY_TOKEN", Secret: true, Validate: func(string) error { return errors.New("rejected") }},
+	})
+	_, err := registry.NewOverlay(map[string]string{
+		"OVERLAY_PORT":    "abc",
+		"OVERLAY_UNKNOWN": "x",
+		"OVERLAY_TOKEN":   "hunter2",
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"OVERLAY_PORT=\"abc\"", "OVERLAY_UNKNOWN (not registered)", "OVERLAY_TOKEN ("} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Error("secret values must not appear in errors")
+	}
+}
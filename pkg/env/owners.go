@@ -0,0 +1,154 @@
+package env
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ================================================================
+// Ownership
+// ================================================================
+// Who to ask about a variable. Owners are assigned CODEOWNERS-style: each
+// rule matches variable names (a glob such as "GOOGLE_*") or a whole group
+// ("group:Google OAuth"), and the last matching rule wins, so a broad rule
+// can be followed by narrower exceptions. A variable's own Owner field
+// overrides the rules. Validation errors, the configuration reference and
+// the webui name the owner, so the answer is not tribal knowledge.
+//
+//	registry := env.NewRegistry(vars, env.Owners(
+//		env.OwnerRule{Pattern: "*", Ownership: env.Ownership{Owner: "@platform"}},
+//		env.OwnerRule{Pattern: "group:Google OAuth", Ownership: env.Ownership{Owner: "@auth", Contact: "#auth-oncall"}},
+//	))
+
+// GroupPatternPrefix marks an OwnerRule pattern that matches a group name
+const GroupPatternPrefix = "group:"
+
+// Ownership is who owns a variable and how to reach them
+type Ownership struct {
+	Owner   string `json:"owner"`             // Team or person, e.g. "@platform"
+	Contact string `json:"contact,omitempty"` // Channel, email or URL, e.g. "#platform-oncall"
+}
+
+// String returns "owner (contact)", or "" without an owner
+func (o Ownership) String() string {
+	if o.Owner == "" {
+		return ""
+	}
+	if o.Contact == "" {
+		return o.Owner
+	}
+	return o.Owner + " (" + o.Contact + ")"
+}
+
+// OwnerRule assigns an owner to the variables matching Pattern
+type OwnerRule struct {
+	Pattern string // Name glob (path.Match syntax) or GroupPatternPrefix + group name
+	Ownership
+}
+
+// matches reports whether the rule applies to v (logical is v's name without
+// the registry prefix, which name globs are matched against too)
+func (r OwnerRule) matches(v EnvVar, logical string) bool {
+	if group, ok := strings.CutPrefix(r.Pattern, GroupPatternPrefix); ok {
+		return group == v.Group
+	}
+	for _, name := range []string{v.Name, logical} {
+		if ok, _ := path.Match(r.Pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Owners sets the ownership rules of the registry (last match wins)
+func Owners(rules ...OwnerRule) RegistryOption {
+	return func(r *Registry) {
+		r.owners = append(r.owners, rules...)
+	}
+}
+
+// OwnerOf returns the ownership of the variable name (logical or prefixed):
+// its own Owner/Contact fields, else the last matching rule. ok is false for
+// unknown or unowned variables.
+func (r *Registry) OwnerOf(name string) (o Ownership, ok bool) {
+	v := r.ByName(name)
+	if v == nil {
+		return Ownership{}, false
+	}
+	return r.ownerOf(*v)
+}
+
+func (r *Registry) ownerOf(v EnvVar) (Ownership, bool) {
+	if v.Owner != "" {
+		return Ownership{Owner: v.Owner, Contact: v.Contact}, true
+	}
+	logical := r.LogicalName(v.Name)
+	for i := len(r.owners) - 1; i >= 0; i-- {
+		if r.owners[i].matches(v, logical) {
+			return r.owners[i].Ownership, true
+		}
+	}
+	return Ownership{}, false
+}
+
+// withOwner returns name followed by its owner, for error messages
+func (r *Registry) withOwner(v EnvVar, name string) string {
+	if o, ok := r.ownerOf(v); ok {
+		return name + " [ask " + o.String() + "]"
+	}
+	return name
+}
+
+// ParseOwners reads ownership rules in CODEOWNERS style, one per line:
+//
+//	# pattern           owner      contact (optional, rest of the line)
+//	*                   @platform  #platform
+//	GOOGLE_*            @auth      auth@example.com
+//	"group:Google OAuth" @auth     #auth-oncall
+//
+// Patterns containing spaces are quoted. Blank lines and # comments are skipped.
+func ParseOwners(rd io.Reader) ([]OwnerRule, error) {
+	var rules []OwnerRule
+	scanner := bufio.NewScanner(rd)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var pattern string
+		if rest, quoted := strings.CutPrefix(line, `"`); quoted {
+			end := strings.Index(rest, `"`)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quote", lineNo)
+			}
+			pattern, line = rest[:end], rest[end+1:]
+		} else {
+			pattern, line = line, ""
+			if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+				pattern, line = pattern[:i], pattern[i:]
+			}
+		}
+
+		fields := strings.Fields(line)
+		if pattern == "" || len(fields) == 0 {
+			return nil, fmt.Errorf("line %d: want <pattern> <owner> [contact]", lineNo)
+		}
+		if !strings.HasPrefix(pattern, GroupPatternPrefix) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("line %d: invalid pattern %q: %w", lineNo, pattern, err)
+			}
+		}
+		rules = append(rules, OwnerRule{
+			Pattern:   pattern,
+			Ownership: Ownership{Owner: fields[0], Contact: strings.Join(fields[1:], " ")},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,117 @@
+package env
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_OwnerOf(t *testing.T) {
+	r := NewRegistry([]EnvVar{
+		{Name: "GOOGLE_CLIENT_ID", Group: "Google OAuth"},
+		{Name: "GOOGLE_TOKEN_KEY", Group: "Google OAuth", Owner: "@security", Contact: "security@example.com"},
+		{Name: "SERVER_PORT", Group: "Server"},
+		{Name: "PORT", Group: "Server"},
+	}, Prefix("APP_"), Owners(
+		OwnerRule{Pattern: "*", Ownership: Ownership{Owner: "@platform"}},
+		OwnerRule{Pattern: "group:Google OAuth", Ownership: Ownership{Owner: "@auth", Contact: "#auth-oncall"}},
+		OwnerRule{Pattern: "PORT", Ownership: Ownership{Owner: "@edge"}}, // Logical name
+	))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"GOOGLE_CLIENT_ID", "@auth (#auth-oncall)"},             // Group rule beats the earlier catch-all
+		{"GOOGLE_TOKEN_KEY", "@security (security@example.com)"}, // The variable's own owner wins
+		{"APP_SERVER_PORT", "@platform"},
+		{"PORT", "@edge"},
+	}
+	for _, tt := range tests {
+		o, ok := r.OwnerOf(tt.name)
+		if !ok || o.String() != tt.want {
+			t.Errorf("OwnerOf(%s) = %q, %v; want %q", tt.name, o.String(), ok, tt.want)
+		}
+	}
+	if _, ok := r.OwnerOf("UNKNOWN"); ok {
+		t.Error("OwnerOf(UNKNOWN) should report no owner")
+	}
+	if _, ok := NewRegistry([]EnvVar{{Name: "A"}}).OwnerOf("A"); ok {
+		t.Error("variables without rules should have no owner")
+	}
+}
+
+func TestRegistry_ValidationNamesOwner(t *testing.T) {
+	r := NewRegistry([]EnvVar{
+		{Name: "OWNED_REQUIRED", Required: true},
+		{Name: "OWNED_PORT", Default: "8080"},
+		{Name: "UNOWNED_REQUIRED", Required: true},
+	}, Owners(OwnerRule{Pattern: "OWNED_*", Ownership: Ownership{Owner: "@platform", Contact: "#platform"}}))
+	os.Unsetenv("OWNED_REQUIRED")
+	os.Unsetenv("UNOWNED_REQUIRED")
+	t.Setenv("OWNED_PORT", "not-a-number")
+
+	err := r.ValidateRequired()
+	if err == nil {
+		t.Fatal("ValidateRequired() should fail")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "OWNED_REQUIRED [ask @platform (#platform)]") || strings.Contains(msg, "UNOWNED_REQUIRED [") {
+		t.Errorf("ValidateRequired() = %q, want the owner of OWNED_REQUIRED only", msg)
+	}
+
+	err = r.ValidateTypes()
+	if err == nil || !strings.Contains(err.Error(), "[ask @platform (#platform)]") {
+		t.Errorf("ValidateTypes() = %v, want the owner of OWNED_PORT", err)
+	}
+}
+
+func TestRegistry_ReferenceOwners(t *testing.T) {
+	r := NewRegistry([]EnvVar{
+		{Name: "GOOGLE_CLIENT_ID", Group: "Google OAuth"},
+		{Name: "SERVER_PORT", Group: "Server"},
+	}, Owners(OwnerRule{Pattern: "group:Google OAuth", Ownership: Ownership{Owner: "@auth", Contact: "#auth-oncall"}}))
+
+	doc := r.GenerateMarkdownReferenceSection()
+	if !strings.Contains(doc, "## Google OAuth\n\nOwner: @auth (#auth-oncall)\n") {
+		t.Errorf("reference should name the group owner:\n%s", doc)
+	}
+	if strings.Count(doc, "Owner:") != 1 {
+		t.Errorf("only owned groups should list an owner:\n%s", doc)
+	}
+
+	entries := r.Reference()
+	if entries[0].Owner != "@auth" || entries[0].Contact != "#auth-oncall" || entries[1].Owner != "" {
+		t.Errorf("Reference() owners = %+v", entries)
+	}
+}
+
+func TestParseOwners(t *testing.T) {
+	rules, err := ParseOwners(strings.NewReader(`
+# Catch-all first; later lines win
+*	@platform   #platform
+GOOGLE_*            @auth      auth@example.com
+"group:Google OAuth" @auth     #auth on call
+`))
+	if err != nil {
+		t.Fatalf("ParseOwners: %v", err)
+	}
+	want := []OwnerRule{
+		{Pattern: "*", Ownership: Ownership{Owner: "@platform", Contact: "#platform"}},
+		{Pattern: "GOOGLE_*", Ownership: Ownership{Owner: "@auth", Contact: "auth@example.com"}},
+		{Pattern: "group:Google OAuth", Ownership: Ownership{Owner: "@auth", Contact: "#auth on call"}},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("got %d rules, want %d: %+v", len(rules), len(want), rules)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"PATTERN_ONLY", `"group:Unterminated @team`, "[ @team"} {
+		if _, err := ParseOwners(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseOwners(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ================================================================
+// Value Policies
+// ================================================================
+// Constraints on values that only hold in some environments, such as
+// "DEBUG must be false in production" or "HTTPS_ENABLED must be true in
+// production unless the platform is Fly.io" (which terminates TLS itself).
+// Policies are defined in Go or read from a small policy file (see
+// ParsePolicies), attached with the Policies option and checked by
+// CheckPolicies, workflow.VerifyWorkflow and the webui. Violations name the
+// rule, the environment and the variable's owner, never a secret's value.
+//
+//	registry := env.NewRegistry(vars, env.Policies(
+//		env.Policy{Environments: []string{"production"}, Variable: "DEBUG", Op: env.PolicyEquals, Value: "false"},
+//		env.Policy{
+//			Environments: []string{"production"}, Variable: "HTTPS_ENABLED", Op: env.PolicyEquals, Value: "true",
+//			Unless: []env.Condition{{Key: env.ConditionPlatform, Value: "fly"}},
+//		},
+//	))
+
+// PolicyOp is how a policy constrains a value
+type PolicyOp string
+
+// Policy operators (also their policy file syntax)
+const (
+	PolicyEquals    PolicyOp = "=="       // Value equals Policy.Value (booleans compare by meaning: "1" == "true")
+	PolicyNotEquals PolicyOp = "!="       // Value differs from Policy.Value
+	PolicyMatches   PolicyOp = "=~"       // Value matches the regular expression Policy.Value
+	PolicyOneOf     PolicyOp = "in"       // Value is one of the comma-separated Policy.Value
+	PolicyRequired  PolicyOp = "required" // Value is set (or has a default)
+)
+
+// Condition keys besides variable names
+const (
+	ConditionPlatform    = "platform"    // Deployment platform (see DetectEnvironment)
+	ConditionEnvironment = "environment" // Environment name
+)
+
+// Condition exempts a context from a policy when Key has Value. Key is
+// ConditionPlatform, ConditionEnvironment or a variable name. Platforms
+// also match by their first label, so "fly" matches "fly.io".
+type Condition struct {
+	Key   string
+	Value string
+}
+
+func (c Condition) String() string {
+	return c.Key + " == " + c.Value
+}
+
+// Policy constrains the value of Variable in some environments
+type Policy struct {
+	Environments []string    // Environment names it applies in (empty or "*": all)
+	Variable     string      // Variable name (logical or prefixed)
+	Op           PolicyOp    // Constraint (ignored when Check is set)
+	Value        string      // Operand of Op
+	Unless       []Condition // Exemptions; the policy is skipped when any holds
+	Message      string      // Replaces the generated violation message (optional)
+
+	// Check is a custom constraint written in Go (optional; replaces Op)
+	Check func(value string) error
+}
+
+// Rule describes the policy as written in a policy file, e.g.
+// "DEBUG == false" or "HTTPS_ENABLED == true unless platform == fly"
+func (p Policy) Rule() string {
+	rule := p.Variable + " " + string(p.Op)
+	switch {
+	case p.Check != nil:
+		rule = p.Variable + " (custom check)"
+	case p.Op != PolicyRequired:
+		rule += " " + p.Value
+	}
+	for _, c := range p.Unless {
+		rule += " unless " + c.String()
+	}
+	return rule
+}
+
+// appliesIn reports whether the policy covers environment
+func (p Policy) appliesIn(environment string) bool {
+	if len(p.Environments) == 0 {
+		return true
+	}
+	for _, name := range p.Environments {
+		if name == "*" || strings.EqualFold(name, environment) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyContext is what policies are evaluated against
+type PolicyContext struct {
+	Environment string                   // Environment name, e.g. "production"
+	Platform    string                   // Deployment platform, e.g. "fly.io" (see DetectEnvironment)
+	Lookup      func(name string) string // Variable values (default: Registry.Getenv)
+}
+
+// PolicyViolation is one failed policy
+type PolicyViolation struct {
+	Variable    string `json:"variable"`
+	Environment string `json:"environment"`
+	Rule        string `json:"rule"`    // Policy.Rule
+	Message     string `json:"message"` // Human-readable explanation (never contains a secret value)
+
+	valueNote string // Suffix of Message showing a non-secret value, e.g. ` (is "true")`
+}
+
+func (v PolicyViolation) String() string {
+	return v.Message
+}
+
+// Redacted returns v without the offending value in its message, for callers
+// allowed to see what is misconfigured but not the values themselves
+func (v PolicyViolation) Redacted() PolicyViolation {
+	v.Message = strings.TrimSuffix(v.Message, v.valueNote)
+	v.valueNote = ""
+	return v
+}
+
+// Policies adds value policies to the registry
+func Policies(policies ...Policy) RegistryOption {
+	return func(r *Registry) {
+		r.policies = append(r.policies, policies...)
+	}
+}
+
+// CheckPolicies evaluates the registry's policies for ctx and returns the
+// violations in policy order. Unset variables fall back to their defaults.
+//
+// Example:
+//
+//	violations := registry.CheckPolicies(env.PolicyContext{
+//	    Environment: registry.Status().Name,
+//	    Platform:    env.DetectEnvironment(),
+//	})
+func (r *Registry) CheckPolicies(ctx PolicyContext) []PolicyViolation {
+	if ctx.Lookup == nil {
+		ctx.Lookup = r.Getenv
+	}
+
+	var violations []PolicyViolation
+	for _, p := range r.policies {
+		if !p.appliesIn(ctx.Environment) || r.exempt(p, ctx) {
+			continue
+		}
+		v := r.policyVar(p.Variable)
+		value := v.stringFrom(ctx.Lookup(v.Name))
+		if err := p.check(value); err != nil {
+			var note string
+			if value != "" && !v.Secret {
+				note = fmt.Sprintf(" (is %q)", value)
+			}
+			violations = append(violations, PolicyViolation{
+				Variable:    v.Name,
+				Environment: ctx.Environment,
+				Rule:        p.Rule(),
+				Message:     r.violationMessage(p, v, ctx, value, err) + note,
+				valueNote:   note,
+			})
+		}
+	}
+	return violations
+}
+
+// ValidatePolicies returns an error listing every violation for ctx, or nil
+func (r *Registry) ValidatePolicies(ctx PolicyContext) error {
+	violations := r.CheckPolicies(ctx)
+	if len(violations) == 0 {
+		return nil
+	}
+	messages := make([]string, len(violations))
+	for i, v := range violations {
+		messages[i] = v.Message
+	}
+	return fmt.Errorf("%w: %s", ErrPolicyViolation, strings.Join(messages, "; "))
+}
+
+// policyVar returns the registry variable of name, or a bare one for
+// variables the registry does not know
+func (r *Registry) policyVar(name string) EnvVar {
+	if v := r.ByName(name); v != nil {
+		return *v
+	}
+	return EnvVar{Name: name}
+}
+
+// exempt reports whether one of p's Unless conditions holds in ctx
+func (r *Registry) exempt(p Policy, ctx PolicyContext) bool {
+	for _, c := range p.Unless {
+		switch c.Key {
+		case ConditionPlatform:
+			first, _, _ := strings.Cut(ctx.Platform, ".")
+			if strings.EqualFold(c.Value, ctx.Platform) || strings.EqualFold(c.Value, first) {
+				return true
+			}
+		case ConditionEnvironment:
+			if strings.EqualFold(c.Value, ctx.Environment) {
+				return true
+			}
+		default:
+			v := r.policyVar(c.Key)
+			if sameValue(v.stringFrom(ctx.Lookup(v.Name)), c.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// check returns why value breaks the policy, or nil
+func (p Policy) check(value string) error {
+	if p.Check != nil {
+		return p.Check(value)
+	}
+	switch p.Op {
+	case PolicyEquals:
+		if !sameValue(value, p.Value) {
+			return fmt.Errorf("must be %s", p.Value)
+		}
+	case PolicyNotEquals:
+		if sameValue(value, p.Value) {
+			return fmt.Errorf("must not be %s", p.Value)
+		}
+	case PolicyMatches:
+		re, err := regexp.Compile(p.Value)
+		if err != nil {
+			return fmt.Errorf("has an invalid pattern %q: %w", p.Value, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match %s", p.Value)
+		}
+	case PolicyOneOf:
+		for _, allowed := range strings.Split(p.Value, ",") {
+			if sameValue(value, strings.TrimSpace(allowed)) {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.ReplaceAll(p.Value, ",", ", "))
+	case PolicyRequired:
+		if value == "" {
+			return errors.New("must be set")
+		}
+	default:
+		return fmt.Errorf("has an unknown policy operator %q", p.Op)
+	}
+	return nil
+}
+
+// violationMessage explains a violation, e.g.
+// `DEBUG [ask @platform] must be false in production`; CheckPolicies appends
+// the value of a non-secret variable, e.g. ` (is "true")`
+func (r *Registry) violationMessage(p Policy, v EnvVar, ctx PolicyContext, value string, err error) string {
+	msg := err.Error()
+	if p.Message != "" {
+		msg = p.Message
+	}
+	if ctx.Environment != "" {
+		msg += " in " + ctx.Environment
+	}
+	for _, c := range p.Unless {
+		msg += " unless " + c.String()
+	}
+	if value == "" {
+		msg += " (unset)"
+	}
+	return r.withOwner(v, v.Name) + " " + msg
+}
+
+// sameValue compares values, booleans by meaning
+func sameValue(a, b string) bool {
+	if isBoolString(a) && isBoolString(b) {
+		return (&EnvVar{}).boolFrom(a) == (&EnvVar{}).boolFrom(b)
+	}
+	return a == b
+}
+
+// ParsePolicies reads policies, one per line:
+//
+//	# environments      rule                                  [-- message]
+//	production          DEBUG == false
+//	production          HTTPS_ENABLED == true unless platform == fly
+//	production,staging  APP_URL =~ ^https://                 -- must be an https URL
+//	*                   LOG_LEVEL in debug,info,warn,error
+//	production          SENTRY_DSN required
+//
+// Environments are comma-separated ("*" for all). Several "unless" clauses
+// may follow the rule; "=" is accepted for "==" in them. Blank lines and
+// # comments are skipped.
+func ParsePolicies(rd io.Reader) ([]Policy, error) {
+	var policies []Policy
+	scanner := bufio.NewScanner(rd)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p, err := parsePolicy(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		policies = append(policies, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func parsePolicy(line string) (Policy, error) {
+	var p Policy
+	line, p.Message, _ = strings.Cut(line, " -- ")
+	p.Message = strings.TrimSpace(p.Message)
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return p, errors.New("want <environments> <variable> <op> [value] [unless <key> == <value>]")
+	}
+	p.Environments = strings.Split(fields[0], ",")
+	p.Variable = fields[1]
+	p.Op = PolicyOp(fields[2])
+	rest := fields[3:]
+
+	switch p.Op {
+	case PolicyRequired:
+	case PolicyEquals, PolicyNotEquals, PolicyMatches, PolicyOneOf:
+		if len(rest) == 0 || rest[0] == "unless" {
+			return p, fmt.Errorf("%s needs a value", p.Op)
+		}
+		p.Value, rest = rest[0], rest[1:]
+		if p.Op == PolicyMatches {
+			if _, err := regexp.Compile(p.Value); err != nil {
+				return p, fmt.Errorf("invalid pattern %q: %w", p.Value, err)
+			}
+		}
+	default:
+		return p, fmt.Errorf("unknown operator %q (want ==, !=, =~, in or required)", p.Op)
+	}
+
+	for len(rest) > 0 {
+		if len(rest) < 4 || rest[0] != "unless" || (rest[2] != "==" && rest[2] != "=") {
+			return p, fmt.Errorf("unexpected %q (want unless <key> == <value>)", strings.Join(rest, " "))
+		}
+		p.Unless = append(p.Unless, Condition{Key: rest[1], Value: rest[3]})
+		rest = rest[4:]
+	}
+	return p, nil
+}
@@ -0,0 +1,112 @@
+package env
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_CheckPolicies(t *testing.T) {
+	r := NewRegistry([]EnvVar{
+		{Name: "DEBUG", Default: "false"},
+		{Name: "HTTPS_ENABLED", Default: "false"},
+		{Name: "LOG_LEVEL", Default: "info"},
+		{Name: "API_SECRET", Secret: true},
+	}, Owners(OwnerRule{Pattern: "DEBUG", Ownership: Ownership{Owner: "@platform"}}), Policies(
+		Policy{Environments: []string{"production"}, Variable: "DEBUG", Op: PolicyEquals, Value: "false"},
+		Policy{
+			Environments: []string{"production"}, Variable: "HTTPS_ENABLED", Op: PolicyEquals, Value: "true",
+			Unless: []Condition{{Key: ConditionPlatform, Value: "fly"}},
+		},
+		Policy{Variable: "LOG_LEVEL", Op: PolicyOneOf, Value: "debug,info,warn,error"},
+		Policy{Environments: []string{"production"}, Variable: "API_SECRET", Check: func(v string) error {
+			if len(v) < 8 {
+				return errors.New("must be at least 8 characters")
+			}
+			return nil
+		}},
+	))
+
+	values := map[string]string{"DEBUG": "1", "LOG_LEVEL": "verbose", "API_SECRET": "short"}
+	lookup := func(name string) string { return values[name] }
+
+	// Local: only the environment-independent LOG_LEVEL policy applies
+	local := r.CheckPolicies(PolicyContext{Environment: "local", Lookup: lookup})
+	if len(local) != 1 || local[0].Variable != "LOG_LEVEL" {
+		t.Fatalf("local violations = %+v, want LOG_LEVEL only", local)
+	}
+
+	prod := r.CheckPolicies(PolicyContext{Environment: "production", Platform: "docker", Lookup: lookup})
+	want := []string{
+		`DEBUG [ask @platform] must be false in production (is "1")`,
+		`HTTPS_ENABLED must be true in production unless platform == fly (is "false")`,
+		`LOG_LEVEL must be one of debug, info, warn, error in production (is "verbose")`,
+		`API_SECRET must be at least 8 characters in production`,
+	}
+	if len(prod) != len(want) {
+		t.Fatalf("production violations = %+v, want %d", prod, len(want))
+	}
+	for i, msg := range want {
+		if prod[i].Message != msg {
+			t.Errorf("violation %d = %q, want %q", i, prod[i].Message, msg)
+		}
+	}
+	if prod[0].Rule != "DEBUG == false" {
+		t.Errorf("Rule = %q", prod[0].Rule)
+	}
+	if got := prod[0].Redacted().Message; got != `DEBUG [ask @platform] must be false in production` {
+		t.Errorf("Redacted() = %q, want the value left out", got)
+	}
+	if got := prod[3].Redacted().Message; got != prod[3].Message {
+		t.Errorf("Redacted() = %q, want a message without a value unchanged", got)
+	}
+
+	// Fly.io terminates TLS, so HTTPS_ENABLED is exempt there
+	for _, v := range r.CheckPolicies(PolicyContext{Environment: "production", Platform: "fly.io", Lookup: lookup}) {
+		if v.Variable == "HTTPS_ENABLED" {
+			t.Errorf("HTTPS_ENABLED should be exempt on fly.io: %s", v)
+		}
+	}
+
+	values = map[string]string{"DEBUG": "no", "HTTPS_ENABLED": "true", "API_SECRET": "long-enough"}
+	if err := r.ValidatePolicies(PolicyContext{Environment: "production", Lookup: lookup}); err != nil {
+		t.Errorf("ValidatePolicies() = %v, want nil", err)
+	}
+}
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies(strings.NewReader(`
+# Production hardening
+production          DEBUG == false
+production          HTTPS_ENABLED == true unless platform = fly unless DEV_TLS == true
+production,staging  APP_URL =~ ^https://   -- must be an https URL
+*                   SENTRY_DSN required
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicies: %v", err)
+	}
+	if len(policies) != 4 {
+		t.Fatalf("got %d policies, want 4: %+v", len(policies), policies)
+	}
+	if p := policies[1]; len(p.Unless) != 2 || p.Unless[0] != (Condition{Key: "platform", Value: "fly"}) || p.Unless[1].Key != "DEV_TLS" {
+		t.Errorf("unless = %+v", p.Unless)
+	}
+	if p := policies[2]; len(p.Environments) != 2 || p.Op != PolicyMatches || p.Value != "^https://" || p.Message != "must be an https URL" {
+		t.Errorf("policy 2 = %+v", p)
+	}
+	if p := policies[3]; p.Op != PolicyRequired || p.Rule() != "SENTRY_DSN required" {
+		t.Errorf("policy 3 = %+v", p)
+	}
+
+	for _, bad := range []string{
+		"production DEBUG",
+		"production DEBUG ~= true",
+		"production DEBUG == unless platform == fly",
+		"production APP_URL =~ (",
+		"production DEBUG == false unless platform",
+	} {
+		if _, err := ParsePolicies(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParsePolicies(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package env provides shell prompt integration showing the active environment.
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ================================================================
+// Environment Status
+// ================================================================
+// A one-line summary of which environment the shell is pointed at and
+// whether its .env file is out of sync with the registry, for prompts
+// (see GeneratePromptSnippet) and scripts.
+
+// EnvStatus is the active environment and the drift of its file.
+type EnvStatus struct {
+	Name  string // $APP_ENV, or "local"
+	File  string // Environment file checked (.env.<name>, .env.local for local)
+	Found bool   // File exists
+	Drift bool   // File misses registry variables or has unknown ones
+}
+
+// Status reports the environment selected by APP_ENV and whether its file has
+// drifted. Values that differ from defaults are not drift: environment files
+// are expected to override them.
+//
+// Example:
+//
+//	fmt.Println(registry.Status().Quiet()) // "production" or "production drift"
+func (r *Registry) Status() EnvStatus {
+	s := EnvStatus{Name: os.Getenv("APP_ENV")}
+	if s.Name == "" {
+		s.Name = "local"
+	}
+	s.File = ".env." + s.Name
+
+	diff, err := DiffRegistry(r, s.File)
+	if err != nil {
+		return s
+	}
+	s.Found = true
+	s.Drift = len(diff.Missing) > 0 || len(diff.Extra) > 0
+	return s
+}
+
+// Quiet renders the status as the prompt snippet expects it: the environment
+// name, followed by " drift" or " missing" when the file needs attention.
+func (s EnvStatus) Quiet() string {
+	switch {
+	case !s.Found:
+		return s.Name + " missing"
+	case s.Drift:
+		return s.Name + " drift"
+	}
+	return s.Name
+}
+
+// String describes the status for humans
+func (s EnvStatus) String() string {
+	switch {
+	case !s.Found:
+		return fmt.Sprintf("Environment: %s (%s not found)", s.Name, s.File)
+	case s.Drift:
+		return fmt.Sprintf("Environment: %s (%s has drifted from the registry)", s.Name, s.File)
+	}
+	return fmt.Sprintf("Environment: %s (%s in sync)", s.Name, s.File)
+}
+
+// ================================================================
+// Prompt Snippet
+// ================================================================
+
+// PromptOptions configures GeneratePromptSnippet.
+type PromptOptions struct {
+	Command  string // Prints EnvStatus.Quiet (default: "wellknown env status --quiet")
+	FuncName string // Shell function to put in PS1 (default: "env_ps1")
+	CacheTTL int    // Seconds a cached status is reused in the same directory (default: 30)
+}
+
+// GeneratePromptSnippet generates a bash/zsh function printing the active
+// environment for the prompt, colored by risk (production red, staging
+// yellow, others green) and marked when the file has drifted - like kube-ps1
+// for .env files. The status command runs at most once per CacheTTL per
+// directory (cached in $TMPDIR), so the prompt stays fast.
+//
+// Example:
+//
+//	eval "$(wellknown env prompt)"
+//	PS1='$(env_ps1) '$PS1                 # bash
+//	PROMPT='$(env_ps1) '$PROMPT           # zsh (setopt prompt_subst)
+func GeneratePromptSnippet(opts PromptOptions) string {
+	if opts.Command == "" {
+		opts.Command = "wellknown env status --quiet"
+	}
+	if opts.FuncName == "" {
+		opts.FuncName = "env_ps1"
+	}
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = 30
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Generated environment prompt - load with: eval \"$(wellknown env prompt)\"\n")
+	sb.WriteString(fmt.Sprintf("# Add $(%s) to PS1 (bash) or PROMPT (zsh, with setopt prompt_subst).\n", opts.FuncName))
+	sb.WriteString(fmt.Sprintf("%s() {\n", opts.FuncName))
+	// PS1 runs the function in a subshell, so the cache lives in a file
+	sb.WriteString("  local cache now at key st color\n")
+	sb.WriteString("  cache=\"${TMPDIR:-/tmp}/env_ps1.${USER:-$(id -u)}\"\n")
+	sb.WriteString("  now=$(date +%s)\n")
+	sb.WriteString("  [ -r \"$cache\" ] && { read -r at; read -r key; read -r st; } < \"$cache\"\n")
+	sb.WriteString(fmt.Sprintf("  if [ \"$key\" != \"$PWD:$APP_ENV\" ] || [ $((now - ${at:-0})) -ge %d ]; then\n", opts.CacheTTL))
+	sb.WriteString(fmt.Sprintf("    st=$(%s 2>/dev/null)\n", opts.Command))
+	sb.WriteString("    printf '%s\\n%s\\n%s\\n' \"$now\" \"$PWD:$APP_ENV\" \"$st\" > \"$cache\" 2>/dev/null\n")
+	sb.WriteString("  fi\n")
+	sb.WriteString("  [ -z \"$st\" ] && return 0\n")
+	sb.WriteString("  case \"$st\" in\n")
+	sb.WriteString("    prod*) color=31 ;;\n")
+	sb.WriteString("    stag*) color=33 ;;\n")
+	sb.WriteString("    *) color=32 ;;\n")
+	sb.WriteString("  esac\n")
+	sb.WriteString("  case \"$st\" in\n")
+	sb.WriteString("    *\" drift\") st=\"${st% drift} ⚠\" ;;\n")
+	sb.WriteString("    *\" missing\") st=\"${st% missing} ?\" ;;\n")
+	sb.WriteString("  esac\n")
+	// Color codes must be marked zero-width or line editing miscounts the prompt
+	sb.WriteString("  if [ -n \"$ZSH_VERSION\" ]; then\n")
+	sb.WriteString("    printf '%%{\\033[%sm%%}(env:%s)%%{\\033[0m%%}' \"$color\" \"$st\"\n")
+	sb.WriteString("  else\n")
+	sb.WriteString("    printf '\\001\\033[%sm\\002(env:%s)\\001\\033[0m\\002' \"$color\" \"$st\"\n")
+	sb.WriteString("  fi\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,66 @@
+package env
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Status(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	registry := NewRegistry([]EnvVar{
+		{Name: "PORT", Default: "8080"},
+		{Name: "API_KEY", Secret: true},
+	})
+
+	t.Setenv("APP_ENV", "")
+	if got := registry.Status().Quiet(); got != "local missing" {
+		t.Errorf("no file: Quiet() = %q", got)
+	}
+
+	os.WriteFile(filepath.Join(dir, ".env.local"), []byte("PORT=9090\nAPI_KEY=x\n"), 0600)
+	if got := registry.Status().Quiet(); got != "local" {
+		t.Errorf("overridden default should not be drift: Quiet() = %q", got)
+	}
+
+	t.Setenv("APP_ENV", "production")
+	os.WriteFile(filepath.Join(dir, ".env.production"), []byte("PORT=8080\nOLD_VAR=1\n"), 0600)
+	s := registry.Status()
+	if got := s.Quiet(); got != "production drift" {
+		t.Errorf("drifted file: Quiet() = %q", got)
+	}
+	if !strings.Contains(s.String(), ".env.production") {
+		t.Errorf("String() = %q", s.String())
+	}
+}
+
+func TestGeneratePromptSnippet(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+
+	t.Setenv("TMPDIR", t.TempDir())
+	snippet := GeneratePromptSnippet(PromptOptions{Command: "echo production drift", FuncName: "my_ps1"})
+	out, err := exec.Command(bash, "-c", snippet+"\nmy_ps1").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); got != "\001\033[31m\002(env:production ⚠)\001\033[0m\002" {
+		t.Errorf("prompt = %q", got)
+	}
+
+	// The status command is cached between prompts (each runs in a subshell)
+	t.Setenv("TMPDIR", t.TempDir())
+	counter := filepath.Join(t.TempDir(), "calls")
+	snippet = GeneratePromptSnippet(PromptOptions{Command: "echo x >> " + counter + "; echo local"})
+	if _, err := exec.Command(bash, "-c", snippet+"\necho $(env_ps1) $(env_ps1) $(env_ps1)").Output(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(counter); strings.Count(string(data), "x") != 1 {
+		t.Errorf("status command ran %d times, want 1", strings.Count(string(data), "x"))
+	}
+}
@@ -0,0 +1,121 @@
+// Package env provides secret redaction for subprocess output.
+package env
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+)
+
+// MinRedactLength is the shortest secret value that gets masked.
+// Shorter values ("1", "yes") would mangle unrelated output.
+const MinRedactLength = 4
+
+// Redactor masks secret values in everything written through it.
+// Create one with RedactingWriter; it is safe for concurrent use, so the
+// same Redactor can back both Stdout and Stderr of a command.
+type Redactor struct {
+	mu      sync.Mutex
+	w       io.Writer
+	secrets map[string]string // value -> variable name
+	values  []string          // secret values, longest first
+	pending []byte            // tail that may be the start of a secret
+}
+
+// RedactingWriter wraps w so that the current values of the registry's secret
+// variables are replaced with [REDACTED:NAME] before reaching w. A secret split
+// across writes is still caught: output that could be the start of a secret is
+// held back until the next write or Flush. A nil registry masks nothing until
+// values are added with AddSecret.
+//
+// Example:
+//
+//	out := env.RedactingWriter(os.Stdout, registry)
+//	defer out.Flush()
+//	cmd.Stdout, cmd.Stderr = out, out
+func RedactingWriter(w io.Writer, registry *Registry) *Redactor {
+	r := &Redactor{w: w, secrets: make(map[string]string)}
+	if registry != nil {
+		for _, v := range registry.GetSecrets() {
+			r.AddSecret(v.Name, registry.Getenv(v.Name))
+		}
+	}
+	return r
+}
+
+// AddSecret registers an additional value to mask (e.g., secrets loaded from a file
+// that aren't in the process environment). Values shorter than MinRedactLength are ignored.
+func (r *Redactor) AddSecret(name, value string) {
+	if len(value) < MinRedactLength {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.secrets[value]; !exists {
+		r.values = append(r.values, value)
+		// Longest first so a secret containing another is masked whole
+		sort.Slice(r.values, func(i, j int) bool { return len(r.values[i]) > len(r.values[j]) })
+	}
+	r.secrets[value] = name
+}
+
+// Write masks secrets in p and forwards the result. It always reports len(p)
+// bytes written on success, since the output length differs from the input.
+func (r *Redactor) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := r.redact(append(r.pending, p...))
+	hold := r.partialSuffix(data)
+	r.pending = append([]byte(nil), data[len(data)-hold:]...)
+
+	if _, err := r.w.Write(data[:len(data)-hold]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any held-back output. Call it after the command exits.
+func (r *Redactor) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil
+	}
+	data := r.pending
+	r.pending = nil
+	_, err := r.w.Write(data)
+	return err
+}
+
+// redact replaces every known secret value in data
+func (r *Redactor) redact(data []byte) []byte {
+	for _, value := range r.values {
+		if bytes.Contains(data, []byte(value)) {
+			data = bytes.ReplaceAll(data, []byte(value), []byte("[REDACTED:"+r.secrets[value]+"]"))
+		}
+	}
+	return data
+}
+
+// partialSuffix returns the length of the longest suffix of data that is a
+// proper prefix of some secret (and so must be held back)
+func (r *Redactor) partialSuffix(data []byte) int {
+	longest := 0
+	for _, value := range r.values {
+		max := len(value) - 1
+		if max > len(data) {
+			max = len(data)
+		}
+		for n := max; n > longest; n-- {
+			if bytes.HasSuffix(data, []byte(value[:n])) {
+				longest = n
+				break
+			}
+		}
+	}
+	return longest
+}
@@ -0,0 +1,63 @@
+package env
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactingWriter_MasksRegistrySecrets(t *testing.T) {
+	t.Setenv("TEST_REDACT_TOKEN", "tok_abcdef123")
+	t.Setenv("TEST_REDACT_PORT", "8080")
+	registry := NewRegistry([]EnvVar{
+		{Name: "TEST_REDACT_TOKEN", Secret: true},
+		{Name: "TEST_REDACT_PORT"},
+	})
+
+	var buf bytes.Buffer
+	w := RedactingWriter(&buf, registry)
+	w.Write([]byte("token=tok_abcdef123 port=8080\n"))
+	w.Flush()
+
+	want := "token=[REDACTED:TEST_REDACT_TOKEN] port=8080\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRedactingWriter_SplitAcrossWrites(t *testing.T) {
+	var buf bytes.Buffer
+	w := RedactingWriter(&buf, nil)
+	w.AddSecret("API_KEY", "supersecret")
+
+	for _, chunk := range []string{"key: sup", "ers", "ecret done", "\nsup"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), "supersecret") {
+			t.Fatalf("secret leaked mid-stream: %q", buf.String())
+		}
+	}
+	// "sup" is held back until Flush since it might start the secret
+	if strings.HasSuffix(buf.String(), "sup") {
+		t.Errorf("expected partial prefix to be held back, got %q", buf.String())
+	}
+	w.Flush()
+
+	want := "key: [REDACTED:API_KEY] done\nsup"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRedactingWriter_IgnoresShortValues(t *testing.T) {
+	var buf bytes.Buffer
+	w := RedactingWriter(&buf, nil)
+	w.AddSecret("FLAG", "1")
+	w.Write([]byte("exit 1\n"))
+	w.Flush()
+
+	if buf.String() != "exit 1\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+}
@@ -0,0 +1,193 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ================================================================
+// Markdown Configuration Reference
+// ================================================================
+// A CONFIGURATION.md generated from the registry, so the documented
+// variables never drift from the code. Only the part between the markers is
+// generated; text around it (intro, links, examples) is kept on resync.
+
+// Markers around the generated part of the configuration reference
+const (
+	ReferenceStartMarker = "<!-- BEGIN GENERATED CONFIGURATION REFERENCE (do not edit between markers) -->"
+	ReferenceEndMarker   = "<!-- END GENERATED CONFIGURATION REFERENCE -->"
+)
+
+// DefaultReferenceFile is the conventional name of the configuration reference
+const DefaultReferenceFile = "CONFIGURATION.md"
+
+// GenerateMarkdownReference returns a complete CONFIGURATION.md: a title, a
+// short introduction and the generated section (see GenerateMarkdownReferenceSection)
+func (r *Registry) GenerateMarkdownReference() string {
+	var sb strings.Builder
+	sb.WriteString("# Configuration Reference\n\n")
+	sb.WriteString("Environment variables read by the application, by group.\n")
+	sb.WriteString("Required variables must be set; the others fall back to their default.\n")
+	sb.WriteString("Secret variables belong in the secrets files or the deployment platform's secret store, never in committed files.\n\n")
+	sb.WriteString(r.GenerateMarkdownReferenceSection())
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// GenerateMarkdownReferenceSection returns the generated part of the
+// reference, markers included: a summary and one table per group (name,
+// type, default, required, secret, description), groups and variables sorted
+func (r *Registry) GenerateMarkdownReferenceSection() string {
+	var sb strings.Builder
+	sb.WriteString(ReferenceStartMarker + "\n\n")
+
+	vars := r.All()
+	required, secrets := 0, 0
+	for _, v := range vars {
+		if v.Required {
+			required++
+		}
+		if v.Secret {
+			secrets++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%d variables (%d required, %d secret).\n", len(vars), required, secrets))
+
+	groups := r.GetByGroup()
+	groupNames := make([]string, 0, len(groups))
+	for name := range groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+
+	for _, groupName := range groupNames {
+		groupVars := append([]EnvVar(nil), groups[groupName]...)
+		sort.Slice(groupVars, func(i, j int) bool { return groupVars[i].Name < groupVars[j].Name })
+
+		title := groupName
+		if title == "" {
+			title = "General"
+		}
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", title))
+		if owners := r.groupOwners(groupVars); len(owners) > 0 {
+			sb.WriteString("Owner: " + markdownCell(strings.Join(owners, ", ")) + "\n\n")
+		}
+		sb.WriteString("| Name | Type | Default | Required | Secret | Description |\n")
+		sb.WriteString("|------|------|---------|----------|--------|-------------|\n")
+		for _, v := range groupVars {
+			def := "-"
+			if v.Default != "" && !v.Secret {
+				def = "`" + markdownCell(v.Default) + "`"
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s |\n",
+				v.Name, v.typeName(), def, yesNo(v.Required), yesNo(v.Secret), markdownCell(strings.Join(strings.Fields(v.Description), " "))))
+		}
+	}
+
+	sb.WriteString("\n" + ReferenceEndMarker)
+	return sb.String()
+}
+
+// groupOwners returns the distinct owners of vars, in order
+func (r *Registry) groupOwners(vars []EnvVar) []string {
+	var owners []string
+	seen := make(map[string]bool)
+	for _, v := range vars {
+		if o, ok := r.ownerOf(v); ok && !seen[o.String()] {
+			seen[o.String()] = true
+			owners = append(owners, o.String())
+		}
+	}
+	return owners
+}
+
+// SyncMarkdownReference keeps the reference at path up to date: the whole
+// file is written when it does not exist, else only the generated section
+func (r *Registry) SyncMarkdownReference(path string, dryRun bool) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		content := r.GenerateMarkdownReference()
+		if dryRun {
+			fmt.Printf("=== Dry Run: %s ===\n%s=== End Dry Run ===\n", path, content)
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", path, err)
+		}
+		return nil
+	}
+	return SyncFileSection(SyncOptions{
+		FilePath:    path,
+		StartMarker: ReferenceStartMarker,
+		EndMarker:   ReferenceEndMarker,
+		Content:     r.GenerateMarkdownReferenceSection(),
+		DryRun:      dryRun,
+	})
+}
+
+// ReferenceEntry is one variable of the configuration reference as data, for
+// JSON consumers such as the MCP env resource (EnvVar itself holds a func)
+type ReferenceEntry struct {
+	Name        string `json:"name"`
+	Group       string `json:"group"` // "General" for ungrouped variables
+	Type        string `json:"type"`
+	Default     string `json:"default,omitempty"` // Not published for secrets
+	Required    bool   `json:"required"`
+	Secret      bool   `json:"secret"`
+	Description string `json:"description"`
+	Owner       string `json:"owner,omitempty"` // See OwnerOf
+	Contact     string `json:"contact,omitempty"`
+}
+
+// Reference returns the same information as GenerateMarkdownReferenceSection,
+// sorted by group, then name
+func (r *Registry) Reference() []ReferenceEntry {
+	vars := r.All()
+	entries := make([]ReferenceEntry, 0, len(vars))
+	for _, v := range vars {
+		entry := ReferenceEntry{
+			Name:        v.Name,
+			Group:       v.Group,
+			Type:        v.typeName(),
+			Required:    v.Required,
+			Secret:      v.Secret,
+			Description: strings.Join(strings.Fields(v.Description), " "),
+		}
+		if entry.Group == "" {
+			entry.Group = "General"
+		}
+		if !v.Secret {
+			entry.Default = v.Default
+		}
+		if o, ok := r.ownerOf(v); ok {
+			entry.Owner, entry.Contact = o.Owner, o.Contact
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Group != entries[j].Group {
+			return entries[i].Group < entries[j].Group
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// typeName is the type the variable is read as: its Kind, else the type
+// implied by its default (see ValidateValue)
+func (e EnvVar) typeName() string {
+	if e.Kind != "" {
+		return string(e.Kind)
+	}
+	if e.Default != "" {
+		if _, err := strconv.Atoi(e.Default); err == nil {
+			return "int"
+		}
+		if isBoolString(e.Default) {
+			return "bool"
+		}
+	}
+	return "string"
+}
@@ -0,0 +1,102 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_GenerateMarkdownReference(t *testing.T) {
+	r := NewRegistry([]EnvVar{
+		{Name: "SERVER_PORT", Description: "HTTP port", Default: "8080", Group: "Server"},
+		{Name: "DEBUG", Description: "Verbose | chatty\nlogging", Default: "false", Group: "Server"},
+		{Name: "API_KEY", Description: "Upstream key", Required: true, Secret: true, Default: "dev-key", Group: "Auth"},
+		{Name: "MISC", Description: "Ungrouped"},
+	})
+
+	doc := r.GenerateMarkdownReference()
+	for _, want := range []string{
+		"# Configuration Reference",
+		ReferenceStartMarker,
+		ReferenceEndMarker,
+		"4 variables (1 required, 1 secret).",
+		"## Auth\n",
+		"## General\n",
+		"| `SERVER_PORT` | int | `8080` | no | no | HTTP port |",
+		"| `DEBUG` | bool | `false` | no | no | Verbose \\| chatty logging |",
+		"| `API_KEY` | string | - | yes | yes | Upstream key |", // Secret defaults are not published
+		"| `MISC` | string | - | no | no | Ungrouped |",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("reference missing %q:\n%s", want, doc)
+		}
+	}
+	if strings.Index(doc, "## Auth") > strings.Index(doc, "## Server") {
+		t.Error("groups should be sorted")
+	}
+	if strings.Index(doc, "`DEBUG`") > strings.Index(doc, "`SERVER_PORT`") {
+		t.Error("variables should be sorted within a group")
+	}
+}
+
+func TestRegistry_Reference(t *testing.T) {
+	r := NewRegistry([]EnvVar{
+		{Name: "SERVER_PORT", Description: "HTTP port", Default: "8080", Group: "Server"},
+		{Name: "API_KEY", Description: "Upstream\nkey", Secret: true, Default: "dev-key", Group: "Auth"},
+		{Name: "MISC", Description: "Ungrouped", Kind: KindURL},
+	})
+
+	entries := r.Reference()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if entries[0].Name != "API_KEY" || entries[1].Name != "MISC" || entries[2].Name != "SERVER_PORT" {
+		t.Errorf("entries should be sorted by group, then name: %+v", entries)
+	}
+	if key := entries[0]; key.Default != "" || !key.Secret || key.Description != "Upstream key" {
+		t.Errorf("API_KEY = %+v, want secret without default and a one-line description", key)
+	}
+	if misc := entries[1]; misc.Group != "General" || misc.Type != "url" {
+		t.Errorf("MISC = %+v, want group General and type url", misc)
+	}
+	if port := entries[2]; port.Default != "8080" || port.Type != "int" {
+		t.Errorf("SERVER_PORT = %+v, want default 8080 and type int", port)
+	}
+}
+
+func TestRegistry_SyncMarkdownReference(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultReferenceFile)
+	r := NewRegistry([]EnvVar{{Name: "A", Description: "first"}})
+
+	// Created when missing
+	if err := r.SyncMarkdownReference(path, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hand-written text around the section survives a resync
+	data, _ := os.ReadFile(path)
+	edited := strings.Replace(string(data), "# Configuration Reference", "# Config\n\nSee also the runbook.", 1)
+	os.WriteFile(path, []byte(edited), 0644)
+
+	if err := r.Add(EnvVar{Name: "B", Description: "second"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SyncMarkdownReference(path, false); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "See also the runbook.") || !strings.Contains(string(data), "| `B` |") {
+		t.Errorf("unexpected reference after resync:\n%s", data)
+	}
+
+	upToDate, err := SectionUpToDate(SyncOptions{
+		FilePath:    path,
+		StartMarker: ReferenceStartMarker,
+		EndMarker:   ReferenceEndMarker,
+		Content:     r.GenerateMarkdownReferenceSection(),
+	})
+	if err != nil || !upToDate {
+		t.Errorf("expected reference to be up to date (%v)", err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // EnvVar represents an environment variable definition with metadata.
@@ -19,38 +21,127 @@ type EnvVar struct {
 	Secret      bool   // Should this be treated as a secret (masked in logs, etc.)?
 	Default     string // Default value (empty string if no default)
 	Group       string // Logical grouping for organization (e.g., "Server", "OAuth")
+	Kind        Kind   // Value syntax: duration, bytes, url, hostport, path (optional; see Kind)
+	Owner       string // Who to ask about it (optional; overrides the registry's Owners rules)
+	Contact     string // How to reach Owner, e.g. a channel or email (optional)
+
+	// Validate checks a value's format (optional; see ValidateValue). Not
+	// encoded: the /env JSON and exports carry the other fields only.
+	Validate func(value string) error `json:"-"`
 }
 
 // Registry holds a collection of environment variables and provides lookup/filtering operations.
+//
+// A Registry is safe for concurrent use. Reads work on an immutable snapshot
+// without locking; Add, Remove and Override copy the snapshot, modify the copy
+// and swap it in (copy-on-write), then notify subscribers.
 type Registry struct {
-	vars  []EnvVar
-	index map[string]*EnvVar // Fast lookup by name
+	snap atomic.Pointer[registrySnapshot]
+	env  atomic.Pointer[EnvSnapshot] // Cached process environment (see CacheEnvironment)
+
+	prefix   string      // Applied to every name (see Prefix)
+	owners   []OwnerRule // Ownership rules, last match wins (see Owners)
+	policies []Policy    // Value constraints per environment (see Policies)
+
+	mu          sync.Mutex // Serializes writers and guards subscribers
+	subscribers map[int]func(RegistryChange)
+	nextSubID   int
 }
 
-// NewRegistry creates a new environment variable registry from a slice of EnvVar.
-func NewRegistry(vars []EnvVar) *Registry {
-	r := &Registry{
-		vars:  vars,
-		index: make(map[string]*EnvVar, len(vars)),
+// registrySnapshot is an immutable view of the registry contents
+type registrySnapshot struct {
+	vars    []EnvVar
+	index   map[string]*EnvVar // Fast lookup by name
+	version uint64
+}
+
+// newSnapshot builds a snapshot (and its index) from vars
+func newSnapshot(vars []EnvVar, version uint64) *registrySnapshot {
+	s := &registrySnapshot{
+		vars:    vars,
+		index:   make(map[string]*EnvVar, len(vars)),
+		version: version,
 	}
 
 	// Build index for O(1) lookup
-	for i := range r.vars {
-		r.index[r.vars[i].Name] = &r.vars[i]
+	for i := range s.vars {
+		s.index[s.vars[i].Name] = &s.vars[i]
+	}
+
+	return s
+}
+
+// RegistryOption configures a Registry (see NewRegistry)
+type RegistryOption func(*Registry)
+
+// Prefix namespaces the registry for twelve-factor apps: libraries define
+// logical names ("PORT") and the consuming app picks the concrete ones.
+// Every registered name gets the prefix ("MYAPP_PORT"), so getters read,
+// validation checks and generators write the prefixed variables, while
+// ByName, Remove and Overlay accept either form. Names that already start
+// with the prefix are kept as they are.
+//
+//	registry := env.NewRegistry(lib.EnvVars, env.Prefix("MYAPP_"))
+//	registry.ByName("PORT").GetInt() // reads $MYAPP_PORT
+func Prefix(prefix string) RegistryOption {
+	return func(r *Registry) {
+		r.prefix = prefix
 	}
+}
 
+// NewRegistry creates a new environment variable registry from a slice of EnvVar.
+func NewRegistry(vars []EnvVar, opts ...RegistryOption) *Registry {
+	r := &Registry{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.snap.Store(newSnapshot(r.applyPrefix(vars), 0))
 	return r
 }
 
-// ByName returns the environment variable with the given name, or nil if not found.
+// Prefix returns the prefix applied to every name ("" for none)
+func (r *Registry) Prefix() string {
+	return r.prefix
+}
+
+// EnvName returns the concrete variable name for a logical name: the name
+// with the registry prefix applied (unchanged if it already has it)
+func (r *Registry) EnvName(name string) string {
+	if r.prefix == "" || strings.HasPrefix(name, r.prefix) {
+		return name
+	}
+	return r.prefix + name
+}
+
+// LogicalName returns name with the registry prefix stripped
+func (r *Registry) LogicalName(name string) string {
+	return strings.TrimPrefix(name, r.prefix)
+}
+
+// applyPrefix returns vars with EnvName applied (vars itself without a prefix)
+func (r *Registry) applyPrefix(vars []EnvVar) []EnvVar {
+	if r.prefix == "" {
+		return vars
+	}
+	prefixed := make([]EnvVar, len(vars))
+	for i, v := range vars {
+		v.Name = r.EnvName(v.Name)
+		prefixed[i] = v
+	}
+	return prefixed
+}
+
+// ByName returns the environment variable with the given name (logical or
+// prefixed, see Prefix), or nil if not found.
+// The returned EnvVar belongs to the current snapshot and must not be modified.
 func (r *Registry) ByName(name string) *EnvVar {
-	return r.index[name]
+	return r.snap.Load().index[r.EnvName(name)]
 }
 
 // GetRequired returns all required environment variables.
 func (r *Registry) GetRequired() []EnvVar {
 	var required []EnvVar
-	for _, v := range r.vars {
+	for _, v := range r.All() {
 		if v.Required {
 			required = append(required, v)
 		}
@@ -61,7 +152,7 @@ func (r *Registry) GetRequired() []EnvVar {
 // GetSecrets returns all environment variables marked as secrets.
 func (r *Registry) GetSecrets() []EnvVar {
 	var secrets []EnvVar
-	for _, v := range r.vars {
+	for _, v := range r.All() {
 		if v.Secret {
 			secrets = append(secrets, v)
 		}
@@ -72,21 +163,23 @@ func (r *Registry) GetSecrets() []EnvVar {
 // GetByGroup returns a map of environment variables grouped by their Group field.
 func (r *Registry) GetByGroup() map[string][]EnvVar {
 	groups := make(map[string][]EnvVar)
-	for _, v := range r.vars {
+	for _, v := range r.All() {
 		groups[v.Group] = append(groups[v.Group], v)
 	}
 	return groups
 }
 
 // All returns all environment variables in the registry.
+// The slice is a snapshot: later mutations don't affect it, and it must not be modified.
 func (r *Registry) All() []EnvVar {
-	return r.vars
+	return r.snap.Load().vars
 }
 
 // AllSorted returns all environment variables sorted by group and name.
 func (r *Registry) AllSorted() []EnvVar {
-	sorted := make([]EnvVar, len(r.vars))
-	copy(sorted, r.vars)
+	vars := r.All()
+	sorted := make([]EnvVar, len(vars))
+	copy(sorted, vars)
 
 	sort.Slice(sorted, func(i, j int) bool {
 		if sorted[i].Group != sorted[j].Group {
@@ -103,32 +196,115 @@ func (r *Registry) AllSorted() []EnvVar {
 func (r *Registry) ValidateRequired() error {
 	var missing []string
 	for _, v := range r.GetRequired() {
-		if os.Getenv(v.Name) == "" {
-			missing = append(missing, v.Name)
+		if r.Getenv(v.Name) == "" {
+			missing = append(missing, r.withOwner(v, v.Name))
 		}
 	}
 
 	if len(missing) > 0 {
-		return fmt.Errorf("missing required environment variables: %v", missing)
+		return fmt.Errorf("%w: %s", ErrMissingRequired, strings.Join(missing, ", "))
 	}
 
 	return nil
 }
 
+// ValidateTypes checks that set variables parse as the type of their default:
+// an integer default requires an integer value, a boolean default ("true",
+// "false", ...) a boolean one, and the value must pass EnvVar.Validate.
+// Secret values are never included in the error.
+// Without this, GetInt and GetBool silently fall back to the default.
+func (r *Registry) ValidateTypes() error {
+	var invalid []string
+	for _, v := range r.All() {
+		value := r.Getenv(v.Name)
+		if value == "" {
+			continue
+		}
+		if err := v.ValidateValue(value); err != nil {
+			if v.Secret {
+				invalid = append(invalid, r.withOwner(v, fmt.Sprintf("%s (%v)", v.Name, err)))
+			} else {
+				invalid = append(invalid, r.withOwner(v, fmt.Sprintf("%s=%q (%v)", v.Name, value, err)))
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidValue, strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// ValidateValue checks value against Kind, or else the type implied by
+// Default (integer or boolean), and then the Validate func, if set. Empty
+// values are not checked.
+func (e EnvVar) ValidateValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if e.Kind != "" {
+		if err := e.Kind.Validate(value); err != nil {
+			return err
+		}
+	} else if e.Default != "" {
+		if _, err := strconv.Atoi(e.Default); err == nil {
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("expected integer")
+			}
+		} else if isBoolString(e.Default) && !isBoolString(value) {
+			return fmt.Errorf("expected true/false")
+		}
+	}
+	if e.Validate != nil {
+		return e.Validate(value)
+	}
+	return nil
+}
+
+// isBoolString reports whether s is a value GetBool understands
+func isBoolString(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "1", "yes", "false", "0", "no":
+		return true
+	}
+	return false
+}
+
 // GetString returns the value of the environment variable as a string.
 // If the variable is not set, returns the default value.
 func (e *EnvVar) GetString() string {
-	if value := os.Getenv(e.Name); value != "" {
-		return value
-	}
-	return e.Default
+	recordUsage(e.Name)
+	return e.stringFrom(os.Getenv(e.Name))
 }
 
 // GetInt returns the value of the environment variable as an integer.
 // If the variable is not set or cannot be parsed, returns the default value as an int.
 // If the default cannot be parsed, returns 0.
 func (e *EnvVar) GetInt() int {
-	if value := os.Getenv(e.Name); value != "" {
+	recordUsage(e.Name)
+	return e.intFrom(os.Getenv(e.Name))
+}
+
+// GetBool returns the value of the environment variable as a boolean.
+// Accepts "true", "1", "yes" as true; "false", "0", "no" as false.
+// If the variable is not set or cannot be parsed, returns the default value as a bool.
+// If the default cannot be parsed, returns false.
+func (e *EnvVar) GetBool() bool {
+	recordUsage(e.Name)
+	return e.boolFrom(os.Getenv(e.Name))
+}
+
+// stringFrom returns value, or the default when value is empty
+func (e *EnvVar) stringFrom(value string) string {
+	if value != "" {
+		return value
+	}
+	return e.Default
+}
+
+// intFrom parses value as GetInt does
+func (e *EnvVar) intFrom(value string) int {
+	if value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
 			return intValue
 		}
@@ -145,12 +321,9 @@ func (e *EnvVar) GetInt() int {
 	return 0
 }
 
-// GetBool returns the value of the environment variable as a boolean.
-// Accepts "true", "1", "yes" as true; "false", "0", "no" as false.
-// If the variable is not set or cannot be parsed, returns the default value as a bool.
-// If the default cannot be parsed, returns false.
-func (e *EnvVar) GetBool() bool {
-	if value := os.Getenv(e.Name); value != "" {
+// boolFrom parses value as GetBool does
+func (e *EnvVar) boolFrom(value string) bool {
+	if value != "" {
 		switch strings.ToLower(value) {
 		case "true", "1", "yes":
 			return true
@@ -173,3 +346,152 @@ func (e *EnvVar) GetBool() bool {
 
 	return false
 }
+
+// ChangeKind identifies the type of registry mutation.
+type ChangeKind string
+
+const (
+	ChangeAdded      ChangeKind = "added"
+	ChangeRemoved    ChangeKind = "removed"
+	ChangeOverridden ChangeKind = "overridden"
+)
+
+// RegistryChange describes a single mutation delivered to subscribers.
+type RegistryChange struct {
+	Kind     ChangeKind
+	Var      EnvVar  // The added/overriding definition (the removed one for ChangeRemoved)
+	Previous *EnvVar // Definition before an override (nil otherwise)
+	Version  uint64  // Registry version after the change
+}
+
+// Version returns a counter that increases with every mutation.
+// Useful as a cheap "has anything changed?" check or ETag.
+func (r *Registry) Version() uint64 {
+	return r.snap.Load().version
+}
+
+// Add registers new variables, e.g., from a plugin's init function.
+// Fails without changing anything if any name is already registered or repeated.
+//
+// Example:
+//
+//	func init() {
+//	    AppRegistry.Add(env.EnvVar{Name: "STRIPE_KEY", Secret: true, Group: "Billing"})
+//	}
+func (r *Registry) Add(vars ...EnvVar) error {
+	vars = r.applyPrefix(vars)
+	r.mu.Lock()
+	old := r.snap.Load()
+	seen := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		if v.Name == "" {
+			r.mu.Unlock()
+			return fmt.Errorf("cannot add variable with empty name")
+		}
+		if old.index[v.Name] != nil || seen[v.Name] {
+			r.mu.Unlock()
+			return fmt.Errorf("variable %s is already registered", v.Name)
+		}
+		seen[v.Name] = true
+	}
+
+	next := make([]EnvVar, 0, len(old.vars)+len(vars))
+	next = append(append(next, old.vars...), vars...)
+	snap := newSnapshot(next, old.version+1)
+	r.snap.Store(snap)
+
+	changes := make([]RegistryChange, len(vars))
+	for i, v := range vars {
+		changes[i] = RegistryChange{Kind: ChangeAdded, Var: v, Version: snap.version}
+	}
+	r.publishLocked(changes)
+	return nil
+}
+
+// Remove unregisters a variable. Returns false if it wasn't registered.
+func (r *Registry) Remove(name string) bool {
+	r.mu.Lock()
+	old := r.snap.Load()
+	removed := old.index[r.EnvName(name)]
+	if removed == nil {
+		r.mu.Unlock()
+		return false
+	}
+
+	next := make([]EnvVar, 0, len(old.vars)-1)
+	for _, v := range old.vars {
+		if v.Name != removed.Name {
+			next = append(next, v)
+		}
+	}
+	snap := newSnapshot(next, old.version+1)
+	r.snap.Store(snap)
+
+	r.publishLocked([]RegistryChange{{Kind: ChangeRemoved, Var: *removed, Version: snap.version}})
+	return true
+}
+
+// Override replaces the definition of an already-registered variable
+// (e.g., a plugin changing a default), keeping its position.
+func (r *Registry) Override(v EnvVar) error {
+	v.Name = r.EnvName(v.Name)
+	r.mu.Lock()
+	old := r.snap.Load()
+	previous := old.index[v.Name]
+	if previous == nil {
+		r.mu.Unlock()
+		return fmt.Errorf("variable %s is not registered", v.Name)
+	}
+	prev := *previous
+
+	next := make([]EnvVar, len(old.vars))
+	copy(next, old.vars)
+	for i := range next {
+		if next[i].Name == v.Name {
+			next[i] = v
+		}
+	}
+	snap := newSnapshot(next, old.version+1)
+	r.snap.Store(snap)
+
+	r.publishLocked([]RegistryChange{{Kind: ChangeOverridden, Var: v, Previous: &prev, Version: snap.version}})
+	return nil
+}
+
+// Subscribe registers fn to be called after every mutation.
+// Callbacks run synchronously on the mutating goroutine, after the new snapshot
+// is visible, so they may read the registry but must not block for long.
+// Call the returned function to unsubscribe.
+func (r *Registry) Subscribe(fn func(RegistryChange)) (unsubscribe func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.subscribers == nil {
+		r.subscribers = make(map[int]func(RegistryChange))
+	}
+	id := r.nextSubID
+	r.nextSubID++
+	r.subscribers[id] = fn
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+// publishLocked releases r.mu and then notifies subscribers (outside the lock,
+// so callbacks can call back into the registry)
+func (r *Registry) publishLocked(changes []RegistryChange) {
+	subs := make([]func(RegistryChange), 0, len(r.subscribers))
+	for _, fn := range r.subscribers {
+		subs = append(subs, fn)
+	}
+	r.mu.Unlock()
+
+	for _, change := range changes {
+		for _, fn := range subs {
+			fn(change)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package env
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -409,3 +413,184 @@ func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		len(s) > 0 && (s[0:len(substr)] == substr || contains(s[1:], substr)))
 }
+
+func TestRegistry_AddRemoveOverride(t *testing.T) {
+	registry := NewRegistry([]EnvVar{{Name: "BASE", Default: "1"}})
+	snapshot := registry.All()
+
+	var changes []RegistryChange
+	unsubscribe := registry.Subscribe(func(c RegistryChange) { changes = append(changes, c) })
+
+	if err := registry.Add(EnvVar{Name: "PLUGIN_KEY", Secret: true}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := registry.Add(EnvVar{Name: "BASE"}); err == nil {
+		t.Error("expected error adding duplicate name")
+	}
+	if err := registry.Override(EnvVar{Name: "BASE", Default: "2"}); err != nil {
+		t.Fatalf("Override failed: %v", err)
+	}
+	if err := registry.Override(EnvVar{Name: "MISSING"}); err == nil {
+		t.Error("expected error overriding unknown variable")
+	}
+	if !registry.Remove("PLUGIN_KEY") || registry.Remove("PLUGIN_KEY") {
+		t.Error("Remove should succeed once")
+	}
+
+	// Copy-on-write: earlier snapshots are unaffected
+	if len(snapshot) != 1 || snapshot[0].Default != "1" {
+		t.Errorf("snapshot mutated: %+v", snapshot)
+	}
+	if got := registry.ByName("BASE").Default; got != "2" {
+		t.Errorf("BASE default = %q, want 2", got)
+	}
+	if registry.Version() != 3 {
+		t.Errorf("Version() = %d, want 3", registry.Version())
+	}
+
+	wantKinds := []ChangeKind{ChangeAdded, ChangeOverridden, ChangeRemoved}
+	if len(changes) != len(wantKinds) {
+		t.Fatalf("got %d changes, want %d", len(changes), len(wantKinds))
+	}
+	for i, kind := range wantKinds {
+		if changes[i].Kind != kind {
+			t.Errorf("change %d kind = %s, want %s", i, changes[i].Kind, kind)
+		}
+	}
+	if changes[1].Previous == nil || changes[1].Previous.Default != "1" {
+		t.Errorf("override Previous = %+v", changes[1].Previous)
+	}
+
+	unsubscribe()
+	registry.Add(EnvVar{Name: "AFTER"})
+	if len(changes) != 3 {
+		t.Error("unsubscribed callback was still called")
+	}
+}
+
+func TestRegistry_ConcurrentAdd(t *testing.T) {
+	registry := NewRegistry(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			registry.Add(EnvVar{Name: fmt.Sprintf("VAR_%d", i)})
+			_ = registry.All()
+			_ = registry.ByName("VAR_0")
+		}(i)
+	}
+	wg.Wait()
+
+	if len(registry.All()) != 50 {
+		t.Errorf("got %d vars, want 50", len(registry.All()))
+	}
+}
+
+// Test ValidateTypes against typed defaults
+func TestRegistry_ValidateTypes(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "TYPES_PORT", Default: "8080"},
+		{Name: "TYPES_FLAG", Default: "false"},
+		{Name: "TYPES_NAME", Default: "app"},
+	})
+
+	t.Setenv("TYPES_PORT", "9090")
+	t.Setenv("TYPES_FLAG", "yes")
+	t.Setenv("TYPES_NAME", "anything")
+	if err := registry.ValidateTypes(); err != nil {
+		t.Errorf("ValidateTypes() failed for valid values: %v", err)
+	}
+
+	t.Setenv("TYPES_PORT", "eighty")
+	t.Setenv("TYPES_FLAG", "maybe")
+	err := registry.ValidateTypes()
+	if err == nil {
+		t.Fatal("ValidateTypes() should fail for invalid values")
+	}
+	if !strings.Contains(err.Error(), "TYPES_PORT") || !strings.Contains(err.Error(), "TYPES_FLAG") {
+		t.Errorf("error should name both variables: %v", err)
+	}
+}
+
+// Test EnvVar encodes to JSON with a Validate func set (the /env JSON view)
+func TestEnvVar_JSON(t *testing.T) {
+	v := EnvVar{Name: "JSON_VAR", Validate: func(string) error { return nil }}
+	data, err := json.Marshal([]EnvVar{v})
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"Name":"JSON_VAR"`) || strings.Contains(string(data), "Validate") {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+}
+
+func TestRegistry_Prefix(t *testing.T) {
+	vars := []EnvVar{
+		{Name: "PORT", Default: "8080", Group: "Server"},
+		{Name: "TOKEN", Required: true, Secret: true},
+		{Name: "PFXTEST_DEBUG", Default: "false"},
+	}
+	registry := NewRegistry(vars, Prefix("PFXTEST_"))
+
+	if vars[0].Name != "PORT" {
+		t.Error("NewRegistry must not modify the caller's slice")
+	}
+	if registry.Prefix() != "PFXTEST_" {
+		t.Errorf("Prefix() = %q", registry.Prefix())
+	}
+
+	names := []string{}
+	for _, v := range registry.All() {
+		names = append(names, v.Name)
+	}
+	if got := strings.Join(names, ","); got != "PFXTEST_PORT,PFXTEST_TOKEN,PFXTEST_DEBUG" {
+		t.Errorf("names = %s (already-prefixed names must be kept)", got)
+	}
+	if registry.ByName("PORT") != registry.ByName("PFXTEST_PORT") || registry.ByName("PORT") == nil {
+		t.Error("ByName should accept logical and prefixed names")
+	}
+	if got := registry.LogicalName("PFXTEST_PORT"); got != "PORT" {
+		t.Errorf("LogicalName = %q", got)
+	}
+
+	t.Setenv("PORT", "1111")
+	t.Setenv("PFXTEST_PORT", "9090")
+	if got := registry.ByName("PORT").GetInt(); got != 9090 {
+		t.Errorf("GetInt() = %d, want the prefixed variable's 9090", got)
+	}
+	if err := registry.ValidateRequired(); err == nil || !strings.Contains(err.Error(), "PFXTEST_TOKEN") {
+		t.Errorf("ValidateRequired() = %v, want PFXTEST_TOKEN missing", err)
+	}
+	if list := registry.GenerateEnvList("Test"); !strings.Contains(list, "PFXTEST_PORT") {
+		t.Errorf("generated list should use prefixed names:\n%s", list)
+	}
+
+	if err := registry.Add(EnvVar{Name: "REGION"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Add(EnvVar{Name: "PFXTEST_REGION"}); err == nil {
+		t.Error("Add should detect the duplicate under its prefixed name")
+	}
+	if err := registry.Override(EnvVar{Name: "PORT", Default: "7070"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := registry.ByName("PFXTEST_PORT").Default; got != "7070" {
+		t.Errorf("overridden default = %q", got)
+	}
+	if !registry.Remove("REGION") || registry.ByName("PFXTEST_REGION") != nil {
+		t.Error("Remove should accept the logical name")
+	}
+
+	overlay, err := registry.NewOverlay(map[string]string{"PORT": "6060"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := overlay.GetInt("PFXTEST_PORT"); got != 6060 {
+		t.Errorf("overlay GetInt = %d", got)
+	}
+	if got := strings.Join(overlay.Overrides(), ","); got != "PFXTEST_PORT" {
+		t.Errorf("Overrides() = %s", got)
+	}
+}
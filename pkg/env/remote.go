@@ -0,0 +1,140 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ================================================================
+// Remote Comparison
+// ================================================================
+// Compares which variables are configured on a deployed instance (via the
+// webui /env?format=json endpoint) with the values in a local env file.
+// Only configured/not-configured status is compared - the endpoint never
+// exposes secret values, and none are sent.
+
+// CompareRemoteOptions configures CompareRemoteWithOptions.
+type CompareRemoteOptions struct {
+	URL       string       // Base URL of the deployed app (e.g., "https://my-app.fly.dev")
+	Registry  *Registry    // Local registry (required)
+	LocalFile string       // Local env file to compare (default: Production.FileName)
+	Client    *http.Client // HTTP client (default: 10s timeout)
+}
+
+// RemoteComparison is the result of CompareRemote.
+type RemoteComparison struct {
+	URL           string   // Instance compared against
+	Environment   string   // Environment reported by the instance
+	LocalFile     string   // Local file compared
+	MissingRemote []string // Set locally but not configured remotely
+	MissingLocal  []string // Configured remotely but not set locally
+	UnknownRemote []string // Reported remotely but not in the local registry (registry drift)
+	NotReported   []string // In the local registry but not reported remotely (older deploy)
+	Matching      int      // Variables with the same status on both sides
+}
+
+// HasDifferences returns true if local and remote disagree on anything.
+func (c *RemoteComparison) HasDifferences() bool {
+	return len(c.MissingRemote) > 0 || len(c.MissingLocal) > 0 ||
+		len(c.UnknownRemote) > 0 || len(c.NotReported) > 0
+}
+
+// CompareRemote compares the local .env.production against a deployed instance.
+//
+// Example:
+//
+//	cmp, err := env.CompareRemote("https://my-app.fly.dev", registry)
+//	for _, name := range cmp.MissingRemote {
+//	    fmt.Printf("%s is set locally but not on the server\n", name)
+//	}
+func CompareRemote(url string, registry *Registry) (*RemoteComparison, error) {
+	return CompareRemoteWithOptions(CompareRemoteOptions{URL: url, Registry: registry})
+}
+
+// CompareRemoteWithOptions is CompareRemote with a custom local file or HTTP client.
+func CompareRemoteWithOptions(opts CompareRemoteOptions) (*RemoteComparison, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("remote URL is required")
+	}
+	if opts.Registry == nil {
+		return nil, fmt.Errorf("registry is required")
+	}
+	if opts.LocalFile == "" {
+		opts.LocalFile = Production.FileName
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	local, err := LoadSecrets(SecretsSource{FilePath: opts.LocalFile})
+	if err != nil {
+		return nil, err
+	}
+	remote, environment, err := fetchRemoteStatus(opts.Client, strings.TrimSuffix(opts.URL, "/")+"/env?format=json")
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := &RemoteComparison{URL: opts.URL, Environment: environment, LocalFile: opts.LocalFile}
+	known := make(map[string]bool)
+	for _, v := range opts.Registry.All() {
+		key := strings.ToLower(v.Name) + "_configured"
+		known[key] = true
+
+		remoteSet, reported := remote[key]
+		localSet := local[v.Name] != ""
+		switch {
+		case !reported:
+			cmp.NotReported = append(cmp.NotReported, v.Name)
+		case localSet && !remoteSet:
+			cmp.MissingRemote = append(cmp.MissingRemote, v.Name)
+		case !localSet && remoteSet:
+			cmp.MissingLocal = append(cmp.MissingLocal, v.Name)
+		default:
+			cmp.Matching++
+		}
+	}
+	for key := range remote {
+		if !known[key] {
+			cmp.UnknownRemote = append(cmp.UnknownRemote, strings.ToUpper(strings.TrimSuffix(key, "_configured")))
+		}
+	}
+	sort.Strings(cmp.UnknownRemote)
+
+	return cmp, nil
+}
+
+// fetchRemoteStatus reads the configured-status map from a webui /env JSON endpoint
+func fetchRemoteStatus(client *http.Client, url string) (map[string]bool, string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+
+	var body struct {
+		Environment string                 `json:"environment"`
+		Variables   map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, "", fmt.Errorf("GET %s: invalid JSON: %w", url, err)
+	}
+
+	status := make(map[string]bool, len(body.Variables))
+	for key, value := range body.Variables {
+		if !strings.HasSuffix(key, "_configured") {
+			continue
+		}
+		configured, _ := value.(bool)
+		status[key] = configured
+	}
+	return status, body.Environment, nil
+}
@@ -0,0 +1,83 @@
+package env
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareRemote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/env" || r.URL.Query().Get("format") != "json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"environment":"production","variables":{
+			"api_key_configured": true,
+			"db_url_configured": false,
+			"log_level_configured": true,
+			"legacy_token_configured": true
+		}}`))
+	}))
+	defer srv.Close()
+
+	localFile := filepath.Join(t.TempDir(), ".env.production")
+	os.WriteFile(localFile, []byte("API_KEY=secret\nDB_URL=postgres://db\n# LOG_LEVEL=info\nNEW_FLAG=on\n"), 0600)
+
+	registry := NewRegistry([]EnvVar{
+		{Name: "API_KEY", Secret: true},
+		{Name: "DB_URL", Required: true},
+		{Name: "LOG_LEVEL", Default: "info"},
+		{Name: "NEW_FLAG"},
+	})
+
+	cmp, err := CompareRemoteWithOptions(CompareRemoteOptions{
+		URL:       srv.URL + "/",
+		Registry:  registry,
+		LocalFile: localFile,
+	})
+	if err != nil {
+		t.Fatalf("CompareRemote failed: %v", err)
+	}
+
+	checks := map[string][]string{
+		"MissingRemote": cmp.MissingRemote,
+		"MissingLocal":  cmp.MissingLocal,
+		"UnknownRemote": cmp.UnknownRemote,
+		"NotReported":   cmp.NotReported,
+	}
+	want := map[string]string{
+		"MissingRemote": "DB_URL",
+		"MissingLocal":  "LOG_LEVEL",
+		"UnknownRemote": "LEGACY_TOKEN",
+		"NotReported":   "NEW_FLAG",
+	}
+	for field, got := range checks {
+		if strings.Join(got, ",") != want[field] {
+			t.Errorf("%s = %v, want %s", field, got, want[field])
+		}
+	}
+	if cmp.Matching != 1 || cmp.Environment != "production" || !cmp.HasDifferences() {
+		t.Errorf("unexpected comparison: %+v", cmp)
+	}
+}
+
+func TestCompareRemote_BadStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	localFile := filepath.Join(t.TempDir(), ".env.production")
+	os.WriteFile(localFile, []byte("A=1\n"), 0600)
+
+	_, err := CompareRemoteWithOptions(CompareRemoteOptions{
+		URL:       srv.URL,
+		Registry:  NewRegistry([]EnvVar{{Name: "A"}}),
+		LocalFile: localFile,
+	})
+	if err == nil {
+		t.Error("Expected error for 404")
+	}
+}
@@ -0,0 +1,154 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// ================================================================
+// Resolved Configuration
+// ================================================================
+// GenerateEnvList shows registry metadata; Resolve shows what the process
+// will actually use: each variable's effective value, where it came from
+// and whether it differs from the registry default.
+
+// Sources reported by Resolve besides chain files and SourceEnvironment
+const (
+	SourceDefault = "(default)" // Not set; the registry default applies
+	SourceUnset   = "(unset)"   // Not set and no default
+)
+
+// MaskedValue replaces secret values in resolved output
+const MaskedValue = "***set***"
+
+// ResolvedVar is one variable's effective configuration
+type ResolvedVar struct {
+	Name    string `json:"name"`
+	Group   string `json:"group"`
+	Value   string `json:"value"`             // Effective value (MaskedValue for secrets)
+	Source  string `json:"source"`            // "file:line", SourceEnvironment, SourceDefault or SourceUnset
+	Secret  bool   `json:"secret"`            // Value is masked
+	Default string `json:"default,omitempty"` // Registry default (masked for secrets)
+	Changed bool   `json:"changed"`           // Set to something other than the default
+}
+
+// Resolve returns the effective configuration of every registry variable, in
+// registry order. chain supplies file provenance (nil uses LoadedChain; without
+// either, set values are reported as SourceEnvironment).
+//
+// Example:
+//
+//	for _, v := range registry.Resolve(nil) {
+//	    fmt.Printf("%s=%s (%s)\n", v.Name, v.Value, v.Source)
+//	}
+func (r *Registry) Resolve(chain *ChainResult) []ResolvedVar {
+	if chain == nil {
+		chain = LoadedChain()
+	}
+
+	vars := r.All()
+	resolved := make([]ResolvedVar, 0, len(vars))
+	for _, v := range vars {
+		rv := ResolvedVar{
+			Name:    v.Name,
+			Group:   v.Group,
+			Secret:  v.Secret,
+			Default: v.Default,
+		}
+
+		value, set := r.lookupEnv(v.Name)
+		switch {
+		case set && value != "":
+			rv.Value = value
+			rv.Source = SourceEnvironment
+			if chain != nil {
+				if src, ok := chain.Sources[v.Name]; ok {
+					rv.Source = src.File
+					if src.Line > 0 {
+						rv.Source = fmt.Sprintf("%s:%d", src.File, src.Line)
+					}
+				}
+			}
+			rv.Changed = value != v.Default
+		case v.Default != "":
+			rv.Value = v.Default
+			rv.Source = SourceDefault
+		default:
+			rv.Source = SourceUnset
+		}
+
+		if v.Secret {
+			if rv.Value != "" {
+				rv.Value = MaskedValue
+			}
+			if rv.Default != "" {
+				rv.Default = MaskedValue
+			}
+		}
+		resolved = append(resolved, rv)
+	}
+	return resolved
+}
+
+// FormatResolvedTable renders resolved variables as an aligned text table
+func FormatResolvedTable(vars []ResolvedVar) string {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVALUE\tSOURCE\tCHANGED")
+	for _, v := range vars {
+		changed := ""
+		if v.Changed {
+			changed = "yes"
+		}
+		value := v.Value
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", v.Name, value, v.Source, changed)
+	}
+	tw.Flush()
+	return sb.String()
+}
+
+// FormatResolvedJSON renders resolved variables as an indented JSON array
+func FormatResolvedJSON(vars []ResolvedVar) (string, error) {
+	data, err := json.MarshalIndent(vars, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal resolved variables: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// FormatResolvedYAML renders resolved variables as a YAML sequence
+func FormatResolvedYAML(vars []ResolvedVar) string {
+	var sb strings.Builder
+	for _, v := range vars {
+		sb.WriteString(fmt.Sprintf("- name: %s\n", v.Name))
+		sb.WriteString(fmt.Sprintf("  group: %s\n", strconv.Quote(v.Group)))
+		sb.WriteString(fmt.Sprintf("  value: %s\n", strconv.Quote(v.Value)))
+		sb.WriteString(fmt.Sprintf("  source: %s\n", strconv.Quote(v.Source)))
+		sb.WriteString(fmt.Sprintf("  secret: %t\n", v.Secret))
+		if v.Default != "" {
+			sb.WriteString(fmt.Sprintf("  default: %s\n", strconv.Quote(v.Default)))
+		}
+		sb.WriteString(fmt.Sprintf("  changed: %t\n", v.Changed))
+	}
+	return sb.String()
+}
+
+// FormatResolved renders resolved variables as "table", "json" or "yaml"
+func FormatResolved(vars []ResolvedVar, format string) (string, error) {
+	switch format {
+	case "", "table":
+		return FormatResolvedTable(vars), nil
+	case "json":
+		return FormatResolvedJSON(vars)
+	case "yaml", "yml":
+		return FormatResolvedYAML(vars), nil
+	default:
+		return "", fmt.Errorf("unknown format %q (use table, json or yaml)", format)
+	}
+}
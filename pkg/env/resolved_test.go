@@ -0,0 +1,75 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	os.WriteFile(envFile, []byte("RESOLVE_PORT=9090\nRESOLVE_TOKEN=abc\n"), 0644)
+
+	registry := NewRegistry([]EnvVar{
+		{Name: "RESOLVE_PORT", Default: "8080"},
+		{Name: "RESOLVE_TOKEN", Secret: true},
+		{Name: "RESOLVE_MODE", Default: "dev"},
+		{Name: "RESOLVE_EMPTY"},
+		{Name: "RESOLVE_REAL", Default: "x"},
+	})
+
+	for _, name := range []string{"RESOLVE_PORT", "RESOLVE_TOKEN", "RESOLVE_MODE", "RESOLVE_EMPTY"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	t.Setenv("RESOLVE_REAL", "x")
+
+	chain, err := ResolveChain(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range chain.Values {
+		t.Setenv(k, v)
+	}
+
+	got := make(map[string]ResolvedVar)
+	for _, v := range registry.Resolve(chain) {
+		got[v.Name] = v
+	}
+
+	if v := got["RESOLVE_PORT"]; v.Value != "9090" || v.Source != envFile+":1" || !v.Changed {
+		t.Errorf("RESOLVE_PORT: %+v", v)
+	}
+	if v := got["RESOLVE_TOKEN"]; v.Value != MaskedValue || strings.Contains(FormatResolvedTable(registry.Resolve(chain)), "abc") {
+		t.Errorf("RESOLVE_TOKEN must be masked: %+v", v)
+	}
+	if v := got["RESOLVE_MODE"]; v.Value != "dev" || v.Source != SourceDefault || v.Changed {
+		t.Errorf("RESOLVE_MODE: %+v", v)
+	}
+	if v := got["RESOLVE_EMPTY"]; v.Source != SourceUnset {
+		t.Errorf("RESOLVE_EMPTY: %+v", v)
+	}
+	if v := got["RESOLVE_REAL"]; v.Source != SourceEnvironment || v.Changed {
+		t.Errorf("RESOLVE_REAL: %+v", v)
+	}
+}
+
+func TestFormatResolved(t *testing.T) {
+	vars := []ResolvedVar{{Name: "A", Value: `say "hi"`, Source: SourceDefault}}
+
+	for format, want := range map[string]string{
+		"table": "NAME",
+		"json":  `"name": "A"`,
+		"yaml":  `value: "say \"hi\""`,
+	} {
+		out, err := FormatResolved(vars, format)
+		if err != nil || !strings.Contains(out, want) {
+			t.Errorf("%s: expected %q in output, got %q (%v)", format, want, out, err)
+		}
+	}
+	if _, err := FormatResolved(vars, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
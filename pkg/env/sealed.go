@@ -0,0 +1,141 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"filippo.io/age/armor"
+)
+
+// ================================================================
+// Sealed Secrets Store
+// ================================================================
+// Secret values encrypted at rest, one Age file per variable (dir/NAME.age).
+// Values arrive already encrypted to the store's recipient - e.g. by the
+// browser when editing in webui (see webui.WithSealedEditing) - so the
+// process that writes them never sees the plaintext. Only holders of the
+// matching identity can Load them.
+
+// DefaultSealedDir is the conventional directory of a SealedStore
+const DefaultSealedDir = ".env.sealed"
+
+// ageHeader starts every (binary) Age file
+const ageHeader = "age-encryption.org/v1\n"
+
+// varNamePattern matches valid variable names (also safe as file names)
+var varNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SealedStore stores Age-encrypted secret values in a directory
+type SealedStore struct {
+	dir string
+}
+
+// NewSealedStore creates a store in dir (created on the first Put)
+func NewSealedStore(dir string) *SealedStore {
+	return &SealedStore{dir: dir}
+}
+
+// Dir returns the store directory
+func (s *SealedStore) Dir() string {
+	return s.dir
+}
+
+// IsAgeCiphertext reports whether data is an Age file, binary or ASCII-armored
+func IsAgeCiphertext(data []byte) bool {
+	_, err := dearmor(data)
+	return err == nil
+}
+
+// dearmor returns the binary Age file for a binary or armored one
+func dearmor(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte(armor.Header)) {
+		decoded, err := io.ReadAll(armor.NewReader(bytes.NewReader(trimmed)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Age armor: %w", err)
+		}
+		data = decoded
+	}
+	if !bytes.HasPrefix(data, []byte(ageHeader)) {
+		return nil, fmt.Errorf("not an Age-encrypted value")
+	}
+	return data, nil
+}
+
+// Put stores the encrypted value of name. ciphertext must be an Age file
+// (armored or binary); plaintext is rejected, never written.
+func (s *SealedStore) Put(name string, ciphertext []byte) error {
+	if !varNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q", name)
+	}
+	data, err := dearmor(ciphertext)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", s.dir, err)
+	}
+	// Write to a temporary file and rename, so readers never see a partial value
+	path := filepath.Join(s.dir, name+".age")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Names returns the names of the stored values, sorted (none if the
+// directory does not exist)
+func (s *SealedStore) Names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.dir, err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".age")
+		if !e.IsDir() && name != e.Name() && varNamePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load decrypts every stored value with the identities DecryptAgeFile finds
+// (AGE_IDENTITY, ~/.ssh/age, ~/.config/age/keys.txt)
+func (s *SealedStore) Load() (map[string]string, error) {
+	names, err := s.Names()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(s.dir, name+".age"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		plaintext, err := DecryptAgeFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		values[name] = string(plaintext)
+	}
+	return values, nil
+}
@@ -0,0 +1,84 @@
+package env
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// encryptTo encrypts value to the identity's recipient (armored, as the browser sends it)
+func encryptTo(t *testing.T, identity *age.X25519Identity, value string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, identity.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(value))
+	w.Close()
+	aw.Close()
+	return buf.Bytes()
+}
+
+func TestSealedStore_PutLoad(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(keyPath, []byte(identity.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AGE_IDENTITY", keyPath)
+
+	store := NewSealedStore(filepath.Join(t.TempDir(), DefaultSealedDir))
+	if names, err := store.Names(); err != nil || len(names) != 0 {
+		t.Fatalf("empty store: names=%v err=%v", names, err)
+	}
+
+	if err := store.Put("API_KEY", encryptTo(t, identity, "sk-123")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("DB_PASSWORD", encryptTo(t, identity, "hunter2")); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := os.ReadFile(filepath.Join(store.Dir(), "API_KEY.age"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stored, []byte("sk-123")) || !IsAgeCiphertext(stored) {
+		t.Error("stored value must be Age ciphertext")
+	}
+
+	names, _ := store.Names()
+	if got := strings.Join(names, ","); got != "API_KEY,DB_PASSWORD" {
+		t.Errorf("Names() = %s", got)
+	}
+	values, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["API_KEY"] != "sk-123" || values["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("Load() = %v", values)
+	}
+}
+
+func TestSealedStore_RejectsPlaintext(t *testing.T) {
+	store := NewSealedStore(t.TempDir())
+	if err := store.Put("API_KEY", []byte("sk-123")); err == nil {
+		t.Error("plaintext must be rejected")
+	}
+	if err := store.Put("../escape", []byte("age-encryption.org/v1\n")); err == nil {
+		t.Error("invalid names must be rejected")
+	}
+	if names, _ := store.Names(); len(names) != 0 {
+		t.Errorf("nothing should be stored, got %v", names)
+	}
+}
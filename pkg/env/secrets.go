@@ -7,7 +7,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"filippo.io/age"
 )
@@ -56,13 +59,13 @@ func DecryptAgeFile(encryptedData []byte) ([]byte, error) {
 	}
 
 	if len(identities) == 0 {
-		return nil, fmt.Errorf("no Age identities found. Create one with:\n  age-keygen -o ~/.ssh/age\n\nOr set AGE_IDENTITY environment variable to your identity file path")
+		return nil, Classify(ErrNoIdentity, fmt.Errorf("no Age identities found. Create one with:\n  age-keygen -o ~/.ssh/age\n\nOr set AGE_IDENTITY environment variable to your identity file path"))
 	}
 
 	// Decrypt the file
 	r, err := age.Decrypt(bytes.NewReader(encryptedData), identities...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt: %w\n\nMake sure you have the correct Age identity key", err)
+		return nil, fmt.Errorf("%w: %w\n\nMake sure you have the correct Age identity key", ErrDecryptFailed, err)
 	}
 
 	decrypted, err := io.ReadAll(r)
@@ -74,11 +77,13 @@ func DecryptAgeFile(encryptedData []byte) ([]byte, error) {
 }
 
 // ParseSecretsFile parses a key=value formatted secrets file (like .env.secrets).
-// It ignores comments (lines starting with #) and empty lines.
+// It ignores comments (lines starting with #) and empty lines, a leading
+// UTF-8 byte order mark and an "export " prefix; lines may end in CRLF.
+// Lines whose key is not a usable variable name (see validEnvKey) are skipped.
 // Returns a map of environment variable names to their values.
 func ParseSecretsFile(data []byte) map[string]string {
 	secrets := make(map[string]string)
-	lines := bytes.Split(data, []byte("\n"))
+	lines := bytes.Split(bytes.TrimPrefix(data, utf8BOM), []byte("\n"))
 
 	for _, line := range lines {
 		// Trim whitespace
@@ -90,17 +95,36 @@ func ParseSecretsFile(data []byte) map[string]string {
 		}
 
 		// Split on first '=' only
-		parts := bytes.SplitN(line, []byte("="), 2)
-		if len(parts) == 2 {
-			key := string(bytes.TrimSpace(parts[0]))
-			value := string(bytes.TrimSpace(parts[1]))
-			secrets[key] = value
+		key, value, ok := strings.Cut(strings.TrimPrefix(lineStr, "export "), "=")
+		if !ok {
+			continue
+		}
+		if key = strings.TrimSpace(key); validEnvKey(key) {
+			secrets[key] = strings.TrimSpace(value)
 		}
 	}
 
 	return secrets
 }
 
+// utf8BOM is the byte order mark some editors put at the start of a file
+var utf8BOM = []byte("\ufeff")
+
+// validEnvKey reports whether key can be a variable name in a secrets file:
+// non-empty valid UTF-8 (unicode names are allowed) without whitespace, '='
+// or NUL, which the process environment or the file format cannot hold
+func validEnvKey(key string) bool {
+	if key == "" || !utf8.ValidString(key) {
+		return false
+	}
+	for _, r := range key {
+		if r == '=' || r == 0 || r == '\ufeff' || unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
 // SecretsSource specifies where to load secrets from.
 // This supports automatic fallback from encrypted to plaintext versions.
 type SecretsSource struct {
@@ -140,10 +164,10 @@ func LoadSecrets(src SecretsSource) (map[string]string, error) {
 	// Check if file exists
 	if _, err := os.Stat(actualPath); os.IsNotExist(err) {
 		if src.PreferEncrypted {
-			return nil, fmt.Errorf("secrets file not found: %s or %s.age\n\nPlease create it from .env.secrets.example\nOptional: Encrypt with Age:\n  age -e -r YOUR_PUBLIC_KEY %s > %s.age",
-				src.FilePath, src.FilePath, src.FilePath, src.FilePath)
+			return nil, fmt.Errorf("%w: %s or %s.age\n\nPlease create it from .env.secrets.example\nOptional: Encrypt with Age:\n  age -e -r YOUR_PUBLIC_KEY %s > %s.age",
+				ErrSecretsNotFound, src.FilePath, src.FilePath, src.FilePath, src.FilePath)
 		}
-		return nil, fmt.Errorf("secrets file not found: %s", actualPath)
+		return nil, fmt.Errorf("%w: %s", ErrSecretsNotFound, actualPath)
 	}
 
 	// Read file
@@ -167,8 +191,9 @@ func LoadSecrets(src SecretsSource) (map[string]string, error) {
 
 // MergeIntoTemplate merges secrets map into a template string.
 //
-// This preserves the template structure (comments, headers, blank lines)
-// while replacing variable values where secrets exist.
+// This preserves the template structure (comments, headers, blank lines,
+// CRLF line endings, a byte order mark) while replacing variable values where
+// secrets exist. With no matching secrets the template is returned unchanged.
 //
 // Template format:
 //
@@ -179,33 +204,69 @@ func LoadSecrets(src SecretsSource) (map[string]string, error) {
 //
 //	KEY_NAME=secret_value
 //
-// Otherwise the template line is kept as-is.
-func MergeIntoTemplate(template string, secrets map[string]string) string {
+// Otherwise the template line is kept as-is. An "export " prefix is kept.
+//
+// A value containing a line break cannot be held by this format and would
+// inject lines into the file: MergeIntoTemplate then returns an error
+// wrapping ErrInvalidValue that names the keys, and no content.
+func MergeIntoTemplate(template string, secrets map[string]string) (string, error) {
+	if err := checkSingleLine(secrets); err != nil {
+		return "", err
+	}
+
 	var sb strings.Builder
+	sb.Grow(len(template))
+	if strings.HasPrefix(template, string(utf8BOM)) {
+		sb.Write(utf8BOM)
+		template = template[len(utf8BOM):]
+	}
 	lines := strings.Split(template, "\n")
 
-	for _, line := range lines {
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
 		// Check if this is a variable assignment (not a comment or empty line)
 		trimmedLine := strings.TrimSpace(line)
-		if strings.Contains(line, "=") && !strings.HasPrefix(trimmedLine, "#") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-
-				// If we have a secret value for this key, use it
-				if secretValue, exists := secrets[key]; exists {
-					sb.WriteString(fmt.Sprintf("%s=%s\n", key, secretValue))
-					continue
+		if key, _, ok := strings.Cut(trimmedLine, "="); ok && !strings.HasPrefix(trimmedLine, "#") {
+			export := strings.HasPrefix(key, "export ")
+			key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+
+			// If we have a secret value for this key, use it
+			if secretValue, exists := secrets[key]; exists {
+				if export {
+					sb.WriteString("export ")
+				}
+				sb.WriteString(key + "=" + secretValue)
+				if strings.HasSuffix(line, "\r") {
+					sb.WriteString("\r")
 				}
+				continue
 			}
 		}
 
 		// Otherwise, keep the line as-is (comments, headers, empty lines, or vars without secrets)
 		sb.WriteString(line)
-		sb.WriteString("\n")
 	}
 
-	return sb.String()
+	return sb.String(), nil
+}
+
+// checkSingleLine returns an error naming the keys whose values contain a
+// line break
+func checkSingleLine(values map[string]string) error {
+	var keys []string
+	for key, value := range values {
+		if strings.ContainsAny(value, "\r\n") {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	return fmt.Errorf("%w: %s (line breaks cannot be stored in an env file)", ErrInvalidValue, strings.Join(keys, ", "))
 }
 
 // ================================================================
@@ -352,7 +413,7 @@ func EncryptEnvironments(opts EncryptionOptions) (*EncryptionResult, error) {
 
 	// Check if key exists
 	if _, err := os.Stat(opts.KeyPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("no Age key found at %s. Generate one with GenerateAgeKey()", opts.KeyPath)
+		return nil, Classify(ErrNoIdentity, fmt.Errorf("no Age key found at %s. Generate one with GenerateAgeKey()", opts.KeyPath))
 	}
 
 	// Read and parse identity
@@ -414,7 +475,7 @@ func EncryptEnvironments(opts EncryptionOptions) (*EncryptionResult, error) {
 
 	// If nothing was processed and we have errors, return error
 	if len(result.ProcessedFiles) == 0 && len(result.Errors) > 0 {
-		return result, fmt.Errorf("failed to encrypt any files: %v", result.Errors[0])
+		return result, fmt.Errorf("failed to encrypt any files: %w", result.Errors[0])
 	}
 
 	return result, nil
@@ -488,7 +549,7 @@ func DecryptEnvironments(opts EncryptionOptions) (*EncryptionResult, error) {
 
 	// If nothing was processed and we have errors, return error
 	if len(result.ProcessedFiles) == 0 && len(result.Errors) > 0 {
-		return result, fmt.Errorf("failed to decrypt any files: %v", result.Errors[0])
+		return result, fmt.Errorf("failed to decrypt any files: %w", result.Errors[0])
 	}
 
 	return result, nil
@@ -60,7 +60,7 @@ func SyncSecretsToEnvironment(opts SecretsSyncOptions) (*SecretsSyncResult, erro
 		var usedFallback bool
 		secretsEnv, usedFallback = ResolveSecretsFile(opts.TargetEnv)
 		if secretsEnv == nil {
-			return nil, fmt.Errorf("no secrets file found for %s", opts.TargetEnv.FileName)
+			return nil, Classify(ErrSecretsNotFound, fmt.Errorf("no secrets file found for %s", opts.TargetEnv.FileName))
 		}
 		result.UsedFallback = usedFallback
 		if usedFallback {
@@ -87,7 +87,10 @@ func SyncSecretsToEnvironment(opts SecretsSyncOptions) (*SecretsSyncResult, erro
 
 	// Generate template and merge secrets
 	template := opts.TargetEnv.Generate(opts.Registry, opts.AppName)
-	mergedContent := MergeIntoTemplate(template, secrets)
+	mergedContent, err := MergeIntoTemplate(template, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge %s: %w", secretsEnv.FileName, err)
+	}
 
 	// Write merged content to target environment file
 	if err := os.WriteFile(opts.TargetEnv.FileName, []byte(mergedContent), 0600); err != nil {
@@ -0,0 +1,103 @@
+package env
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// ================================================================
+// Environment Snapshot
+// ================================================================
+// The registry's bulk operations (ValidateRequired, ValidateTypes, Export,
+// GenerateEnvList, Resolve, LintSecrets, CheckPolicies, RedactingWriter) read
+// one value per variable. By default that is an os.Getenv call, which takes
+// the runtime's environment lock each time; with thousands of variables and
+// frequent checks (a /env page under load, a health probe) CacheEnvironment
+// copies the environment into a map once and serves every read from it:
+//
+//	registry.CacheEnvironment()
+//	...
+//	registry.RefreshEnvironment() // after os.Setenv, LoadChain, a SIGHUP reload
+//
+// The snapshot is per registry and swapped atomically, so readers never see
+// a half-refreshed environment. EnvVar.GetString and friends still read the
+// live environment.
+
+// EnvSnapshot is a copy of the process environment taken at one point in time
+type EnvSnapshot struct {
+	values map[string]string
+	taken  time.Time
+}
+
+// TakeEnvSnapshot copies the current process environment
+func TakeEnvSnapshot() *EnvSnapshot {
+	environ := os.Environ()
+	s := &EnvSnapshot{values: make(map[string]string, len(environ)), taken: time.Now()}
+	for _, kv := range environ {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			// First wins, like os.Getenv with duplicate entries
+			if _, dup := s.values[key]; !dup {
+				s.values[key] = value
+			}
+		}
+	}
+	return s
+}
+
+// Lookup returns the value of name and whether it was set
+func (s *EnvSnapshot) Lookup(name string) (string, bool) {
+	value, ok := s.values[name]
+	return value, ok
+}
+
+// Getenv returns the value of name ("" when unset)
+func (s *EnvSnapshot) Getenv(name string) string {
+	return s.values[name]
+}
+
+// Len returns the number of variables in the snapshot
+func (s *EnvSnapshot) Len() int {
+	return len(s.values)
+}
+
+// Taken returns when the snapshot was taken
+func (s *EnvSnapshot) Taken() time.Time {
+	return s.taken
+}
+
+// CacheEnvironment makes the registry's bulk operations read from a snapshot
+// of the environment taken now (see RefreshEnvironment) and returns it
+func (r *Registry) CacheEnvironment() *EnvSnapshot {
+	s := TakeEnvSnapshot()
+	r.env.Store(s)
+	return s
+}
+
+// RefreshEnvironment retakes the snapshot after the environment changed. It
+// does nothing unless CacheEnvironment was called.
+func (r *Registry) RefreshEnvironment() {
+	if r.env.Load() != nil {
+		r.env.Store(TakeEnvSnapshot())
+	}
+}
+
+// UncacheEnvironment drops the snapshot; reads go to os.Getenv again
+func (r *Registry) UncacheEnvironment() {
+	r.env.Store(nil)
+}
+
+// Getenv returns the value of name from the snapshot, or from the process
+// environment when caching is off
+func (r *Registry) Getenv(name string) string {
+	value, _ := r.lookupEnv(name)
+	return value
+}
+
+// lookupEnv is os.LookupEnv through the snapshot, if any
+func (r *Registry) lookupEnv(name string) (string, bool) {
+	if s := r.env.Load(); s != nil {
+		return s.Lookup(name)
+	}
+	return os.LookupEnv(name)
+}
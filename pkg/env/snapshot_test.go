@@ -0,0 +1,189 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Test the cached environment serves reads until refreshed
+func TestRegistry_CacheEnvironment(t *testing.T) {
+	t.Setenv("SNAP_PORT", "8080")
+	registry := NewRegistry([]EnvVar{
+		{Name: "SNAP_PORT", Default: "80"},
+		{Name: "SNAP_TOKEN", Required: true},
+	})
+
+	s := registry.CacheEnvironment()
+	if got, ok := s.Lookup("SNAP_PORT"); !ok || got != "8080" {
+		t.Fatalf("snapshot SNAP_PORT = %q, %v", got, ok)
+	}
+	if s.Len() < 1 || s.Taken().IsZero() {
+		t.Errorf("snapshot Len = %d, Taken = %v", s.Len(), s.Taken())
+	}
+
+	t.Setenv("SNAP_PORT", "9090")
+	t.Setenv("SNAP_TOKEN", "abc")
+	if got := registry.Getenv("SNAP_PORT"); got != "8080" {
+		t.Errorf("cached Getenv = %q, want the snapshot value 8080", got)
+	}
+	if err := registry.ValidateRequired(); err == nil {
+		t.Error("ValidateRequired should use the snapshot, where SNAP_TOKEN is unset")
+	}
+
+	registry.RefreshEnvironment()
+	if got := registry.Getenv("SNAP_PORT"); got != "9090" {
+		t.Errorf("refreshed Getenv = %q, want 9090", got)
+	}
+	if err := registry.ValidateRequired(); err != nil {
+		t.Errorf("ValidateRequired after refresh: %v", err)
+	}
+
+	registry.UncacheEnvironment()
+	os.Setenv("SNAP_PORT", "7070")
+	if got := registry.Getenv("SNAP_PORT"); got != "7070" {
+		t.Errorf("uncached Getenv = %q, want the live value 7070", got)
+	}
+}
+
+// Test RefreshEnvironment does not turn caching on
+func TestRegistry_RefreshEnvironmentWithoutCache(t *testing.T) {
+	registry := NewRegistry([]EnvVar{{Name: "SNAP_LIVE"}})
+	registry.RefreshEnvironment()
+
+	t.Setenv("SNAP_LIVE", "yes")
+	if got := registry.Getenv("SNAP_LIVE"); got != "yes" {
+		t.Errorf("Getenv = %q, want the live value", got)
+	}
+}
+
+// Test a cached registry resolves and exports the same as an uncached one
+func TestRegistry_CacheEnvironmentSameResults(t *testing.T) {
+	registry := largeRegistry(200)
+	for i := 0; i < 200; i += 3 {
+		t.Setenv(fmt.Sprintf("BENCH_VAR_%04d", i), fmt.Sprint(i))
+	}
+
+	live := registry.Export(ExportOptions{Format: FormatSimple})
+	resolved := registry.Resolve(&ChainResult{})
+	registry.CacheEnvironment()
+	if got := registry.Export(ExportOptions{Format: FormatSimple}); got != live {
+		t.Errorf("cached Export differs:\n%s\nwant:\n%s", got, live)
+	}
+	for i, rv := range registry.Resolve(&ChainResult{}) {
+		if rv != resolved[i] {
+			t.Errorf("cached Resolve[%d] = %+v, want %+v", i, rv, resolved[i])
+		}
+	}
+}
+
+// Test template generation writes each group once, in order
+func TestGenerateTemplate_SinglePassOrder(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "B", Group: "Two"},
+		{Name: "A", Group: "One"},
+		{Name: "C", Group: "Two"},
+		{Name: "D", Group: "One"},
+	})
+
+	out := registry.GenerateTemplate(TemplateOptions{
+		IncludeGroupHeaders: true,
+		GroupHeaderFormat:   func(g string) string { return "[" + g + "]\n" },
+	})
+	want := "[One]\nA=\n\nD=\n\n[Two]\nB=\n\nC=\n\n"
+	if out != want {
+		t.Errorf("alphabetical:\n%q\nwant:\n%q", out, want)
+	}
+
+	out = registry.GenerateTemplate(TemplateOptions{
+		IncludeGroupHeaders: true,
+		GroupHeaderFormat:   func(g string) string { return "[" + g + "]\n" },
+		GroupOrder:          []string{"Two", "Missing"},
+	})
+	want = "[Two]\nB=\n\nC=\n\n"
+	if out != want {
+		t.Errorf("GroupOrder:\n%q\nwant:\n%q", out, want)
+	}
+}
+
+// ================================================================
+// Benchmarks
+// ================================================================
+// go test ./pkg/env -run '^$' -bench . -benchmem
+//
+// ByName is a map lookup, flat across registry sizes; the generators and
+// validators are one pass over the variables (plus one sort for templates).
+
+// largeRegistry builds a registry of n variables across 20 groups
+func largeRegistry(n int) *Registry {
+	vars := make([]EnvVar, n)
+	for i := range vars {
+		vars[i] = EnvVar{
+			Name:        fmt.Sprintf("BENCH_VAR_%04d", i),
+			Description: "Benchmark variable",
+			Group:       fmt.Sprintf("Group %02d", i%20),
+			Default:     fmt.Sprint(i),
+			Secret:      i%10 == 0,
+			Required:    i%7 == 0,
+		}
+	}
+	return NewRegistry(vars)
+}
+
+var benchSizes = []int{100, 1000, 10000}
+
+func BenchmarkRegistry_ByName(b *testing.B) {
+	for _, n := range benchSizes {
+		registry := largeRegistry(n)
+		names := make([]string, n)
+		for i, v := range registry.All() {
+			names[i] = v.Name
+		}
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if registry.ByName(names[i%n]) == nil {
+					b.Fatal("missing variable")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRegistry_ValidateTypes(b *testing.B) {
+	for _, n := range benchSizes {
+		registry := largeRegistry(n)
+		b.Run(fmt.Sprintf("%d/live", n), func(b *testing.B) {
+			registry.UncacheEnvironment()
+			for i := 0; i < b.N; i++ {
+				registry.ValidateTypes()
+			}
+		})
+		b.Run(fmt.Sprintf("%d/cached", n), func(b *testing.B) {
+			registry.CacheEnvironment()
+			for i := 0; i < b.N; i++ {
+				registry.ValidateTypes()
+			}
+		})
+	}
+}
+
+func BenchmarkRegistry_GenerateTemplate(b *testing.B) {
+	for _, n := range benchSizes {
+		registry := largeRegistry(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				out := registry.GenerateEnvExample("Bench")
+				if !strings.Contains(out, "BENCH_VAR_0000") {
+					b.Fatal("variable missing from output")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTakeEnvSnapshot(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		TakeEnvSnapshot()
+	}
+}
@@ -48,28 +48,10 @@ func SyncFileSection(opts SyncOptions) error {
 		return fmt.Errorf("failed to read file %s: %w", opts.FilePath, err)
 	}
 
-	content := string(data)
-
-	// Find start marker
-	startIdx := strings.Index(content, opts.StartMarker)
-	if startIdx == -1 {
-		return fmt.Errorf("could not find start marker in %s: %q", opts.FilePath, opts.StartMarker)
-	}
-
-	// Find end marker (search from after start marker)
-	endIdx := strings.Index(content[startIdx:], opts.EndMarker)
-	if endIdx == -1 {
-		return fmt.Errorf("could not find end marker in %s: %q", opts.FilePath, opts.EndMarker)
+	newContent, err := replaceSection(string(data), opts)
+	if err != nil {
+		return err
 	}
-	// Convert relative index to absolute
-	endIdx = startIdx + endIdx
-
-	// Calculate replacement end position
-	replaceEnd := endIdx + len(opts.EndMarker)
-
-	// Build new content
-	// Replace from start marker through end marker
-	newContent := content[:startIdx] + opts.Content + content[replaceEnd:]
 
 	// Dry run - just print what would change
 	if opts.DryRun {
@@ -100,3 +82,41 @@ func SyncFileSection(opts SyncOptions) error {
 
 	return nil
 }
+
+// SectionUpToDate reports whether the section between markers already matches opts.Content.
+// Nothing is written; this is the check behind "files needing regeneration" in drift reports.
+func SectionUpToDate(opts SyncOptions) (bool, error) {
+	data, err := os.ReadFile(opts.FilePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read file %s: %w", opts.FilePath, err)
+	}
+
+	newContent, err := replaceSection(string(data), opts)
+	if err != nil {
+		return false, err
+	}
+	return newContent == string(data), nil
+}
+
+// replaceSection replaces everything from the start marker through the end marker with opts.Content
+func replaceSection(content string, opts SyncOptions) (string, error) {
+	// Find start marker
+	startIdx := strings.Index(content, opts.StartMarker)
+	if startIdx == -1 {
+		return "", fmt.Errorf("could not find start marker in %s: %q", opts.FilePath, opts.StartMarker)
+	}
+
+	// Find end marker (search from after start marker)
+	endIdx := strings.Index(content[startIdx:], opts.EndMarker)
+	if endIdx == -1 {
+		return "", fmt.Errorf("could not find end marker in %s: %q", opts.FilePath, opts.EndMarker)
+	}
+	// Convert relative index to absolute
+	endIdx = startIdx + endIdx
+
+	// Calculate replacement end position
+	replaceEnd := endIdx + len(opts.EndMarker)
+
+	// Replace from start marker through end marker
+	return content[:startIdx] + opts.Content + content[replaceEnd:], nil
+}
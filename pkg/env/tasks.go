@@ -0,0 +1,136 @@
+// Package env provides Taskfile and Makefile target generation.
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Markers for the auto-generated task block (shared by Taskfile.yml and Makefile includes)
+const (
+	TasksStartMarker = "# === AUTO-GENERATED TASKS (do not edit between markers) ==="
+	TasksEndMarker   = "# === END AUTO-GENERATED TASKS ==="
+)
+
+// TaskTarget is a single automation entry point (Taskfile task or Makefile target).
+type TaskTarget struct {
+	Name        string // Target name (e.g., "sync-registry")
+	Description string // One-line description
+	Command     string // Shell command to run
+}
+
+// DefaultTaskTargets returns the standard targets wired to the project's CLI.
+// cli is the command prefix used to invoke the project (e.g., "go run ." or "./bin/app").
+func DefaultTaskTargets(cli string) []TaskTarget {
+	if cli == "" {
+		cli = "go run ."
+	}
+	return []TaskTarget{
+		{Name: "sync-registry", Description: "Sync deployment configs and environment templates from the registry", Command: cli + " sync-registry"},
+		{Name: "sync-environments", Description: "Merge secrets into environments and validate", Command: cli + " sync-environments"},
+		{Name: "finalize", Description: "Encrypt environment files for git", Command: cli + " finalize"},
+		{Name: "fly-deploy", Description: "Deploy to Fly.io", Command: "flyctl deploy"},
+		{Name: "age-keygen", Description: "Generate an Age encryption key", Command: "mkdir -p .age && age-keygen -o " + DefaultAgeKeyPath},
+	}
+}
+
+// TasksOptions configures Taskfile/Makefile generation.
+type TasksOptions struct {
+	CLI     string       // Command prefix for DefaultTaskTargets (default: "go run .")
+	Targets []TaskTarget // Targets to generate (default: DefaultTaskTargets(CLI))
+}
+
+// targets returns the configured targets or the defaults
+func (o TasksOptions) targets() []TaskTarget {
+	if len(o.Targets) > 0 {
+		return o.Targets
+	}
+	return DefaultTaskTargets(o.CLI)
+}
+
+// GenerateTaskfileSection generates the marker block for the tasks: map of a Taskfile.yml.
+// Entries are indented two spaces so the block sits directly under "tasks:".
+func GenerateTaskfileSection(opts TasksOptions) string {
+	var sb strings.Builder
+	sb.WriteString("  " + TasksStartMarker + "\n")
+	for _, t := range opts.targets() {
+		sb.WriteString(fmt.Sprintf("  %s:\n", t.Name))
+		sb.WriteString(fmt.Sprintf("    desc: %s\n", yamlQuote(t.Description)))
+		sb.WriteString("    cmds:\n")
+		sb.WriteString(fmt.Sprintf("      - %s\n", yamlQuote(t.Command)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("  " + TasksEndMarker)
+	return sb.String()
+}
+
+// GenerateTaskfile generates a complete Taskfile.yml (https://taskfile.dev) with the marker block.
+func GenerateTaskfile(opts TasksOptions) string {
+	var sb strings.Builder
+	sb.WriteString("# Taskfile.yml - see https://taskfile.dev\n")
+	sb.WriteString("# Add your own tasks outside the auto-generated block.\n\n")
+	sb.WriteString("version: '3'\n\n")
+	sb.WriteString("tasks:\n")
+	sb.WriteString(GenerateTaskfileSection(opts))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// GenerateMakefileSection generates the marker block of Makefile targets.
+// Suitable for a Makefile include (e.g., "include env.mk").
+func GenerateMakefileSection(opts TasksOptions) string {
+	targets := opts.targets()
+
+	var sb strings.Builder
+	sb.WriteString(TasksStartMarker + "\n")
+
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.Name
+	}
+	sb.WriteString(".PHONY: " + strings.Join(names, " ") + "\n\n")
+
+	for _, t := range targets {
+		sb.WriteString(fmt.Sprintf("## %s: %s\n", t.Name, t.Description))
+		sb.WriteString(t.Name + ":\n")
+		sb.WriteString("\t" + t.Command + "\n\n")
+	}
+	sb.WriteString(TasksEndMarker)
+	return sb.String()
+}
+
+// SyncTaskfile creates the Taskfile if missing, otherwise refreshes the marker block.
+// Returns true if the file was created.
+func SyncTaskfile(path string, opts TasksOptions) (bool, error) {
+	return syncTasksFile(path, GenerateTaskfile(opts), GenerateTaskfileSection(opts), "  "+TasksStartMarker, "  "+TasksEndMarker)
+}
+
+// SyncMakefileTargets creates the Makefile include if missing, otherwise refreshes the marker block.
+// Returns true if the file was created.
+func SyncMakefileTargets(path string, opts TasksOptions) (bool, error) {
+	section := GenerateMakefileSection(opts)
+	return syncTasksFile(path, section+"\n", section, TasksStartMarker, TasksEndMarker)
+}
+
+// syncTasksFile writes full content for new files or replaces the marker block in existing ones
+func syncTasksFile(path, full, section, startMarker, endMarker string) (bool, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.WriteFile(path, []byte(full), 0644); err != nil {
+			return false, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return true, nil
+	}
+
+	return false, SyncFileSection(SyncOptions{
+		FilePath:    path,
+		StartMarker: startMarker,
+		EndMarker:   endMarker,
+		Content:     section,
+	})
+}
+
+// yamlQuote single-quotes a YAML scalar (single quotes are escaped by doubling)
+func yamlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
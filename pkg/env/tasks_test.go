@@ -0,0 +1,72 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTaskfile(t *testing.T) {
+	content := GenerateTaskfile(TasksOptions{CLI: "./bin/app"})
+
+	for _, want := range []string{
+		"version: '3'",
+		"tasks:",
+		"  sync-registry:",
+		"      - './bin/app sync-registry'",
+		"  fly-deploy:",
+		"  age-keygen:",
+		TasksStartMarker,
+		TasksEndMarker,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Taskfile missing %q\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerateMakefileSection(t *testing.T) {
+	content := GenerateMakefileSection(TasksOptions{
+		Targets: []TaskTarget{{Name: "finalize", Description: "Encrypt", Command: "go run . finalize"}},
+	})
+
+	if !strings.Contains(content, ".PHONY: finalize") {
+		t.Errorf("missing .PHONY line\n%s", content)
+	}
+	if !strings.Contains(content, "finalize:\n\tgo run . finalize\n") {
+		t.Errorf("missing tab-indented recipe\n%s", content)
+	}
+}
+
+func TestSyncTaskfile_PreservesCustomTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+
+	created, err := SyncTaskfile(path, TasksOptions{})
+	if err != nil || !created {
+		t.Fatalf("SyncTaskfile create: created=%v err=%v", created, err)
+	}
+
+	// Add a custom task outside the markers
+	data, _ := os.ReadFile(path)
+	custom := string(data) + "  lint:\n    cmds:\n      - golangci-lint run\n"
+	if err := os.WriteFile(path, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err = SyncTaskfile(path, TasksOptions{CLI: "./bin/app"})
+	if err != nil || created {
+		t.Fatalf("SyncTaskfile update: created=%v err=%v", created, err)
+	}
+
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "golangci-lint run") {
+		t.Error("custom task was removed")
+	}
+	if !strings.Contains(string(data), "./bin/app sync-registry") {
+		t.Error("generated block was not updated")
+	}
+	if strings.Count(string(data), TasksStartMarker) != 1 {
+		t.Error("marker block duplicated")
+	}
+}
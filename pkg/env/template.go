@@ -2,7 +2,6 @@ package env
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 )
@@ -37,6 +36,86 @@ type TemplateOptions struct {
 	GroupHeaderFormat func(groupName string) string
 }
 
+// Extend returns opts with additions layered on top, for template inheritance:
+//   - Header and Footer: additions are appended after the base lines
+//   - GroupOrder: additional groups are appended (duplicates dropped)
+//   - ValueOverrides: additions are consulted first, then the base
+//   - IncludeComments, IncludeGroupHeaders: can only be switched on
+//   - GroupHeaderFormat: replaced if set
+func (opts TemplateOptions) Extend(additions TemplateOptions) TemplateOptions {
+	merged := opts
+	merged.Header = append(append([]string{}, opts.Header...), additions.Header...)
+	merged.Footer = append(append([]string{}, opts.Footer...), additions.Footer...)
+
+	merged.GroupOrder = append([]string{}, opts.GroupOrder...)
+	for _, g := range additions.GroupOrder {
+		if !containsString(merged.GroupOrder, g) {
+			merged.GroupOrder = append(merged.GroupOrder, g)
+		}
+	}
+
+	if additions.ValueOverrides != nil {
+		base, add := opts.ValueOverrides, additions.ValueOverrides
+		merged.ValueOverrides = func(v EnvVar) (string, bool) {
+			if value, ok := add(v); ok {
+				return value, true
+			}
+			if base != nil {
+				return base(v)
+			}
+			return "", false
+		}
+	}
+
+	merged.IncludeComments = opts.IncludeComments || additions.IncludeComments
+	merged.IncludeGroupHeaders = opts.IncludeGroupHeaders || additions.IncludeGroupHeaders
+	if additions.GroupHeaderFormat != nil {
+		merged.GroupHeaderFormat = additions.GroupHeaderFormat
+	}
+	return merged
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// templateOrder returns vars sorted by group, then name. With groupOrder the
+// groups come in that order and groups not listed are left out; otherwise
+// they are alphabetical. One sort replaces grouping into a map and sorting
+// each group, which matters for registries with thousands of variables.
+func templateOrder(vars []EnvVar, groupOrder []string) []EnvVar {
+	rank := make(map[string]int, len(groupOrder))
+	for i, name := range groupOrder {
+		if _, dup := rank[name]; !dup {
+			rank[name] = i
+		}
+	}
+
+	sorted := make([]EnvVar, 0, len(vars))
+	for _, v := range vars {
+		if _, listed := rank[v.Group]; listed || len(groupOrder) == 0 {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Group != b.Group {
+			if len(groupOrder) > 0 {
+				return rank[a.Group] < rank[b.Group]
+			}
+			return a.Group < b.Group
+		}
+		return a.Name < b.Name
+	})
+	return sorted
+}
+
 // GenerateTemplate creates an environment file template from the registry
 // This is the core generic template builder used by all format-specific functions
 func (r *Registry) GenerateTemplate(opts TemplateOptions) string {
@@ -50,27 +129,6 @@ func (r *Registry) GenerateTemplate(opts TemplateOptions) string {
 		}
 	}
 
-	// Get groups
-	groups := r.GetByGroup()
-
-	// Determine group ordering
-	var groupNames []string
-	if len(opts.GroupOrder) > 0 {
-		// Use specified order, but only include groups that exist
-		for _, name := range opts.GroupOrder {
-			if _, exists := groups[name]; exists {
-				groupNames = append(groupNames, name)
-			}
-		}
-	} else {
-		// Alphabetical order
-		groupNames = make([]string, 0, len(groups))
-		for name := range groups {
-			groupNames = append(groupNames, name)
-		}
-		sort.Strings(groupNames)
-	}
-
 	// Default group header format
 	groupHeaderFmt := opts.GroupHeaderFormat
 	if groupHeaderFmt == nil {
@@ -79,52 +137,49 @@ func (r *Registry) GenerateTemplate(opts TemplateOptions) string {
 		}
 	}
 
-	// Process each group
-	for _, groupName := range groupNames {
-		vars := groups[groupName]
-
-		// Sort variables within group alphabetically by name
-		sort.Slice(vars, func(i, j int) bool {
-			return vars[i].Name < vars[j].Name
-		})
+	// Process the variables in one pass, in group order then by name, writing
+	// a header wherever the group changes
+	group := ""
+	for i, v := range templateOrder(r.All(), opts.GroupOrder) {
+		if opts.IncludeGroupHeaders && (i == 0 || v.Group != group) {
+			sb.WriteString(groupHeaderFmt(v.Group))
+		}
+		group = v.Group
 
-		// Write group header
-		if opts.IncludeGroupHeaders {
-			sb.WriteString(groupHeaderFmt(groupName))
+		// Add description comment
+		if opts.IncludeComments && v.Description != "" {
+			sb.WriteString(fmt.Sprintf("# %s\n", v.Description))
 		}
 
-		// Write each variable
-		for _, v := range vars {
-			// Add description comment
-			if opts.IncludeComments && v.Description != "" {
-				sb.WriteString(fmt.Sprintf("# %s\n", v.Description))
-			}
+		// Show the expected syntax of typed values
+		if opts.IncludeComments && v.Kind.Syntax() != "" {
+			sb.WriteString(fmt.Sprintf("# Format: %s\n", v.Kind.Syntax()))
+		}
 
-			// Mark as required
-			if opts.IncludeComments && v.Required {
-				sb.WriteString("# REQUIRED\n")
-			}
+		// Mark as required
+		if opts.IncludeComments && v.Required {
+			sb.WriteString("# REQUIRED\n")
+		}
 
-			// Determine value (custom override or default)
-			var value string
-			if opts.ValueOverrides != nil {
-				if customValue, useCustom := opts.ValueOverrides(v); useCustom {
-					value = customValue
-				} else {
-					value = v.Default
-				}
+		// Determine value (custom override or default)
+		var value string
+		if opts.ValueOverrides != nil {
+			if customValue, useCustom := opts.ValueOverrides(v); useCustom {
+				value = customValue
 			} else {
 				value = v.Default
 			}
+		} else {
+			value = v.Default
+		}
 
-			// Write variable line
-			if value != "" {
-				sb.WriteString(fmt.Sprintf("%s=%s\n", v.Name, value))
-			} else {
-				sb.WriteString(fmt.Sprintf("%s=\n", v.Name))
-			}
-			sb.WriteString("\n")
+		// Write variable line
+		if value != "" {
+			sb.WriteString(fmt.Sprintf("%s=%s\n", v.Name, value))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s=\n", v.Name))
 		}
+		sb.WriteString("\n")
 	}
 
 	// Write footer
@@ -170,45 +225,41 @@ func (r *Registry) GenerateEnvList(title string) string {
 	sb.WriteString(title + "\n")
 	sb.WriteString(strings.Repeat("=", len(title)) + "\n\n")
 
-	groups := r.GetByGroup()
-	groupNames := make([]string, 0, len(groups))
-	for name := range groups {
-		groupNames = append(groupNames, name)
-	}
-	sort.Strings(groupNames)
+	// Groups alphabetically, variables in registry order within a group
+	vars := append([]EnvVar(nil), r.All()...)
+	sort.SliceStable(vars, func(i, j int) bool { return vars[i].Group < vars[j].Group })
 
-	for _, groupName := range groupNames {
-		vars := groups[groupName]
-		sb.WriteString(fmt.Sprintf("## %s\n", groupName))
+	for i, v := range vars {
+		if i == 0 || v.Group != vars[i-1].Group {
+			sb.WriteString(fmt.Sprintf("## %s\n", v.Group))
+		}
 
-		for _, v := range vars {
-			// Status badges
-			status := ""
-			if v.Required {
-				status = " [REQUIRED]"
-			}
-			if v.Secret {
-				status += " [SECRET]"
-			}
+		// Status badges
+		status := ""
+		if v.Required {
+			status = " [REQUIRED]"
+		}
+		if v.Secret {
+			status += " [SECRET]"
+		}
 
-			// Current value (masked if secret)
-			currentValue := os.Getenv(v.Name)
-			valueDisplay := "not set"
-			if currentValue != "" {
-				if v.Secret {
-					valueDisplay = "***set***"
-				} else {
-					valueDisplay = currentValue
-				}
-			} else if v.Default != "" {
-				valueDisplay = fmt.Sprintf("(default: %s)", v.Default)
+		// Current value (masked if secret)
+		currentValue := r.Getenv(v.Name)
+		valueDisplay := "not set"
+		if currentValue != "" {
+			if v.Secret {
+				valueDisplay = "***set***"
+			} else {
+				valueDisplay = currentValue
 			}
-
-			sb.WriteString(fmt.Sprintf("  %s%s\n", v.Name, status))
-			sb.WriteString(fmt.Sprintf("    %s\n", v.Description))
-			sb.WriteString(fmt.Sprintf("    Current: %s\n", valueDisplay))
-			sb.WriteString("\n")
+		} else if v.Default != "" {
+			valueDisplay = fmt.Sprintf("(default: %s)", v.Default)
 		}
+
+		sb.WriteString(fmt.Sprintf("  %s%s\n", v.Name, status))
+		sb.WriteString(fmt.Sprintf("    %s\n", v.Description))
+		sb.WriteString(fmt.Sprintf("    Current: %s\n", valueDisplay))
+		sb.WriteString("\n")
 	}
 
 	return sb.String()
@@ -423,3 +474,90 @@ func (r *Registry) GenerateDockerComposeEnv(comments []string) string {
 
 	return sb.String()
 }
+
+// ================================================================
+// Container Image Generators (docker build, OCI labels, ko)
+// ================================================================
+
+// OCILabelPrefix is the label namespace used by GenerateOCILabels
+const OCILabelPrefix = "org.wellknown.env."
+
+// GenerateDockerBuildArgs returns --build-arg flags for non-secret variables.
+// The current environment value wins over the registry default; variables with
+// neither are skipped. Secrets are never included (build args end up in image history).
+//
+// Example:
+//
+//	args := append([]string{"build", "-t", "app", "."}, registry.GenerateDockerBuildArgs()...)
+//	exec.Command("docker", args...)
+func (r *Registry) GenerateDockerBuildArgs() []string {
+	var args []string
+	for _, v := range r.All() {
+		if v.Secret {
+			continue
+		}
+		value := v.GetString()
+		if value == "" {
+			continue
+		}
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", v.Name, value))
+	}
+	return args
+}
+
+// GenerateOCILabels generates a Dockerfile LABEL instruction documenting the
+// non-secret variables and their defaults, so the image itself records its config surface.
+//
+// Example:
+//
+//	LABEL org.wellknown.env.server_port="8080" \
+//	      org.wellknown.env.log_level="info"
+func (r *Registry) GenerateOCILabels() string {
+	var labels []string
+	for _, v := range r.All() {
+		if v.Secret {
+			continue
+		}
+		labels = append(labels, fmt.Sprintf("%s%s=%q", OCILabelPrefix, strings.ToLower(v.Name), v.Default))
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	return "LABEL " + strings.Join(labels, " \\\n      ") + "\n"
+}
+
+// goBuildEnv are the go command settings GenerateKoEnv takes from the registry
+var goBuildEnv = map[string]bool{
+	"CGO_ENABLED": true, "CGO_CFLAGS": true, "CGO_LDFLAGS": true,
+	"GOOS": true, "GOARCH": true, "GOAMD64": true, "GOARM": true, "GOARM64": true,
+	"GOEXPERIMENT": true, "GOFLAGS": true, "GOPRIVATE": true, "GOPROXY": true,
+	"GOTOOLCHAIN": true, "GOWORK": true,
+}
+
+// GenerateKoEnv generates the env list for a .ko.yaml build entry.
+// ko passes builds[].env to go build, so it holds build settings only: the
+// values never reach the running container. baseEnv holds the settings to
+// keep (e.g., "CGO_ENABLED=0"); non-secret registry defaults of go build
+// settings (goBuildEnv) are appended after them, and runtime variables are
+// left out. Indentation matches a builds[] item.
+//
+// Example:
+//
+//	# go build environment (build time only, not set in the image)
+//	env:
+//	  - CGO_ENABLED=0
+//	  - GOFLAGS=-trimpath
+func (r *Registry) GenerateKoEnv(baseEnv []string) string {
+	var sb strings.Builder
+	sb.WriteString("    # go build environment (build time only, not set in the image)\n")
+	sb.WriteString("    env:\n")
+	for _, e := range baseEnv {
+		sb.WriteString(fmt.Sprintf("      - %s\n", e))
+	}
+	for _, v := range r.All() {
+		if goBuildEnv[v.Name] && !v.Secret && v.Default != "" {
+			sb.WriteString(fmt.Sprintf("      - %s=%s\n", v.Name, v.Default))
+		}
+	}
+	return sb.String()
+}
@@ -244,6 +244,80 @@ func TestRegistry_GenerateTemplate_GroupSorting(t *testing.T) {
 	}
 }
 
+// ================================================================
+// Template Inheritance Tests
+// ================================================================
+
+func TestTemplateOptions_Extend(t *testing.T) {
+	base := TemplateOptions{
+		Header:     []string{"# base"},
+		GroupOrder: []string{"Server", "Database"},
+		ValueOverrides: func(v EnvVar) (string, bool) {
+			return "base-" + v.Name, v.Name == "A" || v.Name == "B"
+		},
+		IncludeComments: true,
+	}
+	merged := base.Extend(TemplateOptions{
+		Header:     []string{"# child"},
+		GroupOrder: []string{"Database", "Cache"},
+		ValueOverrides: func(v EnvVar) (string, bool) {
+			return "child-" + v.Name, v.Name == "A"
+		},
+	})
+
+	if strings.Join(merged.Header, ",") != "# base,# child" {
+		t.Errorf("Header = %v", merged.Header)
+	}
+	if strings.Join(merged.GroupOrder, ",") != "Server,Database,Cache" {
+		t.Errorf("GroupOrder = %v", merged.GroupOrder)
+	}
+	if v, _ := merged.ValueOverrides(EnvVar{Name: "A"}); v != "child-A" {
+		t.Errorf("override A = %q, want child-A", v)
+	}
+	if v, _ := merged.ValueOverrides(EnvVar{Name: "B"}); v != "base-B" {
+		t.Errorf("override B = %q, want base-B", v)
+	}
+	if !merged.IncludeComments {
+		t.Error("IncludeComments should be inherited")
+	}
+	if len(base.Header) != 1 || len(base.GroupOrder) != 2 {
+		t.Error("Extend must not modify the base options")
+	}
+}
+
+func TestEnvironment_Extends(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "PORT", Default: "8080", Group: "Server"},
+		{Name: "DB_URL", Default: "postgres://", Group: "Database"},
+	})
+
+	base := PartialTemplate(TemplateOptions{
+		Header:              []string{"# shared header"},
+		GroupOrder:          []string{"Server", "Database"},
+		IncludeGroupHeaders: true,
+	})
+	staging := &Environment{Name: "staging", FileName: ".env.staging", Extends: base,
+		Template: &TemplateOptions{Header: []string{"# staging"}}}
+
+	result := staging.Generate(registry, "Test App")
+
+	if !strings.HasPrefix(result, "# shared header\n# staging\n") {
+		t.Errorf("Expected shared header followed by staging header, got:\n%s", result)
+	}
+	if strings.Index(result, "PORT=") > strings.Index(result, "DB_URL=") {
+		t.Error("Expected inherited group order: Server before Database")
+	}
+	if strings.Contains(result, "Test App - staging") {
+		t.Error("Extending environment should not get the generic default header")
+	}
+
+	// Cycles must not hang
+	a := &Environment{Name: "a"}
+	b := &Environment{Name: "b", Extends: a}
+	a.Extends = b
+	_ = a.Generate(registry, "Test App")
+}
+
 // ================================================================
 // GenerateEnvExample Tests
 // ================================================================
@@ -448,6 +522,63 @@ func TestRegistry_GenerateTOMLEnv_CustomSection(t *testing.T) {
 	}
 }
 
+// ================================================================
+// Container Image Generator Tests
+// ================================================================
+
+func TestRegistry_GenerateDockerBuildArgs(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "TEST_BUILDARG_PORT", Default: "8080"},
+		{Name: "TEST_BUILDARG_SECRET", Secret: true, Default: "hunter2"},
+		{Name: "TEST_BUILDARG_EMPTY"},
+	})
+	t.Setenv("TEST_BUILDARG_PORT", "9090")
+
+	args := registry.GenerateDockerBuildArgs()
+	want := []string{"--build-arg", "TEST_BUILDARG_PORT=9090"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("GenerateDockerBuildArgs() = %v, want %v", args, want)
+	}
+}
+
+func TestRegistry_GenerateOCILabels(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "SERVER_PORT", Default: "8080"},
+		{Name: "LOG_LEVEL", Default: "info"},
+		{Name: "API_KEY", Secret: true},
+	})
+
+	result := registry.GenerateOCILabels()
+	for _, needle := range []string{`LABEL org.wellknown.env.server_port="8080"`, `org.wellknown.env.log_level="info"`} {
+		if !strings.Contains(result, needle) {
+			t.Errorf("Expected output to contain %q.\nOutput:\n%s", needle, result)
+		}
+	}
+	if strings.Contains(result, "api_key") {
+		t.Errorf("Secrets must not appear in labels.\nOutput:\n%s", result)
+	}
+
+	if got := NewRegistry([]EnvVar{{Name: "API_KEY", Secret: true}}).GenerateOCILabels(); got != "" {
+		t.Errorf("Expected no LABEL for secrets-only registry, got %q", got)
+	}
+}
+
+func TestRegistry_GenerateKoEnv(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "SERVER_PORT", Default: "8080"}, // Runtime: not a build setting
+		{Name: "GOFLAGS", Default: "-trimpath"},
+		{Name: "GOOGLE_CLIENT_ID", Default: "x"},
+		{Name: "API_KEY", Secret: true, Default: "x"},
+	})
+
+	result := registry.GenerateKoEnv([]string{"CGO_ENABLED=0"})
+	want := "    # go build environment (build time only, not set in the image)\n" +
+		"    env:\n      - CGO_ENABLED=0\n      - GOFLAGS=-trimpath\n"
+	if result != want {
+		t.Errorf("GenerateKoEnv() = %q, want %q", result, want)
+	}
+}
+
 // ================================================================
 // Edge Cases and Error Handling
 // ================================================================
@@ -0,0 +1,250 @@
+package env
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// ================================================================
+// Terminal Output
+// ================================================================
+// GenerateEnvList is plain text for files and pipes. FormatEnvListTerminal
+// renders the registry for people reading a terminal: a heading per group,
+// one aligned row per variable with its status colored, and summary counts.
+// FormatValidationTerminal shows only the variables that fail validation.
+//
+// Colors are fatih/color's; pass ColorEnabled() to use them only when stdout
+// is a terminal and NO_COLOR is not set.
+
+// maxValueWidth truncates long values so the description column stays aligned
+const maxValueWidth = 32
+
+// Variable statuses in terminal output
+const (
+	statusSet     = "set"
+	statusDefault = "default"
+	statusUnset   = "unset"
+	statusMissing = "missing" // Required and not set
+	statusInvalid = "invalid" // Set, but fails ValidateValue
+)
+
+// TerminalOptions configures FormatEnvListTerminal and FormatValidationTerminal
+type TerminalOptions struct {
+	Title string // Heading (default "Environment Variables Registry")
+	Color bool   // Emit ANSI colors (see ColorEnabled)
+}
+
+// ColorEnabled reports whether stdout takes colors: it is a terminal, TERM
+// is not "dumb" and NO_COLOR is not set
+func ColorEnabled() bool {
+	return !color.NoColor
+}
+
+// terminalRow is one variable as rendered
+type terminalRow struct {
+	v      EnvVar
+	status string
+	value  string
+	flags  string
+}
+
+// terminalRows returns every variable's row, groups alphabetically and
+// variables in registry order within a group (as GenerateEnvList)
+func (r *Registry) terminalRows() []terminalRow {
+	vars := append([]EnvVar(nil), r.All()...)
+	sort.SliceStable(vars, func(i, j int) bool { return vars[i].Group < vars[j].Group })
+
+	rows := make([]terminalRow, len(vars))
+	for i, v := range vars {
+		row := terminalRow{v: v, value: "-"}
+		value := r.Getenv(v.Name)
+		switch {
+		case value != "" && v.ValidateValue(value) != nil:
+			row.status = statusInvalid
+		case value != "":
+			row.status = statusSet
+		case v.Required:
+			row.status = statusMissing
+		case v.Default != "":
+			row.status = statusDefault
+		default:
+			row.status = statusUnset
+		}
+		switch {
+		case value != "" && v.Secret:
+			row.value = MaskedValue
+		case value != "":
+			row.value = value
+		case v.Default != "":
+			row.value = v.Default
+		}
+		row.value = truncate(row.value, maxValueWidth)
+
+		var flags []string
+		if v.Required {
+			flags = append(flags, "required")
+		}
+		if v.Secret {
+			flags = append(flags, "secret")
+		}
+		row.flags = strings.Join(flags, ",")
+		rows[i] = row
+	}
+	return rows
+}
+
+// FormatEnvListTerminal renders every variable for a terminal
+func (r *Registry) FormatEnvListTerminal(opts TerminalOptions) string {
+	rows := r.terminalRows()
+	if opts.Title == "" {
+		opts.Title = "Environment Variables Registry"
+	}
+	return formatTerminal(rows, rows, opts)
+}
+
+// FormatValidationTerminal renders the variables that are missing or invalid,
+// or a single line when there are none. Values of invalid secrets are masked.
+func (r *Registry) FormatValidationTerminal(opts TerminalOptions) string {
+	all := r.terminalRows()
+	var problems []terminalRow
+	for _, row := range all {
+		if row.status == statusMissing || row.status == statusInvalid {
+			if err := row.v.ValidateValue(r.Getenv(row.v.Name)); err != nil {
+				row.v.Description = err.Error()
+			}
+			problems = append(problems, row)
+		}
+	}
+	p := newPalette(opts.Color)
+	if len(problems) == 0 {
+		return p.ok.Sprintf("✅ All %d environment variables are valid", len(all)) + "\n"
+	}
+	if opts.Title == "" {
+		opts.Title = "Environment Validation"
+	}
+	return formatTerminal(problems, all, opts)
+}
+
+// formatTerminal renders rows under group headings, then the summary of all
+func formatTerminal(rows, all []terminalRow, opts TerminalOptions) string {
+	p := newPalette(opts.Color)
+	var sb strings.Builder
+	sb.WriteString(p.title.Sprint(opts.Title) + "\n")
+	sb.WriteString(p.faint.Sprint(strings.Repeat("=", utf8.RuneCountInString(opts.Title))) + "\n")
+
+	// Column widths over all rows, so every group lines up
+	var nameW, flagsW, statusW, valueW int
+	for _, row := range rows {
+		nameW = max(nameW, utf8.RuneCountInString(row.v.Name))
+		flagsW = max(flagsW, utf8.RuneCountInString(row.flags))
+		statusW = max(statusW, utf8.RuneCountInString(row.status))
+		valueW = max(valueW, utf8.RuneCountInString(row.value))
+	}
+
+	for i, row := range rows {
+		if i == 0 || row.v.Group != rows[i-1].v.Group {
+			n := 0
+			for _, other := range rows[i:] {
+				if other.v.Group != row.v.Group {
+					break
+				}
+				n++
+			}
+			group := row.v.Group
+			if group == "" {
+				group = "Other"
+			}
+			sb.WriteString("\n" + p.group.Sprint(group) + " " + p.faint.Sprintf("(%d)", n) + "\n")
+		}
+		sb.WriteString("  " + p.name.Sprint(pad(row.v.Name, nameW)))
+		sb.WriteString("  " + p.faint.Sprint(pad(row.flags, flagsW)))
+		sb.WriteString("  " + p.status(row.status).Sprint(pad(row.status, statusW)))
+		sb.WriteString("  " + pad(row.value, valueW))
+		sb.WriteString("  " + p.faint.Sprint(row.v.Description))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n" + formatSummary(all, p) + "\n")
+	return sb.String()
+}
+
+// formatSummary counts rows by status: "42 variables in 9 groups: 10 set, ..."
+func formatSummary(rows []terminalRow, p palette) string {
+	counts := make(map[string]int)
+	groups := make(map[string]bool)
+	for _, row := range rows {
+		counts[row.status]++
+		groups[row.v.Group] = true
+	}
+	parts := []string{}
+	for _, status := range []string{statusSet, statusDefault, statusUnset, statusMissing, statusInvalid} {
+		if counts[status] > 0 {
+			parts = append(parts, p.status(status).Sprintf("%d %s", counts[status], status))
+		}
+	}
+	summary := fmt.Sprintf("%d variables in %d groups", len(rows), len(groups))
+	if len(parts) > 0 {
+		summary += ": " + strings.Join(parts, ", ")
+	}
+	return summary
+}
+
+// palette holds the colors of one rendering
+type palette struct {
+	title, group, name, faint, ok, warn, bad *color.Color
+}
+
+// newPalette returns the colors, enabled or not regardless of the terminal
+func newPalette(enabled bool) palette {
+	mk := func(attrs ...color.Attribute) *color.Color {
+		c := color.New(attrs...)
+		if enabled {
+			c.EnableColor()
+		} else {
+			c.DisableColor()
+		}
+		return c
+	}
+	return palette{
+		title: mk(color.Bold),
+		group: mk(color.Bold, color.FgCyan),
+		name:  mk(color.Bold),
+		faint: mk(color.Faint),
+		ok:    mk(color.FgGreen),
+		warn:  mk(color.FgYellow),
+		bad:   mk(color.FgRed, color.Bold),
+	}
+}
+
+// status returns the color of a variable status
+func (p palette) status(status string) *color.Color {
+	switch status {
+	case statusSet:
+		return p.ok
+	case statusMissing:
+		return p.bad
+	case statusInvalid:
+		return p.warn
+	}
+	return p.faint
+}
+
+// pad right-pads s with spaces to width runes
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// truncate shortens s to width runes, ending in "…"
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
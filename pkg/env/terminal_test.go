@@ -0,0 +1,95 @@
+package env
+
+import (
+	"strings"
+	"testing"
+)
+
+func terminalTestRegistry(t *testing.T) *Registry {
+	t.Setenv("TERM_TEST_PORT", "9090")
+	t.Setenv("TERM_TEST_TOKEN", "s3cr3t-value")
+	t.Setenv("TERM_TEST_TIMEOUT", "soon")
+	return NewRegistry([]EnvVar{
+		{Name: "TERM_TEST_PORT", Description: "Port", Default: "8080", Group: "Server"},
+		{Name: "TERM_TEST_HOST", Description: "Host", Default: "localhost", Group: "Server"},
+		{Name: "TERM_TEST_TIMEOUT", Description: "Timeout", Kind: KindDuration, Group: "Server"},
+		{Name: "TERM_TEST_TOKEN", Description: "API token", Secret: true, Group: "Auth"},
+		{Name: "TERM_TEST_CLIENT_ID", Description: "OAuth client", Required: true, Secret: true, Group: "Auth"},
+		{Name: "TERM_TEST_DEBUG", Description: "Debug logging", Group: "Server"},
+	})
+}
+
+func TestFormatEnvListTerminal(t *testing.T) {
+	out := terminalTestRegistry(t).FormatEnvListTerminal(TerminalOptions{})
+
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("Color: false should not emit escape codes:\n%s", out)
+	}
+	if strings.Contains(out, "s3cr3t-value") {
+		t.Error("secret value should be masked")
+	}
+	// Groups alphabetically, each with its count
+	if auth, server := strings.Index(out, "Auth (2)"), strings.Index(out, "Server (4)"); auth < 0 || server < auth {
+		t.Errorf("expected Auth (2) before Server (4):\n%s", out)
+	}
+
+	// Every row's description starts in the same column
+	column := -1
+	for _, desc := range []string{"Port", "Host", "Timeout", "API token", "OAuth client", "Debug logging"} {
+		for _, line := range strings.Split(out, "\n") {
+			if !strings.HasSuffix(line, "  "+desc) {
+				continue
+			}
+			at := len(line) - len(desc)
+			if column == -1 {
+				column = at
+			} else if at != column {
+				t.Errorf("misaligned row %q (description at %d, want %d)", line, at, column)
+			}
+		}
+	}
+	if column == -1 {
+		t.Fatalf("no rows found:\n%s", out)
+	}
+
+	for _, want := range []string{
+		"TERM_TEST_CLIENT_ID  required,secret  missing",
+		"TERM_TEST_TOKEN      secret           set      ***set***",
+		"TERM_TEST_HOST                        default  localhost",
+		"TERM_TEST_TIMEOUT                     invalid  soon",
+		"6 variables in 2 groups: 2 set, 1 default, 1 unset, 1 missing, 1 invalid",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatEnvListTerminal_Color(t *testing.T) {
+	out := terminalTestRegistry(t).FormatEnvListTerminal(TerminalOptions{Title: "Vars", Color: true})
+
+	if !strings.HasPrefix(out, "\x1b[1mVars\x1b[") {
+		t.Errorf("expected a bold title, got %q", strings.SplitN(out, "\n", 2)[0])
+	}
+	if !strings.Contains(out, "\x1b[31;1mmissing\x1b[") {
+		t.Errorf("expected missing in bold red:\n%q", out)
+	}
+}
+
+func TestFormatValidationTerminal(t *testing.T) {
+	out := terminalTestRegistry(t).FormatValidationTerminal(TerminalOptions{})
+
+	for _, want := range []string{"Environment Validation", "TERM_TEST_CLIENT_ID", "TERM_TEST_TIMEOUT", "expected duration"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "TERM_TEST_PORT") || strings.Contains(out, "TERM_TEST_HOST") {
+		t.Errorf("valid variables should not be listed:\n%s", out)
+	}
+
+	valid := NewRegistry([]EnvVar{{Name: "TERM_TEST_UNUSED", Default: "x"}})
+	if out := valid.FormatValidationTerminal(TerminalOptions{}); out != "✅ All 1 environment variables are valid\n" {
+		t.Errorf("got %q", out)
+	}
+}
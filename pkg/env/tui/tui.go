@@ -0,0 +1,435 @@
+// Package tui provides an interactive terminal UI for an env.Registry.
+//
+// Users browse variables by group, toggle booleans, edit values (secrets with
+// masked input), validate, save to the environment file and run sync actions
+// without leaving the terminal:
+//
+//	err := tui.Run(tui.Options{
+//	    Registry:    registry,
+//	    Environment: env.Local,
+//	    Actions: []tui.Action{
+//	        {Key: "S", Name: "sync secrets", Run: syncSecrets},
+//	    },
+//	})
+//
+// Keys: ←/→ switch group, ↑/↓ select, enter edit, space toggle, v validate,
+// w save, q quit. Action keys are listed in the footer.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// Action is a workflow the user can trigger with a key (e.g. syncing secrets).
+// The terminal is released while Run executes, so it may print and prompt.
+type Action struct {
+	Key  string // Single key, e.g. "S" (must not clash with the built-in keys)
+	Name string // Shown in the footer
+	Run  func() error
+}
+
+// Options configures Run and New.
+type Options struct {
+	Registry    *env.Registry    // Variables to manage (required)
+	Environment *env.Environment // File values are read from and saved to (default: env.Local)
+	AppName     string           // Header of a newly generated file (default: "Application")
+	Actions     []Action         // Extra workflows, e.g. sync-secrets
+}
+
+// Run opens the TUI on the terminal and returns when the user quits.
+func Run(opts Options) error {
+	m, err := New(opts)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+// Model is the bubbletea model behind Run, exported for embedding in other programs.
+type Model struct {
+	opts   Options
+	groups []string
+	vars   map[string][]env.EnvVar
+
+	values map[string]string // Current file values (edited in place)
+	dirty  bool
+
+	group, cursor int
+	editing       bool
+	input         textinput.Model
+
+	problems []string // Last validation result
+	status   string
+	quitting bool
+}
+
+// New loads the environment file and returns the model
+func New(opts Options) (*Model, error) {
+	if opts.Registry == nil {
+		return nil, fmt.Errorf("registry is required")
+	}
+	if opts.Environment == nil {
+		opts.Environment = env.Local
+	}
+	if opts.AppName == "" {
+		opts.AppName = "Application"
+	}
+
+	m := &Model{opts: opts, vars: opts.Registry.GetByGroup(), input: textinput.New()}
+	if err := m.reload(); err != nil {
+		return nil, err
+	}
+	for g := range m.vars {
+		m.groups = append(m.groups, g)
+	}
+	sort.Strings(m.groups)
+	m.status = fmt.Sprintf("Editing %s", opts.Environment.FullPath())
+	return m, nil
+}
+
+// reload reads the values from the environment file (none if it is missing)
+func (m *Model) reload() error {
+	m.values = map[string]string{}
+	data, err := os.ReadFile(m.opts.Environment.FullPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	m.values = env.ParseSecretsFile(data)
+	return nil
+}
+
+// Init implements tea.Model
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// actionDoneMsg reports the result of an Action
+type actionDoneMsg struct {
+	name string
+	err  error
+}
+
+// Update implements tea.Model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("❌ %s: %v", msg.name, msg.err)
+		} else {
+			m.status = fmt.Sprintf("✅ %s done", msg.name)
+		}
+		// Actions may rewrite the file; unsaved edits win over it
+		if !m.dirty {
+			if err := m.reload(); err != nil {
+				m.status = fmt.Sprintf("❌ Reload failed: %v", err)
+			}
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEditing(msg)
+		}
+		return m.updateBrowsing(msg)
+	}
+	return m, nil
+}
+
+// updateBrowsing handles keys while navigating the variable list
+func (m *Model) updateBrowsing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	vars := m.current()
+	switch msg.String() {
+	case "q", "ctrl+c", "esc":
+		m.quitting = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(vars)-1 {
+			m.cursor++
+		}
+	case "left", "h", "shift+tab":
+		m.switchGroup(-1)
+	case "right", "l", "tab":
+		m.switchGroup(1)
+	case " ":
+		if v, ok := m.selected(); ok && isBool(v) {
+			m.set(v, fmt.Sprint(!isTrue(m.value(v))))
+		}
+	case "enter":
+		if v, ok := m.selected(); ok {
+			m.startEditing(v)
+			return m, textinput.Blink
+		}
+	case "v":
+		m.problems = m.validate()
+		if len(m.problems) == 0 {
+			m.status = "✅ All values valid"
+		} else {
+			m.status = fmt.Sprintf("❌ %d problems", len(m.problems))
+		}
+	case "w":
+		if err := m.Save(); err != nil {
+			m.status = fmt.Sprintf("❌ Save failed: %v", err)
+		} else {
+			m.status = fmt.Sprintf("✅ Saved %s", m.opts.Environment.FullPath())
+		}
+	default:
+		for _, a := range m.opts.Actions {
+			if msg.String() == a.Key {
+				return m, m.runAction(a)
+			}
+		}
+	}
+	return m, nil
+}
+
+// updateEditing handles keys while the value input is focused
+func (m *Model) updateEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v, _ := m.selected()
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.status = "Edit cancelled"
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		if err := v.ValidateValue(value); err != nil {
+			m.status = fmt.Sprintf("❌ %s: %v", v.Name, err)
+			return m, nil
+		}
+		m.set(v, value)
+		m.editing = false
+		m.status = fmt.Sprintf("%s updated (w to save)", v.Name)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) startEditing(v env.EnvVar) {
+	m.input = textinput.New()
+	m.input.Prompt = v.Name + "="
+	m.input.Placeholder = v.Default
+	m.input.SetValue(m.values[v.Name])
+	if v.Secret {
+		m.input.EchoMode = textinput.EchoPassword
+		m.input.EchoCharacter = '•'
+	}
+	m.input.Focus()
+	m.editing = true
+	m.status = "enter: apply  esc: cancel"
+	if syntax := v.Kind.Syntax(); syntax != "" {
+		m.status += "  (" + syntax + ")"
+	}
+}
+
+// runAction runs a with the terminal released and reports the result
+func (m *Model) runAction(a Action) tea.Cmd {
+	return tea.Exec(&actionCommand{run: a.Run}, func(err error) tea.Msg {
+		return actionDoneMsg{name: a.Name, err: err}
+	})
+}
+
+// actionCommand adapts an Action to tea.ExecCommand
+type actionCommand struct {
+	run func() error
+}
+
+func (c *actionCommand) Run() error          { return c.run() }
+func (c *actionCommand) SetStdin(io.Reader)  {}
+func (c *actionCommand) SetStdout(io.Writer) {}
+func (c *actionCommand) SetStderr(io.Writer) {}
+
+func (m *Model) switchGroup(delta int) {
+	if len(m.groups) == 0 {
+		return
+	}
+	m.group = (m.group + delta + len(m.groups)) % len(m.groups)
+	m.cursor = 0
+}
+
+func (m *Model) current() []env.EnvVar {
+	if len(m.groups) == 0 {
+		return nil
+	}
+	return m.vars[m.groups[m.group]]
+}
+
+func (m *Model) selected() (env.EnvVar, bool) {
+	vars := m.current()
+	if m.cursor >= len(vars) {
+		return env.EnvVar{}, false
+	}
+	return vars[m.cursor], true
+}
+
+// value returns the file value of v, or its default
+func (m *Model) value(v env.EnvVar) string {
+	if value, ok := m.values[v.Name]; ok && value != "" {
+		return value
+	}
+	return v.Default
+}
+
+func (m *Model) set(v env.EnvVar, value string) {
+	m.values[v.Name] = value
+	m.dirty = true
+}
+
+// validate checks every variable's value and that required ones are set
+func (m *Model) validate() []string {
+	var problems []string
+	for _, v := range m.opts.Registry.AllSorted() {
+		value := m.values[v.Name]
+		if value == "" && v.Required && v.Default == "" {
+			problems = append(problems, fmt.Sprintf("%s is required", v.Name))
+			continue
+		}
+		if err := v.ValidateValue(value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", v.Name, err))
+		}
+	}
+	return problems
+}
+
+// Save writes the edited values to the environment file, keeping its comments
+// and layout (a new file is generated from the registry first)
+func (m *Model) Save() error {
+	path := m.opts.Environment.FullPath()
+	template := m.opts.Environment.Generate(m.opts.Registry, m.opts.AppName)
+	if data, err := os.ReadFile(path); err == nil {
+		template = string(data)
+	}
+
+	merged, err := env.MergeIntoTemplate(template, m.values)
+	if err != nil {
+		return err
+	}
+	content := strings.TrimRight(merged, "\n") + "\n"
+	existing := env.ParseSecretsFile([]byte(content))
+	var missing []string
+	for name, value := range m.values {
+		if _, ok := existing[name]; !ok {
+			missing = append(missing, name+"="+value)
+		}
+	}
+	sort.Strings(missing)
+	for _, line := range missing {
+		content += line + "\n"
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return err
+	}
+	m.dirty = false
+	return nil
+}
+
+// isBool reports whether v is a true/false flag (by its default)
+func isBool(v env.EnvVar) bool {
+	d := strings.ToLower(v.Default)
+	return d == "true" || d == "false"
+}
+
+func isTrue(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "1", "yes":
+		return true
+	}
+	return false
+}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	activeStyle   = lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1)
+	tabStyle      = lipgloss.NewStyle().Padding(0, 1)
+	selectedStyle = lipgloss.NewStyle().Bold(true)
+	dimStyle      = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// View implements tea.Model
+func (m *Model) View() string {
+	if m.quitting {
+		return ""
+	}
+	var b strings.Builder
+
+	title := "Environment: " + m.opts.Environment.FullPath()
+	if m.dirty {
+		title += " (modified)"
+	}
+	b.WriteString(titleStyle.Render(title) + "\n\n")
+
+	var tabs []string
+	for i, g := range m.groups {
+		if i == m.group {
+			tabs = append(tabs, activeStyle.Render(g))
+		} else {
+			tabs = append(tabs, tabStyle.Render(g))
+		}
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...) + "\n\n")
+
+	for i, v := range m.current() {
+		line := fmt.Sprintf("%-28s %s", v.Name, m.displayValue(v))
+		if v.Required {
+			line += " *"
+		}
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render("> "+line) + "\n")
+			if v.Description != "" {
+				b.WriteString(dimStyle.Render("    "+v.Description) + "\n")
+			}
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if m.editing {
+		b.WriteString("\n" + m.input.View() + "\n")
+	}
+	for _, p := range m.problems {
+		b.WriteString(errorStyle.Render("  • "+p) + "\n")
+	}
+
+	b.WriteString("\n" + m.status + "\n")
+	help := "←/→ group  ↑/↓ select  enter edit  space toggle  v validate  w save  q quit"
+	for _, a := range m.opts.Actions {
+		help += fmt.Sprintf("  %s %s", a.Key, a.Name)
+	}
+	b.WriteString(dimStyle.Render(help) + "\n")
+	return b.String()
+}
+
+// displayValue shows secrets masked and defaults dimmed
+func (m *Model) displayValue(v env.EnvVar) string {
+	value, set := m.values[v.Name]
+	switch {
+	case set && value != "" && v.Secret:
+		return "••••••••"
+	case set && value != "":
+		return value
+	case v.Default != "":
+		return dimStyle.Render(v.Default + " (default)")
+	default:
+		return dimStyle.Render("(not set)")
+	}
+}
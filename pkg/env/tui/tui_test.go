@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+func newTestModel(t *testing.T, content string) (*Model, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if content != "" {
+		if err := os.WriteFile(filepath.Join(dir, ".env.local"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "API_KEY", Secret: true, Required: true, Group: "API"},
+		{Name: "DEBUG", Default: "false", Group: "Server"},
+		{Name: "PORT", Default: "8080", Group: "Server"},
+	})
+	m, err := New(Options{Registry: registry, Environment: env.Local.WithBaseDir(dir)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, filepath.Join(dir, ".env.local")
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "right":
+		return tea.KeyMsg{Type: tea.KeyRight}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestModel_ToggleAndSave(t *testing.T) {
+	m, path := newTestModel(t, "# keep me\nDEBUG=false\n")
+
+	m.Update(key("right")) // Server group
+	m.Update(key(" "))     // DEBUG
+	if got := m.values["DEBUG"]; got != "true" {
+		t.Fatalf("DEBUG = %q after toggle, want true", got)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# keep me\nDEBUG=true\n") {
+		t.Errorf("saved file lost its layout:\n%s", data)
+	}
+}
+
+func TestModel_EditRejectsInvalid(t *testing.T) {
+	m, _ := newTestModel(t, "")
+
+	m.Update(key("right"))
+	m.Update(key("j")) // PORT
+	m.Update(key("enter"))
+	m.Update(key("abc"))
+	m.Update(key("enter"))
+	if !m.editing || m.values["PORT"] != "" {
+		t.Fatalf("invalid PORT accepted: editing=%v value=%q", m.editing, m.values["PORT"])
+	}
+
+	m.input.SetValue("9090")
+	m.Update(key("enter"))
+	if m.editing || m.values["PORT"] != "9090" {
+		t.Errorf("valid PORT not applied: editing=%v value=%q", m.editing, m.values["PORT"])
+	}
+}
+
+func TestModel_ValidateAndNewFile(t *testing.T) {
+	m, path := newTestModel(t, "")
+
+	m.Update(key("v"))
+	if len(m.problems) != 1 || !strings.Contains(m.problems[0], "API_KEY") {
+		t.Errorf("problems = %v, want missing API_KEY", m.problems)
+	}
+
+	m.values["API_KEY"] = "sk-live"
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if got := env.ParseSecretsFile(data)["API_KEY"]; got != "sk-live" {
+		t.Errorf("API_KEY = %q in new file", got)
+	}
+	if strings.Contains(m.View(), "sk-live") {
+		t.Error("View shows the secret value")
+	}
+}
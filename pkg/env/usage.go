@@ -0,0 +1,113 @@
+// Package env provides runtime usage tracking for registry variables.
+package env
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// VariableUsage records how often a variable was read at runtime.
+type VariableUsage struct {
+	Name      string    `json:"name"`
+	Reads     int64     `json:"reads"`
+	FirstRead time.Time `json:"first_read"`
+	LastRead  time.Time `json:"last_read"`
+}
+
+// UsageReport summarizes runtime reads against a registry.
+type UsageReport struct {
+	Enabled bool            `json:"enabled"`
+	Since   time.Time       `json:"since"`  // When tracking was enabled or last reset
+	Used    []VariableUsage `json:"used"`   // Variables read at least once, most-read first
+	Unused  []string        `json:"unused"` // Registry variables never read (candidates for deletion)
+}
+
+var (
+	usageEnabled atomic.Bool
+	usageMu      sync.Mutex
+	usageSince   time.Time
+	usageCounts  = make(map[string]*VariableUsage)
+)
+
+// EnableUsageTracking starts counting GetString/GetInt/GetBool calls per variable.
+// Tracking is off by default; when off the accessors pay only an atomic load.
+//
+// Example:
+//
+//	env.EnableUsageTracking()
+//	// ... run the app for a while, then check /env/usage for dead configuration
+func EnableUsageTracking() {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	if !usageEnabled.Load() {
+		usageSince = time.Now()
+	}
+	usageEnabled.Store(true)
+}
+
+// DisableUsageTracking stops counting reads (collected data is kept).
+func DisableUsageTracking() {
+	usageEnabled.Store(false)
+}
+
+// UsageTrackingEnabled reports whether reads are being counted.
+func UsageTrackingEnabled() bool {
+	return usageEnabled.Load()
+}
+
+// ResetUsage clears all collected counters.
+func ResetUsage() {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	usageCounts = make(map[string]*VariableUsage)
+	usageSince = time.Now()
+}
+
+// recordUsage counts one read of name (no-op unless tracking is enabled)
+func recordUsage(name string) {
+	if !usageEnabled.Load() {
+		return
+	}
+	now := time.Now()
+
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	u, ok := usageCounts[name]
+	if !ok {
+		u = &VariableUsage{Name: name, FirstRead: now}
+		usageCounts[name] = u
+	}
+	u.Reads++
+	u.LastRead = now
+}
+
+// UsageReport returns read counts for the registry's variables and lists those never read.
+func (r *Registry) UsageReport() UsageReport {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+
+	report := UsageReport{
+		Enabled: usageEnabled.Load(),
+		Since:   usageSince,
+		Used:    []VariableUsage{},
+		Unused:  []string{},
+	}
+	for _, v := range r.All() {
+		if u, ok := usageCounts[v.Name]; ok {
+			report.Used = append(report.Used, *u)
+		} else {
+			report.Unused = append(report.Unused, v.Name)
+		}
+	}
+
+	sort.Slice(report.Used, func(i, j int) bool {
+		if report.Used[i].Reads != report.Used[j].Reads {
+			return report.Used[i].Reads > report.Used[j].Reads
+		}
+		return report.Used[i].Name < report.Used[j].Name
+	})
+	sort.Strings(report.Unused)
+	return report
+}
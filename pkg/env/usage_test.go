@@ -0,0 +1,39 @@
+package env
+
+import "testing"
+
+func TestUsageTracking(t *testing.T) {
+	registry := NewRegistry([]EnvVar{
+		{Name: "TEST_USAGE_PORT", Default: "8080"},
+		{Name: "TEST_USAGE_DEBUG", Default: "false"},
+		{Name: "TEST_USAGE_DEAD"},
+	})
+
+	// Disabled by default: reads are not counted
+	registry.ByName("TEST_USAGE_PORT").GetString()
+	if report := registry.UsageReport(); report.Enabled || len(report.Used) != 0 {
+		t.Fatalf("expected no usage while disabled, got %+v", report)
+	}
+
+	EnableUsageTracking()
+	ResetUsage()
+	defer func() {
+		DisableUsageTracking()
+		ResetUsage()
+	}()
+
+	registry.ByName("TEST_USAGE_PORT").GetInt()
+	registry.ByName("TEST_USAGE_PORT").GetString()
+	registry.ByName("TEST_USAGE_DEBUG").GetBool()
+
+	report := registry.UsageReport()
+	if !report.Enabled {
+		t.Error("expected Enabled")
+	}
+	if len(report.Used) != 2 || report.Used[0].Name != "TEST_USAGE_PORT" || report.Used[0].Reads != 2 {
+		t.Errorf("Used = %+v", report.Used)
+	}
+	if len(report.Unused) != 1 || report.Unused[0] != "TEST_USAGE_DEAD" {
+		t.Errorf("Unused = %v, want [TEST_USAGE_DEAD]", report.Unused)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build js && wasm
+
+// Command wasm is Age encryption compiled to WebAssembly, so the /env page
+// of pkg/env/webui seals secret values in the browser with the same library
+// the server decrypts them with, served from the binary rather than a CDN.
+// Build it with `make env-wasm`.
+//
+// It defines one global function:
+//
+//	wellknownSeal(recipient, value) -> {ciphertext, error}
+//
+// recipient is an age1... X25519 recipient and ciphertext the ASCII-armored
+// Age file POST /env/sealed accepts; error is set instead when sealing fails.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"syscall/js"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+func main() {
+	js.Global().Set("wellknownSeal", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 2 {
+			return result("", errors.New("wellknownSeal(recipient, value)"))
+		}
+		return result(seal(args[0].String(), args[1].String()))
+	}))
+
+	select {} // Keep the function alive for the page's lifetime
+}
+
+// seal encrypts value to recipient as an armored Age file
+func seal(recipient, value string) (string, error) {
+	r, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, r)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, value); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	if err := aw.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func result(ciphertext string, err error) map[string]any {
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	return map[string]any{"ciphertext": ciphertext}
+}
@@ -49,7 +49,12 @@
 // # Available Endpoints
 //
 //   - GET /env - Environment variables view (HTML by default, ?format=json for JSON)
-//   - GET /health - Health check with environment detection and uptime
+//   - GET /health - Health check with environment detection and uptime (package health;
+//     share the application's checker with WithHealth, or register checks on Health())
+//   - GET /env/events - Server-Sent Events stream of registry changes (Add/Remove/Override)
+//   - GET /env/usage - Variables read at runtime vs never read (requires env.EnableUsageTracking)
+//   - GET /env/recipient, POST /env/sealed - Sealed editing of secrets (see below)
+//   - POST /env/actions/{name} - Operator actions added with WithAction (see below)
 //
 // # HTML View Features
 //
@@ -82,6 +87,78 @@
 //	  }
 //	}
 //
+// # What-if Mode
+//
+// Add ?simulate=VAR1,VAR2 to render the page as if those variables were unset.
+// A summary lists the groups that would break (required variables missing) or
+// degrade (optional variables falling back to defaults), which helps when
+// planning deployments and writing runbooks. Works with ?format=json too, where
+// the result is returned under "simulation".
+//
+// # Embedding
+//
+// Handler.Status returns the counts behind /env and /health (missing required
+// variables, secret findings, uptime) for status pages that show the
+// environment next to other subsystems, such as the PocketBase /admin/status page.
+//
+// # Sealed Editing
+//
+// WithSealedEditing makes secret values editable from /env without the server
+// ever seeing them: the page encrypts each value in the browser to the
+// server's published Age recipient and POSTs only the ciphertext, which is
+// written to an env.SealedStore (one .age file per variable). The page
+// encrypts with Age compiled to WebAssembly (`make env-wasm`, embedded in the
+// binary and served from GET /env/age.wasm), not a script from a CDN. Decrypt
+// where the identity lives:
+//
+//	recipient, err := age.ParseX25519Recipient("age1...")
+//	handler := webui.NewHandler(registry).
+//	    WithSealedEditing(recipient, env.NewSealedStore(env.DefaultSealedDir))
+//
+//	values, err := env.NewSealedStore(env.DefaultSealedDir).Load() // needs the identity
+//
+// # Permission Tiers
+//
+// WithRoles gives every request a role from a callback, so any identity
+// system can plug in. Viewers see only whether each variable is set; operators
+// also see non-secret values, edit sealed secrets and trigger actions such as
+// a reload or sync. RoleNone is refused with 403; /health stays public.
+// Without WithRoles every caller is an operator.
+//
+//	handler := webui.NewHandler(registry).
+//	    WithRoles(func(r *http.Request) webui.Role {
+//	        switch r.Header.Get("X-Forwarded-Groups") { // set by the auth proxy
+//	        case "ops":
+//	            return webui.RoleOperator
+//	        case "staff":
+//	            return webui.RoleViewer
+//	        }
+//	        return webui.RoleNone
+//	    }).
+//	    WithAction(webui.Action{Name: "reload", Label: "Reload config", Run: reloadConfig})
+//
+// # Branding
+//
+// WithOptions white-labels the HTML views for products that embed them: a
+// title and logo in place of "env", a primary color for links and buttons,
+// and a footer. Options.Templates overrides the embedded html/template files
+// by name ("page", "styles", "brand", "footer"; see templates/page.html):
+//
+//	//go:embed branding/*.html
+//	var branding embed.FS
+//
+//	sub, _ := fs.Sub(branding, "branding")
+//	handler := webui.NewHandler(registry).WithOptions(webui.Options{
+//	    Title:        "Acme Config",
+//	    Logo:         "/static/acme.svg",
+//	    PrimaryColor: "#d9480f",
+//	    FooterHTML:   `&copy; Acme Inc. · <a href="/support">Support</a>`,
+//	    Templates:    sub, // e.g. brand.html: {{define "brand"}}...{{end}}
+//	})
+//	if err := handler.TemplateError(); err != nil {
+//	    log.Fatal(err) // Otherwise every page shows the error
+//	}
+//
 // # Environment Detection
 //
 // The webui automatically detects the runtime environment:
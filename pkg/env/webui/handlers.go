@@ -11,63 +11,147 @@ package webui
 import (
 	"encoding/json"
 	"fmt"
+	"html"
+	"html/template"
 	"net/http"
-	"os"
-	"runtime"
+	"net/url"
 	"strings"
-	"time"
+
+	"filippo.io/age"
 
 	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/guard"
+	"github.com/joeblew999/wellknown/pkg/health"
 )
 
 // Handler provides HTTP handlers for environment variable inspection.
 type Handler struct {
-	registry  *env.Registry
-	baseURL   string
-	startTime time.Time
+	registry *env.Registry
+	baseURL  string
+	health   *health.Checker
+	guard    func(http.Handler) http.Handler
+
+	// Sealed editing (see WithSealedEditing)
+	recipient *age.X25519Recipient
+	sealed    *env.SealedStore
+
+	// Permission tiers (see WithRoles) and operator actions (see WithAction)
+	roles   RoleFunc
+	actions []Action
+
+	// Branding (see WithOptions)
+	options     Options
+	templates   *template.Template
+	templateErr error // Override parse error, reported by every page
 }
 
 // NewHandler creates a new webui handler for the given registry.
 func NewHandler(registry *env.Registry) *Handler {
 	return &Handler{
 		registry:  registry,
-		startTime: time.Now(),
+		health:    health.New(""),
+		options:   Options{Title: defaultTitle},
+		templates: pageTemplates,
 	}
 }
 
+// WithVersion overrides the version reported by /health (default: VCS revision
+// or module version from the build info). deploy.VerifyDeployment compares it
+// against the expected version after a deploy.
+func (h *Handler) WithVersion(version string) *Handler {
+	h.health.WithVersion(version)
+	return h
+}
+
+// WithHealth serves /health from the application's checker (see package
+// health), so checks it registers show up there. Call before WithVersion.
+func (h *Handler) WithHealth(c *health.Checker) *Handler {
+	h.health = c
+	return h
+}
+
+// Health returns the checker behind /health, to register checks on
+func (h *Handler) Health() *health.Checker {
+	return h.health
+}
+
+// WithGuard applies CORS and rate limiting (see package guard) to the webui routes,
+// for deployments where /env and /health are reachable from the internet.
+func (h *Handler) WithGuard(cfg guard.Config) *Handler {
+	h.guard = cfg.Middleware()
+	return h
+}
+
 // RegisterRoutes registers all webui routes on the given mux.
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/env", h.handleEnv)
-	mux.HandleFunc("/health", h.handleHealth)
+	mux.Handle("/env", h.wrap(h.require(RoleViewer, h.handleEnv)))
+	mux.Handle("/env/events", h.wrap(h.require(RoleViewer, h.handleEvents)))
+	mux.Handle("/env/usage", h.wrap(h.require(RoleViewer, h.handleUsage)))
+	mux.Handle("/health", h.wrap(h.health.ServeHTTP))
+	if h.sealed != nil {
+		mux.Handle("/env/recipient", h.wrap(h.require(RoleOperator, h.handleRecipient)))
+		mux.Handle("/env/sealed", h.wrap(h.require(RoleOperator, h.handleSealed)))
+		mux.Handle("/env/"+ageWASMFile, h.wrap(h.require(RoleOperator, serveWASMFile(ageWASMFile, "application/wasm"))))
+		mux.Handle("/env/"+ageExecJSFile, h.wrap(h.require(RoleOperator, serveWASMFile(ageExecJSFile, "text/javascript"))))
+	}
+	if len(h.actions) > 0 {
+		mux.Handle("/env/actions/", h.wrap(h.require(RoleOperator, h.handleAction)))
+	}
 }
 
-// handleHealth returns health check information including environment detection.
-func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
-	health := map[string]interface{}{
-		"status":         "ok",
-		"timestamp":      time.Now().UTC().Format(time.RFC3339),
-		"environment":    env.DetectEnvironment(),
-		"uptime":         time.Since(h.startTime).String(),
-		"go_version":     runtime.Version(),
-		"num_goroutines": runtime.NumGoroutine(),
+// wrap applies the guard middleware, if any, to a route handler
+func (h *Handler) wrap(fn http.HandlerFunc) http.Handler {
+	if h.guard == nil {
+		return fn
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(health)
+	return h.guard(fn)
 }
 
 // handleEnv displays all environment variables from the registry.
 // Supports dual format: HTML (default) and JSON (?format=json).
+// With ?simulate=VAR1,VAR2 the page renders as if those variables were unset ("what-if" mode).
 func (h *Handler) handleEnv(w http.ResponseWriter, r *http.Request) {
 	vars := h.registry.All()
 	grouped := groupVariables(vars)
 
+	simulate := parseSimulate(r)
+	lookup := simulatedLookup(simulate)
+
 	// Build JSON response
 	response := map[string]interface{}{
 		"total_variables": len(vars),
 		"groups":          grouped,
 		"environment":     env.DetectEnvironment(),
-		"variables":       buildVariableStatus(vars),
+		"variables":       buildVariableStatus(vars, lookup),
+	}
+	if owners := h.owners(vars); len(owners) > 0 {
+		response["owners"] = owners
+	}
+
+	findings := h.registry.LintSecrets()
+	if len(findings) > 0 {
+		response["secret_findings"] = findings
+	}
+
+	role := h.role(r)
+	violations := h.checkPolicies(lookup)
+	if role < RoleOperator {
+		for i, v := range violations {
+			violations[i] = v.Redacted() // Viewers never see a value
+		}
+	}
+	if len(violations) > 0 {
+		response["policy_violations"] = violations
+	}
+
+	var sim *simulation
+	if len(simulate) > 0 {
+		sim = &simulation{
+			Unset:   simulate,
+			Unknown: unknownVariables(h.registry, simulate),
+			Impact:  simulateImpact(vars, lookup),
+		}
+		response["simulation"] = sim
 	}
 
 	// Check format preference
@@ -78,44 +162,111 @@ func (h *Handler) handleEnv(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Default: HTML output
-	h.renderEnvHTML(w, grouped, vars)
+	h.renderEnvHTML(w, role, grouped, vars, lookup, sim, findings, violations)
+}
+
+// handleEvents streams registry changes as Server-Sent Events ("registry" events),
+// so open /env pages reflect variables added or removed at runtime.
+func (h *Handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	changes := make(chan env.RegistryChange, 16)
+	unsubscribe := h.registry.Subscribe(func(c env.RegistryChange) {
+		select {
+		case changes <- c:
+		default: // Slow client - drop; the next event triggers a reload anyway
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprintf(w, ": version %d\n\n", h.registry.Version())
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case c := <-changes:
+			data, _ := json.Marshal(map[string]interface{}{
+				"kind":    c.Kind,
+				"name":    c.Var.Name,
+				"version": c.Version,
+			})
+			fmt.Fprintf(w, "event: registry\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
 }
 
-// renderEnvHTML renders the HTML view of environment variables.
-func (h *Handler) renderEnvHTML(w http.ResponseWriter, grouped map[string][]env.EnvVar, allVars []env.EnvVar) {
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+// renderFindingsBanner lists secrets hygiene findings (see env.LintSecrets)
+func renderFindingsBanner(findings []env.SecretFinding) string {
+	if len(findings) == 0 {
+		return ""
+	}
 
+	var b strings.Builder
+	b.WriteString(`
+        <article class="findings">
+            <p><strong>Secrets hygiene:</strong></p>
+            <ul>`)
+	for _, f := range findings {
+		fmt.Fprintf(&b, `
+                <li class="finding-%s"><code>%s</code> %s</li>`,
+			f.Severity, html.EscapeString(f.Name), html.EscapeString(f.Message))
+	}
+	b.WriteString(`
+            </ul>
+        </article>
+`)
+	return b.String()
+}
+
+// simulation holds the what-if results for ?simulate=...
+type simulation struct {
+	Unset   []string      `json:"unset"`
+	Unknown []string      `json:"unknown,omitempty"`
+	Impact  []GroupImpact `json:"impact"`
+}
+
+// renderEnvHTML renders the HTML view of environment variables. Viewers get
+// the status of each variable without values, copy buttons or actions.
+func (h *Handler) renderEnvHTML(w http.ResponseWriter, role Role, grouped map[string][]env.EnvVar, allVars []env.EnvVar, lookup lookupFunc, sim *simulation, findings []env.SecretFinding, violations []env.PolicyViolation) {
 	environment := env.DetectEnvironment()
-	configured := countConfigured(allVars)
-	missing := countMissingRequired(allVars)
-
-	html := fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>env | %s</title>
-    %s
-    %s
-</head>
-<body>
-    <main class="container">
-        <header>
-            <h2>env</h2>
-            <div class="stats">
+	configured := countConfigured(allVars, lookup)
+	missing := countMissingRequired(allVars, lookup)
+
+	operator := role >= RoleOperator
+
+	jsonURL := "/env?format=json"
+	if sim != nil {
+		jsonURL += "&simulate=" + url.QueryEscape(strings.Join(sim.Unset, ","))
+	}
+
+	header := fmt.Sprintf(`<div class="stats">
                 <span><strong>%d</strong> set</span>
                 <span><strong>%d</strong> missing</span>
                 <span>%s</span>
-            </div>
-        </header>
-
-        <input type="search" id="filter" placeholder="Filter variables..." autocomplete="off">
+            </div>`, configured, missing, environment)
 
-        <div class="export-bar">
+	exportButtons := ""
+	if operator {
+		exportButtons = `
             <button onclick="copyAsExport()">Copy export commands</button>
             <button onclick="copyAsDotenv()">Copy .env format</button>
-            <button onclick="copyAsJSON()">Copy JSON</button>
-            <a href="/env?format=json" role="button" class="outline">View JSON</a>
+            <button onclick="copyAsJSON()">Copy JSON</button>`
+	}
+
+	body := fmt.Sprintf(`%s%s%s
+        <input type="search" id="filter" placeholder="Filter variables..." autocomplete="off">
+
+        <div class="export-bar">%s
+            <a href="%s" role="button" class="outline">View JSON</a>
         </div>
 
         <table id="envTable">
@@ -128,24 +279,37 @@ func (h *Handler) renderEnvHTML(w http.ResponseWriter, grouped map[string][]env.
                 </tr>
             </thead>
             <tbody>`,
-		environment,
-		picoCSSLink,
-		customStyles,
-		configured,
-		missing,
-		environment,
+		renderSimulationBanner(sim),
+		renderFindingsBanner(findings),
+		renderPolicyBanner(violations),
+		exportButtons,
+		jsonURL,
 	)
 
 	// Render ALL variables in a single table (no grouping - simpler!)
+	var stored map[string]bool // nil hides the sealed-editing buttons
+	if operator {
+		stored = h.sealedNames()
+	}
+	simulated := make(map[string]bool)
+	if sim != nil {
+		for _, name := range sim.Unset {
+			simulated[name] = true
+		}
+	}
 	for _, v := range allVars {
-		html += renderVariableRow(v)
+		owner, _ := h.registry.OwnerOf(v.Name)
+		body += renderVariableRow(v, lookup, simulated[v.Name], operator, owner, renderSealedAction(v, stored))
 	}
 
-	html += `
+	body += `
             </tbody>
-        </table>
-    </main>
-    <script>
+        </table>`
+	if operator {
+		body += h.renderActions()
+	}
+
+	scripts := `<script>
 // Filter functionality
 document.getElementById('filter').addEventListener('input', (e) => {
     const filter = e.target.value.toLowerCase();
@@ -204,11 +368,22 @@ function copyAsJSON() {
     });
     copyToClipboard(JSON.stringify(obj, null, 2));
 }
-    </script>
-</body>
-</html>`
 
-	fmt.Fprint(w, html)
+// Reload when variables are added/removed at runtime (e.g., plugins registering late)
+if (window.EventSource) {
+    new EventSource('/env/events').addEventListener('registry', () => location.reload());
+}
+    </script>`
+	if operator {
+		scripts += h.renderSealedEditor()
+	}
+
+	h.renderPage(w, pageData{
+		Environment: environment,
+		Header:      template.HTML(header),
+		Body:        template.HTML(body),
+		Scripts:     template.HTML(scripts),
+	})
 }
 
 // Helper functions
@@ -226,11 +401,22 @@ func groupVariables(vars []env.EnvVar) map[string][]env.EnvVar {
 	return grouped
 }
 
+// owners maps each owned variable to its ownership (see env.Owners)
+func (h *Handler) owners(vars []env.EnvVar) map[string]env.Ownership {
+	owners := make(map[string]env.Ownership)
+	for _, v := range vars {
+		if o, ok := h.registry.OwnerOf(v.Name); ok {
+			owners[v.Name] = o
+		}
+	}
+	return owners
+}
+
 // buildVariableStatus creates the variable status map for JSON responses.
-func buildVariableStatus(vars []env.EnvVar) map[string]interface{} {
+func buildVariableStatus(vars []env.EnvVar, lookup lookupFunc) map[string]interface{} {
 	varStatus := make(map[string]interface{})
 	for _, v := range vars {
-		value := os.Getenv(v.Name)
+		value := lookup(v.Name)
 		status := map[string]interface{}{
 			"configured":  value != "",
 			"required":    v.Required,
@@ -254,9 +440,12 @@ func wantsJSON(r *http.Request) bool {
 	return format == "json" || strings.Contains(acceptHeader, "application/json")
 }
 
-// renderVariableRow renders a single variable as a table row - ultra-simple developer format
-func renderVariableRow(v env.EnvVar) string {
-	value := os.Getenv(v.Name)
+// renderVariableRow renders a single variable as a table row - ultra-simple developer format.
+// Non-secret values are shown when showValues is set (operators), else only
+// that the variable is set. owner is shown under the name when set; action is
+// the HTML of the last cell (e.g. the sealed-editing button).
+func renderVariableRow(v env.EnvVar, lookup lookupFunc, simulated, showValues bool, owner env.Ownership, action string) string {
+	value := lookup(v.Name)
 	configured := value != ""
 
 	// Row class for highlighting missing required vars
@@ -278,6 +467,8 @@ func renderVariableRow(v env.EnvVar) string {
 	if configured {
 		if v.Secret {
 			valueHTML = fmt.Sprintf(`<div class="value-cell"><span class="secret">••••••••</span></div>`)
+		} else if !showValues {
+			valueHTML = `<div class="value-cell"><span class="secret">set</span></div>`
 		} else {
 			// Escape value for HTML attribute
 			escapedValue := strings.ReplaceAll(value, `"`, `&quot;`)
@@ -298,11 +489,17 @@ func renderVariableRow(v env.EnvVar) string {
 	if v.Required {
 		tags = append(tags, `<span class="tag tag-required">REQ</span>`)
 	}
+	if simulated {
+		tags = append(tags, `<span class="tag tag-simulated">UNSET</span>`)
+	}
 	tagsHTML := strings.Join(tags, " ")
+	if owner.Owner != "" {
+		tagsHTML += fmt.Sprintf(`<br><span class="owner" title="Owner">%s</span>`, html.EscapeString(owner.String()))
+	}
 
 	// Escape value for data attribute
 	dataValue := ""
-	if configured && !v.Secret {
+	if configured && !v.Secret && showValues {
 		dataValue = strings.ReplaceAll(value, `"`, `&quot;`)
 	}
 
@@ -311,19 +508,19 @@ func renderVariableRow(v env.EnvVar) string {
                     <td><span class="status %s"></span></td>
                     <td><span class="var-name">%s</span> %s</td>
                     <td>%s</td>
-                    <td></td>
+                    <td>%s</td>
                 </tr>`,
 		rowClass, v.Name, dataValue,
 		statusClass,
 		v.Name, tagsHTML,
-		valueHTML)
+		valueHTML, action)
 }
 
 // countMissingRequired counts how many required variables are not configured
-func countMissingRequired(vars []env.EnvVar) int {
+func countMissingRequired(vars []env.EnvVar, lookup lookupFunc) int {
 	count := 0
 	for _, v := range vars {
-		if v.Required && os.Getenv(v.Name) == "" {
+		if v.Required && lookup(v.Name) == "" {
 			count++
 		}
 	}
@@ -331,10 +528,10 @@ func countMissingRequired(vars []env.EnvVar) int {
 }
 
 // countConfigured counts how many variables are configured.
-func countConfigured(vars []env.EnvVar) int {
+func countConfigured(vars []env.EnvVar, lookup lookupFunc) int {
 	count := 0
 	for _, v := range vars {
-		if os.Getenv(v.Name) != "" {
+		if lookup(v.Name) != "" {
 			count++
 		}
 	}
@@ -0,0 +1,43 @@
+package webui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// checkPolicies evaluates the registry's policies for the active environment
+// (APP_ENV) on the detected platform, with values from lookup so what-if
+// simulations show the policies they would break
+func (h *Handler) checkPolicies(lookup lookupFunc) []env.PolicyViolation {
+	return h.registry.CheckPolicies(env.PolicyContext{
+		Environment: h.registry.Status().Name,
+		Platform:    env.DetectEnvironment(),
+		Lookup:      lookup,
+	})
+}
+
+// renderPolicyBanner lists policy violations (see env.Policies)
+func renderPolicyBanner(violations []env.PolicyViolation) string {
+	if len(violations) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `
+        <article class="findings">
+            <p><strong>Policy violations (%s):</strong></p>
+            <ul>`, html.EscapeString(violations[0].Environment))
+	for _, v := range violations {
+		fmt.Fprintf(&b, `
+                <li class="finding-error">%s <small><code>%s</code></small></li>`,
+			html.EscapeString(v.Message), html.EscapeString(v.Rule))
+	}
+	b.WriteString(`
+            </ul>
+        </article>
+`)
+	return b.String()
+}
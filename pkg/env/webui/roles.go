@@ -0,0 +1,156 @@
+package webui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ================================================================
+// Permission Tiers
+// ================================================================
+// Without WithRoles every caller of the routes is an operator, as before
+// (protect them with WithGuard or a reverse proxy). With it, each request is
+// given a role by the caller's own identity system:
+//
+//   - RoleViewer sees which variables are set, missing or defaulted, the
+//     usage report and the banners, but never a value
+//   - RoleOperator also sees non-secret values (and the copy/export buttons),
+//     edits sealed secrets and triggers actions such as a reload or sync
+//   - RoleNone is refused with 403 Forbidden
+//
+// /health stays public, for load balancers and deploy checks.
+
+// Role is what a caller may see and do
+type Role int
+
+const (
+	RoleNone     Role = iota // No access
+	RoleViewer               // Configuration status only
+	RoleOperator             // Non-secret values, sealed editing and actions
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleOperator:
+		return "operator"
+	}
+	return "none"
+}
+
+// RoleFunc returns the role of a request's caller, e.g. from a session, a
+// verified JWT or the groups a reverse proxy passes in a header
+type RoleFunc func(r *http.Request) Role
+
+// WithRoles enables permission tiers, with roles from fn
+func (h *Handler) WithRoles(fn RoleFunc) *Handler {
+	h.roles = fn
+	return h
+}
+
+// role returns the caller's role (RoleOperator when tiers are off)
+func (h *Handler) role(r *http.Request) Role {
+	if h.roles == nil {
+		return RoleOperator
+	}
+	return h.roles(r)
+}
+
+// require wraps fn so it only runs for callers with at least role min
+func (h *Handler) require(min Role, fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.role(r) < min {
+			http.Error(w, fmt.Sprintf("Forbidden: %s role required", min), http.StatusForbidden)
+			return
+		}
+		fn(w, r)
+	}
+}
+
+// Action is an operation operators trigger from a button on /env, such as
+// reloading configuration or syncing secrets to a deploy target
+type Action struct {
+	Name  string                          // Route name: POST /env/actions/{Name}
+	Label string                          // Button text (default: Name)
+	Run   func(ctx context.Context) error // Runs the action; an error is shown to the operator
+}
+
+// WithAction adds an operator action
+func (h *Handler) WithAction(a Action) *Handler {
+	if a.Label == "" {
+		a.Label = a.Name
+	}
+	h.actions = append(h.actions, a)
+	return h
+}
+
+// handleAction runs an action. Like POST /env/sealed it only accepts JSON,
+// so a cross-site form cannot trigger it without a CORS preflight.
+func (h *Handler) handleAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/env/actions/")
+	for _, a := range h.actions {
+		if a.Name != name {
+			continue
+		}
+		if err := a.Run(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"action": a.Name, "success": true})
+		return
+	}
+	http.Error(w, fmt.Sprintf("Unknown action %q", name), http.StatusNotFound)
+}
+
+// renderActions renders the action buttons and their script ("" without
+// actions)
+func (h *Handler) renderActions() string {
+	if len(h.actions) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(`
+        <div class="export-bar actions">`)
+	for _, a := range h.actions {
+		name, _ := json.Marshal(a.Name)
+		fmt.Fprintf(&b, `
+            <button class="secondary" onclick="runAction(%s, this)">%s</button>`,
+			html.EscapeString(string(name)), html.EscapeString(a.Label))
+	}
+	b.WriteString(`
+        </div>
+        <script>
+async function runAction(name, button) {
+    button.setAttribute('aria-busy', 'true');
+    const res = await fetch('/env/actions/' + encodeURIComponent(name), {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: '{}',
+    });
+    button.removeAttribute('aria-busy');
+    if (res.ok) {
+        location.reload();
+    } else {
+        alert(name + ' failed: ' + await res.text());
+    }
+}
+        </script>`)
+	return b.String()
+}
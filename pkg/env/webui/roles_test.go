@@ -0,0 +1,131 @@
+package webui
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// rolesServer serves the webui with sealed editing and a "reload" action,
+// giving each request the role named in its X-Role header
+func rolesServer(t *testing.T) (*http.ServeMux, *int) {
+	t.Helper()
+	t.Setenv("WK_LOG_LEVEL", "verbose")
+	t.Setenv("WK_API_KEY", "sk-live-123")
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "WK_LOG_LEVEL", Group: "Logging"},
+		{Name: "WK_API_KEY", Secret: true, Group: "API"},
+	}, env.Policies(env.Policy{Variable: "WK_LOG_LEVEL", Op: env.PolicyOneOf, Value: "debug,info"}))
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloads := new(int)
+	h := NewHandler(registry).
+		WithSealedEditing(identity.Recipient(), env.NewSealedStore(t.TempDir())).
+		WithAction(Action{Name: "reload", Label: "Reload", Run: func(ctx context.Context) error {
+			*reloads++
+			return nil
+		}}).
+		WithRoles(func(r *http.Request) Role {
+			switch r.Header.Get("X-Role") {
+			case "operator":
+				return RoleOperator
+			case "viewer":
+				return RoleViewer
+			}
+			return RoleNone
+		})
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	return mux, reloads
+}
+
+func serveAs(mux *http.ServeMux, role, method, path, contentType string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	r.Header.Set("X-Role", role)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestRoles_Access(t *testing.T) {
+	mux, reloads := rolesServer(t)
+
+	for _, tt := range []struct {
+		role, method, path string
+		want               int
+	}{
+		{"", "GET", "/env", http.StatusForbidden},
+		{"", "GET", "/env/usage", http.StatusForbidden},
+		{"", "GET", "/health", http.StatusOK}, // Public
+		{"viewer", "GET", "/env", http.StatusOK},
+		{"viewer", "GET", "/env/recipient", http.StatusForbidden},
+		{"viewer", "POST", "/env/sealed", http.StatusForbidden},
+		{"viewer", "POST", "/env/actions/reload", http.StatusForbidden},
+		{"operator", "GET", "/env/recipient", http.StatusOK},
+		{"operator", "POST", "/env/actions/reload", http.StatusOK},
+	} {
+		if rec := serveAs(mux, tt.role, tt.method, tt.path, "application/json"); rec.Code != tt.want {
+			t.Errorf("%s %s as %q: status %d, want %d", tt.method, tt.path, tt.role, rec.Code, tt.want)
+		}
+	}
+	if *reloads != 1 {
+		t.Errorf("action ran %d times, want once (operator only)", *reloads)
+	}
+}
+
+func TestRoles_ViewerSeesNoValues(t *testing.T) {
+	mux, _ := rolesServer(t)
+
+	viewer := serveAs(mux, "viewer", "GET", "/env", "").Body.String()
+	for _, s := range []string{"verbose", "sk-live-123", "runAction("} {
+		if strings.Contains(viewer, s) {
+			t.Errorf("viewer page contains %q", s)
+		}
+	}
+	if !strings.Contains(viewer, "WK_LOG_LEVEL must be one of debug, info") {
+		t.Error("viewer page lacks the policy violation")
+	}
+	if json := serveAs(mux, "viewer", "GET", "/env?format=json", "").Body.String(); strings.Contains(json, "verbose") ||
+		!strings.Contains(json, `"policy_violations"`) {
+		t.Errorf("viewer JSON = %s, want violations without values", json)
+	}
+
+	operator := serveAs(mux, "operator", "GET", "/env", "").Body.String()
+	for _, s := range []string{"<code>verbose</code>", `(is &#34;verbose&#34;)`, "runAction("} {
+		if !strings.Contains(operator, s) {
+			t.Errorf("operator page lacks %s", s)
+		}
+	}
+	if strings.Contains(operator, "sk-live-123") {
+		t.Error("secret value shown to an operator")
+	}
+}
+
+func TestHandleAction_Rejects(t *testing.T) {
+	mux, reloads := rolesServer(t)
+
+	if rec := serveAs(mux, "operator", "GET", "/env/actions/reload", ""); rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "POST" {
+		t.Errorf("GET: status %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+	if rec := serveAs(mux, "operator", "POST", "/env/actions/reload", "application/x-www-form-urlencoded"); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("form POST: status %d, want 415", rec.Code)
+	}
+	if rec := serveAs(mux, "operator", "POST", "/env/actions/nope", "application/json"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown action: status %d, want 404", rec.Code)
+	}
+	if *reloads != 0 {
+		t.Errorf("action ran %d times, want never", *reloads)
+	}
+}
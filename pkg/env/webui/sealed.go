@@ -0,0 +1,215 @@
+package webui
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/fs"
+	"mime"
+	"net/http"
+	"strings"
+
+	"filippo.io/age"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// Files built by `make env-wasm` (see pkg/env/wasm): Age encryption for the
+// browser, served from the binary so no third-party script sees the values.
+// GOROOT's wasm_exec.js loads the module.
+const (
+	ageWASMFile   = "age.wasm"
+	ageExecJSFile = "wasm_exec.js"
+)
+
+//go:embed all:wasm
+var wasmFS embed.FS
+
+// ageWASMBuilt reports whether the Age module was built into the binary
+func ageWASMBuilt() bool {
+	_, err := fs.Stat(wasmFS, "wasm/"+ageWASMFile)
+	return err == nil
+}
+
+// serveWASMFile serves an embedded file of the Age build
+func serveWASMFile(name, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := wasmFS.ReadFile("wasm/" + name)
+		if err != nil {
+			http.Error(w, "Age WebAssembly not built (make env-wasm)", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.Write(data)
+	}
+}
+
+// maxSealedBody bounds POST /env/sealed bodies
+const maxSealedBody = 64 << 10
+
+// WithSealedEditing makes secret values editable from /env, encrypted end to
+// end: the page encrypts each value in the browser to recipient (published at
+// GET /env/recipient) and POSTs only the ciphertext, which the server writes
+// to store unread. The server needs no identity - values are decrypted where
+// the identity lives, with store.Load.
+//
+// The page encrypts with the Age WebAssembly build of `make env-wasm`,
+// embedded in the binary; without it the edit buttons only say so.
+//
+// Editing changes files, so protect the routes (WithGuard, or a reverse proxy
+// with authentication) wherever /env is reachable by others.
+func (h *Handler) WithSealedEditing(recipient *age.X25519Recipient, store *env.SealedStore) *Handler {
+	h.recipient = recipient
+	h.sealed = store
+	return h
+}
+
+// sealedSecretRequest is the body of POST /env/sealed
+type sealedSecretRequest struct {
+	Name       string `json:"name"`
+	Ciphertext string `json:"ciphertext"` // ASCII-armored Age file
+}
+
+// handleRecipient publishes the Age recipient values must be encrypted to
+func (h *Handler) handleRecipient(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"recipient": h.recipient.String()})
+}
+
+// handleSealed stores an encrypted secret value. Only JSON is accepted (so a
+// cross-site form cannot post without a CORS preflight), only for registered
+// secrets, and only Age ciphertext.
+func (h *Handler) handleSealed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req sealedSecretRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSealedBody)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	v := h.registry.ByName(req.Name)
+	if v == nil || !v.Secret {
+		http.Error(w, fmt.Sprintf("%s is not a registered secret", req.Name), http.StatusBadRequest)
+		return
+	}
+	if err := h.sealed.Put(v.Name, []byte(req.Ciphertext)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"name": v.Name, "sealed": true})
+}
+
+// sealedNames returns the names with a stored value (nil when editing is off)
+func (h *Handler) sealedNames() map[string]bool {
+	if h.sealed == nil {
+		return nil
+	}
+	names, _ := h.sealed.Names()
+	stored := make(map[string]bool, len(names))
+	for _, name := range names {
+		stored[name] = true
+	}
+	return stored
+}
+
+// renderSealedAction renders the edit button of a secret row (and whether a
+// sealed value is stored)
+func renderSealedAction(v env.EnvVar, stored map[string]bool) string {
+	if stored == nil || !v.Secret {
+		return ""
+	}
+	action := fmt.Sprintf(`<button class="copy-btn" onclick="editSealed('%s')" title="Set value (encrypted in the browser)">✏️</button>`, html.EscapeString(v.Name))
+	if stored[v.Name] {
+		action += ` <span class="tag tag-sealed">SEALED</span>`
+	}
+	return action
+}
+
+// renderSealedEditor renders the edit dialog and the script that encrypts
+// values in the browser ("" when editing is off)
+func (h *Handler) renderSealedEditor() string {
+	if h.sealed == nil {
+		return ""
+	}
+	if !ageWASMBuilt() {
+		return `
+    <script>
+window.editSealed = () => alert('Sealed editing needs the Age WebAssembly build: run make env-wasm and rebuild.');
+    </script>`
+	}
+	recipient, _ := json.Marshal(h.recipient.String())
+	return strings.Replace(`
+    <dialog id="sealedDialog">
+        <article>
+            <form id="sealedForm">
+                <p><strong id="sealedName"></strong></p>
+                <input type="password" id="sealedValue" autocomplete="off" placeholder="New value" required>
+                <small>Encrypted in this browser before it is sent; the server only stores the ciphertext.</small>
+                <footer>
+                    <button type="button" class="secondary" onclick="document.getElementById('sealedDialog').close()">Cancel</button>
+                    <button type="submit">Encrypt &amp; save</button>
+                </footer>
+            </form>
+        </article>
+    </dialog>
+    <script src="/env/`+ageExecJSFile+`"></script>
+    <script type="module">
+const recipient = RECIPIENT;
+const dialog = document.getElementById('sealedDialog');
+const input = document.getElementById('sealedValue');
+
+// The Age module is loaded on first use
+let seal;
+function loadSeal() {
+    seal ??= (async () => {
+        const go = new Go();
+        const { instance } = await WebAssembly.instantiateStreaming(fetch('/env/`+ageWASMFile+`'), go.importObject);
+        go.run(instance);
+        return window.wellknownSeal;
+    })();
+    return seal;
+}
+
+window.editSealed = (name) => {
+    document.getElementById('sealedName').textContent = name;
+    input.value = '';
+    dialog.showModal();
+};
+
+document.getElementById('sealedForm').addEventListener('submit', async (e) => {
+    e.preventDefault();
+    const name = document.getElementById('sealedName').textContent;
+    const { ciphertext, error } = (await loadSeal())(recipient, input.value);
+    input.value = '';
+    if (error) {
+        dialog.close();
+        alert('Failed to encrypt ' + name + ': ' + error);
+        return;
+    }
+
+    const res = await fetch('/env/sealed', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ name, ciphertext }),
+    });
+    dialog.close();
+    if (res.ok) {
+        location.reload();
+    } else {
+        alert('Failed to store ' + name + ': ' + await res.text());
+    }
+});
+    </script>`, "RECIPIENT", string(recipient), 1)
+}
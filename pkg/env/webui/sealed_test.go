@@ -0,0 +1,33 @@
+package webui
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSealedEditor_Assets(t *testing.T) {
+	mux, _ := rolesServer(t)
+
+	if rec := serveAs(mux, "viewer", "GET", "/env/"+ageWASMFile, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer GET /env/%s: status %d, want 403", ageWASMFile, rec.Code)
+	}
+	rec := serveAs(mux, "operator", "GET", "/env/"+ageWASMFile, "")
+	page := serveAs(mux, "operator", "GET", "/env", "").Body.String()
+	if strings.Contains(page, "age-encryption") {
+		t.Error("page loads Age from a CDN")
+	}
+
+	if !ageWASMBuilt() {
+		if rec.Code != http.StatusNotFound || !strings.Contains(page, "make env-wasm") {
+			t.Errorf("without the build: status %d, want 404 and a notice on the page", rec.Code)
+		}
+		return
+	}
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/wasm" {
+		t.Errorf("GET /env/%s: status %d, Content-Type %q", ageWASMFile, rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(page, `<script src="/env/`+ageExecJSFile+`">`) {
+		t.Error("page does not load the embedded module")
+	}
+}
@@ -0,0 +1,184 @@
+package webui
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// lookupFunc resolves the current value of an environment variable.
+// The default is os.Getenv; what-if mode wraps it to hide simulated variables.
+type lookupFunc func(name string) string
+
+// GroupImpact describes how a registry group is affected by simulating unset variables.
+type GroupImpact struct {
+	Group    string   `json:"group"`
+	Status   string   `json:"status"`             // "broken", "degraded" or "ok"
+	Missing  []string `json:"missing,omitempty"`  // Required vars that would be missing
+	Fallback []string `json:"fallback,omitempty"` // Optional vars that would fall back to default/empty
+}
+
+// parseSimulate reads the ?simulate=VAR1,VAR2 query parameter.
+// Names are upper-cased and de-duplicated; unknown names are kept so the caller can report them.
+func parseSimulate(r *http.Request) []string {
+	raw := r.URL.Query().Get("simulate")
+	if raw == "" {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.ToUpper(strings.TrimSpace(part))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// simulatedLookup returns a lookupFunc that reports the given variables as unset.
+func simulatedLookup(unset []string) lookupFunc {
+	if len(unset) == 0 {
+		return os.Getenv
+	}
+	hidden := make(map[string]bool, len(unset))
+	for _, name := range unset {
+		hidden[name] = true
+	}
+	return func(name string) string {
+		if hidden[name] {
+			return ""
+		}
+		return os.Getenv(name)
+	}
+}
+
+// simulateImpact compares the real environment with the simulated one and
+// reports, per group, which features would break (required vars missing)
+// or degrade (optional vars falling back to their defaults).
+// Groups that are unaffected by the simulation are omitted.
+func simulateImpact(vars []env.EnvVar, simulated lookupFunc) []GroupImpact {
+	byGroup := make(map[string]*GroupImpact)
+	for _, v := range vars {
+		// Only variables that change state because of the simulation matter
+		if os.Getenv(v.Name) == "" || simulated(v.Name) != "" {
+			continue
+		}
+
+		group := v.Group
+		if group == "" {
+			group = "General"
+		}
+		impact, ok := byGroup[group]
+		if !ok {
+			impact = &GroupImpact{Group: group, Status: "ok"}
+			byGroup[group] = impact
+		}
+
+		if v.Required {
+			impact.Missing = append(impact.Missing, v.Name)
+			impact.Status = "broken"
+		} else {
+			impact.Fallback = append(impact.Fallback, v.Name)
+			if impact.Status != "broken" {
+				impact.Status = "degraded"
+			}
+		}
+	}
+
+	impacts := make([]GroupImpact, 0, len(byGroup))
+	for _, impact := range byGroup {
+		impacts = append(impacts, *impact)
+	}
+	sort.Slice(impacts, func(i, j int) bool {
+		if impacts[i].Status != impacts[j].Status {
+			return impacts[i].Status == "broken"
+		}
+		return impacts[i].Group < impacts[j].Group
+	})
+	return impacts
+}
+
+// unknownVariables returns simulated names that are not in the registry.
+func unknownVariables(registry *env.Registry, names []string) []string {
+	var unknown []string
+	for _, name := range names {
+		if registry.ByName(name) == nil {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// renderSimulationBanner renders the what-if summary shown above the table.
+// Returns an empty string when no simulation is active.
+func renderSimulationBanner(sim *simulation) string {
+	if sim == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(`
+        <article class="simulation">
+            <p><strong>What-if:</strong> simulating unset `)
+	for i, name := range sim.Unset {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "<code>%s</code>", html.EscapeString(name))
+	}
+	b.WriteString(` &middot; <a href="/env">clear</a></p>`)
+
+	if len(sim.Impact) == 0 {
+		b.WriteString(`
+            <p class="impact-ok">No configured variables affected.</p>`)
+	} else {
+		b.WriteString(`
+            <ul>`)
+		for _, impact := range sim.Impact {
+			switch impact.Status {
+			case "broken":
+				fmt.Fprintf(&b, `
+                <li class="impact-broken"><strong>%s</strong> breaks: missing %s`,
+					html.EscapeString(impact.Group), escapeNames(impact.Missing))
+				if len(impact.Fallback) > 0 {
+					fmt.Fprintf(&b, "; falls back: %s", escapeNames(impact.Fallback))
+				}
+				b.WriteString("</li>")
+			default:
+				fmt.Fprintf(&b, `
+                <li class="impact-degraded"><strong>%s</strong> degraded: falls back %s</li>`,
+					html.EscapeString(impact.Group), escapeNames(impact.Fallback))
+			}
+		}
+		b.WriteString(`
+            </ul>`)
+	}
+
+	if len(sim.Unknown) > 0 {
+		fmt.Fprintf(&b, `
+            <p class="empty">Not in registry: %s</p>`, escapeNames(sim.Unknown))
+	}
+
+	b.WriteString(`
+        </article>
+`)
+	return b.String()
+}
+
+// escapeNames HTML-escapes variable names and joins them with commas
+func escapeNames(names []string) string {
+	escaped := make([]string, len(names))
+	for i, name := range names {
+		escaped[i] = html.EscapeString(name)
+	}
+	return strings.Join(escaped, ", ")
+}
@@ -0,0 +1,115 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// simulateServer serves /env for a registry whose variables are all set,
+// except CACHE_URL
+func simulateServer(t *testing.T) *http.ServeMux {
+	t.Helper()
+	for _, name := range []string{"WK_DB_URL", "WK_DB_POOL", "WK_LOG_LEVEL", "WK_SENTRY_DSN"} {
+		t.Setenv(name, "set")
+	}
+	t.Setenv("WK_CACHE_URL", "")
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "WK_DB_URL", Required: true, Group: "Database"},
+		{Name: "WK_DB_POOL", Group: "Database"},
+		{Name: "WK_LOG_LEVEL", Group: "Logging"},
+		{Name: "WK_SENTRY_DSN", Group: "Logging"},
+		{Name: "WK_CACHE_URL", Group: "Cache"},
+	})
+	mux := http.NewServeMux()
+	NewHandler(registry).RegisterRoutes(mux)
+	return mux
+}
+
+func TestSimulate_JSON(t *testing.T) {
+	mux := simulateServer(t)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/env?format=json&simulate=wk_db_pool,WK_DB_URL,wk_log_level,WK_CACHE_URL,NOPE,nope", nil))
+	var response struct {
+		Simulation *simulation            `json:"simulation"`
+		Variables  map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("decoding /env JSON (status %d): %v", rec.Code, err)
+	}
+
+	sim := response.Simulation
+	if sim == nil {
+		t.Fatal("no simulation key")
+	}
+	if want := []string{"WK_DB_POOL", "WK_DB_URL", "WK_LOG_LEVEL", "WK_CACHE_URL", "NOPE"}; !reflect.DeepEqual(sim.Unset, want) {
+		t.Errorf("unset = %v, want %v (upper-cased, de-duplicated)", sim.Unset, want)
+	}
+	if !reflect.DeepEqual(sim.Unknown, []string{"NOPE"}) {
+		t.Errorf("unknown = %v", sim.Unknown)
+	}
+	// Broken first; Cache is unaffected (already unset)
+	want := []GroupImpact{
+		{Group: "Database", Status: "broken", Missing: []string{"WK_DB_URL"}, Fallback: []string{"WK_DB_POOL"}},
+		{Group: "Logging", Status: "degraded", Fallback: []string{"WK_LOG_LEVEL"}},
+	}
+	if !reflect.DeepEqual(sim.Impact, want) {
+		t.Errorf("impact =\n%+v\nwant\n%+v", sim.Impact, want)
+	}
+	if response.Variables["wk_db_url_configured"] != false || response.Variables["wk_sentry_dsn_configured"] != true {
+		t.Errorf("variables = %v, want the simulated view", response.Variables)
+	}
+
+	// Without simulate there is no key
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/env?format=json", nil))
+	if strings.Contains(rec.Body.String(), `"simulation"`) {
+		t.Error("simulation key present without ?simulate=")
+	}
+}
+
+func TestSimulate_HTML(t *testing.T) {
+	mux := simulateServer(t)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/env?simulate=WK_DB_URL,WK_LOG_LEVEL,%3Cimg+src%3Dx%3E", nil))
+	body := rec.Body.String()
+	for _, s := range []string{
+		`<article class="simulation">`,
+		`<li class="impact-broken"><strong>Database</strong> breaks: missing WK_DB_URL</li>`,
+		`<li class="impact-degraded"><strong>Logging</strong> degraded: falls back WK_LOG_LEVEL</li>`,
+		`Not in registry: &lt;IMG SRC=X&gt;`,
+		`href="/env?format=json&simulate=WK_DB_URL%2CWK_LOG_LEVEL%2C%3CIMG+SRC%3DX%3E"`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("page lacks %s", s)
+		}
+	}
+	if strings.Contains(body, "<IMG") {
+		t.Error("unknown name rendered unescaped")
+	}
+}
+
+func TestRenderSimulationBanner_Escapes(t *testing.T) {
+	banner := renderSimulationBanner(&simulation{
+		Unset: []string{"A<B"},
+		Impact: []GroupImpact{
+			{Group: "<G>", Status: "broken", Missing: []string{"A<B"}, Fallback: []string{"C&D"}},
+			{Group: "H", Status: "degraded", Fallback: []string{`E"F`}},
+		},
+	})
+	for _, s := range []string{"missing A&lt;B", "falls back: C&amp;D", "falls back E&#34;F", "<strong>&lt;G&gt;</strong>"} {
+		if !strings.Contains(banner, s) {
+			t.Errorf("banner lacks %s:\n%s", s, banner)
+		}
+	}
+	if renderSimulationBanner(nil) != "" {
+		t.Error("banner rendered without a simulation")
+	}
+}
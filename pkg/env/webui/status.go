@@ -0,0 +1,56 @@
+package webui
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// Status summarises what /env and /health show, for dashboards that embed the
+// environment view in a wider status page instead of linking to it.
+type Status struct {
+	Environment      string   `json:"environment"`
+	Version          string   `json:"version,omitempty"`
+	Uptime           string   `json:"uptime"`
+	GoVersion        string   `json:"go_version"`
+	Goroutines       int      `json:"num_goroutines"`
+	Total            int      `json:"total_variables"`
+	Configured       int      `json:"configured"`
+	Secrets          int      `json:"secrets"`
+	MissingRequired  []string `json:"missing_required,omitempty"`
+	SecretFindings   int      `json:"secret_findings"`
+	PolicyViolations []string `json:"policy_violations,omitempty"`
+}
+
+// Healthy reports whether every required variable is set, no secret lint
+// findings are open and no policy is violated
+func (s Status) Healthy() bool {
+	return len(s.MissingRequired) == 0 && s.SecretFindings == 0 && len(s.PolicyViolations) == 0
+}
+
+// Status returns the current environment status of the handler's registry.
+func (h *Handler) Status() Status {
+	vars := h.registry.All()
+	lookup := simulatedLookup(nil)
+	status := Status{
+		Environment:    env.DetectEnvironment(),
+		Version:        h.health.Version(),
+		Uptime:         h.health.Uptime().Round(time.Second).String(),
+		GoVersion:      runtime.Version(),
+		Goroutines:     runtime.NumGoroutine(),
+		Total:          len(vars),
+		Configured:     countConfigured(vars, lookup),
+		Secrets:        countSecrets(vars),
+		SecretFindings: len(h.registry.LintSecrets()),
+	}
+	for _, v := range vars {
+		if v.Required && lookup(v.Name) == "" {
+			status.MissingRequired = append(status.MissingRequired, v.Name)
+		}
+	}
+	for _, v := range h.checkPolicies(lookup) {
+		status.PolicyViolations = append(status.PolicyViolations, v.Message)
+	}
+	return status
+}
@@ -1,95 +1,113 @@
 package webui
 
-// Pico CSS CDN link - classless CSS framework with dark mode support
-const picoCSSLink = `<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/@picocss/pico@2/css/pico.min.css">`
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+)
 
-// Minimal custom styles optimized for developer workflow
-const customStyles = `
-<style>
-/* Compact header */
-header { padding: 1rem 0; border-bottom: 1px solid var(--pico-muted-border-color); }
-header h2 { margin: 0; }
-.stats { display: flex; gap: 1rem; font-size: 0.9rem; color: var(--pico-muted-color); }
-.stats span { display: flex; align-items: center; gap: 0.25rem; }
+// ================================================================
+// Theme and Branding
+// ================================================================
+// Every HTML view renders through the "page" template (templates/page.html),
+// which places the view's header, body and scripts between the named
+// templates "styles", "brand" and "footer". Options fills those from a title,
+// logo, primary color and footer, and Options.Templates replaces any of them
+// (or "page" itself) with the caller's own, so a product embedding the env UI
+// can white-label it (example in doc.go).
 
-/* Filter box */
-#filter { margin: 1rem 0; }
+//go:embed templates/*.html
+var defaultTemplates embed.FS
 
-/* Compact table */
-table { font-size: 0.9rem; }
-table td, table th { padding: 0.5rem; vertical-align: top; }
-table th { font-size: 0.8rem; text-transform: uppercase; letter-spacing: 0.5px; }
+// Options brands the HTML views (see WithOptions)
+type Options struct {
+	Title        string // Name shown in the header and <title> (default: "env")
+	Logo         string // URL of an image shown before the title
+	PrimaryColor string // CSS color of links and buttons (default: Pico's blue)
+	FooterHTML   string // Trusted HTML shown below every page (not escaped)
 
-/* Variable name - bold monospace */
-.var-name {
-    font-family: monospace;
-    font-weight: 600;
-    font-size: 0.95rem;
+	// Templates holds *.html files parsed after the defaults; a {{define}}
+	// of "page", "styles", "brand" or "footer" replaces the default
+	Templates fs.FS
 }
 
-/* Value display with copy button */
-.value-cell {
-    display: flex;
-    align-items: center;
-    gap: 0.5rem;
-    font-family: monospace;
-    font-size: 0.9rem;
+// pageData is what the page templates render
+type pageData struct {
+	Title        string
+	Subtitle     string // View name after the title, e.g. "usage"
+	Environment  string // Runtime environment shown in <title>, if any
+	Logo         string
+	PrimaryColor string
+	FooterHTML   template.HTML
+	Header       template.HTML // View HTML below the brand
+	Body         template.HTML
+	Scripts      template.HTML
 }
-.copy-btn {
-    opacity: 0.3;
-    cursor: pointer;
-    border: none;
-    background: none;
-    padding: 0.25rem;
-    font-size: 1rem;
-}
-.copy-btn:hover { opacity: 1; }
-tr:hover .copy-btn { opacity: 0.6; }
 
-/* Status badges - minimal */
-.status {
-    display: inline-block;
-    width: 8px;
-    height: 8px;
-    border-radius: 50%;
-    margin-right: 0.5rem;
+// pageTemplates are the default templates, parsed once
+var pageTemplates = template.Must(template.ParseFS(defaultTemplates, "templates/*.html"))
+
+// WithOptions sets the title, logo, colors, footer and template overrides of
+// the HTML views. When a template in opts.Templates does not parse, the views
+// keep the default templates and every page reports the error (see
+// TemplateError).
+func (h *Handler) WithOptions(opts Options) *Handler {
+	if opts.Title == "" {
+		opts.Title = defaultTitle
+	}
+	h.options = opts
+	h.templates, h.templateErr = parseTemplates(opts.Templates)
+	if h.templateErr != nil {
+		h.templates = pageTemplates
+	}
+	return h
 }
-.status-set { background: #28a745; }
-.status-missing { background: #ffc107; }
-.status-empty { background: #6c757d; opacity: 0.3; }
 
-/* Tags - ultra minimal */
-.tag {
-    font-size: 0.7rem;
-    padding: 0.1rem 0.4rem;
-    border-radius: 2px;
-    font-weight: 500;
-    margin-right: 0.25rem;
-    opacity: 0.7;
+// TemplateError returns the error parsing Options.Templates, if any, so
+// callers can fail at startup rather than on the first page
+func (h *Handler) TemplateError() error {
+	return h.templateErr
 }
-.tag-secret { background: #dc3545; color: white; }
-.tag-required { background: #ffc107; color: #000; }
 
-/* Secret values */
-.secret { color: #dc3545; font-weight: 600; }
-.empty { color: var(--pico-muted-color); font-style: italic; }
+// defaultTitle is shown when Options.Title is empty
+const defaultTitle = "env"
 
-/* Export buttons */
-.export-bar {
-    display: flex;
-    gap: 0.5rem;
-    margin: 1rem 0;
-    flex-wrap: wrap;
-}
-.export-bar button {
-    font-size: 0.8rem;
-    padding: 0.4rem 0.8rem;
+// parseTemplates parses the overrides, if any, over a copy of the defaults
+func parseTemplates(overrides fs.FS) (*template.Template, error) {
+	if overrides == nil {
+		return pageTemplates, nil
+	}
+	matches, err := fs.Glob(overrides, "*.html")
+	if err != nil || len(matches) == 0 {
+		return pageTemplates, err
+	}
+	// Parsed afresh: pageTemplates cannot be cloned once a page has rendered
+	tmpl := template.Must(template.ParseFS(defaultTemplates, "templates/*.html"))
+	if tmpl, err = tmpl.ParseFS(overrides, "*.html"); err != nil {
+		return nil, fmt.Errorf("webui: parse template overrides: %w", err)
+	}
+	return tmpl, nil
 }
 
-/* Highlight missing required vars */
-tr.missing-required { background: rgba(255, 193, 7, 0.1); }
+// renderPage writes a view through the "page" template
+func (h *Handler) renderPage(w http.ResponseWriter, page pageData) {
+	page.Title = h.options.Title
+	page.Logo = h.options.Logo
+	page.PrimaryColor = h.options.PrimaryColor
+	page.FooterHTML = template.HTML(h.options.FooterHTML)
 
-/* Hidden rows (for filter) */
-tr.hidden { display: none; }
-</style>
-`
+	if h.templateErr != nil {
+		http.Error(w, h.templateErr.Error(), http.StatusInternalServerError)
+		return
+	}
+	var buf bytes.Buffer
+	if err := h.templates.ExecuteTemplate(&buf, "page", page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
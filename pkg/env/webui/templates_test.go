@@ -0,0 +1,76 @@
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+func brandedPage(t *testing.T, opts Options) (*Handler, *httptest.ResponseRecorder) {
+	t.Helper()
+	h := NewHandler(env.NewRegistry([]env.EnvVar{{Name: "WK_BRAND_VAR"}})).WithOptions(opts)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/env", nil))
+	return h, rec
+}
+
+func TestWithOptions_Branding(t *testing.T) {
+	_, rec := brandedPage(t, Options{
+		Title:        "Acme Config",
+		Logo:         "/static/acme.svg",
+		PrimaryColor: "#d9480f",
+		FooterHTML:   `&copy; Acme Inc.`,
+	})
+	body := rec.Body.String()
+	for _, s := range []string{"<title>Acme Config", `<img src="/static/acme.svg"`, "#d9480f", "<footer class=\"container\">&copy; Acme Inc.</footer>"} {
+		if !strings.Contains(body, s) {
+			t.Errorf("page lacks %s", s)
+		}
+	}
+}
+
+func TestWithOptions_TemplateOverride(t *testing.T) {
+	h, rec := brandedPage(t, Options{Templates: fstest.MapFS{
+		"brand.html": {Data: []byte(`{{define "brand"}}<h1 class="acme">{{.Title}} by Acme</h1>{{end}}`)},
+		"notes.txt":  {Data: []byte("not a template")},
+	}})
+	if err := h.TemplateError(); err != nil {
+		t.Fatal(err)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `<h1 class="acme">env by Acme</h1>`) || strings.Contains(body, `class="brand"`) {
+		t.Errorf("brand not overridden:\n%s", body)
+	}
+	if !strings.Contains(body, "WK_BRAND_VAR") {
+		t.Error("overridden page lacks the view body")
+	}
+
+	// The defaults are not changed for other handlers
+	if _, rec := brandedPage(t, Options{}); !strings.Contains(rec.Body.String(), `<h2 class="brand">env`) {
+		t.Error("default brand changed by another handler's override")
+	}
+}
+
+func TestWithOptions_ParseError(t *testing.T) {
+	h, rec := brandedPage(t, Options{Title: "Acme", Templates: fstest.MapFS{
+		"brand.html": {Data: []byte(`{{define "brand"}}{{.Title}`)},
+	}})
+	err := h.TemplateError()
+	if err == nil || !strings.Contains(err.Error(), "parse template overrides") {
+		t.Fatalf("TemplateError() = %v, want the parse error", err)
+	}
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "parse template overrides") {
+		t.Errorf("page: %d %s, want the parse error", rec.Code, rec.Body)
+	}
+
+	// Options that parse clear the error
+	if h.WithOptions(Options{}).TemplateError() != nil {
+		t.Error("error kept after valid options")
+	}
+}
@@ -0,0 +1,58 @@
+package webui
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// handleUsage reports which registry variables were read at runtime (see env.EnableUsageTracking).
+// Supports dual format: HTML (default) and JSON (?format=json).
+func (h *Handler) handleUsage(w http.ResponseWriter, r *http.Request) {
+	report := h.registry.UsageReport()
+
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	var notice string
+	if !report.Enabled {
+		notice = `<article class="simulation"><p>Usage tracking is disabled. Call <code>env.EnableUsageTracking()</code> at startup to record reads.</p></article>`
+	} else {
+		notice = fmt.Sprintf(`<p>Tracking since %s (%s ago)</p>`,
+			report.Since.Format(time.RFC3339), time.Since(report.Since).Round(time.Second))
+	}
+
+	var used strings.Builder
+	for _, u := range report.Used {
+		used.WriteString(fmt.Sprintf("<tr><td><code>%s</code></td><td>%d</td><td>%s</td></tr>\n",
+			html.EscapeString(u.Name), u.Reads, u.LastRead.Format(time.RFC3339)))
+	}
+
+	var unused strings.Builder
+	for _, name := range report.Unused {
+		unused.WriteString(fmt.Sprintf("<li><code>%s</code></li>\n", html.EscapeString(name)))
+	}
+	if len(report.Unused) == 0 {
+		unused.WriteString(`<li class="empty">Every variable has been read</li>`)
+	}
+
+	h.renderPage(w, pageData{
+		Subtitle: "usage",
+		Header: template.HTML(notice + `
+            <a href="/env">← back to /env</a> · <a href="/env/usage?format=json">JSON</a>`),
+		Body: template.HTML(fmt.Sprintf(`        <h3>Never read (%d)</h3>
+        <ul>%s</ul>
+        <h3>Read (%d)</h3>
+        <table>
+            <thead><tr><th>Variable</th><th>Reads</th><th>Last read</th></tr></thead>
+            <tbody>%s</tbody>
+        </table>`, len(report.Unused), unused.String(), len(report.Used), used.String())),
+	})
+}
@@ -0,0 +1,33 @@
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+func TestUsage_DisabledNotice(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(env.NewRegistry([]env.EnvVar{{Name: "WK_USAGE_VAR"}})).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/env/usage", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "Usage tracking is disabled") {
+		t.Fatalf("no disabled notice:\n%s", body)
+	}
+
+	// Every class on the notice is styled
+	styles, err := defaultTemplates.ReadFile("templates/styles.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notice := regexp.MustCompile(`<article class="([^"]+)"><p>Usage tracking`).FindStringSubmatch(body)
+	if notice == nil || !strings.Contains(string(styles), "."+notice[1]+" {") {
+		t.Errorf("notice class %v has no rule in styles.html", notice)
+	}
+}
@@ -0,0 +1,342 @@
+package env
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// ================================================================
+// Secrets Wizard
+// ================================================================
+// Walks through every Secret variable, reads each value with hidden input,
+// validates it, and writes the result straight to the encrypted .age file.
+// The plaintext secrets file is never written to disk.
+
+// SecretsWizardOptions configures RunSecretsWizard.
+type SecretsWizardOptions struct {
+	Registry    *Registry    // Registry defining the secrets (required)
+	Environment *Environment // Secrets file to write (default: SecretsLocal; only its .age version is written)
+	KeyPath     string       // Age key used to encrypt (and read existing values) (default: DefaultAgeKeyPath)
+	AppName     string       // Application name for the file header (default: "Application")
+	Out         io.Writer    // Where prompts are written (default: os.Stdout)
+
+	// ReadSecret reads one value after the prompt has been written
+	// (default: TerminalSecretReader(os.Stdin, Out), which hides input on a terminal)
+	ReadSecret func() (string, error)
+}
+
+// SecretsWizardResult reports what the wizard did.
+type SecretsWizardResult struct {
+	Path  string   // Encrypted file written
+	Set   []string // Variables given a new value
+	Kept  []string // Variables whose existing value was kept
+	Empty []string // Variables left without a value
+}
+
+// RunSecretsWizard prompts for each registry secret and writes the encrypted secrets file.
+//
+// Existing values in the .age file are decrypted first; pressing Enter keeps them.
+// Values failing EnvVar.ValidateValue are rejected and prompted for again.
+//
+// Example:
+//
+//	result, err := env.RunSecretsWizard(env.SecretsWizardOptions{
+//	    Registry:    registry,
+//	    Environment: env.SecretsProduction,
+//	})
+//	fmt.Printf("Wrote %s (%d set)\n", result.Path, len(result.Set))
+func RunSecretsWizard(opts SecretsWizardOptions) (*SecretsWizardResult, error) {
+	if opts.Registry == nil {
+		return nil, fmt.Errorf("registry is required")
+	}
+	if opts.Environment == nil {
+		opts.Environment = SecretsLocal
+	}
+	if opts.KeyPath == "" {
+		opts.KeyPath = DefaultAgeKeyPath
+	}
+	if opts.AppName == "" {
+		opts.AppName = "Application"
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.ReadSecret == nil {
+		opts.ReadSecret = TerminalSecretReader(os.Stdin, opts.Out)
+	}
+
+	recipient, existing, err := openSecretsFile(opts.Environment, opts.KeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := opts.Registry.GetSecrets()
+	result := &SecretsWizardResult{Path: opts.Environment.FullEncryptedPath()}
+	values := make(map[string]string, len(secrets))
+
+	fmt.Fprintf(opts.Out, "Entering %d secrets for %s (input is hidden; Enter keeps the current value)\n\n",
+		len(secrets), opts.Environment.FileName)
+
+	for _, v := range secrets {
+		value, err := promptSecret(opts, v, existing[v.Name] != "")
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case value != "":
+			values[v.Name] = value
+			result.Set = append(result.Set, v.Name)
+		case existing[v.Name] != "":
+			values[v.Name] = existing[v.Name]
+			result.Kept = append(result.Kept, v.Name)
+		default:
+			result.Empty = append(result.Empty, v.Name)
+		}
+	}
+
+	if err := writeSecretsFile(opts.Environment, secrets, values, recipient, opts.AppName); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ================================================================
+// Setting Secrets Programmatically
+// ================================================================
+// Tools that collect secrets themselves (e.g. the GCP setup wizard) store
+// them with SetSecrets instead of a plaintext side file. Only the given
+// values change; the rest of the encrypted file is kept as it is.
+
+// SetSecretsOptions configures SetSecrets.
+type SetSecretsOptions struct {
+	Registry    *Registry         // Registry the values are validated against; also the template of a new file (required)
+	Values      map[string]string // Values to set ("" clears one)
+	Environment *Environment      // Secrets file to update (default: SecretsLocal; only its .age version is written)
+	KeyPath     string            // Age key used to decrypt and re-encrypt (default: DefaultAgeKeyPath)
+	AppName     string            // Application name for the header of a new file (default: "Application")
+}
+
+// SetSecrets sets values in the encrypted secrets file, keeping its other
+// values, comments and layout, and returns the path written. Every name must
+// be in the registry and non-empty values must pass EnvVar.ValidateValue;
+// nothing is written otherwise.
+//
+// Example:
+//
+//	path, err := env.SetSecrets(env.SetSecretsOptions{
+//	    Registry: registry,
+//	    Values:   map[string]string{"GOOGLE_CLIENT_ID": id, "GOOGLE_CLIENT_SECRET": secret},
+//	})
+func SetSecrets(opts SetSecretsOptions) (string, error) {
+	if opts.Registry == nil {
+		return "", fmt.Errorf("registry is required")
+	}
+	if opts.Environment == nil {
+		opts.Environment = SecretsLocal
+	}
+	if opts.KeyPath == "" {
+		opts.KeyPath = DefaultAgeKeyPath
+	}
+	if opts.AppName == "" {
+		opts.AppName = "Application"
+	}
+
+	for name, value := range opts.Values {
+		v := opts.Registry.ByName(name)
+		if v == nil {
+			return "", fmt.Errorf("%s is not in the registry", name)
+		}
+		if value == "" {
+			continue
+		}
+		if err := v.ValidateValue(value); err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	recipient, content, err := readSecretsFile(opts.Environment, opts.KeyPath)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		content = opts.Environment.Generate(opts.Registry, opts.AppName)
+	}
+	content, err = setValues(content, opts.Values)
+	if err != nil {
+		return "", err
+	}
+	if err := writeEncrypted(opts.Environment, content, recipient); err != nil {
+		return "", err
+	}
+	return opts.Environment.FullEncryptedPath(), nil
+}
+
+// ReadSecrets returns the values in e's encrypted secrets file, decrypted
+// with the age key at keyPath (default: DefaultAgeKeyPath). A missing file
+// has no values.
+func ReadSecrets(e *Environment, keyPath string) (map[string]string, error) {
+	if keyPath == "" {
+		keyPath = DefaultAgeKeyPath
+	}
+	data, err := os.ReadFile(e.FullEncryptedPath())
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	identities, err := loadIdentityFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decryptWithIdentities(data, identities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", e.FullEncryptedPath(), err)
+	}
+	return ParseSecretsFile(plaintext), nil
+}
+
+// openSecretsFile returns the recipient of the age key at keyPath and the
+// values already in e's .age file (empty when it does not exist yet)
+func openSecretsFile(e *Environment, keyPath string) (*age.X25519Recipient, map[string]string, error) {
+	recipient, content, err := readSecretsFile(e, keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return recipient, ParseSecretsFile([]byte(content)), nil
+}
+
+// readSecretsFile is openSecretsFile returning the decrypted file as is
+// ("" when it does not exist yet)
+func readSecretsFile(e *Environment, keyPath string) (*age.X25519Recipient, string, error) {
+	identities, err := loadIdentityFile(keyPath)
+	if err != nil {
+		return nil, "", err
+	}
+	x25519, ok := identities[0].(*age.X25519Identity)
+	if !ok {
+		return nil, "", fmt.Errorf("key at %s is not an X25519 age identity", keyPath)
+	}
+
+	encryptedPath := e.FullEncryptedPath()
+	data, err := os.ReadFile(encryptedPath)
+	if os.IsNotExist(err) {
+		return x25519.Recipient(), "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	plaintext, err := decryptWithIdentities(data, identities)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decrypt %s: %w", encryptedPath, err)
+	}
+	return x25519.Recipient(), string(plaintext), nil
+}
+
+// writeSecretsFile renders e's template for vars with values and writes it
+// encrypted to recipient. Rendering and encryption happen in memory, so the
+// plaintext never touches disk.
+func writeSecretsFile(e *Environment, vars []EnvVar, values map[string]string, recipient age.Recipient, appName string) error {
+	template := e.Generate(NewRegistry(vars), appName)
+	content, err := MergeIntoTemplate(template, values)
+	if err != nil {
+		return err
+	}
+	return writeEncrypted(e, content, recipient)
+}
+
+// writeEncrypted encrypts plaintext to recipient and writes it to e's .age file
+func writeEncrypted(e *Environment, plaintext string, recipient age.Recipient) error {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if _, err := io.WriteString(w, plaintext); err != nil {
+		return fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to encrypt: %w", err)
+	}
+	encryptedPath := e.FullEncryptedPath()
+	if err := os.WriteFile(encryptedPath, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", encryptedPath, err)
+	}
+	return nil
+}
+
+// promptSecret prompts for one variable until the value is empty or valid
+func promptSecret(opts SecretsWizardOptions, v EnvVar, hasExisting bool) (string, error) {
+	if v.Description != "" {
+		fmt.Fprintf(opts.Out, "# %s\n", v.Description)
+	}
+	label := v.Name
+	if v.Required {
+		label += " (required)"
+	}
+	if hasExisting {
+		label += " [set]"
+	}
+
+	for {
+		fmt.Fprintf(opts.Out, "%s: ", label)
+		value, err := opts.ReadSecret()
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", v.Name, err)
+		}
+		value = strings.TrimSpace(value)
+		if err := v.ValidateValue(value); err != nil {
+			fmt.Fprintf(opts.Out, "  invalid: %v - try again\n", err)
+			continue
+		}
+		if value == "" && v.Required && !hasExisting {
+			fmt.Fprintf(opts.Out, "  warning: %s is required, leaving it empty\n", v.Name)
+		}
+		fmt.Fprintln(opts.Out)
+		return value, nil
+	}
+}
+
+// TerminalSecretReader returns a ReadSecret func reading lines from in.
+// When in is a terminal, echo is turned off with stty while reading; where
+// that isn't possible (no stty, e.g. Windows) a warning is written to out once
+// and input stays visible. Non-terminal input (pipes) is read as-is.
+func TerminalSecretReader(in *os.File, out io.Writer) func() (string, error) {
+	reader := bufio.NewReader(in)
+	warned := false
+
+	return func() (string, error) {
+		if info, err := in.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			if err := stty(in, "-echo"); err == nil {
+				defer func() {
+					stty(in, "echo")
+					fmt.Fprintln(out)
+				}()
+			} else if !warned {
+				fmt.Fprintln(out, "(warning: cannot hide input on this terminal)")
+				warned = true
+			}
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+}
+
+// stty runs stty with args against the terminal in
+func stty(in *os.File, args ...string) error {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = in
+	return cmd.Run()
+}
@@ -0,0 +1,152 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// scriptedReader returns answers in order, like a user typing them
+func scriptedReader(answers ...string) func() (string, error) {
+	return func() (string, error) {
+		if len(answers) == 0 {
+			return "", errors.New("no more input")
+		}
+		a := answers[0]
+		answers = answers[1:]
+		return a, nil
+	}
+}
+
+func TestRunSecretsWizard(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.txt")
+	if _, err := GenerateAgeKey(KeygenOptions{KeyPath: keyPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry([]EnvVar{
+		{Name: "API_KEY", Secret: true, Required: true, Validate: func(v string) error {
+			if !strings.HasPrefix(v, "sk_") {
+				return errors.New("must start with sk_")
+			}
+			return nil
+		}},
+		{Name: "SMTP_PORT", Secret: true, Default: "587"},
+		{Name: "WEBHOOK_SECRET", Secret: true},
+		{Name: "LOG_LEVEL", Default: "info"},
+	})
+	secretsEnv := SecretsLocal.WithBaseDir(dir)
+
+	var out bytes.Buffer
+	result, err := RunSecretsWizard(SecretsWizardOptions{
+		Registry:    registry,
+		Environment: secretsEnv,
+		KeyPath:     keyPath,
+		Out:         &out,
+		// API_KEY: invalid then valid; SMTP_PORT: not an integer then valid; WEBHOOK_SECRET: empty
+		ReadSecret: scriptedReader("wrong", "sk_live_123", "abc", "2525", ""),
+	})
+	if err != nil {
+		t.Fatalf("RunSecretsWizard failed: %v", err)
+	}
+
+	if strings.Join(result.Set, ",") != "API_KEY,SMTP_PORT" || strings.Join(result.Empty, ",") != "WEBHOOK_SECRET" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if !strings.Contains(out.String(), "must start with sk_") || !strings.Contains(out.String(), "expected integer") {
+		t.Errorf("expected validation messages, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "sk_live_123") {
+		t.Error("secret value echoed to output")
+	}
+	if secretsEnv.Exists() {
+		t.Error("plaintext secrets file must not be written")
+	}
+
+	// Second run: Enter keeps existing values
+	result, err = RunSecretsWizard(SecretsWizardOptions{
+		Registry:    registry,
+		Environment: secretsEnv,
+		KeyPath:     keyPath,
+		Out:         &out,
+		ReadSecret:  scriptedReader("", "", "whsec_1"),
+	})
+	if err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if strings.Join(result.Kept, ",") != "API_KEY,SMTP_PORT" || strings.Join(result.Set, ",") != "WEBHOOK_SECRET" {
+		t.Errorf("unexpected second result: %+v", result)
+	}
+
+	identities, _ := loadIdentityFile(keyPath)
+	data, _ := os.ReadFile(secretsEnv.FullEncryptedPath())
+	plaintext, err := decryptWithIdentities(data, identities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := ParseSecretsFile(plaintext)
+	if values["API_KEY"] != "sk_live_123" || values["SMTP_PORT"] != "2525" || values["WEBHOOK_SECRET"] != "whsec_1" {
+		t.Errorf("unexpected encrypted values: %v", values)
+	}
+	if _, ok := values["LOG_LEVEL"]; ok {
+		t.Error("non-secret variables must not be written")
+	}
+}
+
+func TestSetSecrets(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.txt")
+	if _, err := GenerateAgeKey(KeygenOptions{KeyPath: keyPath}); err != nil {
+		t.Fatal(err)
+	}
+	registry := NewRegistry([]EnvVar{
+		{Name: "API_KEY", Secret: true},
+		{Name: "SMTP_PORT", Secret: true, Default: "587"},
+		{Name: "CLIENT_ID", Secret: true},
+	})
+	secretsEnv := SecretsLocal.WithBaseDir(dir)
+
+	if values, err := ReadSecrets(secretsEnv, keyPath); err != nil || len(values) != 0 {
+		t.Fatalf("ReadSecrets before the file exists = %v, %v", values, err)
+	}
+
+	set := func(values map[string]string) error {
+		_, err := SetSecrets(SetSecretsOptions{Registry: registry, Values: values, Environment: secretsEnv, KeyPath: keyPath})
+		return err
+	}
+	if err := set(map[string]string{"API_KEY": "sk_1", "SMTP_PORT": "2525"}); err != nil {
+		t.Fatal(err)
+	}
+	// A tool setting only its own values keeps the others
+	if err := set(map[string]string{"CLIENT_ID": "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := set(map[string]string{"SMTP_PORT": "lots"}); err == nil {
+		t.Error("invalid value should be rejected")
+	}
+	if err := set(map[string]string{"UNKNOWN": "x"}); err == nil {
+		t.Error("unregistered name should be rejected")
+	}
+
+	values, err := ReadSecrets(secretsEnv, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["API_KEY"] != "sk_1" || values["SMTP_PORT"] != "2525" || values["CLIENT_ID"] != "abc" {
+		t.Errorf("values = %v", values)
+	}
+
+	if err := set(map[string]string{"CLIENT_ID": ""}); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ := ReadSecrets(secretsEnv, keyPath); values["CLIENT_ID"] != "" || values["API_KEY"] != "sk_1" {
+		t.Errorf("after clearing CLIENT_ID: %v", values)
+	}
+	if secretsEnv.Exists() {
+		t.Error("plaintext secrets file must not be written")
+	}
+}
@@ -5,8 +5,9 @@
 // # Overview
 //
 // The workflow package implements the standard 3-phase environment management
-// workflow:
+// workflow, preceded by a one-time init:
 //
+//  0. Init: Scaffold → Age Key → Git Hooks → Registry Sync
 //  1. Registry Sync: Registry → Templates → Deployment Configs
 //  2. Environments Sync: Secrets → Environments → Validation
 //  3. Finalize: Encryption → Git Staging
@@ -14,7 +15,21 @@
 // Each workflow is implemented as a single function that handles all steps,
 // error handling, and returns structured results.
 //
-// # The Three Workflows
+// # The Workflows
+//
+// InitWorkflow - Phase 0: Once per project
+//
+//	result, err := workflow.InitWorkflow(workflow.InitOptions{
+//	    Generator: scaffold.GeneratorOptions{AppName: "My Application"},
+//	    Registry:  AppRegistry, // nil = skip sync until registry.go is compiled
+//	    Sync:      workflow.RegistrySyncOptions{CreateSecretsFiles: true},
+//	})
+//
+// What it does:
+//   - Generates registry.go (an existing one is kept unless Generator.Force)
+//   - Generates the age encryption key (an existing key is kept)
+//   - Installs the pre-commit hook (a missing .git is only a warning)
+//   - Runs SyncRegistryWorkflow when a Registry is provided
 //
 // SyncRegistryWorkflow - Phase 1: After editing registry.go
 //
@@ -64,6 +79,37 @@
 //   - Optionally adds encrypted files to git staging area
 //   - Returns list of files ready for commit
 //
+// VerifyWorkflow - CI: Check everything without writing
+//
+//	result, err := workflow.VerifyWorkflow(workflow.VerifyOptions{
+//	    Registry:          AppRegistry,
+//	    DeploymentConfigs: deploymentConfigs,
+//	    ValidateRequired:  true,
+//	})
+//	if err != nil || result.HasErrors() {
+//	    os.Exit(1)
+//	}
+//
+// What it does:
+//   - Dry-runs the deployment config syncs and fails on stale sections
+//   - Diffs .env.local/.env.production against the registry
+//   - Fails when a plaintext file is newer than its .age version
+//   - Optionally validates that all required variables are set
+//
+// ReviewAppWorkflow - CI: Fly.io review app per pull request
+//
+//	// on: pull_request (opened, reopened, synchronize, closed)
+//	result, err := workflow.ReviewAppWorkflow(workflow.ReviewAppOptions{
+//	    Registry: AppRegistry,
+//	    App:      deploy.ReviewAppOptions{Volume: "pb_data"},
+//	})
+//
+// What it does:
+//   - Reads the action and PR number from $GITHUB_EVENT_PATH
+//   - On open/push: creates "<app>-pr-<number>", copies the .env.local secrets,
+//     deploys and comments the URL on the PR (first deploy only)
+//   - On close: destroys the review app
+//
 // # Design Philosophy
 //
 // Library vs CLI Separation:
@@ -107,6 +153,18 @@
 //	    }
 //	}
 //
+// # Output Formats
+//
+// Progress is written to OutputWriter as Events, rendered per OutputFormat:
+//
+//	OutputText     ✅ Updated .env.local        (default)
+//	OutputPlain    [updated] Updated .env.local (no emoji, for CI logs)
+//	OutputVerbose  text plus detail events
+//	OutputQuiet    warnings and errors only
+//	OutputJSON     {"workflow":"sync-registry","level":"info","action":"updated",...}
+//
+// CLIs can print their own lines in the same style with NewReporter.
+//
 // # Options Patterns
 //
 // All workflows use Options structs for clean, extensible APIs:
@@ -145,6 +203,7 @@
 //	            return r.GenerateDockerComposeEnv([]string{}), nil
 //	        },
 //	    },
+//	    workflow.MarkdownReferenceConfig("CONFIGURATION.md"), // Created if missing
 //	}
 //
 // # Error Handling
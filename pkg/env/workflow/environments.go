@@ -2,7 +2,6 @@ package workflow
 
 import (
 	"fmt"
-	"io"
 	"os"
 
 	"github.com/joeblew999/wellknown/pkg/env"
@@ -18,11 +17,7 @@ import (
 func SyncEnvironmentsWorkflow(opts EnvironmentsSyncOptions) (*WorkflowResult, error) {
 	result := &WorkflowResult{}
 
-	// Use discard writer if none provided
-	w := opts.OutputWriter
-	if w == nil {
-		w = io.Discard
-	}
+	out := newProgress(opts.OutputWriter, opts.OutputFormat, "sync-environments", result)
 
 	// Validate inputs
 	if opts.Registry == nil {
@@ -36,11 +31,11 @@ func SyncEnvironmentsWorkflow(opts EnvironmentsSyncOptions) (*WorkflowResult, er
 	if opts.LocalEnv != nil {
 		secretsEnv, usedFallback := env.ResolveSecretsFile(opts.LocalEnv)
 		if secretsEnv == nil {
-			return nil, fmt.Errorf("no secrets file found for %s", opts.LocalEnv.Name)
+			return nil, env.Classify(env.ErrSecretsNotFound, fmt.Errorf("no secrets file found for %s", opts.LocalEnv.Name))
 		}
 
 		if usedFallback {
-			result.AddWarning(fmt.Sprintf("Using fallback secrets file: %s", secretsEnv.FileName))
+			out.warn(fmt.Sprintf("Using fallback secrets file: %s", secretsEnv.FileName))
 		}
 
 		secrets, err := env.LoadSecrets(env.SecretsSource{
@@ -52,23 +47,26 @@ func SyncEnvironmentsWorkflow(opts EnvironmentsSyncOptions) (*WorkflowResult, er
 		}
 
 		template := opts.LocalEnv.Generate(opts.Registry, opts.AppName)
-		mergedContent := env.MergeIntoTemplate(template, secrets)
+		mergedContent, err := env.MergeIntoTemplate(template, secrets)
+		if err != nil {
+			return result, fmt.Errorf("failed to merge %s: %w", secretsEnv.FileName, err)
+		}
 
 		if err := os.WriteFile(opts.LocalEnv.FullPath(), []byte(mergedContent), 0600); err != nil {
 			return result, fmt.Errorf("failed to write %s: %w", opts.LocalEnv.FileName, err)
 		}
-		result.AddUpdated(opts.LocalEnv.FileName)
+		out.updated(opts.LocalEnv.FileName)
 	}
 
 	// Step 2: Sync production environment (if provided)
 	if opts.ProductionEnv != nil {
 		secretsEnvProd, usedFallbackProd := env.ResolveSecretsFile(opts.ProductionEnv)
 		if secretsEnvProd == nil {
-			return result, fmt.Errorf("no secrets file found for %s", opts.ProductionEnv.Name)
+			return result, env.Classify(env.ErrSecretsNotFound, fmt.Errorf("no secrets file found for %s", opts.ProductionEnv.Name))
 		}
 
 		if usedFallbackProd {
-			result.AddWarning(fmt.Sprintf("Using fallback secrets file: %s", secretsEnvProd.FileName))
+			out.warn(fmt.Sprintf("Using fallback secrets file: %s", secretsEnvProd.FileName))
 		}
 
 		secretsProd, err := env.LoadSecrets(env.SecretsSource{
@@ -80,18 +78,21 @@ func SyncEnvironmentsWorkflow(opts EnvironmentsSyncOptions) (*WorkflowResult, er
 		}
 
 		templateProd := opts.ProductionEnv.Generate(opts.Registry, opts.AppName)
-		mergedContentProd := env.MergeIntoTemplate(templateProd, secretsProd)
+		mergedContentProd, err := env.MergeIntoTemplate(templateProd, secretsProd)
+		if err != nil {
+			return result, fmt.Errorf("failed to merge %s: %w", secretsEnvProd.FileName, err)
+		}
 
 		if err := os.WriteFile(opts.ProductionEnv.FullPath(), []byte(mergedContentProd), 0600); err != nil {
 			return result, fmt.Errorf("failed to write %s: %w", opts.ProductionEnv.FileName, err)
 		}
-		result.AddUpdated(opts.ProductionEnv.FileName)
+		out.updated(opts.ProductionEnv.FileName)
 	}
 
 	// Step 3: Validate required variables (optional)
 	if opts.ValidateRequired {
 		if err := opts.Registry.ValidateRequired(); err != nil {
-			result.AddWarning(fmt.Sprintf("Validation failed: %v", err))
+			out.warn(fmt.Sprintf("Validation failed: %v", err))
 		}
 	}
 
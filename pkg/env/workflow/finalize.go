@@ -2,7 +2,6 @@ package workflow
 
 import (
 	"fmt"
-	"io"
 	"os/exec"
 	"strings"
 
@@ -18,11 +17,7 @@ import (
 func FinalizeWorkflow(opts FinalizeOptions) (*WorkflowResult, error) {
 	result := &WorkflowResult{}
 
-	// Use discard writer if none provided
-	w := opts.OutputWriter
-	if w == nil {
-		w = io.Discard
-	}
+	out := newProgress(opts.OutputWriter, opts.OutputFormat, "finalize", result)
 
 	// Validate inputs
 	if opts.EncryptionKeyPath == "" {
@@ -44,13 +39,13 @@ func FinalizeWorkflow(opts FinalizeOptions) (*WorkflowResult, error) {
 
 	// Transfer results from encryption to workflow result
 	for _, file := range encryptResult.ProcessedFiles {
-		result.AddGenerated(file)
+		out.generated(file)
 	}
 	for _, file := range encryptResult.SkippedFiles {
-		result.AddSkipped(file)
+		out.skipped(file, "not found (skipped)")
 	}
 	for _, err := range encryptResult.Errors {
-		result.AddWarning(err.Error())
+		out.warn(err.Error())
 	}
 
 	// Step 2: Git add (optional)
@@ -66,8 +61,15 @@ func FinalizeWorkflow(opts FinalizeOptions) (*WorkflowResult, error) {
 		if len(encryptedPaths) > 0 {
 			args := append([]string{"add"}, encryptedPaths...)
 			cmd := exec.Command("git", args...)
-			if err := cmd.Run(); err != nil {
-				result.AddWarning(fmt.Sprintf("Failed to git add files: %v. You can manually add: git add %s",
+			// Git echoes paths and hook output; mask any secrets before they reach the writer
+			out.detail("git %s", strings.Join(args, " "))
+			raw := env.RedactingWriter(out.Raw(), opts.Registry)
+			cmd.Stdout = raw
+			cmd.Stderr = raw
+			err := cmd.Run()
+			raw.Flush()
+			if err != nil {
+				out.warn(fmt.Sprintf("Failed to git add files: %v. You can manually add: git add %s",
 					err, strings.Join(encryptedPaths, " ")))
 			}
 		}
@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/env/scaffold"
+)
+
+// InitWorkflow orchestrates project initialization (phase 0)
+// This workflow:
+// 1. Generates registry.go (skipped if it already exists and Force is false)
+// 2. Generates an age encryption key (an existing key is kept)
+// 3. Installs the pre-commit hook (an existing hook is kept)
+// 4. Runs SyncRegistryWorkflow when a compiled Registry is provided
+//
+// Missing git or an existing hook is not fatal - init continues with a warning.
+//
+// Returns a WorkflowResult with details about files created/updated/skipped
+func InitWorkflow(opts InitOptions) (*WorkflowResult, error) {
+	result := &WorkflowResult{}
+
+	out := newProgress(opts.OutputWriter, opts.OutputFormat, "init", result)
+
+	if opts.KeyPath == "" {
+		opts.KeyPath = env.DefaultAgeKeyPath
+	}
+	if opts.HookPath == "" {
+		opts.HookPath = ".git/hooks/pre-commit"
+	}
+
+	// Step 1: Scaffold registry.go
+	registryPath := filepath.Join(opts.Generator.Dir, "registry.go")
+	if scaffold.RegistryExists(opts.Generator.Dir) && !opts.Generator.Force {
+		out.skipped(registryPath, "exists (not overwriting)")
+	} else {
+		if err := scaffold.GenerateRegistry(opts.Generator); err != nil {
+			return nil, fmt.Errorf("failed to generate registry: %w", err)
+		}
+		out.generated(registryPath)
+	}
+
+	// Step 2: Age key
+	if !opts.SkipKeygen {
+		if _, err := os.Stat(opts.KeyPath); err == nil {
+			out.skipped(opts.KeyPath, "exists (keeping existing key)")
+		} else {
+			keygen, err := env.GenerateAgeKey(env.KeygenOptions{KeyPath: opts.KeyPath})
+			if err != nil {
+				return result, fmt.Errorf("failed to generate age key: %w", err)
+			}
+			out.generated(keygen.KeyPath)
+			out.ok("Age public key: " + keygen.PublicKey)
+		}
+	}
+
+	// Step 3: Git hooks
+	if !opts.SkipHooks {
+		if _, err := os.Stat(opts.HookPath); err == nil {
+			out.skipped(opts.HookPath, "exists (not overwriting)")
+		} else if hooks, err := scaffold.InstallGitHooks(scaffold.GitHooksOptions{HookPath: opts.HookPath}); err != nil {
+			out.warn(fmt.Sprintf("Skipped git hooks: %v", err))
+		} else {
+			out.generated(hooks.HookPath)
+		}
+	}
+
+	// Step 4: Initial sync-registry
+	if opts.Registry == nil {
+		out.warn("Skipped sync-registry: build with the new registry.go, then run sync-registry")
+		return result, nil
+	}
+
+	syncOpts := opts.Sync
+	syncOpts.Registry = opts.Registry
+	syncOpts.OutputWriter = opts.OutputWriter
+	syncOpts.OutputFormat = opts.OutputFormat
+	if syncOpts.AppName == "" {
+		syncOpts.AppName = opts.Generator.AppName
+	}
+	syncResult, err := SyncRegistryWorkflow(syncOpts)
+	if err != nil {
+		return result, fmt.Errorf("failed to sync registry: %w", err)
+	}
+	result.merge(syncResult)
+
+	return result, nil
+}
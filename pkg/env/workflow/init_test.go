@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/env/scaffold"
+)
+
+// Test InitWorkflow in a fresh git repository
+func TestInitWorkflow_Fresh(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	if err := os.Mkdir(".git", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "API_KEY", Description: "API key", Secret: true, Group: "Test"},
+	})
+
+	result, err := InitWorkflow(InitOptions{
+		Generator: scaffold.GeneratorOptions{AppName: "Test App"},
+		Registry:  registry,
+		Sync:      RegistrySyncOptions{CreateSecretsFiles: true},
+	})
+	if err != nil {
+		t.Fatalf("InitWorkflow failed: %v", err)
+	}
+
+	for _, path := range []string{"registry.go", env.DefaultAgeKeyPath, ".git/hooks/pre-commit", env.Local.FileName, env.SecretsLocal.FileName} {
+		if !fileExists(path) {
+			t.Errorf("Expected %s to be created", path)
+		}
+	}
+	if result.HasWarnings() {
+		t.Errorf("Expected no warnings, got %v", result.Warnings)
+	}
+	if len(result.GeneratedFiles) != 5 {
+		t.Errorf("Expected 5 generated files, got %v", result.GeneratedFiles)
+	}
+}
+
+// Test InitWorkflow keeps existing files and tolerates a missing git repo
+func TestInitWorkflow_Existing(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.WriteFile("registry.go", []byte("package main\n"), 0644)
+	os.MkdirAll(filepath.Dir(env.DefaultAgeKeyPath), 0700)
+	os.WriteFile(env.DefaultAgeKeyPath, []byte("existing"), 0600)
+
+	result, err := InitWorkflow(InitOptions{})
+	if err != nil {
+		t.Fatalf("InitWorkflow failed: %v", err)
+	}
+
+	if readFile("registry.go") != "package main\n" {
+		t.Error("registry.go was overwritten")
+	}
+	if readFile(env.DefaultAgeKeyPath) != "existing" {
+		t.Error("age key was overwritten")
+	}
+	if len(result.SkippedFiles) != 2 {
+		t.Errorf("Expected 2 skipped files, got %v", result.SkippedFiles)
+	}
+	// Missing .git and nil Registry are both warnings
+	if len(result.Warnings) != 2 {
+		t.Errorf("Expected 2 warnings, got %v", result.Warnings)
+	}
+}
@@ -0,0 +1,199 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ================================================================
+// Output Formatting
+// ================================================================
+// Workflows report progress as Events through a Reporter, which renders them
+// in the OutputFormat chosen by the caller:
+//
+//	text     ✅ Updated .env.local            (default)
+//	plain    [updated] .env.local             (no emoji: CI logs, Windows terminals)
+//	verbose  text plus per-step detail
+//	quiet    warnings and errors only
+//	json     one JSON object per line
+
+// OutputFormat selects how workflow progress is written to OutputWriter
+type OutputFormat string
+
+const (
+	OutputText    OutputFormat = "text"    // Emoji-prefixed lines (default)
+	OutputPlain   OutputFormat = "plain"   // Like text, with ASCII tags instead of emoji
+	OutputVerbose OutputFormat = "verbose" // Like text, including detail events
+	OutputQuiet   OutputFormat = "quiet"   // Warnings and errors only
+	OutputJSON    OutputFormat = "json"    // JSON-lines events
+)
+
+// ParseOutputFormat parses a --output flag value ("" means OutputText)
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return OutputText, nil
+	case OutputText, OutputPlain, OutputVerbose, OutputQuiet, OutputJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format: %s (use text, plain, verbose, quiet or json)", s)
+	}
+}
+
+// EventLevel is the severity of an Event
+type EventLevel string
+
+const (
+	LevelDetail EventLevel = "detail" // Shown only in verbose and json output
+	LevelInfo   EventLevel = "info"
+	LevelWarn   EventLevel = "warn"
+	LevelError  EventLevel = "error"
+)
+
+// Event is one progress message from a workflow
+type Event struct {
+	Workflow string     `json:"workflow"`         // e.g. "sync-registry"
+	Level    EventLevel `json:"level"`            // Severity
+	Action   string     `json:"action,omitempty"` // "generated", "updated", "skipped", "ok" (empty for plain messages)
+	File     string     `json:"file,omitempty"`   // File the event refers to
+	Message  string     `json:"message"`          // Human-readable text
+}
+
+// Reporter writes Events in an OutputFormat
+type Reporter struct {
+	w      io.Writer
+	format OutputFormat
+}
+
+// NewReporter creates a reporter writing to w (nil = discard)
+func NewReporter(w io.Writer, format OutputFormat) *Reporter {
+	if w == nil {
+		w = io.Discard
+	}
+	if format == "" {
+		format = OutputText
+	}
+	return &Reporter{w: w, format: format}
+}
+
+// Emit writes one event, or nothing if the format hides its level
+func (r *Reporter) Emit(e Event) {
+	switch r.format {
+	case OutputJSON:
+		data, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+		r.w.Write(append(data, '\n'))
+		return
+	case OutputQuiet:
+		if e.Level != LevelWarn && e.Level != LevelError {
+			return
+		}
+	case OutputText, OutputPlain:
+		if e.Level == LevelDetail {
+			return
+		}
+	}
+
+	prefix := eventEmoji(e)
+	if r.format == OutputPlain || r.format == OutputQuiet {
+		prefix = eventTag(e)
+	}
+	fmt.Fprintf(r.w, "%s %s\n", prefix, e.Message)
+}
+
+// Raw returns a writer for unstructured output (e.g. git), or io.Discard for
+// formats that must stay machine-readable or silent
+func (r *Reporter) Raw() io.Writer {
+	if r.format == OutputJSON || r.format == OutputQuiet {
+		return io.Discard
+	}
+	return r.w
+}
+
+// eventEmoji returns the text-format prefix for an event
+func eventEmoji(e Event) string {
+	switch {
+	case e.Level == LevelError:
+		return "❌"
+	case e.Level == LevelWarn:
+		return "⚠️ "
+	case e.Level == LevelDetail:
+		return "  "
+	case e.Action == "skipped":
+		return "ℹ️ "
+	default:
+		return "✅"
+	}
+}
+
+// eventTag returns the ASCII prefix for an event
+func eventTag(e Event) string {
+	switch {
+	case e.Level == LevelError:
+		return "[error]"
+	case e.Level == LevelWarn:
+		return "[warn]"
+	case e.Action != "":
+		return "[" + e.Action + "]"
+	default:
+		return "[info]"
+	}
+}
+
+// ================================================================
+// Workflow progress (Reporter + WorkflowResult)
+// ================================================================
+
+// progress records into a WorkflowResult and reports each entry as an Event
+type progress struct {
+	*Reporter
+	workflow string
+	result   *WorkflowResult
+}
+
+func newProgress(w io.Writer, format OutputFormat, workflow string, result *WorkflowResult) *progress {
+	return &progress{Reporter: NewReporter(w, format), workflow: workflow, result: result}
+}
+
+func (p *progress) emit(level EventLevel, action, file, msg string) {
+	p.Emit(Event{Workflow: p.workflow, Level: level, Action: action, File: file, Message: msg})
+}
+
+func (p *progress) generated(file string) {
+	p.result.AddGenerated(file)
+	p.emit(LevelInfo, "generated", file, "Created "+file)
+}
+
+func (p *progress) updated(file string) {
+	p.result.AddUpdated(file)
+	p.emit(LevelInfo, "updated", file, "Updated "+file)
+}
+
+func (p *progress) skipped(file, reason string) {
+	p.result.AddSkipped(file)
+	p.emit(LevelInfo, "skipped", file, fmt.Sprintf("%s %s", file, reason))
+}
+
+func (p *progress) warn(msg string) {
+	p.result.AddWarning(msg)
+	p.emit(LevelWarn, "", "", msg)
+}
+
+func (p *progress) fail(err error) {
+	p.result.AddError(err)
+	p.emit(LevelError, "", "", err.Error())
+}
+
+// ok reports a passed check without recording it in the result
+func (p *progress) ok(msg string) {
+	p.emit(LevelInfo, "ok", "", msg)
+}
+
+// detail reports verbose-only information
+func (p *progress) detail(format string, args ...interface{}) {
+	p.emit(LevelDetail, "", "", fmt.Sprintf(format, args...))
+}
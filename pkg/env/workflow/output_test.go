@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	for in, want := range map[string]OutputFormat{"": OutputText, "JSON": OutputJSON, " plain ": OutputPlain} {
+		got, err := ParseOutputFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseOutputFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseOutputFormat("xml"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestReporter_Formats(t *testing.T) {
+	events := []Event{
+		{Workflow: "test", Level: LevelInfo, Action: "updated", File: "a", Message: "Updated a"},
+		{Workflow: "test", Level: LevelDetail, Message: "detail"},
+		{Workflow: "test", Level: LevelWarn, Message: "careful"},
+	}
+	render := func(format OutputFormat) string {
+		var buf bytes.Buffer
+		r := NewReporter(&buf, format)
+		for _, e := range events {
+			r.Emit(e)
+		}
+		return buf.String()
+	}
+
+	if got := render(OutputText); got != "✅ Updated a\n⚠️  careful\n" {
+		t.Errorf("text output = %q", got)
+	}
+	if got := render(OutputPlain); got != "[updated] Updated a\n[warn] careful\n" {
+		t.Errorf("plain output = %q", got)
+	}
+	if got := render(OutputQuiet); got != "[warn] careful\n" {
+		t.Errorf("quiet output = %q", got)
+	}
+	if got := render(OutputVerbose); !strings.Contains(got, "detail") {
+		t.Errorf("verbose output missing detail: %q", got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(render(OutputJSON)), "\n")
+	if len(lines) != len(events) {
+		t.Fatalf("Expected %d JSON lines, got %d", len(events), len(lines))
+	}
+	var e Event
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil || e != events[0] {
+		t.Errorf("JSON line = %q (%v)", lines[0], err)
+	}
+}
+
+// Test that workflow events reach the writer as JSON lines
+func TestSyncRegistryWorkflow_JSONOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "TEST", Description: "Test"},
+	})
+
+	var buf bytes.Buffer
+	if _, err := SyncRegistryWorkflow(RegistrySyncOptions{
+		Registry:     registry,
+		OutputWriter: &buf,
+		OutputFormat: OutputJSON,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		if e.Workflow != "sync-registry" || e.Action != "updated" {
+			t.Errorf("unexpected event %+v", e)
+		}
+		files = append(files, e.File)
+	}
+	if len(files) != 2 {
+		t.Errorf("Expected 2 updated events, got %v", files)
+	}
+}
@@ -2,7 +2,6 @@ package workflow
 
 import (
 	"fmt"
-	"io"
 	"os"
 
 	"github.com/joeblew999/wellknown/pkg/env"
@@ -18,11 +17,7 @@ import (
 func SyncRegistryWorkflow(opts RegistrySyncOptions) (*WorkflowResult, error) {
 	result := &WorkflowResult{}
 
-	// Use discard writer if none provided
-	w := opts.OutputWriter
-	if w == nil {
-		w = io.Discard
-	}
+	out := newProgress(opts.OutputWriter, opts.OutputFormat, "sync-registry", result)
 
 	// Validate inputs
 	if opts.Registry == nil {
@@ -48,9 +43,22 @@ func SyncRegistryWorkflow(opts RegistrySyncOptions) (*WorkflowResult, error) {
 			}
 		}
 
+		if _, err := os.Stat(cfg.FilePath); os.IsNotExist(err) && cfg.Create != nil {
+			content, err := cfg.Create(opts.Registry)
+			if err == nil {
+				err = os.WriteFile(cfg.FilePath, []byte(content), 0644)
+			}
+			if err != nil {
+				out.warn(fmt.Sprintf("Failed to create %s: %v", cfg.FilePath, err))
+			} else {
+				out.generated(cfg.FilePath)
+			}
+			continue
+		}
+
 		content, err := cfg.Generator(opts.Registry)
 		if err != nil {
-			result.AddWarning(fmt.Sprintf("Failed to generate %s: %v", cfg.FilePath, err))
+			out.warn(fmt.Sprintf("Failed to generate %s: %v", cfg.FilePath, err))
 			continue
 		}
 
@@ -62,9 +70,9 @@ func SyncRegistryWorkflow(opts RegistrySyncOptions) (*WorkflowResult, error) {
 		})
 
 		if err != nil {
-			result.AddWarning(fmt.Sprintf("Failed to sync %s: %v", cfg.FilePath, err))
+			out.warn(fmt.Sprintf("Failed to sync %s: %v", cfg.FilePath, err))
 		} else {
-			result.AddUpdated(cfg.FilePath)
+			out.updated(cfg.FilePath)
 		}
 	}
 
@@ -75,14 +83,14 @@ func SyncRegistryWorkflow(opts RegistrySyncOptions) (*WorkflowResult, error) {
 		if err := os.WriteFile(env.Local.FullPath(), []byte(localContent), 0600); err != nil {
 			return result, fmt.Errorf("failed to write %s: %w", env.Local.FileName, err)
 		}
-		result.AddUpdated(env.Local.FileName)
+		out.updated(env.Local.FileName)
 
 		// Update production environment template
 		prodContent := env.Production.Generate(opts.Registry, opts.AppName)
 		if err := os.WriteFile(env.Production.FullPath(), []byte(prodContent), 0600); err != nil {
 			return result, fmt.Errorf("failed to write %s: %w", env.Production.FileName, err)
 		}
-		result.AddUpdated(env.Production.FileName)
+		out.updated(env.Production.FileName)
 	}
 
 	// Step 4: Generate secrets templates if they don't exist (and requested)
@@ -95,9 +103,9 @@ func SyncRegistryWorkflow(opts RegistrySyncOptions) (*WorkflowResult, error) {
 			if err := os.WriteFile(env.SecretsLocal.FullPath(), []byte(secretsContent), 0600); err != nil {
 				return result, fmt.Errorf("failed to write %s: %w", env.SecretsLocal.FileName, err)
 			}
-			result.AddGenerated(env.SecretsLocal.FileName)
+			out.generated(env.SecretsLocal.FileName)
 		} else {
-			result.AddSkipped(env.SecretsLocal.FileName)
+			out.skipped(env.SecretsLocal.FileName, "exists (not overwriting)")
 		}
 
 		// Production secrets
@@ -106,9 +114,9 @@ func SyncRegistryWorkflow(opts RegistrySyncOptions) (*WorkflowResult, error) {
 			if err := os.WriteFile(env.SecretsProduction.FullPath(), []byte(secretsContentProd), 0600); err != nil {
 				return result, fmt.Errorf("failed to write %s: %w", env.SecretsProduction.FileName, err)
 			}
-			result.AddGenerated(env.SecretsProduction.FileName)
+			out.generated(env.SecretsProduction.FileName)
 		} else {
-			result.AddSkipped(env.SecretsProduction.FileName)
+			out.skipped(env.SecretsProduction.FileName, "exists (not overwriting)")
 		}
 	}
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/joeblew999/wellknown/pkg/env"
@@ -466,6 +467,45 @@ func TestSyncRegistryWorkflow_SkipEnvironments(t *testing.T) {
 	}
 }
 
+func TestSyncRegistryWorkflow_MarkdownReference(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "TEST_VAR", Description: "Test variable", Default: "test", Group: "Test"},
+	})
+	opts := RegistrySyncOptions{
+		Registry:          registry,
+		SkipEnvironments:  true,
+		DeploymentConfigs: []DeploymentConfig{MarkdownReferenceConfig("")},
+	}
+
+	// First sync creates the file
+	result, err := SyncRegistryWorkflow(opts)
+	if err != nil {
+		t.Fatalf("SyncRegistryWorkflow failed: %v", err)
+	}
+	if len(result.GeneratedFiles) != 1 || result.GeneratedFiles[0] != env.DefaultReferenceFile {
+		t.Fatalf("expected %s to be generated, got %+v", env.DefaultReferenceFile, result)
+	}
+
+	// Later syncs update the section, and verify sees it up to date
+	registry.Add(env.EnvVar{Name: "NEW_VAR", Description: "Added later", Group: "Test"})
+	if result, err = SyncRegistryWorkflow(opts); err != nil || len(result.UpdatedFiles) != 1 {
+		t.Fatalf("expected the reference to be updated, got %+v (%v)", result, err)
+	}
+	content, _ := os.ReadFile(env.DefaultReferenceFile)
+	if !strings.Contains(string(content), "`NEW_VAR`") {
+		t.Errorf("reference not updated:\n%s", content)
+	}
+	verify, err := VerifyWorkflow(VerifyOptions{Registry: registry, DeploymentConfigs: opts.DeploymentConfigs, DriftFiles: []*env.Environment{}, Environments: []*env.Environment{}})
+	if err != nil || verify.HasErrors() {
+		t.Errorf("expected verify to pass, got %+v (%v)", verify, err)
+	}
+}
+
 // Helper functions
 
 func fileExists(path string) bool {
@@ -0,0 +1,94 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/joeblew999/wellknown/pkg/env/deploy"
+)
+
+// PullRequestEvent is the part of a GitHub pull_request event payload the
+// review app workflow reads
+type PullRequestEvent struct {
+	Action string `json:"action"`
+	Number int    `json:"number"`
+}
+
+// ReadPullRequestEvent reads a GitHub event payload ($GITHUB_EVENT_PATH in Actions)
+func ReadPullRequestEvent(path string) (*PullRequestEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event %s: %w", path, err)
+	}
+	var event PullRequestEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse event %s: %w", path, err)
+	}
+	if event.Number == 0 {
+		return nil, fmt.Errorf("%s is not a pull_request event", path)
+	}
+	return &event, nil
+}
+
+// ReviewAppWorkflow manages the Fly.io review app of a pull request, run from
+// CI on every pull_request event:
+// 1. Resolves the action and PR number (from the event payload unless set)
+// 2. opened/reopened/synchronize: creates the app on first use, copies the
+// non-production secrets, deploys and posts the URL (see deploy.ReviewApp)
+// 3. closed: destroys the app (see deploy.DestroyReviewApp)
+//
+// Other actions (labeled, edited, ...) are skipped.
+func ReviewAppWorkflow(opts ReviewAppOptions) (*WorkflowResult, error) {
+	result := &WorkflowResult{}
+
+	out := newProgress(opts.OutputWriter, opts.OutputFormat, "review-app", result)
+
+	// Step 1: Resolve the event
+	if opts.Action == "" || opts.PRNumber == 0 {
+		if opts.EventPath == "" {
+			opts.EventPath = os.Getenv("GITHUB_EVENT_PATH")
+		}
+		if opts.EventPath == "" {
+			return nil, fmt.Errorf("action and PR number are required (or run in GitHub Actions)")
+		}
+		event, err := ReadPullRequestEvent(opts.EventPath)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Action == "" {
+			opts.Action = event.Action
+		}
+		if opts.PRNumber == 0 {
+			opts.PRNumber = event.Number
+		}
+	}
+
+	app := opts.App
+	app.PRNumber = opts.PRNumber
+	app.Registry = opts.Registry
+
+	// Steps 2-3: Deploy or destroy
+	switch opts.Action {
+	case "opened", "reopened", "synchronize":
+		deployed, err := deploy.ReviewAppWithOptions(app)
+		if err != nil {
+			out.fail(err)
+			return result, nil
+		}
+		if deployed.Created {
+			out.ok(fmt.Sprintf("Created review app %s", deployed.App))
+		}
+		out.ok(fmt.Sprintf("Review app for PR #%d deployed: %s", opts.PRNumber, deployed.URL))
+	case "closed":
+		if err := deploy.DestroyReviewAppWithOptions(app); err != nil {
+			out.fail(err)
+			return result, nil
+		}
+		out.ok(fmt.Sprintf("Review app for PR #%d destroyed", opts.PRNumber))
+	default:
+		out.detail("Pull request action %q does not affect review apps, skipped", opts.Action)
+	}
+
+	return result, nil
+}
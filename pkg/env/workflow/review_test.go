@@ -0,0 +1,58 @@
+package workflow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test ReadPullRequestEvent reads the action and number of a pull_request payload
+func TestReadPullRequestEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "event.json")
+	os.WriteFile(path, []byte(`{"action": "synchronize", "number": 42, "pull_request": {"title": "Add review apps"}}`), 0644)
+
+	event, err := ReadPullRequestEvent(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Action != "synchronize" || event.Number != 42 {
+		t.Errorf("event = %+v", event)
+	}
+
+	os.WriteFile(path, []byte(`{"ref": "refs/heads/main"}`), 0644)
+	if _, err := ReadPullRequestEvent(path); err == nil {
+		t.Error("expected error for a push event")
+	}
+}
+
+// Test ReviewAppWorkflow skips actions that don't affect review apps
+func TestReviewAppWorkflow_SkipsOtherActions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "event.json")
+	os.WriteFile(path, []byte(`{"action": "labeled", "number": 7}`), 0644)
+
+	var buf bytes.Buffer
+	result, err := ReviewAppWorkflow(ReviewAppOptions{
+		EventPath:    path,
+		OutputWriter: &buf,
+		OutputFormat: OutputVerbose,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasErrors() {
+		t.Errorf("unexpected errors: %v", result.Errors)
+	}
+	if !strings.Contains(buf.String(), `"labeled"`) {
+		t.Errorf("expected skip message, got: %s", buf.String())
+	}
+}
+
+// Test ReviewAppWorkflow needs an event when action or PR number is missing
+func TestReviewAppWorkflow_NoEvent(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	if _, err := ReviewAppWorkflow(ReviewAppOptions{Action: "opened"}); err == nil {
+		t.Error("expected error without PR number or event")
+	}
+}
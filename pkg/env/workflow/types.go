@@ -2,9 +2,12 @@
 package workflow
 
 import (
+	"errors"
 	"io"
 
 	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/env/deploy"
+	"github.com/joeblew999/wellknown/pkg/env/scaffold"
 )
 
 // ================================================================
@@ -18,16 +21,33 @@ type RegistrySyncOptions struct {
 	DeploymentConfigs  []DeploymentConfig  // Optional deployment configs to sync
 	CreateSecretsFiles bool                // Create .env.secrets.* templates if missing
 	OutputWriter       io.Writer           // Where to write progress messages (nil = discard)
+	OutputFormat       OutputFormat        // How progress is rendered (default: OutputText)
 	SyncOnlyConfigs    []string            // Optional: only sync these config files (nil = sync all)
 	SkipEnvironments   bool                // Skip .env.local/.env.production generation
 }
 
 // DeploymentConfig defines a deployment configuration file to sync
 type DeploymentConfig struct {
-	FilePath    string                             // Path to the config file
-	StartMarker string                             // Start marker for auto-generated section
-	EndMarker   string                             // End marker for auto-generated section
+	FilePath    string                              // Path to the config file
+	StartMarker string                              // Start marker for auto-generated section
+	EndMarker   string                              // End marker for auto-generated section
 	Generator   func(*env.Registry) (string, error) // Function to generate content
+	Create      func(*env.Registry) (string, error) // Whole file, written when FilePath does not exist (optional; must contain the markers)
+}
+
+// MarkdownReferenceConfig keeps the generated configuration reference at
+// path (default env.DefaultReferenceFile) in sync, creating it if missing
+func MarkdownReferenceConfig(path string) DeploymentConfig {
+	if path == "" {
+		path = env.DefaultReferenceFile
+	}
+	return DeploymentConfig{
+		FilePath:    path,
+		StartMarker: env.ReferenceStartMarker,
+		EndMarker:   env.ReferenceEndMarker,
+		Generator:   func(r *env.Registry) (string, error) { return r.GenerateMarkdownReferenceSection(), nil },
+		Create:      func(r *env.Registry) (string, error) { return r.GenerateMarkdownReference(), nil },
+	}
 }
 
 // EnvironmentsSyncOptions configures the environments synchronization workflow
@@ -40,6 +60,7 @@ type EnvironmentsSyncOptions struct {
 	ProductionSecrets *env.Environment  // Production secrets file
 	ValidateRequired  bool              // Whether to validate required variables
 	OutputWriter      io.Writer         // Where to write progress messages (nil = discard)
+	OutputFormat      OutputFormat      // How progress is rendered (default: OutputText)
 }
 
 // FinalizeOptions configures the finalization workflow (encryption + git)
@@ -48,6 +69,45 @@ type FinalizeOptions struct {
 	EncryptionKeyPath string             // Path to age encryption key
 	GitAdd            bool               // Whether to add encrypted files to git
 	OutputWriter      io.Writer          // Where to write progress messages (nil = discard)
+	OutputFormat      OutputFormat       // How progress is rendered (default: OutputText)
+	Registry          *env.Registry      // Secret values masked in git output (optional)
+}
+
+// InitOptions configures the project initialization workflow (scaffold + keygen + hooks)
+type InitOptions struct {
+	Generator    scaffold.GeneratorOptions // registry.go scaffolding (skipped if it exists and Force is false)
+	KeyPath      string                    // Path to age encryption key (default: env.DefaultAgeKeyPath)
+	SkipKeygen   bool                      // Don't generate an age key
+	SkipHooks    bool                      // Don't install the pre-commit hook
+	HookPath     string                    // Pre-commit hook path (default: ".git/hooks/pre-commit")
+	Registry     *env.Registry             // Registry for the initial sync (nil = skip; a new registry.go must be compiled first)
+	Sync         RegistrySyncOptions       // Options for the initial sync (Registry and output settings are filled in)
+	OutputWriter io.Writer                 // Where to write progress messages (nil = discard)
+	OutputFormat OutputFormat              // How progress is rendered (default: OutputText)
+}
+
+// VerifyOptions configures the verification workflow (the single CI check)
+type VerifyOptions struct {
+	Registry          *env.Registry      // Registry to verify against
+	DeploymentConfigs []DeploymentConfig // Configs whose generated sections must be up to date
+	DriftFiles        []*env.Environment // Files diffed against the registry (default: Local, Production; missing files are skipped)
+	Environments      []*env.Environment // Files checked for encryption freshness (default: env.AllEnvironmentFiles())
+	RequireEncrypted  bool               // Treat a plaintext file without a .age version as a failure
+	ValidateRequired  bool               // Whether required variables must be set (and policies hold) in the process environment
+	Platform          string             // Platform the environment files deploy to, for "unless platform" policies (e.g. "fly.io")
+	OutputWriter      io.Writer          // Where to write progress messages (nil = discard)
+	OutputFormat      OutputFormat       // How progress is rendered (default: OutputText)
+}
+
+// ReviewAppOptions configures the review app workflow (CI, on pull request events)
+type ReviewAppOptions struct {
+	Registry     *env.Registry           // Registry selecting the secrets copied to review apps
+	Action       string                  // Pull request action (default: from the event file)
+	PRNumber     int                     // Pull request number (default: from the event file)
+	EventPath    string                  // GitHub event payload (default: $GITHUB_EVENT_PATH)
+	App          deploy.ReviewAppOptions // Review app settings (PRNumber and Registry are filled in)
+	OutputWriter io.Writer               // Where to write progress messages (nil = discard)
+	OutputFormat OutputFormat            // How progress is rendered (default: OutputText)
 }
 
 // ================================================================
@@ -90,11 +150,26 @@ func (r *WorkflowResult) AddError(err error) {
 	}
 }
 
+// merge appends the files, warnings and errors of other
+func (r *WorkflowResult) merge(other *WorkflowResult) {
+	r.GeneratedFiles = append(r.GeneratedFiles, other.GeneratedFiles...)
+	r.UpdatedFiles = append(r.UpdatedFiles, other.UpdatedFiles...)
+	r.SkippedFiles = append(r.SkippedFiles, other.SkippedFiles...)
+	r.Warnings = append(r.Warnings, other.Warnings...)
+	r.Errors = append(r.Errors, other.Errors...)
+}
+
 // HasErrors returns true if any errors were encountered
 func (r *WorkflowResult) HasErrors() bool {
 	return len(r.Errors) > 0
 }
 
+// Err returns the errors joined (nil without errors), so errors.Is finds
+// their categories and env.ExitCode(result.Err()) is the exit code
+func (r *WorkflowResult) Err() error {
+	return errors.Join(r.Errors...)
+}
+
 // HasWarnings returns true if any warnings were generated
 func (r *WorkflowResult) HasWarnings() bool {
 	return len(r.Warnings) > 0
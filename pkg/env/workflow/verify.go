@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// VerifyWorkflow checks, without writing anything, that the project is in sync
+// This workflow:
+// 1. Dry-runs each deployment config sync and fails on stale sections
+// 2. Diffs the environment files against the registry (drift)
+// 3. Fails when a plaintext file is newer than its .age version
+// 4. Checks the registry's policies against each environment file
+// 5. Optionally validates required variables and policies in the process environment
+//
+// Every check runs; failures are collected in result.Errors and warnings in
+// result.Warnings, so !result.HasErrors() is the overall pass/fail.
+// The returned error is reserved for invalid options.
+func VerifyWorkflow(opts VerifyOptions) (*WorkflowResult, error) {
+	result := &WorkflowResult{}
+
+	out := newProgress(opts.OutputWriter, opts.OutputFormat, "verify", result)
+
+	// Validate inputs
+	if opts.Registry == nil {
+		return nil, fmt.Errorf("registry cannot be nil")
+	}
+	if opts.DriftFiles == nil {
+		opts.DriftFiles = []*env.Environment{env.Local, env.Production}
+	}
+	if opts.Environments == nil {
+		opts.Environments = env.AllEnvironmentFiles()
+	}
+
+	// Step 1: Dry-run deployment config syncs
+	for _, cfg := range opts.DeploymentConfigs {
+		content, err := cfg.Generator(opts.Registry)
+		if err != nil {
+			out.fail(fmt.Errorf("failed to generate %s: %w", cfg.FilePath, err))
+			continue
+		}
+		upToDate, err := env.SectionUpToDate(env.SyncOptions{
+			FilePath:    cfg.FilePath,
+			StartMarker: cfg.StartMarker,
+			EndMarker:   cfg.EndMarker,
+			Content:     content,
+		})
+		switch {
+		case err != nil:
+			out.fail(fmt.Errorf("failed to check %s: %w", cfg.FilePath, err))
+		case !upToDate:
+			out.fail(env.Classify(env.ErrDriftDetected, fmt.Errorf("%s is out of date (run sync-registry)", cfg.FilePath)))
+		default:
+			out.ok(cfg.FilePath + " is up to date")
+		}
+	}
+
+	// Step 2: Drift between registry and environment files
+	for _, e := range opts.DriftFiles {
+		if !e.Exists() {
+			out.detail("%s not present, drift check skipped", e.FileName)
+			continue
+		}
+		diff, err := env.DiffRegistry(opts.Registry, e.FullPath())
+		if err != nil {
+			out.fail(err)
+			continue
+		}
+		if len(diff.Missing) > 0 {
+			names := make([]string, len(diff.Missing))
+			for i, v := range diff.Missing {
+				names[i] = v.Name
+			}
+			out.fail(env.Classify(env.ErrDriftDetected, fmt.Errorf("%s is missing %s (run sync-registry)", e.FileName, strings.Join(names, ", "))))
+		}
+		if len(diff.Extra) > 0 {
+			out.warn(fmt.Sprintf("%s has variables not in the registry: %s", e.FileName, strings.Join(diff.Extra, ", ")))
+		}
+		if !diff.HasDrift() {
+			out.ok(e.FileName + " matches registry")
+		}
+	}
+
+	// Step 3: Encryption freshness
+	for _, e := range opts.Environments {
+		plain, err := os.Stat(e.FullPath())
+		if err != nil {
+			continue // No plaintext (e.g. CI) - nothing to compare
+		}
+		encrypted, err := os.Stat(e.FullEncryptedPath())
+		if err != nil {
+			msg := fmt.Sprintf("%s has no encrypted version (run finalize)", e.FileName)
+			if opts.RequireEncrypted {
+				out.fail(env.Classify(env.ErrNotEncrypted, errors.New(msg)))
+			} else {
+				out.warn(msg)
+			}
+			continue
+		}
+		if plain.ModTime().After(encrypted.ModTime()) {
+			out.fail(env.Classify(env.ErrNotEncrypted, fmt.Errorf("%s is newer than %s (run finalize)", e.FileName, e.EncryptedFileName())))
+			continue
+		}
+		out.ok(e.FileName + " is encrypted")
+	}
+
+	// Step 4: Policies, per environment file
+	for _, e := range opts.DriftFiles {
+		data, err := os.ReadFile(e.FullPath())
+		if err != nil {
+			continue // Missing files were reported by the drift check
+		}
+		values := env.ParseSecretsFile(data)
+		violations := opts.Registry.CheckPolicies(env.PolicyContext{
+			Environment: e.Name,
+			Platform:    opts.Platform,
+			Lookup:      func(name string) string { return values[name] },
+		})
+		for _, v := range violations {
+			out.fail(env.Classify(env.ErrPolicyViolation, fmt.Errorf("%s: %s", e.FileName, v.Message)))
+		}
+		if len(violations) == 0 {
+			out.detail("%s satisfies the policies", e.FileName)
+		}
+	}
+
+	// Step 5: Required variables and policies in the process environment
+	if opts.ValidateRequired {
+		if err := opts.Registry.ValidateRequired(); err != nil {
+			out.fail(err)
+		} else {
+			out.ok("Required variables set")
+		}
+		err := opts.Registry.ValidatePolicies(env.PolicyContext{
+			Environment: opts.Registry.Status().Name,
+			Platform:    env.DetectEnvironment(),
+		})
+		if err != nil {
+			out.fail(err)
+		}
+	}
+
+	return result, nil
+}
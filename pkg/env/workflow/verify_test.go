@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// Test VerifyWorkflow passes right after a sync
+func TestVerifyWorkflow_Pass(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "TEST_VAR", Description: "Test variable", Default: "test", Group: "Test"},
+	})
+	configs := []DeploymentConfig{{
+		FilePath:    "Dockerfile",
+		StartMarker: "# START",
+		EndMarker:   "# END",
+		Generator: func(r *env.Registry) (string, error) {
+			// Markers are replaced along with the section, so emit them again
+			return "# START\n" + r.GenerateDockerfileDocs(env.DockerfileDocsOptions{}) + "# END", nil
+		},
+	}}
+	os.WriteFile("Dockerfile", []byte("FROM scratch\n# START\n# END\n"), 0644)
+
+	if _, err := SyncRegistryWorkflow(RegistrySyncOptions{Registry: registry, DeploymentConfigs: configs}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := VerifyWorkflow(VerifyOptions{
+		Registry:          registry,
+		DeploymentConfigs: configs,
+		Environments:      []*env.Environment{},
+	})
+	if err != nil {
+		t.Fatalf("VerifyWorkflow failed: %v", err)
+	}
+	if result.HasErrors() {
+		t.Errorf("Expected pass, got errors: %v", result.Errors)
+	}
+}
+
+// Test VerifyWorkflow reports stale sections, drift, stale encryption and missing required vars
+func TestVerifyWorkflow_Failures(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "TEST_VAR", Description: "Test variable", Default: "test", Group: "Test"},
+		{Name: "VERIFY_TEST_REQUIRED", Description: "Required", Required: true, Group: "Test"},
+	})
+	configs := []DeploymentConfig{{
+		FilePath:    "Dockerfile",
+		StartMarker: "# START",
+		EndMarker:   "# END",
+		Generator:   func(r *env.Registry) (string, error) { return "ENV TEST_VAR=test", nil },
+	}}
+	os.WriteFile("Dockerfile", []byte("# START\n# END\n"), 0644)
+	os.WriteFile(env.Local.FileName, []byte("TEST_VAR=test\n"), 0600)
+
+	// Plaintext newer than its .age version
+	os.WriteFile(env.Local.EncryptedFileName(), []byte("old"), 0600)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(env.Local.EncryptedFileName(), past, past)
+
+	result, err := VerifyWorkflow(VerifyOptions{
+		Registry:          registry,
+		DeploymentConfigs: configs,
+		ValidateRequired:  true,
+	})
+	if err != nil {
+		t.Fatalf("VerifyWorkflow failed: %v", err)
+	}
+
+	// Stale Dockerfile, missing VERIFY_TEST_REQUIRED in .env.local, stale .age, unset required var
+	if len(result.Errors) != 4 {
+		t.Errorf("Expected 4 errors, got %d: %v", len(result.Errors), result.Errors)
+	}
+
+	// Each failure has its category; the lowest exit code wins
+	err = result.Err()
+	for _, category := range []error{env.ErrDriftDetected, env.ErrNotEncrypted, env.ErrMissingRequired} {
+		if !errors.Is(err, category) {
+			t.Errorf("Expected errors.Is(result.Err(), %v)", category)
+		}
+	}
+	if code := env.ExitCode(err); code != env.ExitMissingRequired {
+		t.Errorf("ExitCode = %d, want %d", code, env.ExitMissingRequired)
+	}
+}
+
+// Test VerifyWorkflow checks policies against each environment file
+func TestVerifyWorkflow_Policies(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "DEBUG", Default: "false", Group: "Test"},
+		{Name: "HTTPS_ENABLED", Default: "false", Group: "Test"},
+	}, env.Policies(
+		env.Policy{Environments: []string{"production"}, Variable: "DEBUG", Op: env.PolicyEquals, Value: "false"},
+		env.Policy{
+			Environments: []string{"production"}, Variable: "HTTPS_ENABLED", Op: env.PolicyEquals, Value: "true",
+			Unless: []env.Condition{{Key: env.ConditionPlatform, Value: "fly"}},
+		},
+	))
+	os.WriteFile(env.Local.FileName, []byte("DEBUG=true\nHTTPS_ENABLED=false\n"), 0600)
+	os.WriteFile(env.Production.FileName, []byte("DEBUG=true\nHTTPS_ENABLED=false\n"), 0600)
+
+	result, err := VerifyWorkflow(VerifyOptions{Registry: registry, Environments: []*env.Environment{}})
+	if err != nil {
+		t.Fatalf("VerifyWorkflow failed: %v", err)
+	}
+	// DEBUG and HTTPS_ENABLED in .env.production; .env.local is unconstrained
+	if len(result.Errors) != 2 {
+		t.Errorf("Expected 2 errors, got %d: %v", len(result.Errors), result.Errors)
+	}
+	if code := env.ExitCode(result.Err()); code != env.ExitPolicyViolation {
+		t.Errorf("ExitCode = %d, want %d", code, env.ExitPolicyViolation)
+	}
+
+	result, _ = VerifyWorkflow(VerifyOptions{Registry: registry, Environments: []*env.Environment{}, Platform: "fly.io"})
+	if len(result.Errors) != 1 {
+		t.Errorf("Expected only DEBUG on fly.io, got %d: %v", len(result.Errors), result.Errors)
+	}
+}
+
+// Test VerifyWorkflow rejects a nil registry
+func TestVerifyWorkflow_NilRegistry(t *testing.T) {
+	if _, err := VerifyWorkflow(VerifyOptions{}); err == nil {
+		t.Error("Expected error for nil registry")
+	}
+}
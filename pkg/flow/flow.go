@@ -0,0 +1,248 @@
+// Package flow runs workflow DAGs whose steps are described by JSON Schemas.
+//
+// Each step declares the JSON Schema of its input and (optionally) its output.
+// The schema package renders a step's input as a form, pre-filled with the
+// outputs of the steps it depends on, so a workflow's GUI is generated from its
+// declaration instead of hand-written pages:
+//
+//	f := flow.New("vehicle-transfer", "Vehicle transfer")
+//	f.AddStep(&flow.Step{ID: "buyer", Title: "Buyer details", Input: buyerSchema})
+//	f.AddStep(&flow.Step{ID: "fill", DependsOn: []string{"buyer"}, Input: fillSchema, Run: fillPDF})
+//
+// A Runner executes steps in dependency order, persisting each step's input and
+// output in a Store. Steps whose input is complete (from upstream outputs) run
+// straight away; the others wait for their form to be submitted.
+//
+// Plain Go functions become steps through a Registry, which reflects their
+// parameter and result types into schemas (schema.FromStruct):
+//
+//	reg := flow.NewRegistry()
+//	reg.MustRegister("pdf.fill", pdfform.Fill)
+//	step, _ := reg.Step("fill", "pdf.fill", "buyer")
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// StepFunc executes a step. It receives the validated input and returns the
+// step's output, which feeds the input of downstream steps.
+type StepFunc func(ctx context.Context, in Input) (map[string]interface{}, error)
+
+// Input is what a StepFunc receives
+type Input struct {
+	RunID    string
+	Data     map[string]interface{}            // Validated step input (upstream outputs + submitted form)
+	Upstream map[string]map[string]interface{} // Outputs of the steps this one depends on, by step ID
+}
+
+// Step is one node of a flow
+type Step struct {
+	ID          string
+	Title       string
+	Description string
+	DependsOn   []string         // IDs of steps that must complete first
+	Input       string           // JSON Schema of the step input ("" = no input)
+	Output      string           // JSON Schema of the step output ("" = not validated)
+	UI          *schema.UISchema // Form layout (nil = schema.DefaultLayout)
+	Run         StepFunc         // nil = the step just collects its input, which becomes its output
+
+	inputSchema  *jsonschema.Schema
+	outputSchema *jsonschema.Schema
+}
+
+// InputSchema returns the compiled input schema (nil if the step has no input)
+func (s *Step) InputSchema() *jsonschema.Schema {
+	return s.inputSchema
+}
+
+// OutputSchema returns the compiled output schema (nil if not declared)
+func (s *Step) OutputSchema() *jsonschema.Schema {
+	return s.outputSchema
+}
+
+// Flow is a named DAG of steps
+type Flow struct {
+	ID    string
+	Title string
+
+	steps     []*Step
+	byID      map[string]*Step
+	validator *schema.ValidatorV6
+}
+
+// New creates an empty flow
+func New(id, title string) *Flow {
+	return &Flow{
+		ID:        id,
+		Title:     title,
+		byID:      make(map[string]*Step),
+		validator: schema.NewValidatorV6(),
+	}
+}
+
+// AddStep adds a step and compiles its schemas. Dependencies may refer to
+// steps added later; Validate checks them once the flow is complete.
+func (f *Flow) AddStep(s *Step) error {
+	if s.ID == "" || strings.ContainsAny(s.ID, "/ ") {
+		return fmt.Errorf("invalid step ID %q", s.ID)
+	}
+	if _, exists := f.byID[s.ID]; exists {
+		return fmt.Errorf("duplicate step %q", s.ID)
+	}
+
+	var err error
+	if s.Input != "" {
+		if s.inputSchema, err = f.compile(s.ID, "input", s.Input); err != nil {
+			return err
+		}
+	}
+	if s.Output != "" {
+		if s.outputSchema, err = f.compile(s.ID, "output", s.Output); err != nil {
+			return err
+		}
+	}
+	if s.UI != nil && s.inputSchema != nil {
+		if err := s.UI.CheckScopes(s.inputSchema); err != nil {
+			return fmt.Errorf("step %s: %w", s.ID, err)
+		}
+	}
+
+	f.steps = append(f.steps, s)
+	f.byID[s.ID] = s
+	return nil
+}
+
+// MustAddStep is AddStep that panics on error, for package-level flows
+func (f *Flow) MustAddStep(s *Step) *Flow {
+	if err := f.AddStep(s); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func (f *Flow) compile(stepID, kind, schemaJSON string) (*jsonschema.Schema, error) {
+	if !json.Valid([]byte(schemaJSON)) {
+		return nil, fmt.Errorf("step %s: %s schema is not valid JSON", stepID, kind)
+	}
+	compiled, err := f.validator.CompileSchemaJSON(fmt.Sprintf("flow/%s/%s/%s", f.ID, stepID, kind), []byte(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("step %s: %w", stepID, err)
+	}
+	return compiled, nil
+}
+
+// Step returns the step with the given ID (nil if none)
+func (f *Flow) Step(id string) *Step {
+	return f.byID[id]
+}
+
+// Steps returns the steps in declaration order
+func (f *Flow) Steps() []*Step {
+	return f.steps
+}
+
+// Validate checks that every dependency exists and the steps form a DAG
+func (f *Flow) Validate() error {
+	if len(f.steps) == 0 {
+		return fmt.Errorf("flow %s has no steps", f.ID)
+	}
+	for _, s := range f.steps {
+		for _, dep := range s.DependsOn {
+			if _, ok := f.byID[dep]; !ok {
+				return fmt.Errorf("step %s depends on unknown step %q", s.ID, dep)
+			}
+		}
+	}
+	_, err := f.Order()
+	return err
+}
+
+// Order returns the steps in dependency order. Independent steps keep their
+// declaration order, so the result is stable.
+func (f *Flow) Order() ([]*Step, error) {
+	remaining := make(map[string]int, len(f.steps)) // Unfinished dependencies per step
+	for _, s := range f.steps {
+		remaining[s.ID] = len(s.DependsOn)
+	}
+
+	ordered := make([]*Step, 0, len(f.steps))
+	done := make(map[string]bool, len(f.steps))
+	for len(ordered) < len(f.steps) {
+		progressed := false
+		for _, s := range f.steps {
+			if done[s.ID] || remaining[s.ID] > 0 {
+				continue
+			}
+			done[s.ID] = true
+			ordered = append(ordered, s)
+			progressed = true
+			for _, other := range f.steps {
+				for _, dep := range other.DependsOn {
+					if dep == s.ID {
+						remaining[other.ID]--
+					}
+				}
+			}
+		}
+		if !progressed {
+			var cyclic []string
+			for _, s := range f.steps {
+				if !done[s.ID] {
+					cyclic = append(cyclic, s.ID)
+				}
+			}
+			return nil, fmt.Errorf("flow %s has a dependency cycle between steps: %s", f.ID, strings.Join(cyclic, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+// Definition is the serialisable description of a flow (everything except the
+// step functions), for storage and for clients that render their own forms
+type Definition struct {
+	ID    string           `json:"id"`
+	Title string           `json:"title"`
+	Steps []StepDefinition `json:"steps"`
+}
+
+// StepDefinition is the serialisable description of a step
+type StepDefinition struct {
+	ID          string           `json:"id"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	DependsOn   []string         `json:"depends_on,omitempty"`
+	Input       json.RawMessage  `json:"input,omitempty"`
+	Output      json.RawMessage  `json:"output,omitempty"`
+	UI          *schema.UISchema `json:"ui,omitempty"`
+	Executes    bool             `json:"executes"` // Has a StepFunc (false = form-only step)
+}
+
+// Definition describes f in declaration order
+func (f *Flow) Definition() Definition {
+	def := Definition{ID: f.ID, Title: f.Title, Steps: make([]StepDefinition, 0, len(f.steps))}
+	for _, s := range f.steps {
+		sd := StepDefinition{
+			ID:          s.ID,
+			Title:       s.Title,
+			Description: s.Description,
+			DependsOn:   s.DependsOn,
+			UI:          s.UI,
+			Executes:    s.Run != nil,
+		}
+		if s.Input != "" {
+			sd.Input = json.RawMessage(s.Input)
+		}
+		if s.Output != "" {
+			sd.Output = json.RawMessage(s.Output)
+		}
+		def.Steps = append(def.Steps, sd)
+	}
+	return def
+}
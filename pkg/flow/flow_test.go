@@ -0,0 +1,248 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const buyerSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"rego": {"type": "string"}
+	},
+	"required": ["name"]
+}`
+
+const fillSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string"},
+		"rego": {"type": "string"}
+	},
+	"required": ["name", "rego"]
+}`
+
+// newTestFlow builds buyer -> fill -> notify, where fill needs a rego the buyer form may omit
+func newTestFlow(t *testing.T) *Flow {
+	t.Helper()
+	f := New("transfer", "Vehicle transfer")
+	steps := []*Step{
+		{ID: "notify", DependsOn: []string{"fill"}, Run: func(ctx context.Context, in Input) (map[string]interface{}, error) {
+			return map[string]interface{}{"sent_to": in.Upstream["fill"]["owner"]}, nil
+		}},
+		{ID: "buyer", Title: "Buyer details", Input: buyerSchema},
+		{ID: "fill", DependsOn: []string{"buyer"}, Input: fillSchema, Output: `{"type":"object","required":["owner"]}`,
+			Run: func(ctx context.Context, in Input) (map[string]interface{}, error) {
+				return map[string]interface{}{"owner": in.Data["name"], "rego": in.Data["rego"]}, nil
+			}},
+	}
+	for _, s := range steps {
+		if err := f.AddStep(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f
+}
+
+func TestOrder(t *testing.T) {
+	f := newTestFlow(t)
+	order, err := f.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range order {
+		ids = append(ids, s.ID)
+	}
+	if got := strings.Join(ids, ","); got != "buyer,fill,notify" {
+		t.Errorf("order = %s, want buyer,fill,notify", got)
+	}
+}
+
+func TestDefinition(t *testing.T) {
+	def := newTestFlow(t).Definition()
+	if def.ID != "transfer" || len(def.Steps) != 3 {
+		t.Fatalf("unexpected definition: %+v", def)
+	}
+	data, err := json.Marshal(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Definition
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	buyer := decoded.Steps[1]
+	if buyer.ID != "buyer" || buyer.Executes || !strings.Contains(string(buyer.Input), `"minLength"`) {
+		t.Errorf("buyer step = %+v", buyer)
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	cyclic := New("cyclic", "")
+	cyclic.MustAddStep(&Step{ID: "a", DependsOn: []string{"b"}})
+	cyclic.MustAddStep(&Step{ID: "b", DependsOn: []string{"a"}})
+	if err := cyclic.Validate(); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+
+	unknown := New("unknown", "")
+	unknown.MustAddStep(&Step{ID: "a", DependsOn: []string{"missing"}})
+	if err := unknown.Validate(); err == nil || !strings.Contains(err.Error(), "unknown step") {
+		t.Errorf("expected unknown step error, got %v", err)
+	}
+
+	if err := New("bad", "").AddStep(&Step{ID: "a", Input: `{"type": 42}`}); err == nil {
+		t.Error("expected invalid schema error")
+	}
+}
+
+func TestRunner_FormsFeedDownstreamSteps(t *testing.T) {
+	ctx := context.Background()
+	runner, err := NewRunner(newTestFlow(t), NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := runner.Start(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Steps["buyer"].Status != StatusWaiting || run.Status != StatusWaiting {
+		t.Fatalf("entry step should wait for its form: %+v", run.Steps["buyer"])
+	}
+
+	// Invalid input keeps the step waiting with errors
+	run, err = runner.Submit(ctx, run.ID, "buyer", map[string]interface{}{"name": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Steps["buyer"].Status != StatusWaiting || run.Steps["buyer"].Errors["name"] == "" {
+		t.Fatalf("expected validation error on name, got %+v", run.Steps["buyer"])
+	}
+
+	// Without a rego, fill waits for its own form, pre-filled with the buyer's name
+	run, err = runner.Submit(ctx, run.ID, "buyer", map[string]interface{}{"name": "Jane Doe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Steps["buyer"].Status != StatusCompleted || run.Steps["fill"].Status != StatusWaiting {
+		t.Fatalf("unexpected statuses: buyer %s, fill %s", run.Steps["buyer"].Status, run.Steps["fill"].Status)
+	}
+	form, err := runner.RenderStepForm(run, "fill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(form), "Jane Doe") {
+		t.Error("fill form should be pre-filled from the buyer step output")
+	}
+
+	run, err = runner.Submit(ctx, run.ID, "fill", map[string]interface{}{"rego": "ABC123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != StatusCompleted {
+		t.Fatalf("run status = %s, want completed (%+v)", run.Status, run.Steps)
+	}
+	if got := run.Steps["notify"].Output["sent_to"]; got != "Jane Doe" {
+		t.Errorf("notify output sent_to = %v, want Jane Doe", got)
+	}
+
+	// State is persisted
+	stored, err := runner.Store.LoadRun(run.ID)
+	if err != nil || stored.Status != StatusCompleted {
+		t.Errorf("stored run = %+v, err %v", stored, err)
+	}
+}
+
+func TestRunner_StepFailure(t *testing.T) {
+	f := New("failing", "")
+	f.MustAddStep(&Step{ID: "boom", Run: func(ctx context.Context, in Input) (map[string]interface{}, error) {
+		return nil, errors.New("printer on fire")
+	}})
+	runner, err := NewRunner(f, NewFileStore(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := runner.Start(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != StatusFailed || run.Steps["boom"].Error != "printer on fire" {
+		t.Errorf("expected failed run, got %+v", run.Steps["boom"])
+	}
+
+	runs, err := runner.Store.ListRuns("failing")
+	if err != nil || len(runs) != 1 {
+		t.Errorf("ListRuns = %d runs, err %v", len(runs), err)
+	}
+}
+
+func TestHandler_SubmitStep(t *testing.T) {
+	runner, err := NewRunner(newTestFlow(t), NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	NewHandler(runner, "/flows/transfer").RegisterRoutes(mux)
+
+	// List page sets the session cookie and CSRF token
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/flows/transfer/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list: status %d", rec.Code)
+	}
+	cookie := rec.Result().Cookies()[0]
+	token := regexp.MustCompile(`name="_csrf" value="([^"]+)"`).FindStringSubmatch(rec.Body.String())
+	if token == nil {
+		t.Fatal("no CSRF token on list page")
+	}
+
+	post := func(path string, form url.Values) *httptest.ResponseRecorder {
+		form.Set("_csrf", token[1])
+		req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec = post("/flows/transfer/runs", url.Values{})
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("start: status %d", rec.Code)
+	}
+	runPath := rec.Header().Get("Location")
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", runPath, nil))
+	if !strings.Contains(rec.Body.String(), `name="name"`) {
+		t.Fatalf("run page should render the buyer form:\n%s", rec.Body.String())
+	}
+
+	rec = post(runPath+"/steps/buyer", url.Values{"name": {"Jane Doe"}, "rego": {"XYZ789"}})
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("submit: status %d: %s", rec.Code, rec.Body.String())
+	}
+	runID := runPath[strings.LastIndex(runPath, "/")+1:]
+	run, _ := runner.Store.LoadRun(runID)
+	if run.Status != StatusCompleted {
+		t.Errorf("run status = %s, want completed", run.Status)
+	}
+
+	// Missing CSRF token is rejected
+	req := httptest.NewRequest("POST", "/flows/transfer/runs", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("start without CSRF: status %d, want 403", rec.Code)
+	}
+}
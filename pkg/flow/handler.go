@@ -0,0 +1,156 @@
+package flow
+
+import (
+	"embed"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+var pageTemplate = template.Must(template.ParseFS(templatesFS, "templates/run.html"))
+
+// Handler serves a schema-generated GUI for one flow:
+//
+//	GET  {prefix}/                          past runs and a start button
+//	POST {prefix}/runs                      start a run
+//	GET  {prefix}/runs/{run}                step status and the next form
+//	POST {prefix}/runs/{run}/steps/{step}   submit a step's form
+type Handler struct {
+	Runner   *Runner
+	Prefix   string // Mount point, e.g. "/flows/vehicle-transfer"
+	sessions *schema.FormSessionManager
+}
+
+// NewHandler creates a GUI handler for runner mounted at prefix
+func NewHandler(runner *Runner, prefix string) *Handler {
+	return &Handler{
+		Runner:   runner,
+		Prefix:   strings.TrimSuffix(prefix, "/"),
+		sessions: schema.NewFormSessionManager(),
+	}
+}
+
+// RegisterRoutes registers the flow GUI on mux
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+h.Prefix+"/{$}", h.handleList)
+	mux.HandleFunc("POST "+h.Prefix+"/runs", h.handleStart)
+	mux.HandleFunc("GET "+h.Prefix+"/runs/{run}", h.handleRun)
+	mux.HandleFunc("POST "+h.Prefix+"/runs/{run}/steps/{step}", h.handleSubmit)
+}
+
+// stepView is one step as shown on the run page
+type stepView struct {
+	Step     *Step
+	Run      *StepRun
+	FormHTML template.HTML // Set for steps waiting on input
+}
+
+type pageData struct {
+	Prefix    string
+	Flow      *Flow
+	Runs      []*Run // List page
+	Run       *Run   // Run page
+	Steps     []stepView
+	CSRFField template.HTML
+}
+
+func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.Runner.Store.ListRuns(h.Runner.Flow.ID)
+	if err != nil {
+		http.Error(w, "Failed to list runs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.render(w, r, pageData{Runs: runs})
+}
+
+func (h *Handler) handleStart(w http.ResponseWriter, r *http.Request) {
+	if !h.verifyCSRF(w, r) {
+		return
+	}
+	run, err := h.Runner.Start(r.Context(), nil)
+	if err != nil {
+		http.Error(w, "Failed to start run: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, h.Prefix+"/runs/"+run.ID, http.StatusSeeOther)
+}
+
+func (h *Handler) handleRun(w http.ResponseWriter, r *http.Request) {
+	run, err := h.Runner.Store.LoadRun(r.PathValue("run"))
+	if errors.Is(err, ErrRunNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load run: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	order, err := h.Runner.Flow.Order()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var steps []stepView
+	for _, step := range order {
+		view := stepView{Step: step, Run: run.Steps[step.ID]}
+		if view.Run != nil && view.Run.Status == StatusWaiting {
+			if view.FormHTML, err = h.Runner.RenderStepForm(run, step.ID); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		steps = append(steps, view)
+	}
+	h.render(w, r, pageData{Run: run, Steps: steps})
+}
+
+func (h *Handler) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	if !h.verifyCSRF(w, r) {
+		return
+	}
+	runID, stepID := r.PathValue("run"), r.PathValue("step")
+
+	data := schema.FormDataToMap(r.PostForm)
+	if _, err := h.Runner.Submit(r.Context(), runID, stepID, data); err != nil {
+		if errors.Is(err, ErrRunNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("flow %s: submit %s/%s failed: %v", h.Runner.Flow.ID, runID, stepID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Validation errors are stored on the step and shown on the run page
+	http.Redirect(w, r, h.Prefix+"/runs/"+runID, http.StatusSeeOther)
+}
+
+func (h *Handler) verifyCSRF(w http.ResponseWriter, r *http.Request) bool {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return false
+	}
+	if _, err := h.sessions.VerifyCSRF(r); err != nil {
+		http.Error(w, "Form session expired or invalid - reload the page and try again", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, data pageData) {
+	data.Prefix = h.Prefix
+	data.Flow = h.Runner.Flow
+	data.CSRFField = h.sessions.Get(w, r).CSRFField()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		log.Printf("flow %s: render failed: %v", h.Runner.Flow.ID, err)
+	}
+}
@@ -0,0 +1,299 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// Func is a Go function usable as a step, with JSON Schemas reflected from its
+// parameter and return types (see NewFunc)
+type Func struct {
+	Name        string
+	Description string
+	Input       string // JSON Schema of the parameter ("" = no parameter)
+	Output      string // JSON Schema of the result, wrapped as {"result": ...} unless it is a struct or map
+	Run         StepFunc
+}
+
+// FuncOption customises a Func
+type FuncOption func(*Func)
+
+// WithFuncDescription sets the description shown on the step's form
+func WithFuncDescription(description string) FuncOption {
+	return func(f *Func) { f.Description = description }
+}
+
+// WithInputSchema replaces the reflected input schema, for functions that take
+// a map but have a schema.json describing it (e.g. google calendar.GenerateURL)
+func WithInputSchema(schemaJSON string) FuncOption {
+	return func(f *Func) { f.Input = schemaJSON }
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	mapType     = reflect.TypeOf(map[string]interface{}(nil))
+)
+
+// NewFunc wraps fn as a step function. Supported signatures are
+//
+//	func([ctx context.Context,] [in T]) (R, error)
+//	func([ctx context.Context,] [in T]) R
+//	func([ctx context.Context,] [in T]) error
+//
+// where T is a struct, a pointer to one, or map[string]interface{}. The step
+// input is decoded into T via encoding/json, so existing functions such as
+// pdfform.Fill(FillOptions) (*FillResult, error) need no wrapper.
+func NewFunc(name string, fn interface{}, opts ...FuncOption) (*Func, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil, fmt.Errorf("func %s: expected a function, got %T", name, fn)
+	}
+	t := v.Type()
+
+	// Parameters: optional context, then at most one input
+	params := make([]reflect.Type, 0, t.NumIn())
+	for i := 0; i < t.NumIn(); i++ {
+		params = append(params, t.In(i))
+	}
+	takesContext := len(params) > 0 && params[0] == contextType
+	if takesContext {
+		params = params[1:]
+	}
+	if len(params) > 1 || t.IsVariadic() {
+		return nil, fmt.Errorf("func %s: expected at most one parameter besides context, got %s", name, t)
+	}
+	var inType reflect.Type
+	if len(params) == 1 {
+		inType = params[0]
+		if !isObjectType(inType) {
+			return nil, fmt.Errorf("func %s: parameter must be a struct or map[string]interface{}, got %s", name, inType)
+		}
+	}
+
+	// Results: optional value, then optional error
+	returnsError := t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType
+	results := t.NumOut()
+	if returnsError {
+		results--
+	}
+	if results > 1 {
+		return nil, fmt.Errorf("func %s: expected at most one result besides error, got %s", name, t)
+	}
+	var outType reflect.Type
+	if results == 1 {
+		outType = t.Out(0)
+	}
+
+	f := &Func{Name: name}
+	var err error
+	if inType != nil {
+		if f.Input, err = reflectSchema(inType, false); err != nil {
+			return nil, fmt.Errorf("func %s: input: %w", name, err)
+		}
+	}
+	if outType != nil {
+		if f.Output, err = reflectSchema(outType, !isObjectType(outType)); err != nil {
+			return nil, fmt.Errorf("func %s: output: %w", name, err)
+		}
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	f.Run = func(ctx context.Context, in Input) (map[string]interface{}, error) {
+		args := make([]reflect.Value, 0, t.NumIn())
+		if takesContext {
+			args = append(args, reflect.ValueOf(ctx))
+		}
+		if inType != nil {
+			arg, err := decodeInput(in.Data, inType)
+			if err != nil {
+				return nil, fmt.Errorf("func %s: %w", name, err)
+			}
+			args = append(args, arg)
+		}
+
+		out := v.Call(args)
+		if returnsError {
+			if errVal := out[len(out)-1]; !errVal.IsNil() {
+				return nil, errVal.Interface().(error)
+			}
+		}
+		if outType == nil {
+			return map[string]interface{}{}, nil
+		}
+		return encodeOutput(out[0])
+	}
+	return f, nil
+}
+
+// Step returns a step running f. Title defaults to the function name.
+func (f *Func) Step(id string, dependsOn ...string) *Step {
+	return &Step{
+		ID:          id,
+		Title:       f.Name,
+		Description: f.Description,
+		DependsOn:   dependsOn,
+		Input:       f.Input,
+		Output:      f.Output,
+		Run:         f.Run,
+	}
+}
+
+// isObjectType reports whether values of t encode as JSON objects
+func isObjectType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return false // Encodes as a string
+	}
+	return t.Kind() == reflect.Struct || t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// reflectSchema returns t's JSON Schema, wrapped as {"result": ...} when wrap is set
+func reflectSchema(t reflect.Type, wrap bool) (string, error) {
+	data, err := schema.FromStruct(reflect.Zero(t).Interface())
+	if err != nil {
+		return "", err
+	}
+	if !wrap {
+		return string(data), nil
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", err
+	}
+	delete(result, "$schema")
+	wrapped, err := json.Marshal(map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"result": result},
+		"required":   []string{"result"},
+	})
+	return string(wrapped), err
+}
+
+// decodeInput converts step data into a value of type t
+func decodeInput(data map[string]interface{}, t reflect.Type) (reflect.Value, error) {
+	if t == mapType {
+		return reflect.ValueOf(data), nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to encode input: %w", err)
+	}
+	ptr := reflect.New(t)
+	if err := json.Unmarshal(raw, ptr.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to decode input into %s: %w", t, err)
+	}
+	return ptr.Elem(), nil
+}
+
+// encodeOutput converts a result into step output, dropping nulls so optional
+// fields left nil still validate against the reflected output schema
+func encodeOutput(v reflect.Value) (map[string]interface{}, error) {
+	if !isObjectType(v.Type()) {
+		return map[string]interface{}{"result": v.Interface()}, nil
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return map[string]interface{}{}, nil
+	}
+	raw, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode output: %w", err)
+	}
+	dropNulls(out)
+	return out, nil
+}
+
+func dropNulls(m map[string]interface{}) {
+	for k, v := range m {
+		switch val := v.(type) {
+		case nil:
+			delete(m, k)
+		case map[string]interface{}:
+			dropNulls(val)
+		case []interface{}:
+			for _, item := range val {
+				if obj, ok := item.(map[string]interface{}); ok {
+					dropNulls(obj)
+				}
+			}
+		}
+	}
+}
+
+// Registry is a named set of Funcs, so flows (and clients listing available
+// steps) can refer to functions by name
+type Registry struct {
+	mu    sync.RWMutex
+	funcs map[string]*Func
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{funcs: make(map[string]*Func)}
+}
+
+// Register reflects fn (see NewFunc) and adds it under name
+func (r *Registry) Register(name string, fn interface{}, opts ...FuncOption) error {
+	f, err := NewFunc(name, fn, opts...)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.funcs[name]; exists {
+		return fmt.Errorf("func %s already registered", name)
+	}
+	r.funcs[name] = f
+	return nil
+}
+
+// MustRegister is Register that panics on error, for package-level registries
+func (r *Registry) MustRegister(name string, fn interface{}, opts ...FuncOption) *Registry {
+	if err := r.Register(name, fn, opts...); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Get returns the func registered under name (nil if none)
+func (r *Registry) Get(name string) *Func {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.funcs[name]
+}
+
+// Funcs returns the registered funcs sorted by name
+func (r *Registry) Funcs() []*Func {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	funcs := make([]*Func, 0, len(r.funcs))
+	for _, f := range r.funcs {
+		funcs = append(funcs, f)
+	}
+	sort.Slice(funcs, func(i, j int) bool { return funcs[i].Name < funcs[j].Name })
+	return funcs
+}
+
+// Step returns a step with the given ID running the func registered under name
+func (r *Registry) Step(id, name string, dependsOn ...string) (*Step, error) {
+	f := r.Get(name)
+	if f == nil {
+		return nil, fmt.Errorf("func %s not registered", name)
+	}
+	return f.Step(id, dependsOn...), nil
+}
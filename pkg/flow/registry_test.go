@@ -0,0 +1,123 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type quoteRequest struct {
+	Rego   string    `json:"rego" jsonschema:"title=Registration"`
+	State  string    `json:"state" jsonschema:"enum=NSW|VIC|QLD"`
+	Weight int       `json:"weight,omitempty"`
+	Due    time.Time `json:"due,omitempty"`
+	Notes  *string   `json:"notes"`
+}
+
+type quote struct {
+	Fee     float64 `json:"fee"`
+	Receipt *string `json:"receipt"` // nil: dropped from the output
+}
+
+func TestNewFunc_ReflectsSchemas(t *testing.T) {
+	f, err := NewFunc("quote", func(ctx context.Context, req quoteRequest) (*quote, error) {
+		if req.State != "NSW" {
+			return nil, errors.New("unsupported state")
+		}
+		return &quote{Fee: 35.5}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var input struct {
+		Required   []string                          `json:"required"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(f.Input), &input); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(input.Required, ","); got != "rego,state" {
+		t.Errorf("required = %s, want rego,state (omitempty and pointers are optional)", got)
+	}
+	if input.Properties["rego"]["title"] != "Registration" || input.Properties["due"]["format"] != "date-time" {
+		t.Errorf("unexpected properties: %+v", input.Properties)
+	}
+
+	fl := New("quote", "")
+	fl.MustAddStep(f.Step("quote"))
+	runner, err := NewRunner(fl, NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := runner.Start(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The enum is enforced before the function runs
+	run, _ = runner.Submit(context.Background(), run.ID, "quote", map[string]interface{}{"rego": "ABC123", "state": "TAS"})
+	if run.Steps["quote"].Errors["state"] == "" {
+		t.Fatalf("expected enum error, got %+v", run.Steps["quote"])
+	}
+
+	run, err = runner.Submit(context.Background(), run.ID, "quote", map[string]interface{}{"rego": "ABC123", "state": "NSW"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != StatusCompleted || run.Steps["quote"].Output["fee"] != 35.5 {
+		t.Errorf("run %s, step %+v", run.Status, run.Steps["quote"])
+	}
+}
+
+func TestNewFunc_WrapsScalarResults(t *testing.T) {
+	f, err := NewFunc("link", func(data map[string]interface{}) (string, error) {
+		return "https://example.com/" + data["id"].(string), nil
+	}, WithInputSchema(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(f.Output, `"result"`) {
+		t.Errorf("output schema should wrap the result: %s", f.Output)
+	}
+	out, err := f.Run(context.Background(), Input{Data: map[string]interface{}{"id": "42"}})
+	if err != nil || out["result"] != "https://example.com/42" {
+		t.Errorf("out = %+v, err %v", out, err)
+	}
+}
+
+func TestNewFunc_RejectsUnsupportedSignatures(t *testing.T) {
+	for name, fn := range map[string]interface{}{
+		"not a func":    42,
+		"scalar param":  func(s string) error { return nil },
+		"two params":    func(a, b quoteRequest) error { return nil },
+		"two results":   func() (int, int) { return 0, 0 },
+		"variadic args": func(in ...quoteRequest) error { return nil },
+	} {
+		if _, err := NewFunc(name, fn); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	reg.MustRegister("b", func() error { return nil })
+	reg.MustRegister("a", func(ctx context.Context) (quote, error) { return quote{}, nil })
+	if err := reg.Register("a", func() error { return nil }); err == nil {
+		t.Error("expected duplicate error")
+	}
+	if funcs := reg.Funcs(); len(funcs) != 2 || funcs[0].Name != "a" {
+		t.Errorf("Funcs() not sorted by name: %+v", funcs)
+	}
+	if _, err := reg.Step("x", "missing"); err == nil {
+		t.Error("expected error for unregistered func")
+	}
+	step, err := reg.Step("first", "a", "zero")
+	if err != nil || step.Input != "" || step.DependsOn[0] != "zero" {
+		t.Errorf("step = %+v, err %v", step, err)
+	}
+}
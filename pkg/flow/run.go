@@ -0,0 +1,211 @@
+package flow
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// Status is the state of a run or one of its steps
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // Dependencies not finished yet
+	StatusWaiting   Status = "waiting"   // Needs (valid) form input
+	StatusRunning   Status = "running"   // StepFunc executing
+	StatusCompleted Status = "completed" // Output recorded
+	StatusFailed    Status = "failed"    // StepFunc or output validation failed
+)
+
+// ErrRunNotFound is returned by a Store for an unknown run ID
+var ErrRunNotFound = errors.New("flow run not found")
+
+// StepRun is the persisted state of one step in a run
+type StepRun struct {
+	Status     Status                  `json:"status"`
+	Input      map[string]interface{}  `json:"input,omitempty"`  // Submitted form data
+	Output     map[string]interface{}  `json:"output,omitempty"` // StepFunc result
+	Errors     schema.ValidationErrors `json:"errors,omitempty"` // Input validation errors
+	Error      string                  `json:"error,omitempty"`  // Execution error
+	StartedAt  *time.Time              `json:"started_at,omitempty"`
+	FinishedAt *time.Time              `json:"finished_at,omitempty"`
+}
+
+// Run is one execution of a flow
+type Run struct {
+	ID        string              `json:"id"`
+	FlowID    string              `json:"flow_id"`
+	Status    Status              `json:"status"`
+	Steps     map[string]*StepRun `json:"steps"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// newRun creates a run with every step pending
+func newRun(f *Flow) *Run {
+	now := time.Now()
+	run := &Run{
+		ID:        newRunID(),
+		FlowID:    f.ID,
+		Status:    StatusPending,
+		Steps:     make(map[string]*StepRun, len(f.steps)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, s := range f.steps {
+		run.Steps[s.ID] = &StepRun{Status: StatusPending}
+	}
+	return run
+}
+
+func newRunID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// ================================================================
+// Stores
+// ================================================================
+
+// Store persists runs
+type Store interface {
+	SaveRun(run *Run) error
+	LoadRun(id string) (*Run, error)
+	ListRuns(flowID string) ([]*Run, error) // Newest first
+}
+
+// MemoryStore keeps runs in memory (tests and demos)
+type MemoryStore struct {
+	mu   sync.Mutex
+	runs map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{runs: make(map[string][]byte)}
+}
+
+// SaveRun stores a copy of run
+func (s *MemoryStore) SaveRun(run *Run) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[run.ID] = data
+	return nil
+}
+
+// LoadRun returns a copy of the stored run
+func (s *MemoryStore) LoadRun(id string) (*Run, error) {
+	s.mu.Lock()
+	data, ok := s.runs[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrRunNotFound
+	}
+	return decodeRun(data)
+}
+
+// ListRuns returns the runs of flowID, newest first
+func (s *MemoryStore) ListRuns(flowID string) ([]*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var runs []*Run
+	for _, data := range s.runs {
+		run, err := decodeRun(data)
+		if err != nil {
+			return nil, err
+		}
+		if run.FlowID == flowID {
+			runs = append(runs, run)
+		}
+	}
+	sortRuns(runs)
+	return runs, nil
+}
+
+// FileStore keeps each run as <dir>/<flow-id>/<run-id>.json
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore creates a store rooted at dir
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// SaveRun writes run to disk
+func (s *FileStore) SaveRun(run *Run) error {
+	dir := filepath.Join(s.Dir, run.FlowID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run: %w", err)
+	}
+	path := filepath.Join(dir, run.ID+".json")
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write run: %w", err)
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// LoadRun reads a run from disk
+func (s *FileStore) LoadRun(id string) (*Run, error) {
+	matches, err := filepath.Glob(filepath.Join(s.Dir, "*", id+".json"))
+	if err != nil || len(matches) == 0 {
+		return nil, ErrRunNotFound
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run: %w", err)
+	}
+	return decodeRun(data)
+}
+
+// ListRuns returns the runs of flowID, newest first
+func (s *FileStore) ListRuns(flowID string) ([]*Run, error) {
+	matches, err := filepath.Glob(filepath.Join(s.Dir, flowID, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]*Run, 0, len(matches))
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read run: %w", err)
+		}
+		run, err := decodeRun(data)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	sortRuns(runs)
+	return runs, nil
+}
+
+func decodeRun(data []byte) (*Run, error) {
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse run: %w", err)
+	}
+	return &run, nil
+}
+
+func sortRuns(runs []*Run) {
+	sort.Slice(runs, func(i, j int) bool { return runs[i].CreatedAt.After(runs[j].CreatedAt) })
+}
@@ -0,0 +1,261 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// Runner executes a flow's steps in dependency order, persisting each change
+type Runner struct {
+	Flow  *Flow
+	Store Store
+
+	mu sync.Mutex // Serialises updates so concurrent submits don't lose step results
+}
+
+// NewRunner validates f and returns a runner backed by store
+func NewRunner(f *Flow, store Store) (*Runner, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return &Runner{Flow: f, Store: store}, nil
+}
+
+// Start creates a run, seeding step forms from inputs (step ID -> data, may be
+// nil), and executes every step whose input is already complete
+func (r *Runner) Start(ctx context.Context, inputs map[string]map[string]interface{}) (*Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run := newRun(r.Flow)
+	for stepID, data := range inputs {
+		sr, ok := run.Steps[stepID]
+		if !ok {
+			return nil, fmt.Errorf("unknown step %q", stepID)
+		}
+		sr.Input = data
+	}
+	if err := r.Store.SaveRun(run); err != nil {
+		return nil, err
+	}
+	return run, r.advance(ctx, run)
+}
+
+// Submit records form data for a step and resumes the run. Invalid input
+// leaves the step waiting with its validation errors in StepRun.Errors.
+func (r *Runner) Submit(ctx context.Context, runID, stepID string, data map[string]interface{}) (*Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, err := r.Store.LoadRun(runID)
+	if err != nil {
+		return nil, err
+	}
+	sr, ok := run.Steps[stepID]
+	if !ok {
+		return nil, fmt.Errorf("unknown step %q", stepID)
+	}
+	if sr.Status == StatusCompleted || sr.Status == StatusRunning {
+		return nil, fmt.Errorf("step %s is already %s", stepID, sr.Status)
+	}
+
+	sr.Input = data
+	if sr.Status == StatusFailed {
+		sr.Status, sr.Error = StatusWaiting, "" // Retry with the new input
+	}
+	return run, r.advance(ctx, run)
+}
+
+// Resume re-evaluates a run (e.g. after a restart) and executes any steps that are ready
+func (r *Runner) Resume(ctx context.Context, runID string) (*Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, err := r.Store.LoadRun(runID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sr := range run.Steps {
+		if sr.Status == StatusRunning {
+			sr.Status = StatusPending // Interrupted mid-step: run it again
+		}
+	}
+	return run, r.advance(ctx, run)
+}
+
+// advance runs every ready step in dependency order, then saves the run
+func (r *Runner) advance(ctx context.Context, run *Run) error {
+	order, err := r.Flow.Order()
+	if err != nil {
+		return err
+	}
+
+	for _, step := range order {
+		sr := run.Steps[step.ID]
+		if sr == nil {
+			sr = &StepRun{Status: StatusPending} // Step added to the flow after the run started
+			run.Steps[step.ID] = sr
+		}
+		if sr.Status != StatusPending && sr.Status != StatusWaiting {
+			continue
+		}
+		if !r.depsCompleted(run, step) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Entry steps always show their form; downstream steps run as soon as
+		// upstream outputs satisfy their input schema
+		if step.inputSchema != nil && sr.Input == nil && len(step.DependsOn) == 0 {
+			sr.Status = StatusWaiting
+			continue
+		}
+
+		data := r.stepData(run, step)
+		if step.inputSchema != nil {
+			if errs := r.Flow.validator.Validate(data, step.inputSchema); len(errs) > 0 {
+				sr.Status = StatusWaiting
+				sr.Errors = nil
+				if sr.Input != nil {
+					sr.Errors = errs // Only report errors once the user has submitted something
+				}
+				continue
+			}
+		}
+		sr.Errors = nil
+
+		if err := r.execute(ctx, run, step, sr, data); err != nil {
+			return err
+		}
+	}
+
+	run.Status = runStatus(run)
+	run.UpdatedAt = time.Now()
+	return r.Store.SaveRun(run)
+}
+
+// execute runs one step and records its output or error
+func (r *Runner) execute(ctx context.Context, run *Run, step *Step, sr *StepRun, data map[string]interface{}) error {
+	started := time.Now()
+	sr.Status, sr.StartedAt, sr.FinishedAt, sr.Error = StatusRunning, &started, nil, ""
+	run.Status, run.UpdatedAt = StatusRunning, started
+	if err := r.Store.SaveRun(run); err != nil {
+		return err
+	}
+
+	output := data
+	var err error
+	if step.Run != nil {
+		output, err = step.Run(ctx, Input{RunID: run.ID, Data: data, Upstream: r.upstream(run, step)})
+	}
+	if err == nil && step.outputSchema != nil {
+		if errs := r.Flow.validator.Validate(output, step.outputSchema); len(errs) > 0 {
+			err = fmt.Errorf("output does not match schema: %v", errs)
+		}
+	}
+
+	finished := time.Now()
+	sr.FinishedAt = &finished
+	if err != nil {
+		sr.Status, sr.Error = StatusFailed, err.Error()
+		return nil
+	}
+	sr.Status, sr.Output = StatusCompleted, output
+	return nil
+}
+
+func (r *Runner) depsCompleted(run *Run, step *Step) bool {
+	for _, dep := range step.DependsOn {
+		if sr := run.Steps[dep]; sr == nil || sr.Status != StatusCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Runner) upstream(run *Run, step *Step) map[string]map[string]interface{} {
+	upstream := make(map[string]map[string]interface{}, len(step.DependsOn))
+	for _, dep := range step.DependsOn {
+		if sr := run.Steps[dep]; sr != nil {
+			upstream[dep] = sr.Output
+		}
+	}
+	return upstream
+}
+
+// stepData builds a step's input: properties of its input schema found in
+// upstream outputs (later dependencies win), overlaid with the submitted form
+func (r *Runner) stepData(run *Run, step *Step) map[string]interface{} {
+	data := make(map[string]interface{})
+	if step.inputSchema != nil {
+		for _, dep := range step.DependsOn {
+			sr := run.Steps[dep]
+			if sr == nil {
+				continue
+			}
+			for name := range step.inputSchema.Properties {
+				if value, ok := sr.Output[name]; ok {
+					data[name] = value
+				}
+			}
+		}
+	}
+	if sr := run.Steps[step.ID]; sr != nil {
+		for name, value := range sr.Input {
+			data[name] = value
+		}
+	}
+	return data
+}
+
+// runStatus derives a run's status from its steps
+func runStatus(run *Run) Status {
+	completed, waiting := 0, false
+	for _, sr := range run.Steps {
+		switch sr.Status {
+		case StatusFailed:
+			return StatusFailed
+		case StatusCompleted:
+			completed++
+		case StatusWaiting:
+			waiting = true
+		}
+	}
+	switch {
+	case completed == len(run.Steps):
+		return StatusCompleted
+	case waiting:
+		return StatusWaiting
+	default:
+		return StatusPending
+	}
+}
+
+// RenderStepForm renders a step's input form, pre-filled from upstream outputs
+// and earlier submissions, with any validation errors
+func (r *Runner) RenderStepForm(run *Run, stepID string) (template.HTML, error) {
+	step := r.Flow.Step(stepID)
+	if step == nil {
+		return "", fmt.Errorf("unknown step %q", stepID)
+	}
+	if step.inputSchema == nil {
+		return "", nil
+	}
+
+	ui := step.UI
+	if ui == nil {
+		ui = schema.DefaultLayout(step.inputSchema)
+	}
+	var errs schema.ValidationErrors
+	if sr := run.Steps[stepID]; sr != nil {
+		errs = sr.Errors
+	}
+	return ui.GenerateFormHTMLWithData(step.inputSchema, r.stepData(run, step), errs), nil
+}
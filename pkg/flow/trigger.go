@@ -0,0 +1,176 @@
+package flow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// TriggerKind is how a trigger starts runs
+type TriggerKind string
+
+const (
+	TriggerWebhook TriggerKind = "webhook" // HTTP POST signed with the trigger's secret
+	TriggerCron    TriggerKind = "cron"    // Cron schedule, e.g. "0 2 * * *" (nightly at 2 AM)
+)
+
+// SignatureHeader carries a webhook payload's HMAC-SHA256, as "sha256=<hex>"
+const SignatureHeader = "X-Wellknown-Signature"
+
+// MaxWebhookBody caps webhook payloads (1MB)
+const MaxWebhookBody = 1 << 20
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrTriggerDisabled  = errors.New("trigger is disabled")
+)
+
+// Trigger starts runs of a flow without anyone opening its GUI
+type Trigger struct {
+	ID       string                            `json:"id"`
+	FlowID   string                            `json:"flow_id"`
+	Kind     TriggerKind                       `json:"kind"`
+	Schedule string                            `json:"schedule,omitempty"` // Cron expression (cron triggers)
+	Secret   string                            `json:"-"`                  // HMAC key (webhook triggers)
+	Step     string                            `json:"step,omitempty"`     // Step that receives the webhook payload as its input
+	Inputs   map[string]map[string]interface{} `json:"inputs,omitempty"`   // Fixed step inputs (step ID -> data)
+	Enabled  bool                              `json:"enabled"`
+}
+
+// Validate checks the trigger against the flow it starts. Cron expressions are
+// checked by the scheduler that runs them.
+func (t *Trigger) Validate(f *Flow) error {
+	if t.FlowID != f.ID {
+		return fmt.Errorf("trigger %s is for flow %s, not %s", t.ID, t.FlowID, f.ID)
+	}
+	switch t.Kind {
+	case TriggerWebhook:
+		if t.Secret == "" {
+			return fmt.Errorf("webhook trigger %s needs a secret", t.ID)
+		}
+	case TriggerCron:
+		if t.Schedule == "" {
+			return fmt.Errorf("cron trigger %s needs a schedule", t.ID)
+		}
+	default:
+		return fmt.Errorf("trigger %s has unknown kind %q", t.ID, t.Kind)
+	}
+	if t.Step != "" && f.Step(t.Step) == nil {
+		return fmt.Errorf("trigger %s targets unknown step %q", t.ID, t.Step)
+	}
+	for stepID := range t.Inputs {
+		if f.Step(stepID) == nil {
+			return fmt.Errorf("trigger %s has input for unknown step %q", t.ID, stepID)
+		}
+	}
+	return nil
+}
+
+// Fire starts a run from the trigger. payload (may be nil) is merged over the
+// fixed inputs of t.Step.
+func (r *Runner) Fire(ctx context.Context, t *Trigger, payload map[string]interface{}) (*Run, error) {
+	if !t.Enabled {
+		return nil, ErrTriggerDisabled
+	}
+	if err := t.Validate(r.Flow); err != nil {
+		return nil, err
+	}
+
+	inputs := make(map[string]map[string]interface{}, len(t.Inputs)+1)
+	for stepID, data := range t.Inputs {
+		copied := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			copied[k] = v
+		}
+		inputs[stepID] = copied
+	}
+	if t.Step != "" && len(payload) > 0 {
+		if inputs[t.Step] == nil {
+			inputs[t.Step] = make(map[string]interface{}, len(payload))
+		}
+		for k, v := range payload {
+			inputs[t.Step][k] = v
+		}
+	}
+	return r.Start(ctx, inputs)
+}
+
+// Sign returns the SignatureHeader value for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a SignatureHeader value against body in constant time
+func VerifySignature(secret string, body []byte, signature string) error {
+	hexSum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ServeWebhook verifies a signed webhook request for t and starts a run with
+// its JSON body (an object, or empty) as the payload. It responds 202 with the
+// run, 401 on a bad signature and 404 when the trigger is disabled.
+func (r *Runner) ServeWebhook(w http.ResponseWriter, req *http.Request, t *Trigger) {
+	if t.Kind != TriggerWebhook {
+		http.Error(w, "Not a webhook trigger", http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, MaxWebhookBody+1))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > MaxWebhookBody {
+		http.Error(w, "Payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := VerifySignature(t.Secret, body, req.Header.Get(SignatureHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var payload map[string]interface{}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "Payload must be a JSON object", http.StatusBadRequest)
+			return
+		}
+	}
+
+	run, err := r.Fire(req.Context(), t, payload)
+	switch {
+	case errors.Is(err, ErrTriggerDisabled):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil && run == nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// A failed step is recorded on the run; the webhook itself was accepted
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(run)
+}
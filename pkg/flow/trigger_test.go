@@ -0,0 +1,105 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"name":"Jane Doe"}`)
+	sig := Sign("s3cret", body)
+	if err := VerifySignature("s3cret", body, sig); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	for name, bad := range map[string]string{
+		"wrong secret": Sign("other", body),
+		"no prefix":    strings.TrimPrefix(sig, "sha256="),
+		"not hex":      "sha256=zz",
+		"empty":        "",
+	} {
+		if err := VerifySignature("s3cret", body, bad); err != ErrInvalidSignature {
+			t.Errorf("%s: got %v, want ErrInvalidSignature", name, err)
+		}
+	}
+}
+
+func TestTrigger_Validate(t *testing.T) {
+	f := newTestFlow(t)
+	for name, tr := range map[string]*Trigger{
+		"other flow":     {ID: "t", FlowID: "other", Kind: TriggerCron, Schedule: "* * * * *"},
+		"no secret":      {ID: "t", FlowID: "transfer", Kind: TriggerWebhook},
+		"no schedule":    {ID: "t", FlowID: "transfer", Kind: TriggerCron},
+		"unknown kind":   {ID: "t", FlowID: "transfer", Kind: "email"},
+		"unknown step":   {ID: "t", FlowID: "transfer", Kind: TriggerWebhook, Secret: "x", Step: "missing"},
+		"unknown inputs": {ID: "t", FlowID: "transfer", Kind: TriggerCron, Schedule: "@daily", Inputs: map[string]map[string]interface{}{"missing": {}}},
+	} {
+		if err := tr.Validate(f); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestRunner_ServeWebhook(t *testing.T) {
+	runner, err := NewRunner(newTestFlow(t), NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger := &Trigger{
+		ID: "intake", FlowID: "transfer", Kind: TriggerWebhook, Secret: "s3cret", Step: "buyer", Enabled: true,
+		Inputs: map[string]map[string]interface{}{"buyer": {"rego": "ABC123"}},
+	}
+
+	post := func(body, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/hooks/intake", strings.NewReader(body))
+		req.Header.Set(SignatureHeader, signature)
+		rec := httptest.NewRecorder()
+		runner.ServeWebhook(rec, req, trigger)
+		return rec
+	}
+
+	body := `{"name":"Jane Doe"}`
+	if rec := post(body, Sign("wrong", []byte(body))); rec.Code != http.StatusUnauthorized {
+		t.Errorf("bad signature: status %d, want 401", rec.Code)
+	}
+
+	// The payload merges with the trigger's fixed inputs, so every step runs
+	rec := post(body, Sign("s3cret", []byte(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var run Run
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != StatusCompleted || run.Steps["notify"].Output["sent_to"] != "Jane Doe" {
+		t.Errorf("run %s, steps %+v", run.Status, run.Steps)
+	}
+	if trigger.Inputs["buyer"]["name"] != nil {
+		t.Error("payload must not leak into the trigger's fixed inputs")
+	}
+
+	trigger.Enabled = false
+	if rec := post(body, Sign("s3cret", []byte(body))); rec.Code != http.StatusNotFound {
+		t.Errorf("disabled trigger: status %d, want 404", rec.Code)
+	}
+}
+
+func TestRunner_FireCron(t *testing.T) {
+	runner, err := NewRunner(newTestFlow(t), NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := runner.Fire(context.Background(), &Trigger{
+		ID: "nightly", FlowID: "transfer", Kind: TriggerCron, Schedule: "0 2 * * *", Enabled: true,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Steps["buyer"].Status != StatusWaiting {
+		t.Errorf("without inputs the entry step waits for its form, got %s", run.Steps["buyer"].Status)
+	}
+}
@@ -0,0 +1,159 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+
+	gcal "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// ================================================================
+// Calendar API Client Mode
+// ================================================================
+// GenerateURL produces a deep link the user must open and save; CalendarAPI
+// creates the event server-side from the same validated data, using an
+// OAuth-authorized HTTP client (the pb app's stored Google tokens). Unlike the
+// URL, the API also applies APIOnlyFields (reminders, video conference).
+
+// Calendar API defaults
+const (
+	DefaultCalendarID = "primary"
+
+	// VideoConferenceNone and VideoConferenceGoogleMeet are the videoConference values
+	VideoConferenceNone       = "none"
+	VideoConferenceGoogleMeet = "googleMeet"
+)
+
+// CalendarAPI creates events through the Google Calendar API
+type CalendarAPI struct {
+	Client      *http.Client // OAuth-authorized client with the calendar.events scope
+	CalendarID  string       // Target calendar (default: DefaultCalendarID)
+	TimeZone    string       // IANA zone for datetime-local values (default: UTC, like GenerateURL)
+	SendUpdates string       // Invitation emails: "all", "externalOnly" or "none" (default: "all")
+	Endpoint    string       // API base URL override (for tests)
+}
+
+// NewCalendarAPI creates a client for the primary calendar
+//
+// Example (pb app):
+//
+//	client := oauthConfig.Client(ctx, token)
+//	event, err := calendar.NewCalendarAPI(client).CreateEvent(ctx, data)
+func NewCalendarAPI(client *http.Client) *CalendarAPI {
+	return &CalendarAPI{Client: client}
+}
+
+// BuildEvent converts validated form data (schema.json) into a Calendar API event
+func (a *CalendarAPI) BuildEvent(data map[string]interface{}) (*gcal.Event, error) {
+	title, startTime, endTime, err := requiredEventFields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &gcal.Event{
+		Summary: title,
+		Start:   a.eventDateTime(startTime),
+		End:     a.eventDateTime(endTime),
+	}
+	if location, ok := data[FieldLocation].(string); ok {
+		event.Location = location
+	}
+	if description, ok := data[FieldDescription].(string); ok {
+		event.Description = description
+	}
+
+	for _, email := range stringList(data[FieldAttendees]) {
+		event.Attendees = append(event.Attendees, &gcal.EventAttendee{Email: email})
+	}
+
+	if reminders, ok := data[FieldReminders].([]interface{}); ok && len(reminders) > 0 {
+		event.Reminders = &gcal.EventReminders{ForceSendFields: []string{"UseDefault"}}
+		for _, raw := range reminders {
+			reminder, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			minutes, ok := reminder["minutesBefore"].(float64)
+			if !ok {
+				return nil, fmt.Errorf("reminder is missing minutesBefore")
+			}
+			method, _ := reminder["method"].(string)
+			if method == "" {
+				method = "popup"
+			}
+			event.Reminders.Overrides = append(event.Reminders.Overrides, &gcal.EventReminder{
+				Method:          method,
+				Minutes:         int64(minutes),
+				ForceSendFields: []string{"Minutes"}, // 0 = at start time
+			})
+		}
+	}
+
+	switch conference, _ := data[FieldVideoConference].(string); conference {
+	case "", VideoConferenceNone:
+	case VideoConferenceGoogleMeet:
+		event.ConferenceData = &gcal.ConferenceData{
+			CreateRequest: &gcal.CreateConferenceRequest{
+				RequestId:             rand.Text(),
+				ConferenceSolutionKey: &gcal.ConferenceSolutionKey{Type: "hangoutsMeet"},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported video conference %q", conference)
+	}
+
+	return event, nil
+}
+
+// CreateEvent creates the event and returns it as stored by Google
+// (including HtmlLink and, for Google Meet, HangoutLink)
+func (a *CalendarAPI) CreateEvent(ctx context.Context, data map[string]interface{}) (*gcal.Event, error) {
+	if a.Client == nil {
+		return nil, fmt.Errorf("calendar API client is required")
+	}
+	event, err := a.BuildEvent(data)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []option.ClientOption{option.WithHTTPClient(a.Client)}
+	if a.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(a.Endpoint))
+	}
+	srv, err := gcal.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Calendar service: %w", err)
+	}
+
+	calendarID := a.CalendarID
+	if calendarID == "" {
+		calendarID = DefaultCalendarID
+	}
+	sendUpdates := a.SendUpdates
+	if sendUpdates == "" {
+		sendUpdates = "all"
+	}
+
+	call := srv.Events.Insert(calendarID, event).SendUpdates(sendUpdates).Context(ctx)
+	if event.ConferenceData != nil {
+		call = call.ConferenceDataVersion(1)
+	}
+	created, err := call.Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+	return created, nil
+}
+
+// eventDateTime formats t for the API. datetime-local values carry no zone, so
+// they are sent as wall-clock time in TimeZone, or as UTC when none is set.
+func (a *CalendarAPI) eventDateTime(t time.Time) *gcal.EventDateTime {
+	if a.TimeZone != "" && t.Location() == time.UTC {
+		return &gcal.EventDateTime{DateTime: t.Format("2006-01-02T15:04:05"), TimeZone: a.TimeZone}
+	}
+	return &gcal.EventDateTime{DateTime: t.Format(time.RFC3339)}
+}
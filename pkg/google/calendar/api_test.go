@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCalendarAPI_CreateEvent creates an event against a fake Calendar API
+func TestCalendarAPI_CreateEvent(t *testing.T) {
+	var got map[string]interface{}
+	var query map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/calendars/primary/events" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query = r.URL.Query()
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"evt1","htmlLink":"https://calendar.google.com/event?eid=evt1","hangoutLink":"https://meet.google.com/abc"}`))
+	}))
+	defer srv.Close()
+
+	api := &CalendarAPI{Client: srv.Client(), Endpoint: srv.URL + "/", TimeZone: "Australia/Sydney"}
+	event, err := api.CreateEvent(context.Background(), map[string]interface{}{
+		"title":           "Daily Standup",
+		"start":           "2025-12-02T09:00",
+		"end":             "2025-12-02T09:15",
+		"attendees":       []interface{}{"team@example.com"},
+		"reminders":       []interface{}{map[string]interface{}{"minutesBefore": float64(0)}},
+		"videoConference": VideoConferenceGoogleMeet,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	if event.Id != "evt1" || event.HangoutLink == "" {
+		t.Errorf("unexpected created event: %+v", event)
+	}
+
+	if got["summary"] != "Daily Standup" {
+		t.Errorf("summary = %v", got["summary"])
+	}
+	start, _ := got["start"].(map[string]interface{})
+	if start["dateTime"] != "2025-12-02T09:00:00" || start["timeZone"] != "Australia/Sydney" {
+		t.Errorf("start = %v", start)
+	}
+	if attendees, _ := got["attendees"].([]interface{}); len(attendees) != 1 {
+		t.Errorf("attendees = %v", got["attendees"])
+	}
+	reminders, _ := got["reminders"].(map[string]interface{})
+	overrides, _ := reminders["overrides"].([]interface{})
+	if len(overrides) != 1 || reminders["useDefault"] != false {
+		t.Errorf("reminders = %v", got["reminders"])
+	} else if minutes := overrides[0].(map[string]interface{})["minutes"]; minutes != float64(0) {
+		t.Errorf("reminder minutes = %v, want 0", minutes)
+	}
+	if got["conferenceData"] == nil || query["conferenceDataVersion"][0] != "1" {
+		t.Errorf("Google Meet not requested: conferenceData=%v query=%v", got["conferenceData"], query)
+	}
+}
+
+// TestCalendarAPI_BuildEventErrors rejects data GenerateURL would also reject
+func TestCalendarAPI_BuildEventErrors(t *testing.T) {
+	api := NewCalendarAPI(http.DefaultClient)
+	cases := map[string]map[string]interface{}{
+		"missing title":  {"start": "2025-11-01T10:00", "end": "2025-11-01T11:00"},
+		"bad start":      {"title": "x", "start": "soon", "end": "2025-11-01T11:00"},
+		"bad conference": {"title": "x", "start": "2025-11-01T10:00", "end": "2025-11-01T11:00", "videoConference": "zoom"},
+	}
+	for name, data := range cases {
+		if _, err := api.BuildEvent(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
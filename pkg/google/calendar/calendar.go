@@ -4,6 +4,7 @@ package calendar
 import (
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	cal "github.com/joeblew999/wellknown/pkg/calendar"
@@ -16,6 +17,7 @@ const (
 	TimeFormat       = "20060102T150405Z"
 	QueryParamAction = "action"
 	QueryParamDates  = "dates"
+	QueryParamGuests = "add" // Comma-separated attendee emails
 )
 
 // Re-export shared field names from pkg/calendar for backwards compatibility
@@ -25,8 +27,20 @@ const (
 	FieldEnd         = cal.FieldEnd
 	FieldLocation    = cal.FieldLocation
 	FieldDescription = cal.FieldDescription
+	FieldAttendees   = cal.FieldAttendees
+	FieldReminders   = cal.FieldReminders
+
+	FieldVideoConference = cal.FieldVideoConference
 )
 
+// APIOnlyFields are schema fields the TEMPLATE URL cannot carry. Google ignores
+// unknown URL parameters, so these only take effect when the event is created
+// through the Calendar API; GenerateURL leaves them out.
+var APIOnlyFields = []string{
+	FieldReminders,
+	FieldVideoConference,
+}
+
 // FieldMapping maps schema fields to Google Calendar URL parameters (exported for tests)
 var FieldMapping = map[string]string{
 	cal.FieldTitle:       "text",
@@ -42,36 +56,15 @@ var FieldMapping = map[string]string{
 //   - end: string in datetime-local format "2006-01-02T15:04" (required)
 //   - location: string (optional)
 //   - description: string (optional)
+//   - attendees: array of email strings (optional, encoded as add=)
+//   - reminders, videoConference: accepted but not encoded (see APIOnlyFields)
 //
 // This function assumes data has already been validated against schema.json.
 // It does NOT perform validation - that's the JSON Schema's job!
 func GenerateURL(data map[string]interface{}) (string, error) {
-	// Extract required fields from validated data
-	title, ok := data[FieldTitle].(string)
-	if !ok || title == "" {
-		return "", fmt.Errorf("missing or invalid title field")
-	}
-
-	startStr, ok := data[FieldStart].(string)
-	if !ok || startStr == "" {
-		return "", fmt.Errorf("missing or invalid start field")
-	}
-
-	endStr, ok := data[FieldEnd].(string)
-	if !ok || endStr == "" {
-		return "", fmt.Errorf("missing or invalid end field")
-	}
-
-	// Parse datetime-local format: "2006-01-02T15:04"
-	// This is the HTML5 datetime-local input format
-	startTime, err := time.Parse("2006-01-02T15:04", startStr)
-	if err != nil {
-		return "", fmt.Errorf("invalid start time format: %w", err)
-	}
-
-	endTime, err := time.Parse("2006-01-02T15:04", endStr)
+	title, startTime, endTime, err := requiredEventFields(data)
 	if err != nil {
-		return "", fmt.Errorf("invalid end time format: %w", err)
+		return "", err
 	}
 
 	// Format times in Google Calendar format (UTC, ISO 8601: 20060102T150405Z)
@@ -93,9 +86,68 @@ func GenerateURL(data map[string]interface{}) (string, error) {
 		params.Set(FieldMapping[FieldDescription], description)
 	}
 
+	if attendees := stringList(data[FieldAttendees]); len(attendees) > 0 {
+		params.Set(QueryParamGuests, strings.Join(attendees, ","))
+	}
+
 	return BaseURL + "?" + params.Encode(), nil
 }
 
+// requiredEventFields extracts the title and times shared by GenerateURL and CalendarAPI
+func requiredEventFields(data map[string]interface{}) (string, time.Time, time.Time, error) {
+	title, ok := data[FieldTitle].(string)
+	if !ok || title == "" {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("missing or invalid title field")
+	}
+
+	startStr, ok := data[FieldStart].(string)
+	if !ok || startStr == "" {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("missing or invalid start field")
+	}
+
+	endStr, ok := data[FieldEnd].(string)
+	if !ok || endStr == "" {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("missing or invalid end field")
+	}
+
+	startTime, err := parseEventTime(startStr)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("invalid start time format: %w", err)
+	}
+
+	endTime, err := parseEventTime(endStr)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("invalid end time format: %w", err)
+	}
+
+	return title, startTime, endTime, nil
+}
+
+// parseEventTime parses the HTML5 datetime-local format ("2006-01-02T15:04"),
+// falling back to RFC 3339 for API callers
+func parseEventTime(s string) (time.Time, error) {
+	t, err := time.Parse(cal.DateTimeLocalFormat, s)
+	if err == nil {
+		return t, nil
+	}
+	if t, rfcErr := time.Parse(time.RFC3339, s); rfcErr == nil {
+		return t, nil
+	}
+	return time.Time{}, err
+}
+
+// stringList returns the non-empty strings in an array value
+func stringList(v interface{}) []string {
+	items, _ := v.([]interface{})
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			out = append(out, strings.TrimSpace(s))
+		}
+	}
+	return out
+}
+
 
 // formatTime converts a time.Time to Google Calendar format: 20060102T150405Z
 // Google Calendar requires UTC time in this specific format
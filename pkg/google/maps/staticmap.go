@@ -0,0 +1,293 @@
+// Package maps provides Google Maps URL generation.
+package maps
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ================================================================
+// Static Maps
+// ================================================================
+// A Static Maps URL returns a plain image, so emails and PDFs can embed a map
+// preview next to the interactive deep link:
+//
+//	src, err := maps.NewStaticMap(600, 300).
+//	    Marker(maps.MarkerStyle{Color: "red", Label: "A"}, "Sydney Opera House").
+//	    Key(apiKey).
+//	    SigningSecret(secret).
+//	    Build()
+//
+// With a signing secret the URL carries a signature, which Google requires
+// for keys restricted to signed requests and for high-volume use.
+
+// Static Maps API constants (exported for tests)
+const (
+	StaticMapBaseURL = "https://maps.googleapis.com/maps/api/staticmap"
+
+	MaxStaticMapSize = 640   // Largest width/height in pixels (before scale)
+	MaxZoom          = 21    // Deepest zoom level
+	MaxURLLength     = 16384 // Longest URL Google accepts
+)
+
+// Map types
+const (
+	MapTypeRoadmap   = "roadmap"
+	MapTypeSatellite = "satellite"
+	MapTypeTerrain   = "terrain"
+	MapTypeHybrid    = "hybrid"
+)
+
+// Marker sizes
+const (
+	MarkerSizeTiny  = "tiny"
+	MarkerSizeMid   = "mid"
+	MarkerSizeSmall = "small"
+)
+
+// MarkerStyle styles a group of markers
+type MarkerStyle struct {
+	Size  string // MarkerSizeTiny, MarkerSizeMid or MarkerSizeSmall (default: normal)
+	Color string // Named color ("red") or 0xRRGGBB
+	Label string // Single uppercase letter or digit (not shown on tiny/small markers)
+}
+
+// PathStyle styles a path
+type PathStyle struct {
+	Weight    int    // Line width in pixels (default: 5)
+	Color     string // Named color or 0xRRGGBB[AA]
+	FillColor string // Fills the area when the path is closed
+	Geodesic  bool   // Follow the curvature of the earth
+}
+
+// LatLng formats coordinates as a location ("-33.8568,151.2153"); any other
+// location is an address or place name
+func LatLng(lat, lng float64) string {
+	return strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lng, 'f', -1, 64)
+}
+
+type markerGroup struct {
+	style     MarkerStyle
+	locations []string
+}
+
+type path struct {
+	style  PathStyle
+	points []string
+}
+
+// StaticMapBuilder builds a Google Static Maps image URL
+type StaticMapBuilder struct {
+	width, height int
+	center        string
+	zoom          int
+	hasZoom       bool
+	scale         int
+	mapType       string
+	format        string
+	markers       []markerGroup
+	paths         []path
+	key           string
+	secret        string
+}
+
+// NewStaticMap starts a width x height map (pixels, at most MaxStaticMapSize)
+func NewStaticMap(width, height int) *StaticMapBuilder {
+	return &StaticMapBuilder{width: width, height: height}
+}
+
+// Center centers the map on a location (default: fit the markers and paths)
+func (b *StaticMapBuilder) Center(location string) *StaticMapBuilder {
+	b.center = location
+	return b
+}
+
+// Zoom sets the zoom level, 0 (world) to MaxZoom (default: fit the markers and paths)
+func (b *StaticMapBuilder) Zoom(level int) *StaticMapBuilder {
+	b.zoom = level
+	b.hasZoom = true
+	return b
+}
+
+// Scale multiplies the pixel density: 1, 2 (high-DPI screens and print) or 4
+func (b *StaticMapBuilder) Scale(scale int) *StaticMapBuilder {
+	b.scale = scale
+	return b
+}
+
+// MapType sets MapTypeRoadmap (default), MapTypeSatellite, MapTypeTerrain or MapTypeHybrid
+func (b *StaticMapBuilder) MapType(mapType string) *StaticMapBuilder {
+	b.mapType = mapType
+	return b
+}
+
+// Format sets the image format: "png" (default), "gif" or "jpg"
+func (b *StaticMapBuilder) Format(format string) *StaticMapBuilder {
+	b.format = format
+	return b
+}
+
+// Marker adds markers with one style at each location
+func (b *StaticMapBuilder) Marker(style MarkerStyle, locations ...string) *StaticMapBuilder {
+	b.markers = append(b.markers, markerGroup{style: style, locations: locations})
+	return b
+}
+
+// Path adds a line through points; repeat the first point to close it
+func (b *StaticMapBuilder) Path(style PathStyle, points ...string) *StaticMapBuilder {
+	b.paths = append(b.paths, path{style: style, points: points})
+	return b
+}
+
+// Key sets the API key
+func (b *StaticMapBuilder) Key(key string) *StaticMapBuilder {
+	b.key = key
+	return b
+}
+
+// SigningSecret signs the URL with the key's URL signing secret (URL-safe base64,
+// from the Google Cloud console)
+func (b *StaticMapBuilder) SigningSecret(secret string) *StaticMapBuilder {
+	b.secret = secret
+	return b
+}
+
+// Build validates the map and returns its URL
+func (b *StaticMapBuilder) Build() (string, error) {
+	if err := b.validate(); err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("size", fmt.Sprintf("%dx%d", b.width, b.height))
+	if b.center != "" {
+		params.Set("center", b.center)
+	}
+	if b.hasZoom {
+		params.Set("zoom", strconv.Itoa(b.zoom))
+	}
+	if b.scale != 0 {
+		params.Set("scale", strconv.Itoa(b.scale))
+	}
+	if b.mapType != "" {
+		params.Set("maptype", b.mapType)
+	}
+	if b.format != "" {
+		params.Set("format", b.format)
+	}
+	for _, m := range b.markers {
+		params.Add("markers", m.encode())
+	}
+	for _, p := range b.paths {
+		params.Add("path", p.encode())
+	}
+	if b.key != "" {
+		params.Set("key", b.key)
+	}
+
+	u := StaticMapBaseURL + "?" + params.Encode()
+	if b.secret != "" {
+		signed, err := SignURL(u, b.secret)
+		if err != nil {
+			return "", err
+		}
+		u = signed
+	}
+
+	if len(u) > MaxURLLength {
+		return "", fmt.Errorf("static map URL is %d characters (max %d) - use fewer markers or path points", len(u), MaxURLLength)
+	}
+	return u, nil
+}
+
+// validate checks the map against the Static Maps API limits
+func (b *StaticMapBuilder) validate() error {
+	if b.width < 1 || b.width > MaxStaticMapSize || b.height < 1 || b.height > MaxStaticMapSize {
+		return fmt.Errorf("size %dx%d out of range (1-%d)", b.width, b.height, MaxStaticMapSize)
+	}
+	if b.hasZoom && (b.zoom < 0 || b.zoom > MaxZoom) {
+		return fmt.Errorf("zoom %d out of range (0-%d)", b.zoom, MaxZoom)
+	}
+	if b.scale != 0 && b.scale != 1 && b.scale != 2 && b.scale != 4 {
+		return fmt.Errorf("scale must be 1, 2 or 4, got %d", b.scale)
+	}
+	switch b.mapType {
+	case "", MapTypeRoadmap, MapTypeSatellite, MapTypeTerrain, MapTypeHybrid:
+	default:
+		return fmt.Errorf("unknown map type %q", b.mapType)
+	}
+	if (b.center == "" || !b.hasZoom) && len(b.markers) == 0 && len(b.paths) == 0 {
+		return errors.New("static map needs a center and zoom, or markers or paths to fit")
+	}
+	for _, m := range b.markers {
+		if len(m.locations) == 0 {
+			return errors.New("marker group has no locations")
+		}
+		if len(m.style.Label) > 1 {
+			return fmt.Errorf("marker label %q must be a single character", m.style.Label)
+		}
+	}
+	for _, p := range b.paths {
+		if len(p.points) < 2 {
+			return errors.New("path needs at least two points")
+		}
+	}
+	return nil
+}
+
+// encode formats the markers parameter: styles then locations, separated by |
+func (m markerGroup) encode() string {
+	var parts []string
+	if m.style.Size != "" {
+		parts = append(parts, "size:"+m.style.Size)
+	}
+	if m.style.Color != "" {
+		parts = append(parts, "color:"+m.style.Color)
+	}
+	if m.style.Label != "" {
+		parts = append(parts, "label:"+strings.ToUpper(m.style.Label))
+	}
+	return strings.Join(append(parts, m.locations...), "|")
+}
+
+// encode formats the path parameter: styles then points, separated by |
+func (p path) encode() string {
+	var parts []string
+	if p.style.Weight != 0 {
+		parts = append(parts, "weight:"+strconv.Itoa(p.style.Weight))
+	}
+	if p.style.Color != "" {
+		parts = append(parts, "color:"+p.style.Color)
+	}
+	if p.style.FillColor != "" {
+		parts = append(parts, "fillcolor:"+p.style.FillColor)
+	}
+	if p.style.Geodesic {
+		parts = append(parts, "geodesic:true")
+	}
+	return strings.Join(append(parts, p.points...), "|")
+}
+
+// SignURL appends a signature to a Google Maps URL: an HMAC-SHA1 of its path
+// and query keyed with the URL signing secret, in URL-safe base64
+func SignURL(rawURL, secret string) (string, error) {
+	key, err := base64.URLEncoding.DecodeString(secret)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL signing secret: %w", err)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write([]byte(u.EscapedPath() + "?" + u.RawQuery))
+	signature := base64.URLEncoding.EncodeToString(mac.Sum(nil))
+	return rawURL + "&signature=" + signature, nil
+}
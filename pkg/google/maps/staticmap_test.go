@@ -0,0 +1,93 @@
+package maps
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestStaticMapBuild checks markers and paths are encoded as Google expects
+func TestStaticMapBuild(t *testing.T) {
+	u, err := NewStaticMap(600, 300).
+		Scale(2).
+		MapType(MapTypeTerrain).
+		Marker(MarkerStyle{Color: "red", Label: "a"}, "Sydney Opera House", LatLng(-33.8523, 151.2108)).
+		Path(PathStyle{Weight: 3, Color: "0x0000ff"}, LatLng(-33.8568, 151.2153), LatLng(-33.8523, 151.2108)).
+		Key("test-key").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if !strings.HasPrefix(u, StaticMapBaseURL+"?") {
+		t.Fatalf("URL should start with %s, got %s", StaticMapBaseURL, u)
+	}
+
+	parsed, _ := url.Parse(u)
+	q := parsed.Query()
+	want := map[string]string{
+		"size":    "600x300",
+		"scale":   "2",
+		"maptype": "terrain",
+		"markers": "color:red|label:A|Sydney Opera House|-33.8523,151.2108",
+		"path":    "weight:3|color:0x0000ff|-33.8568,151.2153|-33.8523,151.2108",
+		"key":     "test-key",
+	}
+	for param, value := range want {
+		if got := q.Get(param); got != value {
+			t.Errorf("%s: expected %q, got %q", param, value, got)
+		}
+	}
+	if q.Has("zoom") || q.Has("center") {
+		t.Error("zoom and center should be omitted when fitting markers")
+	}
+}
+
+// TestStaticMapSignature checks the signature is an HMAC-SHA1 of the path and query
+func TestStaticMapSignature(t *testing.T) {
+	secret := base64.URLEncoding.EncodeToString([]byte("not-a-real-secret"))
+	u, err := NewStaticMap(400, 400).Center("Zürich").Zoom(12).Key("test-key").SigningSecret(secret).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	unsigned, signature, ok := strings.Cut(u, "&signature=")
+	if !ok {
+		t.Fatalf("URL should be signed: %s", u)
+	}
+	mac := hmac.New(sha1.New, []byte("not-a-real-secret"))
+	mac.Write([]byte(strings.TrimPrefix(unsigned, "https://maps.googleapis.com")))
+	if want := base64.URLEncoding.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("expected signature %s, got %s", want, signature)
+	}
+
+	if _, err := NewStaticMap(400, 400).Center("Zürich").Zoom(12).SigningSecret("%%%").Build(); err == nil {
+		t.Error("expected an error for an invalid signing secret")
+	}
+}
+
+// TestStaticMapValidation checks requests outside the API limits are rejected
+func TestStaticMapValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *StaticMapBuilder
+	}{
+		{"too wide", NewStaticMap(641, 300).Center("Sydney").Zoom(10)},
+		{"bad zoom", NewStaticMap(300, 300).Center("Sydney").Zoom(22)},
+		{"bad scale", NewStaticMap(300, 300).Center("Sydney").Zoom(10).Scale(3)},
+		{"bad map type", NewStaticMap(300, 300).Center("Sydney").Zoom(10).MapType("moon")},
+		{"nothing to show", NewStaticMap(300, 300).Center("Sydney")},
+		{"empty markers", NewStaticMap(300, 300).Marker(MarkerStyle{})},
+		{"long label", NewStaticMap(300, 300).Marker(MarkerStyle{Label: "AB"}, "Sydney")},
+		{"short path", NewStaticMap(300, 300).Path(PathStyle{}, "Sydney")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
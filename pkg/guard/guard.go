@@ -0,0 +1,107 @@
+// Package guard provides the shared protection for public JSON endpoints:
+// CORS and per-client rate limiting, configured from registry variables.
+//
+// Usage:
+//
+//	cfg := guard.ConfigFromRegistry(registry) // CORS_ORIGINS, RATE_LIMIT_RPS
+//	mux.Handle("/api/", cfg.Middleware()(apiHandler))
+//
+// Applications add EnvVars to their registry so the variables show up in
+// templates, the env web GUI and deploy checks.
+package guard
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// Registry variable names
+const (
+	EnvCORSOrigins  = "CORS_ORIGINS"
+	EnvRateLimitRPS = "RATE_LIMIT_RPS"
+	EnvBehindProxy  = "BEHIND_PROXY"
+)
+
+// EnvVars are the registry variables that configure the guard
+var EnvVars = []env.EnvVar{
+	{
+		Name:        EnvCORSOrigins,
+		Description: "Comma-separated origins allowed to call the JSON APIs (* for any, empty for same-origin only)",
+		Group:       "Security",
+	},
+	{
+		Name:        EnvRateLimitRPS,
+		Description: "Requests per second allowed per client on the JSON APIs (0 disables rate limiting)",
+		Default:     "10",
+		Group:       "Security",
+	},
+}
+
+// Config configures Middleware
+type Config struct {
+	CORSOrigins  []string // Allowed origins; "*" allows any, empty adds no CORS headers
+	RateLimitRPS float64  // Requests per second per client; 0 disables rate limiting
+	TrustProxy   bool     // Identify clients by Fly-Client-IP or X-Forwarded-For (only behind a trusted proxy, see ClientIP)
+}
+
+// ConfigFromRegistry reads CORS_ORIGINS, RATE_LIMIT_RPS and BEHIND_PROXY.
+// Variables missing from the registry fall back to EnvVars defaults.
+func ConfigFromRegistry(registry *env.Registry) Config {
+	lookup := func(name string) *env.EnvVar {
+		if registry != nil {
+			if v := registry.ByName(name); v != nil {
+				return v
+			}
+		}
+		for i := range EnvVars {
+			if EnvVars[i].Name == name {
+				return &EnvVars[i]
+			}
+		}
+		return &env.EnvVar{Name: name}
+	}
+
+	cfg := Config{
+		CORSOrigins: ParseOrigins(lookup(EnvCORSOrigins).GetString()),
+		TrustProxy:  lookup(EnvBehindProxy).GetBool(),
+	}
+	if rps, err := strconv.ParseFloat(lookup(EnvRateLimitRPS).GetString(), 64); err == nil && rps > 0 {
+		cfg.RateLimitRPS = rps
+	}
+	return cfg
+}
+
+// ParseOrigins splits a comma-separated origin list
+func ParseOrigins(s string) []string {
+	var origins []string
+	for _, o := range strings.Split(s, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			origins = append(origins, o)
+		}
+	}
+	return origins
+}
+
+// Middleware returns the CORS and rate limiting middleware for cfg.
+// CORS runs first so preflight requests are not rate limited.
+func (cfg Config) Middleware() func(http.Handler) http.Handler {
+	cors := CORS(cfg.CORSOrigins...)
+	var limiter *RateLimiter
+	if cfg.RateLimitRPS > 0 {
+		limiter = NewRateLimiter(cfg.RateLimitRPS, 0)
+		limiter.TrustProxy = cfg.TrustProxy
+	}
+
+	return func(next http.Handler) http.Handler {
+		if limiter != nil {
+			next = limiter.Middleware(next)
+		}
+		if len(cfg.CORSOrigins) > 0 {
+			next = cors(next)
+		}
+		return next
+	}
+}
@@ -0,0 +1,115 @@
+package guard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+func TestRateLimiterRefills(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(1, 2)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d: expected burst to allow", i+1)
+		}
+	}
+	ok, wait := l.Allow("a")
+	if ok || wait != time.Second {
+		t.Fatalf("expected limit with 1s wait, got ok=%v wait=%s", ok, wait)
+	}
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("other clients must have their own bucket")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("expected a token after 1s")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		forwardedFor []string
+		fly          string
+		trustProxy   bool
+		want         string
+	}{
+		{nil, "", false, "10.0.0.1"},
+		{[]string{"203.0.113.9"}, "", false, "10.0.0.1"}, // Not behind a proxy: header ignored
+		{[]string{"203.0.113.9"}, "", true, "203.0.113.9"},
+		{[]string{"6.6.6.6, 203.0.113.9"}, "", true, "203.0.113.9"},      // Spoofed leading entry
+		{[]string{"6.6.6.6", "203.0.113.9 , "}, "", true, "203.0.113.9"}, // Several headers
+		{[]string{"6.6.6.6, 203.0.113.9"}, "198.51.100.7", true, "198.51.100.7"},
+		{nil, "", true, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "10.0.0.1:4321"
+		for _, v := range tt.forwardedFor {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if tt.fly != "" {
+			r.Header.Set("Fly-Client-IP", tt.fly)
+		}
+		if got := ClientIP(r, tt.trustProxy); got != tt.want {
+			t.Errorf("ClientIP(%q, fly %q, %v) = %s, want %s", tt.forwardedFor, tt.fly, tt.trustProxy, got, tt.want)
+		}
+	}
+}
+
+func TestMiddlewareRejectsOverLimit(t *testing.T) {
+	h := Config{RateLimitRPS: 1}.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	codes := make([]int, 3)
+	for i := range codes {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/x", nil))
+		codes[i] = rec.Code
+	}
+	if codes[0] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("expected 200 ... 429, got %v", codes)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	h := CORS("https://example.com")(http.NotFoundHandler())
+
+	for origin, want := range map[string]int{
+		"https://example.com": http.StatusNoContent,
+		"https://evil.test":   http.StatusNotFound,
+	} {
+		req := httptest.NewRequest("OPTIONS", "/api/x", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("preflight from %s: expected %d, got %d", origin, want, rec.Code)
+		}
+	}
+}
+
+func TestConfigFromRegistry(t *testing.T) {
+	t.Setenv(EnvCORSOrigins, "https://a.test, https://b.test")
+	t.Setenv(EnvRateLimitRPS, "2.5")
+
+	cfg := ConfigFromRegistry(env.NewRegistry(EnvVars))
+	if len(cfg.CORSOrigins) != 2 || cfg.CORSOrigins[1] != "https://b.test" {
+		t.Errorf("unexpected origins: %v", cfg.CORSOrigins)
+	}
+	if cfg.RateLimitRPS != 2.5 {
+		t.Errorf("expected 2.5 rps, got %v", cfg.RateLimitRPS)
+	}
+
+	// Missing variables fall back to defaults
+	t.Setenv(EnvRateLimitRPS, "")
+	if cfg := ConfigFromRegistry(nil); cfg.RateLimitRPS != 10 {
+		t.Errorf("expected default 10 rps, got %v", cfg.RateLimitRPS)
+	}
+}
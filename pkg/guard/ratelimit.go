@@ -0,0 +1,122 @@
+package guard
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clientIdleTimeout is how long an idle client's bucket is kept
+const clientIdleTimeout = 5 * time.Minute
+
+// RateLimiter is a per-client token bucket limiter
+type RateLimiter struct {
+	RPS        float64 // Tokens added per second
+	Burst      int     // Bucket size
+	TrustProxy bool    // Identify clients by X-Forwarded-For
+
+	mu        sync.Mutex
+	clients   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time // For tests
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing rps requests per second per client,
+// with bursts up to burst (0 means max(1, 2*rps))
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(2*rps)))
+	}
+	return &RateLimiter{
+		RPS:     rps,
+		Burst:   burst,
+		clients: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow reports whether client may make a request now, consuming a token if so.
+// When it may not, it returns how long until the next token.
+func (l *RateLimiter) Allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &bucket{tokens: float64(l.Burst), last: now}
+		l.clients[client] = b
+	}
+	b.tokens = math.Min(float64(l.Burst), b.tokens+now.Sub(b.last).Seconds()*l.RPS)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.RPS * float64(time.Second))
+	return false, wait
+}
+
+// sweep drops idle clients at most once per clientIdleTimeout (mu held)
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < clientIdleTimeout {
+		return
+	}
+	for client, b := range l.clients {
+		if now.Sub(b.last) > clientIdleTimeout {
+			delete(l.clients, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Middleware rejects requests over the limit with 429 Too Many Requests
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := l.Allow(ClientIP(r, l.TrustProxy))
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]string{"error": "rate limit exceeded"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientIP returns the client address of r. With trustProxy it is the
+// address the proxy saw: Fly-Client-IP on Fly.io, else the last
+// X-Forwarded-For entry (the one the proxy appended; earlier entries come
+// from the client and can be spoofed).
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fly := strings.TrimSpace(r.Header.Get("Fly-Client-IP")); fly != "" {
+			return fly
+		}
+		entries := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entry := strings.TrimSpace(entries[i]); entry != "" {
+				return entry
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,261 @@
+// Package health provides the health endpoint shared by every wellknown
+// binary, so deploy checks and monitors see the same JSON everywhere.
+//
+// Usage:
+//
+//	checker := health.New("pdf")
+//	checker.Register("database", func(ctx context.Context) error { return db.PingContext(ctx) })
+//	checker.RegisterOptional("smtp", smtpCheck) // Failure degrades, not fails
+//	checker.RegisterRoutes(mux)                  // GET /health
+//
+// The report always carries status, timestamp, environment, version, uptime
+// and Go runtime info (the fields deploy.VerifyDeployment reads), plus one
+// result per registered check. A failing critical check turns the status to
+// "down" and the response to 503; a failing optional check to "degraded".
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// Status is the overall or per-check health status
+type Status string
+
+// Health statuses
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded" // An optional check failed
+	StatusDown     Status = "down"     // A critical check failed
+)
+
+// DefaultTimeout bounds each check (see WithTimeout)
+const DefaultTimeout = 5 * time.Second
+
+// CheckFunc reports a dependency's health: nil when healthy
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Status   Status `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the JSON served by the health endpoint
+type Report struct {
+	Status        Status                 `json:"status"`
+	Service       string                 `json:"service,omitempty"`
+	Timestamp     string                 `json:"timestamp"`
+	Environment   string                 `json:"environment"`
+	Version       string                 `json:"version"`
+	Uptime        string                 `json:"uptime"`
+	GoVersion     string                 `json:"go_version"`
+	NumGoroutines int                    `json:"num_goroutines"`
+	Checks        map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// check is a registered check
+type check struct {
+	name     string
+	fn       CheckFunc
+	critical bool
+}
+
+// Checker runs registered checks and serves the health report
+type Checker struct {
+	service   string
+	version   string
+	startTime time.Time
+	timeout   time.Duration
+
+	mu     sync.RWMutex
+	checks []check
+}
+
+// New creates a checker for the named service, started now
+func New(service string) *Checker {
+	return &Checker{
+		service:   service,
+		version:   BuildVersion(),
+		startTime: time.Now(),
+		timeout:   DefaultTimeout,
+	}
+}
+
+// WithVersion overrides the reported version (default: BuildVersion)
+func (c *Checker) WithVersion(version string) *Checker {
+	c.version = version
+	return c
+}
+
+// WithTimeout overrides how long each check may take (default: DefaultTimeout)
+func (c *Checker) WithTimeout(d time.Duration) *Checker {
+	c.timeout = d
+	return c
+}
+
+// Register adds a critical check: when it fails the service is down.
+// Registering a name again replaces the check.
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.register(check{name: name, fn: fn, critical: true})
+}
+
+// RegisterOptional adds a check whose failure only degrades the service
+func (c *Checker) RegisterOptional(name string, fn CheckFunc) {
+	c.register(check{name: name, fn: fn})
+}
+
+func (c *Checker) register(ch check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.checks {
+		if c.checks[i].name == ch.name {
+			c.checks[i] = ch
+			return
+		}
+	}
+	c.checks = append(c.checks, ch)
+}
+
+// Checks returns the registered check names, sorted
+func (c *Checker) Checks() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.checks))
+	for _, ch := range c.checks {
+		names = append(names, ch.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Version returns the reported version
+func (c *Checker) Version() string {
+	return c.version
+}
+
+// Uptime returns how long ago the checker was created
+func (c *Checker) Uptime() time.Duration {
+	return time.Since(c.startTime)
+}
+
+// Run runs every check concurrently and builds the report
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]check(nil), c.checks...)
+	c.mu.RUnlock()
+
+	report := Report{
+		Status:        StatusOK,
+		Service:       c.service,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		Environment:   env.DetectEnvironment(),
+		Version:       c.version,
+		Uptime:        c.Uptime().Round(time.Second).String(),
+		GoVersion:     runtime.Version(),
+		NumGoroutines: runtime.NumGoroutine(),
+	}
+	if len(checks) == 0 {
+		return report
+	}
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.runCheck(ctx, ch)
+		}()
+	}
+	wg.Wait()
+
+	report.Checks = make(map[string]CheckResult, len(checks))
+	for i, ch := range checks {
+		result := results[i]
+		report.Checks[ch.name] = result
+		switch {
+		case result.Status == StatusOK:
+		case ch.critical:
+			report.Status = StatusDown
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// runCheck runs one check within the timeout (a panicking check fails)
+func (c *Checker) runCheck(ctx context.Context, ch check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- ch.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{Status: StatusOK, Critical: ch.critical, Duration: time.Since(start).Round(time.Millisecond).String()}
+	if err != nil {
+		result.Status, result.Error = StatusDown, err.Error()
+	}
+	return result
+}
+
+// ServeHTTP serves the report as JSON: 200 when ok or degraded, 503 when down
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == StatusDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// RegisterRoutes serves the report at /health
+func (c *Checker) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle("/health", c)
+}
+
+// BuildVersion returns the VCS revision stamped by go build, else the main module version
+func BuildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+	if info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return ""
+}
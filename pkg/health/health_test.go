@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecker(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("unreachable") }
+
+	tests := []struct {
+		name     string
+		setup    func(c *Checker)
+		status   Status
+		httpCode int
+	}{
+		{"no checks", func(c *Checker) {}, StatusOK, http.StatusOK},
+		{"all passing", func(c *Checker) {
+			c.Register("db", ok)
+			c.RegisterOptional("smtp", ok)
+		}, StatusOK, http.StatusOK},
+		{"optional failing", func(c *Checker) {
+			c.Register("db", ok)
+			c.RegisterOptional("smtp", failing)
+		}, StatusDegraded, http.StatusOK},
+		{"critical failing", func(c *Checker) {
+			c.Register("db", failing)
+			c.RegisterOptional("smtp", failing)
+		}, StatusDown, http.StatusServiceUnavailable},
+		{"panicking", func(c *Checker) {
+			c.Register("db", func(ctx context.Context) error { panic("boom") })
+		}, StatusDown, http.StatusServiceUnavailable},
+		{"replaced", func(c *Checker) {
+			c.Register("db", failing)
+			c.Register("db", ok)
+		}, StatusOK, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("test").WithVersion("v1.2.3")
+			tt.setup(c)
+
+			mux := http.NewServeMux()
+			c.RegisterRoutes(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			if rec.Code != tt.httpCode {
+				t.Errorf("HTTP %d, want %d", rec.Code, tt.httpCode)
+			}
+			var report Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if report.Status != tt.status {
+				t.Errorf("status %q, want %q (%+v)", report.Status, tt.status, report.Checks)
+			}
+			if report.Service != "test" || report.Version != "v1.2.3" || report.Environment == "" || report.GoVersion == "" {
+				t.Errorf("incomplete report: %+v", report)
+			}
+			if len(report.Checks) != len(c.Checks()) {
+				t.Errorf("%d results for checks %v", len(report.Checks), c.Checks())
+			}
+		})
+	}
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	c := New("test").WithTimeout(10 * time.Millisecond)
+	c.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	report := c.Run(context.Background())
+	if report.Status != StatusDown || report.Checks["slow"].Error != context.DeadlineExceeded.Error() {
+		t.Errorf("expected timeout, got %+v", report)
+	}
+}
@@ -0,0 +1,187 @@
+// Package netutil provides cross-platform port management for the serve
+// commands, the example CLI and the e2e harness: finding a free port,
+// waiting for a server to listen, and freeing a port held by a stale process.
+//
+// Usage:
+//
+//	port, _ := netutil.FindFreePort()                 // For tests and --port 0
+//	err := netutil.WaitForPort(ctx, "localhost:8090") // Instead of sleep 4
+//	pids, err := netutil.KillPort(8080)               // Instead of lsof | xargs kill
+//
+// Listening processes are found via /proc on Linux, lsof on macOS and
+// netstat on Windows.
+package netutil
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FindFreePort returns a TCP port that is free on localhost right now
+func FindFreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// WaitForPort blocks until something accepts TCP connections on addr
+// ("host:port") or ctx is done
+func WaitForPort(ctx context.Context, addr string) error {
+	var dialer net.Dialer
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s is not accepting connections: %w", addr, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// PortInUseError reports a port held by other processes
+type PortInUseError struct {
+	Port int
+	PIDs []int // Listening processes (empty if they could not be determined)
+}
+
+func (e *PortInUseError) Error() string {
+	if len(e.PIDs) == 0 {
+		return fmt.Sprintf("port %d is already in use", e.Port)
+	}
+	pids := make([]string, len(e.PIDs))
+	for i, pid := range e.PIDs {
+		pids[i] = strconv.Itoa(pid)
+	}
+	return fmt.Sprintf("port %d is already in use by PID %s", e.Port, strings.Join(pids, ", "))
+}
+
+// CheckPortFree returns a *PortInUseError if port cannot be listened on
+func CheckPortFree(port int) error {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err == nil {
+		l.Close()
+		return nil
+	}
+	pids, _ := ListeningPIDs(port)
+	return &PortInUseError{Port: port, PIDs: pids}
+}
+
+// ListeningPIDs returns the processes listening on TCP port, sorted
+func ListeningPIDs(port int) ([]int, error) {
+	pids, err := listeningPIDs(port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find processes on port %d: %w", port, err)
+	}
+	return uniqueSorted(pids), nil
+}
+
+// KillPort kills the processes listening on port (never the current one) and
+// returns their PIDs; none is not an error
+func KillPort(port int) ([]int, error) {
+	pids, err := ListeningPIDs(port)
+	if err != nil {
+		return nil, err
+	}
+
+	var killed []int
+	for _, pid := range pids {
+		if pid == os.Getpid() {
+			continue
+		}
+		p, err := os.FindProcess(pid)
+		if err == nil {
+			err = p.Kill()
+		}
+		if err != nil {
+			return killed, fmt.Errorf("failed to kill PID %d on port %d: %w", pid, port, err)
+		}
+		killed = append(killed, pid)
+	}
+	return killed, nil
+}
+
+func uniqueSorted(pids []int) []int {
+	seen := make(map[int]bool, len(pids))
+	var out []int
+	for _, pid := range pids {
+		if !seen[pid] {
+			seen[pid] = true
+			out = append(out, pid)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// ================================================================
+// Platform Output Parsers
+// ================================================================
+// Kept platform-independent so every parser is tested on every platform.
+
+// parseProcNetTCP returns the socket inodes listening on port in a Linux
+// /proc/net/tcp or /proc/net/tcp6 table
+func parseProcNetTCP(data string, port int) []string {
+	const stateListen = "0A"
+	var inodes []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != stateListen {
+			continue
+		}
+		i := strings.LastIndex(fields[1], ":")
+		if i < 0 {
+			continue
+		}
+		if p, err := strconv.ParseInt(fields[1][i+1:], 16, 32); err == nil && int(p) == port {
+			inodes = append(inodes, fields[9])
+		}
+	}
+	return inodes
+}
+
+// parseLsof parses the PIDs printed by "lsof -t"
+func parseLsof(out string) []int {
+	var pids []int
+	for _, line := range strings.Fields(out) {
+		if pid, err := strconv.Atoi(line); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// parseNetstat returns the PIDs listening on port in "netstat -ano" output (Windows)
+func parseNetstat(out string, port int) []int {
+	suffix := ":" + strconv.Itoa(port)
+	var pids []int
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		// Proto Local-Address Foreign-Address State PID
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 || fields[0] != "TCP" || fields[3] != "LISTENING" || !strings.HasSuffix(fields[1], suffix) {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[4]); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
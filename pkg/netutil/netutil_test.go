@@ -0,0 +1,158 @@
+package netutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFindFreePort(t *testing.T) {
+	port, err := FindFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatalf("port %d should be free: %v", port, err)
+	}
+	l.Close()
+}
+
+func TestWaitForPort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := WaitForPort(ctx, l.Addr().String()); err != nil {
+		t.Errorf("listening port: %v", err)
+	}
+
+	port, _ := FindFreePort()
+	ctx, cancel = context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := WaitForPort(ctx, fmt.Sprintf("127.0.0.1:%d", port)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("closed port: got %v, want deadline exceeded", err)
+	}
+}
+
+func TestCheckPortFree(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+
+	var inUse *PortInUseError
+	if err := CheckPortFree(port); !errors.As(err, &inUse) || inUse.Port != port {
+		t.Errorf("CheckPortFree(%d) = %v, want PortInUseError", port, err)
+	}
+	l.Close()
+	if err := CheckPortFree(port); err != nil {
+		t.Errorf("CheckPortFree after close: %v", err)
+	}
+}
+
+func TestParseProcNetTCP(t *testing.T) {
+	table := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4242 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 4343 1 0000000000000000 20 4 30 10 -1
+   2: 0100007F:1F9A 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4444 1 0000000000000000 100 0 0 10 0`
+	if got := parseProcNetTCP(table, 8080); !reflect.DeepEqual(got, []string{"4242"}) {
+		t.Errorf("parseProcNetTCP = %v, want only the listening socket on 8080", got)
+	}
+}
+
+func TestParseNetstat(t *testing.T) {
+	out := `
+Active Connections
+
+  Proto  Local Address          Foreign Address        State           PID
+  TCP    0.0.0.0:8080           0.0.0.0:0              LISTENING       1234
+  TCP    127.0.0.1:18080        0.0.0.0:0              LISTENING       999
+  TCP    127.0.0.1:8080         127.0.0.1:52000        ESTABLISHED     1234
+  TCP    [::]:8080              [::]:0                 LISTENING       5678
+  UDP    0.0.0.0:8080           *:*                                    4321`
+	if got := parseNetstat(out, 8080); !reflect.DeepEqual(got, []int{1234, 5678}) {
+		t.Errorf("parseNetstat = %v", got)
+	}
+}
+
+func TestParseLsof(t *testing.T) {
+	if got := parseLsof("123\n456\n"); !reflect.DeepEqual(got, []int{123, 456}) {
+		t.Errorf("parseLsof = %v", got)
+	}
+}
+
+// TestHelperListener is not a real test: TestKillPort runs the test binary
+// with NETUTIL_HELPER_PORT set to get a separate process listening on a port
+func TestHelperListener(t *testing.T) {
+	port := os.Getenv("NETUTIL_HELPER_PORT")
+	if port == "" {
+		t.Skip("helper process")
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:"+port)
+	if err != nil {
+		os.Exit(2)
+	}
+	defer l.Close()
+	time.Sleep(time.Minute)
+}
+
+func TestKillPort(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("process lookup is exercised on linux and darwin")
+	}
+	if runtime.GOOS == "darwin" {
+		if _, err := exec.LookPath("lsof"); err != nil {
+			t.Skip("lsof not installed")
+		}
+	}
+
+	port, err := FindFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperListener$")
+	cmd.Env = append(os.Environ(), "NETUTIL_HELPER_PORT="+strconv.Itoa(port))
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() { cmd.Wait(); close(done) }()
+	defer cmd.Process.Kill()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := WaitForPort(ctx, fmt.Sprintf("127.0.0.1:%d", port)); err != nil {
+		t.Fatal(err)
+	}
+
+	killed, err := KillPort(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(killed, []int{cmd.Process.Pid}) {
+		t.Errorf("KillPort = %v, want [%d]", killed, cmd.Process.Pid)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("helper process still running")
+	}
+
+	if killed, err := KillPort(port); err != nil || len(killed) != 0 {
+		t.Errorf("free port: KillPort = %v, %v", killed, err)
+	}
+}
@@ -0,0 +1,20 @@
+package netutil
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// listeningPIDs asks lsof for the processes listening on port
+func listeningPIDs(port int) ([]int, error) {
+	out, err := exec.Command("lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-t").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil, nil // Nothing listening
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseLsof(string(out)), nil
+}
@@ -0,0 +1,42 @@
+package netutil
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// listeningPIDs finds the listening sockets in /proc/net/tcp{,6}, then the
+// processes holding them. Processes of other users are only visible to root.
+func listeningPIDs(port int) ([]int, error) {
+	inodes := map[string]bool{}
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(table)
+		if err != nil {
+			continue // No IPv6
+		}
+		for _, inode := range parseProcNetTCP(string(data), port) {
+			inodes["socket:["+inode+"]"] = true
+		}
+	}
+	if len(inodes) == 0 {
+		return nil, nil
+	}
+
+	fds, err := filepath.Glob("/proc/[0-9]*/fd/[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, fd := range fds {
+		link, err := os.Readlink(fd)
+		if err != nil || !inodes[link] {
+			continue
+		}
+		// /proc/<pid>/fd/<fd>
+		if pid, err := strconv.Atoi(filepath.Base(filepath.Dir(filepath.Dir(fd)))); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
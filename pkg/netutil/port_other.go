@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package netutil
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// listeningPIDs is not supported on this platform
+func listeningPIDs(port int) ([]int, error) {
+	return nil, fmt.Errorf("finding processes by port is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,12 @@
+package netutil
+
+import "os/exec"
+
+// listeningPIDs reads the listening sockets and their owners from netstat
+func listeningPIDs(port int) ([]int, error) {
+	out, err := exec.Command("netstat", "-ano").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseNetstat(string(out), port), nil
+}
@@ -0,0 +1,381 @@
+package wellknown
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/env/webui"
+	"github.com/joeblew999/wellknown/pkg/pbmcp"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// ================================================================
+// Admin Status Dashboard
+// ================================================================
+// GET /admin/status is the operator view of a deployed instance: the env
+// status from pkg/env/webui, PocketBase backups, Google OAuth health, whether
+// an MCP server is attached to the data directory, and the latest errors from
+// the PocketBase logs. HTML by default, JSON with ?format=json.
+//
+// Superusers only: a superuser token (Authorization header), or HTTP Basic
+// auth with superuser credentials so the page opens in a browser.
+
+// recentErrorsLimit and recentErrorsWindow bound the errors shown
+const (
+	recentErrorsLimit  = 20
+	recentErrorsWindow = 24 * time.Hour
+)
+
+// AdminStatus is the data behind /admin/status
+type AdminStatus struct {
+	Generated time.Time     `json:"generated"`
+	Fly       FlyInstance   `json:"fly"`
+	Env       webui.Status  `json:"env"`
+	Backups   BackupStatus  `json:"backups"`
+	OAuth     OAuthStatus   `json:"oauth"`
+	MCP       *pbmcp.State  `json:"mcp"` // nil: no MCP server has run on this data directory
+	Errors    []RecentError `json:"errors"`
+}
+
+// FlyInstance identifies the Fly.io machine serving the page (empty elsewhere)
+type FlyInstance struct {
+	App     string `json:"app,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Machine string `json:"machine,omitempty"`
+}
+
+// BackupStatus summarises PocketBase backups (Settings > Backups)
+type BackupStatus struct {
+	Cron      string      `json:"cron,omitempty"` // Empty: automatic backups disabled
+	MaxKeep   int         `json:"max_keep,omitempty"`
+	S3        bool        `json:"s3"`
+	Active    string      `json:"active,omitempty"` // Backup or restore in progress
+	Count     int         `json:"count"`
+	TotalSize int64       `json:"total_size"`
+	Latest    *BackupFile `json:"latest,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// BackupFile is one backup archive
+type BackupFile struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// OAuthStatus summarises the Google OAuth provider and its stored tokens
+type OAuthStatus struct {
+	Google          bool   `json:"google"` // Deployment-wide client configured
+	RedirectURL     string `json:"redirect_url,omitempty"`
+	TenantOverrides int    `json:"tenant_overrides"` // Tenants with their own GOOGLE_* client
+	Tokens          int    `json:"tokens"`
+	Expired         int    `json:"expired"`    // Access token expired (refreshed on next use)
+	NoRefresh       int    `json:"no_refresh"` // Expired without a refresh token: user must sign in again
+	Error           string `json:"error,omitempty"`
+}
+
+// RecentError is an error-level PocketBase log entry
+type RecentError struct {
+	Time    time.Time      `json:"time"`
+	Message string         `json:"message"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// Healthy reports whether nothing on the page needs attention
+func (s *AdminStatus) Healthy() bool {
+	return s.Env.Healthy() && s.Backups.Error == "" && s.OAuth.Error == "" &&
+		s.OAuth.NoRefresh == 0 && len(s.Errors) == 0
+}
+
+// CollectAdminStatus gathers the status of every subsystem; a failing
+// subsystem is reported in its section rather than failing the whole page
+func CollectAdminStatus(wk *Wellknown, envUI *webui.Handler) *AdminStatus {
+	status := &AdminStatus{
+		Generated: time.Now().UTC(),
+		Fly: FlyInstance{
+			App:     os.Getenv("FLY_APP_NAME"),
+			Region:  os.Getenv("FLY_REGION"),
+			Machine: os.Getenv("FLY_MACHINE_ID"),
+		},
+		Env:     envUI.Status(),
+		Backups: backupStatus(wk),
+		OAuth:   oauthStatus(wk),
+	}
+
+	mcpState, err := pbmcp.ReadState(wk.DataDir())
+	if err != nil {
+		log.Printf("⚠️  MCP state unreadable: %v", err)
+	}
+	status.MCP = mcpState
+
+	status.Errors = recentErrors(wk)
+	return status
+}
+
+// backupStatus lists the backups filesystem (local or S3)
+func backupStatus(wk *Wellknown) BackupStatus {
+	settings := wk.Settings().Backups
+	status := BackupStatus{
+		Cron:    settings.Cron,
+		MaxKeep: settings.CronMaxKeep,
+		S3:      settings.S3.Enabled,
+	}
+	if active, ok := wk.Store().Get(core.StoreKeyActiveBackup).(string); ok {
+		status.Active = active
+	}
+
+	fsys, err := wk.NewBackupsFilesystem()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	defer fsys.Close()
+
+	files, err := fsys.List("")
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	for _, f := range files {
+		status.Count++
+		status.TotalSize += f.Size
+		if status.Latest == nil || f.ModTime.After(status.Latest.Modified) {
+			status.Latest = &BackupFile{Name: f.Key, Size: f.Size, Modified: f.ModTime}
+		}
+	}
+	return status
+}
+
+// oauthStatus reports the Google client configuration and token health
+func oauthStatus(wk *Wellknown) OAuthStatus {
+	var status OAuthStatus
+	if wk.oauthService != nil {
+		status.Google = true
+		status.RedirectURL = wk.oauthService.GoogleConfig.RedirectURL
+	}
+
+	if tenants, err := wk.FindAllRecords(tenantsCollection); err == nil {
+		for _, record := range tenants {
+			if t, err := wk.FindTenant(record.GetString("slug")); err == nil && t.overridesGoogle() {
+				status.TenantOverrides++
+			}
+		}
+	}
+
+	now := dbx.Params{"now": time.Now().UTC().Format(time.DateTime)}
+	var err error
+	if status.Tokens, err = countRecords(wk, "google_tokens", nil); err == nil {
+		if status.Expired, err = countRecords(wk, "google_tokens", dbx.NewExp("expiry < {:now}", now)); err == nil {
+			status.NoRefresh, err = countRecords(wk, "google_tokens", dbx.NewExp("expiry < {:now} AND refresh_token = ''", now))
+		}
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// countRecords counts a collection's records matching expr (all when nil)
+func countRecords(wk *Wellknown, collection string, expr dbx.Expression) (int, error) {
+	var exprs []dbx.Expression
+	if expr != nil {
+		exprs = append(exprs, expr)
+	}
+	n, err := wk.CountRecords(collection, exprs...)
+	return int(n), err
+}
+
+// recentErrors returns the latest error-level log entries (logs are
+// disabled when Settings > Logs > max days is 0)
+func recentErrors(wk *Wellknown) []RecentError {
+	var logs []*core.Log
+	err := wk.LogQuery().
+		AndWhere(dbx.NewExp("level >= {:level}", dbx.Params{"level": int(slog.LevelError)})).
+		AndWhere(dbx.NewExp("created >= {:since}", dbx.Params{"since": time.Now().UTC().Add(-recentErrorsWindow).Format(time.DateTime)})).
+		OrderBy("created DESC").
+		Limit(recentErrorsLimit).
+		All(&logs)
+	if err != nil {
+		log.Printf("⚠️  Recent errors unavailable: %v", err)
+		return nil
+	}
+
+	errs := make([]RecentError, 0, len(logs))
+	for _, l := range logs {
+		errs = append(errs, RecentError{Time: l.Created.Time(), Message: l.Message, Data: l.Data})
+	}
+	return errs
+}
+
+// requireSuperuser admits superuser tokens and superuser Basic auth
+func requireSuperuser(wk *Wellknown) func(*core.RequestEvent) error {
+	return func(c *core.RequestEvent) error {
+		if c.HasSuperuserAuth() {
+			return c.Next()
+		}
+		if email, password, ok := c.Request.BasicAuth(); ok {
+			record, err := wk.FindAuthRecordByEmail(core.CollectionNameSuperusers, email)
+			if err == nil && record.ValidatePassword(password) {
+				c.Auth = record
+				return c.Next()
+			}
+		}
+		c.Response.Header().Set("WWW-Authenticate", `Basic realm="wellknown admin", charset="UTF-8"`)
+		return problemJSON(c, ProblemUnauthenticated, "Superuser credentials required")
+	}
+}
+
+// RegisterAdminRoutes registers the operator status page
+func RegisterAdminRoutes(wk *Wellknown, e *core.ServeEvent, registry *RouteRegistry) {
+	// Shares the /health checker, so the env uptime is the server's
+	envUI := webui.NewHandler(EnvRegistry).WithHealth(wk.health)
+
+	route := e.Router.GET("/admin/status", func(c *core.RequestEvent) error {
+		status := CollectAdminStatus(wk, envUI)
+		if c.Request.URL.Query().Get("format") == "json" || strings.Contains(c.Request.Header.Get("Accept"), "application/json") {
+			return c.JSON(http.StatusOK, status)
+		}
+		c.Response.Header().Set("Content-Type", "text/html; charset=utf-8")
+		return adminStatusTemplate.Execute(c.Response, status)
+	})
+	if registry.guard != nil {
+		route.BindFunc(registry.guard)
+	}
+	route.BindFunc(requireSuperuser(wk))
+	registry.Register("Admin", "/admin/status", "GET", "Operator status: env, backups, OAuth, MCP, recent errors (superuser; ?format=json)", true)
+}
+
+// formatBytes renders a size in B/KB/MB/GB
+func formatBytes(n int64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	size, unit := float64(n), 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
+
+// ago renders the time since t, rounded for display
+func ago(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// errorData renders a log entry's attributes as "key=value ..." (sorted)
+func errorData(data map[string]any) string {
+	parts := make([]string, 0, len(data))
+	for k, v := range data {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+var adminStatusTemplate = template.Must(template.New("admin_status").Funcs(template.FuncMap{
+	"bytes": formatBytes,
+	"ago":   ago,
+	"data":  errorData,
+	"join":  strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1.0">
+<meta http-equiv="refresh" content="60">
+<title>Status{{with .Fly.App}} - {{.}}{{end}}</title>
+<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/@picocss/pico@2/css/pico.min.css">
+<style>
+.ok { color: var(--pico-ins-color); }
+.bad { color: var(--pico-del-color); }
+.grid article { margin: 0; }
+td small { color: var(--pico-muted-color); }
+</style>
+</head>
+<body>
+<main class="container">
+<hgroup>
+<h1>{{if .Healthy}}<span class="ok">●</span>{{else}}<span class="bad">●</span>{{end}} Status</h1>
+<p>{{with .Fly.App}}{{.}}{{else}}local{{end}}{{with .Fly.Region}} · {{.}}{{end}}{{with .Fly.Machine}} · machine {{.}}{{end}} · {{.Env.Environment}}{{with .Env.Version}} · {{.}}{{end}} · up {{.Env.Uptime}} · generated {{.Generated.Format "2006-01-02 15:04:05 MST"}} · <a href="?format=json">JSON</a></p>
+</hgroup>
+
+<div class="grid">
+<article>
+<header><strong>Environment</strong></header>
+<p>{{.Env.Configured}} of {{.Env.Total}} variables set · {{.Env.Secrets}} secrets</p>
+{{with .Env.MissingRequired}}<p class="bad">Missing required: {{join . ", "}}</p>{{else}}<p class="ok">All required variables set</p>{{end}}
+{{if .Env.SecretFindings}}<p class="bad">{{.Env.SecretFindings}} secret lint finding(s)</p>{{end}}
+{{range .Env.PolicyViolations}}<p class="bad">Policy: {{.}}</p>{{end}}
+<small>{{.Env.GoVersion}} · {{.Env.Goroutines}} goroutines</small>
+</article>
+
+<article>
+<header><strong>Backups</strong></header>
+{{with .Backups}}
+{{if .Error}}<p class="bad">{{.Error}}</p>{{end}}
+<p>{{if .Cron}}Automatic: <code>{{.Cron}}</code>{{if .MaxKeep}}, keep {{.MaxKeep}}{{end}}{{else}}<span class="bad">Automatic backups disabled</span>{{end}} · {{if .S3}}S3{{else}}local disk{{end}}</p>
+<p>{{.Count}} backup(s), {{bytes .TotalSize}}</p>
+{{with .Latest}}<p>Latest: {{.Name}} ({{bytes .Size}}, {{ago .Modified}})</p>{{end}}
+{{with .Active}}<p>In progress: {{.}}</p>{{end}}
+{{end}}
+</article>
+</div>
+
+<div class="grid">
+<article>
+<header><strong>Google OAuth</strong></header>
+{{with .OAuth}}
+{{if .Error}}<p class="bad">{{.Error}}</p>{{end}}
+<p>{{if .Google}}<span class="ok">Configured</span>{{with .RedirectURL}} · <small>{{.}}</small>{{end}}{{else}}<span class="bad">Not configured</span>{{end}}{{if .TenantOverrides}} · {{.TenantOverrides}} tenant client(s){{end}}</p>
+<p>{{.Tokens}} token(s) · {{.Expired}} expired{{if .NoRefresh}} · <span class="bad">{{.NoRefresh}} need sign-in again</span>{{end}}</p>
+{{end}}
+</article>
+
+<article>
+<header><strong>MCP server</strong></header>
+{{with .MCP}}
+<p>{{if .Running}}<span class="ok">Running</span>{{else}}<span class="bad">Not running</span> (last seen){{end}}: {{.Name}} {{.Version}} over {{.Transport}}, pid {{.PID}}, started {{ago .Started}}</p>
+<p><small>Tools: {{join .Tools ", "}}<br>Resources: {{join .Resources ", "}}<br>Prompts: {{join .Prompts ", "}}</small></p>
+{{else}}
+<p>No MCP server has run on this data directory</p>
+{{end}}
+</article>
+</div>
+
+<article>
+<header><strong>Recent errors</strong> <small>(last 24h)</small></header>
+{{with .Errors}}
+<table>
+<tbody>
+{{range .}}<tr><td><small>{{.Time.Format "01-02 15:04:05"}}</small></td><td>{{.Message}}{{with .Data}}<br><small>{{data .}}</small>{{end}}</td></tr>
+{{end}}
+</tbody>
+</table>
+{{else}}
+<p class="ok">No errors logged</p>
+{{end}}
+</article>
+</main>
+</body>
+</html>
+`))
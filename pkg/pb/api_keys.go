@@ -0,0 +1,450 @@
+package wellknown
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/guard"
+	"github.com/joeblew999/wellknown/pkg/server"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// ================================================================
+// API Keys (public link-generation API)
+// ================================================================
+// Third parties call the link builders at POST /api/links/{platform}/{app}
+// (same body and success response as the standalone JSON API, see
+// pkg/server/api.go; errors are problem+json like every wellknown route)
+// with an X-API-Key header. Keys are records in the api_keys collection (see
+// pb_migrations/1731100000_init_api_keys.go); only a SHA-256 of the key is
+// stored, so the key itself is shown once, when a superuser creates it:
+//
+//	curl -X POST localhost:8090/api/keys -H "Authorization: $SUPERUSER_TOKEN" -d '{"name":"Acme"}'
+//	{"id":"...","key":"wk_3f9c...","prefix":"wk_3f9c1a2b", ...}
+//
+// Each key has its own rate limit (rate_limit, else API_KEY_RATE_LIMIT_RPS).
+// Usage is counted in memory and written to api_key_usage once a minute as
+// one record per key per UTC day.
+
+const (
+	apiKeysCollection     = "api_keys"
+	apiKeyUsageCollection = "api_key_usage"
+
+	// APIKeyHeader carries the API key of a link-generation request
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyPrefix        = "wk_"
+	apiKeyPrefixLen     = len(apiKeyPrefix) + 8 // Stored unhashed to recognise a key
+	apiKeyStoreKey      = "wellknown.api_key"   // RequestEvent store key
+	apiKeyUsageFlushJob = "api_key_usage_flush"
+)
+
+// ErrAPIKeyNotFound is returned for an unknown or revoked API key
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// APIKey is an issued key (without the key itself)
+type APIKey struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Prefix    string  `json:"prefix"`
+	Tenant    string  `json:"tenant,omitempty"`
+	RateLimit float64 `json:"rate_limit,omitempty"` // Requests per second; 0 uses the default
+	Enabled   bool    `json:"enabled"`
+}
+
+// GenerateAPIKey returns a new random key and the hash to store for it
+func GenerateAPIKey() (key, hash string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key = apiKeyPrefix + hex.EncodeToString(b)
+	return key, hashAPIKey(key), nil
+}
+
+// hashAPIKey is the key_hash stored for key
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyFromRecord converts an api_keys record
+func apiKeyFromRecord(record *core.Record) *APIKey {
+	return &APIKey{
+		ID:        record.Id,
+		Name:      record.GetString("name"),
+		Prefix:    record.GetString("prefix"),
+		Tenant:    record.GetString("tenant"),
+		RateLimit: record.GetFloat("rate_limit"),
+		Enabled:   record.GetBool("enabled"),
+	}
+}
+
+// APIKeyFromRequest returns the key that authenticated a link-generation
+// request (nil elsewhere)
+func APIKeyFromRequest(e *core.RequestEvent) *APIKey {
+	k, _ := e.Get(apiKeyStoreKey).(*APIKey)
+	return k
+}
+
+// apiKeyUsage identifies a pending usage counter
+type apiKeyUsage struct {
+	keyID string
+	day   string
+}
+
+type apiKeyCount struct {
+	requests int
+	limited  int
+}
+
+// apiKeyGate authenticates, rate limits and meters API key requests
+type apiKeyGate struct {
+	wk         *Wellknown
+	defaultRPS float64
+
+	mu       sync.Mutex
+	keys     map[string]*APIKey            // Enabled keys by hash, cleared when a key changes
+	limiters map[string]*guard.RateLimiter // By key ID; kept across cache clears
+	usage    map[apiKeyUsage]*apiKeyCount  // Not yet written to api_key_usage
+}
+
+func newAPIKeyGate(wk *Wellknown) *apiKeyGate {
+	rps, err := strconv.ParseFloat(EnvRegistry.ByName("API_KEY_RATE_LIMIT_RPS").GetString(), 64)
+	if err != nil || rps <= 0 {
+		rps = 2
+	}
+	return &apiKeyGate{
+		wk:         wk,
+		defaultRPS: rps,
+		keys:       make(map[string]*APIKey),
+		limiters:   make(map[string]*guard.RateLimiter),
+		usage:      make(map[apiKeyUsage]*apiKeyCount),
+	}
+}
+
+// find loads an enabled key by its plaintext value
+func (g *apiKeyGate) find(key string) (*APIKey, error) {
+	hash := hashAPIKey(key)
+	g.mu.Lock()
+	k, ok := g.keys[hash]
+	g.mu.Unlock()
+	if ok {
+		return k, nil
+	}
+
+	record, err := g.wk.FindFirstRecordByData(apiKeysCollection, "key_hash", hash)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !record.GetBool("enabled")) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+
+	k = apiKeyFromRecord(record)
+	g.mu.Lock()
+	g.keys[hash] = k
+	g.mu.Unlock()
+	return k, nil
+}
+
+// limiter returns the rate limiter of k, replacing it when k's rate changed
+func (g *apiKeyGate) limiter(k *APIKey) *guard.RateLimiter {
+	rps := k.RateLimit
+	if rps <= 0 {
+		rps = g.defaultRPS
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	l, ok := g.limiters[k.ID]
+	if !ok || l.RPS != rps {
+		l = guard.NewRateLimiter(rps, 0)
+		g.limiters[k.ID] = l
+	}
+	return l
+}
+
+// meter counts one request of k (limited: rejected by the rate limit)
+func (g *apiKeyGate) meter(k *APIKey, limited bool) {
+	id := apiKeyUsage{keyID: k.ID, day: time.Now().UTC().Format("2006-01-02")}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c, ok := g.usage[id]
+	if !ok {
+		c = &apiKeyCount{}
+		g.usage[id] = c
+	}
+	if limited {
+		c.limited++
+	} else {
+		c.requests++
+	}
+}
+
+// clear drops cached keys after an api_keys record changes
+func (g *apiKeyGate) clear() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.keys = make(map[string]*APIKey)
+}
+
+// flush adds the pending counts to api_key_usage
+func (g *apiKeyGate) flush() {
+	g.mu.Lock()
+	pending := g.usage
+	g.usage = make(map[apiKeyUsage]*apiKeyCount)
+	g.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	collection, err := g.wk.FindCollectionByNameOrId(apiKeyUsageCollection)
+	if err != nil {
+		log.Printf("⚠️  API key usage not saved: %v", err)
+		return
+	}
+	for id, c := range pending {
+		record, err := g.wk.FindFirstRecordByFilter(apiKeyUsageCollection,
+			"api_key = {:key} && day = {:day}", dbx.Params{"key": id.keyID, "day": id.day})
+		if errors.Is(err, sql.ErrNoRows) {
+			record = core.NewRecord(collection)
+			record.Set("api_key", id.keyID)
+			record.Set("day", id.day)
+		} else if err != nil {
+			log.Printf("⚠️  API key usage of %s not saved: %v", id.keyID, err)
+			continue
+		}
+		record.Set("requests", record.GetInt("requests")+c.requests)
+		record.Set("limited", record.GetInt("limited")+c.limited)
+		if err := g.wk.Save(record); err != nil {
+			// Expected when the key was deleted since (its usage is deleted with it)
+			log.Printf("⚠️  API key usage of %s not saved: %v", id.keyID, err)
+		}
+	}
+}
+
+// middleware admits requests with a valid, enabled key of the request's
+// tenant, within the key's rate limit
+func (g *apiKeyGate) middleware(c *core.RequestEvent) error {
+	key := c.Request.Header.Get(APIKeyHeader)
+	if key == "" {
+		return problemJSON(c, ProblemUnauthenticated, "API key required ("+APIKeyHeader+" header)")
+	}
+	k, err := g.find(key)
+	if err == nil && k.Tenant != tenantSlug(TenantFromRequest(c)) {
+		err = ErrAPIKeyNotFound
+	}
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		return problemJSON(c, ProblemUnauthenticated, "Invalid API key")
+	}
+	if err != nil {
+		return internalProblem(c, ProblemInternal, "Failed to check API key", err)
+	}
+
+	if ok, wait := g.limiter(k).Allow(k.ID); !ok {
+		g.meter(k, true)
+		c.Response.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		return problemJSON(c, ProblemRateLimited, "rate limit exceeded")
+	}
+	g.meter(k, false)
+	c.Set(apiKeyStoreKey, k)
+	return c.Next()
+}
+
+// linkHandler serves a standalone JSON API route (see adaptHandler), turning
+// its error bodies ({"error"} or {"errors"}) into problems
+func linkHandler(mux *http.ServeMux, path, description string) func(*core.RequestEvent) error {
+	serve := adaptHandler(mux, path, description)
+	return func(e *core.RequestEvent) error {
+		buf := &bufferedResponse{header: make(http.Header)}
+		w := e.Response
+		e.Response = buf
+		err := serve(e)
+		e.Response = w
+		if err != nil {
+			return err
+		}
+
+		buf.WriteHeader(http.StatusOK) // Nothing written: an empty 200
+		if buf.status < http.StatusBadRequest {
+			for name, values := range buf.header {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(buf.status)
+			_, err := w.Write(buf.body.Bytes())
+			return err
+		}
+		var resp server.APIResponse
+		json.Unmarshal(buf.body.Bytes(), &resp)
+		switch {
+		case len(resp.Errors) > 0:
+			return fieldsProblem(e, resp.Errors)
+		case buf.status >= http.StatusInternalServerError:
+			return internalProblem(e, ProblemInternal, "Failed to generate the link", errors.New(resp.Error))
+		case buf.status == http.StatusNotFound:
+			return problemJSON(e, ProblemNotFound, "Unknown link builder")
+		}
+		detail := resp.Error
+		if detail == "" {
+			detail = http.StatusText(buf.status)
+		}
+		return problemJSON(e, ProblemBadRequest, detail)
+	}
+}
+
+// bufferedResponse holds a response until it has been inspected
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+// RegisterAPIKeyRoutes registers the API-key-protected link-generation API and
+// the superuser routes that issue and revoke keys
+func RegisterAPIKeyRoutes(wk *Wellknown, e *core.ServeEvent, registry *RouteRegistry) {
+	// Pre-flight check: Validate required collections exist
+	for _, collectionName := range []string{apiKeysCollection, apiKeyUsageCollection} {
+		if _, err := wk.FindCollectionByNameOrId(collectionName); err != nil {
+			log.Printf("⚠️  API key routes NOT registered: collection '%s' not found (migrations may not have run)", collectionName)
+			log.Printf("   Run 'go run . migrate up' to create required collections")
+			return
+		}
+	}
+
+	// The builders are served by a standalone server, as for the demo routes
+	linkSvr, err := server.New("8090")
+	if err != nil {
+		log.Printf("⚠️  API key routes NOT registered: %v", err)
+		return
+	}
+	mux := linkSvr.GetMux()
+
+	gate := newAPIKeyGate(wk)
+	clearCache := func(e *core.RecordEvent) error {
+		gate.clear()
+		return e.Next()
+	}
+	wk.OnRecordAfterUpdateSuccess(apiKeysCollection).BindFunc(clearCache)
+	wk.OnRecordAfterDeleteSuccess(apiKeysCollection).BindFunc(clearCache)
+
+	// Write usage once a minute and on shutdown
+	wk.Cron().MustAdd(apiKeyUsageFlushJob, "* * * * *", gate.flush)
+	wk.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
+		gate.flush()
+		return e.Next()
+	})
+
+	links := 0
+	for _, p := range server.DefaultPlatforms() {
+		if p.Build == nil {
+			continue
+		}
+		path := "/api/links" + p.Path()
+		e.Router.POST(path, linkHandler(mux, server.APIPrefix+p.Path(), p.Title+" API")).BindFunc(gate.middleware)
+		registry.Register("API Keys", path, "POST", "Generate a "+p.Title+" "+p.SuccessLabel+" ("+APIKeyHeader+" header)", false)
+		links++
+	}
+	log.Printf("✅ API key routes: %d link builder(s), %.4g req/s per key by default", links, gate.defaultRPS)
+
+	handler := NewRouteHandler(registry, "API Keys", e)
+
+	handler.GET("/api/keys", func(c *core.RequestEvent) error {
+		if !c.HasSuperuserAuth() {
+			return problemJSON(c, ProblemForbidden, "Superuser required")
+		}
+		records, err := wk.FindAllRecords(apiKeysCollection, dbx.HashExp{"tenant": tenantSlug(TenantFromRequest(c))})
+		if err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to list API keys", err)
+		}
+		keys := make([]*APIKey, 0, len(records))
+		for _, record := range records {
+			keys = append(keys, apiKeyFromRecord(record))
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"keys": keys, "count": len(keys)})
+	}, WithAuth(), WithDescription("List API keys of the tenant (superuser)"))
+
+	handler.POST("/api/keys", func(c *core.RequestEvent) error {
+		if !c.HasSuperuserAuth() {
+			return problemJSON(c, ProblemForbidden, "Superuser required")
+		}
+		var req struct {
+			Name      string  `json:"name"`
+			RateLimit float64 `json:"rate_limit"`
+		}
+		if err := c.BindBody(&req); err != nil || req.Name == "" || req.RateLimit < 0 {
+			return problemJSON(c, ProblemBadRequest, "Body must be {\"name\": ..., \"rate_limit\": <req/s, optional>}")
+		}
+
+		key, hash, err := GenerateAPIKey()
+		if err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to generate API key", err)
+		}
+		collection, err := wk.FindCollectionByNameOrId(apiKeysCollection)
+		if err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to create API key", err)
+		}
+		record := core.NewRecord(collection)
+		record.Set("name", req.Name)
+		record.Set("key_hash", hash)
+		record.Set("prefix", key[:apiKeyPrefixLen])
+		record.Set("tenant", tenantSlug(TenantFromRequest(c)))
+		record.Set("rate_limit", req.RateLimit)
+		record.Set("enabled", true)
+		if err := wk.Save(record); err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to create API key", err)
+		}
+
+		// The only time the key is returned
+		return c.JSON(http.StatusCreated, struct {
+			*APIKey
+			Key string `json:"key"`
+		}{apiKeyFromRecord(record), key})
+	}, WithAuth(), WithDescription("Issue an API key; the response holds the key, shown once (superuser)"))
+
+	handler.DELETE("/api/keys/{id}", func(c *core.RequestEvent) error {
+		if !c.HasSuperuserAuth() {
+			return problemJSON(c, ProblemForbidden, "Superuser required")
+		}
+		record, err := wk.FindRecordById(apiKeysCollection, c.Request.PathValue("id"))
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && record.GetString("tenant") != tenantSlug(TenantFromRequest(c))) {
+			return problemJSON(c, ProblemNotFound, "API key not found")
+		}
+		if err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to find API key", err)
+		}
+		// Revoke rather than delete, so the key's usage history is kept
+		record.Set("enabled", false)
+		if err := wk.Save(record); err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to revoke API key", err)
+		}
+		return c.JSON(http.StatusOK, apiKeyFromRecord(record))
+	}, WithAuth(), WithDescription("Revoke an API key (superuser)"))
+}
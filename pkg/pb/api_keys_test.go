@@ -0,0 +1,140 @@
+package wellknown
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestAPIKeyGate(t *testing.T) {
+	t.Setenv("TENANT_BASE_DOMAIN", "example.com")
+	wk := newTokenTestApp(t)
+
+	tenants, err := wk.FindCollectionByNameOrId(tenantsCollection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acme := core.NewRecord(tenants)
+	acme.Set("slug", "acme")
+	acme.Set("active", true)
+	if err := wk.Save(acme); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := wk.FindCollectionByNameOrId(apiKeysCollection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue := func(name, tenant string, rateLimit float64, enabled bool) (string, *core.Record) {
+		key, hash, err := GenerateAPIKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := core.NewRecord(keys)
+		record.Set("name", name)
+		record.Set("key_hash", hash)
+		record.Set("prefix", key[:apiKeyPrefixLen])
+		record.Set("tenant", tenant)
+		record.Set("rate_limit", rateLimit)
+		record.Set("enabled", enabled)
+		if err := wk.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		return key, record
+	}
+	defaultKey, defaultRecord := issue("Default", "", 100, true)
+	acmeKey, _ := issue("Acme", "acme", 100, true)
+	disabledKey, _ := issue("Disabled", "", 100, false)
+	slowKey, slowRecord := issue("Slow", "", 0.5, true) // Burst of one
+
+	gate := newAPIKeyGate(wk)
+	mux := testRouter(t, wk, func(e *core.ServeEvent) {
+		RegisterRequestIDMiddleware(e)
+		RegisterTenantMiddleware(wk, e)
+		e.Router.POST("/api/links/probe", func(e *core.RequestEvent) error {
+			return e.String(http.StatusOK, APIKeyFromRequest(e).Name)
+		}).BindFunc(gate.middleware)
+	})
+	post := func(host, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/api/links/probe", nil)
+		r.Host = host
+		if key != "" {
+			r.Header.Set(APIKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		return rec
+	}
+
+	for _, tt := range []struct {
+		name, host, key string
+		want            int
+	}{
+		{"missing", "example.com", "", http.StatusUnauthorized},
+		{"invalid", "example.com", "wk_nope", http.StatusUnauthorized},
+		{"disabled", "example.com", disabledKey, http.StatusUnauthorized},
+		{"valid", "example.com", defaultKey, http.StatusOK},
+		{"tenant key", "acme.example.com", acmeKey, http.StatusOK},
+		{"tenant key elsewhere", "example.com", acmeKey, http.StatusUnauthorized},
+		{"default key in a tenant", "acme.example.com", defaultKey, http.StatusUnauthorized},
+	} {
+		rec := post(tt.host, tt.key)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.name, rec.Code, tt.want, rec.Body)
+			continue
+		}
+		if tt.want != http.StatusOK {
+			if p := decodeProblem(t, rec); p.Code != ProblemUnauthenticated {
+				t.Errorf("%s: code = %s", tt.name, p.Code)
+			}
+		}
+	}
+
+	// Revoking takes effect once the cache is cleared (done by a record hook)
+	defaultRecord.Set("enabled", false)
+	if err := wk.Save(defaultRecord); err != nil {
+		t.Fatal(err)
+	}
+	gate.clear()
+	if rec := post("example.com", defaultKey); rec.Code != http.StatusUnauthorized {
+		t.Errorf("revoked: status = %d, want 401", rec.Code)
+	}
+
+	if rec := post("example.com", slowKey); rec.Code != http.StatusOK {
+		t.Fatalf("first request: status = %d", rec.Code)
+	}
+	rec := post("example.com", slowKey)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Errorf("over the limit: status = %d, Retry-After = %q, want 429 after 2s", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if p := decodeProblem(t, rec); p.Code != ProblemRateLimited {
+		t.Errorf("over the limit: code = %s", p.Code)
+	}
+
+	usage := func() (requests, limited int) {
+		record, err := wk.FindFirstRecordByFilter(apiKeyUsageCollection, "api_key = {:key} && day = {:day}",
+			dbx.Params{"key": slowRecord.Id, "day": time.Now().UTC().Format("2006-01-02")})
+		if err != nil {
+			t.Fatalf("usage of the slow key: %v", err)
+		}
+		return record.GetInt("requests"), record.GetInt("limited")
+	}
+	gate.flush()
+	if requests, limited := usage(); requests != 1 || limited != 1 {
+		t.Errorf("usage = %d requests, %d limited, want 1 and 1", requests, limited)
+	}
+
+	// A second flush adds to the day's record
+	post("example.com", slowKey)
+	gate.flush()
+	if requests, limited := usage(); requests != 1 || limited != 2 {
+		t.Errorf("usage = %d requests, %d limited, want 1 and 2", requests, limited)
+	}
+	if n, err := wk.CountRecords(apiKeyUsageCollection, dbx.HashExp{"api_key": slowRecord.Id}); err != nil || n != 1 {
+		t.Errorf("%d usage records (%v), want 1", n, err)
+	}
+}
@@ -1,8 +1,6 @@
 package wellknown
 
 import (
-	"net/http"
-
 	"github.com/pocketbase/pocketbase/core"
 )
 
@@ -11,9 +9,7 @@ import (
 func RequireAuth() func(*core.RequestEvent) error {
 	return func(c *core.RequestEvent) error {
 		if c.Auth == nil {
-			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error": "Authentication required",
-			})
+			return problemJSON(c, ProblemUnauthenticated, "Authentication required")
 		}
 		return c.Next()
 	}
@@ -34,9 +30,7 @@ func GetAuthRecord(c *core.RequestEvent) (*core.Record, bool) {
 // Returns the record and nil error if authenticated, or nil record and JSON error
 func MustGetAuthRecord(c *core.RequestEvent) (*core.Record, error) {
 	if c.Auth == nil {
-		return nil, c.JSON(http.StatusUnauthorized, map[string]string{
-			"error": "Authentication required",
-		})
+		return nil, problemJSON(c, ProblemUnauthenticated, "Authentication required")
 	}
 	return c.Auth, nil
 }
@@ -59,9 +59,7 @@ func handleListAccounts(c *core.RequestEvent, service *banking.Service) error {
 	// Get user's accounts
 	accounts, err := service.Accounts.GetUserAccounts(userRecord.Id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return internalProblem(c, ProblemInternal, "Failed to get accounts", err)
 	}
 
 	// Convert to summaries
@@ -97,16 +95,12 @@ func handleCreateAccount(c *core.RequestEvent, service *banking.Service) error {
 	// Parse request
 	var req CreateAccountRequest
 	if err := c.BindBody(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request body",
-		})
+		return problemJSON(c, ProblemBadRequest, "Invalid request body")
 	}
 
 	// Validate required fields
 	if req.AccountNumber == "" || req.AccountName == "" || req.AccountType == "" || req.Currency == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Missing required fields",
-		})
+		return problemJSON(c, ProblemBadRequest, "Missing required fields")
 	}
 
 	// Create account
@@ -119,17 +113,13 @@ func handleCreateAccount(c *core.RequestEvent, service *banking.Service) error {
 		req.InitialBalance,
 	)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return internalProblem(c, ProblemInternal, "Failed to create account", err)
 	}
 
 	// Get summary
 	summary, err := service.Accounts.GetAccountSummary(account.Id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return internalProblem(c, ProblemInternal, "Failed to get account summary", err)
 	}
 
 	return c.JSON(http.StatusCreated, summary)
@@ -142,24 +132,18 @@ func handleGetAccount(c *core.RequestEvent, service *banking.Service) error {
 
 	accountID := c.Request.PathValue("id")
 	if accountID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Account ID required",
-		})
+		return problemJSON(c, ProblemBadRequest, "Account ID required")
 	}
 
 	// Validate user owns account
 	if err := service.Accounts.ValidateAccountAccess(accountID, userRecord.Id); err != nil {
-		return c.JSON(http.StatusForbidden, map[string]string{
-			"error": "Access denied",
-		})
+		return problemJSON(c, ProblemForbidden, "Access denied")
 	}
 
 	// Get account summary
 	summary, err := service.Accounts.GetAccountSummary(accountID)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "Account not found",
-		})
+		return problemJSON(c, ProblemNotFound, "Account not found")
 	}
 
 	return c.JSON(http.StatusOK, summary)
@@ -172,24 +156,18 @@ func handleListTransactions(c *core.RequestEvent, service *banking.Service) erro
 
 	accountID := c.Request.PathValue("id")
 	if accountID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Account ID required",
-		})
+		return problemJSON(c, ProblemBadRequest, "Account ID required")
 	}
 
 	// Validate user owns account
 	if err := service.Accounts.ValidateAccountAccess(accountID, userRecord.Id); err != nil {
-		return c.JSON(http.StatusForbidden, map[string]string{
-			"error": "Access denied",
-		})
+		return problemJSON(c, ProblemForbidden, "Access denied")
 	}
 
 	// Get transactions
 	transactions, err := service.Transactions.GetAccountTransactions(accountID, 50, 0)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return internalProblem(c, ProblemInternal, "Failed to get transactions", err)
 	}
 
 	// Convert to summaries
@@ -230,23 +208,17 @@ func handleCreateTransaction(c *core.RequestEvent, service *banking.Service) err
 	// Parse request
 	var req CreateTransactionRequest
 	if err := c.BindBody(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid request body",
-		})
+		return problemJSON(c, ProblemBadRequest, "Invalid request body")
 	}
 
 	// Validate required fields
 	if req.AccountID == "" || req.TransactionType == "" || req.Amount <= 0 || req.Currency == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Missing or invalid required fields",
-		})
+		return problemJSON(c, ProblemBadRequest, "Missing or invalid required fields")
 	}
 
 	// Validate user owns account
 	if err := service.Accounts.ValidateAccountAccess(req.AccountID, userRecord.Id); err != nil {
-		return c.JSON(http.StatusForbidden, map[string]string{
-			"error": "Access denied",
-		})
+		return problemJSON(c, ProblemForbidden, "Access denied")
 	}
 
 	// Set default transaction date if not provided
@@ -268,17 +240,13 @@ func handleCreateTransaction(c *core.RequestEvent, service *banking.Service) err
 		IsPending:       req.IsPending,
 	})
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return internalProblem(c, ProblemInternal, "Failed to create transaction", err)
 	}
 
 	// Get summary
 	summary, err := service.Transactions.GetTransactionSummary(transaction.Id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return internalProblem(c, ProblemInternal, "Failed to get transaction summary", err)
 	}
 
 	return c.JSON(http.StatusCreated, summary)
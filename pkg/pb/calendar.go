@@ -3,14 +3,13 @@ package wellknown
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
 	"time"
 
-	"github.com/joeblew999/wellknown/pkg/pb/codegen/models"
+	googlecal "github.com/joeblew999/wellknown/pkg/google/calendar"
+	"github.com/joeblew999/wellknown/pkg/types"
 	"github.com/pocketbase/pocketbase/core"
-	"golang.org/x/oauth2"
 	calendar "google.golang.org/api/calendar/v3"
 	"google.golang.org/api/option"
 )
@@ -47,20 +46,20 @@ func handleListEvents(wk *Wellknown) func(e *core.RequestEvent) error {
 		userID := e.Auth.Id
 
 		// Get Google token for user
-		token, err := getGoogleToken(wk, userID)
+		tenant := TenantFromRequest(e)
+		if tenant.GoogleConfig(wk) == nil {
+			return problemJSON(e, ProblemNotConfigured, "Google OAuth is not configured for this tenant")
+		}
+		token, err := getGoogleToken(wk, tenant, userID)
 		if err != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to get Google token",
-			})
+			return googleTokenProblem(e, err)
 		}
 
 		// Create Calendar API client
-		client := wk.oauthService.GoogleConfig.Client(context.Background(), token)
+		client := tenant.GoogleConfig(wk).Client(context.Background(), token)
 		srv, err := calendar.NewService(context.Background(), option.WithHTTPClient(client))
 		if err != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to create Calendar service",
-			})
+			return internalProblem(e, ProblemInternal, "Failed to create Calendar service", err)
 		}
 
 		// List upcoming events
@@ -73,10 +72,7 @@ func handleListEvents(wk *Wellknown) func(e *core.RequestEvent) error {
 			Do()
 
 		if err != nil {
-			log.Printf("Failed to list events: %v", err)
-			return e.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to list events",
-			})
+			return internalProblem(e, ProblemUpstream, "Failed to list events", err)
 		}
 
 		return e.JSON(http.StatusOK, events.Items)
@@ -89,108 +85,56 @@ func handleCreateEvent(wk *Wellknown) func(e *core.RequestEvent) error {
 		// Auth middleware ensures e.Auth is populated
 		userID := e.Auth.Id
 
-		// Parse request body
-		var eventData struct {
-			Summary     string    `json:"summary"`
-			Description string    `json:"description"`
-			Location    string    `json:"location"`
-			StartTime   time.Time `json:"start_time"`
-			EndTime     time.Time `json:"end_time"`
-		}
-
+		// Parse request body: the same event data the deep-link generator takes
+		// (pkg/google/calendar/schema.json)
+		var eventData map[string]interface{}
 		if err := json.NewDecoder(e.Request.Body).Decode(&eventData); err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]string{
-				"error": "Invalid request body",
-			})
+			return problemJSON(e, ProblemBadRequest, "Invalid request body")
+		}
+		legacyEventFields(eventData)
+		if err := types.ValidateCalendarData("google", eventData); err != nil {
+			return validationProblem(e, err)
 		}
 
 		// Get Google token for user
-		token, err := getGoogleToken(wk, userID)
-		if err != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to get Google token",
-			})
+		tenant := TenantFromRequest(e)
+		if tenant.GoogleConfig(wk) == nil {
+			return problemJSON(e, ProblemNotConfigured, "Google OAuth is not configured for this tenant")
 		}
-
-		// Create Calendar API client
-		client := wk.oauthService.GoogleConfig.Client(context.Background(), token)
-		srv, err := calendar.NewService(context.Background(), option.WithHTTPClient(client))
+		token, err := getGoogleToken(wk, tenant, userID)
 		if err != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to create Calendar service",
-			})
+			return googleTokenProblem(e, err)
 		}
 
-		// Create event
-		event := &calendar.Event{
-			Summary:     eventData.Summary,
-			Description: eventData.Description,
-			Location:    eventData.Location,
-			Start: &calendar.EventDateTime{
-				DateTime: eventData.StartTime.Format(time.RFC3339),
-			},
-			End: &calendar.EventDateTime{
-				DateTime: eventData.EndTime.Format(time.RFC3339),
-			},
+		// Create event through the Calendar API
+		client := tenant.GoogleConfig(wk).Client(context.Background(), token)
+		createdEvent, err := googlecal.NewCalendarAPI(client).CreateEvent(e.Request.Context(), eventData)
+		if err != nil {
+			return internalProblem(e, ProblemUpstream, "Failed to create event", err)
 		}
 
-		createdEvent, err := srv.Events.Insert("primary", event).Do()
-		if err != nil {
-			log.Printf("Failed to create event: %v", err)
-			return e.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to create event",
-			})
+		// Keep a copy for reminders (see reminders.go); the event exists either way
+		if err := storeCalendarEvent(wk, tenant, userID, createdEvent); err != nil {
+			log.Printf("Warning: event %s not stored for reminders: %v", createdEvent.Id, err)
 		}
 
 		return e.JSON(http.StatusCreated, createdEvent)
 	}
 }
 
-// getGoogleToken retrieves stored OAuth token for user using type-safe proxy
-func getGoogleToken(wk *Wellknown, userID string) (*oauth2.Token, error) {
-	collection, err := wk.FindCollectionByNameOrId("google_tokens")
-	if err != nil {
-		return nil, fmt.Errorf("failed to find google_tokens collection: %w", err)
-	}
-
-	record, err := wk.FindFirstRecordByFilter(collection.Name, "user_id = {:user_id}", map[string]any{
-		"user_id": userID,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to find token for user: %w", err)
-	}
-
-	// Wrap record in type-safe proxy
-	tokenProxy, err := models.WrapRecord[models.GoogleTokens](record)
-	if err != nil {
-		return nil, fmt.Errorf("failed to wrap record as GoogleTokens: %w", err)
-	}
-
-	// Parse expiry using type-safe getter
-	expiry := tokenProxy.Expiry().Time()
-
-	token := &oauth2.Token{
-		AccessToken:  tokenProxy.AccessToken(),
-		RefreshToken: tokenProxy.RefreshToken(),
-		TokenType:    tokenProxy.TokenType(),
-		Expiry:       expiry,
-	}
-
-	// Check if token needs refresh
-	if time.Now().After(token.Expiry) {
-		// Token expired, refresh it
-		newToken, err := wk.oauthService.GoogleConfig.TokenSource(context.Background(), token).Token()
-		if err != nil {
-			return nil, fmt.Errorf("failed to refresh token: %w", err)
-		}
-
-		// Update stored token
-		if err := storeGoogleToken(wk, userID, newToken); err != nil {
-			log.Printf("Warning: failed to update refreshed token: %v", err)
+// legacyEventFields maps the original request fields (summary, start_time,
+// end_time) onto the schema field names
+func legacyEventFields(data map[string]interface{}) {
+	for legacy, field := range map[string]string{
+		"summary":    googlecal.FieldTitle,
+		"start_time": googlecal.FieldStart,
+		"end_time":   googlecal.FieldEnd,
+	} {
+		if v, ok := data[legacy]; ok {
+			if _, exists := data[field]; !exists {
+				data[field] = v
+			}
+			delete(data, legacy)
 		}
-
-		return newToken, nil
 	}
-
-	return token, nil
 }
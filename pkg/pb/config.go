@@ -3,8 +3,7 @@ package wellknown
 import (
 	"fmt"
 
-	"github.com/joho/godotenv"
-	"github.com/pocketbase/pocketbase/tools/osutils"
+	"github.com/joeblew999/wellknown/pkg/env"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
@@ -64,36 +63,38 @@ type AnthropicConfig struct {
 }
 
 // LoadConfig loads configuration from environment variables using the env registry
-// In development (go run), it will try to load .env file first
+// (.env files are loaded beforehand by LoadEnvironment)
 func LoadConfig() (*Config, error) {
-	// PocketBase best practice: Only load .env in development
-	// In production, use environment variables or command-line flags
-	if osutils.IsProbablyGoRun() {
-		// Try to load .env file, but don't fail if it doesn't exist
-		_ = godotenv.Load()
+	base, err := EnvRegistry.NewOverlay(nil)
+	if err != nil {
+		return nil, err
 	}
+	return LoadConfigFrom(base)
+}
 
-	// Load from env registry (single source of truth)
+// LoadConfigFrom loads configuration through an env overlay, e.g. a tenant's
+// overrides (see Tenant.Config)
+func LoadConfigFrom(src *env.Overlay) (*Config, error) {
 	cfg := &Config{
 		Server: ServerConfig{
-			Host: EnvRegistry.ByName("SERVER_HOST").GetString(),
-			Port: EnvRegistry.ByName("SERVER_PORT").GetInt(),
+			Host: src.GetString("SERVER_HOST"),
+			Port: src.GetInt("SERVER_PORT"),
 		},
 		OAuth: OAuthConfig{
 			Google: GoogleOAuthConfig{
-				ClientID:     EnvRegistry.ByName("GOOGLE_CLIENT_ID").GetString(),
-				ClientSecret: EnvRegistry.ByName("GOOGLE_CLIENT_SECRET").GetString(),
-				RedirectURL:  EnvRegistry.ByName("GOOGLE_REDIRECT_URL").GetString(),
+				ClientID:     src.GetString("GOOGLE_CLIENT_ID"),
+				ClientSecret: src.GetString("GOOGLE_CLIENT_SECRET"),
+				RedirectURL:  src.GetString("GOOGLE_REDIRECT_URL"),
 			},
 		},
 		Database: DatabaseConfig{
-			DataDir: EnvRegistry.ByName("PB_DATA_DIR").GetString(),
+			DataDir: src.GetString("PB_DATA_DIR"),
 		},
 		AI: AIConfig{
 			Anthropic: AnthropicConfig{
-				UseOAuth: EnvRegistry.ByName("AI_USE_OAUTH").GetBool(),
-				APIKey:   EnvRegistry.ByName("ANTHROPIC_API_KEY").GetString(),
-				Model:    EnvRegistry.ByName("ANTHROPIC_MODEL").GetString(),
+				UseOAuth: src.GetBool("AI_USE_OAUTH"),
+				APIKey:   src.GetString("ANTHROPIC_API_KEY"),
+				Model:    src.GetString("ANTHROPIC_MODEL"),
 			},
 		},
 	}
@@ -2,6 +2,7 @@ package wellknown
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strings"
 
@@ -23,9 +24,41 @@ func GetVarsByGroup() map[string][]env.EnvVar {
 	return EnvRegistry.GetByGroup()
 }
 
-// ValidateEnv checks if all required environment variables are set
+// LoadEnvironment loads .env < .env.local < .env.$APP_ENV into the process
+// environment (real env vars always win), warns about values that don't parse
+// as their registry type, and logs where each registry variable came from.
+//
+// It is the only place .env files are loaded. Required variables are checked
+// by ValidateEnv when the server starts, so utility commands (env, mcp, help)
+// work without credentials.
+func LoadEnvironment() (*env.ChainResult, error) {
+	result, err := env.LoadChain(env.DefaultChainFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load .env files: %w", err)
+	}
+
+	if len(result.Files) > 0 {
+		log.Printf("🔧 Environment: loaded %s", strings.Join(result.Files, " < "))
+		for _, v := range EnvRegistry.All() {
+			if _, ok := result.Sources[v.Name]; ok {
+				log.Printf("   %s", result.Explain(v.Name))
+			}
+		}
+	}
+
+	if err := EnvRegistry.ValidateTypes(); err != nil {
+		log.Printf("⚠️  %v", err)
+	}
+	return result, nil
+}
+
+// ValidateEnv checks that all required environment variables are set and
+// that typed variables parse
 func ValidateEnv() error {
-	return EnvRegistry.ValidateRequired()
+	if err := EnvRegistry.ValidateRequired(); err != nil {
+		return err
+	}
+	return EnvRegistry.ValidateTypes()
 }
 
 // ExportSecretsFormat outputs secret environment variables in NAME=VALUE format for flyctl secrets import
@@ -133,10 +166,12 @@ func GenerateEnvLocal() string {
 			"Google OAuth",
 			"HTTPS (Development)",
 			"Server",
+			"API Keys",
 			"AI",
 			"Apple OAuth",
 			"PocketBase Admin",
 			"SMTP",
+			"Reminders",
 			"S3",
 			"Deployment",
 			"Binary Update",
@@ -181,10 +216,12 @@ func GenerateEnvProduction() string {
 		GroupOrder: []string{
 			"Google OAuth",
 			"HTTPS (Development)", // Special note for production
+			"HTTPS (ACME)",        // Self-hosting outside Fly.io
 			"AI",
 			"Apple OAuth",
 			"PocketBase Admin",
 			"SMTP",
+			"Reminders",
 			"S3",
 		},
 		ValueOverrides: func(v env.EnvVar) (string, bool) {
@@ -252,7 +289,10 @@ func MergeSecretsIntoEnv(secretsPath, templateType, outputPath string) error {
 	}
 
 	// 3. Merge secrets into template
-	mergedContent := env.MergeIntoTemplate(template, secrets)
+	mergedContent, err := env.MergeIntoTemplate(template, secrets)
+	if err != nil {
+		return err
+	}
 
 	// 4. Write merged content to output file
 	if err := os.WriteFile(outputPath, []byte(mergedContent), 0600); err != nil {
@@ -1,7 +1,10 @@
 package wellknown
 
 import (
+	"fmt"
+
 	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/guard"
 )
 
 // AllEnvVars is the single source of truth for all environment variables in this PocketBase application.
@@ -29,9 +32,15 @@ var AllEnvVars = []env.EnvVar{
 	{
 		Name:        "PB_DATA_DIR",
 		Description: "PocketBase data directory",
+		Kind:        env.KindPath,
 		Default:     ".data/pb",
 		Group:       "Server",
 	},
+	{
+		Name:        "TENANT_BASE_DOMAIN",
+		Description: "Domain whose subdomains select a tenant (e.g. wellknown.example.com: acme.wellknown.example.com -> tenant acme); the X-Tenant header always works",
+		Group:       "Server",
+	},
 
 	// ================================================================
 	// Google OAuth (REQUIRED secrets)
@@ -53,10 +62,24 @@ var AllEnvVars = []env.EnvVar{
 	{
 		Name:        "GOOGLE_REDIRECT_URL",
 		Description: "Google OAuth callback URL",
+		Kind:        env.KindURL,
 		Required:    true,
 		Secret:      true,
 		Group:       "Google OAuth",
 	},
+	{
+		Name:        "GCP_PROJECT_ID",
+		Description: "Google Cloud project the OAuth client belongs to (set by 'wellknown setup gcp')",
+		Secret:      true,
+		Group:       "Google OAuth",
+	},
+	{
+		Name:        "GOOGLE_TOKEN_KEY",
+		Description: "32-character key stored Google tokens are encrypted with (defaults to the PocketBase --encryptionEnv key; unencrypted without either)",
+		Secret:      true,
+		Group:       "Google OAuth",
+		Validate:    validateTokenKey,
+	},
 
 	// ================================================================
 	// Apple OAuth (OPTIONAL secrets)
@@ -88,12 +111,14 @@ var AllEnvVars = []env.EnvVar{
 	{
 		Name:        "APPLE_PRIVATE_KEY_PATH",
 		Description: "Path to Apple private key file (alternative to inline)",
+		Kind:        env.KindPath,
 		Secret:      true,
 		Group:       "Apple OAuth",
 	},
 	{
 		Name:        "APPLE_REDIRECT_URL",
 		Description: "Apple OAuth callback URL",
+		Kind:        env.KindURL,
 		Secret:      true,
 		Group:       "Apple OAuth",
 	},
@@ -178,6 +203,11 @@ var AllEnvVars = []env.EnvVar{
 		Secret:      true,
 		Group:       "SMTP",
 	},
+	{
+		Name:        "PDF_DELIVERY_TO",
+		Description: "Default recipients for emailed PDF forms (comma-separated)",
+		Group:       "SMTP",
+	},
 
 	// ================================================================
 	// S3 Storage (OPTIONAL secrets)
@@ -226,12 +256,74 @@ var AllEnvVars = []env.EnvVar{
 		Group:       "S3",
 	},
 
+	// ================================================================
+	// API Keys (public link-generation API)
+	// ================================================================
+	{
+		Name:        "API_KEY_RATE_LIMIT_RPS",
+		Description: "Requests per second allowed per API key on /api/links/* (keys may set their own rate_limit)",
+		Default:     "2",
+		Group:       "API Keys",
+	},
+
+	// ================================================================
+	// Event Reminders (email via SMTP, optional web push)
+	// ================================================================
+	{
+		Name:        "REMINDER_LEAD_MINUTES",
+		Description: "Minutes before an event its reminder is sent, unless the event sets remind_before",
+		Default:     "30",
+		Group:       "Reminders",
+	},
+	{
+		Name:        "VAPID_PUBLIC_KEY",
+		Description: "Web push VAPID public key (base64url); web push is off without the key pair",
+		Group:       "Reminders",
+	},
+	{
+		Name:        "VAPID_PRIVATE_KEY",
+		Description: "Web push VAPID private key (base64url)",
+		Secret:      true,
+		Group:       "Reminders",
+	},
+	{
+		Name:        "VAPID_SUBJECT",
+		Description: "Contact push services can reach the sender at (mailto: or https: URL)",
+		Default:     "mailto:admin@example.com",
+		Group:       "Reminders",
+	},
+
+	// ================================================================
+	// Schema Drift (live collections vs x-collection JSON Schemas)
+	// ================================================================
+	{
+		Name:        "SCHEMA_DRIFT_CHECK",
+		Description: "Startup check of collections against their JSON Schemas: 'warn' logs drift, 'fail' stops the server (also when it finds no schemas), 'off' skips it",
+		Default:     SchemaDriftWarn,
+		Group:       "Schema",
+		Validate: func(value string) error {
+			switch value {
+			case SchemaDriftWarn, SchemaDriftFail, SchemaDriftOff:
+				return nil
+			}
+			return fmt.Errorf("must be %s, %s or %s", SchemaDriftWarn, SchemaDriftFail, SchemaDriftOff)
+		},
+	},
+	{
+		Name:        "SCHEMA_ROOT",
+		Description: "Directory searched for the JSON Schemas the drift check compares against",
+		Kind:        env.KindPath,
+		Default:     ".",
+		Group:       "Schema",
+	},
+
 	// ================================================================
 	// Deployment Configuration (OPTIONAL)
 	// ================================================================
 	{
 		Name:        "APP_URL",
 		Description: "Application URL (for production deployments)",
+		Kind:        env.KindURL,
 		Group:       "Deployment",
 	},
 	{
@@ -246,19 +338,21 @@ var AllEnvVars = []env.EnvVar{
 	// ================================================================
 	{
 		Name:        "HTTPS_ENABLED",
-		Description: "Enable HTTPS with custom certificates (development only, use mkcert)",
+		Description: "Enable HTTPS with mkcert certificates (development), or Let's Encrypt when ACME_DOMAINS is set",
 		Default:     "false",
 		Group:       "HTTPS (Development)",
 	},
 	{
 		Name:        "CERT_FILE",
 		Description: "Path to SSL certificate file",
+		Kind:        env.KindPath,
 		Default:     ".data/certs/cert.pem",
 		Group:       "HTTPS (Development)",
 	},
 	{
 		Name:        "KEY_FILE",
 		Description: "Path to SSL private key file",
+		Kind:        env.KindPath,
 		Default:     ".data/certs/key.pem",
 		Group:       "HTTPS (Development)",
 	},
@@ -269,6 +363,27 @@ var AllEnvVars = []env.EnvVar{
 		Group:       "HTTPS (Development)",
 	},
 
+	// ================================================================
+	// HTTPS/TLS via ACME (self-hosted deployments outside Fly.io)
+	// ================================================================
+	{
+		Name:        "ACME_DOMAINS",
+		Description: "Comma-separated domains to obtain Let's Encrypt certificates for; with HTTPS_ENABLED=true this replaces CERT_FILE/KEY_FILE (serve with --https=0.0.0.0:443)",
+		Group:       "HTTPS (ACME)",
+	},
+	{
+		Name:        "ACME_EMAIL",
+		Description: "Contact email for the Let's Encrypt account (expiry notices)",
+		Group:       "HTTPS (ACME)",
+	},
+	{
+		Name:        "ACME_CACHE_DIR",
+		Description: "Directory where ACME account keys and certificates are cached",
+		Kind:        env.KindPath,
+		Default:     ".data/autocert",
+		Group:       "HTTPS (ACME)",
+	},
+
 	// ================================================================
 	// Binary Update Configuration (Development/Testing)
 	// ================================================================
@@ -281,11 +396,25 @@ var AllEnvVars = []env.EnvVar{
 	{
 		Name:        "UPDATE_LOCAL_DIR",
 		Description: "Local directory for binary updates when UPDATE_SOURCE=local",
+		Kind:        env.KindPath,
 		Default:     ".dist",
 		Group:       "Binary Update",
 	},
 }
 
+// EnvPolicies constrain values per environment; env verify checks them
+// against .env.production and /env against the running server
+var EnvPolicies = []env.Policy{
+	{Environments: []string{"production"}, Variable: "APP_URL", Op: env.PolicyMatches, Value: "^(https://|$)", Message: "must be an https:// URL when set"},
+}
+
 // EnvRegistry is the global environment variable registry for this application.
 // It provides O(1) lookups and filtering capabilities.
-var EnvRegistry = env.NewRegistry(AllEnvVars)
+var EnvRegistry = env.NewRegistry(AllEnvVars, env.Policies(EnvPolicies...))
+
+func init() {
+	// CORS_ORIGINS and RATE_LIMIT_RPS protect the custom JSON routes
+	if err := EnvRegistry.Add(guard.EnvVars...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,343 @@
+package wellknown
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	cal "github.com/joeblew999/wellknown/pkg/calendar"
+	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/joeblew999/wellknown/pkg/types"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ================================================================
+// Calendar Event Import/Export (CSV, ICS)
+// ================================================================
+// Onboarding an existing calendar: events are read with pkg/calendar's CSV and
+// ICS readers, validated against the Google Calendar schema (pkg/schema) like
+// events created through the API, and saved to calendar_events, where the
+// reminder worker picks them up. Events with a UID update the event imported
+// from the same UID, so a file can be re-imported after fixing its errors.
+//
+// Served at POST /api/calendar/events/import and GET .../export, and by the
+// "events import" / "events export" commands (pkg/cmd/events).
+
+// EventFormat is a bulk import/export file format
+type EventFormat string
+
+const (
+	EventFormatCSV EventFormat = "csv"
+	EventFormatICS EventFormat = "ics"
+)
+
+// maxEventImportSize caps uploaded import files
+const maxEventImportSize = 10 << 20
+
+// ParseEventFormat returns the format named by format, else the one implied by
+// a file name's extension or a media type
+func ParseEventFormat(format, filenameOrType string) (EventFormat, error) {
+	if format == "" {
+		switch ext := strings.ToLower(filepath.Ext(filenameOrType)); {
+		case ext == ".csv" || strings.HasPrefix(filenameOrType, "text/csv"):
+			format = string(EventFormatCSV)
+		case ext == ".ics" || ext == ".ical" || strings.HasPrefix(filenameOrType, "text/calendar"):
+			format = string(EventFormatICS)
+		}
+	}
+	switch f := EventFormat(strings.ToLower(format)); f {
+	case EventFormatCSV, EventFormatICS:
+		return f, nil
+	case "":
+		return "", errors.New("format unknown: use csv or ics")
+	default:
+		return "", fmt.Errorf("unsupported format %q: use csv or ics", format)
+	}
+}
+
+// EventImportOptions configures ImportEvents
+type EventImportOptions struct {
+	UserID   string                    // Owner of the imported events
+	Tenant   string                    // Tenant slug ("" for the default tenant)
+	DryRun   bool                      // Validate only
+	Progress func(EventImportProgress) // Called after each event (optional)
+}
+
+// EventImportError is an event that was not imported
+type EventImportError struct {
+	Row    int               `json:"row"` // CSV data row or VEVENT number
+	Title  string            `json:"title,omitempty"`
+	Errors map[string]string `json:"errors"` // Field -> message ("" for the whole event)
+}
+
+// EventImportResult summarises an import
+type EventImportResult struct {
+	Total    int                `json:"total"`
+	Imported int                `json:"imported"` // New events
+	Updated  int                `json:"updated"`  // Events matched by UID
+	Skipped  int                `json:"skipped"`
+	Errors   []EventImportError `json:"errors,omitempty"`
+	DryRun   bool               `json:"dry_run,omitempty"`
+}
+
+// EventImportProgress reports an import after each event
+type EventImportProgress struct {
+	Row      int               `json:"row"`
+	Total    int               `json:"total"`
+	Imported int               `json:"imported"`
+	Updated  int               `json:"updated"`
+	Skipped  int               `json:"skipped"`
+	Error    *EventImportError `json:"error,omitempty"` // Set when this event was skipped
+}
+
+// readEvents parses r in format
+func readEvents(r io.Reader, format EventFormat) ([]cal.ImportedEvent, error) {
+	if format == EventFormatICS {
+		return cal.ReadICS(r)
+	}
+	return cal.ReadCSV(r)
+}
+
+// eventValidationData is the schema form data of an imported event
+func eventValidationData(e cal.EventData) map[string]interface{} {
+	data := map[string]interface{}{
+		cal.FieldTitle: e.Title,
+		cal.FieldStart: e.Start.UTC().Format(cal.DateTimeLocalFormat),
+		cal.FieldEnd:   e.End.UTC().Format(cal.DateTimeLocalFormat),
+	}
+	if e.Location != "" {
+		data[cal.FieldLocation] = e.Location
+	}
+	if e.Description != "" {
+		data[cal.FieldDescription] = e.Description
+	}
+	return data
+}
+
+// ImportEvents imports events from r into calendar_events. Invalid events are
+// skipped and reported; the error is for unreadable input or a failed save.
+func ImportEvents(app core.App, r io.Reader, format EventFormat, opts EventImportOptions) (*EventImportResult, error) {
+	if opts.UserID == "" {
+		return nil, errors.New("a user is required")
+	}
+	collection, err := app.FindCollectionByNameOrId(calendarEventsCollection)
+	if err != nil {
+		return nil, fmt.Errorf("collection '%s' not found (migrations may not have run): %w", calendarEventsCollection, err)
+	}
+	_, compiledSchema, validator, err := schema.LoadSchemasForRendering("google", "calendar")
+	if err != nil {
+		return nil, err
+	}
+	events, err := readEvents(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EventImportResult{Total: len(events), DryRun: opts.DryRun}
+	for _, e := range events {
+		updated, importErr := importEvent(app, collection, validator, compiledSchema, e, opts)
+		switch {
+		case importErr != nil:
+			result.Skipped++
+			result.Errors = append(result.Errors, *importErr)
+		case updated:
+			result.Updated++
+		default:
+			result.Imported++
+		}
+		if opts.Progress != nil {
+			opts.Progress(EventImportProgress{
+				Row: e.Row, Total: result.Total,
+				Imported: result.Imported, Updated: result.Updated, Skipped: result.Skipped,
+				Error: importErr,
+			})
+		}
+	}
+	return result, nil
+}
+
+// importEvent validates and saves one event; updated reports a UID match
+func importEvent(app core.App, collection *core.Collection, validator *schema.ValidatorV6, compiledSchema *jsonschema.Schema, e cal.ImportedEvent, opts EventImportOptions) (updated bool, importErr *EventImportError) {
+	fail := func(errs map[string]string) *EventImportError {
+		return &EventImportError{Row: e.Row, Title: e.Title, Errors: errs}
+	}
+	if e.Err != nil {
+		return false, fail(map[string]string{"": e.Err.Error()})
+	}
+
+	data := eventValidationData(e.EventData)
+	errs := validator.Validate(data, compiledSchema)
+	if verr, ok := types.ValidateCalendarData("google", data).(*types.ValidationError); ok {
+		for field, msg := range verr.Fields() {
+			if _, exists := errs[field]; !exists {
+				errs[field] = msg
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return false, fail(errs)
+	}
+	record := findImportedEvent(app, opts, e.UID)
+	if opts.DryRun {
+		return record != nil, nil
+	}
+	if updated = record != nil; !updated {
+		record = core.NewRecord(collection)
+		record.Set("user_id", opts.UserID)
+		record.Set("tenant", opts.Tenant)
+		record.Set("uid", e.UID)
+	}
+	record.Set("title", e.Title)
+	record.Set("start", e.Start.UTC())
+	record.Set("end", e.End.UTC())
+	record.Set("all_day", e.AllDay)
+	record.Set("location", e.Location)
+	record.Set("description", e.Description)
+	if e.URL != "" {
+		record.Set("html_link", e.URL)
+	}
+	scheduleReminder(record)
+	if err := app.Save(record); err != nil {
+		return false, fail(map[string]string{"": err.Error()})
+	}
+	return updated, nil
+}
+
+// findImportedEvent returns the user's event imported with uid, if any
+func findImportedEvent(app core.App, opts EventImportOptions, uid string) *core.Record {
+	if uid == "" {
+		return nil
+	}
+	records, err := app.FindAllRecords(calendarEventsCollection, dbx.HashExp{
+		"user_id": opts.UserID,
+		"tenant":  opts.Tenant,
+		"uid":     uid,
+	})
+	if err != nil || len(records) == 0 {
+		return nil
+	}
+	return records[0]
+}
+
+// ExportEvents writes a user's events in format, ordered by start, and
+// returns how many were written
+func ExportEvents(app core.App, w io.Writer, format EventFormat, userID, tenant string) (int, error) {
+	records, err := app.FindRecordsByFilter(calendarEventsCollection,
+		"user_id = {:user} && tenant = {:tenant}", "start", 0, 0,
+		dbx.Params{"user": userID, "tenant": tenant})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to load events: %w", err)
+	}
+
+	events := make([]cal.EventData, 0, len(records))
+	for _, record := range records {
+		uid := record.GetString("uid")
+		if uid == "" {
+			uid = record.Id + "@wellknown"
+		}
+		events = append(events, cal.EventData{
+			UID:         uid,
+			Title:       record.GetString("title"),
+			Start:       record.GetDateTime("start").Time(),
+			End:         record.GetDateTime("end").Time(),
+			AllDay:      record.GetBool("all_day"),
+			Location:    record.GetString("location"),
+			Description: record.GetString("description"),
+			URL:         record.GetString("html_link"),
+		})
+	}
+
+	if format == EventFormatICS {
+		err = cal.WriteICS(w, events)
+	} else {
+		err = cal.WriteCSV(w, events)
+	}
+	return len(events), err
+}
+
+// RegisterEventTransferRoutes registers the calendar import/export endpoints
+func RegisterEventTransferRoutes(wk *Wellknown, e *core.ServeEvent, registry *RouteRegistry) {
+	if _, err := wk.FindCollectionByNameOrId(calendarEventsCollection); err != nil {
+		log.Printf("⚠️  Event import/export NOT registered: collection '%s' not found (migrations may not have run)", calendarEventsCollection)
+		return
+	}
+
+	handler := NewRouteHandler(registry, "Calendar", e)
+
+	handler.POST("/api/calendar/events/import", func(c *core.RequestEvent) error {
+		body := io.Reader(http.MaxBytesReader(c.Response, c.Request.Body, maxEventImportSize))
+		name := c.Request.Header.Get("Content-Type")
+		if mediaType, _, _ := mime.ParseMediaType(name); mediaType == "multipart/form-data" {
+			file, header, err := c.Request.FormFile("file")
+			if err != nil {
+				return problemJSON(c, ProblemBadRequest, "Upload the file as the 'file' form field")
+			}
+			defer file.Close()
+			body, name = file, header.Filename
+		}
+		format, err := ParseEventFormat(c.Request.URL.Query().Get("format"), name)
+		if err != nil {
+			return problemJSON(c, ProblemBadRequest, err.Error())
+		}
+		opts := EventImportOptions{
+			UserID: c.Auth.Id,
+			Tenant: tenantSlug(TenantFromRequest(c)),
+			DryRun: c.Request.URL.Query().Get("dry_run") == "true",
+		}
+
+		// Progress as Server-Sent Events when asked for, else one JSON result
+		flusher, stream := c.Response.(http.Flusher)
+		stream = stream && strings.Contains(c.Request.Header.Get("Accept"), "text/event-stream")
+		if stream {
+			c.Response.Header().Set("Content-Type", "text/event-stream")
+			c.Response.Header().Set("Cache-Control", "no-cache")
+			opts.Progress = func(p EventImportProgress) {
+				data, _ := json.Marshal(p)
+				fmt.Fprintf(c.Response, "event: progress\ndata: %s\n\n", data)
+				flusher.Flush()
+			}
+		}
+
+		result, err := ImportEvents(wk, body, format, opts)
+		if stream {
+			event, payload := "done", interface{}(result)
+			if err != nil {
+				event, payload = "error", map[string]string{"error": err.Error()}
+			}
+			data, _ := json.Marshal(payload)
+			fmt.Fprintf(c.Response, "event: %s\ndata: %s\n\n", event, data)
+			flusher.Flush()
+			return nil
+		}
+		if err != nil {
+			return problemJSON(c, ProblemBadRequest, err.Error())
+		}
+		return c.JSON(http.StatusOK, result)
+	}, WithAuth(), WithDescription("Import events from CSV or ICS (?format=, ?dry_run=true; Accept: text/event-stream for progress)"))
+
+	handler.GET("/api/calendar/events/export", func(c *core.RequestEvent) error {
+		format, err := ParseEventFormat(c.Request.URL.Query().Get("format"), "")
+		if err != nil {
+			format = EventFormatICS
+		}
+		contentType := "text/calendar; charset=utf-8"
+		if format == EventFormatCSV {
+			contentType = "text/csv; charset=utf-8"
+		}
+		c.Response.Header().Set("Content-Type", contentType)
+		c.Response.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="events.%s"`, format))
+		if _, err := ExportEvents(wk, c.Response, format, c.Auth.Id, tenantSlug(TenantFromRequest(c))); err != nil {
+			log.Printf("⚠️  Event export failed: %v", err)
+		}
+		return nil
+	}, WithAuth(), WithDescription("Export your events as ICS (default) or CSV (?format=csv)"))
+}
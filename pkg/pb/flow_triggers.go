@@ -0,0 +1,157 @@
+package wellknown
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/joeblew999/wellknown/pkg/flow"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/cron"
+)
+
+// ================================================================
+// Flow Triggers (webhooks + cron)
+// ================================================================
+// Triggers are records in the flow_triggers collection (see
+// pb_migrations/1730900100_init_flow_triggers.go), edited in the admin UI.
+// Cron triggers are (re)scheduled whenever a record changes, so e.g. a nightly
+// flow that refreshes the PDF catalog needs no redeploy to change its time.
+// Webhook triggers are served at POST /api/flows/{flow}/hooks/{trigger} and
+// authenticated by an HMAC-SHA256 of the body (flow.SignatureHeader). A
+// trigger's runs belong to its tenant, so its webhook must be called with that
+// tenant's subdomain or X-Tenant header.
+
+const flowTriggersCollection = "flow_triggers"
+
+// triggerFromRecord converts a flow_triggers record
+func triggerFromRecord(record *core.Record) (*flow.Trigger, error) {
+	t := &flow.Trigger{
+		ID:       record.Id,
+		FlowID:   record.GetString("flow_id"),
+		Kind:     flow.TriggerKind(record.GetString("kind")),
+		Schedule: record.GetString("schedule"),
+		Secret:   record.GetString("secret"),
+		Step:     record.GetString("step"),
+		Enabled:  record.GetBool("enabled"),
+	}
+	if err := record.UnmarshalJSONField("inputs", &t.Inputs); err != nil {
+		return nil, fmt.Errorf("trigger %s: invalid inputs: %w", record.Id, err)
+	}
+	return t, nil
+}
+
+// flowTriggerJobID is the cron job ID of a trigger
+func flowTriggerJobID(triggerID string) string {
+	return "flow_trigger_" + triggerID
+}
+
+// scheduleFlowTrigger adds, replaces or removes the cron job of a trigger,
+// whose runs belong to the trigger's tenant
+func scheduleFlowTrigger(wk *Wellknown, t *flow.Trigger, tenant string) error {
+	jobID := flowTriggerJobID(t.ID)
+	runner, ok := wk.flowRunner(t.FlowID, tenant)
+	if t.Kind != flow.TriggerCron || !t.Enabled || !ok {
+		wk.Cron().Remove(jobID)
+		return nil
+	}
+
+	return wk.Cron().Add(jobID, t.Schedule, func() {
+		run, err := runner.Fire(context.Background(), t, nil)
+		if err != nil {
+			log.Printf("⚠️  Cron trigger %s (flow %s) failed: %v", t.ID, t.FlowID, err)
+			return
+		}
+		log.Printf("⏰ Cron trigger %s started run %s of flow %s (%s)", t.ID, run.ID, t.FlowID, run.Status)
+	})
+}
+
+// RegisterFlowTriggers schedules cron triggers, keeps them in sync with the
+// flow_triggers collection and registers the webhook endpoint
+func RegisterFlowTriggers(wk *Wellknown, e *core.ServeEvent, registry *RouteRegistry) {
+	if _, err := wk.FindCollectionByNameOrId(flowTriggersCollection); err != nil {
+		log.Printf("⚠️  Flow triggers NOT registered: collection '%s' not found (migrations may not have run)", flowTriggersCollection)
+		return
+	}
+
+	records, err := wk.FindAllRecords(flowTriggersCollection)
+	if err != nil {
+		log.Printf("⚠️  Failed to load flow triggers: %v", err)
+		return
+	}
+	scheduled := 0
+	for _, record := range records {
+		t, err := triggerFromRecord(record)
+		if err == nil {
+			err = scheduleFlowTrigger(wk, t, record.GetString("tenant"))
+		}
+		if err != nil {
+			log.Printf("⚠️  Flow trigger %s not scheduled: %v", record.Id, err)
+			continue
+		}
+		if t.Kind == flow.TriggerCron && t.Enabled {
+			scheduled++
+		}
+	}
+	log.Printf("✅ Flow triggers: %d cron job(s) scheduled", scheduled)
+
+	// Reject triggers that could never fire
+	wk.OnRecordValidate(flowTriggersCollection).BindFunc(func(e *core.RecordEvent) error {
+		t, err := triggerFromRecord(e.Record)
+		if err != nil {
+			return err
+		}
+		runner, ok := wk.flows[t.FlowID]
+		if !ok {
+			return fmt.Errorf("unknown flow %q", t.FlowID)
+		}
+		if err := t.Validate(runner.Flow); err != nil {
+			return err
+		}
+		if t.Kind == flow.TriggerCron {
+			if _, err := cron.NewSchedule(t.Schedule); err != nil {
+				return fmt.Errorf("invalid schedule: %w", err)
+			}
+		}
+		return e.Next()
+	})
+
+	// Keep cron jobs in sync with the collection
+	reschedule := func(e *core.RecordEvent) error {
+		if t, err := triggerFromRecord(e.Record); err == nil {
+			if err := scheduleFlowTrigger(wk, t, e.Record.GetString("tenant")); err != nil {
+				log.Printf("⚠️  Flow trigger %s not scheduled: %v", t.ID, err)
+			}
+		}
+		return e.Next()
+	}
+	wk.OnRecordAfterCreateSuccess(flowTriggersCollection).BindFunc(reschedule)
+	wk.OnRecordAfterUpdateSuccess(flowTriggersCollection).BindFunc(reschedule)
+	wk.OnRecordAfterDeleteSuccess(flowTriggersCollection).BindFunc(func(e *core.RecordEvent) error {
+		wk.Cron().Remove(flowTriggerJobID(e.Record.Id))
+		return e.Next()
+	})
+
+	// Webhooks authenticate with their HMAC signature, not a user session
+	handler := NewRouteHandler(registry, "Flows", e)
+	handler.POST("/api/flows/{flow}/hooks/{trigger}", func(c *core.RequestEvent) error {
+		return withFlowRunner(c, wk, func(runner *flow.Runner) error {
+			record, err := wk.FindRecordById(flowTriggersCollection, c.Request.PathValue("trigger"))
+			if errors.Is(err, sql.ErrNoRows) || (err == nil && (record.GetString("flow_id") != runner.Flow.ID ||
+				record.GetString("tenant") != tenantSlug(TenantFromRequest(c)))) {
+				return problemJSON(c, ProblemNotFound, "Trigger not found")
+			}
+			if err != nil {
+				return internalProblem(c, ProblemInternal, "Failed to find trigger", err)
+			}
+			t, err := triggerFromRecord(record)
+			if err != nil {
+				return internalProblem(c, ProblemInternal, "Failed to load trigger", err)
+			}
+			runner.ServeWebhook(c.Response, c.Request, t)
+			return nil
+		})
+	}, WithDescription("Start a flow run from a signed webhook (X-Wellknown-Signature: sha256=<hmac>)"))
+}
@@ -0,0 +1,290 @@
+package wellknown
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/joeblew999/wellknown/pkg/flow"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// ================================================================
+// Flow Persistence (PocketBase)
+// ================================================================
+// Flow definitions and run history live in the flow_definitions and flow_runs
+// collections (see pb_migrations/1730900000_init_flows.go), so runs survive
+// restarts and past executions can be browsed in the admin UI.
+
+const (
+	flowDefinitionsCollection = "flow_definitions"
+	flowRunsCollection        = "flow_runs"
+)
+
+// FlowStore is a flow.Store backed by the flow_runs collection. Each store
+// sees the runs of one tenant only (see ForTenant).
+type FlowStore struct {
+	app    core.App
+	tenant string
+}
+
+// NewFlowStore creates a store for the default tenant using app's database
+func NewFlowStore(app core.App) *FlowStore {
+	return &FlowStore{app: app}
+}
+
+// ForTenant returns a store for the runs of the tenant with the given slug
+func (s *FlowStore) ForTenant(slug string) *FlowStore {
+	return &FlowStore{app: s.app, tenant: slug}
+}
+
+// findRun finds the record of a run belonging to the store's tenant
+func (s *FlowStore) findRun(id string) (*core.Record, error) {
+	return s.app.FindFirstRecordByFilter(flowRunsCollection, "run_id = {:run_id} && tenant = {:tenant}", map[string]any{
+		"run_id": id,
+		"tenant": s.tenant,
+	})
+}
+
+// SaveRun creates or updates the run's record
+func (s *FlowStore) SaveRun(run *flow.Run) error {
+	record, err := s.findRun(run.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		collection, err := s.app.FindCollectionByNameOrId(flowRunsCollection)
+		if err != nil {
+			return fmt.Errorf("flow runs collection not found: %w", err)
+		}
+		record = core.NewRecord(collection)
+		record.Set("run_id", run.ID)
+		record.Set("flow_id", run.FlowID)
+		record.Set("tenant", s.tenant)
+		record.Set("started_at", run.CreatedAt)
+	} else if err != nil {
+		return fmt.Errorf("failed to find run %s: %w", run.ID, err)
+	}
+
+	record.Set("status", string(run.Status))
+	record.Set("steps", run.Steps)
+	record.Set("updated_at", run.UpdatedAt)
+	if err := s.app.Save(record); err != nil {
+		return fmt.Errorf("failed to save run %s: %w", run.ID, err)
+	}
+	return nil
+}
+
+// LoadRun loads a run by its run ID (flow.ErrRunNotFound for other tenants' runs)
+func (s *FlowStore) LoadRun(id string) (*flow.Run, error) {
+	record, err := s.findRun(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, flow.ErrRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load run %s: %w", id, err)
+	}
+	return runFromRecord(record)
+}
+
+// ListRuns returns the runs of flowID, newest first
+func (s *FlowStore) ListRuns(flowID string) ([]*flow.Run, error) {
+	records, err := s.app.FindRecordsByFilter(flowRunsCollection, "flow_id = {:flow_id} && tenant = {:tenant}", "-started_at", 100, 0, map[string]any{
+		"flow_id": flowID,
+		"tenant":  s.tenant,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	runs := make([]*flow.Run, 0, len(records))
+	for _, record := range records {
+		run, err := runFromRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// SaveDefinition creates or updates f's flow_definitions record
+func (s *FlowStore) SaveDefinition(f *flow.Flow) error {
+	record, err := s.app.FindFirstRecordByData(flowDefinitionsCollection, "flow_id", f.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		collection, err := s.app.FindCollectionByNameOrId(flowDefinitionsCollection)
+		if err != nil {
+			return fmt.Errorf("flow definitions collection not found: %w", err)
+		}
+		record = core.NewRecord(collection)
+		record.Set("flow_id", f.ID)
+	} else if err != nil {
+		return fmt.Errorf("failed to find flow %s: %w", f.ID, err)
+	}
+
+	record.Set("title", f.Title)
+	record.Set("definition", f.Definition())
+	return s.app.Save(record)
+}
+
+func runFromRecord(record *core.Record) (*flow.Run, error) {
+	run := &flow.Run{
+		ID:        record.GetString("run_id"),
+		FlowID:    record.GetString("flow_id"),
+		Status:    flow.Status(record.GetString("status")),
+		CreatedAt: record.GetDateTime("started_at").Time(),
+		UpdatedAt: record.GetDateTime("updated_at").Time(),
+	}
+	if err := record.UnmarshalJSONField("steps", &run.Steps); err != nil {
+		return nil, fmt.Errorf("failed to parse steps of run %s: %w", run.ID, err)
+	}
+	if run.Steps == nil {
+		run.Steps = make(map[string]*flow.StepRun)
+	}
+	return run, nil
+}
+
+// ================================================================
+// Flow Registration & Routes
+// ================================================================
+
+// RegisterFlow makes f available under /api/flows/{flow}, with runs stored in
+// PocketBase per tenant. Call before Start; the definition is saved when the
+// server starts.
+func (wk *Wellknown) RegisterFlow(f *flow.Flow) error {
+	runner, err := flow.NewRunner(f, NewFlowStore(wk))
+	if err != nil {
+		return err
+	}
+	if wk.flows == nil {
+		wk.flows = make(map[string]*flow.Runner)
+	}
+	if _, exists := wk.flows[f.ID]; exists {
+		return fmt.Errorf("flow %s already registered", f.ID)
+	}
+	wk.flows[f.ID] = runner
+	return nil
+}
+
+// flowRunner returns the runner of a registered flow for a tenant ("" = the
+// default tenant), so each tenant only sees its own runs
+func (wk *Wellknown) flowRunner(flowID, tenant string) (*flow.Runner, bool) {
+	runner, ok := wk.flows[flowID]
+	if !ok || tenant == "" {
+		return runner, ok
+	}
+
+	wk.tenantFlowsMu.Lock()
+	defer wk.tenantFlowsMu.Unlock()
+	key := tenant + "/" + flowID
+	if tr, ok := wk.tenantFlows[key]; ok {
+		return tr, true
+	}
+	tr := &flow.Runner{Flow: runner.Flow, Store: NewFlowStore(wk).ForTenant(tenant)}
+	if wk.tenantFlows == nil {
+		wk.tenantFlows = make(map[string]*flow.Runner)
+	}
+	wk.tenantFlows[key] = tr
+	return tr, true
+}
+
+// RegisterFlowRoutes registers the flow API (start, resume, submit and browse runs)
+func RegisterFlowRoutes(wk *Wellknown, e *core.ServeEvent, registry *RouteRegistry) {
+	// Pre-flight check: Validate required collections exist
+	for _, collectionName := range []string{flowDefinitionsCollection, flowRunsCollection} {
+		if _, err := wk.FindCollectionByNameOrId(collectionName); err != nil {
+			log.Printf("⚠️  Flow routes NOT registered: collection '%s' not found (migrations may not have run)", collectionName)
+			log.Printf("   Run 'go run . migrate up' to create required collections")
+			return
+		}
+	}
+
+	store := NewFlowStore(wk)
+	for _, runner := range wk.flows {
+		if err := store.SaveDefinition(runner.Flow); err != nil {
+			log.Printf("⚠️  Failed to save flow definition %s: %v", runner.Flow.ID, err)
+		}
+	}
+	log.Printf("✅ Flow routes: %d flow(s) registered", len(wk.flows))
+
+	handler := NewRouteHandler(registry, "Flows", e)
+
+	handler.GET("/api/flows", func(c *core.RequestEvent) error {
+		defs := make([]flow.Definition, 0, len(wk.flows))
+		for _, runner := range wk.flows {
+			defs = append(defs, runner.Flow.Definition())
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"flows": defs, "count": len(defs)})
+	}, WithAuth(), WithDescription("List registered flows with their step schemas"))
+
+	handler.GET("/api/flows/{flow}/runs", func(c *core.RequestEvent) error {
+		return withFlowRunner(c, wk, func(runner *flow.Runner) error {
+			runs, err := runner.Store.ListRuns(runner.Flow.ID)
+			if err != nil {
+				return internalProblem(c, ProblemInternal, "Failed to list runs", err)
+			}
+			return c.JSON(http.StatusOK, map[string]interface{}{"runs": runs, "count": len(runs)})
+		})
+	}, WithAuth(), WithDescription("List past runs of a flow (newest first)"))
+
+	handler.POST("/api/flows/{flow}/runs", func(c *core.RequestEvent) error {
+		return withFlowRunner(c, wk, func(runner *flow.Runner) error {
+			var req struct {
+				Inputs map[string]map[string]interface{} `json:"inputs"` // Step ID -> form data
+			}
+			if err := c.BindBody(&req); err != nil {
+				return problemJSON(c, ProblemBadRequest, "Invalid request body")
+			}
+			run, err := runner.Start(c.Request.Context(), req.Inputs)
+			return respondFlowRun(c, run, err, http.StatusCreated)
+		})
+	}, WithAuth(), WithDescription("Start a flow run (optional inputs per step)"))
+
+	handler.GET("/api/flows/{flow}/runs/{run}", func(c *core.RequestEvent) error {
+		return withFlowRunner(c, wk, func(runner *flow.Runner) error {
+			run, err := runner.Store.LoadRun(c.Request.PathValue("run"))
+			return respondFlowRun(c, run, err, http.StatusOK)
+		})
+	}, WithAuth(), WithDescription("Get a run with each step's status, input and output"))
+
+	handler.POST("/api/flows/{flow}/runs/{run}/steps/{step}", func(c *core.RequestEvent) error {
+		return withFlowRunner(c, wk, func(runner *flow.Runner) error {
+			var data map[string]interface{}
+			if err := c.BindBody(&data); err != nil {
+				return problemJSON(c, ProblemBadRequest, "Invalid request body")
+			}
+			run, err := runner.Submit(c.Request.Context(), c.Request.PathValue("run"), c.Request.PathValue("step"), data)
+			return respondFlowRun(c, run, err, http.StatusOK)
+		})
+	}, WithAuth(), WithDescription("Submit a step's input and continue the run"))
+
+	handler.POST("/api/flows/{flow}/runs/{run}/resume", func(c *core.RequestEvent) error {
+		return withFlowRunner(c, wk, func(runner *flow.Runner) error {
+			run, err := runner.Resume(c.Request.Context(), c.Request.PathValue("run"))
+			return respondFlowRun(c, run, err, http.StatusOK)
+		})
+	}, WithAuth(), WithDescription("Resume a run (re-runs interrupted steps)"))
+
+	RegisterFlowTriggers(wk, e, registry)
+}
+
+// withFlowRunner looks up the {flow} path parameter and calls fn with its
+// runner for the request's tenant
+func withFlowRunner(c *core.RequestEvent, wk *Wellknown, fn func(*flow.Runner) error) error {
+	runner, ok := wk.flowRunner(c.Request.PathValue("flow"), tenantSlug(TenantFromRequest(c)))
+	if !ok {
+		return problemJSON(c, ProblemNotFound, "Flow not found")
+	}
+	return fn(runner)
+}
+
+// respondFlowRun writes run as JSON, mapping runner errors to status codes
+func respondFlowRun(c *core.RequestEvent, run *flow.Run, err error, status int) error {
+	switch {
+	case errors.Is(err, flow.ErrRunNotFound):
+		return problemJSON(c, ProblemNotFound, "Run not found")
+	case err != nil && run == nil:
+		return problemJSON(c, ProblemBadRequest, err.Error())
+	case err != nil:
+		return internalProblem(c, ProblemInternal, "Failed to run flow", err)
+	}
+	return c.JSON(status, run)
+}
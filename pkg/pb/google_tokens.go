@@ -0,0 +1,445 @@
+package wellknown
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/pb/codegen/models"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+// ================================================================
+// Google API Tokens
+// ================================================================
+// The OAuth callback stores each user's Google token in google_tokens (one
+// per user and tenant). Access and refresh tokens are encrypted at rest with
+// AES-256-GCM (PocketBase's field encryption) using GOOGLE_TOKEN_KEY, or the
+// PocketBase --encryptionEnv key when that is unset; without either they are
+// stored in plain text, as before, and a warning is logged on startup.
+// Encrypted values carry the sealedTokenPrefix, so plain-text tokens from
+// older deployments keep working and are sealed by the refresher.
+//
+// A cron job refreshes tokens shortly before they expire, so API calls rarely
+// wait for a refresh; a token Google no longer accepts (the user revoked
+// access) is deleted. A token whose refresh failed otherwise is skipped for
+// googleTokenRetryAfter, so a few failing tokens can't fill every batch. POST /auth/revoke revokes the signed-in user's token at
+// Google and deletes it.
+
+const (
+	googleTokensCollection = "google_tokens"
+
+	googleTokenRefreshJob    = "google_token_refresh"
+	googleTokenRefreshWindow = 10 * time.Minute // Tokens expiring within this are refreshed
+	googleTokenRefreshBatch  = 100              // Tokens refreshed per run
+	googleTokenRetryAfter    = 30 * time.Minute // Failed tokens are skipped for this long
+
+	googleRevokeURL = "https://oauth2.googleapis.com/revoke"
+
+	sealedTokenPrefix = "enc:"
+	tokenKeyLength    = 32 // AES-256
+)
+
+// validateTokenKey checks a GOOGLE_TOKEN_KEY value (EnvVar.Validate)
+func validateTokenKey(value string) error {
+	if len(value) != tokenKeyLength {
+		return fmt.Errorf("must be %d characters (got %d)", tokenKeyLength, len(value))
+	}
+	return nil
+}
+
+// tokenKey returns the AES key tokens are sealed with ("" = not encrypted)
+func (wk *Wellknown) tokenKey() string {
+	if key := EnvRegistry.ByName("GOOGLE_TOKEN_KEY").GetString(); key != "" {
+		return key
+	}
+	if name := wk.EncryptionEnv(); name != "" {
+		return os.Getenv(name)
+	}
+	return ""
+}
+
+// sealToken encrypts a token value for storage
+func (wk *Wellknown) sealToken(value string) (string, error) {
+	key := wk.tokenKey()
+	if key == "" || value == "" {
+		return value, nil
+	}
+	sealed, err := security.Encrypt([]byte(value), key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt token: %w", err)
+	}
+	return sealedTokenPrefix + sealed, nil
+}
+
+// OpenToken decrypts a stored token value (plain-text values are returned as is)
+func (wk *Wellknown) OpenToken(value string) (string, error) {
+	sealed, ok := strings.CutPrefix(value, sealedTokenPrefix)
+	if !ok {
+		return value, nil
+	}
+	key := wk.tokenKey()
+	if key == "" {
+		return "", errors.New("token is encrypted but GOOGLE_TOKEN_KEY is not set")
+	}
+	plain, err := security.Decrypt(sealed, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt token (wrong GOOGLE_TOKEN_KEY?): %w", err)
+	}
+	return string(plain), nil
+}
+
+// storeGoogleToken stores the user's OAuth token for a tenant using type-safe proxy
+func storeGoogleToken(wk *Wellknown, tenant *Tenant, userID string, token *oauth2.Token) error {
+	collection, err := wk.FindCollectionByNameOrId(googleTokensCollection)
+	if err != nil {
+		return fmt.Errorf("failed to find google_tokens collection: %w", err)
+	}
+
+	// Check if token already exists for this user (tokens are per tenant)
+	existingToken, err := findGoogleToken(wk, collection.Name, tenantSlug(tenant), userID)
+
+	var tokenProxy *models.GoogleTokens
+	if err != nil {
+		// Token doesn't exist, create new proxy
+		tokenProxy, err = models.NewProxy[models.GoogleTokens](wk)
+		if err != nil {
+			return fmt.Errorf("failed to create GoogleTokens proxy: %w", err)
+		}
+	} else {
+		// Token exists, wrap it in proxy
+		tokenProxy, err = models.WrapRecord[models.GoogleTokens](existingToken)
+		if err != nil {
+			return fmt.Errorf("failed to wrap record as GoogleTokens: %w", err)
+		}
+	}
+
+	accessToken, err := wk.sealToken(token.AccessToken)
+	if err != nil {
+		return err
+	}
+	// A refresh returns no refresh token; keep the one we have
+	refreshToken := tokenProxy.RefreshToken()
+	if token.RefreshToken != "" {
+		if refreshToken, err = wk.sealToken(token.RefreshToken); err != nil {
+			return err
+		}
+	}
+
+	// Set token fields using type-safe setters
+	tokenProxy.SetUserId(userID)
+	tokenProxy.SetAccessToken(accessToken)
+	tokenProxy.SetRefreshToken(refreshToken)
+	tokenProxy.SetTokenType(token.TokenType)
+	tokenProxy.SetExpiry(types.NowDateTime().Add(time.Until(token.Expiry)))
+	tokenProxy.ProxyRecord().Set("tenant", tenantSlug(tenant))
+
+	return wk.Save(tokenProxy.ProxyRecord())
+}
+
+// getGoogleToken retrieves the user's stored OAuth token for a tenant,
+// refreshing it first when it has expired
+func getGoogleToken(wk *Wellknown, tenant *Tenant, userID string) (*oauth2.Token, error) {
+	record, err := findGoogleToken(wk, googleTokensCollection, tenantSlug(tenant), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find token for user: %w", err)
+	}
+	token, err := tokenFromRecord(wk, record)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normally done by the refresher; this covers tokens it has not reached yet
+	if time.Now().After(token.Expiry) {
+		return refreshGoogleToken(wk, tenant, userID, token)
+	}
+	return token, nil
+}
+
+// findGoogleToken returns the token record of a user in a tenant
+func findGoogleToken(wk *Wellknown, collection, slug, userID string) (*core.Record, error) {
+	return wk.FindFirstRecordByFilter(collection, "user_id = {:user_id} && tenant = {:tenant}", dbx.Params{
+		"user_id": userID,
+		"tenant":  slug,
+	})
+}
+
+// tokenFromRecord decrypts a google_tokens record using type-safe proxy
+func tokenFromRecord(wk *Wellknown, record *core.Record) (*oauth2.Token, error) {
+	tokenProxy, err := models.WrapRecord[models.GoogleTokens](record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap record as GoogleTokens: %w", err)
+	}
+	accessToken, err := wk.OpenToken(tokenProxy.AccessToken())
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := wk.OpenToken(tokenProxy.RefreshToken())
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    tokenProxy.TokenType(),
+		Expiry:       tokenProxy.Expiry().Time(),
+	}, nil
+}
+
+// refreshGoogleToken exchanges token's refresh token for a new access token
+// and stores it
+func refreshGoogleToken(wk *Wellknown, tenant *Tenant, userID string, token *oauth2.Token) (*oauth2.Token, error) {
+	googleConfig := tenant.GoogleConfig(wk)
+	if googleConfig == nil {
+		return nil, errors.New("Google OAuth is not configured for this tenant")
+	}
+	// Expiry in the past forces the token source to refresh
+	stale := *token
+	stale.Expiry = time.Now().Add(-time.Minute)
+	newToken, err := googleConfig.TokenSource(context.Background(), &stale).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	// Update stored token
+	if err := storeGoogleToken(wk, tenant, userID, newToken); err != nil {
+		log.Printf("Warning: failed to update refreshed token: %v", err)
+	}
+	return newToken, nil
+}
+
+// revokedGrant reports whether a refresh failed because Google no longer
+// accepts the refresh token
+func revokedGrant(err error) bool {
+	var re *oauth2.RetrieveError
+	return errors.As(err, &re) && re.ErrorCode == "invalid_grant"
+}
+
+// googleTokenRefresher refreshes tokens before they expire
+type googleTokenRefresher struct {
+	wk      *Wellknown
+	running sync.Mutex // Runs don't overlap when one outlasts the cron interval
+
+	failed map[string]time.Time // Record ID -> last failed refresh (guarded by running)
+}
+
+// seal encrypts tokens stored in plain text (before a key was configured)
+func (r *googleTokenRefresher) seal() {
+	if r.wk.tokenKey() == "" {
+		return
+	}
+	records, err := r.wk.FindAllRecords(googleTokensCollection, dbx.Not(dbx.Like("access_token", sealedTokenPrefix).Match(false, true)))
+	if err != nil {
+		log.Printf("⚠️  Google tokens: failed to find plain-text tokens: %v", err)
+		return
+	}
+	for _, record := range records {
+		for _, field := range []string{"access_token", "refresh_token"} {
+			value := record.GetString(field)
+			if strings.HasPrefix(value, sealedTokenPrefix) {
+				continue
+			}
+			sealed, err := r.wk.sealToken(value)
+			if err != nil {
+				log.Printf("⚠️  Google tokens: token %s: %v", record.Id, err)
+				return
+			}
+			record.Set(field, sealed)
+		}
+		if err := r.wk.Save(record); err != nil {
+			log.Printf("⚠️  Google tokens: token %s: %v", record.Id, err)
+		}
+	}
+	if len(records) > 0 {
+		log.Printf("🔒 Google tokens: encrypted %d plain-text token(s)", len(records))
+	}
+}
+
+// refresh refreshes the tokens expiring within googleTokenRefreshWindow
+func (r *googleTokenRefresher) refresh() {
+	if !r.running.TryLock() {
+		return
+	}
+	defer r.running.Unlock()
+
+	records, err := r.due(time.Now())
+	if err != nil {
+		log.Printf("⚠️  Google tokens: refresh scan failed: %v", err)
+		return
+	}
+	for _, record := range records {
+		if err := r.refreshRecord(record); err != nil {
+			log.Printf("⚠️  Google tokens: token %s: %v (retrying in %s)", record.Id, err, googleTokenRetryAfter)
+			r.failed[record.Id] = time.Now()
+		} else {
+			delete(r.failed, record.Id)
+		}
+	}
+}
+
+// due returns the oldest googleTokenRefreshBatch tokens expiring within
+// googleTokenRefreshWindow of now, leaving out those that failed within
+// googleTokenRetryAfter
+func (r *googleTokenRefresher) due(now time.Time) ([]*core.Record, error) {
+	if r.failed == nil {
+		r.failed = make(map[string]time.Time)
+	}
+	var skip []interface{}
+	for id, at := range r.failed {
+		if now.Sub(at) < googleTokenRetryAfter {
+			skip = append(skip, id)
+		} else {
+			delete(r.failed, id)
+		}
+	}
+
+	soon, err := types.ParseDateTime(now.Add(googleTokenRefreshWindow))
+	if err != nil {
+		return nil, err
+	}
+	query := r.wk.RecordQuery(googleTokensCollection).
+		AndWhere(dbx.NewExp("[[expiry]] <= {:soon} AND [[refresh_token]] != ''", dbx.Params{"soon": soon.String()})).
+		OrderBy("expiry ASC").
+		Limit(googleTokenRefreshBatch)
+	if len(skip) > 0 {
+		query = query.AndWhere(dbx.NotIn("id", skip...))
+	}
+	var records []*core.Record
+	if err := query.All(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// refreshRecord refreshes one stored token, deleting it when its grant was
+// revoked; other failures are returned
+func (r *googleTokenRefresher) refreshRecord(record *core.Record) error {
+	slug := record.GetString("tenant")
+	var tenant *Tenant
+	if slug != "" {
+		t, err := r.wk.FindTenant(slug)
+		if err != nil {
+			return err
+		}
+		tenant = t
+	}
+	token, err := tokenFromRecord(r.wk, record)
+	if err != nil {
+		return err
+	}
+	_, err = refreshGoogleToken(r.wk, tenant, record.GetString("user_id"), token)
+	if !revokedGrant(err) {
+		return err
+	}
+	if err := r.wk.Delete(record); err != nil {
+		return err
+	}
+	log.Printf("Google tokens: access revoked by user %s - token deleted", record.GetString("user_id"))
+	return nil
+}
+
+// googleTokenProblem writes the problem for a getGoogleToken error
+func googleTokenProblem(e *core.RequestEvent, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return problemJSON(e, ProblemForbidden, "No Google account connected - sign in with Google first")
+	case revokedGrant(err):
+		return problemJSON(e, ProblemForbidden, "Google access was revoked - sign in with Google again")
+	}
+	return internalProblem(e, ProblemUpstream, "Failed to get Google token", err)
+}
+
+// revokeGoogleToken revokes token at Google; revoking the refresh token
+// ends the whole grant
+func revokeGoogleToken(ctx context.Context, token *oauth2.Token) error {
+	value := token.RefreshToken
+	if value == "" {
+		value = token.AccessToken
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, googleRevokeURL,
+		strings.NewReader(url.Values{"token": {value}}.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 400 invalid_token: already revoked or expired, which is what we want
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("revoke returned %s", resp.Status)
+	}
+	return nil
+}
+
+// handleRevoke revokes the signed-in user's Google token and deletes it
+func handleRevoke(wk *Wellknown) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		record, err := findGoogleToken(wk, googleTokensCollection, tenantSlug(TenantFromRequest(e)), e.Auth.Id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return problemJSON(e, ProblemNotFound, "No Google token stored")
+		}
+		if err != nil {
+			return internalProblem(e, ProblemInternal, "Failed to find Google token", err)
+		}
+
+		// The local copy goes either way; Google may already have dropped it
+		remote := true
+		if token, err := tokenFromRecord(wk, record); err != nil {
+			log.Printf("Failed to read token for revoke: %v", err)
+			remote = false
+		} else if err := revokeGoogleToken(e.Request.Context(), token); err != nil {
+			log.Printf("Failed to revoke token at Google: %v", err)
+			remote = false
+		}
+		if err := wk.Delete(record); err != nil {
+			return internalProblem(e, ProblemInternal, "Failed to delete Google token", err)
+		}
+		return e.JSON(http.StatusOK, map[string]bool{"revoked": true, "revokedAtGoogle": remote})
+	}
+}
+
+// RegisterGoogleTokens starts the token refresher and registers
+// /auth/revoke. It fails when the token key is not a valid AES-256 key, as
+// tokens could then neither be stored nor read.
+func RegisterGoogleTokens(wk *Wellknown, e *core.ServeEvent, registry *RouteRegistry) error {
+	// Pre-flight check: Validate required collections exist
+	if _, err := wk.FindCollectionByNameOrId(googleTokensCollection); err != nil {
+		log.Printf("⚠️  Google token refresher NOT started: collection '%s' not found (migrations may not have run)", googleTokensCollection)
+		log.Printf("   Run 'go run . migrate up' to create required collections")
+		return nil
+	}
+
+	// GOOGLE_TOKEN_KEY itself is checked by ValidateEnv; this covers the
+	// --encryptionEnv fallback too
+	key := wk.tokenKey()
+	if key == "" {
+		log.Printf("⚠️  Google tokens are stored unencrypted - set GOOGLE_TOKEN_KEY (%d characters)", tokenKeyLength)
+	} else if err := validateTokenKey(key); err != nil {
+		return fmt.Errorf("Google token key (GOOGLE_TOKEN_KEY or --encryptionEnv) %w", err)
+	}
+
+	refresher := &googleTokenRefresher{wk: wk}
+	refresher.seal()
+	wk.Cron().MustAdd(googleTokenRefreshJob, "*/5 * * * *", refresher.refresh)
+	log.Printf("✅ Google tokens: refreshing tokens expiring within %s every 5 minutes", googleTokenRefreshWindow)
+
+	handler := NewRouteHandler(registry, "OAuth", e)
+	handler.POST("/auth/revoke", handleRevoke(wk),
+		WithAuth(), WithDescription("Revoke the Google token of the signed-in user"))
+	return nil
+}
@@ -0,0 +1,168 @@
+package wellknown
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	_ "github.com/joeblew999/wellknown/pkg/cmd/pocketbase/pb_migrations"
+)
+
+const (
+	testTokenKey  = "0123456789abcdef0123456789abcdef"
+	otherTokenKey = "fedcba9876543210fedcba9876543210"
+)
+
+// newTokenTestApp returns a Wellknown on a migrated test app
+func newTokenTestApp(t *testing.T) *Wellknown {
+	t.Helper()
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(app.Cleanup)
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatal(err)
+	}
+	return &Wellknown{PocketBase: &pocketbase.PocketBase{App: app}}
+}
+
+// saveToken stores a google_tokens record as is (no sealing)
+func saveToken(t *testing.T, wk *Wellknown, userID, access, refresh string, expiry time.Time) *core.Record {
+	t.Helper()
+	collection, err := wk.FindCollectionByNameOrId(googleTokensCollection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := core.NewRecord(collection)
+	record.Set("user_id", userID)
+	record.Set("access_token", access)
+	record.Set("refresh_token", refresh)
+	record.Set("expiry", types.NowDateTime().Add(time.Until(expiry)))
+	if err := wk.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func TestSealToken(t *testing.T) {
+	wk := newTokenTestApp(t)
+
+	t.Setenv("GOOGLE_TOKEN_KEY", testTokenKey)
+	sealed, err := wk.sealToken("ya29.access")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, sealedTokenPrefix) || strings.Contains(sealed, "ya29.access") {
+		t.Fatalf("sealToken() = %q, want an encrypted value", sealed)
+	}
+	if plain, err := wk.OpenToken(sealed); err != nil || plain != "ya29.access" {
+		t.Errorf("OpenToken() = %q, %v; want the sealed value", plain, err)
+	}
+	if empty, _ := wk.sealToken(""); empty != "" {
+		t.Errorf("sealToken(\"\") = %q, want no value", empty)
+	}
+
+	// Plain-text values from before a key was configured are read as is
+	if plain, err := wk.OpenToken("ya29.legacy"); err != nil || plain != "ya29.legacy" {
+		t.Errorf("OpenToken(plain) = %q, %v", plain, err)
+	}
+
+	t.Setenv("GOOGLE_TOKEN_KEY", otherTokenKey)
+	if _, err := wk.OpenToken(sealed); err == nil || !strings.Contains(err.Error(), "wrong GOOGLE_TOKEN_KEY") {
+		t.Errorf("OpenToken() with another key: err = %v", err)
+	}
+
+	t.Setenv("GOOGLE_TOKEN_KEY", "")
+	if _, err := wk.OpenToken(sealed); err == nil {
+		t.Error("expected an error opening a sealed token without a key")
+	}
+	if plain, _ := wk.sealToken("ya29.access"); plain != "ya29.access" {
+		t.Errorf("sealToken() without a key = %q, want the value unchanged", plain)
+	}
+}
+
+func TestRefresherSeal(t *testing.T) {
+	wk := newTokenTestApp(t)
+	plain := saveToken(t, wk, "u1", "ya29.plain", "1//refresh", time.Now().Add(time.Hour))
+
+	t.Setenv("GOOGLE_TOKEN_KEY", testTokenKey)
+	sealedAccess, _ := wk.sealToken("ya29.sealed")
+	sealed := saveToken(t, wk, "u2", sealedAccess, "", time.Now().Add(time.Hour))
+
+	(&googleTokenRefresher{wk: wk}).seal()
+
+	record, err := wk.FindRecordById(googleTokensCollection, plain.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := tokenFromRecord(wk, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(record.GetString("access_token"), sealedTokenPrefix) ||
+		!strings.HasPrefix(record.GetString("refresh_token"), sealedTokenPrefix) {
+		t.Errorf("plain-text token not sealed: %v", record.FieldsData())
+	}
+	if token.AccessToken != "ya29.plain" || token.RefreshToken != "1//refresh" {
+		t.Errorf("sealed token opens to %q, %q", token.AccessToken, token.RefreshToken)
+	}
+
+	// Already sealed values are left alone
+	if record, _ = wk.FindRecordById(googleTokensCollection, sealed.Id); record.GetString("access_token") != sealedAccess {
+		t.Error("sealed token was sealed again")
+	}
+}
+
+func TestRefresherSkipsFailedTokens(t *testing.T) {
+	wk := newTokenTestApp(t)
+	failing := saveToken(t, wk, "u1", "a", "r", time.Now().Add(-time.Hour))
+	other := saveToken(t, wk, "u2", "a", "r", time.Now().Add(time.Minute))
+	saveToken(t, wk, "u3", "a", "r", time.Now().Add(time.Hour)) // Not due
+	saveToken(t, wk, "u4", "a", "", time.Now())                 // Nothing to refresh with
+
+	ids := func(now time.Time, r *googleTokenRefresher) []string {
+		records, err := r.due(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, record := range records {
+			ids = append(ids, record.Id)
+		}
+		return ids
+	}
+	now := time.Now()
+	r := &googleTokenRefresher{wk: wk}
+	if got := ids(now, r); len(got) != 2 || got[0] != failing.Id || got[1] != other.Id {
+		t.Fatalf("due() = %v, want [%s %s] (oldest first)", got, failing.Id, other.Id)
+	}
+
+	r.failed[failing.Id] = now
+	if got := ids(now, r); len(got) != 1 || got[0] != other.Id {
+		t.Errorf("due() = %v, want the failed token skipped", got)
+	}
+	if got := ids(now.Add(googleTokenRetryAfter), r); len(got) != 2 {
+		t.Errorf("due() = %v, want the failed token retried after %s", got, googleTokenRetryAfter)
+	}
+	if len(r.failed) != 0 {
+		t.Errorf("failed = %v, want expired entries dropped", r.failed)
+	}
+}
+
+func TestValidateTokenKey(t *testing.T) {
+	if err := validateTokenKey(testTokenKey); err != nil {
+		t.Error(err)
+	}
+	if err := validateTokenKey("short"); err == nil || !strings.Contains(err.Error(), "got 5") {
+		t.Errorf("err = %v, want a length error", err)
+	}
+	if err := EnvRegistry.ByName("GOOGLE_TOKEN_KEY").ValidateValue("short"); err == nil {
+		t.Error("GOOGLE_TOKEN_KEY accepts a short key")
+	}
+}
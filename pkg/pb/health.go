@@ -0,0 +1,38 @@
+package wellknown
+
+import (
+	"context"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/joeblew999/wellknown/pkg/health"
+)
+
+// newHealthChecker creates the checker behind /health, with the database as
+// its critical check
+func newHealthChecker(app core.App) *health.Checker {
+	checker := health.New("pocketbase")
+	checker.Register("database", func(ctx context.Context) error {
+		var one int
+		return app.DB().NewQuery("SELECT 1").WithContext(ctx).Row(&one)
+	})
+	return checker
+}
+
+// Health returns the checker behind /health, so main.go and plugins can
+// register checks of their own (see package health)
+func (wk *Wellknown) Health() *health.Checker {
+	return wk.health
+}
+
+// RegisterHealthRoutes serves /health in the same format as the other
+// wellknown binaries. PocketBase's own /api/health only reports that the
+// server answers; this one runs the registered checks and returns 503 when a
+// critical one fails. Not rate limited, so monitors are never throttled.
+func RegisterHealthRoutes(wk *Wellknown, e *core.ServeEvent, registry *RouteRegistry) {
+	e.Router.GET("/health", func(c *core.RequestEvent) error {
+		wk.health.ServeHTTP(c.Response, c.Request)
+		return nil
+	})
+	registry.Register("System", "/health", "GET", "Health check: status, version, uptime and subsystem checks (503 when down)", false)
+}
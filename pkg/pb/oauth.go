@@ -8,9 +8,7 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/joeblew999/wellknown/pkg/pb/codegen/models"
 	"github.com/pocketbase/pocketbase/core"
-	"github.com/pocketbase/pocketbase/tools/types"
 	"golang.org/x/oauth2"
 )
 
@@ -37,6 +35,12 @@ func RegisterOAuthRoutes(wk *Wellknown, e *core.ServeEvent, registry *RouteRegis
 // handleGoogleLogin initiates the OAuth flow
 func handleGoogleLogin(wk *Wellknown) func(e *core.RequestEvent) error {
 	return func(e *core.RequestEvent) error {
+		// The tenant's own OAuth client, if it configures one
+		googleConfig := TenantFromRequest(e).GoogleConfig(wk)
+		if googleConfig == nil {
+			return problemJSON(e, ProblemNotConfigured, "Google OAuth is not configured for this tenant")
+		}
+
 		// Generate state token for CSRF protection
 		state := generateStateToken()
 
@@ -52,7 +56,7 @@ func handleGoogleLogin(wk *Wellknown) func(e *core.RequestEvent) error {
 		})
 
 		// Redirect to Google OAuth consent page
-		url := wk.oauthService.GoogleConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
+		url := googleConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
 		return e.Redirect(http.StatusTemporaryRedirect, url)
 	}
 }
@@ -63,28 +67,31 @@ func handleGoogleCallback(wk *Wellknown) func(e *core.RequestEvent) error {
 		// Verify state token
 		stateCookie, err := e.Request.Cookie("oauth_state")
 		if err != nil {
-			return e.String(http.StatusBadRequest, "Missing state cookie")
+			return problemJSON(e, ProblemInvalidOAuthFlow, "Missing state cookie")
 		}
 
 		state := e.Request.URL.Query().Get("state")
 		if state != stateCookie.Value {
-			return e.String(http.StatusBadRequest, "Invalid state token")
+			return problemJSON(e, ProblemInvalidOAuthFlow, "Invalid state token")
 		}
 
 		// Exchange code for token
+		tenant := TenantFromRequest(e)
+		googleConfig := tenant.GoogleConfig(wk)
+		if googleConfig == nil {
+			return problemJSON(e, ProblemNotConfigured, "Google OAuth is not configured for this tenant")
+		}
 		code := e.Request.URL.Query().Get("code")
-		token, err := wk.oauthService.GoogleConfig.Exchange(context.Background(), code)
+		token, err := googleConfig.Exchange(context.Background(), code)
 		if err != nil {
-			log.Printf("Failed to exchange code: %v", err)
-			return e.String(http.StatusInternalServerError, "Failed to exchange code")
+			return internalProblem(e, ProblemUpstream, "Failed to exchange code", err)
 		}
 
 		// Get user info
-		client := wk.oauthService.GoogleConfig.Client(context.Background(), token)
+		client := googleConfig.Client(context.Background(), token)
 		resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
 		if err != nil {
-			log.Printf("Failed to get user info: %v", err)
-			return e.String(http.StatusInternalServerError, "Failed to get user info")
+			return internalProblem(e, ProblemUpstream, "Failed to get user info", err)
 		}
 		defer resp.Body.Close()
 
@@ -94,14 +101,13 @@ func handleGoogleCallback(wk *Wellknown) func(e *core.RequestEvent) error {
 			Name  string `json:"name"`
 		}
 		if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
-			log.Printf("Failed to decode user info: %v", err)
-			return e.String(http.StatusInternalServerError, "Failed to decode user info")
+			return internalProblem(e, ProblemUpstream, "Failed to decode user info", err)
 		}
 
 		// Find or create user in Pocketbase
 		userCollection, err := wk.FindCollectionByNameOrId("users")
 		if err != nil {
-			return e.String(http.StatusInternalServerError, "Failed to find users collection")
+			return internalProblem(e, ProblemInternal, "Failed to find users collection", err)
 		}
 
 		// Try to find existing user by email
@@ -116,27 +122,27 @@ func handleGoogleCallback(wk *Wellknown) func(e *core.RequestEvent) error {
 			user.Set("name", userInfo.Name)
 			user.Set("username", userInfo.Email) // Use email as username
 			user.Set("verified", true)           // Auto-verify Google users
+			user.Set("tenant", tenantSlug(tenant))
 			// Set password (required for auth collection)
 			user.SetPassword(generateStateToken()) // Random password since we use OAuth
 
 			if err := wk.Save(user); err != nil {
-				log.Printf("Failed to create user: %v", err)
-				return e.String(http.StatusInternalServerError, "Failed to create user")
+				return internalProblem(e, ProblemInternal, "Failed to create user", err)
 			}
 			log.Printf("Created new user: %s", userInfo.Email)
+		} else if !userInTenant(user, tenant) {
+			return problemJSON(e, ProblemForbidden, "Account belongs to another tenant")
 		}
 
 		// Store Google OAuth token
-		if err := storeGoogleToken(wk, user.Id, token); err != nil {
-			log.Printf("Failed to store token: %v", err)
-			return e.String(http.StatusInternalServerError, "Failed to store token")
+		if err := storeGoogleToken(wk, tenant, user.Id, token); err != nil {
+			return internalProblem(e, ProblemInternal, "Failed to store token", err)
 		}
 
 		// Generate JWT token for the user
 		authToken, err := user.NewAuthToken()
 		if err != nil {
-			log.Printf("Failed to generate auth token: %v", err)
-			return e.String(http.StatusInternalServerError, "Failed to generate auth token")
+			return internalProblem(e, ProblemInternal, "Failed to generate auth token", err)
 		}
 
 		// Set auth cookie
@@ -187,43 +193,6 @@ func handleAuthStatus(wk *Wellknown) func(e *core.RequestEvent) error {
 	}
 }
 
-// storeGoogleToken stores the OAuth token in the database using type-safe proxy
-func storeGoogleToken(wk *Wellknown, userID string, token *oauth2.Token) error {
-	collection, err := wk.FindCollectionByNameOrId("google_tokens")
-	if err != nil {
-		return fmt.Errorf("failed to find google_tokens collection: %w", err)
-	}
-
-	// Check if token already exists for this user
-	existingToken, err := wk.FindFirstRecordByFilter(collection.Name, "user_id = {:user_id}", map[string]any{
-		"user_id": userID,
-	})
-
-	var tokenProxy *models.GoogleTokens
-	if err != nil {
-		// Token doesn't exist, create new proxy
-		tokenProxy, err = models.NewProxy[models.GoogleTokens](wk)
-		if err != nil {
-			return fmt.Errorf("failed to create GoogleTokens proxy: %w", err)
-		}
-	} else {
-		// Token exists, wrap it in proxy
-		tokenProxy, err = models.WrapRecord[models.GoogleTokens](existingToken)
-		if err != nil {
-			return fmt.Errorf("failed to wrap record as GoogleTokens: %w", err)
-		}
-	}
-
-	// Set token fields using type-safe setters
-	tokenProxy.SetUserId(userID)
-	tokenProxy.SetAccessToken(token.AccessToken)
-	tokenProxy.SetRefreshToken(token.RefreshToken)
-	tokenProxy.SetTokenType(token.TokenType)
-	tokenProxy.SetExpiry(types.NowDateTime().Add(time.Until(token.Expiry)))
-
-	return wk.Save(tokenProxy.ProxyRecord())
-}
-
 // generateStateToken generates a random state token for CSRF protection
 func generateStateToken() string {
 	// Simple implementation - in production use crypto/rand
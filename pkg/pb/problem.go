@@ -0,0 +1,161 @@
+package wellknown
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/joeblew999/wellknown/pkg/types"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// ================================================================
+// Problem Details (RFC 7807)
+// ================================================================
+// Every error of the custom wellknown routes is an application/problem+json
+// body with a stable code clients can switch on; the HTTP status follows
+// from the code, so the same failure always gets the same status. Each
+// request has a correlation ID (the caller's X-Request-ID, else a generated
+// one) that is echoed in the response header, included in the problem and
+// logged with server errors, so a user's report can be matched to the log.
+
+const (
+	// RequestIDHeader carries the correlation ID of a request
+	RequestIDHeader = "X-Request-ID"
+
+	requestIDStoreKey = "wellknown.requestID" // RequestEvent store key
+
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:wellknown:problem:"
+)
+
+// ProblemCode identifies a kind of error
+type ProblemCode string
+
+// Problem codes
+const (
+	ProblemBadRequest       ProblemCode = "bad_request"       // Malformed request
+	ProblemValidation       ProblemCode = "validation_failed" // Well-formed, but invalid fields (see Problem.Errors)
+	ProblemUnauthenticated  ProblemCode = "unauthenticated"   // No or invalid credentials
+	ProblemForbidden        ProblemCode = "forbidden"         // Authenticated, but not allowed
+	ProblemNotFound         ProblemCode = "not_found"
+	ProblemRateLimited      ProblemCode = "rate_limited"
+	ProblemNotConfigured    ProblemCode = "not_configured" // A feature the deployment (or tenant) has not set up
+	ProblemUpstream         ProblemCode = "upstream_error" // Google or another provider failed
+	ProblemInternal         ProblemCode = "internal_error"
+	ProblemUnknownTenant    ProblemCode = "unknown_tenant"
+	ProblemInvalidOAuthFlow ProblemCode = "invalid_oauth_state" // OAuth callback without a matching state
+)
+
+// problemStatus is the HTTP status of each code
+var problemStatus = map[ProblemCode]int{
+	ProblemBadRequest:       http.StatusBadRequest,
+	ProblemValidation:       http.StatusBadRequest,
+	ProblemUnauthenticated:  http.StatusUnauthorized,
+	ProblemForbidden:        http.StatusForbidden,
+	ProblemNotFound:         http.StatusNotFound,
+	ProblemRateLimited:      http.StatusTooManyRequests,
+	ProblemNotConfigured:    http.StatusServiceUnavailable,
+	ProblemUpstream:         http.StatusBadGateway,
+	ProblemInternal:         http.StatusInternalServerError,
+	ProblemUnknownTenant:    http.StatusNotFound,
+	ProblemInvalidOAuthFlow: http.StatusBadRequest,
+}
+
+// Status returns the HTTP status of code (500 for unknown codes)
+func (c ProblemCode) Status() int {
+	if status, ok := problemStatus[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type      string            `json:"type"`   // urn:wellknown:problem:<code>
+	Title     string            `json:"title"`  // Status text
+	Status    int               `json:"status"` // Same as the response status
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"` // Request path
+	Code      ProblemCode       `json:"code"`
+	RequestID string            `json:"request_id,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"` // Field -> message, for validation_failed
+}
+
+// NewProblem builds the problem for code with detail
+func NewProblem(code ProblemCode, detail string) *Problem {
+	status := code.Status()
+	return &Problem{
+		Type:   problemTypePrefix + string(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// problemJSON writes a problem response for code. Server errors are logged
+// with the request ID; their detail should not leak internals.
+func problemJSON(e *core.RequestEvent, code ProblemCode, detail string) error {
+	return writeProblem(e, NewProblem(code, detail))
+}
+
+// internalProblem logs err and writes a problem for code with a generic detail
+func internalProblem(e *core.RequestEvent, code ProblemCode, detail string, err error) error {
+	log.Printf("[%s] %s %s: %s: %v", RequestID(e), e.Request.Method, e.Request.URL.Path, detail, err)
+	return problemJSON(e, code, detail)
+}
+
+// validationProblem writes a validation_failed problem listing the invalid fields
+func validationProblem(e *core.RequestEvent, err error) error {
+	if verr, ok := err.(*types.ValidationError); ok {
+		return fieldsProblem(e, verr.Fields())
+	}
+	return problemJSON(e, ProblemValidation, err.Error())
+}
+
+// fieldsProblem writes a validation_failed problem for fields (field -> message)
+func fieldsProblem(e *core.RequestEvent, fields map[string]string) error {
+	p := NewProblem(ProblemValidation, "The request has invalid fields")
+	p.Errors = fields
+	return writeProblem(e, p)
+}
+
+func writeProblem(e *core.RequestEvent, p *Problem) error {
+	p.Instance = e.Request.URL.Path
+	p.RequestID = RequestID(e)
+	e.Response.Header().Set("Content-Type", problemContentType)
+	return e.JSON(p.Status, p)
+}
+
+// validRequestID limits caller-supplied IDs to safe, log-friendly values
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID returns the correlation ID of a request ("" outside the middleware)
+func RequestID(e *core.RequestEvent) string {
+	id, _ := e.Get(requestIDStoreKey).(string)
+	return id
+}
+
+// newRequestID returns a random 16-character hex ID
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// RegisterRequestIDMiddleware gives every request a correlation ID and echoes
+// it in the X-Request-ID response header
+func RegisterRequestIDMiddleware(e *core.ServeEvent) {
+	e.Router.BindFunc(func(e *core.RequestEvent) error {
+		id := e.Request.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		e.Set(requestIDStoreKey, id)
+		e.Response.Header().Set(RequestIDHeader, id)
+		return e.Next()
+	})
+}
@@ -0,0 +1,176 @@
+package wellknown
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/server"
+	"github.com/joeblew999/wellknown/pkg/types"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// testRouter returns wk's router with the routes and middleware of register
+func testRouter(t *testing.T, wk *Wellknown, register func(e *core.ServeEvent)) http.Handler {
+	t.Helper()
+	router, err := apis.NewRouter(wk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	register(&core.ServeEvent{App: wk, Router: router})
+	mux, err := router.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mux
+}
+
+// decodeProblem checks rec is a problem response and returns it
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != problemContentType {
+		t.Errorf("Content-Type = %q, want %s", ct, problemContentType)
+	}
+	var p Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decoding problem %q: %v", rec.Body, err)
+	}
+	if p.Status != rec.Code || p.Status != p.Code.Status() || p.Type != problemTypePrefix+string(p.Code) {
+		t.Errorf("problem %+v does not match status %d", p, rec.Code)
+	}
+	return p
+}
+
+func TestProblemCode_Status(t *testing.T) {
+	for code, want := range map[ProblemCode]int{
+		ProblemValidation:      http.StatusBadRequest,
+		ProblemUnauthenticated: http.StatusUnauthorized,
+		ProblemRateLimited:     http.StatusTooManyRequests,
+		ProblemUnknownTenant:   http.StatusNotFound,
+		ProblemCode("nope"):    http.StatusInternalServerError,
+	} {
+		if got := code.Status(); got != want {
+			t.Errorf("%s.Status() = %d, want %d", code, got, want)
+		}
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	for id, ok := range map[string]bool{
+		"abc-123":                true,
+		"trace:span.1_x":         true,
+		strings.Repeat("a", 128): true,
+		strings.Repeat("a", 129): false,
+		"":                       false,
+		"has space":              false,
+		"line\nbreak":            false,
+		"<script>":               false,
+	} {
+		if got := validRequestID.MatchString(id); got != ok {
+			t.Errorf("validRequestID(%q) = %v, want %v", id, got, ok)
+		}
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	wk := newTokenTestApp(t)
+	mux := testRouter(t, wk, func(e *core.ServeEvent) {
+		RegisterRequestIDMiddleware(e)
+		e.Router.GET("/missing", func(e *core.RequestEvent) error {
+			return problemJSON(e, ProblemNotFound, "No such thing")
+		})
+		e.Router.GET("/invalid", func(e *core.RequestEvent) error {
+			var verr types.ValidationError
+			verr.Add("title", "is required")
+			return validationProblem(e, verr.Err())
+		})
+	})
+	get := func(path, requestID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		if requestID != "" {
+			r.Header.Set(RequestIDHeader, requestID)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		return rec
+	}
+
+	rec := get("/missing", "client-trace-1")
+	p := decodeProblem(t, rec)
+	if rec.Header().Get(RequestIDHeader) != "client-trace-1" || p.RequestID != "client-trace-1" {
+		t.Errorf("request ID = %q / %q, want the caller's echoed", rec.Header().Get(RequestIDHeader), p.RequestID)
+	}
+	if p.Code != ProblemNotFound || p.Detail != "No such thing" || p.Instance != "/missing" {
+		t.Errorf("problem = %+v", p)
+	}
+
+	// An unsafe ID is replaced
+	rec = get("/missing", "bad id")
+	if id := rec.Header().Get(RequestIDHeader); len(id) != 16 || id != decodeProblem(t, rec).RequestID {
+		t.Errorf("request ID = %q, want a generated one", id)
+	}
+
+	p = decodeProblem(t, get("/invalid", ""))
+	if p.Code != ProblemValidation || p.Errors["title"] != "is required" {
+		t.Errorf("problem = %+v, want the invalid fields", p)
+	}
+}
+
+func TestLinkHandler(t *testing.T) {
+	wk := newTokenTestApp(t)
+	standalone := http.NewServeMux()
+	respond := func(status int, resp server.APIResponse) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(resp)
+		}
+	}
+	standalone.HandleFunc("/api/ok", respond(http.StatusOK, server.APIResponse{URL: "https://example.com"}))
+	standalone.HandleFunc("/api/invalid", respond(http.StatusBadRequest, server.APIResponse{Errors: map[string]string{"start": "is required"}}))
+	standalone.HandleFunc("/api/bad", respond(http.StatusBadRequest, server.APIResponse{Error: "request body must be a JSON object"}))
+	standalone.HandleFunc("/api/fail", respond(http.StatusInternalServerError, server.APIResponse{Error: "template: secret internals"}))
+	mux := testRouter(t, wk, func(e *core.ServeEvent) {
+		RegisterRequestIDMiddleware(e)
+		for _, name := range []string{"ok", "invalid", "bad", "fail"} {
+			e.Router.POST("/api/links/"+name, linkHandler(standalone, "/api/"+name, name))
+		}
+	})
+	post := func(name string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("POST", "/api/links/"+name, strings.NewReader("{}")))
+		return rec
+	}
+
+	if rec := post("ok"); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" ||
+		!strings.Contains(rec.Body.String(), `"url":"https://example.com"`) {
+		t.Errorf("ok: %d %s %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+	if p := decodeProblem(t, post("invalid")); p.Code != ProblemValidation || p.Errors["start"] != "is required" {
+		t.Errorf("invalid: %+v", p)
+	}
+	if p := decodeProblem(t, post("bad")); p.Code != ProblemBadRequest || p.Detail != "request body must be a JSON object" {
+		t.Errorf("bad: %+v", p)
+	}
+	if p := decodeProblem(t, post("fail")); p.Code != ProblemInternal || strings.Contains(p.Detail, "internals") || p.RequestID == "" {
+		t.Errorf("fail: %+v, want a generic detail", p)
+	}
+}
+
+func TestValidationProblem_PlainError(t *testing.T) {
+	wk := newTokenTestApp(t)
+	mux := testRouter(t, wk, func(e *core.ServeEvent) {
+		e.Router.GET("/invalid", func(e *core.RequestEvent) error {
+			return validationProblem(e, errors.New("end must be after start"))
+		})
+	})
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/invalid", nil))
+	if p := decodeProblem(t, rec); p.Code != ProblemValidation || p.Detail != "end must be after start" || p.Errors != nil {
+		t.Errorf("problem = %+v", p)
+	}
+}
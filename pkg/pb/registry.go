@@ -20,6 +20,7 @@ type RouteMetadata struct {
 // RouteRegistry maintains a registry of all API routes with their metadata
 type RouteRegistry struct {
 	routes []RouteMetadata
+	guard  func(*core.RequestEvent) error // CORS + rate limiting for custom routes (see package guard)
 }
 
 // NewRouteRegistry creates a new route registry
@@ -221,8 +222,11 @@ func (h *RouteHandler) GET(path string, handler func(*core.RequestEvent) error,
 	// Register route metadata (using pre-computed meta values instead of re-applying opts)
 	h.registry.Register(h.domain, path, "GET", meta.Description, meta.AuthRequired)
 
-	// Register actual HTTP route with auth middleware if required
+	// Register actual HTTP route with guard and auth middleware
 	route := h.event.Router.GET(path, handler)
+	if h.registry.guard != nil {
+		route.BindFunc(h.registry.guard)
+	}
 	if meta.AuthRequired {
 		route.BindFunc(RequireAuth())
 	}
@@ -238,8 +242,11 @@ func (h *RouteHandler) POST(path string, handler func(*core.RequestEvent) error,
 	// Register route metadata (using pre-computed meta values instead of re-applying opts)
 	h.registry.Register(h.domain, path, "POST", meta.Description, meta.AuthRequired)
 
-	// Register actual HTTP route with auth middleware if required
+	// Register actual HTTP route with guard and auth middleware
 	route := h.event.Router.POST(path, handler)
+	if h.registry.guard != nil {
+		route.BindFunc(h.registry.guard)
+	}
 	if meta.AuthRequired {
 		route.BindFunc(RequireAuth())
 	}
@@ -255,8 +262,11 @@ func (h *RouteHandler) PUT(path string, handler func(*core.RequestEvent) error,
 	// Register route metadata (using pre-computed meta values instead of re-applying opts)
 	h.registry.Register(h.domain, path, "PUT", meta.Description, meta.AuthRequired)
 
-	// Register actual HTTP route with auth middleware if required
+	// Register actual HTTP route with guard and auth middleware
 	route := h.event.Router.PUT(path, handler)
+	if h.registry.guard != nil {
+		route.BindFunc(h.registry.guard)
+	}
 	if meta.AuthRequired {
 		route.BindFunc(RequireAuth())
 	}
@@ -272,8 +282,11 @@ func (h *RouteHandler) DELETE(path string, handler func(*core.RequestEvent) erro
 	// Register route metadata (using pre-computed meta values instead of re-applying opts)
 	h.registry.Register(h.domain, path, "DELETE", meta.Description, meta.AuthRequired)
 
-	// Register actual HTTP route with auth middleware if required
+	// Register actual HTTP route with guard and auth middleware
 	route := h.event.Router.DELETE(path, handler)
+	if h.registry.guard != nil {
+		route.BindFunc(h.registry.guard)
+	}
 	if meta.AuthRequired {
 		route.BindFunc(RequireAuth())
 	}
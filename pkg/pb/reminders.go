@@ -0,0 +1,457 @@
+package wellknown
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/webpush"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/types"
+	calendar "google.golang.org/api/calendar/v3"
+)
+
+// ================================================================
+// Event Reminders
+// ================================================================
+// Events created through POST /api/calendar/events are also stored in the
+// calendar_events collection (see pb_migrations/1731200000_init_reminders.go),
+// where they can be edited in the admin UI. A worker scans it every minute
+// and sends each due reminder by email (SMTP_* variables) and web push to the
+// user's push_subscriptions (VAPID_* variables); both use the event tenant's
+// overrides.
+//
+// Delivery state lives on the event record: it is marked "sending" before
+// anything is sent and each channel is flagged once it went out, so a retry
+// never repeats a channel. An event left "sending" by a crash is marked
+// failed on startup rather than retried, because whether its reminder went
+// out is unknown - a missed reminder is preferred over a duplicate one.
+
+const (
+	calendarEventsCollection    = "calendar_events"
+	pushSubscriptionsCollection = "push_subscriptions"
+
+	reminderJob         = "event_reminders"
+	reminderBatch       = 100 // Events handled per scan
+	maxReminderAttempts = 5
+)
+
+// Reminder states (reminder_status)
+const (
+	reminderPending = "pending"
+	reminderSending = "sending"
+	reminderSent    = "sent"
+	reminderFailed  = "failed"
+)
+
+// storeCalendarEvent saves an event created in Google Calendar so the
+// reminder worker picks it up
+func storeCalendarEvent(wk *Wellknown, tenant *Tenant, userID string, event *calendar.Event) error {
+	collection, err := wk.FindCollectionByNameOrId(calendarEventsCollection)
+	if err != nil {
+		return err
+	}
+	start, err := eventTime(event.Start)
+	if err != nil {
+		return fmt.Errorf("event %s: invalid start: %w", event.Id, err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("user_id", userID)
+	record.Set("tenant", tenantSlug(tenant))
+	record.Set("google_event_id", event.Id)
+	record.Set("title", event.Summary)
+	record.Set("location", event.Location)
+	record.Set("description", event.Description)
+	record.Set("html_link", event.HtmlLink)
+	record.Set("start", start)
+	if end, err := eventTime(event.End); err == nil {
+		record.Set("end", end)
+	}
+	if event.Start != nil {
+		record.Set("timezone", event.Start.TimeZone)
+	}
+	// The event's earliest popup/email reminder, if it set any
+	if event.Reminders != nil {
+		var before int64
+		for _, r := range event.Reminders.Overrides {
+			before = max(before, r.Minutes)
+		}
+		record.Set("remind_before", before)
+	}
+	return wk.Save(record)
+}
+
+// eventTime parses a Calendar API date-time (all-day events start at 00:00 UTC)
+func eventTime(dt *calendar.EventDateTime) (time.Time, error) {
+	switch {
+	case dt == nil:
+		return time.Time{}, errors.New("missing")
+	case dt.DateTime != "":
+		return time.Parse(time.RFC3339, dt.DateTime)
+	default:
+		return time.Parse(time.DateOnly, dt.Date)
+	}
+}
+
+// reminderOverlay returns the registry values for a tenant slug ("" = default)
+func reminderOverlay(wk *Wellknown, slug string) (*env.Overlay, error) {
+	if slug == "" {
+		return EnvRegistry.NewOverlay(nil)
+	}
+	t, err := wk.FindTenant(slug)
+	if err != nil {
+		return nil, err
+	}
+	return t.Env, nil
+}
+
+// scheduleReminder sets remind_at from start and remind_before, and makes a
+// rescheduled event pending again
+func scheduleReminder(record *core.Record) {
+	before := record.GetInt("remind_before")
+	if before <= 0 {
+		before = EnvRegistry.ByName("REMINDER_LEAD_MINUTES").GetInt()
+	}
+	remindAt := record.GetDateTime("start").Time().Add(-time.Duration(before) * time.Minute)
+	if record.GetDateTime("remind_at").Time().Equal(remindAt) && record.GetString("reminder_status") != "" {
+		return
+	}
+	if record.GetString("reminder_status") == reminderSending {
+		return // The worker owns it until it finishes
+	}
+	record.Set("remind_at", remindAt)
+	record.Set("reminder_status", reminderPending)
+	record.Set("reminder_email_sent", false)
+	record.Set("reminder_push_sent", false)
+	record.Set("reminder_attempts", 0)
+	record.Set("reminder_error", "")
+	record.Set("reminded_at", nil)
+}
+
+// reminderWorker sends due reminders
+type reminderWorker struct {
+	wk      *Wellknown
+	client  *http.Client
+	running sync.Mutex // Scans don't overlap when one outlasts the cron interval
+}
+
+// recover marks events a previous process left "sending" as failed
+func (w *reminderWorker) recover() {
+	records, err := w.wk.FindAllRecords(calendarEventsCollection, dbx.HashExp{"reminder_status": reminderSending})
+	if err != nil {
+		log.Printf("⚠️  Reminders: failed to check interrupted reminders: %v", err)
+		return
+	}
+	for _, record := range records {
+		record.Set("reminder_status", reminderFailed)
+		record.Set("reminder_error", "interrupted while sending; not retried to avoid a duplicate reminder")
+		if err := w.wk.Save(record); err != nil {
+			log.Printf("⚠️  Reminders: event %s: %v", record.Id, err)
+		}
+	}
+	if len(records) > 0 {
+		log.Printf("⚠️  Reminders: %d interrupted reminder(s) marked failed", len(records))
+	}
+}
+
+// scan sends the reminders that are due
+func (w *reminderWorker) scan() {
+	if !w.running.TryLock() {
+		return
+	}
+	defer w.running.Unlock()
+
+	now := time.Now().UTC()
+	records, err := w.wk.FindRecordsByFilter(calendarEventsCollection,
+		"reminder_status = {:status} && remind_at <= {:now}", "remind_at", reminderBatch, 0,
+		dbx.Params{"status": reminderPending, "now": types.NowDateTime().String()})
+	if err != nil {
+		log.Printf("⚠️  Reminders: scan failed: %v", err)
+		return
+	}
+	for _, record := range records {
+		if !record.GetDateTime("start").Time().After(now) {
+			// Due while the server was down; a reminder after the start is noise
+			record.Set("reminder_status", reminderFailed)
+			record.Set("reminder_error", "missed: the event started before the reminder was sent")
+			if err := w.wk.Save(record); err != nil {
+				log.Printf("⚠️  Reminders: event %s: %v", record.Id, err)
+			}
+			continue
+		}
+		w.remind(record)
+	}
+}
+
+// remind sends one event's reminder, persisting progress around each channel
+func (w *reminderWorker) remind(record *core.Record) {
+	record.Set("reminder_status", reminderSending)
+	if err := w.wk.Save(record); err != nil {
+		log.Printf("⚠️  Reminders: event %s not claimed: %v", record.Id, err)
+		return
+	}
+
+	err := w.deliver(record)
+	attempts := record.GetInt("reminder_attempts") + 1
+	record.Set("reminder_attempts", attempts)
+	switch {
+	case err == nil:
+		record.Set("reminder_status", reminderSent)
+		record.Set("reminder_error", "")
+		record.Set("reminded_at", time.Now().UTC())
+		log.Printf("🔔 Reminder sent for event %s (%s)", record.Id, record.GetString("title"))
+	case attempts >= maxReminderAttempts:
+		record.Set("reminder_status", reminderFailed)
+		record.Set("reminder_error", err.Error())
+		log.Printf("⚠️  Reminders: event %s failed after %d attempts: %v", record.Id, attempts, err)
+	default:
+		record.Set("reminder_status", reminderPending) // Retried on the next scan
+		record.Set("reminder_error", err.Error())
+	}
+	if err := w.wk.Save(record); err != nil {
+		log.Printf("⚠️  Reminders: event %s: %v", record.Id, err)
+	}
+}
+
+// deliver sends the channels not yet sent, flagging each as it succeeds
+func (w *reminderWorker) deliver(record *core.Record) error {
+	src, err := reminderOverlay(w.wk, record.GetString("tenant"))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, ch := range []struct {
+		flag string
+		send func(*env.Overlay, *core.Record) error
+	}{
+		{"reminder_email_sent", w.sendEmail},
+		{"reminder_push_sent", w.sendPush},
+	} {
+		if record.GetBool(ch.flag) {
+			continue
+		}
+		if err := ch.send(src, record); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		record.Set(ch.flag, true)
+		if err := w.wk.Save(record); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendEmail emails the reminder; without SMTP_HOST or a recipient there is
+// nothing to send
+func (w *reminderWorker) sendEmail(src *env.Overlay, record *core.Record) error {
+	host := src.GetString("SMTP_HOST")
+	from := src.GetString("SMTP_FROM_EMAIL")
+	if host == "" || from == "" {
+		return nil
+	}
+	to := record.GetString("email")
+	if to == "" {
+		if user, err := w.wk.FindRecordById("users", record.GetString("user_id")); err == nil {
+			to = user.Email()
+		}
+	}
+	if to == "" {
+		return nil
+	}
+
+	port := src.GetInt("SMTP_PORT")
+	client := &mailer.SMTPClient{
+		Host:     host,
+		Port:     port,
+		Username: src.GetString("SMTP_USERNAME"),
+		Password: src.GetString("SMTP_PASSWORD"),
+		TLS:      port == 465, // Implicit TLS; other ports use STARTTLS
+	}
+	subject, body := reminderText(record)
+	err := client.Send(&mailer.Message{
+		From:    mail.Address{Name: src.GetString("SMTP_FROM_NAME"), Address: from},
+		To:      []mail.Address{{Address: to}},
+		Subject: subject,
+		Text:    body,
+	})
+	if err != nil {
+		return fmt.Errorf("email to %s: %w", to, err)
+	}
+	return nil
+}
+
+// sendPush notifies every push subscription of the event's user. Expired
+// subscriptions are deleted; the channel fails only if no subscription
+// received the reminder.
+func (w *reminderWorker) sendPush(src *env.Overlay, record *core.Record) error {
+	vapid := webpush.VAPID{
+		PublicKey:  src.GetString("VAPID_PUBLIC_KEY"),
+		PrivateKey: src.GetString("VAPID_PRIVATE_KEY"),
+		Subject:    src.GetString("VAPID_SUBJECT"),
+	}
+	if vapid.PublicKey == "" || vapid.PrivateKey == "" {
+		return nil
+	}
+	subs, err := w.wk.FindAllRecords(pushSubscriptionsCollection, dbx.HashExp{
+		"user_id": record.GetString("user_id"),
+		"tenant":  record.GetString("tenant"),
+	})
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+
+	title, body := reminderText(record)
+	payload, _ := json.Marshal(map[string]string{
+		"title": title,
+		"body":  body,
+		"url":   record.GetString("html_link"),
+	})
+	ttl := time.Until(record.GetDateTime("start").Time())
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var errs []error
+	for _, s := range subs {
+		var sub webpush.Subscription
+		sub.Endpoint = s.GetString("endpoint")
+		sub.Keys.P256dh = s.GetString("p256dh")
+		sub.Keys.Auth = s.GetString("auth")
+
+		err := webpush.Send(ctx, w.client, sub, payload, vapid, ttl)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, webpush.ErrSubscriptionGone):
+			if err := w.wk.Delete(s); err != nil {
+				log.Printf("⚠️  Reminders: push subscription %s not deleted: %v", s.Id, err)
+			}
+		default:
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("push: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// reminderText returns the subject and body of an event's reminder, with
+// times in the event's time zone
+func reminderText(record *core.Record) (subject, body string) {
+	loc, err := time.LoadLocation(record.GetString("timezone"))
+	if err != nil {
+		loc = time.UTC
+	}
+	start := record.GetDateTime("start").Time().In(loc)
+	title := record.GetString("title")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nStarts: %s\n", title, start.Format("Mon 2 Jan 2006 15:04 MST"))
+	if where := record.GetString("location"); where != "" {
+		fmt.Fprintf(&b, "Where: %s\n", where)
+	}
+	if desc := record.GetString("description"); desc != "" {
+		fmt.Fprintf(&b, "\n%s\n", desc)
+	}
+	if link := record.GetString("html_link"); link != "" {
+		fmt.Fprintf(&b, "\n%s\n", link)
+	}
+	return "Reminder: " + title + " at " + start.Format("15:04"), b.String()
+}
+
+// RegisterReminders starts the reminder worker and registers the web push
+// subscription routes
+func RegisterReminders(wk *Wellknown, e *core.ServeEvent, registry *RouteRegistry) {
+	// Pre-flight check: Validate required collections exist
+	for _, collectionName := range []string{calendarEventsCollection, pushSubscriptionsCollection} {
+		if _, err := wk.FindCollectionByNameOrId(collectionName); err != nil {
+			log.Printf("⚠️  Reminders NOT started: collection '%s' not found (migrations may not have run)", collectionName)
+			log.Printf("   Run 'go run . migrate up' to create required collections")
+			return
+		}
+	}
+
+	// Keep remind_at in step with the event's start, however it is edited
+	wk.OnRecordValidate(calendarEventsCollection).BindFunc(func(e *core.RecordEvent) error {
+		scheduleReminder(e.Record)
+		return e.Next()
+	})
+
+	worker := &reminderWorker{wk: wk, client: &http.Client{Timeout: 15 * time.Second}}
+	worker.recover()
+	wk.Cron().MustAdd(reminderJob, "* * * * *", worker.scan)
+	log.Printf("✅ Reminders: scanning %s every minute (%d min before by default)",
+		calendarEventsCollection, EnvRegistry.ByName("REMINDER_LEAD_MINUTES").GetInt())
+
+	handler := NewRouteHandler(registry, "Reminders", e)
+
+	handler.GET("/api/push/vapid-key", func(c *core.RequestEvent) error {
+		src, err := reminderOverlay(wk, tenantSlug(TenantFromRequest(c)))
+		if err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to load tenant settings", err)
+		}
+		key := src.GetString("VAPID_PUBLIC_KEY")
+		if key == "" {
+			return problemJSON(c, ProblemNotFound, "Web push is not configured")
+		}
+		return c.JSON(http.StatusOK, map[string]string{"publicKey": key})
+	}, WithDescription("VAPID public key for PushManager.subscribe (applicationServerKey)"))
+
+	handler.POST("/api/push/subscriptions", func(c *core.RequestEvent) error {
+		var sub webpush.Subscription
+		if err := c.BindBody(&sub); err != nil || sub.Endpoint == "" || sub.Keys.P256dh == "" || sub.Keys.Auth == "" {
+			return problemJSON(c, ProblemBadRequest, "Body must be a PushSubscription (endpoint and keys)")
+		}
+		if _, err := webpush.Encrypt(sub, nil); err != nil {
+			return problemJSON(c, ProblemBadRequest, err.Error())
+		}
+
+		record, err := wk.FindFirstRecordByData(pushSubscriptionsCollection, "endpoint", sub.Endpoint)
+		if err != nil {
+			collection, err := wk.FindCollectionByNameOrId(pushSubscriptionsCollection)
+			if err != nil {
+				return internalProblem(c, ProblemInternal, "Failed to save subscription", err)
+			}
+			record = core.NewRecord(collection)
+			record.Set("endpoint", sub.Endpoint)
+		}
+		record.Set("user_id", c.Auth.Id)
+		record.Set("tenant", tenantSlug(TenantFromRequest(c)))
+		record.Set("p256dh", sub.Keys.P256dh)
+		record.Set("auth", sub.Keys.Auth)
+		if err := wk.Save(record); err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to save subscription", err)
+		}
+		return c.JSON(http.StatusCreated, map[string]string{"id": record.Id})
+	}, WithAuth(), WithDescription("Subscribe this browser to event reminders (PushSubscription JSON)"))
+
+	handler.DELETE("/api/push/subscriptions", func(c *core.RequestEvent) error {
+		var req struct {
+			Endpoint string `json:"endpoint"`
+		}
+		if err := c.BindBody(&req); err != nil || req.Endpoint == "" {
+			return problemJSON(c, ProblemBadRequest, "Body must be {\"endpoint\": ...}")
+		}
+		record, err := wk.FindFirstRecordByData(pushSubscriptionsCollection, "endpoint", req.Endpoint)
+		if err != nil || record.GetString("user_id") != c.Auth.Id {
+			return problemJSON(c, ProblemNotFound, "Subscription not found")
+		}
+		if err := wk.Delete(record); err != nil {
+			return internalProblem(c, ProblemInternal, "Failed to delete subscription", err)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}, WithAuth(), WithDescription("Unsubscribe a browser from event reminders"))
+}
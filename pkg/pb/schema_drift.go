@@ -0,0 +1,152 @@
+package wellknown
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/joeblew999/wellknown/pkg/testgen"
+)
+
+// ================================================================
+// Schema Drift
+// ================================================================
+// JSON Schemas with an x-collection keyword are the canonical definitions of
+// their PocketBase collections (see pkg/schema/pocketbase_drift.go); those of
+// this package's collections live in pkg/pb/schemas. When the server starts,
+// the live collections are compared with them and drift - missing
+// collections or fields, type mismatches - is logged
+// (SCHEMA_DRIFT_CHECK=warn), fails the start (fail) or is not checked (off).
+// In fail mode, a check that cannot run or finds no canonical schemas also
+// fails the start.
+// "wellknown schema drift" runs the same check and can write a corrective
+// migration.
+
+// Schema drift check modes (SCHEMA_DRIFT_CHECK)
+const (
+	SchemaDriftWarn = "warn"
+	SchemaDriftFail = "fail"
+	SchemaDriftOff  = "off"
+)
+
+// CanonicalCollections returns the collections defined by the x-collection
+// schemas under root
+func CanonicalCollections(root string) ([]*schema.PBCollection, error) {
+	found, err := testgen.Discover(root, testgen.DefaultSchemaGlobs)
+	if err != nil {
+		return nil, err
+	}
+	var collections []*schema.PBCollection
+	for _, s := range found {
+		data, err := os.ReadFile(s.SchemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+		name := schema.SchemaCollectionName(data)
+		if name == "" {
+			continue
+		}
+		c, err := schema.PocketBaseCollection(name, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.SchemaPath, err)
+		}
+		collections = append(collections, c)
+	}
+	return collections, nil
+}
+
+// LiveCollection reads a collection from the database in the form
+// schema.DiffCollection compares (nil when it does not exist). Relation
+// targets are collection names.
+func LiveCollection(app core.App, name string) (*schema.PBCollection, error) {
+	collection, err := app.FindCollectionByNameOrId(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find collection %s: %w", name, err)
+	}
+
+	data, err := json.Marshal(collection.Fields)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := schema.ParsePBFields(data)
+	if err != nil {
+		return nil, fmt.Errorf("collection %s: %w", name, err)
+	}
+	for i, f := range fields {
+		if f.Type != schema.PBFieldRelation {
+			continue
+		}
+		if target, err := app.FindCachedCollectionByNameOrId(f.CollectionID); err == nil {
+			fields[i].CollectionID = target.Name
+		}
+	}
+	return &schema.PBCollection{Name: collection.Name, Type: collection.Type, Fields: fields}, nil
+}
+
+// CheckSchemaDrift compares the canonical collections under root with the
+// database. It returns the canonical collections (for
+// schema.PocketBaseDriftMigration) and the drift found.
+func CheckSchemaDrift(app core.App, root string) ([]*schema.PBCollection, []schema.PBDrift, error) {
+	want, err := CanonicalCollections(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	var drifts []schema.PBDrift
+	for _, c := range want {
+		live, err := LiveCollection(app, c.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		drifts = append(drifts, schema.DiffCollection(c, live)...)
+	}
+	return want, drifts, nil
+}
+
+// checkSchemaDriftOnServe runs the startup check as SCHEMA_DRIFT_CHECK says
+func checkSchemaDriftOnServe(app core.App) error {
+	mode := EnvRegistry.ByName("SCHEMA_DRIFT_CHECK").GetString()
+	if mode == SchemaDriftOff {
+		return nil
+	}
+	root := EnvRegistry.ByName("SCHEMA_ROOT").GetString()
+
+	want, drifts, err := CheckSchemaDrift(app, root)
+	if err != nil {
+		if mode == SchemaDriftFail {
+			return fmt.Errorf("schema drift check failed (SCHEMA_DRIFT_CHECK=%s): %w", mode, err)
+		}
+		log.Printf("⚠️  Schema drift check failed: %v", err)
+		return nil
+	}
+	if len(want) == 0 {
+		// Usually a wrong SCHEMA_ROOT (e.g. the server started outside the repo)
+		if mode == SchemaDriftFail {
+			return fmt.Errorf("schema drift: no x-collection schemas found under %s (SCHEMA_DRIFT_CHECK=%s)", root, mode)
+		}
+		log.Printf("⚠️  Schema drift not checked: no x-collection schemas found under %s", root)
+		return nil
+	}
+	if len(drifts) == 0 {
+		log.Printf("✅ Schema: %d collection(s) match their JSON Schemas", len(want))
+		return nil
+	}
+
+	log.Printf("⚠️  Schema drift: %d difference(s) from the JSON Schemas under %s", len(drifts), root)
+	for _, d := range drifts {
+		log.Printf("   - %s", d)
+	}
+	log.Println("💡 Run 'go run . schema drift --migration pkg/cmd/pocketbase/pb_migrations' to write a corrective migration")
+	if mode == SchemaDriftFail {
+		return fmt.Errorf("schema drift: %d difference(s) (SCHEMA_DRIFT_CHECK=%s)", len(drifts), mode)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package wellknown
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckSchemaDriftOnServe(t *testing.T) {
+	wk := newTokenTestApp(t)
+
+	empty := t.TempDir()
+	drifted := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("schemas", "api_keys.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), `"x-collection": "api_keys"`, `"x-collection": "api_keys_v2"`, 1))
+	if err := os.WriteFile(filepath.Join(drifted, "api_keys.schema.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, mode, root string
+		wantErr          string
+	}{
+		{"matching", SchemaDriftFail, "schemas", ""},
+		{"drift", SchemaDriftFail, drifted, "schema drift: "},
+		{"drift logged", SchemaDriftWarn, drifted, ""},
+		{"no schemas", SchemaDriftFail, empty, "no x-collection schemas found"},
+		{"no schemas logged", SchemaDriftWarn, empty, ""},
+		{"check fails", SchemaDriftFail, filepath.Join(empty, "missing"), "schema drift check failed"},
+		{"check fails logged", SchemaDriftWarn, filepath.Join(empty, "missing"), ""},
+		{"off", SchemaDriftOff, filepath.Join(empty, "missing"), ""},
+	}
+	for _, tt := range tests {
+		t.Setenv("SCHEMA_DRIFT_CHECK", tt.mode)
+		t.Setenv("SCHEMA_ROOT", tt.root)
+		err := checkSchemaDriftOnServe(wk)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package wellknown
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/shortlink"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// ================================================================
+// Short Links (PocketBase)
+// ================================================================
+// The demo server shortens every generated URL (see pkg/shortlink). Embedded
+// in PocketBase it keeps the links in the short_links collection (see
+// pb_migrations/1731400000_init_short_links.go), so codes and click counts
+// survive restarts and can be browsed in the admin UI.
+
+const shortLinksCollection = "short_links"
+
+// ShortLinkStore is a shortlink.Store backed by the short_links collection
+type ShortLinkStore struct {
+	app core.App
+}
+
+// NewShortLinkStore creates a store using app's database
+func NewShortLinkStore(app core.App) *ShortLinkStore {
+	return &ShortLinkStore{app: app}
+}
+
+// Shorten returns the link for target, creating it on first use
+func (s *ShortLinkStore) Shorten(target string) (*shortlink.Link, error) {
+	if target == "" {
+		return nil, errors.New("nothing to shorten")
+	}
+	record, err := s.app.FindFirstRecordByData(shortLinksCollection, "target", target)
+	if err == nil {
+		return linkFromRecord(record), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to find short link: %w", err)
+	}
+
+	collection, err := s.app.FindCollectionByNameOrId(shortLinksCollection)
+	if err != nil {
+		return nil, fmt.Errorf("short links collection not found: %w", err)
+	}
+	code := shortlink.NewCode()
+	for {
+		if _, err := s.app.FindFirstRecordByData(shortLinksCollection, "code", code); errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		code = shortlink.NewCode()
+	}
+	record = core.NewRecord(collection)
+	record.Set("code", code)
+	record.Set("target", target)
+	record.Set("clicks", 0)
+	if err := s.app.Save(record); err != nil {
+		return nil, fmt.Errorf("failed to save short link: %w", err)
+	}
+	return linkFromRecord(record), nil
+}
+
+// Resolve returns the link for code
+func (s *ShortLinkStore) Resolve(code string) (*shortlink.Link, error) {
+	record, err := s.app.FindFirstRecordByData(shortLinksCollection, "code", code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, shortlink.ErrLinkNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find short link %s: %w", code, err)
+	}
+	return linkFromRecord(record), nil
+}
+
+// Click counts a visit to code; the count is updated in a transaction so
+// concurrent clicks are not lost
+func (s *ShortLinkStore) Click(code string) (*shortlink.Link, error) {
+	var link *shortlink.Link
+	err := s.app.RunInTransaction(func(txApp core.App) error {
+		record, err := txApp.FindFirstRecordByData(shortLinksCollection, "code", code)
+		if errors.Is(err, sql.ErrNoRows) {
+			return shortlink.ErrLinkNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find short link %s: %w", code, err)
+		}
+		record.Set("clicks", record.GetInt("clicks")+1)
+		record.Set("last_click", time.Now().UTC())
+		if err := txApp.Save(record); err != nil {
+			return fmt.Errorf("failed to count click on %s: %w", code, err)
+		}
+		link = linkFromRecord(record)
+		return nil
+	})
+	return link, err
+}
+
+// List returns every link, most clicked first
+func (s *ShortLinkStore) List() ([]*shortlink.Link, error) {
+	records, err := s.app.FindRecordsByFilter(shortLinksCollection, "", "-clicks,-created", 0, 0)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to list short links: %w", err)
+	}
+	links := make([]*shortlink.Link, 0, len(records))
+	for _, record := range records {
+		links = append(links, linkFromRecord(record))
+	}
+	return links, nil
+}
+
+func linkFromRecord(record *core.Record) *shortlink.Link {
+	link := &shortlink.Link{
+		Code:      record.GetString("code"),
+		Target:    record.GetString("target"),
+		Clicks:    int64(record.GetInt("clicks")),
+		CreatedAt: record.GetDateTime("created").Time(),
+	}
+	if last := record.GetDateTime("last_click"); !last.IsZero() {
+		t := last.Time()
+		link.LastClick = &t
+	}
+	return link
+}
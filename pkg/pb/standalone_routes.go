@@ -15,7 +15,14 @@ func RegisterDemoRoutes(wk *Wellknown, e *core.ServeEvent, registry *RouteRegist
 
 	// Create a standalone server instance (we'll use its handlers)
 	// Note: We pass "8090" as port since we're embedding in PocketBase
-	standaloneSvr, err := server.New("8090")
+	// Short links are kept in the short_links collection when migrations have run
+	var opts []server.Option
+	if _, err := wk.FindCollectionByNameOrId(shortLinksCollection); err == nil {
+		opts = append(opts, server.WithShortLinks(NewShortLinkStore(wk)))
+	} else {
+		log.Printf("⚠️  Collection '%s' not found - demo short links are kept in memory", shortLinksCollection)
+	}
+	standaloneSvr, err := server.New("8090", opts...)
 	if err != nil {
 		log.Printf("⚠️  Failed to initialize demo routes: %v", err)
 		return
@@ -61,27 +68,13 @@ func RegisterDemoRoutes(wk *Wellknown, e *core.ServeEvent, registry *RouteRegist
 	e.Router.GET("/demo/apple/maps", adaptHandler(mux, "/apple/maps", "Apple Maps stub"))
 	registry.Register("Demo", "/demo/apple/maps", "GET", "Apple Maps URL generator (stub)", false)
 
-	// GCP Setup Wizard routes
-	e.Router.GET("/demo/tools/gcp-setup", adaptHandler(mux, "/tools/gcp-setup", "GCP setup wizard"))
-	registry.Register("Demo", "/demo/tools/gcp-setup", "GET", "GCP OAuth setup wizard", false)
-
-	// GCP Setup API endpoints (all POST requests)
-	gcpAPIRoutes := []string{
-		"/demo/api/gcp-setup/create-project",
-		"/demo/api/gcp-setup/enable-apis",
-		"/demo/api/gcp-setup/create-credentials",
-		"/demo/api/gcp-setup/configure-consent",
-		"/demo/api/gcp-setup/save-env",
-		"/demo/api/gcp-setup/status",
-	}
-
-	for _, route := range gcpAPIRoutes {
-		// Map /demo/api/gcp-setup/* to /api/gcp-setup/*
-		originalRoute := "/api" + route[10:] // Remove /demo prefix
-		e.Router.POST(route, adaptHandler(mux, originalRoute, "GCP setup API"))
-	}
-
-	registry.Register("Demo", "/demo/api/gcp-setup/*", "POST", "GCP setup API endpoints", false)
+	// Short links and click stats
+	e.Router.GET("/demo/s/{code}", func(e *core.RequestEvent) error {
+		return adaptHandler(mux, "/s/"+e.Request.PathValue("code"), "Short link redirect")(e)
+	})
+	e.Router.GET("/demo/tools/stats", adaptHandler(mux, "/tools/stats", "Short link stats"))
+	registry.Register("Demo", "/demo/s/{code}", "GET", "Short link redirect (counts clicks)", false)
+	registry.Register("Demo", "/demo/tools/stats", "GET", "Short link click stats", false)
 
 	log.Println("   ✅ Demo routes registered successfully")
 }
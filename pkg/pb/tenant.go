@@ -0,0 +1,257 @@
+package wellknown
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/pocketbase/pocketbase/core"
+	"golang.org/x/oauth2"
+)
+
+// ================================================================
+// Multi-Tenancy
+// ================================================================
+// One deployment can serve several customers. Each request is resolved to a
+// tenant (X-Tenant header, else the subdomain of TENANT_BASE_DOMAIN); requests
+// with neither belong to the default tenant (nil), which is how a
+// single-tenant deployment keeps working unchanged.
+//
+// Tenants are records in the tenants collection (see
+// pb_migrations/1731000000_init_tenants.go). Their env field overrides
+// registry variables for that tenant only - e.g. a customer's own
+// GOOGLE_CLIENT_ID - and tenant-scoped collections (google_tokens, flow_runs,
+// flow_triggers) carry a tenant field holding the slug.
+//
+// Users carry the same field, set when they sign up (pb_migrations/
+// 1731600000_users_tenant.go). A signed-in user's requests must resolve to
+// their own tenant, else 403, so the X-Tenant header can't reach another
+// customer's data; superusers may pick any tenant.
+
+const (
+	tenantsCollection = "tenants"
+
+	// TenantHeader selects a tenant explicitly (takes precedence over the subdomain)
+	TenantHeader = "X-Tenant"
+
+	tenantStoreKey = "wellknown.tenant" // RequestEvent store key
+)
+
+// ErrTenantNotFound is returned for an unknown or inactive tenant slug
+var ErrTenantNotFound = errors.New("tenant not found")
+
+// Tenant is one customer of a multi-tenant deployment
+type Tenant struct {
+	ID   string
+	Slug string
+	Name string
+	Env  *env.Overlay // Registry values with this tenant's overrides applied
+
+	googleOnce   sync.Once
+	googleConfig *oauth2.Config
+}
+
+// tenantSlug returns t's slug, or "" for the default tenant (nil)
+func tenantSlug(t *Tenant) string {
+	if t == nil {
+		return ""
+	}
+	return t.Slug
+}
+
+// Config loads the application configuration with the tenant's overrides
+func (t *Tenant) Config() (*Config, error) {
+	return LoadConfigFrom(t.Env)
+}
+
+// GoogleConfig returns the OAuth config for t: the tenant's own when it
+// overrides any GOOGLE_* variable, else the deployment's
+func (t *Tenant) GoogleConfig(wk *Wellknown) *oauth2.Config {
+	if t == nil || !t.overridesGoogle() {
+		if wk.oauthService == nil {
+			return nil
+		}
+		return wk.oauthService.GoogleConfig
+	}
+	t.googleOnce.Do(func() {
+		cfg, err := t.Config()
+		if err != nil {
+			log.Printf("⚠️  Tenant %s: %v", t.Slug, err)
+			return
+		}
+		t.googleConfig = cfg.OAuth.Google.ToOAuth2Config()
+	})
+	return t.googleConfig
+}
+
+func (t *Tenant) overridesGoogle() bool {
+	for _, name := range t.Env.Overrides() {
+		if strings.HasPrefix(name, "GOOGLE_") {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveTenantSlug returns the tenant slug a request asks for: the
+// X-Tenant header, else the first label of a host under baseDomain. "" means
+// the default tenant.
+func ResolveTenantSlug(r *http.Request, baseDomain string) string {
+	if slug := strings.TrimSpace(r.Header.Get(TenantHeader)); slug != "" {
+		return strings.ToLower(slug)
+	}
+	if baseDomain == "" {
+		return ""
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	sub, ok := strings.CutSuffix(host, "."+strings.ToLower(baseDomain))
+	if !ok || sub == "" {
+		return ""
+	}
+	// Only the label directly below the base domain (a.b.base -> b)
+	return sub[strings.LastIndex(sub, ".")+1:]
+}
+
+// tenantCache holds loaded tenants by slug, cleared whenever a tenant changes
+type tenantCache struct {
+	mu      sync.RWMutex
+	tenants map[string]*Tenant
+}
+
+func (c *tenantCache) get(slug string) (*Tenant, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	t, ok := c.tenants[slug]
+	return t, ok
+}
+
+func (c *tenantCache) put(t *Tenant) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tenants == nil {
+		c.tenants = make(map[string]*Tenant)
+	}
+	c.tenants[t.Slug] = t
+}
+
+func (c *tenantCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tenants = nil
+}
+
+// FindTenant loads an active tenant by slug
+func (wk *Wellknown) FindTenant(slug string) (*Tenant, error) {
+	if t, ok := wk.tenants.get(slug); ok {
+		return t, nil
+	}
+
+	record, err := wk.FindFirstRecordByData(tenantsCollection, "slug", slug)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !record.GetBool("active")) {
+		return nil, ErrTenantNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenant %s: %w", slug, err)
+	}
+
+	var overrides map[string]string
+	if err := record.UnmarshalJSONField("env", &overrides); err != nil {
+		return nil, fmt.Errorf("tenant %s: invalid env: %w", slug, err)
+	}
+	overlay, err := EnvRegistry.NewOverlay(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", slug, err)
+	}
+
+	t := &Tenant{
+		ID:   record.Id,
+		Slug: slug,
+		Name: record.GetString("name"),
+		Env:  overlay,
+	}
+	wk.tenants.put(t)
+	return t, nil
+}
+
+// TenantFromRequest returns the tenant resolved by the tenant middleware
+// (nil for the default tenant)
+func TenantFromRequest(e *core.RequestEvent) *Tenant {
+	t, _ := e.Get(tenantStoreKey).(*Tenant)
+	return t
+}
+
+// userInTenant reports whether auth may act in tenant t. Users are bound to
+// the tenant they signed up under; other requests are not restricted.
+func userInTenant(auth *core.Record, t *Tenant) bool {
+	if auth == nil || auth.Collection().Name != "users" {
+		return true
+	}
+	return auth.GetString("tenant") == tenantSlug(t)
+}
+
+// RegisterTenantMiddleware resolves the tenant of every request and keeps the
+// tenant cache in sync with the tenants collection
+func RegisterTenantMiddleware(wk *Wellknown, e *core.ServeEvent) {
+	if _, err := wk.FindCollectionByNameOrId(tenantsCollection); err != nil {
+		log.Printf("⚠️  Multi-tenancy disabled: collection '%s' not found (migrations may not have run)", tenantsCollection)
+		return
+	}
+
+	// Reject overrides the registry can't apply (unknown names, bad types)
+	wk.OnRecordValidate(tenantsCollection).BindFunc(func(e *core.RecordEvent) error {
+		var overrides map[string]string
+		if err := e.Record.UnmarshalJSONField("env", &overrides); err != nil {
+			return fmt.Errorf("env must be an object of string values: %w", err)
+		}
+		if _, err := EnvRegistry.NewOverlay(overrides); err != nil {
+			return err
+		}
+		return e.Next()
+	})
+	clearCache := func(e *core.RecordEvent) error {
+		wk.tenants.clear()
+		return e.Next()
+	}
+	wk.OnRecordAfterUpdateSuccess(tenantsCollection).BindFunc(clearCache)
+	wk.OnRecordAfterDeleteSuccess(tenantsCollection).BindFunc(clearCache)
+
+	baseDomain := EnvRegistry.ByName("TENANT_BASE_DOMAIN").GetString()
+	e.Router.BindFunc(func(e *core.RequestEvent) error {
+		var t *Tenant
+		if slug := ResolveTenantSlug(e.Request, baseDomain); slug != "" {
+			var err error
+			t, err = wk.FindTenant(slug)
+			if errors.Is(err, ErrTenantNotFound) {
+				return problemJSON(e, ProblemUnknownTenant, "Unknown tenant")
+			}
+			if err != nil {
+				return internalProblem(e, ProblemInternal, "Failed to load tenant", err)
+			}
+		}
+		// The header is client-supplied: a signed-in user only gets their own
+		// tenant. Superusers may act on any.
+		if !userInTenant(e.Auth, t) && !e.HasSuperuserAuth() {
+			return problemJSON(e, ProblemForbidden, "Account belongs to another tenant")
+		}
+		if t != nil {
+			e.Set(tenantStoreKey, t)
+		}
+		return e.Next()
+	})
+
+	if baseDomain != "" {
+		log.Printf("✅ Multi-tenancy: tenants resolved from %s header and *.%s", TenantHeader, baseDomain)
+	} else {
+		log.Printf("✅ Multi-tenancy: tenants resolved from %s header", TenantHeader)
+	}
+}
@@ -0,0 +1,124 @@
+package wellknown
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestResolveTenantSlug(t *testing.T) {
+	tests := []struct {
+		host, header, baseDomain, want string
+	}{
+		{"acme.example.com", "", "example.com", "acme"},
+		{"ACME.Example.com:8090", "", "example.com", "acme"}, // Port and case ignored
+		{"eu.acme.example.com", "", "example.com", "acme"},   // Label directly below the base
+		{"acme.example.com", " Globex ", "example.com", "globex"},
+		{"localhost:8090", "globex", "", "globex"}, // Header works without a base domain
+		{"example.com", "", "example.com", ""},
+		{"acme.other.org", "", "example.com", ""},
+		{"notexample.com", "", "example.com", ""},
+		{"acme.example.com", "", "", ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Host = tt.host
+		if tt.header != "" {
+			r.Header.Set(TenantHeader, tt.header)
+		}
+		if got := ResolveTenantSlug(r, tt.baseDomain); got != tt.want {
+			t.Errorf("ResolveTenantSlug(%q, header %q, %q) = %q, want %q", tt.host, tt.header, tt.baseDomain, got, tt.want)
+		}
+	}
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	t.Setenv("TENANT_BASE_DOMAIN", "example.com")
+	wk := newTokenTestApp(t)
+
+	tenants, err := wk.FindCollectionByNameOrId(tenantsCollection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for slug, active := range map[string]bool{"acme": true, "globex": true, "gone": false} {
+		record := core.NewRecord(tenants)
+		record.Set("slug", slug)
+		record.Set("active", active)
+		if err := wk.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	authToken := func(collection, email, tenant string) string {
+		c, err := wk.FindCollectionByNameOrId(collection)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := core.NewRecord(c)
+		record.SetEmail(email)
+		record.SetPassword("password123")
+		if tenant != "" {
+			record.Set("tenant", tenant)
+		}
+		if err := wk.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		token, err := record.NewAuthToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	acmeUser := authToken("users", "ann@acme.test", "acme")
+	defaultUser := authToken("users", "dan@default.test", "")
+	superuser := authToken(core.CollectionNameSuperusers, "root@example.test", "")
+
+	mux := testRouter(t, wk, func(e *core.ServeEvent) {
+		RegisterTenantMiddleware(wk, e)
+		e.Router.GET("/probe", func(e *core.RequestEvent) error {
+			return e.String(http.StatusOK, "tenant="+tenantSlug(TenantFromRequest(e)))
+		})
+	})
+
+	tests := []struct {
+		name, host, header, auth string
+		wantStatus               int
+		wantBody                 string
+	}{
+		{"default", "example.com", "", "", 200, "tenant="},
+		{"subdomain", "acme.example.com", "", "", 200, "tenant=acme"},
+		{"header", "example.com", "globex", "", 200, "tenant=globex"},
+		{"unknown", "nope.example.com", "", "", 404, ""},
+		{"inactive", "example.com", "gone", "", 404, ""},
+		{"own tenant", "acme.example.com", "", acmeUser, 200, "tenant=acme"},
+		{"other tenant by header", "acme.example.com", "globex", acmeUser, 403, ""},
+		{"default tenant", "example.com", "", acmeUser, 403, ""},
+		{"default user", "example.com", "", defaultUser, 200, "tenant="},
+		{"default user in a tenant", "example.com", "acme", defaultUser, 403, ""},
+		{"superuser", "example.com", "globex", superuser, 200, "tenant=globex"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/probe", nil)
+		r.Host = tt.host
+		if tt.header != "" {
+			r.Header.Set(TenantHeader, tt.header)
+		}
+		if tt.auth != "" {
+			r.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.name, rec.Code, tt.wantStatus, rec.Body)
+			continue
+		}
+		if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+			t.Errorf("%s: body = %q, want %q", tt.name, rec.Body, tt.wantBody)
+		}
+		if tt.wantStatus >= 400 && rec.Header().Get("Content-Type") != problemContentType {
+			t.Errorf("%s: Content-Type = %q, want a problem", tt.name, rec.Header().Get("Content-Type"))
+		}
+	}
+}
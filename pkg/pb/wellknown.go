@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 
+	"github.com/joeblew999/wellknown/pkg/flow"
+	"github.com/joeblew999/wellknown/pkg/guard"
+	"github.com/joeblew999/wellknown/pkg/health"
 	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
 	"golang.org/x/oauth2"
 )
@@ -16,6 +21,12 @@ type Wellknown struct {
 	config       *Config
 	registry     *RouteRegistry
 	oauthService *OAuthService
+	flows        map[string]*flow.Runner // Registered via RegisterFlow
+	tenants      tenantCache
+	health       *health.Checker
+
+	tenantFlowsMu sync.Mutex
+	tenantFlows   map[string]*flow.Runner // Per-tenant runners, created on first use
 }
 
 // ServerInfo contains information about the running server
@@ -79,6 +90,7 @@ func NewWithConfig(cfg *Config) (*Wellknown, error) {
 		config:       cfg,
 		registry:     nil, // Set immediately in bindAppHooks
 		oauthService: oauthService,
+		health:       newHealthChecker(app),
 	}
 
 	// Register all lifecycle hooks and initialize route registry
@@ -112,6 +124,9 @@ func bindAppHooks(wk *Wellknown) {
 	// This allows early inspection of available routes
 	wk.registry = NewRouteRegistry()
 
+	// CORS and rate limiting for custom routes (CORS_ORIGINS, RATE_LIMIT_RPS)
+	wk.registry.guard = apis.WrapStdMiddleware(guard.ConfigFromRegistry(EnvRegistry).Middleware())
+
 	// Register system routes in the registry (metadata only, actual routing happens in OnServe)
 	wk.registry.Register("System", "/_/", "GET", "PocketBase Admin UI", false)
 	wk.registry.Register("System", "/api/health", "GET", "Health check endpoint", false)
@@ -125,15 +140,42 @@ func bindAppHooks(wk *Wellknown) {
 
 	// Setup routes on server start (when router is available)
 	wk.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		// Required credentials are only needed to serve (not for env/mcp/migrate commands)
+		if err := ValidateEnv(); err != nil {
+			return fmt.Errorf("environment validation failed: %w\n\n"+
+				"💡 Run 'go run . env list' to see all required variables\n"+
+				"💡 Run 'go run . env validate' for detailed validation", err)
+		}
+
+		// Compare collections with their canonical JSON Schemas (SCHEMA_DRIFT_CHECK)
+		if err := checkSchemaDriftOnServe(e.App); err != nil {
+			return err
+		}
+
 		log.Println("🔗 Wellknown: Registering HTTP routes...")
 
 		// NOTE: Collections are now managed via migrations in cmd/pb_migrations/
 		// No runtime collection creation needed
 
+		// Correlation ID first, so every error (including tenant errors) carries it
+		RegisterRequestIDMiddleware(e)
+
+		// Resolve the tenant of every request before the domain routes run
+		RegisterTenantMiddleware(wk, e)
+
 		// Register domain routes (both registry metadata + actual HTTP handlers)
+		RegisterHealthRoutes(wk, e, wk.registry)
 		RegisterOAuthRoutes(wk, e, wk.registry)
+		if err := RegisterGoogleTokens(wk, e, wk.registry); err != nil {
+			return err
+		}
 		RegisterCalendarRoutes(wk, e, wk.registry)
+		RegisterReminders(wk, e, wk.registry)
+		RegisterEventTransferRoutes(wk, e, wk.registry)
 		RegisterBankingRoutes(wk, e, wk.registry)
+		RegisterFlowRoutes(wk, e, wk.registry)
+		RegisterAPIKeyRoutes(wk, e, wk.registry)
+		RegisterAdminRoutes(wk, e, wk.registry)
 		RegisterDemoRoutes(wk, e, wk.registry)
 
 		// Register root HTML route (shows all endpoints)
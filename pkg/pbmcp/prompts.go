@@ -0,0 +1,264 @@
+package pbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	googlecal "github.com/joeblew999/wellknown/pkg/google/calendar"
+)
+
+// promptArg is a prompt argument. MCP arguments are plain strings; Enum and
+// Default are published in the argument schemas (pocketbase://prompts).
+type promptArg struct {
+	Name        string
+	Title       string
+	Description string
+	Required    bool
+	Enum        []string
+	Default     string
+}
+
+// promptTemplate is a curated prompt: its text is a text/template over the
+// arguments (missing optional arguments render as their Default)
+type promptTemplate struct {
+	Name        string
+	Title       string
+	Description string
+	Args        []promptArg
+	Text        string
+}
+
+// prompts are the curated one-click operations offered to MCP clients
+var prompts = []promptTemplate{
+	{
+		Name:        "calendar_link_from_email",
+		Title:       "Calendar link from email",
+		Description: "Extract the event from an email and build an add-to-calendar link",
+		Args: []promptArg{
+			{Name: "email", Title: "Email", Description: "The email text (headers help resolve relative dates)", Required: true},
+			{Name: "platform", Title: "Calendar", Description: "google: a link; apple: an ICS file", Enum: []string{"google", "apple"}, Default: "google"},
+			{Name: "timezone", Title: "Time zone", Description: "IANA zone for times without one", Default: "UTC"},
+		},
+		Text: `Extract the calendar event from the email below.
+
+1. Find these fields: {{.fields}}. Times without a zone are in {{.timezone}}; if no end is given, assume one hour after the start. Ask me if the date or title is ambiguous.
+2. Show the fields as JSON.
+{{if eq .platform "apple"}}3. Write an ICS file (VCALENDAR with one VEVENT: SUMMARY, DTSTART/DTEND in UTC as 20060102T150405Z, LOCATION, DESCRIPTION) I can open in Apple Calendar.
+{{else}}3. Build the Google Calendar link: {{.baseURL}}?{{.action}}&{{.titleParam}}=<title>&{{.datesParam}}=<start>/<end>, times in UTC as 20060102T150405Z, adding {{.locationParam}}=<location>, {{.detailsParam}}=<description> and {{.guestsParam}}=<comma-separated emails> when present. URL-encode every value.
+{{end}}
+Email:
+"""
+{{.email}}
+"""`,
+	},
+	{
+		Name:        "fill_pdf_form",
+		Title:       "Fill PDF form",
+		Description: "Turn free-form data into the data.json that fills a government PDF form (pdfform 4-fill)",
+		Args: []promptArg{
+			{Name: "form", Title: "Form code", Description: "Form code, e.g. F3520", Required: true},
+			{Name: "data", Title: "Data", Description: "The information to fill in (text or JSON)", Required: true},
+			{Name: "template", Title: "Field template", Description: "The form's field template from 'pdfform 3-inspect' (JSON), if available"},
+		},
+		Text: `Fill form {{.form}} with the data below.
+
+Produce a data.json for 'pdfform 4-fill data.json':
+{"pdf_url": "<path or URL of the {{.form}} PDF>", "fields": {"<field name>": "<value>", ...}}
+{{if .template}}
+Use exactly the field names of this template, and only values its field types allow (checkboxes: "Yes"/"Off"):
+{{.template}}
+{{else}}
+I have no field template: list the fields you would expect on form {{.form}}, and tell me to run 'pdfform 3-inspect' on the downloaded PDF ('pdfform 2-download {{.form}}') to get the exact names.
+{{end}}
+List any field you could not fill from the data, and any value you had to reformat (dates as DD/MM/YYYY).
+
+Data:
+"""
+{{.data}}
+"""`,
+	},
+	{
+		Name:        "query_collection",
+		Title:       "Ask about a collection",
+		Description: "Answer a question from a collection's records",
+		Args: []promptArg{
+			{Name: "collection", Title: "Collection", Description: "Collection name", Required: true},
+			{Name: "question", Title: "Question", Description: "What you want to know", Required: true},
+		},
+		Text: `Answer this question from the "{{.collection}}" collection: {{.question}}
+
+Use list_collections to learn the collection's fields, then query_records with a PocketBase filter, e.g. ` + "`status = 'active' && created >= '2025-01-01'`" + ` (operators: = != > >= < <= ~ for contains, && ||), a sort such as "-created", and pages of at most 50. Say how many records the answer is based on.`,
+	},
+	{
+		Name:        "create_record_from_text",
+		Title:       "Create record from text",
+		Description: "Extract a record from free text and create it after confirmation",
+		Args: []promptArg{
+			{Name: "collection", Title: "Collection", Description: "Collection name", Required: true},
+			{Name: "text", Title: "Text", Description: "Text containing the record's data", Required: true},
+		},
+		Text: `Create a record in the "{{.collection}}" collection from the text below.
+
+Use list_collections to learn the collection's fields and their types. Show me the record as JSON, leaving out fields the text does not mention, and only call create_record once I confirm.
+
+Text:
+"""
+{{.text}}
+"""`,
+	},
+}
+
+// promptContext holds template values available to every prompt
+func promptContext() map[string]string {
+	return map[string]string{
+		"fields":        strings.Join([]string{googlecal.FieldTitle, googlecal.FieldStart, googlecal.FieldEnd, googlecal.FieldLocation, googlecal.FieldDescription, googlecal.FieldAttendees}, ", "),
+		"baseURL":       googlecal.BaseURL,
+		"action":        googlecal.QueryParamAction + "=" + googlecal.ActionParam,
+		"titleParam":    googlecal.FieldMapping[googlecal.FieldTitle],
+		"datesParam":    googlecal.QueryParamDates,
+		"locationParam": googlecal.FieldMapping[googlecal.FieldLocation],
+		"detailsParam":  googlecal.FieldMapping[googlecal.FieldDescription],
+		"guestsParam":   googlecal.QueryParamGuests,
+	}
+}
+
+// mcpPrompt describes p for prompts/list
+func (p promptTemplate) mcpPrompt() *mcp.Prompt {
+	prompt := &mcp.Prompt{Name: p.Name, Title: p.Title, Description: p.Description}
+	for _, arg := range p.Args {
+		desc := arg.Description
+		if len(arg.Enum) > 0 {
+			desc += " (" + strings.Join(arg.Enum, " or ") + ")"
+		}
+		prompt.Arguments = append(prompt.Arguments, &mcp.PromptArgument{
+			Name: arg.Name, Title: arg.Title, Description: desc, Required: arg.Required,
+		})
+	}
+	return prompt
+}
+
+// argumentSchema is the JSON Schema of p's arguments
+func (p promptTemplate) argumentSchema() map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, arg := range p.Args {
+		prop := map[string]interface{}{"type": "string", "title": arg.Title, "description": arg.Description}
+		if len(arg.Enum) > 0 {
+			prop["enum"] = arg.Enum
+		}
+		if arg.Default != "" {
+			prop["default"] = arg.Default
+		}
+		properties[arg.Name] = prop
+		if arg.Required {
+			required = append(required, arg.Name)
+		}
+	}
+	return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+}
+
+// render checks args against p and executes its template
+func (p promptTemplate) render(args map[string]string) (string, error) {
+	values := promptContext()
+	for _, arg := range p.Args {
+		value := strings.TrimSpace(args[arg.Name])
+		if value == "" {
+			if arg.Required {
+				return "", fmt.Errorf("prompt %s: argument %q is required", p.Name, arg.Name)
+			}
+			value = arg.Default
+		}
+		if len(arg.Enum) > 0 && !contains(arg.Enum, value) {
+			return "", fmt.Errorf("prompt %s: %s must be one of %s", p.Name, arg.Name, strings.Join(arg.Enum, ", "))
+		}
+		values[arg.Name] = value
+	}
+
+	tmpl, err := template.New(p.Name).Option("missingkey=error").Parse(p.Text)
+	if err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	if err := tmpl.Execute(&text, values); err != nil {
+		return "", err
+	}
+	return text.String(), nil
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// registerPrompts registers the curated prompts
+func (s *Server) registerPrompts() {
+	for _, p := range prompts {
+		s.server.AddPrompt(p.mcpPrompt(), func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			text, err := p.render(req.Params.Arguments)
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.GetPromptResult{
+				Description: p.Description,
+				Messages: []*mcp.PromptMessage{
+					{Role: "user", Content: &mcp.TextContent{Text: text}},
+				},
+			}, nil
+		})
+		s.prompts = append(s.prompts, p.Name)
+	}
+
+	// Resource: Prompt catalog with argument schemas (for client UIs)
+	s.addResource(
+		&mcp.Resource{
+			URI:         "pocketbase://prompts",
+			Name:        "Prompt Templates",
+			Description: "Curated prompts with JSON Schemas of their arguments",
+			MIMEType:    "application/json",
+		},
+		s.handlePromptsResource,
+	)
+}
+
+func (s *Server) handlePromptsResource(
+	ctx context.Context,
+	req *mcp.ReadResourceRequest,
+) (*mcp.ReadResourceResult, error) {
+	type PromptInfo struct {
+		Name        string                 `json:"name"`
+		Title       string                 `json:"title"`
+		Description string                 `json:"description"`
+		Arguments   map[string]interface{} `json:"arguments"`
+	}
+
+	infos := make([]PromptInfo, 0, len(prompts))
+	for _, p := range prompts {
+		infos = append(infos, PromptInfo{Name: p.Name, Title: p.Title, Description: p.Description, Arguments: p.argumentSchema()})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	data, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal prompts: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      "pocketbase://prompts",
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		},
+	}, nil
+}
@@ -5,13 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/joeblew999/wellknown/pkg/capability"
+	"github.com/joeblew999/wellknown/pkg/env"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // registerResources registers MCP resources (read-only data views)
 func (s *Server) registerResources() {
 	// Resource: Collections schema
-	s.server.AddResource(
+	s.addResource(
 		&mcp.Resource{
 			URI:         "pocketbase://schema/collections",
 			Name:        "Collections Schema",
@@ -22,7 +24,7 @@ func (s *Server) registerResources() {
 	)
 
 	// Resource: Collections list
-	s.server.AddResource(
+	s.addResource(
 		&mcp.Resource{
 			URI:         "pocketbase://collections",
 			Name:        "Collections List",
@@ -31,6 +33,77 @@ func (s *Server) registerResources() {
 		},
 		s.handleCollectionsListResource,
 	)
+
+	// Resource: Capability matrix (which fields each wellknown builder carries)
+	s.addResource(
+		&mcp.Resource{
+			URI:         "wellknown://capabilities",
+			Name:        "Capability Matrix",
+			Description: "Fields each platform/app builder supports (yes, partial or no), to decide which fields to collect",
+			MIMEType:    "application/json",
+		},
+		s.handleCapabilitiesResource,
+	)
+}
+
+// Env registry resource URIs
+const (
+	envReferenceURI = "wellknown://env/reference"
+	envRegistryURI  = "wellknown://env/registry"
+)
+
+// registerEnvResources registers the configuration reference of registry:
+// the CONFIGURATION.md text and the same variables as JSON
+func (s *Server) registerEnvResources(registry *env.Registry) {
+	s.addResource(
+		&mcp.Resource{
+			URI:         envReferenceURI,
+			Name:        "Configuration Reference",
+			Description: "CONFIGURATION.md generated from the env registry: every environment variable with its type, default and meaning",
+			MIMEType:    "text/markdown",
+		},
+		func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{
+					{
+						URI:      envReferenceURI,
+						MIMEType: "text/markdown",
+						Text:     registry.GenerateMarkdownReference(),
+					},
+				},
+			}, nil
+		},
+	)
+
+	s.addResource(
+		&mcp.Resource{
+			URI:         envRegistryURI,
+			Name:        "Env Registry",
+			Description: "Environment variables as JSON (name, group, type, default, required, secret, description); secret defaults are omitted",
+			MIMEType:    "application/json",
+		},
+		func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			data, err := json.MarshalIndent(registry.Reference(), "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal env registry: %w", err)
+			}
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{
+					{
+						URI:      envRegistryURI,
+						MIMEType: "application/json",
+						Text:     string(data),
+					},
+				},
+			}, nil
+		},
+	)
+}
+
+// addResource registers a resource and records its URI for the server State
+func (s *Server) addResource(resource *mcp.Resource, handler mcp.ResourceHandler) {
+	s.server.AddResource(resource, handler)
+	s.resources = append(s.resources, resource.URI)
 }
 
 func (s *Server) handleCollectionsSchemaResource(
@@ -123,3 +196,23 @@ func (s *Server) handleCollectionsListResource(
 		},
 	}, nil
 }
+
+func (s *Server) handleCapabilitiesResource(
+	ctx context.Context,
+	req *mcp.ReadResourceRequest,
+) (*mcp.ReadResourceResult, error) {
+	data, err := json.MarshalIndent(capability.Default(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal capabilities: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      "wellknown://capabilities",
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		},
+	}, nil
+}
@@ -4,21 +4,31 @@ import (
 	"context"
 	"log"
 
+	"github.com/joeblew999/wellknown/pkg/env"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/pocketbase/pocketbase/core"
 )
 
 // Server wraps the MCP server with PocketBase app instance
 type Server struct {
-	server *mcp.Server
-	app    core.App
+	server    *mcp.Server
+	app       core.App
+	tools     []string // Registered tool names (see addTool)
+	resources []string // Registered resource URIs (see addResource)
+	prompts   []string // Registered prompt names (see registerPrompts)
 }
 
+// Server identity reported to MCP clients
+const (
+	serverName    = "pocketbase-mcp"
+	serverVersion = "1.0.0"
+)
+
 // NewServer creates a new MCP server for PocketBase
 func NewServer(app core.App) *Server {
 	impl := &mcp.Implementation{
-		Name:    "pocketbase-mcp",
-		Version: "1.0.0",
+		Name:    serverName,
+		Version: serverVersion,
 	}
 
 	opts := &mcp.ServerOptions{
@@ -38,6 +48,17 @@ func NewServer(app core.App) *Server {
 	// Register resources
 	s.registerResources()
 
+	// Register prompt templates
+	s.registerPrompts()
+
+	return s
+}
+
+// WithEnvRegistry adds resources documenting the variables in registry (see
+// registerEnvResources), so an assistant answers configuration questions from
+// the registry rather than guessing
+func (s *Server) WithEnvRegistry(registry *env.Registry) *Server {
+	s.registerEnvResources(registry)
 	return s
 }
 
@@ -47,5 +68,11 @@ func (s *Server) Run(ctx context.Context) error {
 	log.Println("📡 Listening on stdio for MCP requests")
 	log.Println("💡 Configure in Claude Desktop to enable integration")
 
+	// Let other processes (the /admin/status page) see that we are running
+	if err := s.writeState("stdio"); err != nil {
+		log.Printf("⚠️  MCP state not recorded: %v", err)
+	}
+	defer s.removeState()
+
 	return s.server.Run(ctx, &mcp.StdioTransport{})
 }
@@ -3,8 +3,10 @@ package pbmcp
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/joeblew999/wellknown/pkg/env"
 	"github.com/joeblew999/wellknown/pkg/pbmcp/testutil"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -125,6 +127,8 @@ func TestResourceRegistration(t *testing.T) {
 	expectedResources := map[string]bool{
 		"pocketbase://schema/collections": false,
 		"pocketbase://collections":        false,
+		"pocketbase://prompts":            false,
+		"wellknown://capabilities":        false,
 	}
 
 	for _, resource := range result.Resources {
@@ -140,6 +144,54 @@ func TestResourceRegistration(t *testing.T) {
 	}
 }
 
+// TestEnvResources tests that the env registry is readable as resources
+func TestEnvResources(t *testing.T) {
+	ctx := context.Background()
+	app, err := testutil.NewTestApp()
+	if err != nil {
+		t.Fatalf("Failed to create test app: %v", err)
+	}
+	defer testutil.CleanupTestApp(app)
+
+	registry := env.NewRegistry([]env.EnvVar{
+		{Name: "HTTPS_ENABLED", Description: "Serve HTTPS", Default: "false", Group: "HTTPS"},
+		{Name: "API_TOKEN", Description: "Upstream token", Secret: true, Default: "dev-token"},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	server := NewServer(app).WithEnvRegistry(registry)
+	if _, err := server.server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("Failed to connect server: %v", err)
+	}
+	clientSession, err := mcp.NewClient(testImpl, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer clientSession.Close()
+
+	result, err := clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: envRegistryURI})
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", envRegistryURI, err)
+	}
+	var entries []env.ReferenceEntry
+	if err := json.Unmarshal([]byte(result.Contents[0].Text), &entries); err != nil {
+		t.Fatalf("Failed to parse registry: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Name != "HTTPS_ENABLED" || entries[1].Description != "Serve HTTPS" {
+		t.Errorf("Unexpected registry entries: %+v", entries)
+	}
+	if entries[0].Default != "" {
+		t.Errorf("Secret default should not be published, got %q", entries[0].Default)
+	}
+
+	result, err = clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: envReferenceURI})
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", envReferenceURI, err)
+	}
+	if text := result.Contents[0].Text; !strings.Contains(text, "`HTTPS_ENABLED`") || strings.Contains(text, "dev-token") {
+		t.Errorf("Unexpected reference:\n%s", text)
+	}
+}
+
 // TestListCollectionsTool tests the list_collections tool
 func TestListCollectionsTool(t *testing.T) {
 	ctx := context.Background()
@@ -367,3 +419,40 @@ func TestCreateRecordTool(t *testing.T) {
 		t.Errorf("Expected name to be 'Test User', got %v", output.Record["name"])
 	}
 }
+
+// TestState tests that a running server is visible through its state file
+func TestState(t *testing.T) {
+	app, err := testutil.NewTestApp()
+	if err != nil {
+		t.Fatalf("Failed to create test app: %v", err)
+	}
+	defer testutil.CleanupTestApp(app)
+
+	// No server has run yet
+	state, err := ReadState(app.DataDir())
+	if err != nil || state != nil {
+		t.Fatalf("Expected no state, got %+v (%v)", state, err)
+	}
+
+	server := NewServer(app)
+	if err := server.writeState("stdio"); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	state, err = ReadState(app.DataDir())
+	if err != nil {
+		t.Fatalf("Failed to read state: %v", err)
+	}
+	if !state.Running {
+		t.Error("Expected this process to be reported as running")
+	}
+	if len(state.Tools) != 6 || len(state.Resources) != 4 || len(state.Prompts) != len(prompts) {
+		t.Errorf("Expected 6 tools, 4 resources and %d prompts, got %v, %v and %v", len(prompts), state.Tools, state.Resources, state.Prompts)
+	}
+
+	// Removed on shutdown
+	server.removeState()
+	if state, _ := ReadState(app.DataDir()); state != nil {
+		t.Errorf("Expected state to be removed, got %+v", state)
+	}
+}
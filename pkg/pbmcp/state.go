@@ -0,0 +1,80 @@
+package pbmcp
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// StateFile is written to the app's data directory while an MCP server runs.
+// The MCP server is a separate (stdio) process, so this file is how the web
+// server learns whether one is attached to the same data.
+const StateFile = "mcp_state.json"
+
+// State describes a running MCP server
+type State struct {
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	PID       int       `json:"pid"`
+	Transport string    `json:"transport"`
+	Started   time.Time `json:"started"`
+	Tools     []string  `json:"tools"`
+	Resources []string  `json:"resources"`
+	Prompts   []string  `json:"prompts"`
+	Running   bool      `json:"running"` // Set by ReadState: the process is alive
+}
+
+// writeState records s in the data directory
+func (s *Server) writeState(transport string) error {
+	data, err := json.MarshalIndent(State{
+		Name:      serverName,
+		Version:   serverVersion,
+		PID:       os.Getpid(),
+		Transport: transport,
+		Started:   time.Now().UTC(),
+		Tools:     s.tools,
+		Resources: s.resources,
+		Prompts:   s.prompts,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.app.DataDir(), StateFile), data, 0o644)
+}
+
+// removeState deletes the state file on shutdown
+func (s *Server) removeState() {
+	os.Remove(filepath.Join(s.app.DataDir(), StateFile))
+}
+
+// ReadState returns the state recorded by the last MCP server started on
+// dataDir, or nil if none has run. A server that exited without cleaning up
+// is reported with Running false.
+func ReadState(dataDir string) (*State, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, StateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	state.Running = processAlive(state.PID)
+	return &state, nil
+}
+
+// processAlive reports whether pid is a live process (signal 0 probes
+// without delivering; unsupported on Windows, where it reports false)
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
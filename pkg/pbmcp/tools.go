@@ -11,42 +11,48 @@ import (
 // registerTools registers all MCP tools for PocketBase operations
 func (s *Server) registerTools() {
 	// Tool: List all collections
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "list_collections",
 		Description: "List all PocketBase collections with their schemas",
 	}, s.handleListCollections)
 
 	// Tool: Query records from a collection
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "query_records",
 		Description: "Query records from a PocketBase collection with optional filtering, sorting, and pagination",
 	}, s.handleQueryRecords)
 
 	// Tool: Get a single record by ID
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "get_record",
 		Description: "Get a specific record by its ID from a collection",
 	}, s.handleGetRecord)
 
 	// Tool: Create a new record
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "create_record",
 		Description: "Create a new record in a PocketBase collection",
 	}, s.handleCreateRecord)
 
 	// Tool: Update a record
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "update_record",
 		Description: "Update an existing record in a PocketBase collection",
 	}, s.handleUpdateRecord)
 
 	// Tool: Delete a record
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "delete_record",
 		Description: "Delete a record from a PocketBase collection",
 	}, s.handleDeleteRecord)
 }
 
+// addTool registers a tool and records its name for the server State
+func addTool[In, Out any](s *Server, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) {
+	mcp.AddTool(s.server, tool, handler)
+	s.tools = append(s.tools, tool.Name)
+}
+
 // Input/Output types for tools
 
 type ListCollectionsInput struct{}
@@ -0,0 +1,346 @@
+package pdfform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ================================================================
+// PDF Download Cache
+// ================================================================
+// Downloaded PDFs are stored content-addressed under .data/cache:
+//
+//	cache/blobs/<sha256>.pdf         - PDF bytes, named by content hash
+//	cache/entries/<code>-<sum>.json  - entry per form code + catalog checksum
+//
+// Keying entries by the catalog checksum means editing the catalog (e.g. a
+// new URL for a form) naturally starts a fresh entry. Within the TTL an entry
+// is served without network access; after it, the entry is revalidated with
+// If-None-Match / If-Modified-Since and only re-downloaded if it changed.
+
+// DefaultCacheTTL is how long a cached PDF is served without revalidation
+const DefaultCacheTTL = 24 * time.Hour
+
+// CacheStatus describes how a Fetch was satisfied
+type CacheStatus string
+
+const (
+	CacheHit         CacheStatus = "hit"         // Served from cache within TTL
+	CacheRevalidated CacheStatus = "revalidated" // Server returned 304 Not Modified
+	CacheMiss        CacheStatus = "miss"        // No entry; downloaded
+	CacheRefreshed   CacheStatus = "refreshed"   // Entry was stale and content changed
+)
+
+// CacheEntry is the metadata stored for one cached form
+type CacheEntry struct {
+	Key             string    `json:"key"`
+	FormCode        string    `json:"form_code"`
+	CatalogChecksum string    `json:"catalog_checksum"`
+	URL             string    `json:"url"`
+	ETag            string    `json:"etag,omitempty"`
+	LastModified    string    `json:"last_modified,omitempty"`
+	SHA256          string    `json:"sha256"`
+	Size            int64     `json:"size"`
+	FetchedAt       time.Time `json:"fetched_at"`
+	ValidatedAt     time.Time `json:"validated_at"`
+}
+
+// Expired reports whether the entry is older than ttl since its last validation
+func (e *CacheEntry) Expired(ttl time.Duration) bool {
+	return time.Since(e.ValidatedAt) > ttl
+}
+
+// PDFCache is a content-addressed cache of downloaded PDFs
+type PDFCache struct {
+	Dir    string        // Cache directory (e.g., .data/cache)
+	TTL    time.Duration // Serve without revalidation for this long (default: DefaultCacheTTL)
+	Client *http.Client  // HTTP client (default: DownloadClient())
+}
+
+// NewPDFCache creates a cache rooted at dir with the default TTL
+func NewPDFCache(dir string) *PDFCache {
+	return &PDFCache{Dir: dir, TTL: DefaultCacheTTL}
+}
+
+// CatalogChecksum returns a short SHA-256 of the catalog file, used in cache keys
+func CatalogChecksum(catalogPath string) (string, error) {
+	data, err := os.ReadFile(catalogPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read catalog: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// CacheKey builds the entry key for a form code and catalog checksum
+func CacheKey(formCode, catalogChecksum string) string {
+	return strings.ToLower(formCode) + "-" + catalogChecksum
+}
+
+// BlobPath returns the path of the cached PDF bytes for an entry
+func (c *PDFCache) BlobPath(e *CacheEntry) string {
+	return filepath.Join(c.Dir, "blobs", e.SHA256+".pdf")
+}
+
+func (c *PDFCache) entryPath(key string) string {
+	return filepath.Join(c.Dir, "entries", key+".json")
+}
+
+// Lookup returns the entry for key, or nil if there is none
+func (c *PDFCache) Lookup(key string) (*CacheEntry, error) {
+	data, err := os.ReadFile(c.entryPath(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+	var e CacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("failed to parse cache entry %s: %w", key, err)
+	}
+	// An entry whose blob vanished is treated as missing
+	if _, err := os.Stat(c.BlobPath(&e)); err != nil {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// Fetch returns the cached entry for formCode + catalogChecksum, downloading
+// or revalidating pdfURL as needed
+func (c *PDFCache) Fetch(formCode, catalogChecksum, pdfURL string) (*CacheEntry, CacheStatus, error) {
+	ttl := c.TTL
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+	client := c.Client
+	if client == nil {
+		client = DownloadClient()
+	}
+
+	key := CacheKey(formCode, catalogChecksum)
+	entry, err := c.Lookup(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if entry != nil && entry.URL == pdfURL && !entry.Expired(ttl) {
+		return entry, CacheHit, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, pdfURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build request: %w", err)
+	}
+	if entry != nil && entry.URL == pdfURL {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download PDF: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		entry.ValidatedAt = time.Now()
+		if err := c.saveEntry(entry); err != nil {
+			return nil, "", err
+		}
+		return entry, CacheRevalidated, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download PDF: HTTP %d", resp.StatusCode)
+	}
+
+	sha, size, err := c.storeBlob(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	status := CacheMiss
+	if entry != nil {
+		status = CacheRefreshed
+	}
+	now := time.Now()
+	entry = &CacheEntry{
+		Key:             key,
+		FormCode:        formCode,
+		CatalogChecksum: catalogChecksum,
+		URL:             pdfURL,
+		ETag:            resp.Header.Get("ETag"),
+		LastModified:    resp.Header.Get("Last-Modified"),
+		SHA256:          sha,
+		Size:            size,
+		FetchedAt:       now,
+		ValidatedAt:     now,
+	}
+	if err := c.saveEntry(entry); err != nil {
+		return nil, "", err
+	}
+	return entry, status, nil
+}
+
+// CopyTo copies the cached PDF for entry to outputPath
+func (c *PDFCache) CopyTo(entry *CacheEntry, outputPath string) error {
+	src, err := os.Open(c.BlobPath(entry))
+	if err != nil {
+		return fmt.Errorf("failed to open cached PDF: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return nil
+}
+
+// storeBlob writes r to the blob store, returning its hash and size
+func (c *PDFCache) storeBlob(r io.Reader) (string, int64, error) {
+	blobDir := filepath.Join(c.Dir, "blobs")
+	if err := os.MkdirAll(blobDir, 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(blobDir, "download-*.tmp")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), r)
+	tmp.Close()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to write PDF: %w", err)
+	}
+
+	sha := hex.EncodeToString(h.Sum(nil))
+	if err := os.Rename(tmp.Name(), filepath.Join(blobDir, sha+".pdf")); err != nil {
+		return "", 0, fmt.Errorf("failed to store PDF: %w", err)
+	}
+	return sha, size, nil
+}
+
+func (c *PDFCache) saveEntry(e *CacheEntry) error {
+	if err := os.MkdirAll(filepath.Join(c.Dir, "entries"), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	if err := os.WriteFile(c.entryPath(e.Key), data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// List returns all cache entries, sorted by key
+func (c *PDFCache) List() ([]CacheEntry, error) {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, "entries", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []CacheEntry
+	for _, path := range matches {
+		e, err := c.Lookup(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			entries = append(entries, *e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// Purge removes entries last validated more than olderThan ago (0 removes all),
+// then deletes blobs no remaining entry refers to. Returns the number of entries removed.
+func (c *PDFCache) Purge(olderThan time.Duration) (int, error) {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, "entries", "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	inUse := make(map[string]bool)
+	for _, path := range matches {
+		e, err := c.Lookup(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return removed, err
+		}
+		if e != nil && olderThan > 0 && !e.Expired(olderThan) {
+			inUse[e.SHA256] = true
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+		removed++
+	}
+
+	blobs, err := filepath.Glob(filepath.Join(c.Dir, "blobs", "*"))
+	if err != nil {
+		return removed, err
+	}
+	for _, path := range blobs {
+		if !inUse[strings.TrimSuffix(filepath.Base(path), ".pdf")] {
+			os.Remove(path)
+		}
+	}
+	return removed, nil
+}
+
+// DownloadFormPDFCached is DownloadFormPDF served through cache, with the same
+// failover to mirrors.
+// catalogChecksum comes from CatalogChecksum on the catalog file.
+func (c *FormsCatalog) DownloadFormPDFCached(form *TransferForm, outputDir string, cache *PDFCache, catalogChecksum string) (string, CacheStatus, error) {
+	sources := form.SourceURLs()
+	if len(sources) == 0 {
+		return "", "", fmt.Errorf("form has no direct PDF URL")
+	}
+
+	code := form.FormCode
+	if code == "" {
+		code = strings.ReplaceAll(form.FormName, " ", "_")
+	}
+
+	var entry *CacheEntry
+	var status CacheStatus
+	err := trySources(sources, func(u string) error {
+		var err error
+		entry, status, err = cache.Fetch(code, catalogChecksum, u)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	outputPath := filepath.Join(outputDir, strings.ToLower(code)+".pdf")
+	if err := cache.CopyTo(entry, outputPath); err != nil {
+		return "", "", err
+	}
+	return outputPath, status, nil
+}
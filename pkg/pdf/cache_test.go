@@ -0,0 +1,104 @@
+package pdfform_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+func TestPDFCache_FetchAndRevalidate(t *testing.T) {
+	var downloads, notModified atomic.Int32
+	body := "%PDF-1.4 test"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	cache := pdfform.NewPDFCache(t.TempDir())
+
+	entry, status, err := cache.Fetch("F3520", "abc", srv.URL)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if status != pdfform.CacheMiss {
+		t.Errorf("first fetch status = %s, want miss", status)
+	}
+	if entry.Size != int64(len(body)) || entry.ETag != `"v1"` {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	// Within TTL: no request
+	if _, status, _ = cache.Fetch("F3520", "abc", srv.URL); status != pdfform.CacheHit {
+		t.Errorf("second fetch status = %s, want hit", status)
+	}
+
+	// Expired: conditional request, 304
+	cache.TTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if _, status, _ = cache.Fetch("F3520", "abc", srv.URL); status != pdfform.CacheRevalidated {
+		t.Errorf("stale fetch status = %s, want revalidated", status)
+	}
+
+	if downloads.Load() != 1 || notModified.Load() != 1 {
+		t.Errorf("downloads=%d notModified=%d, want 1 and 1", downloads.Load(), notModified.Load())
+	}
+
+	// New catalog checksum is a new entry
+	if _, status, _ = cache.Fetch("F3520", "def", srv.URL); status != pdfform.CacheMiss {
+		t.Errorf("new checksum status = %s, want miss", status)
+	}
+
+	out := filepath.Join(t.TempDir(), "out.pdf")
+	if err := cache.CopyTo(entry, out); err != nil {
+		t.Fatalf("CopyTo failed: %v", err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != body {
+		t.Errorf("copied content = %q", data)
+	}
+}
+
+func TestPDFCache_ListAndPurge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("%PDF " + r.URL.Path))
+	}))
+	defer srv.Close()
+
+	cache := pdfform.NewPDFCache(t.TempDir())
+	for _, code := range []string{"A1", "B2"} {
+		if _, _, err := cache.Fetch(code, "sum", srv.URL+"/"+code); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := cache.List()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("List = %d entries, %v; want 2", len(entries), err)
+	}
+
+	// Nothing is older than an hour
+	if removed, _ := cache.Purge(time.Hour); removed != 0 {
+		t.Errorf("Purge(1h) removed %d, want 0", removed)
+	}
+
+	removed, err := cache.Purge(0)
+	if err != nil || removed != 2 {
+		t.Errorf("Purge(0) = %d, %v; want 2", removed, err)
+	}
+	blobs, _ := filepath.Glob(filepath.Join(cache.Dir, "blobs", "*"))
+	if len(blobs) != 0 {
+		t.Errorf("orphan blobs left: %v", blobs)
+	}
+}
@@ -1,6 +1,7 @@
 package pdfform
 
 import (
+	"errors"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -14,12 +15,14 @@ type CaseMetadata struct {
 	CaseName  string    `json:"case_name"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Revision  int       `json:"revision"` // Incremented by every SaveCase; checked against the file to detect concurrent edits
 }
 
 // FormReference contains information about the form to fill
 type FormReference struct {
-	FormCode     string `json:"form_code"`
-	TemplatePath string `json:"template_path,omitempty"`
+	FormCode     string       `json:"form_code"`
+	TemplatePath string       `json:"template_path,omitempty"`
+	Version      *FormVersion `json:"version,omitempty"` // Form version the case was created against
 }
 
 // ValidationStatus contains validation results for the case
@@ -32,10 +35,11 @@ type ValidationStatus struct {
 
 // Case represents a complete case with metadata, form reference, and field data
 type Case struct {
-	Metadata      CaseMetadata      `json:"case_metadata"`
-	FormReference FormReference     `json:"form_reference"`
-	Fields        map[string]string `json:"fields"`
-	Validation    *ValidationStatus `json:"validation,omitempty"`
+	Metadata      CaseMetadata       `json:"case_metadata"`
+	FormReference FormReference      `json:"form_reference"`
+	Fields        map[string]string  `json:"fields"`
+	Validation    *ValidationStatus  `json:"validation,omitempty"`
+	History       []CaseHistoryEntry `json:"history,omitempty"` // Audit trail (see RecordCaseOperation)
 }
 
 // LoadCase loads a case from a JSON file
@@ -53,33 +57,129 @@ func LoadCase(casePath string) (*Case, error) {
 	return &c, nil
 }
 
-// SaveCase saves a case to a JSON file
+// SaveCase saves a case to a JSON file.
+//
+// Saves are optimistic: the case must still be at the revision it was loaded
+// with, otherwise another session saved in between and a *CaseConflictError
+// (matching ErrCaseConflict) is returned with the file left untouched. The
+// file is locked for the check and the write, and the revision incremented.
+// The save is recorded in the case history (see SaveCaseWithOptions).
 func SaveCase(c *Case, casePath string) error {
+	return SaveCaseWithOptions(c, casePath, SaveCaseOptions{})
+}
+
+// SaveCaseOptions describes a save for the case history
+type SaveCaseOptions struct {
+	Actor     string        // Who saved (default: DefaultActor)
+	Operation CaseOperation // Recorded operation (default: create for a new file, otherwise save)
+	Detail    string
+}
+
+// SaveCaseWithOptions is SaveCase recording the given actor and operation.
+// The history is always taken from the file, so entries recorded since the
+// case was loaded are kept; a changed validation result is recorded as a
+// status_change.
+func SaveCaseWithOptions(c *Case, casePath string, opts SaveCaseOptions) error {
 	// Ensure directory exists
 	dir := filepath.Dir(casePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create case directory: %w", err)
 	}
 
-	// Update timestamp
-	c.Metadata.UpdatedAt = time.Now()
-
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(c, "", "  ")
+	unlock, err := lockCase(casePath)
 	if err != nil {
-		return fmt.Errorf("failed to marshal case JSON: %w", err)
+		return err
+	}
+	defer unlock()
+
+	// Reject the save if the file moved on since the case was loaded
+	current, err := LoadCase(casePath)
+	switch {
+	case err == nil:
+		if current.Metadata.Revision != c.Metadata.Revision {
+			return &CaseConflictError{Path: casePath, Expected: c.Metadata.Revision, Current: current}
+		}
+	case errors.Is(err, os.ErrNotExist):
+		current = nil
+	default:
+		return err
 	}
 
-	// Write to file
-	if err := os.WriteFile(casePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write case file: %w", err)
+	// Update timestamp and revision on a copy, so a failed write changes nothing
+	saved := *c
+	saved.Metadata.UpdatedAt = time.Now()
+	saved.Metadata.Revision++
+
+	op := opts.Operation
+	saved.History = nil
+	if current != nil {
+		saved.History = append(saved.History, current.History...)
+		if op == "" {
+			op = CaseOpSave
+		}
+	} else if op == "" {
+		op = CaseOpCreate
+	}
+	saved.History = append(saved.History, newHistoryEntry(op, opts.Actor, hashFields(c.Fields), opts.Detail))
+	if current != nil && validationState(current.Validation) != validationState(c.Validation) {
+		saved.History = append(saved.History, newHistoryEntry(CaseOpStatusChange, opts.Actor, "",
+			validationState(current.Validation)+" -> "+validationState(c.Validation)))
 	}
 
+	if err := writeCase(&saved, casePath); err != nil {
+		return err
+	}
+
+	c.Metadata = saved.Metadata
+	c.History = saved.History
 	return nil
 }
 
+// validationState names a validation result for status_change entries
+func validationState(v *ValidationStatus) string {
+	switch {
+	case v == nil:
+		return "unvalidated"
+	case v.Valid:
+		return "valid"
+	}
+	return "invalid"
+}
+
 // CreateCase creates a new case with the given form code and saves it
 func CreateCase(formCode, caseName, entityName string, dataDir string) (*Case, string, error) {
+	return CreateCaseWithOptions(CreateCaseOptions{
+		FormCode:   formCode,
+		CaseName:   caseName,
+		EntityName: entityName,
+		DataDir:    dataDir,
+	})
+}
+
+// CreateCaseOptions contains options for creating a case
+type CreateCaseOptions struct {
+	FormCode    string
+	CaseName    string
+	EntityName  string
+	DataDir     string
+	CatalogPath string // Pin the case to the form's current catalog entry when set (forms not in the catalog stay unpinned)
+	PDFPath     string // Also pin the downloaded form PDF (optional)
+}
+
+// CreateCaseWithOptions creates and saves a new case, pinning its form version
+// when opts.CatalogPath is set
+func CreateCaseWithOptions(opts CreateCaseOptions) (*Case, string, error) {
+	formCode, caseName, entityName, dataDir := opts.FormCode, opts.CaseName, opts.EntityName, opts.DataDir
+
+	var version *FormVersion
+	if opts.CatalogPath != "" {
+		v, err := CaptureFormVersion(opts.CatalogPath, formCode, opts.PDFPath)
+		if err != nil && !errors.Is(err, ErrFormNotInCatalog) {
+			return nil, "", fmt.Errorf("failed to capture form version: %w", err)
+		}
+		version = v
+	}
+
 	// Generate case ID with microseconds for uniqueness
 	timestamp := time.Now().Format("20060102_150405.000000")
 	caseID := fmt.Sprintf("%s_%s_%s", entityName, formCode, timestamp)
@@ -93,6 +193,7 @@ func CreateCase(formCode, caseName, entityName string, dataDir string) (*Case, s
 		},
 		FormReference: FormReference{
 			FormCode: formCode,
+			Version:  version,
 		},
 		Fields: make(map[string]string),
 	}
@@ -110,6 +211,12 @@ func CreateCase(formCode, caseName, entityName string, dataDir string) (*Case, s
 
 // FillFromCase fills a PDF form using data from a case file
 func FillFromCase(casePath, outputDir string, flatten bool) (*FillResult, error) {
+	return FillFromCaseWithOptions(casePath, FillOptions{OutputDir: outputDir, Flatten: flatten})
+}
+
+// FillFromCaseWithOptions is FillFromCase with the full FillOptions
+// (passwords, protection, delivery); opts.DataPath is ignored
+func FillFromCaseWithOptions(casePath string, opts FillOptions) (*FillResult, error) {
 	// Load the case
 	c, err := LoadCase(casePath)
 	if err != nil {
@@ -130,6 +237,8 @@ func FillFromCase(casePath, outputDir string, flatten bool) (*FillResult, error)
 			var template FormData
 			if err := json.Unmarshal(templateData, &template); err == nil {
 				pdfPath = template.PdfURL
+				formData.Engine = template.Engine
+				formData.FieldDefs = template.FieldDefs
 			}
 		}
 	}
@@ -146,7 +255,8 @@ func FillFromCase(casePath, outputDir string, flatten bool) (*FillResult, error)
 		return nil, fmt.Errorf("failed to create temp directory: %w", err)
 	}
 
-	tempJSON := filepath.Join(tempDir, "temp_fill.json")
+	// Named after the case so the output is <case_id>_filled.pdf (see ExportCase)
+	tempJSON := filepath.Join(tempDir, c.Metadata.CaseID+".json")
 	defer os.Remove(tempJSON)
 
 	data, err := json.Marshal(formData)
@@ -158,12 +268,38 @@ func FillFromCase(casePath, outputDir string, flatten bool) (*FillResult, error)
 		return nil, fmt.Errorf("failed to write temp JSON: %w", err)
 	}
 
+	// Warn when the pinned form version no longer matches the catalog or PDF
+	localPDF := pdfPath
+	if isURL(pdfPath) {
+		localPDF = ""
+	}
+	warnings, err := CheckFormVersion(c, opts.CatalogPath, localPDF)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the case ID in the output metadata
+	if opts.Metadata != nil && opts.Metadata.CaseID == "" {
+		meta := *opts.Metadata
+		meta.CaseID = c.Metadata.CaseID
+		opts.Metadata = &meta
+	}
+
 	// Use Fill function
-	return Fill(FillOptions{
-		DataPath:  tempJSON,
-		OutputDir: outputDir,
-		Flatten:   flatten,
-	})
+	opts.DataPath = tempJSON
+	result, err := Fill(opts)
+	if result != nil {
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	entry := newHistoryEntry(CaseOpFill, opts.Actor, HashInput(data), result.OutputPath)
+	if err := RecordCaseOperation(casePath, entry); err != nil {
+		return result, fmt.Errorf("failed to record fill: %w", err)
+	}
+	return result, nil
 }
 
 // ListCases lists all case files for a given entity (or all if entityName is empty)
@@ -0,0 +1,246 @@
+package pdfform
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ================================================================
+// Case Export
+// ================================================================
+// A case bundle is a ZIP for submission packages:
+//
+//	case.json        the case (source field data)
+//	template.json    the form template, when the case references one
+//	filled/*.pdf     every filled PDF produced from the case
+//	manifest.json    audit manifest: SHA-256, size and timestamp of each file
+//
+// Filled PDFs are found by name: filling a case writes <case_id>_filled*.pdf.
+
+// ExportCaseOptions contains options for exporting a case bundle
+type ExportCaseOptions struct {
+	CaseID     string   // Case to export (looked up under DataDir)
+	CasePath   string   // Case file to export (overrides CaseID)
+	DataDir    string   // Data directory containing cases/
+	OutputDirs []string // Directories searched for filled PDFs
+	OutputPath string   // ZIP file to write (default: <case_id>.zip in the current directory)
+	Actor      string   // Recorded in the case history (default: DefaultActor)
+}
+
+// CaseManifest is the audit manifest stored in a case bundle
+type CaseManifest struct {
+	CaseID      string         `json:"case_id"`
+	CaseName    string         `json:"case_name"`
+	FormCode    string         `json:"form_code"`
+	FormVersion *FormVersion   `json:"form_version,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at,omitempty"`
+	ExportedAt  time.Time      `json:"exported_at"`
+	Files       []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes one file in a case bundle
+type ManifestFile struct {
+	Name     string    `json:"name"` // Path inside the ZIP
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+	Modified time.Time `json:"modified"`
+}
+
+// ExportCaseResult contains the results of exporting a case bundle
+type ExportCaseResult struct {
+	OutputPath string
+	Manifest   *CaseManifest
+}
+
+// ExportCase writes a case bundle ZIP to opts.OutputPath
+func ExportCase(opts ExportCaseOptions) (*ExportCaseResult, error) {
+	casePath, err := resolveCasePath(opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.CasePath = casePath
+
+	outputPath := opts.OutputPath
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(filepath.Base(casePath), ".json") + ".zip"
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	tmp := outputPath + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+	manifest, err := WriteCaseBundle(f, opts)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	if err := os.Rename(tmp, outputPath); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return &ExportCaseResult{OutputPath: outputPath, Manifest: manifest}, nil
+}
+
+// WriteCaseBundle streams a case bundle ZIP to w (e.g. an HTTP response)
+func WriteCaseBundle(w io.Writer, opts ExportCaseOptions) (*CaseManifest, error) {
+	casePath, err := resolveCasePath(opts)
+	if err != nil {
+		return nil, err
+	}
+	c, err := LoadCase(casePath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &CaseManifest{
+		CaseID:      c.Metadata.CaseID,
+		CaseName:    c.Metadata.CaseName,
+		FormCode:    c.FormReference.FormCode,
+		FormVersion: c.FormReference.Version,
+		CreatedAt:   c.Metadata.CreatedAt,
+		UpdatedAt:   c.Metadata.UpdatedAt,
+		ExportedAt:  time.Now(),
+	}
+
+	zw := zip.NewWriter(w)
+	if err := addBundleFile(zw, manifest, "case.json", casePath); err != nil {
+		return nil, err
+	}
+	if tp := c.FormReference.TemplatePath; tp != "" {
+		if _, err := os.Stat(tp); err == nil {
+			if err := addBundleFile(zw, manifest, "template.json", tp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	outputs, err := findCaseOutputs(c.Metadata.CaseID, opts.OutputDirs)
+	if err != nil {
+		return nil, err
+	}
+	for _, pdf := range outputs {
+		if err := addBundleFile(zw, manifest, "filled/"+filepath.Base(pdf), pdf); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "manifest.json", Method: zip.Deflate, Modified: manifest.ExportedAt})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if _, err := mw.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+
+	entry := newHistoryEntry(CaseOpDownload, opts.Actor, manifest.Files[0].SHA256, fmt.Sprintf("%d files", len(manifest.Files)))
+	if err := RecordCaseOperation(casePath, entry); err != nil {
+		return nil, fmt.Errorf("failed to record download: %w", err)
+	}
+	return manifest, nil
+}
+
+// resolveCasePath returns opts.CasePath, or finds the case file for opts.CaseID
+func resolveCasePath(opts ExportCaseOptions) (string, error) {
+	if opts.CasePath != "" {
+		return opts.CasePath, nil
+	}
+	if opts.CaseID == "" {
+		return "", fmt.Errorf("case ID or case path is required")
+	}
+	cases, err := ListCases(opts.DataDir, "")
+	if err != nil {
+		return "", err
+	}
+	for _, p := range cases {
+		if filepath.Base(p) == opts.CaseID+".json" {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("case %s not found", opts.CaseID)
+}
+
+// findCaseOutputs lists <caseID>_filled*.pdf in dirs, sorted and de-duplicated
+func findCaseOutputs(caseID string, dirs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var outputs []string
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, caseID+"_filled*.pdf"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", dir, err)
+		}
+		for _, m := range matches {
+			abs, _ := filepath.Abs(m)
+			if !seen[abs] {
+				seen[abs] = true
+				outputs = append(outputs, m)
+			}
+		}
+	}
+	sort.Strings(outputs)
+	return outputs, nil
+}
+
+// addBundleFile copies src into the ZIP as name and records it in the manifest
+func addBundleFile(zw *zip.Writer, manifest *CaseManifest, name, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", src, err)
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	if strings.HasSuffix(name, ".pdf") {
+		header.Method = zip.Store // Already compressed
+	}
+	zf, err := zw.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", src, err)
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(zf, h), f)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", src, err)
+	}
+
+	manifest.Files = append(manifest.Files, ManifestFile{
+		Name:     name,
+		Size:     size,
+		SHA256:   hex.EncodeToString(h.Sum(nil)),
+		Modified: info.ModTime(),
+	})
+	return nil
+}
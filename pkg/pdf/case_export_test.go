@@ -0,0 +1,88 @@
+package pdfform
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportCase(t *testing.T) {
+	tempDir := t.TempDir()
+	outputsDir := filepath.Join(tempDir, "outputs")
+
+	c, _, err := CreateCase("F3520", "Bundle Sale", "test_user", tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(outputsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	filled := []byte("%PDF-1.7 filled")
+	if err := os.WriteFile(filepath.Join(outputsDir, c.Metadata.CaseID+"_filled.pdf"), filled, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outputsDir, "other_case_filled.pdf"), filled, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	zipPath := filepath.Join(tempDir, "bundles", "bundle.zip")
+	result, err := ExportCase(ExportCaseOptions{
+		CaseID:     c.Metadata.CaseID,
+		DataDir:    tempDir,
+		OutputDirs: []string{outputsDir, outputsDir}, // duplicates are ignored
+		OutputPath: zipPath,
+	})
+	if err != nil {
+		t.Fatalf("ExportCase failed: %v", err)
+	}
+	if result.Manifest.CaseID != c.Metadata.CaseID || len(result.Manifest.Files) != 2 {
+		t.Fatalf("unexpected manifest: %+v", result.Manifest)
+	}
+
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatalf("bundle is not a valid ZIP: %v", err)
+	}
+	defer zr.Close()
+
+	contents := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		contents[f.Name] = data
+	}
+
+	pdfName := "filled/" + c.Metadata.CaseID + "_filled.pdf"
+	for _, name := range []string{"case.json", pdfName, "manifest.json"} {
+		if _, ok := contents[name]; !ok {
+			t.Errorf("bundle missing %s", name)
+		}
+	}
+
+	var manifest CaseManifest
+	if err := json.Unmarshal(contents["manifest.json"], &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	for _, f := range manifest.Files {
+		sum := sha256.Sum256(contents[f.Name])
+		if f.SHA256 != hex.EncodeToString(sum[:]) {
+			t.Errorf("manifest hash for %s does not match bundle contents", f.Name)
+		}
+	}
+}
+
+func TestExportCase_NotFound(t *testing.T) {
+	_, err := ExportCase(ExportCaseOptions{CaseID: "missing", DataDir: t.TempDir()})
+	if err == nil {
+		t.Error("expected error for unknown case")
+	}
+}
@@ -0,0 +1,153 @@
+package pdfform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// ================================================================
+// Case History
+// ================================================================
+// Every mutating operation on a case is appended to the case's history, the
+// audit trail stored in the case file itself: who did what, when, and a
+// SHA-256 of the input it acted on. SaveCase records edits and validation
+// status changes; fills, bundle downloads and edits of the case's template
+// are recorded by RecordCaseOperation without changing the case's revision.
+
+// CaseOperation names a recorded operation
+type CaseOperation string
+
+const (
+	CaseOpCreate       CaseOperation = "create"
+	CaseOpSave         CaseOperation = "save"          // Fields edited
+	CaseOpStatusChange CaseOperation = "status_change" // Validation result changed
+	CaseOpMigrate      CaseOperation = "migrate"       // Fields re-mapped to a new form version
+	CaseOpFill         CaseOperation = "fill"          // Filled PDF produced
+	CaseOpDownload     CaseOperation = "download"      // Case bundle exported
+	CaseOpTemplateEdit CaseOperation = "template_edit" // The template the case fills from was saved
+)
+
+// CaseHistoryEntry is one operation in a case's history
+type CaseHistoryEntry struct {
+	Time      time.Time     `json:"time"`
+	Operation CaseOperation `json:"operation"`
+	Actor     string        `json:"actor"`
+	InputHash string        `json:"input_hash,omitempty"` // SHA-256 of the operation's input
+	Detail    string        `json:"detail,omitempty"`
+}
+
+// DefaultActor identifies the local user (user@host) for history entries
+// recorded without an actor
+func DefaultActor() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	if host, err := os.Hostname(); err == nil {
+		return name + "@" + host
+	}
+	return name
+}
+
+// HashInput returns the hex SHA-256 of an operation's input
+func HashInput(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashFields hashes case field values (map keys marshal sorted)
+func hashFields(fields map[string]string) string {
+	data, _ := json.Marshal(fields)
+	return HashInput(data)
+}
+
+// newHistoryEntry fills in the time and default actor
+func newHistoryEntry(op CaseOperation, actor, inputHash, detail string) CaseHistoryEntry {
+	if actor == "" {
+		actor = DefaultActor()
+	}
+	return CaseHistoryEntry{Time: time.Now(), Operation: op, Actor: actor, InputHash: inputHash, Detail: detail}
+}
+
+// RecordCaseOperation appends an entry to the history of the case at casePath.
+// Time and Actor default to now and DefaultActor. The revision is unchanged,
+// so recording never conflicts with an editor's save.
+func RecordCaseOperation(casePath string, entry CaseHistoryEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Actor == "" {
+		entry.Actor = DefaultActor()
+	}
+
+	unlock, err := lockCase(casePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	c, err := LoadCase(casePath)
+	if err != nil {
+		return err
+	}
+	c.History = append(c.History, entry)
+	return writeCase(c, casePath)
+}
+
+// RecordTemplateEdit records a template_edit entry in every case under
+// dataDir that fills from templatePath, returning the number of cases
+func RecordTemplateEdit(dataDir, templatePath, actor string) (int, error) {
+	inputHash, err := fileSHA256(templatePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to hash template: %w", err)
+	}
+	casePaths, err := ListCases(dataDir, "")
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, casePath := range casePaths {
+		c, err := LoadCase(casePath)
+		if err != nil || c.FormReference.TemplatePath == "" || !samePath(c.FormReference.TemplatePath, templatePath) {
+			continue
+		}
+		entry := newHistoryEntry(CaseOpTemplateEdit, actor, inputHash, templatePath)
+		if err := RecordCaseOperation(casePath, entry); err != nil {
+			return recorded, err
+		}
+		recorded++
+	}
+	return recorded, nil
+}
+
+// samePath reports whether two paths name the same file
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// writeCase writes the case as indented JSON, atomically
+func writeCase(c *Case, casePath string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal case JSON: %w", err)
+	}
+	if err := writeFileAtomic(casePath, data); err != nil {
+		return fmt.Errorf("failed to write case file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package pdfform
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func operations(c *Case) []CaseOperation {
+	ops := make([]CaseOperation, len(c.History))
+	for i, entry := range c.History {
+		ops[i] = entry.Operation
+	}
+	return ops
+}
+
+func TestSaveCase_History(t *testing.T) {
+	c, casePath, err := CreateCase("F3520", "Test Vehicle Sale", "test_user", t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateCase failed: %v", err)
+	}
+
+	c.Fields["Text1"] = "John"
+	c.Validation = &ValidationStatus{Valid: true}
+	if err := SaveCaseWithOptions(c, casePath, SaveCaseOptions{Actor: "alice"}); err != nil {
+		t.Fatalf("SaveCase failed: %v", err)
+	}
+
+	loaded, err := LoadCase(casePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CaseOperation{CaseOpCreate, CaseOpSave, CaseOpStatusChange}
+	if got := operations(loaded); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("Expected history %v, got %v", want, got)
+	}
+
+	save := loaded.History[1]
+	if save.Actor != "alice" {
+		t.Errorf("Expected actor 'alice', got '%s'", save.Actor)
+	}
+	if save.InputHash != hashFields(map[string]string{"Text1": "John"}) {
+		t.Errorf("Save entry hash does not match the saved fields")
+	}
+	if loaded.History[0].Actor != DefaultActor() {
+		t.Errorf("Expected default actor on create, got '%s'", loaded.History[0].Actor)
+	}
+	if loaded.History[2].Detail != "unvalidated -> valid" {
+		t.Errorf("Unexpected status change detail '%s'", loaded.History[2].Detail)
+	}
+}
+
+func TestRecordCaseOperation(t *testing.T) {
+	_, casePath, err := CreateCase("F3520", "Test Vehicle Sale", "test_user", t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateCase failed: %v", err)
+	}
+
+	// An editor loads the case, then a fill is recorded before it saves
+	editor, _ := LoadCase(casePath)
+	if err := RecordCaseOperation(casePath, CaseHistoryEntry{Operation: CaseOpFill, InputHash: HashInput([]byte("{}"))}); err != nil {
+		t.Fatalf("RecordCaseOperation failed: %v", err)
+	}
+
+	editor.Fields["Text1"] = "John"
+	if err := SaveCase(editor, casePath); err != nil {
+		t.Fatalf("Recording should not conflict with a save: %v", err)
+	}
+
+	loaded, _ := LoadCase(casePath)
+	got := operations(loaded)
+	if len(got) != 3 || got[1] != CaseOpFill || got[2] != CaseOpSave {
+		t.Errorf("Expected the save to keep the recorded fill, got %v", got)
+	}
+	if loaded.History[1].Time.IsZero() || loaded.History[1].Actor == "" {
+		t.Error("Expected time and actor to be filled in")
+	}
+}
+
+func TestRecordTemplateEdit(t *testing.T) {
+	tempDir := t.TempDir()
+	templatePath := filepath.Join(tempDir, "templates", "f3520.json")
+	if err := os.MkdirAll(filepath.Dir(templatePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(templatePath, []byte(`{"pdf_url":"f3520.pdf"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	uses, usesPath, _ := CreateCase("F3520", "Uses template", "alice", tempDir)
+	uses.FormReference.TemplatePath = templatePath
+	if err := SaveCase(uses, usesPath); err != nil {
+		t.Fatal(err)
+	}
+	_, otherPath, _ := CreateCase("F3520", "No template", "bob", tempDir)
+
+	n, err := RecordTemplateEdit(tempDir, templatePath, "gui@127.0.0.1")
+	if err != nil {
+		t.Fatalf("RecordTemplateEdit failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 case recorded, got %d", n)
+	}
+
+	loaded, _ := LoadCase(usesPath)
+	last := loaded.History[len(loaded.History)-1]
+	if last.Operation != CaseOpTemplateEdit || last.Actor != "gui@127.0.0.1" || last.InputHash == "" {
+		t.Errorf("Unexpected template edit entry %+v", last)
+	}
+	other, _ := LoadCase(otherPath)
+	if got := operations(other); len(got) != 1 {
+		t.Errorf("Case without the template should be unchanged, got %v", got)
+	}
+}
+
+func TestWriteCaseBundle_RecordsDownload(t *testing.T) {
+	_, casePath, err := CreateCase("F3520", "Test Vehicle Sale", "test_user", t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateCase failed: %v", err)
+	}
+
+	manifest, err := WriteCaseBundle(io.Discard, ExportCaseOptions{CasePath: casePath, Actor: "clerk"})
+	if err != nil {
+		t.Fatalf("WriteCaseBundle failed: %v", err)
+	}
+
+	loaded, _ := LoadCase(casePath)
+	last := loaded.History[len(loaded.History)-1]
+	if last.Operation != CaseOpDownload || last.Actor != "clerk" {
+		t.Errorf("Expected a download by clerk, got %+v", last)
+	}
+	if last.InputHash != manifest.Files[0].SHA256 {
+		t.Error("Download entry hash does not match the exported case.json")
+	}
+}
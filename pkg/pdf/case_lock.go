@@ -0,0 +1,106 @@
+package pdfform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ErrCaseConflict is returned (wrapped in a *CaseConflictError) by SaveCase
+// when the case file was saved by someone else since it was loaded
+var ErrCaseConflict = errors.New("case was modified by another session")
+
+// ErrCaseLocked is returned by SaveCase when another save holds the case lock
+// for longer than CaseLockTimeout
+var ErrCaseLocked = errors.New("case is locked by another session")
+
+var (
+	// CaseLockTimeout is how long SaveCase waits for another save to finish
+	CaseLockTimeout = 5 * time.Second
+
+	// CaseLockStale is the age after which a lock file is considered left
+	// behind by a crashed process and removed
+	CaseLockStale = 30 * time.Second
+)
+
+// CaseConflictError describes a rejected save: the caller's copy was loaded at
+// Expected, but the file is now at Current.Metadata.Revision
+type CaseConflictError struct {
+	Path     string
+	Expected int   // Revision the caller loaded
+	Current  *Case // Case as it is on disk now
+}
+
+func (e *CaseConflictError) Error() string {
+	return fmt.Sprintf("%s: %s (loaded revision %d, now at revision %d)",
+		e.Path, ErrCaseConflict, e.Expected, e.Current.Metadata.Revision)
+}
+
+func (e *CaseConflictError) Unwrap() error { return ErrCaseConflict }
+
+// ConflictingFields returns the names of the given fields whose value differs
+// from the saved case, for a merge prompt to list
+func (e *CaseConflictError) ConflictingFields(fields map[string]string) []string {
+	var names []string
+	for name, value := range fields {
+		if e.Current.Fields[name] != value {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lockCase takes the advisory lock of a case file: a <case>.lock file created
+// exclusively, so it works the same on every platform and shared volumes.
+// The returned function releases it.
+func lockCase(casePath string) (func(), error) {
+	lockPath := casePath + ".lock"
+	deadline := time.Now().Add(CaseLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock case file: %w", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > CaseLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s", ErrCaseLocked, casePath)
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never see a half-written case
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,138 @@
+package pdfform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSaveCase_Conflict(t *testing.T) {
+	tempDir := t.TempDir()
+
+	c, casePath, err := CreateCase("F3520", "Test Vehicle Sale", "test_user", tempDir)
+	if err != nil {
+		t.Fatalf("CreateCase failed: %v", err)
+	}
+	if c.Metadata.Revision != 1 {
+		t.Errorf("Expected revision 1 after create, got %d", c.Metadata.Revision)
+	}
+
+	// Two sessions load the same revision
+	first, _ := LoadCase(casePath)
+	second, _ := LoadCase(casePath)
+
+	first.Fields["Text1"] = "John"
+	if err := SaveCase(first, casePath); err != nil {
+		t.Fatalf("SaveCase failed: %v", err)
+	}
+	if first.Metadata.Revision != 2 {
+		t.Errorf("Expected revision 2 after save, got %d", first.Metadata.Revision)
+	}
+
+	// The second save is stale and must not overwrite the first
+	second.Fields["Text1"] = "Jane"
+	second.Fields["Text2"] = "Smith"
+	err = SaveCase(second, casePath)
+	if !errors.Is(err, ErrCaseConflict) {
+		t.Fatalf("Expected ErrCaseConflict, got %v", err)
+	}
+	var conflict *CaseConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Expected *CaseConflictError, got %T", err)
+	}
+	if conflict.Expected != 1 || conflict.Current.Metadata.Revision != 2 {
+		t.Errorf("Expected conflict 1 -> 2, got %d -> %d", conflict.Expected, conflict.Current.Metadata.Revision)
+	}
+	fields := conflict.ConflictingFields(map[string]string{"Text1": "Jane", "Text2": ""})
+	if len(fields) != 1 || fields[0] != "Text1" {
+		t.Errorf("Expected conflicting fields [Text1], got %v", fields)
+	}
+	if second.Metadata.Revision != 1 {
+		t.Errorf("Rejected save changed the revision to %d", second.Metadata.Revision)
+	}
+
+	loaded, _ := LoadCase(casePath)
+	if loaded.Fields["Text1"] != "John" {
+		t.Errorf("Expected Text1 'John' to survive the conflict, got '%s'", loaded.Fields["Text1"])
+	}
+
+	// Saving on top of the current revision resolves it
+	second.Metadata.Revision = conflict.Current.Metadata.Revision
+	if err := SaveCase(second, casePath); err != nil {
+		t.Fatalf("SaveCase after merge failed: %v", err)
+	}
+}
+
+func TestSaveCase_Concurrent(t *testing.T) {
+	_, casePath, err := CreateCase("F3520", "Test Vehicle Sale", "test_user", t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateCase failed: %v", err)
+	}
+
+	const sessions = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, sessions)
+	for i := 0; i < sessions; i++ {
+		c, err := LoadCase(casePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Fields["Text1"] = c.Metadata.CaseID
+			errs <- SaveCase(c, casePath)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	saved := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			saved++
+		case !errors.Is(err, ErrCaseConflict):
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if saved != 1 {
+		t.Errorf("Expected exactly one save to win, got %d", saved)
+	}
+	if _, err := os.Stat(casePath + ".lock"); !os.IsNotExist(err) {
+		t.Error("Lock file was left behind")
+	}
+}
+
+func TestLockCase(t *testing.T) {
+	casePath := filepath.Join(t.TempDir(), "case.json")
+
+	unlock, err := lockCase(casePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldTimeout := CaseLockTimeout
+	CaseLockTimeout = 50 * time.Millisecond
+	defer func() { CaseLockTimeout = oldTimeout }()
+
+	if _, err := lockCase(casePath); !errors.Is(err, ErrCaseLocked) {
+		t.Errorf("Expected ErrCaseLocked while held, got %v", err)
+	}
+	unlock()
+
+	// A lock left behind by a crashed process is taken over
+	stale := time.Now().Add(-2 * CaseLockStale)
+	if err := os.WriteFile(casePath+".lock", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(casePath+".lock", stale, stale)
+	unlock, err = lockCase(casePath)
+	if err != nil {
+		t.Fatalf("Expected stale lock to be removed, got %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,246 @@
+package pdfform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ================================================================
+// Form Version Pinning
+// ================================================================
+// A case records the catalog entry (and, when downloaded, the PDF) it was
+// created against. Filling checks the pin against the current catalog and PDF
+// and warns when either has changed; migrate-case re-maps the case's fields to
+// the new form with a mapping profile and re-pins it.
+
+// ErrFormNotInCatalog is returned by CaptureFormVersion for an unknown form code
+var ErrFormNotInCatalog = errors.New("form not found in catalog")
+
+// FormVersion pins the exact form a case was created against
+type FormVersion struct {
+	EntryChecksum string    `json:"entry_checksum"`       // TransferForm.Checksum of the catalog entry
+	PDFURL        string    `json:"pdf_url,omitempty"`    // Catalog PDF URL at capture time
+	PDFSHA256     string    `json:"pdf_sha256,omitempty"` // Hash of the PDF (empty if not downloaded yet)
+	CapturedAt    time.Time `json:"captured_at"`
+}
+
+// Checksum returns a short SHA-256 over the entry's identifying fields; it
+// changes whenever the catalog points the form at a different document
+func (f *TransferForm) Checksum() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		f.State, f.FormCode, f.FormName, f.Format, f.DirectPDFURL,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// CaptureFormVersion pins formCode's current catalog entry, plus the PDF at
+// pdfPath when it exists (pdfPath may be empty)
+func CaptureFormVersion(catalogPath, formCode, pdfPath string) (*FormVersion, error) {
+	catalog, err := LoadFormsCatalog(catalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load forms catalog: %w", err)
+	}
+	form := catalog.GetFormByCode(formCode)
+	if form == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFormNotInCatalog, formCode)
+	}
+
+	version := &FormVersion{
+		EntryChecksum: form.Checksum(),
+		PDFURL:        form.DirectPDFURL,
+		CapturedAt:    time.Now(),
+	}
+	if pdfPath != "" {
+		if sum, err := fileSHA256(pdfPath); err == nil {
+			version.PDFSHA256 = sum
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return version, nil
+}
+
+// CheckFormVersion compares a case's pinned form version with the current
+// catalog entry and the PDF at pdfPath (optional). It returns one warning per
+// difference, or nil if the case isn't pinned or nothing changed.
+func CheckFormVersion(c *Case, catalogPath, pdfPath string) ([]string, error) {
+	pinned := c.FormReference.Version
+	if pinned == nil {
+		return nil, nil
+	}
+	code := c.FormReference.FormCode
+
+	var warnings []string
+	if catalogPath != "" {
+		catalog, err := LoadFormsCatalog(catalogPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load forms catalog: %w", err)
+		}
+		switch form := catalog.GetFormByCode(code); {
+		case form == nil:
+			warnings = append(warnings, fmt.Sprintf("form %s is no longer in the catalog", code))
+		case form.Checksum() != pinned.EntryChecksum:
+			warnings = append(warnings, fmt.Sprintf("catalog entry for %s changed since the case was created (pinned %s, now %s); consider migrate-case",
+				code, pinned.EntryChecksum, form.Checksum()))
+		}
+	}
+
+	if pdfPath != "" && pinned.PDFSHA256 != "" {
+		sum, err := fileSHA256(pdfPath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil && sum != pinned.PDFSHA256 {
+			warnings = append(warnings, fmt.Sprintf("form PDF %s differs from the version the case was created against", filepath.Base(pdfPath)))
+		}
+	}
+	return warnings, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ================================================================
+// Case Migration
+// ================================================================
+
+// MappingProfile re-maps a case's fields from one form version to the next.
+// Profiles are JSON files, conventionally kept in Config.MappingsPath():
+//
+//	{
+//	  "form_code": "F3520",
+//	  "description": "2024 revision renamed the buyer fields",
+//	  "rename": {"Buyer Name": "Purchaser Name"},
+//	  "drop": ["Fax"],
+//	  "defaults": {"Declaration": "Yes"}
+//	}
+type MappingProfile struct {
+	FormCode    string            `json:"form_code"`
+	Description string            `json:"description,omitempty"`
+	Rename      map[string]string `json:"rename,omitempty"`   // Old field name -> new field name
+	Drop        []string          `json:"drop,omitempty"`     // Fields removed from the new form
+	Defaults    map[string]string `json:"defaults,omitempty"` // New fields and their initial values
+}
+
+// LoadMappingProfile loads a mapping profile from a JSON file
+func LoadMappingProfile(path string) (*MappingProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping profile: %w", err)
+	}
+	var p MappingProfile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping profile %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// MigrateCaseOptions contains options for migrating a case to a new form version
+type MigrateCaseOptions struct {
+	CasePath    string
+	Profile     *MappingProfile
+	CatalogPath string // Catalog to re-pin against (empty = keep the old pin)
+	PDFPath     string // New form PDF to pin (optional)
+	DryRun      bool   // Report changes without saving
+}
+
+// MigrateCaseResult reports what MigrateCase changed
+type MigrateCaseResult struct {
+	CasePath   string
+	Renamed    map[string]string // Old -> new field name
+	Dropped    []string
+	Added      []string     // Fields added from profile defaults
+	OldVersion *FormVersion `json:",omitempty"`
+	NewVersion *FormVersion `json:",omitempty"`
+	Saved      bool
+}
+
+// MigrateCase applies a mapping profile to a case's fields and re-pins its form version
+func MigrateCase(opts MigrateCaseOptions) (*MigrateCaseResult, error) {
+	if opts.Profile == nil {
+		return nil, fmt.Errorf("mapping profile is required")
+	}
+	c, err := LoadCase(opts.CasePath)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Profile.FormCode != "" && !strings.EqualFold(opts.Profile.FormCode, c.FormReference.FormCode) {
+		return nil, fmt.Errorf("mapping profile is for form %s, case uses %s", opts.Profile.FormCode, c.FormReference.FormCode)
+	}
+
+	result := &MigrateCaseResult{
+		CasePath:   opts.CasePath,
+		Renamed:    make(map[string]string),
+		OldVersion: c.FormReference.Version,
+	}
+
+	fields := make(map[string]string, len(c.Fields))
+	for name, value := range c.Fields {
+		fields[name] = value
+	}
+	for oldName, newName := range opts.Profile.Rename {
+		value, ok := fields[oldName]
+		if !ok {
+			continue
+		}
+		delete(fields, oldName)
+		fields[newName] = value
+		result.Renamed[oldName] = newName
+	}
+	for _, name := range opts.Profile.Drop {
+		if _, ok := fields[name]; ok {
+			delete(fields, name)
+			result.Dropped = append(result.Dropped, name)
+		}
+	}
+	for name, value := range opts.Profile.Defaults {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+			result.Added = append(result.Added, name)
+		}
+	}
+	sort.Strings(result.Added)
+
+	if opts.CatalogPath != "" {
+		version, err := CaptureFormVersion(opts.CatalogPath, c.FormReference.FormCode, opts.PDFPath)
+		if err != nil {
+			return nil, err
+		}
+		result.NewVersion = version
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	c.Fields = fields
+	if result.NewVersion != nil {
+		c.FormReference.Version = result.NewVersion
+	}
+	detail := fmt.Sprintf("%d renamed, %d dropped, %d added", len(result.Renamed), len(result.Dropped), len(result.Added))
+	if err := SaveCaseWithOptions(c, opts.CasePath, SaveCaseOptions{Operation: CaseOpMigrate, Detail: detail}); err != nil {
+		return nil, err
+	}
+	result.Saved = true
+	return result, nil
+}
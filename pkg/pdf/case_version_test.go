@@ -0,0 +1,157 @@
+package pdfform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestCatalog writes a one-form catalog CSV pointing F3520 at pdfURL
+func writeTestCatalog(t *testing.T, path, pdfURL string) {
+	t.Helper()
+	csv := "State,Form Name,Form Code,Description,Format,Direct PDF URL,Info URL,Online Available,Notes\n" +
+		"QLD,Vehicle Transfer,F3520,Transfer,PDF," + pdfURL + ",https://example.com,false,\n"
+	if err := os.WriteFile(path, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateCase_PinsFormVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	catalogPath := filepath.Join(tempDir, "catalog.csv")
+	pdfPath := filepath.Join(tempDir, "f3520.pdf")
+	writeTestCatalog(t, catalogPath, "https://example.com/f3520-v1.pdf")
+	if err := os.WriteFile(pdfPath, []byte("%PDF-1.7 v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _, err := CreateCaseWithOptions(CreateCaseOptions{
+		FormCode:    "F3520",
+		CaseName:    "Pinned Sale",
+		EntityName:  "test_user",
+		DataDir:     tempDir,
+		CatalogPath: catalogPath,
+		PDFPath:     pdfPath,
+	})
+	if err != nil {
+		t.Fatalf("CreateCaseWithOptions failed: %v", err)
+	}
+	v := c.FormReference.Version
+	if v == nil || v.EntryChecksum == "" || v.PDFSHA256 == "" {
+		t.Fatalf("expected pinned version with entry and PDF checksums, got %+v", v)
+	}
+
+	warnings, err := CheckFormVersion(c, catalogPath, pdfPath)
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("unchanged form: warnings %v, err %v", warnings, err)
+	}
+
+	// New catalog entry and new PDF both warn
+	writeTestCatalog(t, catalogPath, "https://example.com/f3520-v2.pdf")
+	if err := os.WriteFile(pdfPath, []byte("%PDF-1.7 v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	warnings, err = CheckFormVersion(c, catalogPath, pdfPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "migrate-case") {
+		t.Errorf("expected catalog and PDF warnings, got %v", warnings)
+	}
+}
+
+func TestCreateCase_UnknownFormStaysUnpinned(t *testing.T) {
+	tempDir := t.TempDir()
+	catalogPath := filepath.Join(tempDir, "catalog.csv")
+	writeTestCatalog(t, catalogPath, "https://example.com/f3520.pdf")
+
+	c, _, err := CreateCaseWithOptions(CreateCaseOptions{
+		FormCode:    "CUSTOM1",
+		CaseName:    "Uploaded Form",
+		EntityName:  "test_user",
+		DataDir:     tempDir,
+		CatalogPath: catalogPath,
+	})
+	if err != nil {
+		t.Fatalf("CreateCaseWithOptions failed: %v", err)
+	}
+	if c.FormReference.Version != nil {
+		t.Errorf("expected no version pin for a form outside the catalog, got %+v", c.FormReference.Version)
+	}
+}
+
+func TestMigrateCase(t *testing.T) {
+	tempDir := t.TempDir()
+	catalogPath := filepath.Join(tempDir, "catalog.csv")
+	writeTestCatalog(t, catalogPath, "https://example.com/f3520-v1.pdf")
+
+	c, casePath, err := CreateCaseWithOptions(CreateCaseOptions{
+		FormCode:    "F3520",
+		CaseName:    "Migrating Sale",
+		EntityName:  "test_user",
+		DataDir:     tempDir,
+		CatalogPath: catalogPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Fields = map[string]string{"Buyer Name": "Jane Doe", "Fax": "03 9999 0000", "Rego": "ABC123"}
+	if err := SaveCase(c, casePath); err != nil {
+		t.Fatal(err)
+	}
+	oldChecksum := c.FormReference.Version.EntryChecksum
+
+	writeTestCatalog(t, catalogPath, "https://example.com/f3520-v2.pdf")
+	profile := &MappingProfile{
+		FormCode: "F3520",
+		Rename:   map[string]string{"Buyer Name": "Purchaser Name"},
+		Drop:     []string{"Fax"},
+		Defaults: map[string]string{"Declaration": "Yes", "Rego": "ignored"},
+	}
+
+	// Dry run leaves the case untouched
+	result, err := MigrateCase(MigrateCaseOptions{CasePath: casePath, Profile: profile, CatalogPath: catalogPath, DryRun: true})
+	if err != nil {
+		t.Fatalf("MigrateCase dry run failed: %v", err)
+	}
+	if result.Saved {
+		t.Error("dry run should not save")
+	}
+	if reloaded, _ := LoadCase(casePath); reloaded.Fields["Buyer Name"] != "Jane Doe" {
+		t.Error("dry run modified the case")
+	}
+
+	result, err = MigrateCase(MigrateCaseOptions{CasePath: casePath, Profile: profile, CatalogPath: catalogPath})
+	if err != nil {
+		t.Fatalf("MigrateCase failed: %v", err)
+	}
+	if result.Renamed["Buyer Name"] != "Purchaser Name" || len(result.Dropped) != 1 || len(result.Added) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	migrated, err := LoadCase(casePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Purchaser Name": "Jane Doe", "Rego": "ABC123", "Declaration": "Yes"}
+	if len(migrated.Fields) != len(want) {
+		t.Errorf("fields = %v, want %v", migrated.Fields, want)
+	}
+	for name, value := range want {
+		if migrated.Fields[name] != value {
+			t.Errorf("field %s = %q, want %q", name, migrated.Fields[name], value)
+		}
+	}
+	if migrated.FormReference.Version.EntryChecksum == oldChecksum {
+		t.Error("expected case to be re-pinned to the new catalog entry")
+	}
+	if warnings, _ := CheckFormVersion(migrated, catalogPath, ""); len(warnings) != 0 {
+		t.Errorf("migrated case still warns: %v", warnings)
+	}
+
+	// Profiles for another form are rejected
+	if _, err := MigrateCase(MigrateCaseOptions{CasePath: casePath, Profile: &MappingProfile{FormCode: "F4101"}}); err == nil {
+		t.Error("expected error for a profile targeting another form")
+	}
+}
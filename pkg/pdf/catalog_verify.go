@@ -0,0 +1,105 @@
+package pdfform
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ================================================================
+// Catalog Verification
+// ================================================================
+// Government sites move forms around; VerifyCatalog checks every URL in the
+// catalog (direct PDF, mirrors and info pages) so dead links are found before
+// a download fails. Requests to one host are spaced out (see
+// PoliteTransport), so a large catalog takes a while rather than hammering
+// a site with Concurrency parallel requests.
+
+// URL kinds reported by VerifyCatalog
+const (
+	URLKindPDF    = "pdf"
+	URLKindMirror = "mirror"
+	URLKindInfo   = "info"
+)
+
+// URLCheck is the result of checking one catalog URL
+type URLCheck struct {
+	FormCode string
+	Kind     string // URLKindPDF, URLKindMirror or URLKindInfo
+	URL      string
+	Status   int   // HTTP status (0 when the request failed)
+	Err      error // Request error
+}
+
+// Dead reports whether the URL could not be fetched
+func (u URLCheck) Dead() bool {
+	return u.Err != nil || u.Status >= 400
+}
+
+// VerifyCatalogOptions configures VerifyCatalog
+type VerifyCatalogOptions struct {
+	Client      *http.Client // HTTP client (default: DownloadClient's transport, 1m timeout including waits and retries)
+	Concurrency int          // Parallel requests (default: 8)
+}
+
+// VerifyCatalog checks every URL of the catalog's forms, returning the
+// results in catalog order. A HEAD request is tried first, then GET for
+// servers that reject HEAD.
+func VerifyCatalog(catalog *FormsCatalog, opts VerifyCatalogOptions) []URLCheck {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Transport: DownloadClient().Transport, Timeout: time.Minute}
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+
+	var checks []URLCheck
+	for _, form := range catalog.Forms {
+		for i, u := range form.SourceURLs() {
+			kind := URLKindMirror
+			if i == 0 && form.DirectPDFURL != "" {
+				kind = URLKindPDF
+			}
+			checks = append(checks, URLCheck{FormCode: form.FormCode, Kind: kind, URL: u})
+		}
+		if form.InfoURL != "" {
+			checks = append(checks, URLCheck{FormCode: form.FormCode, Kind: URLKindInfo, URL: form.InfoURL})
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for i := range checks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c *URLCheck) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.Status, c.Err = checkURL(opts.Client, c.URL)
+		}(&checks[i])
+	}
+	wg.Wait()
+	return checks
+}
+
+// checkURL returns the HTTP status of url, falling back from HEAD to GET
+func checkURL(client *http.Client, url string) (int, error) {
+	status, err := requestStatus(client, http.MethodHead, url)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusForbidden || status == http.StatusNotImplemented) {
+		status, err = requestStatus(client, http.MethodGet, url)
+	}
+	return status, err
+}
+
+func requestStatus(client *http.Client, method, url string) (int, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
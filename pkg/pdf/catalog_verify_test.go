@@ -0,0 +1,106 @@
+package pdfform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFormsCatalog_MirrorURLs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.csv")
+	csv := "State,Form Name,Form Code,Description,Format,Direct PDF URL,Info URL,Online Available,Notes,Mirror URLs\n" +
+		"QLD,Transfer,F3520,Vehicle transfer,PDF,https://a.example/f.pdf,,true,,https://b.example/f.pdf | https://c.example/f.pdf\n" +
+		"VIC,Transfer,VR1,Vehicle transfer,PDF,https://v.example/f.pdf,,true,\n"
+	if err := os.WriteFile(path, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog, err := LoadFormsCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadFormsCatalog failed: %v", err)
+	}
+	got := catalog.GetFormByCode("F3520").SourceURLs()
+	want := []string{"https://a.example/f.pdf", "https://b.example/f.pdf", "https://c.example/f.pdf"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected sources %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Source %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+	if n := len(catalog.GetFormByCode("VR1").SourceURLs()); n != 1 {
+		t.Errorf("Expected 1 source for a row without mirrors, got %d", n)
+	}
+}
+
+func TestDownloadFormPDF_Failover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dead.pdf" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("%PDF-1.4 mirror"))
+	}))
+	defer server.Close()
+
+	form := &TransferForm{
+		FormCode:     "F3520",
+		DirectPDFURL: server.URL + "/dead.pdf",
+		MirrorURLs:   []string{server.URL + "/mirror.pdf"},
+	}
+	path, err := (&FormsCatalog{}).DownloadFormPDF(form, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected failover to the mirror, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "%PDF-1.4 mirror" {
+		t.Errorf("Unexpected content %q", data)
+	}
+
+	form.MirrorURLs = []string{server.URL + "/dead.pdf"}
+	if _, err := (&FormsCatalog{}).DownloadFormPDF(form, t.TempDir()); err == nil {
+		t.Error("Expected an error when every source fails")
+	}
+}
+
+func TestVerifyCatalog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dead.pdf":
+			http.NotFound(w, r)
+		case "/no-head.pdf":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		}
+	}))
+	defer server.Close()
+
+	catalog := &FormsCatalog{Forms: []TransferForm{
+		{FormCode: "A", DirectPDFURL: server.URL + "/dead.pdf", MirrorURLs: []string{server.URL + "/ok.pdf"}},
+		{FormCode: "B", DirectPDFURL: server.URL + "/no-head.pdf", InfoURL: server.URL + "/info"},
+	}}
+
+	checks := VerifyCatalog(catalog, VerifyCatalogOptions{})
+	if len(checks) != 4 {
+		t.Fatalf("Expected 4 checks, got %d", len(checks))
+	}
+	want := []struct {
+		kind string
+		dead bool
+	}{
+		{URLKindPDF, true},
+		{URLKindMirror, false},
+		{URLKindPDF, false}, // GET fallback
+		{URLKindInfo, false},
+	}
+	for i, w := range want {
+		if checks[i].Kind != w.kind || checks[i].Dead() != w.dead {
+			t.Errorf("Check %d (%s): got kind %s dead %v, want %s dead %v",
+				i, checks[i].URL, checks[i].Kind, checks[i].Dead(), w.kind, w.dead)
+		}
+	}
+}
@@ -2,10 +2,15 @@ package cli
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+	"github.com/joeblew999/wellknown/pkg/pdf/commands"
+	"github.com/joeblew999/wellknown/pkg/pdf/rpc"
 	"github.com/joeblew999/wellknown/pkg/pdf/web"
 	"github.com/spf13/cobra"
 )
@@ -35,14 +40,26 @@ func Run(cfg *pdfform.Config) error {
     pdfform 4-fill data.json            # Fill the form
     pdfform 4-fill data.json --flatten  # Fill and lock
     pdfform 4-fill --test vba_basic     # Use test case
+    pdfform 4-fill a.json b.json c.json # Batch fill
 
 5️⃣  TEST - Run automated tests (optional)
     pdfform 5-test                      # List all tests
     pdfform 5-test vba_basic            # Run specific test
     pdfform 5-test --all                # Run all tests
 
-Each step guides you to the next! Just follow the numbers.`,
+Each step guides you to the next! Just follow the numbers.
+
+Progress bars are shown on terminals; use --no-progress to turn them off (e.g. in CI).
+
+Downloads are polite to government sites: one request per host per second,
+retries with backoff on 429/5xx (honouring Retry-After). Tune with
+--user-agent, --host-interval and --max-retries.`,
 	}
+	var noProgress bool
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Disable progress bars")
+	rootCmd.PersistentFlags().StringVar(&cfg.Politeness.UserAgent, "user-agent", cfg.Politeness.UserAgent, "User-Agent for downloads (default: "+pdfform.DefaultUserAgent+")")
+	rootCmd.PersistentFlags().DurationVar(&cfg.Politeness.HostInterval, "host-interval", cfg.Politeness.HostInterval, "Minimum gap between requests to one host (default 1s, negative disables)")
+	rootCmd.PersistentFlags().IntVar(&cfg.Politeness.MaxRetries, "max-retries", cfg.Politeness.MaxRetries, "Download retries on 429/5xx and network errors (default 3, negative disables)")
 
 	// ========================================
 	// 1️⃣ BROWSE FORMS
@@ -115,6 +132,7 @@ Examples:
 	// 2️⃣ DOWNLOAD FORM
 	// ========================================
 	var downloadOutDir string
+	var downloadNoCache bool
 	downloadCmd := &cobra.Command{
 		Use:   "2-download [form-code]",
 		Short: "2️⃣  Download a government form by its code",
@@ -123,7 +141,8 @@ Examples:
 Examples:
   pdfform 2-download F3520              # Download Queensland form
   pdfform 2-download VRPIN00613         # Download Victoria form
-  pdfform 2-download F3520 -o pdfs/     # Download to specific directory`,
+  pdfform 2-download F3520 -o pdfs/     # Download to specific directory
+  pdfform 2-download F3520 --no-cache   # Bypass the download cache`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			formCode := args[0]
@@ -135,11 +154,19 @@ Examples:
 			fmt.Println("2️⃣  DOWNLOAD FORM")
 			fmt.Println()
 
-			result, err := pdfform.Download(pdfform.DownloadOptions{
+			cacheDir := cfg.CachePath()
+			if downloadNoCache {
+				cacheDir = ""
+			}
+
+			progress := startProgress(!noProgress, "download.*")
+			result, err := commands.Download(commands.DownloadOptions{
 				CatalogPath: cfg.CatalogFilePath(),
 				FormCode:    formCode,
 				OutputDir:   downloadOutDir,
+				CacheDir:    cacheDir,
 			})
+			progress.Stop()
 			if err != nil {
 				if err.Error() == fmt.Sprintf("form with code '%s' not found", formCode) {
 					return fmt.Errorf("%w\n\n💡 Tip: Use 'pdfform 1-browse --state VIC' to see available forms", err)
@@ -148,7 +175,11 @@ Examples:
 			}
 
 			fmt.Printf("📥 Downloaded: %s (%s)\n", result.Form.FormName, result.Form.FormCode)
-			fmt.Printf("✅ Downloaded to: %s\n\n", result.PDFPath)
+			fmt.Printf("✅ Downloaded to: %s\n", result.PDFPath)
+			if result.CacheStatus != "" {
+				fmt.Printf("🗄️  Cache: %s\n", result.CacheStatus)
+			}
+			fmt.Println()
 
 			fmt.Println("➡️  Next Step: Inspect the form fields")
 			fmt.Printf("   pdfform 3-inspect %s\n", result.PDFPath)
@@ -157,11 +188,13 @@ Examples:
 		},
 	}
 	downloadCmd.Flags().StringVarP(&downloadOutDir, "output-dir", "o", "", "Output directory for downloaded form (default: data/downloads)")
+	downloadCmd.Flags().BoolVar(&downloadNoCache, "no-cache", false, "Always download, bypassing the cache")
 
 	// ========================================
 	// 3️⃣ INSPECT FIELDS
 	// ========================================
 	var inspectOut string
+	var inspectPassword pdfform.Passwords
 	inspectStepCmd := &cobra.Command{
 		Use:   "3-inspect [pdf-file]",
 		Short: "3️⃣  Extract form fields from a PDF to create JSON template",
@@ -169,7 +202,8 @@ Examples:
 
 Examples:
   pdfform 3-inspect form.pdf                    # Creates form_fields.json
-  pdfform 3-inspect form.pdf -o template.json   # Custom output name`,
+  pdfform 3-inspect form.pdf -o template.json   # Custom output name
+  pdfform 3-inspect form.pdf --password secret  # Encrypted PDF`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			pdfFile := args[0]
@@ -185,6 +219,7 @@ Examples:
 			result, err := pdfform.Inspect(pdfform.InspectOptions{
 				PDFPath:   pdfFile,
 				OutputDir: outputDir,
+				Password:  inspectPassword,
 			})
 			if err != nil {
 				return err
@@ -203,6 +238,8 @@ Examples:
 		},
 	}
 	inspectStepCmd.Flags().StringVarP(&inspectOut, "output", "o", "", "Output directory or file (default: data/templates/<pdfname>_template.json)")
+	inspectStepCmd.Flags().StringVar(&inspectPassword.User, "password", "", "User (open) password for an encrypted PDF")
+	inspectStepCmd.Flags().StringVar(&inspectPassword.Owner, "owner-password", "", "Owner password for an encrypted PDF")
 
 	// ========================================
 	// 4️⃣ FILL FORM
@@ -210,6 +247,11 @@ Examples:
 	var fillFlatten bool
 	var fillOutput string
 	var fillTest string
+	var fillSend bool
+	var fillSendTo []string
+	var fillPassword pdfform.Passwords
+	var fillProtect pdfform.Protection
+	var fillMeta pdfform.Metadata
 	fillStepCmd := &cobra.Command{
 		Use:   "4-fill [data.json]",
 		Short: "4️⃣  Fill a PDF form with your data",
@@ -223,8 +265,15 @@ Examples:
   pdfform 4-fill data.json                # Fill form
   pdfform 4-fill data.json --flatten      # Fill and lock fields
   pdfform 4-fill data.json -o output.pdf  # Custom output name
-  pdfform 4-fill --test vba_basic         # Fill using test case`,
-		Args: cobra.MaximumNArgs(1),
+  pdfform 4-fill --test vba_basic         # Fill using test case
+  pdfform 4-fill a.json b.json -o out/    # Batch fill into a directory
+  pdfform 4-fill data.json --flatten --send-to clerk@example.com  # Fill and email
+  pdfform 4-fill data.json --password secret --protect-password new  # Encrypted in and out
+  pdfform 4-fill data.json --flatten --title "Transfer" --lang en-AU --pdfa  # Archival copy
+
+Email uses SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM_EMAIL.
+--send without --send-to emails PDF_DELIVERY_TO.`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var dataFile string
 
@@ -240,6 +289,9 @@ Examples:
 				}
 				dataFile = args[0]
 			}
+			if fillTest != "" && len(args) > 0 {
+				return fmt.Errorf("use either --test or data files, not both")
+			}
 
 			if fillOutput == "" {
 				fillOutput = cfg.OutputsPath()
@@ -248,12 +300,51 @@ Examples:
 			fmt.Println("4️⃣  FILL FORM")
 			fmt.Println()
 
-			result, err := pdfform.Fill(pdfform.FillOptions{
-				DataPath:  dataFile,
+			var deliver *pdfform.DeliveryOptions
+			if fillSend || len(fillSendTo) > 0 {
+				deliver = &pdfform.DeliveryOptions{To: fillSendTo}
+			}
+
+			var protect *pdfform.Protection
+			if fillProtect.UserPassword != "" || fillProtect.OwnerPassword != "" {
+				protect = &fillProtect
+			}
+
+			var meta *pdfform.Metadata
+			if fillMeta.Title != "" || fillMeta.Author != "" || fillMeta.Subject != "" ||
+				fillMeta.CaseID != "" || fillMeta.Language != "" || fillMeta.PDFA {
+				meta = &fillMeta
+			}
+
+			opts := commands.FillOptions{
 				OutputDir: fillOutput,
 				Flatten:   fillFlatten,
-			})
+				Password:  fillPassword,
+				Metadata:  meta,
+				Protect:   protect,
+				Deliver:   deliver,
+			}
+
+			// Anything but a .pdf path is an output directory
+			if filepath.Ext(fillOutput) != ".pdf" {
+				if err := os.MkdirAll(fillOutput, 0755); err != nil {
+					return fmt.Errorf("failed to create output directory: %w", err)
+				}
+			} else if len(args) > 1 {
+				return fmt.Errorf("batch fill needs an output directory, not %s", fillOutput)
+			}
+			if len(args) > 1 {
+				return fillBatch(args, opts, !noProgress)
+			}
+
+			opts.DataPath = dataFile
+			progress := startProgress(!noProgress, "fill.*")
+			result, err := commands.Fill(opts)
+			progress.Stop()
 			if err != nil {
+				if result != nil {
+					fmt.Printf("✅ Filled PDF: %s\n", result.OutputPath)
+				}
 				return err
 			}
 
@@ -264,12 +355,23 @@ Examples:
 			} else {
 				fmt.Printf("✅ Filled PDF: %s\n", result.OutputPath)
 			}
+			if result.PDFA {
+				fmt.Println("🗄️  Converted to PDF/A-2b")
+			} else if result.Stamped {
+				fmt.Println("🏷️  Metadata stamped")
+			}
+			if result.Protected {
+				fmt.Println("🔐 Output is password-protected")
+			}
 
 			fmt.Println()
 			fmt.Printf("🎉 SUCCESS! Your form is ready: %s\n", result.OutputPath)
 			if result.InputPDF != "" {
 				fmt.Printf("   Original form: %s\n", result.InputPDF)
 			}
+			if result.Delivery != nil {
+				fmt.Printf("📧 Emailed to: %s\n", strings.Join(result.Delivery.To, ", "))
+			}
 
 			return nil
 		},
@@ -277,6 +379,18 @@ Examples:
 	fillStepCmd.Flags().BoolVar(&fillFlatten, "flatten", false, "Lock form fields (make read-only)")
 	fillStepCmd.Flags().StringVarP(&fillOutput, "output", "o", "", "Output directory or file (default: data/outputs/<datafile>_filled.pdf)")
 	fillStepCmd.Flags().StringVarP(&fillTest, "test", "t", "", "Load test case from data/cases/test_scenarios/<name>.json")
+	fillStepCmd.Flags().BoolVar(&fillSend, "send", false, "Email the result to PDF_DELIVERY_TO")
+	fillStepCmd.Flags().StringSliceVar(&fillSendTo, "send-to", nil, "Email the result to these addresses")
+	fillStepCmd.Flags().StringVar(&fillPassword.User, "password", "", "User (open) password for an encrypted input PDF")
+	fillStepCmd.Flags().StringVar(&fillPassword.Owner, "owner-password", "", "Owner password for an encrypted input PDF")
+	fillStepCmd.Flags().StringVar(&fillProtect.UserPassword, "protect-password", "", "Protect the output with this open password (AES-256)")
+	fillStepCmd.Flags().StringVar(&fillMeta.Title, "title", "", "Document title metadata")
+	fillStepCmd.Flags().StringVar(&fillMeta.Author, "author", "", "Document author metadata")
+	fillStepCmd.Flags().StringVar(&fillMeta.Subject, "subject", "", "Document subject metadata")
+	fillStepCmd.Flags().StringVar(&fillMeta.CaseID, "case-id", "", "Case ID recorded in the document metadata")
+	fillStepCmd.Flags().StringVar(&fillMeta.Language, "lang", "", "Document language, e.g. en-AU")
+	fillStepCmd.Flags().BoolVar(&fillMeta.PDFA, "pdfa", false, "Convert the output to PDF/A-2b (requires Ghostscript)")
+	fillStepCmd.Flags().StringVar(&fillProtect.OwnerPassword, "protect-owner-password", "", "Owner password for the protected output (default: --protect-password)")
 
 	// ========================================
 	// 5️⃣ TEST
@@ -348,22 +462,26 @@ Examples:
 
 			passed := 0
 			failed := 0
-			for _, name := range testNames {
+			progress := startProgress(!noProgress, "test.*")
+			commands.EmitBatch(commands.EventTestBatch, 0, len(testNames), "")
+			for i, name := range testNames {
 				testFile := filepath.Join(testCasesDir, name+".json")
-				fmt.Printf("🧪 Running: %s\n", name)
+				progress.Printf("🧪 Running: %s\n", name)
 
-				result, err := pdfform.Test(pdfform.TestOptions{
+				result, err := commands.Test(pdfform.TestOptions{
 					TestCasePath: testFile,
 					OutputDir:    outputDir,
 				})
+				commands.EmitBatch(commands.EventTestBatch, i+1, len(testNames), name)
 				if err != nil || !result.Passed {
-					fmt.Printf("   ❌ Failed: %v\n\n", result.Error)
+					progress.Printf("   ❌ Failed: %v\n\n", result.Error)
 					failed++
 					continue
 				}
-				fmt.Printf("   ✅ Passed: %s\n\n", result.OutputPath)
+				progress.Printf("   ✅ Passed: %s\n\n", result.OutputPath)
 				passed++
 			}
+			progress.Stop()
 
 			fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 			fmt.Printf("Results: %d passed, %d failed\n", passed, failed)
@@ -381,6 +499,7 @@ Examples:
 	// ========================================
 	var servePort int
 	var serveHTTP bool
+	var serveGRPCPort int
 	serveCmd := &cobra.Command{
 		Use:   "serve",
 		Short: "🌐 Start web server with 5-step workflow GUI",
@@ -398,10 +517,16 @@ HTTPS Support:
   By default, the server uses HTTPS for mobile device support.
   Certificates are auto-generated using mkcert and stored in .data/certs/
 
+gRPC:
+  --grpc-port also serves the pdfform.v1.PDFForm service (rpc/pdfformpb)
+  for headless automation: Browse, Download, Inspect, Fill and cases, with
+  Download and Fill streaming progress. It uses the same TLS setting.
+
 Examples:
   pdfform serve                       # Start HTTPS on port 8080
   pdfform serve --port 3000           # Start on custom port
-  pdfform serve --http                # Force HTTP (not recommended for mobile)`,
+  pdfform serve --http                # Force HTTP (not recommended for mobile)
+  pdfform serve --grpc-port 9090      # Also serve gRPC on port 9090`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Default to HTTPS unless --http is specified
 			useHTTPS := !serveHTTP
@@ -419,11 +544,22 @@ Examples:
 			// Show connection info
 			pdfform.PrintServerInfo(fmt.Sprintf("%d", servePort), useHTTPS)
 
+			if serveGRPCPort > 0 {
+				fmt.Printf("🔌 gRPC: port %d on all interfaces\n", serveGRPCPort)
+				go func() {
+					if err := rpc.ListenAndServe(serveGRPCPort, cfg, useHTTPS); err != nil {
+						fmt.Fprintf(os.Stderr, "❌ gRPC server: %v\n", err)
+						os.Exit(1)
+					}
+				}()
+			}
+
 			return web.StartServer(servePort, cfg, useHTTPS)
 		},
 	}
 	serveCmd.Flags().IntVarP(&servePort, "port", "p", 8080, "Port to run the server on")
 	serveCmd.Flags().BoolVar(&serveHTTP, "http", false, "Use HTTP instead of HTTPS (not recommended for mobile)")
+	serveCmd.Flags().IntVar(&serveGRPCPort, "grpc-port", 0, "Also serve the gRPC API on this port (0 disables)")
 
 	// ========================================
 	// CERTS - Certificate Management
@@ -470,6 +606,378 @@ Subcommands:
 	certsCmd.AddCommand(certsGenerateCmd)
 	certsCmd.AddCommand(certsRegenerateCmd)
 
+	// ========================================
+	// CACHE - Download Cache Management
+	// ========================================
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "🗄️  Inspect and purge the PDF download cache",
+		Long: `Manage the PDF download cache (.data/cache)
+
+Downloads are cached per form code and catalog version, and revalidated
+with the server (ETag / Last-Modified) once older than 24h.
+
+Subcommands:
+  pdfform cache list                    # Show cached forms
+  pdfform cache purge                   # Remove everything
+  pdfform cache purge --older-than 168h # Remove entries not validated in a week`,
+	}
+
+	cacheListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Show cached forms",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache := pdfform.NewPDFCache(cfg.CachePath())
+			entries, err := cache.List()
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Println("🗄️  Cache is empty")
+				return nil
+			}
+
+			fmt.Printf("🗄️  %d cached form(s) in %s\n\n", len(entries), cfg.CachePath())
+			for _, e := range entries {
+				state := "fresh"
+				if e.Expired(cache.TTL) {
+					state = "stale"
+				}
+				fmt.Printf("   %s (%s)\n", e.FormCode, state)
+				fmt.Printf("      Size: %d bytes | SHA256: %s\n", e.Size, e.SHA256[:12])
+				fmt.Printf("      Fetched: %s | Validated: %s\n", e.FetchedAt.Format("2006-01-02 15:04"), e.ValidatedAt.Format("2006-01-02 15:04"))
+				fmt.Printf("      URL: %s\n", e.URL)
+			}
+			return nil
+		},
+	}
+
+	var cachePurgeOlderThan time.Duration
+	cachePurgeCmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Remove cache entries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			removed, err := pdfform.NewPDFCache(cfg.CachePath()).Purge(cachePurgeOlderThan)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("🧹 Removed %d cache entries\n", removed)
+			return nil
+		},
+	}
+	cachePurgeCmd.Flags().DurationVar(&cachePurgeOlderThan, "older-than", 0, "Only remove entries not validated within this duration (default: all)")
+
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePurgeCmd)
+
+	// ========================================
+	// CATALOG - Forms Catalog Maintenance
+	// ========================================
+	catalogCmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "📚 Check the forms catalog",
+		Long: `Maintain the forms catalog (data/catalog/australian_transfer_forms.csv)
+
+A form can list alternate sources in an optional 10th "Mirror URLs" column
+(separated by "|"); downloads fail over to them when the direct URL fails.
+
+Subcommands:
+  pdfform catalog verify            # Check every URL and report dead links
+  pdfform catalog verify --state VIC`,
+	}
+
+	var verifyState string
+	var verifyTimeout time.Duration
+	catalogVerifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check every catalog URL and report dead links",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog, err := pdfform.LoadFormsCatalog(cfg.CatalogFilePath())
+			if err != nil {
+				return err
+			}
+			if verifyState != "" {
+				catalog = &pdfform.FormsCatalog{Forms: catalog.GetFormsByState(verifyState)}
+			}
+
+			fmt.Printf("📚 Checking URLs of %d form(s)...\n\n", len(catalog.Forms))
+			checks := pdfform.VerifyCatalog(catalog, pdfform.VerifyCatalogOptions{
+				Client: &http.Client{Transport: pdfform.DownloadClient().Transport, Timeout: verifyTimeout},
+			})
+
+			// A form is unreachable when none of its PDF sources work
+			reachable := make(map[string]bool)
+			dead := 0
+			for _, c := range checks {
+				if !c.Dead() {
+					if c.Kind != pdfform.URLKindInfo {
+						reachable[c.FormCode] = true
+					}
+					continue
+				}
+				dead++
+				reason := fmt.Sprintf("HTTP %d", c.Status)
+				if c.Err != nil {
+					reason = c.Err.Error()
+				}
+				fmt.Printf("   ❌ %s [%s] %s (%s)\n", c.FormCode, c.Kind, c.URL, reason)
+			}
+
+			var unreachable []string
+			for _, form := range catalog.Forms {
+				if len(form.SourceURLs()) > 0 && !reachable[form.FormCode] {
+					unreachable = append(unreachable, form.FormCode)
+				}
+			}
+
+			fmt.Println()
+			if dead == 0 {
+				fmt.Printf("✅ All %d URLs are reachable\n", len(checks))
+				return nil
+			}
+			fmt.Printf("⚠️  %d of %d URLs are dead\n", dead, len(checks))
+			if len(unreachable) > 0 {
+				return fmt.Errorf("no working PDF source for: %s", strings.Join(unreachable, ", "))
+			}
+			return fmt.Errorf("%d dead link(s); every form still has a working PDF source", dead)
+		},
+	}
+	catalogVerifyCmd.Flags().StringVarP(&verifyState, "state", "s", "", "Only check forms of this state")
+	catalogVerifyCmd.Flags().DurationVar(&verifyTimeout, "timeout", time.Minute, "Timeout per URL, including waits for the host and retries")
+
+	catalogCmd.AddCommand(catalogVerifyCmd)
+
+	// ========================================
+	// JANITOR - Retention Enforcement
+	// ========================================
+	var janitorDryRun bool
+	janitorCmd := &cobra.Command{
+		Use:   "janitor",
+		Short: "🧹 Remove old downloads, outputs and temp files",
+		Long: `Enforce the retention policies on downloads, outputs and temp
+
+Files older than a directory's max age are removed, then the oldest files
+until the directory is under its max size. Directories without a policy are
+left alone. The web server runs this hourly while it is up.
+
+Examples:
+  pdfform janitor --dry-run   # Show what would be removed
+  pdfform janitor             # Remove it`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := pdfform.RunJanitor(cfg, janitorDryRun)
+			if err != nil {
+				return err
+			}
+
+			verb := "Removed"
+			if report.DryRun {
+				verb = "Would remove"
+				fmt.Println("🔍 Dry run - nothing will be deleted")
+				fmt.Println()
+			}
+			if len(report.Dirs) == 0 {
+				fmt.Println("ℹ️  No retention policies configured")
+				return nil
+			}
+
+			for _, d := range report.Dirs {
+				fmt.Printf("📁 %s (%s)\n", d.Name, d.Path)
+				fmt.Printf("   Policy: max age %s, max size %s\n", formatMaxAge(d.Policy.MaxAge), formatMaxSize(d.Policy.MaxSize))
+				fmt.Printf("   Files: %d (%s)\n", d.Files, pdfform.FormatBytes(d.TotalBytes))
+				for _, f := range d.Removed {
+					fmt.Printf("   🗑️  %s (%s, %s)\n", f.Path, pdfform.FormatBytes(f.Size), f.Reason)
+				}
+				fmt.Println()
+			}
+			fmt.Printf("🧹 %s %d file(s), %s\n", verb, report.Removed, pdfform.FormatBytes(report.FreedBytes))
+			return nil
+		},
+	}
+	janitorCmd.Flags().BoolVar(&janitorDryRun, "dry-run", false, "Report what would be removed without deleting")
+
+	// ========================================
+	// VERIFY - Data Directory Integrity
+	// ========================================
+	var verifyRecord bool
+	var verifyAccept []string
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "🔏 Check downloads, templates and outputs against the checksum manifest",
+		Long: `Verify the integrity of the data directory
+
+Downloads, inspections and fills record the SHA-256 of what they write in
+.data/manifest.json. verify rehashes every recorded file and reports files
+that were modified or are missing, and files nobody recorded. It exits with
+an error when anything does not match.
+
+Examples:
+  pdfform verify                               # Check everything
+  pdfform verify --record                      # Start tracking untracked files
+  pdfform verify --accept outputs/a_filled.pdf # Accept an intended change`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(verifyAccept) > 0 {
+				paths := make([]string, len(verifyAccept))
+				for i, p := range verifyAccept {
+					paths[i] = filepath.Join(cfg.DataDir, filepath.FromSlash(p))
+				}
+				if err := pdfform.RecordFiles(cfg, paths...); err != nil {
+					return err
+				}
+				fmt.Printf("✅ Accepted %d file(s)\n", len(paths))
+			}
+			if verifyRecord {
+				recorded, err := pdfform.RecordUntracked(cfg)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("📝 Recorded %d untracked file(s)\n", len(recorded))
+			}
+
+			report, err := pdfform.VerifyManifest(cfg)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("🔏 Checked %d file(s) against %s\n", report.Checked, report.ManifestPath)
+			if report.OK() {
+				fmt.Println("✅ All files match the manifest")
+				return nil
+			}
+
+			fmt.Println()
+			for _, issue := range report.Issues {
+				switch issue.Status {
+				case pdfform.IntegrityModified:
+					fmt.Printf("   ❌ modified   %s\n      expected %s\n      actual   %s\n", issue.Path, issue.Expected, issue.Actual)
+				case pdfform.IntegrityMissing:
+					fmt.Printf("   ❌ missing    %s\n", issue.Path)
+				default:
+					fmt.Printf("   ⚠️  untracked  %s\n", issue.Path)
+				}
+			}
+			fmt.Println()
+			return fmt.Errorf("%d modified, %d missing, %d untracked file(s)",
+				report.Count(pdfform.IntegrityModified), report.Count(pdfform.IntegrityMissing), report.Count(pdfform.IntegrityUntracked))
+		},
+	}
+	verifyCmd.Flags().BoolVar(&verifyRecord, "record", false, "Record untracked files before verifying")
+	verifyCmd.Flags().StringSliceVar(&verifyAccept, "accept", nil, "Re-record these files (relative to the data directory) before verifying")
+
+	// ========================================
+	// MIGRATE-CASE - Form Version Migration
+	// ========================================
+	var migrateProfile, migratePDF string
+	var migrateDryRun bool
+	migrateCaseCmd := &cobra.Command{
+		Use:   "migrate-case [case.json]",
+		Short: "🔀 Migrate a case to the current version of its form",
+		Long: `Re-map a case's fields with a mapping profile and re-pin it to the
+form's current catalog entry
+
+Cases record the form version they were created against; filling warns
+when the catalog has moved on. A mapping profile (JSON) renames, drops and
+adds fields for the new version. --profile takes a path, or a name looked
+up in the mappings directory (<name>.json).
+
+Examples:
+  pdfform migrate-case case.json --profile f3520-2024 --dry-run
+  pdfform migrate-case case.json --profile f3520-2024 --pdf .data/downloads/f3520.pdf`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if migrateProfile == "" {
+				return fmt.Errorf("--profile is required")
+			}
+			profilePath := migrateProfile
+			if !strings.HasSuffix(profilePath, ".json") {
+				profilePath = filepath.Join(cfg.MappingsPath(), profilePath+".json")
+			}
+			profile, err := pdfform.LoadMappingProfile(profilePath)
+			if err != nil {
+				return err
+			}
+
+			result, err := commands.MigrateCase(pdfform.MigrateCaseOptions{
+				CasePath:    args[0],
+				Profile:     profile,
+				CatalogPath: cfg.CatalogFilePath(),
+				PDFPath:     migratePDF,
+				DryRun:      migrateDryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			if migrateDryRun {
+				fmt.Println("🔍 Dry run - case will not be saved")
+				fmt.Println()
+			}
+			for oldName, newName := range result.Renamed {
+				fmt.Printf("   ✏️  %s → %s\n", oldName, newName)
+			}
+			for _, name := range result.Dropped {
+				fmt.Printf("   🗑️  %s\n", name)
+			}
+			for _, name := range result.Added {
+				fmt.Printf("   ➕ %s\n", name)
+			}
+			if result.OldVersion != nil && result.NewVersion != nil {
+				fmt.Printf("📌 Form version %s → %s\n", result.OldVersion.EntryChecksum, result.NewVersion.EntryChecksum)
+			} else if result.NewVersion != nil {
+				fmt.Printf("📌 Pinned form version %s\n", result.NewVersion.EntryChecksum)
+			}
+			if result.Saved {
+				fmt.Printf("✅ Migrated %s\n", result.CasePath)
+			}
+			return nil
+		},
+	}
+	migrateCaseCmd.Flags().StringVar(&migrateProfile, "profile", "", "Mapping profile name or path (required)")
+	migrateCaseCmd.Flags().StringVar(&migratePDF, "pdf", "", "New form PDF to pin (optional)")
+	migrateCaseCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the changes without saving the case")
+
+	// ========================================
+	// EXPORT-CASE - Submission Bundle
+	// ========================================
+	var exportOut string
+	exportCaseCmd := &cobra.Command{
+		Use:   "export-case [case-id | case.json]",
+		Short: "📦 Export a case as a ZIP bundle for submission",
+		Long: `Bundle a case into a ZIP: the case data, its template, every filled
+PDF produced from it, and manifest.json with SHA-256 hashes and timestamps
+
+Examples:
+  pdfform export-case alice_F3520_20251110_123456.000000
+  pdfform export-case case.json -o bundles/`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := pdfform.ExportCaseOptions{
+				DataDir:    cfg.DataDir,
+				OutputDirs: []string{cfg.OutputsPath(), cfg.DownloadsPath()},
+			}
+			if strings.HasSuffix(args[0], ".json") {
+				opts.CasePath = args[0]
+			} else {
+				opts.CaseID = args[0]
+			}
+			if exportOut != "" && !strings.HasSuffix(exportOut, ".zip") {
+				name := strings.TrimSuffix(filepath.Base(args[0]), ".json") + ".zip"
+				exportOut = filepath.Join(exportOut, name)
+			}
+			opts.OutputPath = exportOut
+
+			result, err := commands.ExportCase(opts)
+			if err != nil {
+				return err
+			}
+
+			for _, f := range result.Manifest.Files {
+				fmt.Printf("   📄 %s (%s, sha256 %s)\n", f.Name, pdfform.FormatBytes(f.Size), f.SHA256[:12])
+			}
+			fmt.Printf("✅ Exported %s to %s\n", result.Manifest.CaseID, result.OutputPath)
+			return nil
+		},
+	}
+	exportCaseCmd.Flags().StringVarP(&exportOut, "output", "o", "", "Output ZIP file or directory (default: <case-id>.zip)")
+
 	// Add numbered workflow commands
 	rootCmd.AddCommand(browseCmd)
 	rootCmd.AddCommand(downloadCmd)
@@ -478,20 +986,31 @@ Subcommands:
 	rootCmd.AddCommand(testStepCmd)
 	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(certsCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(catalogCmd)
+	rootCmd.AddCommand(janitorCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(migrateCaseCmd)
+	rootCmd.AddCommand(exportCaseCmd)
 
 	// Show help by default if no command specified
 	validCommands := map[string]bool{
-		"1-browse":   true,
-		"2-download": true,
-		"3-inspect":  true,
-		"4-fill":     true,
-		"5-test":     true,
-		"serve":      true,
-		"certs":      true,
-		"help":       true,
-		"--help":     true,
-		"-h":         true,
-		"completion": true,
+		"1-browse":     true,
+		"2-download":   true,
+		"3-inspect":    true,
+		"4-fill":       true,
+		"5-test":       true,
+		"serve":        true,
+		"certs":        true,
+		"cache":        true,
+		"catalog":      true,
+		"janitor":      true,
+		"migrate-case": true,
+		"export-case":  true,
+		"help":         true,
+		"--help":       true,
+		"-h":           true,
+		"completion":   true,
 	}
 
 	if len(os.Args) == 1 || (len(os.Args) > 1 && !validCommands[os.Args[1]]) {
@@ -501,3 +1020,45 @@ Subcommands:
 
 	return rootCmd.Execute()
 }
+
+// fillBatch fills each data file into opts.OutputDir, reporting progress across the batch
+func fillBatch(dataFiles []string, opts commands.FillOptions, showProgress bool) error {
+	progress := startProgress(showProgress, "fill.*")
+	commands.EmitBatch(commands.EventFillBatch, 0, len(dataFiles), "")
+	failed := 0
+	for i, dataFile := range dataFiles {
+		opts.DataPath = dataFile
+		result, err := commands.Fill(opts)
+		commands.EmitBatch(commands.EventFillBatch, i+1, len(dataFiles), commands.BaseNameWithoutExt(dataFile))
+		if err != nil {
+			progress.Printf("❌ %s: %v\n", filepath.Base(dataFile), err)
+			failed++
+			continue
+		}
+		progress.Printf("✅ %s → %s\n", filepath.Base(dataFile), result.OutputPath)
+	}
+	progress.Stop()
+
+	fmt.Println()
+	fmt.Printf("Filled %d of %d forms\n", len(dataFiles)-failed, len(dataFiles))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d forms failed", failed, len(dataFiles))
+	}
+	return nil
+}
+
+// formatMaxAge formats a retention max age ("none" when unlimited)
+func formatMaxAge(d time.Duration) string {
+	if d <= 0 {
+		return "none"
+	}
+	return d.String()
+}
+
+// formatMaxSize formats a retention max size ("none" when unlimited)
+func formatMaxSize(n int64) string {
+	if n <= 0 {
+		return "none"
+	}
+	return pdfform.FormatBytes(n)
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/joeblew999/wellknown/pkg/pdf/commands"
+)
+
+// progressBarWidth is the number of cells in a rendered bar
+const progressBarWidth = 30
+
+// progressTracker renders a one-line progress bar from commands event bus events.
+//
+// A single operation follows the "progress" value of *.progress events. Once
+// a *.batch event arrives (see commands.EmitBatch), the bar shows its
+// completed/total plus the current item's progress on top.
+type progressTracker struct {
+	out       io.Writer
+	events    chan *commands.Event
+	done      chan struct{}
+	total     int // Items in the batch (0: a single operation)
+	completed int
+
+	mu   sync.Mutex
+	line string // Last rendered bar ("" = nothing on screen)
+}
+
+// startProgress subscribes to pattern (e.g. "download.*") and renders until Stop.
+// Returns a no-op tracker when disabled or stdout is not a terminal.
+func startProgress(enabled bool, pattern string) *progressTracker {
+	p := &progressTracker{out: os.Stdout}
+	if !enabled || !isTerminal(os.Stdout) {
+		return p
+	}
+
+	p.events = commands.Subscribe(pattern)
+	p.done = make(chan struct{})
+	go func() {
+		defer close(p.done)
+		for e := range p.events {
+			p.handle(e)
+		}
+	}()
+	return p
+}
+
+// Stop unsubscribes, waits for pending events and clears the bar
+func (p *progressTracker) Stop() {
+	if p.events == nil {
+		return
+	}
+	commands.Unsubscribe(p.events)
+	<-p.done
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.line != "" {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.line = ""
+	}
+}
+
+// Printf prints a line above the bar (use instead of fmt.Printf while tracking)
+func (p *progressTracker) Printf(format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.line != "" {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+	fmt.Fprintf(p.out, format, args...)
+	if p.line != "" {
+		fmt.Fprint(p.out, p.line)
+	}
+}
+
+func (p *progressTracker) handle(e *commands.Event) {
+	event := string(e.Type)
+	stage, _ := e.Data["stage"].(string)
+	current, _ := e.Data["progress"].(float64)
+
+	if strings.HasSuffix(event, ".batch") {
+		p.completed, _ = e.Data["completed"].(int)
+		p.total, _ = e.Data["total"].(int)
+		item, _ := e.Data["item"].(string)
+		p.renderBatch(0, item)
+		return
+	}
+
+	if p.total == 0 {
+		switch {
+		case strings.HasSuffix(event, ".started"):
+			p.render(0, "starting")
+		case strings.HasSuffix(event, ".completed"):
+			p.render(1, "done")
+		case strings.HasSuffix(event, ".progress"):
+			p.render(current, stage)
+		}
+		return
+	}
+
+	// The batch event after each item advances the count
+	switch {
+	case strings.HasSuffix(event, ".started"):
+		p.renderBatch(0, labelFor(e))
+	case strings.HasSuffix(event, ".progress"):
+		p.renderBatch(current, stage)
+	}
+}
+
+// renderBatch renders the batch position plus current (0.0 - 1.0) of the next item
+func (p *progressTracker) renderBatch(current float64, label string) {
+	if p.total <= 0 {
+		return
+	}
+	percent := (float64(p.completed) + current) / float64(p.total)
+	p.render(percent, fmt.Sprintf("%d/%d %s", p.completed, p.total, label))
+}
+
+// labelFor names the item an event refers to (test name or data file)
+func labelFor(e *commands.Event) string {
+	for _, key := range []string{"test_name", "data_path", "form_code"} {
+		if s, ok := e.Data[key].(string); ok && s != "" {
+			return commands.BaseNameWithoutExt(s)
+		}
+	}
+	return ""
+}
+
+func (p *progressTracker) render(percent float64, label string) {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 1 {
+		percent = 1
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.line = fmt.Sprintf("%s %3.0f%% %s", renderBar(percent, progressBarWidth), percent*100, label)
+	fmt.Fprint(p.out, "\r\033[K"+p.line)
+}
+
+// renderBar draws [#####.....] for percent (0.0 - 1.0, clamped)
+func renderBar(percent float64, width int) string {
+	filled := int(math.Max(0, math.Min(1, percent)) * float64(width))
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
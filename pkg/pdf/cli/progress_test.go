@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/pdf/commands"
+)
+
+func TestRenderBar(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    string
+	}{
+		{0, "[..........]"},
+		{0.45, "[####......]"},
+		{1, "[##########]"},
+		{1.7, "[##########]"}, // Clamped
+		{-0.2, "[..........]"},
+	}
+	for _, tt := range tests {
+		if got := renderBar(tt.percent, 10); got != tt.want {
+			t.Errorf("renderBar(%v) = %s, want %s", tt.percent, got, tt.want)
+		}
+	}
+}
+
+// line is the bar p last rendered
+func line(t *testing.T, p *progressTracker, out *bytes.Buffer) string {
+	t.Helper()
+	if !strings.HasSuffix(out.String(), p.line) {
+		t.Errorf("output %q does not end with the bar %q", out, p.line)
+	}
+	return p.line
+}
+
+func TestProgressTracker_Single(t *testing.T) {
+	var out bytes.Buffer
+	p := &progressTracker{out: &out}
+
+	p.handle(commands.NewEvent(commands.EventDownloadProgress, map[string]interface{}{"stage": "downloading", "progress": 0.5}))
+	if got := line(t, p, &out); !strings.HasSuffix(got, " 50% downloading") || strings.Count(got, "#") != progressBarWidth/2 {
+		t.Errorf("bar = %q, want half way", got)
+	}
+
+	// A progress value above 1 shows a full bar at 100%
+	p.handle(commands.NewEvent(commands.EventDownloadProgress, map[string]interface{}{"stage": "saving", "progress": 1.4}))
+	if got := line(t, p, &out); !strings.HasSuffix(got, "100% saving") || strings.Contains(got, ".") {
+		t.Errorf("bar = %q, want full", got)
+	}
+}
+
+func TestProgressTracker_Batch(t *testing.T) {
+	var out bytes.Buffer
+	p := &progressTracker{out: &out}
+	batch := func(completed int, item string) {
+		p.handle(commands.NewEvent(commands.EventFillBatch, map[string]interface{}{"completed": completed, "total": 4, "item": item}))
+	}
+
+	batch(0, "")
+	if got := line(t, p, &out); !strings.HasSuffix(got, "  0% 0/4 ") {
+		t.Errorf("start = %q", got)
+	}
+	p.handle(commands.NewEvent(commands.EventFillStarted, map[string]interface{}{"data_path": "data/alice.json"}))
+	p.handle(commands.NewEvent(commands.EventFillProgress, map[string]interface{}{"stage": "flatten", "progress": 0.5}))
+	if got := line(t, p, &out); !strings.HasSuffix(got, " 12% 0/4 flatten") {
+		t.Errorf("first item half way = %q", got)
+	}
+
+	// Per-item events do not count: a failed item emits an error and the
+	// count comes from the batch event after it
+	p.handle(commands.NewErrorEvent(commands.EventFillError, nil, map[string]interface{}{"data_path": "data/alice.json"}))
+	p.handle(commands.NewEvent(commands.EventFillCompleted, map[string]interface{}{"data_path": "data/alice.json"}))
+	batch(1, "alice")
+	if got := line(t, p, &out); !strings.HasSuffix(got, " 25% 1/4 alice") {
+		t.Errorf("after one item = %q", got)
+	}
+
+	// A dropped item's events do not throw the count off
+	batch(3, "carol")
+	p.handle(commands.NewEvent(commands.EventFillStarted, map[string]interface{}{"data_path": "data/dave.json"}))
+	if got := line(t, p, &out); !strings.HasSuffix(got, " 75% 3/4 dave") {
+		t.Errorf("fourth item = %q", got)
+	}
+	batch(4, "dave")
+	if got := line(t, p, &out); !strings.HasSuffix(got, "100% 4/4 dave") {
+		t.Errorf("done = %q", got)
+	}
+}
+
+func TestProgressTracker_EmptyBatch(t *testing.T) {
+	var out bytes.Buffer
+	p := &progressTracker{out: &out}
+	p.handle(commands.NewEvent(commands.EventTestBatch, map[string]interface{}{"completed": 0, "total": 0}))
+	if out.Len() != 0 {
+		t.Errorf("empty batch rendered %q", out.String())
+	}
+}
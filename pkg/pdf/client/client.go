@@ -0,0 +1,423 @@
+// Package client is a typed HTTP client for the pdfform REST API (web/api),
+// for services that call a remote `pdfform serve` instead of importing the
+// PDF engine. It only depends on the standard library: the response types
+// here mirror the commands layer's results rather than importing them.
+//
+// Example:
+//
+//	c := client.New(client.Options{BaseURL: "https://pdf.internal:8080", Token: os.Getenv("PDF_API_TOKEN")})
+//	c.Download(ctx, "F3520")
+//	c.Inspect(ctx, client.InspectOptions{PDFPath: "f3520.pdf"})
+//	result, err := c.FillWithProgress(ctx, client.FillOptions{CaseID: id}, func(e client.Event) {
+//	    fmt.Println(e.Type, e.Stage())
+//	})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default retry settings
+const (
+	DefaultMaxRetries = 3
+	DefaultRetryWait  = 500 * time.Millisecond // Doubled after every attempt
+	MaxRetryWait      = 10 * time.Second       // Cap for backoff and Retry-After
+)
+
+// Options configures a Client
+type Options struct {
+	BaseURL    string       // Server URL, e.g. "https://localhost:8080" (required)
+	Token      string       // Sent as "Authorization: Bearer" (the server's PDF_API_TOKEN)
+	HTTPClient *http.Client // Default: a client with a 5 minute timeout (fills can be slow)
+	MaxRetries int          // Retries after the first attempt (default: DefaultMaxRetries; -1 disables)
+	RetryWait  time.Duration
+}
+
+// Client calls the pdfform REST API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	token      string
+	http       *http.Client
+	maxRetries int
+	retryWait  time.Duration
+}
+
+// New creates a client
+func New(opts Options) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 5 * time.Minute}
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	} else if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.RetryWait == 0 {
+		opts.RetryWait = DefaultRetryWait
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(opts.BaseURL, "/"),
+		token:      opts.Token,
+		http:       opts.HTTPClient,
+		maxRetries: opts.MaxRetries,
+		retryWait:  opts.RetryWait,
+	}
+}
+
+// ================================================================
+// Errors
+// ================================================================
+
+// APIError is a non-2xx response
+type APIError struct {
+	StatusCode int
+	Message    string // Response body (or its "error" field)
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("pdfform API: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Sentinel errors matched by errors.Is on an *APIError
+var (
+	ErrUnauthorized     = errors.New("unauthorized")      // 401: missing or wrong token
+	ErrNotFound         = errors.New("not found")         // 404, e.g. an unknown case ID
+	ErrPasswordRequired = errors.New("password required") // 422 from Inspect or Fill: the PDF is encrypted
+	ErrRateLimited      = errors.New("rate limited")      // 429 after all retries
+)
+
+// Is maps status codes to the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrPasswordRequired:
+		return e.StatusCode == http.StatusUnprocessableEntity && strings.Contains(e.Message, "password")
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// ================================================================
+// Types
+// ================================================================
+// JSON shapes of the API responses (see web/API.md).
+
+// Form is a catalog entry (pdfform.TransferForm)
+type Form struct {
+	State           string
+	FormName        string
+	FormCode        string
+	Description     string
+	Format          string
+	DirectPDFURL    string
+	InfoURL         string
+	OnlineAvailable bool
+	Notes           string
+	MirrorURLs      []string
+}
+
+// BrowseResult lists the states, or the forms of one state
+type BrowseResult struct {
+	States []string
+	Forms  []Form
+}
+
+// DownloadResult is the result of Download
+type DownloadResult struct {
+	PDFPath     string
+	Form        *Form
+	Metadata    string // Path to .meta.json file
+	CacheStatus string // hit, revalidated, miss, refreshed (empty when not cached)
+}
+
+// UploadResult is the result of Upload
+type UploadResult struct {
+	StoredName   string   `json:"stored_name"`
+	PDFPath      string   `json:"pdf_path"`
+	TemplatePath string   `json:"template_path"`
+	FieldCount   int      `json:"field_count"`
+	Fields       []string `json:"fields"`
+}
+
+// Passwords unlock an encrypted PDF
+type Passwords struct {
+	User  string `json:"user,omitempty"`
+	Owner string `json:"owner,omitempty"`
+}
+
+// InspectOptions configures Inspect
+type InspectOptions struct {
+	PDFPath  string    `json:"pdf_path"` // Relative paths are resolved in the server's downloads directory
+	Password Passwords `json:"password"`
+}
+
+// InspectResult is the result of Inspect
+type InspectResult struct {
+	TemplatePath string
+	FieldCount   int
+	Fields       []string
+}
+
+// FillOptions configures Fill
+type FillOptions struct {
+	CaseID          string    `json:"case_id"`
+	Flatten         bool      `json:"flatten"`
+	Password        Passwords `json:"password"`
+	ProtectPassword string    `json:"protect_password,omitempty"` // Re-protect the output with this password
+}
+
+// FillResult is the result of Fill
+type FillResult struct {
+	OutputPath string
+	InputPDF   string
+	Flattened  bool
+	Stamped    bool
+	PDFA       bool
+	Protected  bool
+	Warnings   []string
+}
+
+// CaseMetadata identifies a case
+type CaseMetadata struct {
+	CaseID    string    `json:"case_id"`
+	CaseName  string    `json:"case_name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Revision  int       `json:"revision"`
+}
+
+// Case is a case with its field values
+type Case struct {
+	Metadata      CaseMetadata `json:"case_metadata"`
+	FormReference struct {
+		FormCode     string `json:"form_code"`
+		TemplatePath string `json:"template_path,omitempty"`
+	} `json:"form_reference"`
+	Fields map[string]string `json:"fields"`
+}
+
+// CaseInfo is an entry of ListCases
+type CaseInfo struct {
+	Path     string       `json:"path"`
+	Metadata CaseMetadata `json:"metadata"`
+	FormCode string       `json:"form_code"`
+}
+
+// CreateCaseOptions configures CreateCase
+type CreateCaseOptions struct {
+	FormCode   string `json:"form_code"`
+	CaseName   string `json:"case_name"`
+	EntityName string `json:"entity_name"`
+}
+
+// ================================================================
+// Operations
+// ================================================================
+
+// Browse lists the states, or the forms of state when it is set
+func (c *Client) Browse(ctx context.Context, state string) (*BrowseResult, error) {
+	path := "/api/browse"
+	if state != "" {
+		path += "?state=" + url.QueryEscape(state)
+	}
+	var result BrowseResult
+	if err := c.get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Download downloads a form PDF to the server's downloads directory
+func (c *Client) Download(ctx context.Context, formCode string) (*DownloadResult, error) {
+	var result DownloadResult
+	if err := c.post(ctx, "/api/download", map[string]string{"form_code": formCode}, true, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Upload uploads a fillable PDF for a form that is not in the catalog
+func (c *Client) Upload(ctx context.Context, filename string, pdf io.Reader) (*UploadResult, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, pdf); err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var result UploadResult
+	if err := c.do(ctx, http.MethodPost, "/api/upload", mw.FormDataContentType(), body.Bytes(), true, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Inspect extracts the fields of a PDF on the server into a JSON template
+func (c *Client) Inspect(ctx context.Context, opts InspectOptions) (*InspectResult, error) {
+	var result InspectResult
+	if err := c.post(ctx, "/api/inspect", opts, true, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Fill fills the PDF of a case
+func (c *Client) Fill(ctx context.Context, opts FillOptions) (*FillResult, error) {
+	var result FillResult
+	if err := c.post(ctx, "/api/fill", opts, true, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListCases lists the cases, of one entity when entity is set
+func (c *Client) ListCases(ctx context.Context, entity string) ([]CaseInfo, error) {
+	path := "/api/cases/list"
+	if entity != "" {
+		path += "?entity=" + url.QueryEscape(entity)
+	}
+	var cases []CaseInfo
+	if err := c.get(ctx, path, &cases); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+// CreateCase creates a case and returns it with its path on the server.
+// It is not retried after server errors, which could create it twice.
+func (c *Client) CreateCase(ctx context.Context, opts CreateCaseOptions) (*Case, string, error) {
+	var result struct {
+		Case     *Case  `json:"case"`
+		CasePath string `json:"case_path"`
+	}
+	if err := c.post(ctx, "/api/cases/create", opts, false, &result); err != nil {
+		return nil, "", err
+	}
+	return result.Case, result.CasePath, nil
+}
+
+// LoadCase returns a case by ID
+func (c *Client) LoadCase(ctx context.Context, caseID string) (*Case, error) {
+	var result Case
+	if err := c.get(ctx, "/api/cases/load?case_id="+url.QueryEscape(caseID), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ================================================================
+// Transport
+// ================================================================
+
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, "", nil, true, out)
+}
+
+func (c *Client) post(ctx context.Context, path string, in interface{}, idempotent bool, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, path, "application/json", body, idempotent, out)
+}
+
+// do sends a request, retrying rate limited requests (429, honouring
+// Retry-After) and, when idempotent, network errors and 502/503/504
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte, idempotent bool, out interface{}) error {
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, contentType, body)
+		retry := err != nil && idempotent && ctx.Err() == nil
+		if err == nil {
+			if resp.StatusCode < 300 {
+				defer resp.Body.Close()
+				if out == nil {
+					return nil
+				}
+				if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+					return fmt.Errorf("failed to decode %s response: %w", path, err)
+				}
+				return nil
+			}
+
+			err = readAPIError(resp)
+			switch resp.StatusCode {
+			case http.StatusTooManyRequests:
+				retry = true
+				if s, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil {
+					wait = time.Duration(s) * time.Second
+				}
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				retry = idempotent
+			}
+		}
+
+		if !retry || attempt >= c.maxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(math.Min(float64(wait), float64(MaxRetryWait)))):
+		}
+		wait *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path, contentType string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, r)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	c.authorize(req)
+	return c.http.Do(req)
+}
+
+func (c *Client) authorize(req *http.Request) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
+// readAPIError reads and closes an error response
+func readAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	msg := strings.TrimSpace(string(data))
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		msg = body.Error
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: msg}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/pdf/commands"
+	"github.com/joeblew999/wellknown/pkg/pdf/web/api"
+	"github.com/joeblew999/wellknown/pkg/pdf/web/httputil"
+)
+
+// newTestServer serves the real event stream behind the real guard, with a
+// fake download that emits the events commands.Download would
+func newTestServer(t *testing.T, token string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events", api.NewHandler(nil).HandleEvents)
+	mux.HandleFunc("/api/download", func(w http.ResponseWriter, r *http.Request) {
+		commands.Emit(commands.EventDownloadStarted, map[string]interface{}{"form_code": "OTHER"})
+		commands.Emit(commands.EventDownloadStarted, map[string]interface{}{"form_code": "F3520"})
+		commands.Emit(commands.EventDownloadProgress, map[string]interface{}{
+			"form_code": "F3520", "stage": commands.DownloadStageDownloading, "progress": commands.ProgressDownloading,
+		})
+		commands.Emit(commands.EventDownloadCompleted, map[string]interface{}{"form_code": "F3520"})
+		httputil.RespondJSONOK(w, commands.DownloadResult{PDFPath: "downloads/f3520.pdf"})
+	})
+	srv := httptest.NewServer(httputil.Guard(httputil.GuardConfig{APIToken: token}, mux))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient_DownloadWithProgress(t *testing.T) {
+	srv := newTestServer(t, "s3cret")
+	c := New(Options{BaseURL: srv.URL, Token: "s3cret"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var events []Event
+	result, err := c.DownloadWithProgress(ctx, "F3520", func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatalf("DownloadWithProgress: %v", err)
+	}
+	if result.PDFPath != "downloads/f3520.pdf" {
+		t.Errorf("PDFPath = %q", result.PDFPath)
+	}
+
+	want := []string{"download.started", "download.progress", "download.completed"}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events (%+v), want %v", len(events), events, want)
+	}
+	for i, typ := range want {
+		if events[i].Type != typ {
+			t.Errorf("event %d = %s, want %s", i, events[i].Type, typ)
+		}
+	}
+	if events[1].Stage() != commands.DownloadStageDownloading || events[1].Progress() != commands.ProgressDownloading {
+		t.Errorf("progress event = %+v", events[1])
+	}
+	if !events[2].Done() {
+		t.Error("download.completed should be Done")
+	}
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	srv := newTestServer(t, "s3cret")
+	c := New(Options{BaseURL: srv.URL, Token: "wrong"})
+
+	_, err := c.Download(context.Background(), "F3520")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Download() error = %v, want ErrUnauthorized", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "unauthorized" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			httputil.RespondError(w, http.StatusTooManyRequests, "slow down")
+		case 2:
+			httputil.RespondError(w, http.StatusServiceUnavailable, "restarting")
+		default:
+			httputil.RespondJSONOK(w, []CaseInfo{{Path: "cases/a.json", FormCode: "F3520"}})
+		}
+	}))
+	defer srv.Close()
+
+	c := New(Options{BaseURL: srv.URL, RetryWait: time.Millisecond})
+	cases, err := c.ListCases(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListCases: %v", err)
+	}
+	if len(cases) != 1 || calls.Load() != 3 {
+		t.Errorf("cases = %+v after %d calls, want 1 after 3", cases, calls.Load())
+	}
+
+	// Creating a case is not idempotent, so a 503 is not retried
+	calls.Store(1)
+	if _, _, err := c.CreateCase(context.Background(), CreateCaseOptions{FormCode: "F3520"}); err == nil || calls.Load() != 2 {
+		t.Errorf("CreateCase() error = %v after %d calls, want 503 after 1", err, calls.Load()-1)
+	}
+
+	// Out of retries
+	calls.Store(0)
+	c = New(Options{BaseURL: srv.URL, MaxRetries: -1})
+	if _, err := c.ListCases(context.Background(), ""); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("ListCases() error = %v, want ErrRateLimited", err)
+	}
+}
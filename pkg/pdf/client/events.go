@@ -0,0 +1,204 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ================================================================
+// Progress Events
+// ================================================================
+// The server streams the commands layer's event bus at /api/events as
+// Server-Sent Events. Events are server-wide, so the *WithProgress helpers
+// subscribe before starting the operation and pass on only the events of
+// that operation (by form code or case).
+
+// completionGrace is how long the *WithProgress helpers wait for the final
+// event after the response arrived (the stream can lag behind it)
+const completionGrace = 2 * time.Second
+
+// Event is a command event, e.g. "download.progress"
+type Event struct {
+	Type      string                 `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// Stage returns the "stage" of a progress event, e.g. "downloading"
+func (e Event) Stage() string {
+	s, _ := e.Data["stage"].(string)
+	return s
+}
+
+// Progress returns the "progress" (0.0 - 1.0) of a progress event
+func (e Event) Progress() float64 {
+	p, _ := e.Data["progress"].(float64)
+	return p
+}
+
+// Done reports whether the event ends an operation (.completed or .error)
+func (e Event) Done() bool {
+	return strings.HasSuffix(e.Type, ".completed") || strings.HasSuffix(e.Type, ".error")
+}
+
+// Events streams the server's events matching pattern ("download.*", "*";
+// empty means all). It returns once subscribed; the channel is closed when
+// ctx is done or the connection drops.
+func (c *Client) Events(ctx context.Context, pattern string) (<-chan Event, error) {
+	path := "/api/events"
+	if pattern != "" {
+		path += "?pattern=" + url.QueryEscape(pattern)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	c.authorize(req)
+
+	// The stream outlives the client's request timeout
+	stream := *c.http
+	stream.Timeout = 0
+	resp, err := stream.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, readAPIError(resp)
+	}
+
+	events := make(chan Event, 16)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+		readEvents(resp.Body, func(e Event) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return events, nil
+}
+
+// readEvents parses an SSE stream, calling emit for every event until it
+// returns false or the stream ends
+func readEvents(r io.Reader, emit func(Event) bool) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var e Event
+			err := json.Unmarshal([]byte(data.String()), &e)
+			data.Reset()
+			if err == nil && !emit(e) {
+				return
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		// "event:" repeats the JSON type; ":" lines are heartbeats
+	}
+}
+
+// DownloadWithProgress is Download, calling progress for each of its events
+func (c *Client) DownloadWithProgress(ctx context.Context, formCode string, progress func(Event)) (*DownloadResult, error) {
+	var result *DownloadResult
+	match := func(e Event) bool {
+		code, _ := e.Data["form_code"].(string)
+		return code == formCode
+	}
+	err := c.withProgress(ctx, "download.*", match, progress, func(ctx context.Context) (err error) {
+		result, err = c.Download(ctx, formCode)
+		return err
+	})
+	return result, err
+}
+
+// FillWithProgress is Fill, calling progress for each of its events
+func (c *Client) FillWithProgress(ctx context.Context, opts FillOptions, progress func(Event)) (*FillResult, error) {
+	var result *FillResult
+	match := func(e Event) bool {
+		// Case files are named <case_id>.json
+		casePath, _ := e.Data["case_path"].(string)
+		return strings.HasSuffix(casePath, opts.CaseID+".json")
+	}
+	err := c.withProgress(ctx, "fill.*", match, progress, func(ctx context.Context) (err error) {
+		result, err = c.Fill(ctx, opts)
+		return err
+	})
+	return result, err
+}
+
+// withProgress runs op while forwarding its events to progress, then waits
+// (up to completionGrace) for the event that ends it
+func (c *Client) withProgress(ctx context.Context, pattern string, match func(Event) bool, progress func(Event), op func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := c.Events(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- op(ctx) }()
+
+	var (
+		opErr    error
+		finished bool
+		ended    bool // The final event was seen
+		grace    <-chan time.Time
+	)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				if finished {
+					return opErr
+				}
+				events = nil // Connection dropped; the operation still reports its result
+				continue
+			}
+			if !match(e) {
+				continue
+			}
+			if progress != nil {
+				progress(e)
+			}
+			if e.Done() {
+				if finished {
+					return opErr
+				}
+				ended = true
+			}
+		case opErr = <-done:
+			if ended || events == nil {
+				return opErr
+			}
+			finished = true
+			done = nil
+			grace = time.After(completionGrace)
+		case <-grace:
+			return opErr
+		}
+	}
+}
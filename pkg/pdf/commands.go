@@ -46,13 +46,15 @@ type DownloadOptions struct {
 	CatalogPath string
 	FormCode    string
 	OutputDir   string
+	CacheDir    string // Download cache directory (empty = no cache)
 }
 
 // DownloadResult contains the results of downloading a form
 type DownloadResult struct {
-	PDFPath  string
-	Form     *TransferForm
-	Metadata string // Path to .meta.json file
+	PDFPath     string
+	Form        *TransferForm
+	Metadata    string      // Path to .meta.json file
+	CacheStatus CacheStatus // hit, revalidated, miss, refreshed (empty when not cached)
 }
 
 // Download downloads a form PDF by its code from the catalog
@@ -67,7 +69,7 @@ func Download(opts DownloadOptions) (*DownloadResult, error) {
 		return nil, fmt.Errorf("form with code '%s' not found", opts.FormCode)
 	}
 
-	if form.DirectPDFURL == "" {
+	if len(form.SourceURLs()) == 0 {
 		return nil, fmt.Errorf("form '%s' does not have a direct PDF URL", opts.FormCode)
 	}
 
@@ -76,8 +78,8 @@ func Download(opts DownloadOptions) (*DownloadResult, error) {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Download the form
-	pdfPath, err := catalog.DownloadFormPDF(form, opts.OutputDir)
+	// Download the form (through the cache when configured)
+	pdfPath, cacheStatus, err := downloadForm(catalog, form, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download form: %w", err)
 	}
@@ -88,19 +90,36 @@ func Download(opts DownloadOptions) (*DownloadResult, error) {
 		fmt.Printf("⚠️  Warning: Could not save provenance metadata: %v\n", err)
 	}
 
+	recordChecksums(pdfPath)
+
 	metadataPath := pdfPath[:len(pdfPath)-len(filepath.Ext(pdfPath))] + ".meta.json"
 
 	return &DownloadResult{
-		PDFPath:  pdfPath,
-		Form:     form,
-		Metadata: metadataPath,
+		PDFPath:     pdfPath,
+		Form:        form,
+		Metadata:    metadataPath,
+		CacheStatus: cacheStatus,
 	}, nil
 }
 
+// downloadForm downloads form directly, or via the cache if opts.CacheDir is set
+func downloadForm(catalog *FormsCatalog, form *TransferForm, opts DownloadOptions) (string, CacheStatus, error) {
+	if opts.CacheDir == "" {
+		pdfPath, err := catalog.DownloadFormPDF(form, opts.OutputDir)
+		return pdfPath, "", err
+	}
+	checksum, err := CatalogChecksum(opts.CatalogPath)
+	if err != nil {
+		return "", "", err
+	}
+	return catalog.DownloadFormPDFCached(form, opts.OutputDir, NewPDFCache(opts.CacheDir), checksum)
+}
+
 // InspectOptions contains options for inspecting a PDF form
 type InspectOptions struct {
 	PDFPath   string
 	OutputDir string
+	Password  Passwords // Unlocks an encrypted PDF (owner-only protection needs none)
 }
 
 // InspectResult contains the results of inspecting a PDF form
@@ -134,16 +153,18 @@ func Inspect(opts InspectOptions) (*InspectResult, error) {
 	}
 
 	// Extract form fields
-	fields, err := ListFormFields(opts.PDFPath)
+	fields, err := ListFormFieldsWithPasswords(opts.PDFPath, opts.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list form fields: %w", err)
 	}
 
 	// Export to JSON template
-	if err := ExportFormFieldsToJSON(opts.PDFPath, outputPath); err != nil {
+	if err := ExportFormFieldsToJSONWithPasswords(opts.PDFPath, outputPath, opts.Password); err != nil {
 		return nil, fmt.Errorf("failed to export form fields: %w", err)
 	}
 
+	recordChecksums(outputPath)
+
 	// Update provenance metadata with inspection timestamp
 	if err := AddInspectedTimestamp(opts.PDFPath); err != nil {
 		// Ignore error if no metadata exists
@@ -167,6 +188,13 @@ type FillOptions struct {
 	DataPath  string
 	OutputDir string
 	Flatten   bool
+	Password  Passwords        // Unlocks an encrypted input PDF
+	Metadata  *Metadata        // Stamp document metadata (and optionally convert to PDF/A) when set
+	Protect   *Protection      // Re-protect the output with new passwords when set
+	Deliver   *DeliveryOptions // Email the output when set (PDFPath is filled in)
+
+	CatalogPath string // Catalog to check a case's pinned form version against (FillFromCase only)
+	Actor       string // Recorded in the case history (FillFromCase only; default: DefaultActor)
 }
 
 // FillResult contains the results of filling a PDF form
@@ -174,10 +202,19 @@ type FillResult struct {
 	OutputPath string
 	InputPDF   string
 	Flattened  bool
+	Stamped    bool // Metadata was applied
+	PDFA       bool // Converted to PDF/A
+	Protected  bool
+	Warnings   []string        `json:",omitempty"` // e.g. the case's pinned form version changed
+	Delivery   *DeliveryResult `json:",omitempty"`
 }
 
 // Fill fills a PDF form using JSON data
 func Fill(opts FillOptions) (*FillResult, error) {
+	if opts.Metadata != nil && opts.Metadata.PDFA && opts.Protect != nil {
+		return nil, fmt.Errorf("PDF/A output cannot be password-protected")
+	}
+
 	// Determine output path
 	outputPath := opts.OutputDir
 	if outputPath == "" {
@@ -200,7 +237,7 @@ func Fill(opts FillOptions) (*FillResult, error) {
 	}
 
 	// Fill the PDF
-	inputPDF, err := FillPDFFromJSON(opts.DataPath, outputPath)
+	inputPDF, err := FillPDFFromJSONWithPasswords(opts.DataPath, outputPath, opts.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fill PDF: %w", err)
 	}
@@ -221,6 +258,37 @@ func Fill(opts FillOptions) (*FillResult, error) {
 		result.Flattened = true
 	}
 
+	// Stamp metadata if requested
+	if opts.Metadata != nil {
+		if err := ApplyMetadata(result.OutputPath, *opts.Metadata); err != nil {
+			return nil, err
+		}
+		result.Stamped = true
+		result.PDFA = opts.Metadata.PDFA
+	}
+
+	// Re-protect if requested (last: flattening and stamping need an unencrypted PDF)
+	if opts.Protect != nil {
+		if err := ProtectPDF(result.OutputPath, result.OutputPath, *opts.Protect); err != nil {
+			return nil, err
+		}
+		result.Protected = true
+	}
+
+	// Record the final output (and the unflattened fill, which is kept too)
+	recordChecksums(outputPath, result.OutputPath)
+
+	// Deliver if requested (the filled PDF is kept even if sending fails)
+	if opts.Deliver != nil {
+		deliver := *opts.Deliver
+		deliver.PDFPath = result.OutputPath
+		delivery, err := DeliverPDF(deliver)
+		if err != nil {
+			return result, err
+		}
+		result.Delivery = delivery
+	}
+
 	return result, nil
 }
 
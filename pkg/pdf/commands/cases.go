@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"path/filepath"
 
 	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
@@ -8,10 +9,12 @@ import (
 
 // CreateCaseOptions contains options for creating a case
 type CreateCaseOptions struct {
-	FormCode   string
-	CaseName   string
-	EntityName string
-	DataDir    string
+	FormCode    string
+	CaseName    string
+	EntityName  string
+	DataDir     string
+	CatalogPath string // Pin the case to the form's current catalog entry when set
+	PDFPath     string // Also pin the downloaded form PDF (optional)
 }
 
 // CreateCaseResult contains the result of creating a case
@@ -23,10 +26,23 @@ type CreateCaseResult struct {
 // CreateCase creates a new case
 // Emits events: case.created, case.error
 func CreateCase(formCode, caseName, entityName, dataDir string) (*pdfform.Case, string, error) {
+	return CreateCaseWithOptions(CreateCaseOptions{
+		FormCode:   formCode,
+		CaseName:   caseName,
+		EntityName: entityName,
+		DataDir:    dataDir,
+	})
+}
+
+// CreateCaseWithOptions creates a new case, pinning its form version when CatalogPath is set
+// Emits events: case.created, case.error
+func CreateCaseWithOptions(opts CreateCaseOptions) (*pdfform.Case, string, error) {
+	formCode, caseName, entityName := opts.FormCode, opts.CaseName, opts.EntityName
+
 	// Emit started event (using case.created type since there's no case.started)
 	// We could add a case.creating event if needed
 
-	c, casePath, err := pdfform.CreateCase(formCode, caseName, entityName, dataDir)
+	c, casePath, err := pdfform.CreateCaseWithOptions(pdfform.CreateCaseOptions(opts))
 	if err != nil {
 		EmitError(EventCaseError, err, map[string]interface{}{
 			"form_code":   formCode,
@@ -93,16 +109,25 @@ func LoadCase(casePath string) (*pdfform.Case, error) {
 type SaveCaseOptions struct {
 	Case     *pdfform.Case
 	CasePath string
+	Actor    string // Recorded in the case history (default: pdfform.DefaultActor)
 }
 
 // SaveCase saves a case to a file
 // Emits events: case.updated, case.error
 func SaveCase(c *pdfform.Case, casePath string) error {
-	err := pdfform.SaveCase(c, casePath)
+	return SaveCaseWithOptions(SaveCaseOptions{Case: c, CasePath: casePath})
+}
+
+// SaveCaseWithOptions is SaveCase recording opts.Actor in the case history
+// Emits events: case.updated, case.error
+func SaveCaseWithOptions(opts SaveCaseOptions) error {
+	c, casePath := opts.Case, opts.CasePath
+	err := pdfform.SaveCaseWithOptions(c, casePath, pdfform.SaveCaseOptions{Actor: opts.Actor})
 	if err != nil {
 		EmitError(EventCaseError, err, map[string]interface{}{
-			"case_id": c.Metadata.CaseID,
-			"stage":   "save",
+			"case_id":  c.Metadata.CaseID,
+			"stage":    "save",
+			"conflict": errors.Is(err, pdfform.ErrCaseConflict),
 		})
 		return err
 	}
@@ -113,6 +138,7 @@ func SaveCase(c *pdfform.Case, casePath string) error {
 		"case_name": c.Metadata.CaseName,
 		"form_code": c.FormReference.FormCode,
 		"case_path": casePath,
+		"revision":  c.Metadata.Revision,
 	})
 
 	return nil
@@ -134,3 +160,49 @@ func FindCaseByID(caseID, dataDir string) (string, error) {
 
 	return "", nil
 }
+
+// MigrateCase re-maps a case's fields with a mapping profile and re-pins its form version
+// Emits events: case.updated (unless dry run), case.error
+func MigrateCase(opts pdfform.MigrateCaseOptions) (*pdfform.MigrateCaseResult, error) {
+	result, err := pdfform.MigrateCase(opts)
+	if err != nil {
+		EmitError(EventCaseError, err, map[string]interface{}{
+			"case_path": opts.CasePath,
+			"stage":     "migrate",
+		})
+		return nil, err
+	}
+
+	if result.Saved {
+		Emit(EventCaseUpdated, map[string]interface{}{
+			"case_path": opts.CasePath,
+			"renamed":   len(result.Renamed),
+			"dropped":   len(result.Dropped),
+			"added":     len(result.Added),
+		})
+	}
+
+	return result, nil
+}
+
+// ExportCase writes a case bundle ZIP (case data, template, filled PDFs, manifest)
+// Emits events: case.exported, case.error
+func ExportCase(opts pdfform.ExportCaseOptions) (*pdfform.ExportCaseResult, error) {
+	result, err := pdfform.ExportCase(opts)
+	if err != nil {
+		EmitError(EventCaseError, err, map[string]interface{}{
+			"case_id":   opts.CaseID,
+			"case_path": opts.CasePath,
+			"stage":     "export",
+		})
+		return nil, err
+	}
+
+	Emit(EventCaseExported, map[string]interface{}{
+		"case_id":     result.Manifest.CaseID,
+		"output_path": result.OutputPath,
+		"files":       len(result.Manifest.Files),
+	})
+
+	return result, nil
+}
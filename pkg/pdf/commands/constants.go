@@ -26,6 +26,8 @@ const (
 	StageExportJSON = "export_json"
 	StageFillPDF    = "fill_pdf"
 	StageFlatten    = "flatten"
+	StageMetadata   = "metadata"
+	StageProtect    = "protect"
 	StageLoadCase   = "load_case"
 	StageSaveCase   = "save_case"
 	StageCreate     = "create"
@@ -41,6 +43,14 @@ const (
 	ProgressComplete    = 1.0
 )
 
+// Progress values for fill operations
+const (
+	ProgressFilling    = 0.1
+	ProgressFlattening = 0.6
+	ProgressStamping   = 0.75
+	ProgressProtecting = 0.9
+)
+
 // File permissions
 const (
 	DefaultDirPerm os.FileMode = 0755
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"path/filepath"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+// Deliver emails a filled PDF using the SMTP settings from the environment
+// Emits events: delivery.started, delivery.completed, delivery.error
+func Deliver(opts pdfform.DeliveryOptions) (*pdfform.DeliveryResult, error) {
+	Emit(EventDeliveryStarted, map[string]interface{}{
+		"pdf_path": opts.PDFPath,
+		"to":       opts.To,
+	})
+
+	result, err := pdfform.DeliverPDF(opts)
+	if err != nil {
+		EmitError(EventDeliveryError, err, map[string]interface{}{
+			"pdf_path": opts.PDFPath,
+		})
+		return nil, err
+	}
+
+	Emit(EventDeliveryCompleted, map[string]interface{}{
+		"pdf_path": opts.PDFPath,
+		"file":     filepath.Base(opts.PDFPath),
+		"to":       result.To,
+		"subject":  result.Subject,
+	})
+
+	return result, nil
+}
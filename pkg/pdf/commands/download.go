@@ -13,13 +13,15 @@ type DownloadOptions struct {
 	CatalogPath string
 	FormCode    string
 	OutputDir   string
+	CacheDir    string // Download cache directory (empty = no cache)
 }
 
 // DownloadResult contains the results of downloading a form
 type DownloadResult struct {
-	PDFPath  string
-	Form     *pdfform.TransferForm
-	Metadata string // Path to .meta.json file
+	PDFPath     string
+	Form        *pdfform.TransferForm
+	Metadata    string              // Path to .meta.json file
+	CacheStatus pdfform.CacheStatus // hit, revalidated, miss, refreshed (empty when not cached)
 }
 
 // Download downloads a form PDF by its code from the catalog
@@ -50,7 +52,7 @@ func Download(opts DownloadOptions) (*DownloadResult, error) {
 		return nil, err
 	}
 
-	if form.DirectPDFURL == "" {
+	if len(form.SourceURLs()) == 0 {
 		err := fmt.Errorf("form '%s' does not have a direct PDF URL", opts.FormCode)
 		EmitError(EventDownloadError, err, map[string]interface{}{
 			"form_code": opts.FormCode,
@@ -83,8 +85,18 @@ func Download(opts DownloadOptions) (*DownloadResult, error) {
 		"progress":  ProgressDownloading,
 	})
 
-	// Download the form
-	pdfPath, err := catalog.DownloadFormPDF(form, opts.OutputDir)
+	// Download the form (through the cache when configured)
+	var pdfPath string
+	var cacheStatus pdfform.CacheStatus
+	if opts.CacheDir == "" {
+		pdfPath, err = catalog.DownloadFormPDF(form, opts.OutputDir)
+	} else {
+		var checksum string
+		checksum, err = pdfform.CatalogChecksum(opts.CatalogPath)
+		if err == nil {
+			pdfPath, cacheStatus, err = catalog.DownloadFormPDFCached(form, opts.OutputDir, pdfform.NewPDFCache(opts.CacheDir), checksum)
+		}
+	}
 	if err != nil {
 		EmitStageError(EventDownloadError, DownloadStageDownloadPDF, err, map[string]interface{}{
 			"form_code": opts.FormCode,
@@ -106,12 +118,15 @@ func Download(opts DownloadOptions) (*DownloadResult, error) {
 		fmt.Printf("⚠️  Warning: Could not save provenance metadata: %v\n", err)
 	}
 
+	recordChecksums(pdfPath)
+
 	metadataPath := pdfPath[:len(pdfPath)-len(filepath.Ext(pdfPath))] + MetaJSONSuffix
 
 	result := &DownloadResult{
-		PDFPath:  pdfPath,
-		Form:     form,
-		Metadata: metadataPath,
+		PDFPath:     pdfPath,
+		Form:        form,
+		Metadata:    metadataPath,
+		CacheStatus: cacheStatus,
 	}
 
 	// Emit completed event
@@ -120,6 +135,7 @@ func Download(opts DownloadOptions) (*DownloadResult, error) {
 		"pdf_path":  pdfPath,
 		"form_name": form.FormName,
 		"state":     form.State,
+		"cache":     string(cacheStatus),
 		"progress":  ProgressComplete,
 	})
 
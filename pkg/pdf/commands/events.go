@@ -21,6 +21,11 @@ const (
 	EventDownloadCompleted EventType = "download.completed"
 	EventDownloadError     EventType = "download.error"
 
+	// Upload events
+	EventUploadStarted   EventType = "upload.started"
+	EventUploadCompleted EventType = "upload.completed"
+	EventUploadError     EventType = "upload.error"
+
 	// Inspect events
 	EventInspectStarted   EventType = "inspect.started"
 	EventInspectCompleted EventType = "inspect.completed"
@@ -28,19 +33,28 @@ const (
 
 	// Fill events
 	EventFillStarted   EventType = "fill.started"
+	EventFillProgress  EventType = "fill.progress"
 	EventFillCompleted EventType = "fill.completed"
 	EventFillError     EventType = "fill.error"
+	EventFillBatch     EventType = "fill.batch" // A batch of fills advanced (see EmitBatch)
+
+	// Delivery events
+	EventDeliveryStarted   EventType = "delivery.started"
+	EventDeliveryCompleted EventType = "delivery.completed"
+	EventDeliveryError     EventType = "delivery.error"
 
 	// Case events
-	EventCaseCreated EventType = "case.created"
-	EventCaseLoaded  EventType = "case.loaded"
-	EventCaseUpdated EventType = "case.updated"
-	EventCaseError   EventType = "case.error"
+	EventCaseCreated  EventType = "case.created"
+	EventCaseLoaded   EventType = "case.loaded"
+	EventCaseUpdated  EventType = "case.updated"
+	EventCaseExported EventType = "case.exported"
+	EventCaseError    EventType = "case.error"
 
 	// Test events
 	EventTestStarted   EventType = "test.started"
 	EventTestCompleted EventType = "test.completed"
 	EventTestError     EventType = "test.error"
+	EventTestBatch     EventType = "test.batch" // A batch of tests advanced (see EmitBatch)
 )
 
 // Event represents a system event
@@ -160,6 +174,13 @@ type FillStartedData struct {
 	CasePath  string `json:"case_path,omitempty"` // If filling from case
 }
 
+// FillProgressData contains fields for fill.progress event
+type FillProgressData struct {
+	DataPath string  `json:"data_path"`
+	Stage    string  `json:"stage"`    // fill_pdf, flatten, metadata, protect
+	Progress float64 `json:"progress"` // 0.0 - 1.0
+}
+
 // FillCompletedData contains fields for fill.completed event
 type FillCompletedData struct {
 	DataPath   string `json:"data_path,omitempty"`
@@ -214,9 +235,10 @@ type TestStartedData struct {
 
 // TestCompletedData contains fields for test.completed event
 type TestCompletedData struct {
-	TestName string `json:"test_name"`
-	CasePath string `json:"case_path"`
-	Success  bool   `json:"success"`
+	TestName   string `json:"test_name"`
+	CasePath   string `json:"case_path"`
+	Success    bool   `json:"success"`
+	OutputPath string `json:"output_path,omitempty"`
 }
 
 // TestErrorData contains fields for test.error event
@@ -226,6 +248,13 @@ type TestErrorData struct {
 	Stage    string `json:"stage"`
 }
 
+// BatchData contains fields for fill.batch and test.batch events
+type BatchData struct {
+	Completed int    `json:"completed"` // Items finished, successfully or not
+	Total     int    `json:"total"`
+	Item      string `json:"item,omitempty"` // Item just finished ("" at the start)
+}
+
 // EventBus manages event subscriptions and publishing
 type EventBus struct {
 	mu          sync.RWMutex
@@ -331,6 +360,18 @@ func Emit(eventType EventType, data map[string]interface{}) {
 	DefaultEventBus.Publish(event)
 }
 
+// EmitBatch publishes the position of a batch: completed of total items are
+// finished, the last being item. Loops over an operation emit it once before
+// the first item and after each one, so progress needs no event counting
+// (per-item events may be dropped when a subscriber falls behind).
+func EmitBatch(eventType EventType, completed, total int, item string) {
+	Emit(eventType, map[string]interface{}{
+		"completed": completed,
+		"total":     total,
+		"item":      item,
+	})
+}
+
 // EmitError publishes an error event to the default event bus
 func EmitError(eventType EventType, err error, data map[string]interface{}) {
 	event := NewErrorEvent(eventType, err, data)
@@ -12,6 +12,10 @@ type FillOptions struct {
 	DataPath  string
 	OutputDir string
 	Flatten   bool
+	Password  pdfform.Passwords        // Unlocks an encrypted input PDF
+	Metadata  *pdfform.Metadata        // Stamp document metadata (and optionally convert to PDF/A) when set
+	Protect   *pdfform.Protection      // Re-protect the output with new passwords when set
+	Deliver   *pdfform.DeliveryOptions // Email the output when set (PDFPath is filled in)
 }
 
 // FillResult contains the results of filling a PDF form
@@ -19,11 +23,20 @@ type FillResult struct {
 	OutputPath string
 	InputPDF   string
 	Flattened  bool
+	Stamped    bool // Metadata was applied
+	PDFA       bool // Converted to PDF/A
+	Protected  bool
+	Warnings   []string                `json:",omitempty"` // e.g. the case's pinned form version changed
+	Delivery   *pdfform.DeliveryResult `json:",omitempty"`
 }
 
 // Fill fills a PDF form using JSON data
 // Emits events: fill.started, fill.completed, fill.error
 func Fill(opts FillOptions) (*FillResult, error) {
+	if opts.Metadata != nil && opts.Metadata.PDFA && opts.Protect != nil {
+		return nil, fmt.Errorf("PDF/A output cannot be password-protected")
+	}
+
 	// Emit started event
 	Emit(EventFillStarted, map[string]interface{}{
 		"data_path":  opts.DataPath,
@@ -43,7 +56,8 @@ func Fill(opts FillOptions) (*FillResult, error) {
 	}
 
 	// Fill the PDF
-	inputPDF, err := pdfform.FillPDFFromJSON(opts.DataPath, outputPath)
+	emitFillProgress(opts.DataPath, StageFillPDF, ProgressFilling)
+	inputPDF, err := pdfform.FillPDFFromJSONWithPasswords(opts.DataPath, outputPath, opts.Password)
 	if err != nil {
 		EmitStageError(EventFillError, StageFillPDF, err, map[string]interface{}{
 			"data_path": opts.DataPath,
@@ -59,6 +73,7 @@ func Fill(opts FillOptions) (*FillResult, error) {
 
 	// Flatten if requested
 	if opts.Flatten {
+		emitFillProgress(opts.DataPath, StageFlatten, ProgressFlattening)
 		flatPath := outputPath[:len(outputPath)-len(filepath.Ext(outputPath))] + FlatPDFSuffix
 		if err := pdfform.FlattenPDF(outputPath, flatPath); err != nil {
 			EmitStageError(EventFillError, StageFlatten, err, map[string]interface{}{
@@ -70,35 +85,108 @@ func Fill(opts FillOptions) (*FillResult, error) {
 		result.Flattened = true
 	}
 
+	// Stamp metadata if requested
+	if opts.Metadata != nil {
+		emitFillProgress(opts.DataPath, StageMetadata, ProgressStamping)
+		if err := pdfform.ApplyMetadata(result.OutputPath, *opts.Metadata); err != nil {
+			EmitStageError(EventFillError, StageMetadata, err, map[string]interface{}{
+				"data_path": opts.DataPath,
+			})
+			return nil, err
+		}
+		result.Stamped = true
+		result.PDFA = opts.Metadata.PDFA
+	}
+
+	// Re-protect if requested (last: flattening and stamping need an unencrypted PDF)
+	if opts.Protect != nil {
+		emitFillProgress(opts.DataPath, StageProtect, ProgressProtecting)
+		if err := pdfform.ProtectPDF(result.OutputPath, result.OutputPath, *opts.Protect); err != nil {
+			EmitStageError(EventFillError, StageProtect, err, map[string]interface{}{
+				"data_path": opts.DataPath,
+			})
+			return nil, err
+		}
+		result.Protected = true
+	}
+
+	// Record the final output (and the unflattened fill, which is kept too)
+	recordChecksums(outputPath, result.OutputPath)
+
 	// Emit completed event
 	Emit(EventFillCompleted, map[string]interface{}{
 		"data_path":   opts.DataPath,
 		"output_path": result.OutputPath,
 		"input_pdf":   result.InputPDF,
 		"flattened":   result.Flattened,
+		"pdfa":        result.PDFA,
+		"protected":   result.Protected,
 	})
 
+	// Deliver if requested (the filled PDF is kept even if sending fails)
+	if opts.Deliver != nil {
+		deliver := *opts.Deliver
+		deliver.PDFPath = result.OutputPath
+		delivery, err := Deliver(deliver)
+		if err != nil {
+			return result, err
+		}
+		result.Delivery = delivery
+	}
+
 	return result, nil
 }
 
+// emitFillProgress reports a fill stage on the event bus
+func emitFillProgress(dataPath, stage string, progress float64) {
+	Emit(EventFillProgress, map[string]interface{}{
+		"data_path": dataPath,
+		"stage":     stage,
+		"progress":  progress,
+	})
+}
+
 // FillFromCaseOptions contains options for filling from a case
 type FillFromCaseOptions struct {
 	CasePath  string
 	OutputDir string
 	Flatten   bool
+	Password  pdfform.Passwords   // Unlocks an encrypted input PDF
+	Metadata  *pdfform.Metadata   // Stamp document metadata (CaseID defaults to the case's ID)
+	Protect   *pdfform.Protection // Re-protect the output with new passwords when set
+
+	CatalogPath string // Warn when the case's pinned form version no longer matches this catalog
+	Actor       string // Recorded in the case history (default: pdfform.DefaultActor)
 }
 
 // FillFromCase fills a PDF form using data from a case file
 // Emits events: fill.started, fill.completed, fill.error
 func FillFromCase(casePath, outputDir string, flatten bool) (*FillResult, error) {
+	return FillFromCaseWithOptions(FillFromCaseOptions{CasePath: casePath, OutputDir: outputDir, Flatten: flatten})
+}
+
+// FillFromCaseWithOptions is FillFromCase with passwords and output protection
+// Emits events: fill.started, fill.completed, fill.error
+func FillFromCaseWithOptions(opts FillFromCaseOptions) (*FillResult, error) {
+	casePath := opts.CasePath
+
 	// Emit started event
 	Emit(EventFillStarted, map[string]interface{}{
 		"case_path":  casePath,
-		"output_dir": outputDir,
-		"flatten":    flatten,
+		"output_dir": opts.OutputDir,
+		"flatten":    opts.Flatten,
 	})
 
-	pdfResult, err := pdfform.FillFromCase(casePath, outputDir, flatten)
+	pdfResult, err := pdfform.FillFromCaseWithOptions(casePath, pdfform.FillOptions{
+		OutputDir: opts.OutputDir,
+		Flatten:   opts.Flatten,
+		Password:  opts.Password,
+		Metadata:  opts.Metadata,
+		Protect:   opts.Protect,
+
+		CatalogPath: opts.CatalogPath,
+		Actor:       opts.Actor,
+	})
 	if err != nil {
 		EmitError(EventFillError, err, map[string]interface{}{
 			"case_path": casePath,
@@ -112,6 +200,10 @@ func FillFromCase(casePath, outputDir string, flatten bool) (*FillResult, error)
 		OutputPath: pdfResult.OutputPath,
 		InputPDF:   pdfResult.InputPDF,
 		Flattened:  pdfResult.Flattened,
+		Stamped:    pdfResult.Stamped,
+		PDFA:       pdfResult.PDFA,
+		Protected:  pdfResult.Protected,
+		Warnings:   pdfResult.Warnings,
 	}
 
 	// Emit completed event
@@ -120,6 +212,9 @@ func FillFromCase(casePath, outputDir string, flatten bool) (*FillResult, error)
 		"output_path": result.OutputPath,
 		"input_pdf":   result.InputPDF,
 		"flattened":   result.Flattened,
+		"pdfa":        result.PDFA,
+		"protected":   result.Protected,
+		"warnings":    result.Warnings,
 	})
 
 	return result, nil
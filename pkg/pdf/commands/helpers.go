@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
 )
 
 // DetermineOutputPath determines the final output path for a file
@@ -53,3 +55,11 @@ func BaseNameWithoutExt(path string) string {
 	base := filepath.Base(path)
 	return base[:len(base)-len(filepath.Ext(base))]
 }
+
+// recordChecksums records written files in the data directory's integrity
+// manifest (see pdfform.RecordChecksums), warning instead of failing
+func recordChecksums(paths ...string) {
+	if err := pdfform.RecordChecksums(paths...); err != nil {
+		fmt.Printf("⚠️  Warning: Could not record checksums in the manifest: %v\n", err)
+	}
+}
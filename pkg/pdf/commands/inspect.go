@@ -12,6 +12,7 @@ import (
 type InspectOptions struct {
 	PDFPath   string
 	OutputDir string
+	Password  pdfform.Passwords // Unlocks an encrypted PDF (owner-only protection needs none)
 }
 
 // InspectResult contains the results of inspecting a PDF form
@@ -56,7 +57,7 @@ func Inspect(opts InspectOptions) (*InspectResult, error) {
 	}
 
 	// Extract form fields
-	fields, err := pdfform.ListFormFields(opts.PDFPath)
+	fields, err := pdfform.ListFormFieldsWithPasswords(opts.PDFPath, opts.Password)
 	if err != nil {
 		EmitError(EventInspectError, err, map[string]interface{}{
 			"pdf_path": opts.PDFPath,
@@ -66,7 +67,7 @@ func Inspect(opts InspectOptions) (*InspectResult, error) {
 	}
 
 	// Export to JSON template
-	if err := pdfform.ExportFormFieldsToJSON(opts.PDFPath, outputPath); err != nil {
+	if err := pdfform.ExportFormFieldsToJSONWithPasswords(opts.PDFPath, outputPath, opts.Password); err != nil {
 		EmitError(EventInspectError, err, map[string]interface{}{
 			"pdf_path": opts.PDFPath,
 			"stage":    "export_json",
@@ -74,6 +75,8 @@ func Inspect(opts InspectOptions) (*InspectResult, error) {
 		return nil, fmt.Errorf("failed to export form fields: %w", err)
 	}
 
+	recordChecksums(outputPath)
+
 	// Update provenance metadata with inspection timestamp
 	if err := pdfform.AddInspectedTimestamp(opts.PDFPath); err != nil {
 		// Ignore error if no metadata exists
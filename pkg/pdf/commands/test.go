@@ -0,0 +1,37 @@
+package commands
+
+import (
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+// Test runs a test case
+// Emits events: test.started, test.completed, test.error
+func Test(opts pdfform.TestOptions) (*pdfform.TestResult, error) {
+	name := BaseNameWithoutExt(opts.TestCasePath)
+
+	// Emit started event
+	Emit(EventTestStarted, map[string]interface{}{
+		"test_name": name,
+		"case_path": opts.TestCasePath,
+	})
+
+	result, err := pdfform.Test(opts)
+	if err != nil {
+		EmitError(EventTestError, err, map[string]interface{}{
+			"test_name": name,
+			"case_path": opts.TestCasePath,
+			"stage":     StageLoadCase,
+		})
+		return result, err
+	}
+
+	// Emit completed event (a failing case is a completed test, not an error)
+	Emit(EventTestCompleted, map[string]interface{}{
+		"test_name":   name,
+		"case_path":   opts.TestCasePath,
+		"success":     result.Passed,
+		"output_path": result.OutputPath,
+	})
+
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+// UploadOptions contains options for uploading a user-supplied PDF form
+type UploadOptions struct {
+	Filename     string
+	Reader       io.Reader
+	Storage      pdfform.Storage
+	TemplatesDir string
+	MaxSize      int64 // 0 = pdfform.MaxUploadSize
+}
+
+// Upload stores an uploaded fillable PDF and inspects it, ready for Fill
+// Emits events: upload.started, upload.completed, upload.error
+func Upload(opts UploadOptions) (*pdfform.UploadResult, error) {
+	Emit(EventUploadStarted, map[string]interface{}{
+		"filename": opts.Filename,
+	})
+
+	result, err := pdfform.UploadPDF(pdfform.UploadOptions{
+		Filename:     opts.Filename,
+		Reader:       opts.Reader,
+		Storage:      opts.Storage,
+		TemplatesDir: opts.TemplatesDir,
+		MaxSize:      opts.MaxSize,
+	})
+	if err != nil {
+		EmitError(EventUploadError, err, map[string]interface{}{
+			"filename": opts.Filename,
+		})
+		return nil, fmt.Errorf("upload failed: %w", err)
+	}
+
+	Emit(EventUploadCompleted, map[string]interface{}{
+		"filename":      opts.Filename,
+		"pdf_path":      result.PDFPath,
+		"template_path": result.TemplatePath,
+		"field_count":   result.FieldCount,
+	})
+
+	return result, nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 // Default configuration constants
@@ -16,6 +17,9 @@ const (
 	DefaultCasesDirName     = "cases"
 	DefaultTempDirName      = "temp"
 	DefaultCertsDirName     = "certs"
+	DefaultCacheDirName     = "cache"
+	DefaultUploadsDirName   = "uploads"
+	DefaultMappingsDirName  = "mappings"
 	DefaultCatalogFileName  = "australian_transfer_forms.csv"
 	DefaultCertFileName     = "cert.pem"
 	DefaultKeyFileName      = "key.pem"
@@ -38,6 +42,9 @@ type Config struct {
 	CasesDir     string // Case files
 	TempDir      string // Temporary files
 	CertsDir     string // HTTPS certificates
+	CacheDir     string // Download cache
+	UploadsDir   string // User-uploaded PDFs
+	MappingsDir  string // Case migration mapping profiles (JSON)
 
 	// File names
 	CatalogFile string // australian_transfer_forms.csv
@@ -46,6 +53,16 @@ type Config struct {
 
 	// System temp directory (for OS-level temp files)
 	SystemTempDir string
+
+	// Retention policies enforced by the janitor (zero policy = keep forever)
+	DownloadsRetention RetentionPolicy
+	OutputsRetention   RetentionPolicy
+	TempRetention      RetentionPolicy
+	JanitorInterval    time.Duration // How often the web server runs the janitor (0 = never)
+
+	// How downloads treat government sites: User-Agent, per-host spacing
+	// and retries (zero values = defaults, see DownloadClient)
+	Politeness PolitenessPolicy
 }
 
 var (
@@ -68,10 +85,16 @@ func DefaultConfig() *Config {
 		CasesDir:      DefaultCasesDirName,
 		TempDir:       DefaultTempDirName,
 		CertsDir:      DefaultCertsDirName,
+		CacheDir:      DefaultCacheDirName,
+		UploadsDir:    DefaultUploadsDirName,
+		MappingsDir:   DefaultMappingsDirName,
 		CatalogFile:   DefaultCatalogFileName,
 		CertFile:      DefaultCertFileName,
 		KeyFile:       DefaultKeyFileName,
 		SystemTempDir: os.TempDir(),
+
+		TempRetention:   RetentionPolicy{MaxAge: DefaultTempMaxAge},
+		JanitorInterval: DefaultJanitorInterval,
 	}
 }
 
@@ -129,6 +152,13 @@ func (c *Config) OutputsPath() string {
 	return filepath.Join(c.DataDir, c.OutputsDir)
 }
 
+// InDataDir reports whether path lies inside the data directory (symbolic
+// links are not resolved)
+func (c *Config) InDataDir(path string) bool {
+	_, ok := manifestKey(c, path)
+	return ok
+}
+
 // CasesPath returns the full path to the cases directory
 func (c *Config) CasesPath() string {
 	return filepath.Join(c.DataDir, c.CasesDir)
@@ -149,6 +179,21 @@ func (c *Config) CertsPath() string {
 	return filepath.Join(c.DataDir, c.CertsDir)
 }
 
+// CachePath returns the full path to the download cache directory
+func (c *Config) CachePath() string {
+	return filepath.Join(c.DataDir, c.CacheDir)
+}
+
+// UploadsPath returns the full path to the uploaded PDFs directory
+func (c *Config) UploadsPath() string {
+	return filepath.Join(c.DataDir, c.UploadsDir)
+}
+
+// MappingsPath returns the full path to the mapping profiles directory
+func (c *Config) MappingsPath() string {
+	return filepath.Join(c.DataDir, c.MappingsDir)
+}
+
 // CertFilePath returns the full path to the certificate file
 func (c *Config) CertFilePath() string {
 	return filepath.Join(c.DataDir, c.CertsDir, c.CertFile)
@@ -174,6 +219,9 @@ func (c *Config) EnsureDirectories() error {
 		c.CasesPath(),
 		c.TempPath(),
 		c.CertsPath(),
+		c.CachePath(),
+		c.UploadsPath(),
+		c.MappingsPath(),
 		c.TestScenariosPath(),
 	}
 
@@ -0,0 +1,253 @@
+package pdfform
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ================================================================
+// Email Delivery
+// ================================================================
+// Filled PDFs can be emailed as attachments. SMTP settings use the variable
+// names from the wellknown env registry (SMTP group), so the same .env /
+// Fly secrets configure PocketBase mail and PDF delivery.
+
+// Environment variables read by SMTPConfigFromEnv
+const (
+	EnvSMTPHost      = "SMTP_HOST"
+	EnvSMTPPort      = "SMTP_PORT"
+	EnvSMTPUsername  = "SMTP_USERNAME"
+	EnvSMTPPassword  = "SMTP_PASSWORD"
+	EnvSMTPFromEmail = "SMTP_FROM_EMAIL"
+	EnvSMTPFromName  = "SMTP_FROM_NAME"
+	EnvDeliveryTo    = "PDF_DELIVERY_TO" // Default recipient(s), comma-separated
+)
+
+// DefaultDeliverySubject is the subject template when none is given
+const DefaultDeliverySubject = "Completed form: {{.FileName}}"
+
+// DefaultDeliveryBody is the body template when none is given
+const DefaultDeliveryBody = `Hello,
+
+Please find attached the completed form {{.FileName}}{{if .FormCode}} ({{.FormCode}}){{end}}.
+
+Sent {{.SentAt.Format "2 Jan 2006 15:04 MST"}}
+`
+
+// SMTPConfig holds outgoing mail settings
+type SMTPConfig struct {
+	Host      string
+	Port      string // default: 587
+	Username  string // empty = no authentication
+	Password  string
+	FromEmail string
+	FromName  string
+}
+
+// SMTPConfigFromEnv reads SMTP settings from the environment
+func SMTPConfigFromEnv() SMTPConfig {
+	cfg := SMTPConfig{
+		Host:      os.Getenv(EnvSMTPHost),
+		Port:      os.Getenv(EnvSMTPPort),
+		Username:  os.Getenv(EnvSMTPUsername),
+		Password:  os.Getenv(EnvSMTPPassword),
+		FromEmail: os.Getenv(EnvSMTPFromEmail),
+		FromName:  os.Getenv(EnvSMTPFromName),
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	return cfg
+}
+
+// Validate checks that the settings needed to send are present
+func (c SMTPConfig) Validate() error {
+	var missing []string
+	if c.Host == "" {
+		missing = append(missing, EnvSMTPHost)
+	}
+	if c.FromEmail == "" {
+		missing = append(missing, EnvSMTPFromEmail)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("SMTP not configured: set %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// DefaultRecipients returns the recipients from PDF_DELIVERY_TO
+func DefaultRecipients() []string {
+	return splitAddresses(os.Getenv(EnvDeliveryTo))
+}
+
+// SendMailFunc sends a raw message (net/smtp.SendMail signature)
+type SendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// DeliveryOptions configures DeliverPDF
+type DeliveryOptions struct {
+	PDFPath  string       // PDF to attach
+	To       []string     // Recipients (default: PDF_DELIVERY_TO)
+	Subject  string       // Subject template (default: DefaultDeliverySubject)
+	Body     string       // Body template, text/template syntax (default: DefaultDeliveryBody)
+	FormCode string       // Available to templates as {{.FormCode}}
+	Data     interface{}  // Available to templates as {{.Data}}
+	SMTP     *SMTPConfig  // SMTP settings (default: SMTPConfigFromEnv)
+	Send     SendMailFunc // Transport (default: smtp.SendMail)
+}
+
+// DeliveryResult describes a sent message
+type DeliveryResult struct {
+	To      []string  `json:"to"`
+	Subject string    `json:"subject"`
+	Size    int       `json:"size"` // Message size in bytes
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// deliveryTemplateData is the data passed to subject and body templates
+type deliveryTemplateData struct {
+	FileName string
+	FormCode string
+	SentAt   time.Time
+	Data     interface{}
+}
+
+// DeliverPDF emails a filled PDF as an attachment
+//
+// Example:
+//
+//	result, err := pdfform.DeliverPDF(pdfform.DeliveryOptions{
+//	    PDFPath: "outputs/f3520_filled_flat.pdf",
+//	    To:      []string{"registrations@example.com"},
+//	})
+func DeliverPDF(opts DeliveryOptions) (*DeliveryResult, error) {
+	if opts.SMTP == nil {
+		cfg := SMTPConfigFromEnv()
+		opts.SMTP = &cfg
+	}
+	if err := opts.SMTP.Validate(); err != nil {
+		return nil, err
+	}
+	if len(opts.To) == 0 {
+		opts.To = DefaultRecipients()
+	}
+	if len(opts.To) == 0 {
+		return nil, fmt.Errorf("no recipients: pass To or set %s", EnvDeliveryTo)
+	}
+	for _, addr := range opts.To {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+	}
+	if opts.Send == nil {
+		opts.Send = smtp.SendMail
+	}
+
+	pdfData, err := os.ReadFile(opts.PDFPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	data := deliveryTemplateData{
+		FileName: filepath.Base(opts.PDFPath),
+		FormCode: opts.FormCode,
+		SentAt:   time.Now(),
+		Data:     opts.Data,
+	}
+	subject, err := renderDeliveryTemplate("subject", opts.Subject, DefaultDeliverySubject, data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderDeliveryTemplate("body", opts.Body, DefaultDeliveryBody, data)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := BuildDeliveryMessage(*opts.SMTP, opts.To, strings.TrimSpace(subject), body, data.FileName, pdfData)
+
+	var auth smtp.Auth
+	if opts.SMTP.Username != "" {
+		auth = smtp.PlainAuth("", opts.SMTP.Username, opts.SMTP.Password, opts.SMTP.Host)
+	}
+	addr := net.JoinHostPort(opts.SMTP.Host, opts.SMTP.Port)
+	if err := opts.Send(addr, auth, opts.SMTP.FromEmail, opts.To, msg); err != nil {
+		return nil, fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return &DeliveryResult{
+		To:      opts.To,
+		Subject: strings.TrimSpace(subject),
+		Size:    len(msg),
+		SentAt:  data.SentAt,
+	}, nil
+}
+
+// BuildDeliveryMessage builds a MIME message with a text body and a PDF attachment
+func BuildDeliveryMessage(cfg SMTPConfig, to []string, subject, body, fileName string, pdfData []byte) []byte {
+	from := (&mail.Address{Name: cfg.FromName, Address: cfg.FromEmail}).String()
+	boundary := fmt.Sprintf("pdfform-%d", time.Now().UnixNano())
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	buf.WriteString("\r\n")
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	fmt.Fprintf(&buf, "Content-Type: application/pdf; name=%q\r\n", fileName)
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	fmt.Fprintf(&buf, "Content-Disposition: attachment; filename=%q\r\n\r\n", fileName)
+	encoded := base64.StdEncoding.EncodeToString(pdfData)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes()
+}
+
+// renderDeliveryTemplate executes text (or fallback if empty) with data
+func renderDeliveryTemplate(name, text, fallback string, data deliveryTemplateData) (string, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// splitAddresses splits a comma-separated recipient list
+func splitAddresses(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package pdfform_test
+
+import (
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+func TestDeliverPDF(t *testing.T) {
+	pdfPath := filepath.Join(t.TempDir(), "f3520_filled.pdf")
+	if err := os.WriteFile(pdfPath, []byte("%PDF-1.4 filled"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	send := func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		return nil
+	}
+
+	result, err := pdfform.DeliverPDF(pdfform.DeliveryOptions{
+		PDFPath:  pdfPath,
+		To:       []string{"clerk@example.com"},
+		Body:     "Form {{.FormCode}} attached for {{.Data}}.",
+		FormCode: "F3520",
+		Data:     "Jane Doe",
+		SMTP:     &pdfform.SMTPConfig{Host: "smtp.example.com", Port: "2525", FromEmail: "forms@example.com", FromName: "Forms"},
+		Send:     send,
+	})
+	if err != nil {
+		t.Fatalf("DeliverPDF failed: %v", err)
+	}
+
+	if gotAddr != "smtp.example.com:2525" || gotFrom != "forms@example.com" || len(gotTo) != 1 {
+		t.Errorf("unexpected envelope: %s %s %v", gotAddr, gotFrom, gotTo)
+	}
+	if result.Subject != "Completed form: f3520_filled.pdf" {
+		t.Errorf("subject = %q", result.Subject)
+	}
+	msg := string(gotMsg)
+	for _, want := range []string{
+		"Form F3520 attached for Jane Doe.",
+		`Content-Disposition: attachment; filename="f3520_filled.pdf"`,
+		"JVBERi0xLjQgZmlsbGVk", // base64 of the PDF
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q", want)
+		}
+	}
+}
+
+func TestDeliverPDF_RequiresConfig(t *testing.T) {
+	_, err := pdfform.DeliverPDF(pdfform.DeliveryOptions{
+		PDFPath: "x.pdf",
+		To:      []string{"clerk@example.com"},
+		SMTP:    &pdfform.SMTPConfig{},
+	})
+	if err == nil || !strings.Contains(err.Error(), pdfform.EnvSMTPHost) {
+		t.Errorf("expected SMTP config error, got %v", err)
+	}
+
+	_, err = pdfform.DeliverPDF(pdfform.DeliveryOptions{
+		PDFPath: "x.pdf",
+		To:      []string{"not an address"},
+		SMTP:    &pdfform.SMTPConfig{Host: "h", FromEmail: "f@example.com"},
+	})
+	if err == nil {
+		t.Error("expected invalid recipient error")
+	}
+}
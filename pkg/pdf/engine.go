@@ -0,0 +1,387 @@
+package pdfform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+
+	bmodel "github.com/benoitkugler/pdf/model"
+	"github.com/benoitkugler/pdf/reader"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/form"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// ================================================================
+// Engine Abstraction
+// ================================================================
+// Different PDF libraries succeed on different state forms. Each backend
+// implements Engine; a form picks one by name (FormData.Engine) or uses
+// EngineAuto, which tries pdfcpu first and falls back to benoitkugler.
+
+// Engine names
+const (
+	EngineAuto         = "auto"         // pdfcpu, falling back to benoitkugler (default)
+	EnginePdfcpu       = "pdfcpu"       // github.com/pdfcpu/pdfcpu
+	EngineBenoitkugler = "benoitkugler" // github.com/benoitkugler/pdf (handles some signed PDFs)
+	EnginePdftk        = "pdftk"        // pdftk CLI (external binary, very tolerant of odd AcroForms)
+)
+
+// FormField is an engine-neutral description of a PDF form field
+type FormField struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`            // Text, CheckBox, RadioButtonGroup, ComboBox, ListBox, ...
+	Value string `json:"value,omitempty"` // Current value
+}
+
+// Engine fills and inspects PDF forms
+type Engine interface {
+	Name() string
+	Fill(inputPDF string, fields map[string]string, outputPDF string) error
+	Inspect(inputPDF string) ([]FormField, error)
+}
+
+var (
+	enginesMu sync.RWMutex
+	engines   = map[string]Engine{}
+)
+
+func init() {
+	RegisterEngine(PdfcpuEngine{})
+	RegisterEngine(BenoitkuglerEngine{})
+	RegisterEngine(PdftkEngine{})
+	RegisterEngine(AutoEngine{})
+}
+
+// RegisterEngine makes an engine selectable by name (replacing any engine with the same name)
+func RegisterEngine(e Engine) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+	engines[e.Name()] = e
+}
+
+// GetEngine returns the engine with the given name ("" selects EngineAuto)
+func GetEngine(name string) (Engine, error) {
+	if name == "" {
+		name = EngineAuto
+	}
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	e, ok := engines[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown PDF engine %q (available: %s)", name, strings.Join(engineNamesLocked(), ", "))
+	}
+	return e, nil
+}
+
+// EngineNames returns the registered engine names, sorted
+func EngineNames() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	return engineNamesLocked()
+}
+
+func engineNamesLocked() []string {
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ================================================================
+// pdfcpu
+// ================================================================
+
+// PdfcpuEngine uses the pdfcpu library
+type PdfcpuEngine struct{}
+
+// Name returns EnginePdfcpu
+func (PdfcpuEngine) Name() string { return EnginePdfcpu }
+
+// Fill exports the form in pdfcpu's own JSON layout, sets the values by field
+// name (or ID), and fills from that, so field types are preserved
+func (PdfcpuEngine) Fill(inputPDF string, fields map[string]string, outputPDF string) error {
+	conf := model.NewDefaultConfiguration()
+
+	in, err := os.Open(inputPDF)
+	if err != nil {
+		return fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer in.Close()
+
+	group, err := api.ExportForm(in, inputPDF, conf)
+	if err != nil {
+		return fmt.Errorf("failed to export form: %w", err)
+	}
+	for i := range group.Forms {
+		setPdfcpuValues(&group.Forms[i], fields)
+	}
+
+	data, err := json.Marshal(group)
+	if err != nil {
+		return fmt.Errorf("failed to encode form JSON: %w", err)
+	}
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind PDF: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := api.FillForm(in, bytes.NewReader(data), &buf, conf); err != nil {
+		return fmt.Errorf("failed to fill PDF: %w", err)
+	}
+	return os.WriteFile(outputPDF, buf.Bytes(), 0644)
+}
+
+// setPdfcpuValues applies fields (keyed by name or ID) to an exported pdfcpu form
+func setPdfcpuValues(f *form.Form, fields map[string]string) {
+	lookup := func(id, name string) (string, bool) {
+		if v, ok := fields[name]; ok && name != "" {
+			return v, true
+		}
+		v, ok := fields[id]
+		return v, ok
+	}
+	for _, tf := range f.TextFields {
+		if v, ok := lookup(tf.ID, tf.Name); ok {
+			tf.Value = v
+		}
+	}
+	for _, df := range f.DateFields {
+		if v, ok := lookup(df.ID, df.Name); ok {
+			df.Value = v
+		}
+	}
+	for _, cb := range f.CheckBoxes {
+		if v, ok := lookup(cb.ID, cb.Name); ok {
+			cb.Value = isChecked(v)
+		}
+	}
+	for _, rb := range f.RadioButtonGroups {
+		if v, ok := lookup(rb.ID, rb.Name); ok {
+			rb.Value = v
+		}
+	}
+	for _, cb := range f.ComboBoxes {
+		if v, ok := lookup(cb.ID, cb.Name); ok {
+			cb.Value = v
+		}
+	}
+	for _, lb := range f.ListBoxes {
+		if v, ok := lookup(lb.ID, lb.Name); ok {
+			lb.Values = strings.Split(v, ",")
+		}
+	}
+}
+
+// isChecked interprets common checkbox values ("true", "Yes", "On", "X", "1")
+func isChecked(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "true", "yes", "on", "x", "1", "checked":
+		return true
+	}
+	return false
+}
+
+// Inspect lists form fields via pdfcpu
+func (PdfcpuEngine) Inspect(inputPDF string) ([]FormField, error) {
+	fields, err := ListFormFields(inputPDF)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FormField, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, FormField{Name: f.Name, Type: f.Typ.String(), Value: f.V})
+	}
+	return out, nil
+}
+
+// ================================================================
+// benoitkugler/pdf
+// ================================================================
+
+// BenoitkuglerEngine uses the benoitkugler/pdf library
+type BenoitkuglerEngine struct{}
+
+// Name returns EngineBenoitkugler
+func (BenoitkuglerEngine) Name() string { return EngineBenoitkugler }
+
+// Fill fills the form via FDF merging
+func (BenoitkuglerEngine) Fill(inputPDF string, fields map[string]string, outputPDF string) error {
+	return FillPDFBenoitkugler(inputPDF, fields, outputPDF)
+}
+
+// Inspect lists the fully qualified AcroForm fields
+func (BenoitkuglerEngine) Inspect(inputPDF string) ([]FormField, error) {
+	f, err := os.Open(inputPDF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer f.Close()
+
+	doc, _, err := reader.ParsePDFReader(f, reader.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+
+	var out []FormField
+	for name, field := range doc.Catalog.AcroForm.Flatten() {
+		ff := FormField{Name: name}
+		switch ft := field.Merged.FT.(type) {
+		case bmodel.FormFieldText:
+			ff.Type, ff.Value = "Text", ft.V
+		case bmodel.FormFieldButton:
+			ff.Type, ff.Value = "Button", string(ft.V)
+		case bmodel.FormFieldChoice:
+			ff.Type, ff.Value = "Choice", strings.Join(ft.V, ",")
+		case bmodel.FormFieldSignature:
+			ff.Type = "Signature"
+		default:
+			ff.Type = "Unknown"
+		}
+		out = append(out, ff)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// ================================================================
+// pdftk (CLI)
+// ================================================================
+
+// PdftkEngine shells out to the pdftk binary (PDFTK_BIN overrides the path)
+type PdftkEngine struct{}
+
+// Name returns EnginePdftk
+func (PdftkEngine) Name() string { return EnginePdftk }
+
+// PdftkAvailable reports whether the pdftk binary can be found
+func PdftkAvailable() bool {
+	_, err := exec.LookPath(pdftkBin())
+	return err == nil
+}
+
+func pdftkBin() string {
+	if bin := os.Getenv("PDFTK_BIN"); bin != "" {
+		return bin
+	}
+	return "pdftk"
+}
+
+// xfdf is the minimal XFDF document pdftk accepts for fill_form
+type xfdf struct {
+	XMLName xml.Name    `xml:"xfdf"`
+	NS      string      `xml:"xmlns,attr"`
+	Fields  []xfdfField `xml:"fields>field"`
+}
+
+type xfdfField struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+// Fill writes the fields as XFDF (UTF-8 safe) and runs pdftk fill_form
+func (PdftkEngine) Fill(inputPDF string, fields map[string]string, outputPDF string) error {
+	doc := xfdf{NS: "http://ns.adobe.com/xfdf/"}
+	for name, value := range fields {
+		doc.Fields = append(doc.Fields, xfdfField{Name: name, Value: value})
+	}
+	sort.Slice(doc.Fields, func(i, j int) bool { return doc.Fields[i].Name < doc.Fields[j].Name })
+
+	data, err := xml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode XFDF: %w", err)
+	}
+
+	tempXFDF, err := os.CreateTemp("", "form_fields_*.xfdf")
+	if err != nil {
+		return fmt.Errorf("failed to create temp XFDF: %w", err)
+	}
+	defer os.Remove(tempXFDF.Name())
+
+	_, err = tempXFDF.Write(append([]byte(xml.Header), data...))
+	tempXFDF.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write XFDF: %w", err)
+	}
+
+	cmd := exec.Command(pdftkBin(), inputPDF, "fill_form", tempXFDF.Name(), "output", outputPDF)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("pdftk fill_form failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Inspect parses `pdftk dump_data_fields_utf8`
+func (PdftkEngine) Inspect(inputPDF string) ([]FormField, error) {
+	output, err := exec.Command(pdftkBin(), inputPDF, "dump_data_fields_utf8").Output()
+	if err != nil {
+		return nil, fmt.Errorf("pdftk dump_data_fields failed: %w", err)
+	}
+	return parsePdftkFields(output), nil
+}
+
+// parsePdftkFields parses "---" separated "Key: value" blocks
+func parsePdftkFields(output []byte) []FormField {
+	var fields []FormField
+	var current *FormField
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "---" {
+			current = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		if current == nil {
+			fields = append(fields, FormField{})
+			current = &fields[len(fields)-1]
+		}
+		switch key {
+		case "FieldType":
+			current.Type = value
+		case "FieldName":
+			current.Name = value
+		case "FieldValue":
+			current.Value = value
+		}
+	}
+	return fields
+}
+
+// ================================================================
+// Auto (pdfcpu with fallback)
+// ================================================================
+
+// AutoEngine tries pdfcpu first and falls back to benoitkugler (the historical default)
+type AutoEngine struct{}
+
+// Name returns EngineAuto
+func (AutoEngine) Name() string { return EngineAuto }
+
+// Fill delegates to FillPDFWithFallback
+func (AutoEngine) Fill(inputPDF string, fields map[string]string, outputPDF string) error {
+	return FillPDFWithFallback(inputPDF, fields, outputPDF)
+}
+
+// Inspect uses pdfcpu, falling back to benoitkugler
+func (AutoEngine) Inspect(inputPDF string) ([]FormField, error) {
+	fields, err := PdfcpuEngine{}.Inspect(inputPDF)
+	if err == nil {
+		return fields, nil
+	}
+	return BenoitkuglerEngine{}.Inspect(inputPDF)
+}
@@ -0,0 +1,96 @@
+package pdfform_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+// conformanceFormJSON describes a one-page form with two text fields (pdfcpu create syntax)
+const conformanceFormJSON = `{
+	"paper": "A4P",
+	"origin": "LowerLeft",
+	"fonts": {"input": {"name": "Helvetica", "size": 12}},
+	"pages": {
+		"1": {
+			"content": {
+				"textfield": [
+					{"id": "first_name", "value": "", "pos": [100, 700], "width": 200, "font": {"name": "$input"}},
+					{"id": "last_name", "value": "", "pos": [100, 650], "width": 200, "font": {"name": "$input"}}
+				]
+			}
+		}
+	}
+}`
+
+// createConformanceForm writes a fillable PDF generated by pdfcpu
+func createConformanceForm(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "form.json")
+	pdfPath := filepath.Join(dir, "form.pdf")
+	if err := os.WriteFile(jsonPath, []byte(conformanceFormJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.CreateFile("", jsonPath, pdfPath, model.NewDefaultConfiguration()); err != nil {
+		t.Skipf("Skipping: cannot generate test form: %v", err)
+	}
+	return pdfPath
+}
+
+func TestGetEngine(t *testing.T) {
+	for _, name := range []string{"", pdfform.EngineAuto, pdfform.EnginePdfcpu, pdfform.EngineBenoitkugler, pdfform.EnginePdftk} {
+		if _, err := pdfform.GetEngine(name); err != nil {
+			t.Errorf("GetEngine(%q) failed: %v", name, err)
+		}
+	}
+	if _, err := pdfform.GetEngine("unipdf"); err == nil {
+		t.Error("expected error for unknown engine")
+	}
+}
+
+// TestEngineConformance fills the same form with every backend and checks that
+// all inspectors agree on the field names and filled values.
+func TestEngineConformance(t *testing.T) {
+	input := createConformanceForm(t)
+	fields := map[string]string{"first_name": "Jane", "last_name": "Doe"}
+
+	for _, name := range pdfform.EngineNames() {
+		t.Run(name, func(t *testing.T) {
+			if name == pdfform.EnginePdftk && !pdfform.PdftkAvailable() {
+				t.Skip("pdftk not installed")
+			}
+			engine, _ := pdfform.GetEngine(name)
+
+			inspected, err := engine.Inspect(input)
+			if err != nil {
+				t.Fatalf("Inspect failed: %v", err)
+			}
+			if len(inspected) != 2 {
+				t.Errorf("Inspect found %d fields, want 2: %+v", len(inspected), inspected)
+			}
+
+			output := filepath.Join(t.TempDir(), "filled.pdf")
+			if err := engine.Fill(input, fields, output); err != nil {
+				t.Fatalf("Fill failed: %v", err)
+			}
+
+			// Cross-check with an independent inspector
+			filled, err := pdfform.BenoitkuglerEngine{}.Inspect(output)
+			if err != nil {
+				t.Fatalf("Inspect of filled PDF failed: %v", err)
+			}
+			for _, f := range filled {
+				if want, ok := fields[f.Name]; ok && strings.TrimSpace(f.Value) != want {
+					t.Errorf("field %s = %q, want %q", f.Name, f.Value, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package pdfform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TemplateSuffix is appended to the PDF name for inspected templates
+const TemplateSuffix = "_template.json"
+
+// TemplateField describes one PDF field in an enriched template.
+// The order of FormData.FieldDefs is the display order.
+type TemplateField struct {
+	PDFName  string `json:"pdf_name"`           // Field name inside the PDF
+	Key      string `json:"key"`                // Logical key used in data files and the JSON schema
+	Label    string `json:"label,omitempty"`    // Human-readable label
+	Type     string `json:"type,omitempty"`     // Field type from inspection (Text, CheckBox, ...)
+	Required bool   `json:"required,omitempty"` // Fill fails if no value is supplied
+	Default  string `json:"default,omitempty"`  // Value used when none is supplied
+}
+
+var templateIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// TemplateID returns the editor ID for a template path ("f3520_template.json" -> "f3520")
+func TemplateID(templatePath string) string {
+	return strings.TrimSuffix(filepath.Base(templatePath), TemplateSuffix)
+}
+
+// TemplatePathForID returns the template file for id inside templatesDir
+func TemplatePathForID(templatesDir, id string) (string, error) {
+	if !templateIDPattern.MatchString(id) || strings.Contains(id, "..") {
+		return "", fmt.Errorf("invalid template id: %q", id)
+	}
+	return filepath.Join(templatesDir, id+TemplateSuffix), nil
+}
+
+// ListTemplateIDs returns the IDs of all templates in templatesDir, sorted
+func ListTemplateIDs(templatesDir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(templatesDir, "*"+TemplateSuffix))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, TemplateID(m))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// LoadTemplate reads a template JSON file
+func LoadTemplate(path string) (*FormData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	var fd FormData
+	if err := json.Unmarshal(data, &fd); err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	if fd.Fields == nil {
+		fd.Fields = make(map[string]string)
+	}
+	return &fd, nil
+}
+
+// SaveTemplate writes a template JSON file
+func SaveTemplate(path string, fd *FormData) error {
+	data, err := json.MarshalIndent(fd, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	return nil
+}
+
+// EnsureFieldDefs creates FieldDefs from Fields (sorted, key = PDF name) for
+// templates that haven't been edited yet. Existing definitions are kept.
+func (fd *FormData) EnsureFieldDefs() {
+	if len(fd.FieldDefs) > 0 {
+		return
+	}
+	names := make([]string, 0, len(fd.Fields))
+	for name := range fd.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fd.FieldDefs = append(fd.FieldDefs, TemplateField{PDFName: name, Key: name})
+	}
+}
+
+// FillFieldTypes sets missing FieldDefs types by inspecting pdfPath
+func (fd *FormData) FillFieldTypes(pdfPath string) error {
+	engine, err := GetEngine(fd.Engine)
+	if err != nil {
+		return err
+	}
+	fields, err := engine.Inspect(pdfPath)
+	if err != nil {
+		return err
+	}
+	types := make(map[string]string, len(fields))
+	for _, f := range fields {
+		types[f.Name] = f.Type
+	}
+	for i := range fd.FieldDefs {
+		if fd.FieldDefs[i].Type == "" {
+			fd.FieldDefs[i].Type = types[fd.FieldDefs[i].PDFName]
+		}
+	}
+	return nil
+}
+
+// ValidateFieldDefs checks that logical keys are present and unique
+func (fd *FormData) ValidateFieldDefs() error {
+	seen := make(map[string]string, len(fd.FieldDefs))
+	for _, def := range fd.FieldDefs {
+		if def.Key == "" {
+			return fmt.Errorf("field %q has no key", def.PDFName)
+		}
+		if other, ok := seen[def.Key]; ok {
+			return fmt.Errorf("key %q is used by both %q and %q", def.Key, other, def.PDFName)
+		}
+		seen[def.Key] = def.PDFName
+	}
+	return nil
+}
+
+// ResolveFields maps Fields to PDF field names for filling. Values may be
+// keyed by logical key or PDF name; defaults fill gaps, and missing required
+// fields are an error. Without FieldDefs, Fields is returned unchanged.
+func (fd *FormData) ResolveFields() (map[string]string, error) {
+	if len(fd.FieldDefs) == 0 {
+		return fd.Fields, nil
+	}
+
+	resolved := make(map[string]string, len(fd.Fields))
+	consumed := make(map[string]bool)
+	var missing []string
+	for _, def := range fd.FieldDefs {
+		value, ok := fd.Fields[def.Key]
+		if ok {
+			consumed[def.Key] = true
+		} else if value, ok = fd.Fields[def.PDFName]; ok {
+			consumed[def.PDFName] = true
+		}
+		if value == "" {
+			value = def.Default
+		}
+		if value == "" && def.Required {
+			missing = append(missing, def.Key)
+		}
+		resolved[def.PDFName] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+
+	// Pass through anything not described by the definitions
+	for name, value := range fd.Fields {
+		if !consumed[name] {
+			if _, defined := resolved[name]; !defined {
+				resolved[name] = value
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the template's
+// logical fields, for the form/schema tooling. Each property carries its PDF
+// field name in "x-pdf-field"; "x-order" lists the keys in display order.
+func (fd *FormData) JSONSchema(title string) map[string]interface{} {
+	defs := fd.FieldDefs
+	if len(defs) == 0 {
+		tmp := &FormData{Fields: fd.Fields}
+		tmp.EnsureFieldDefs()
+		defs = tmp.FieldDefs
+	}
+
+	properties := make(map[string]interface{}, len(defs))
+	required := []string{}
+	order := make([]string, 0, len(defs))
+	for _, def := range defs {
+		prop := map[string]interface{}{
+			"type":        "string",
+			"x-pdf-field": def.PDFName,
+		}
+		if strings.EqualFold(def.Type, "CheckBox") || strings.EqualFold(def.Type, "Button") {
+			prop["type"] = "boolean"
+		}
+		if def.Label != "" {
+			prop["title"] = def.Label
+		}
+		if def.Default != "" {
+			prop["default"] = def.Default
+		}
+		properties[def.Key] = prop
+		order = append(order, def.Key)
+		if def.Required {
+			required = append(required, def.Key)
+		}
+	}
+
+	return map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      title,
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+		"x-order":    order,
+	}
+}
@@ -0,0 +1,78 @@
+package pdfform_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+func TestResolveFields(t *testing.T) {
+	fd := &pdfform.FormData{
+		Fields: map[string]string{
+			"first_name": "Jane", // logical key
+			"Text2":      "Doe",  // PDF name
+			"Extra":      "kept", // not described
+		},
+		FieldDefs: []pdfform.TemplateField{
+			{PDFName: "Text1", Key: "first_name", Required: true},
+			{PDFName: "Text2", Key: "last_name"},
+			{PDFName: "Text3", Key: "country", Default: "Australia"},
+		},
+	}
+
+	got, err := fd.ResolveFields()
+	if err != nil {
+		t.Fatalf("ResolveFields failed: %v", err)
+	}
+	want := map[string]string{"Text1": "Jane", "Text2": "Doe", "Text3": "Australia", "Extra": "kept"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveFields = %v, want %v", got, want)
+	}
+
+	delete(fd.Fields, "first_name")
+	if _, err := fd.ResolveFields(); err == nil || !strings.Contains(err.Error(), "first_name") {
+		t.Errorf("expected missing required error, got %v", err)
+	}
+}
+
+func TestTemplateRoundTripAndSchema(t *testing.T) {
+	dir := t.TempDir()
+	path, err := pdfform.TemplatePathForID(dir, "f3520")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pdfform.TemplatePathForID(dir, "../etc"); err == nil {
+		t.Error("expected error for path traversal id")
+	}
+
+	fd := &pdfform.FormData{Fields: map[string]string{"B": "", "A": ""}}
+	fd.EnsureFieldDefs()
+	fd.FieldDefs[0].Key, fd.FieldDefs[0].Required = "alpha", true
+	if err := pdfform.SaveTemplate(path, fd); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := pdfform.LoadTemplate(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids, _ := pdfform.ListTemplateIDs(dir); len(ids) != 1 || ids[0] != "f3520" || pdfform.TemplateID(filepath.Base(path)) != "f3520" {
+		t.Errorf("unexpected template ids: %v", ids)
+	}
+
+	schema := loaded.JSONSchema("f3520")
+	if !reflect.DeepEqual(schema["required"], []string{"alpha"}) {
+		t.Errorf("required = %v", schema["required"])
+	}
+	if !reflect.DeepEqual(schema["x-order"], []string{"alpha", "B"}) {
+		t.Errorf("x-order = %v", schema["x-order"])
+	}
+
+	loaded.FieldDefs[1].Key = "alpha"
+	if err := loaded.ValidateFieldDefs(); err == nil {
+		t.Error("expected duplicate key error")
+	}
+}
@@ -2,11 +2,13 @@ package pdfform
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // TransferForm represents a government transfer form
@@ -20,6 +22,29 @@ type TransferForm struct {
 	InfoURL         string
 	OnlineAvailable bool
 	Notes           string
+	MirrorURLs      []string // Alternate sources tried when DirectPDFURL fails (optional 10th column)
+}
+
+// SourceURLs returns the form's PDF sources in failover order: the direct URL,
+// then its mirrors
+func (f *TransferForm) SourceURLs() []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, u := range append([]string{f.DirectPDFURL}, f.MirrorURLs...) {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// parseMirrorURLs splits the mirror column, which lists URLs separated by
+// "|" or whitespace
+func parseMirrorURLs(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == '|' || unicode.IsSpace(r)
+	})
 }
 
 // FormsCatalog holds a collection of transfer forms
@@ -36,6 +61,7 @@ func LoadFormsCatalog(csvPath string) (*FormsCatalog, error) {
 	defer f.Close()
 
 	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1 // The mirror column is optional
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CSV: %w", err)
@@ -68,6 +94,9 @@ func LoadFormsCatalog(csvPath string) (*FormsCatalog, error) {
 			OnlineAvailable: onlineAvailable,
 			Notes:           strings.TrimSpace(record[8]),
 		}
+		if len(record) > 9 {
+			form.MirrorURLs = parseMirrorURLs(record[9])
+		}
 
 		catalog.Forms = append(catalog.Forms, form)
 	}
@@ -123,9 +152,11 @@ func (c *FormsCatalog) GetPDFForms() []TransferForm {
 	return pdfForms
 }
 
-// DownloadFormPDF downloads a form PDF to the specified directory
+// DownloadFormPDF downloads a form PDF to the specified directory, failing
+// over to the form's mirrors when the direct URL cannot be downloaded
 func (c *FormsCatalog) DownloadFormPDF(form *TransferForm, outputDir string) (string, error) {
-	if form.DirectPDFURL == "" {
+	sources := form.SourceURLs()
+	if len(sources) == 0 {
 		return "", fmt.Errorf("form has no direct PDF URL")
 	}
 
@@ -137,9 +168,29 @@ func (c *FormsCatalog) DownloadFormPDF(form *TransferForm, outputDir string) (st
 	filename = strings.ToLower(filename) + ".pdf"
 
 	outputPath := filepath.Join(outputDir, filename)
-	if err := DownloadPDF(form.DirectPDFURL, outputPath); err != nil {
+	err := trySources(sources, func(u string) error {
+		return DownloadPDF(u, outputPath)
+	})
+	if err != nil {
 		return "", err
 	}
 
 	return outputPath, nil
 }
+
+// trySources calls fetch with each source URL until one succeeds, warning
+// when a mirror had to be used. The error lists every failed source.
+func trySources(sources []string, fetch func(u string) error) error {
+	var errs []error
+	for _, u := range sources {
+		err := fetch(u)
+		if err == nil {
+			if len(errs) > 0 {
+				fmt.Printf("⚠️  Warning: %s failed, downloaded from mirror %s\n", sources[0], u)
+			}
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", u, err))
+	}
+	return errors.Join(errs...)
+}
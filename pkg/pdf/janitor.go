@@ -0,0 +1,243 @@
+package pdfform
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ================================================================
+// Retention Janitor
+// ================================================================
+// Downloads, outputs and temp files accumulate forever unless cleaned up.
+// The janitor enforces a RetentionPolicy per directory: files older than
+// MaxAge are removed, then the oldest files are removed until the directory
+// is under MaxSize. Templates, cases, uploads and certs are never touched.
+
+// Janitor defaults
+const (
+	DefaultTempMaxAge      = 24 * time.Hour
+	DefaultJanitorInterval = time.Hour
+)
+
+// Removal reasons reported by the janitor
+const (
+	RetentionReasonAge  = "age"
+	RetentionReasonSize = "size"
+)
+
+// RetentionPolicy limits how much a directory keeps. Zero values mean no limit.
+type RetentionPolicy struct {
+	MaxAge  time.Duration // Remove files not modified within this duration
+	MaxSize int64         // Remove oldest files until the directory is at most this many bytes
+}
+
+// Enabled reports whether the policy limits anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxSize > 0
+}
+
+// RetentionTarget is a directory with its policy
+type RetentionTarget struct {
+	Name   string
+	Path   string
+	Policy RetentionPolicy
+}
+
+// RetentionTargets returns the directories the janitor manages
+func (c *Config) RetentionTargets() []RetentionTarget {
+	return []RetentionTarget{
+		{Name: "downloads", Path: c.DownloadsPath(), Policy: c.DownloadsRetention},
+		{Name: "outputs", Path: c.OutputsPath(), Policy: c.OutputsRetention},
+		{Name: "temp", Path: c.TempPath(), Policy: c.TempRetention},
+	}
+}
+
+// RemovedFile is a file the janitor removed (or would remove, in a dry run)
+type RemovedFile struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Reason  string    `json:"reason"` // RetentionReasonAge or RetentionReasonSize
+}
+
+// DirRetentionReport describes one directory's cleanup
+type DirRetentionReport struct {
+	Name       string          `json:"name"`
+	Path       string          `json:"path"`
+	Policy     RetentionPolicy `json:"policy"`
+	Files      int             `json:"files"`       // Files found
+	TotalBytes int64           `json:"total_bytes"` // Bytes before cleanup
+	Removed    []RemovedFile   `json:"removed"`
+	FreedBytes int64           `json:"freed_bytes"`
+}
+
+// JanitorReport summarises a janitor run
+type JanitorReport struct {
+	DryRun     bool                 `json:"dry_run"`
+	StartedAt  time.Time            `json:"started_at"`
+	Dirs       []DirRetentionReport `json:"dirs"`
+	Removed    int                  `json:"removed"`
+	FreedBytes int64                `json:"freed_bytes"`
+}
+
+// RunJanitor enforces every configured retention policy.
+// With dryRun, nothing is deleted and the report lists what would be.
+func RunJanitor(cfg *Config, dryRun bool) (*JanitorReport, error) {
+	report := &JanitorReport{DryRun: dryRun, StartedAt: time.Now()}
+	for _, target := range cfg.RetentionTargets() {
+		if !target.Policy.Enabled() {
+			continue
+		}
+		dir, err := EnforceRetention(target, dryRun)
+		if !dryRun && dir != nil && len(dir.Removed) > 0 {
+			// Removed on purpose: not a missing file for VerifyManifest
+			removed := make([]string, len(dir.Removed))
+			for i, f := range dir.Removed {
+				removed[i] = f.Path
+			}
+			if ferr := ForgetFiles(cfg, removed...); ferr != nil && err == nil {
+				err = ferr
+			}
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to clean %s: %w", target.Name, err)
+		}
+		report.Dirs = append(report.Dirs, *dir)
+		report.Removed += len(dir.Removed)
+		report.FreedBytes += dir.FreedBytes
+	}
+	return report, nil
+}
+
+// EnforceRetention applies target.Policy to the files under target.Path
+func EnforceRetention(target RetentionTarget, dryRun bool) (*DirRetentionReport, error) {
+	report := &DirRetentionReport{Name: target.Name, Path: target.Path, Policy: target.Policy}
+
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	err := filepath.WalkDir(target.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == target.Path {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, file{path, info.Size(), info.ModTime()})
+		report.TotalBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Files = len(files)
+
+	// Oldest first
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	remaining := report.TotalBytes
+	cutoff := time.Now().Add(-target.Policy.MaxAge)
+	for _, f := range files {
+		reason := ""
+		switch {
+		case target.Policy.MaxAge > 0 && f.modTime.Before(cutoff):
+			reason = RetentionReasonAge
+		case target.Policy.MaxSize > 0 && remaining > target.Policy.MaxSize:
+			reason = RetentionReasonSize
+		default:
+			continue
+		}
+
+		if !dryRun {
+			if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+				return report, err
+			}
+		}
+		report.Removed = append(report.Removed, RemovedFile{Path: f.path, Size: f.size, ModTime: f.modTime, Reason: reason})
+		report.FreedBytes += f.size
+		remaining -= f.size
+	}
+
+	if !dryRun {
+		removeEmptyDirs(target.Path)
+	}
+	return report, nil
+}
+
+// removeEmptyDirs removes empty subdirectories of root (deepest first), keeping root
+func removeEmptyDirs(root string) {
+	var dirs []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && path != root {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i]) // fails harmlessly if not empty
+	}
+}
+
+// StartJanitor runs the janitor every cfg.JanitorInterval until ctx is done.
+// It does nothing if the interval is zero or no policy is enabled.
+func StartJanitor(ctx context.Context, cfg *Config) {
+	if cfg.JanitorInterval <= 0 {
+		return
+	}
+	enabled := false
+	for _, t := range cfg.RetentionTargets() {
+		enabled = enabled || t.Policy.Enabled()
+	}
+	if !enabled {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(cfg.JanitorInterval)
+		defer ticker.Stop()
+		for {
+			report, err := RunJanitor(cfg, false)
+			if err != nil {
+				log.Printf("⚠️  Janitor: %v", err)
+			} else if report.Removed > 0 {
+				log.Printf("🧹 Janitor removed %d file(s), freed %s", report.Removed, FormatBytes(report.FreedBytes))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// FormatBytes formats a byte count for display (e.g., "1.5 MB")
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,100 @@
+package pdfform_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+// writeAged writes a file of size bytes with a modification time age ago
+func writeAged(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnforceRetention_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	writeAged(t, filepath.Join(dir, "old.pdf"), 10, 48*time.Hour)
+	writeAged(t, filepath.Join(dir, "nested", "old.pdf"), 10, 48*time.Hour)
+	writeAged(t, filepath.Join(dir, "new.pdf"), 10, time.Minute)
+
+	target := pdfform.RetentionTarget{Name: "outputs", Path: dir, Policy: pdfform.RetentionPolicy{MaxAge: 24 * time.Hour}}
+
+	// Dry run reports but keeps files
+	report, err := pdfform.EnforceRetention(target, true)
+	if err != nil {
+		t.Fatalf("EnforceRetention: %v", err)
+	}
+	if len(report.Removed) != 2 || report.FreedBytes != 20 {
+		t.Fatalf("dry run removed %d files (%d bytes), want 2 (20 bytes)", len(report.Removed), report.FreedBytes)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old.pdf")); err != nil {
+		t.Fatalf("dry run deleted a file: %v", err)
+	}
+
+	if _, err := pdfform.EnforceRetention(target, false); err != nil {
+		t.Fatalf("EnforceRetention: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old.pdf")); !os.IsNotExist(err) {
+		t.Error("old.pdf should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "nested")); !os.IsNotExist(err) {
+		t.Error("empty nested directory should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.pdf")); err != nil {
+		t.Errorf("new.pdf should be kept: %v", err)
+	}
+}
+
+func TestEnforceRetention_MaxSize(t *testing.T) {
+	dir := t.TempDir()
+	writeAged(t, filepath.Join(dir, "a.pdf"), 100, 3*time.Hour)
+	writeAged(t, filepath.Join(dir, "b.pdf"), 100, 2*time.Hour)
+	writeAged(t, filepath.Join(dir, "c.pdf"), 100, time.Hour)
+
+	target := pdfform.RetentionTarget{Name: "downloads", Path: dir, Policy: pdfform.RetentionPolicy{MaxSize: 150}}
+	report, err := pdfform.EnforceRetention(target, false)
+	if err != nil {
+		t.Fatalf("EnforceRetention: %v", err)
+	}
+	if report.TotalBytes != 300 || len(report.Removed) != 2 {
+		t.Fatalf("got total %d, removed %d; want 300, 2", report.TotalBytes, len(report.Removed))
+	}
+	for i, want := range []string{"a.pdf", "b.pdf"} {
+		if got := filepath.Base(report.Removed[i].Path); got != want || report.Removed[i].Reason != pdfform.RetentionReasonSize {
+			t.Errorf("removed[%d] = %s (%s), want %s (size)", i, got, report.Removed[i].Reason, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c.pdf")); err != nil {
+		t.Errorf("newest file should be kept: %v", err)
+	}
+}
+
+func TestRunJanitor_SkipsDisabledAndMissing(t *testing.T) {
+	cfg := pdfform.NewConfig(t.TempDir())
+	writeAged(t, filepath.Join(cfg.OutputsPath(), "old.pdf"), 10, 365*24*time.Hour)
+
+	// Default config only expires temp (which does not exist yet)
+	report, err := pdfform.RunJanitor(cfg, false)
+	if err != nil {
+		t.Fatalf("RunJanitor: %v", err)
+	}
+	if len(report.Dirs) != 1 || report.Dirs[0].Name != "temp" || report.Removed != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.OutputsPath(), "old.pdf")); err != nil {
+		t.Errorf("outputs without a policy must be kept: %v", err)
+	}
+}
@@ -0,0 +1,354 @@
+package pdfform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ================================================================
+// Integrity Manifest
+// ================================================================
+// Filled forms are legal documents, so the data directory keeps a manifest
+// (.data/manifest.json) of the SHA-256 of every PDF downloaded, template
+// written and form filled into it. Download, Inspect and Fill record what
+// they write (RecordChecksums), the janitor forgets what it removes, and
+// VerifyManifest (pdfform verify) rehashes everything to find files that were
+// modified, removed or added behind the tool's back.
+//
+// The manifest sits next to the files: it detects corruption and casual
+// tampering, not someone who rewrites the manifest too. Keep a copy elsewhere
+// (or sign it) where that matters.
+
+// DefaultManifestFileName is the manifest file inside the data directory
+const DefaultManifestFileName = "manifest.json"
+
+// ManifestVersion is the format version written to new manifests
+const ManifestVersion = 1
+
+// Integrity statuses reported by VerifyManifest
+const (
+	IntegrityModified  = "modified"  // Content differs from the recorded checksum
+	IntegrityMissing   = "missing"   // Recorded, but the file is gone
+	IntegrityUntracked = "untracked" // In a tracked directory, but never recorded
+)
+
+// ManifestFilePath returns the full path to the integrity manifest
+func (c *Config) ManifestFilePath() string {
+	return filepath.Join(c.DataDir, DefaultManifestFileName)
+}
+
+// ManifestDirs returns the directories whose files the manifest tracks
+func (c *Config) ManifestDirs() []string {
+	return []string{c.DownloadsPath(), c.TemplatesPath(), c.OutputsPath()}
+}
+
+// ManifestEntry is the recorded state of one file
+type ManifestEntry struct {
+	SHA256     string    `json:"sha256"`
+	Size       int64     `json:"size"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Manifest maps files (slash-separated, relative to the data directory) to
+// their recorded checksums
+type Manifest struct {
+	Version int                      `json:"version"`
+	Files   map[string]ManifestEntry `json:"files"`
+}
+
+// LoadManifest reads a manifest; a missing file is an empty manifest
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{Version: ManifestVersion, Files: make(map[string]ManifestEntry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]ManifestEntry)
+	}
+	return m, nil
+}
+
+// Save writes the manifest atomically
+func (m *Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+	if err := writeFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// manifestMu serializes manifest updates within the process; the lock file
+// (see lockCase) serializes them across processes
+var manifestMu sync.Mutex
+
+// updateManifest loads, changes and saves cfg's manifest under its lock
+func updateManifest(cfg *Config, change func(m *Manifest) error) error {
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+
+	path := cfg.ManifestFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+	unlock, err := lockCase(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m, err := LoadManifest(path)
+	if err != nil {
+		return err
+	}
+	if err := change(m); err != nil {
+		return err
+	}
+	return m.Save(path)
+}
+
+// manifestKey returns path relative to cfg's data directory, or false when
+// it lies outside it
+func manifestKey(cfg *Config, path string) (string, bool) {
+	dataDir, err := filepath.Abs(cfg.DataDir)
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(dataDir, abs)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// hashFile returns the SHA-256 and size of a file
+func hashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// RecordFiles records the current checksums of paths in cfg's manifest.
+// Paths outside the data directory are ignored.
+func RecordFiles(cfg *Config, paths ...string) error {
+	return updateManifest(cfg, func(m *Manifest) error {
+		for _, path := range paths {
+			key, ok := manifestKey(cfg, path)
+			if !ok {
+				continue
+			}
+			sum, size, err := hashFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to hash %s: %w", path, err)
+			}
+			m.Files[key] = ManifestEntry{SHA256: sum, Size: size, RecordedAt: time.Now().UTC()}
+		}
+		return nil
+	})
+}
+
+// ForgetFiles removes paths from cfg's manifest (for files deleted on
+// purpose). Without a manifest there is nothing to forget.
+func ForgetFiles(cfg *Config, paths ...string) error {
+	if _, err := os.Stat(cfg.ManifestFilePath()); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return updateManifest(cfg, func(m *Manifest) error {
+		for _, path := range paths {
+			if key, ok := manifestKey(cfg, path); ok {
+				delete(m.Files, key)
+			}
+		}
+		return nil
+	})
+}
+
+// RecordChecksums records paths in the default configuration's manifest
+// (see SetDefaultConfig) when they were written inside its data directory.
+// Download, Inspect and Fill call it for what they write.
+func RecordChecksums(paths ...string) error {
+	cfg := GetDefaultConfig()
+	var inside []string
+	for _, path := range paths {
+		if _, ok := manifestKey(cfg, path); path != "" && ok {
+			inside = append(inside, path)
+		}
+	}
+	if len(inside) == 0 {
+		return nil
+	}
+	return RecordFiles(cfg, inside...)
+}
+
+// recordChecksums is RecordChecksums that only warns, like provenance
+// metadata: a failed manifest update does not fail the download or fill
+func recordChecksums(paths ...string) {
+	if err := RecordChecksums(paths...); err != nil {
+		fmt.Printf("⚠️  Warning: Could not record checksums in the manifest: %v\n", err)
+	}
+}
+
+// IntegrityIssue is one file that does not match the manifest
+type IntegrityIssue struct {
+	Path     string `json:"path"`               // Relative to the data directory
+	Status   string `json:"status"`             // IntegrityModified, IntegrityMissing or IntegrityUntracked
+	Expected string `json:"expected,omitempty"` // Recorded SHA-256
+	Actual   string `json:"actual,omitempty"`   // Current SHA-256
+}
+
+// IntegrityReport is the result of VerifyManifest
+type IntegrityReport struct {
+	ManifestPath string           `json:"manifest_path"`
+	Checked      int              `json:"checked"` // Recorded files rehashed
+	Issues       []IntegrityIssue `json:"issues,omitempty"`
+}
+
+// OK reports whether every file matches the manifest
+func (r *IntegrityReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Count returns the number of issues with a status
+func (r *IntegrityReport) Count(status string) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// VerifyManifest rehashes every file recorded in cfg's manifest and lists
+// the files of the tracked directories (ManifestDirs) that were never
+// recorded. Provenance (.meta.json), lock and temporary files are not
+// tracked. Issues are sorted by path.
+func VerifyManifest(cfg *Config) (*IntegrityReport, error) {
+	report := &IntegrityReport{ManifestPath: cfg.ManifestFilePath()}
+	m, err := LoadManifest(report.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, entry := range m.Files {
+		report.Checked++
+		sum, _, err := hashFile(filepath.Join(cfg.DataDir, filepath.FromSlash(key)))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			report.Issues = append(report.Issues, IntegrityIssue{Path: key, Status: IntegrityMissing, Expected: entry.SHA256})
+		case err != nil:
+			return nil, fmt.Errorf("failed to hash %s: %w", key, err)
+		case sum != entry.SHA256:
+			report.Issues = append(report.Issues, IntegrityIssue{Path: key, Status: IntegrityModified, Expected: entry.SHA256, Actual: sum})
+		}
+	}
+
+	untracked, err := untrackedFiles(cfg, m)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range untracked {
+		report.Issues = append(report.Issues, IntegrityIssue{Path: key, Status: IntegrityUntracked})
+	}
+
+	sort.Slice(report.Issues, func(i, j int) bool { return report.Issues[i].Path < report.Issues[j].Path })
+	return report, nil
+}
+
+// RecordUntracked records every untracked file of the tracked directories,
+// e.g. to start a manifest for an existing data directory. Modified files
+// are left alone; accept those one by one with RecordFiles.
+func RecordUntracked(cfg *Config) ([]string, error) {
+	var recorded []string
+	err := updateManifest(cfg, func(m *Manifest) error {
+		untracked, err := untrackedFiles(cfg, m)
+		if err != nil {
+			return err
+		}
+		for _, key := range untracked {
+			sum, size, err := hashFile(filepath.Join(cfg.DataDir, filepath.FromSlash(key)))
+			if err != nil {
+				return fmt.Errorf("failed to hash %s: %w", key, err)
+			}
+			m.Files[key] = ManifestEntry{SHA256: sum, Size: size, RecordedAt: time.Now().UTC()}
+		}
+		recorded = untracked
+		return nil
+	})
+	return recorded, err
+}
+
+// untrackedFiles lists the files of the tracked directories not in m
+func untrackedFiles(cfg *Config, m *Manifest) ([]string, error) {
+	var untracked []string
+	for _, dir := range cfg.ManifestDirs() {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !d.Type().IsRegular() || !manifestTracked(d.Name()) {
+				return nil
+			}
+			if key, ok := manifestKey(cfg, path); ok {
+				if _, recorded := m.Files[key]; !recorded {
+					untracked = append(untracked, key)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+		}
+	}
+	sort.Strings(untracked)
+	return untracked, nil
+}
+
+// manifestTracked reports whether a file name is content the manifest covers
+// (not provenance metadata, which changes on inspection, or a lock or
+// temporary file)
+func manifestTracked(name string) bool {
+	return !strings.HasPrefix(name, ".") &&
+		!strings.HasSuffix(name, ".meta.json") &&
+		!strings.HasSuffix(name, ".lock") &&
+		!strings.Contains(name, ".tmp")
+}
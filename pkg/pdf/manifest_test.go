@@ -0,0 +1,125 @@
+package pdfform_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+// writeData writes content to a file, creating its directory
+func writeData(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyManifest_DetectsChanges(t *testing.T) {
+	cfg := pdfform.NewConfig(t.TempDir())
+	download := filepath.Join(cfg.DownloadsPath(), "VIC", "form.pdf")
+	template := filepath.Join(cfg.TemplatesPath(), "form_template.json")
+	output := filepath.Join(cfg.OutputsPath(), "form_filled.pdf")
+	writeData(t, download, "original pdf")
+	writeData(t, template, `{"fields":[]}`)
+	writeData(t, output, "filled pdf")
+
+	if err := pdfform.RecordFiles(cfg, download, template, output); err != nil {
+		t.Fatalf("RecordFiles: %v", err)
+	}
+	report, err := pdfform.VerifyManifest(cfg)
+	if err != nil {
+		t.Fatalf("VerifyManifest: %v", err)
+	}
+	if !report.OK() || report.Checked != 3 {
+		t.Fatalf("fresh manifest: checked %d, issues %+v", report.Checked, report.Issues)
+	}
+
+	writeData(t, download, "tampered pdf")
+	os.Remove(output)
+	writeData(t, filepath.Join(cfg.OutputsPath(), "stranger.pdf"), "unknown")
+	writeData(t, filepath.Join(cfg.DownloadsPath(), "VIC", "form.meta.json"), "{}")
+
+	report, err = pdfform.VerifyManifest(cfg)
+	if err != nil {
+		t.Fatalf("VerifyManifest: %v", err)
+	}
+	want := []pdfform.IntegrityIssue{
+		{Path: "downloads/VIC/form.pdf", Status: pdfform.IntegrityModified},
+		{Path: "outputs/form_filled.pdf", Status: pdfform.IntegrityMissing},
+		{Path: "outputs/stranger.pdf", Status: pdfform.IntegrityUntracked},
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("issues = %+v, want %d", report.Issues, len(want))
+	}
+	for i, w := range want {
+		if got := report.Issues[i]; got.Path != w.Path || got.Status != w.Status {
+			t.Errorf("issue %d = %s %s, want %s %s", i, got.Status, got.Path, w.Status, w.Path)
+		}
+	}
+	if issue := report.Issues[0]; issue.Expected == "" || issue.Actual == "" || issue.Expected == issue.Actual {
+		t.Errorf("modified issue should carry both checksums: %+v", issue)
+	}
+
+	// Accepting the change and recording the stranger leaves only the missing file
+	if err := pdfform.RecordFiles(cfg, download); err != nil {
+		t.Fatalf("RecordFiles: %v", err)
+	}
+	recorded, err := pdfform.RecordUntracked(cfg)
+	if err != nil {
+		t.Fatalf("RecordUntracked: %v", err)
+	}
+	if len(recorded) != 1 || recorded[0] != "outputs/stranger.pdf" {
+		t.Errorf("RecordUntracked = %v", recorded)
+	}
+	report, err = pdfform.VerifyManifest(cfg)
+	if err != nil {
+		t.Fatalf("VerifyManifest: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Count(pdfform.IntegrityMissing) != 1 {
+		t.Errorf("issues = %+v, want only the missing output", report.Issues)
+	}
+}
+
+func TestRecordFiles_IgnoresPathsOutsideDataDir(t *testing.T) {
+	cfg := pdfform.NewConfig(t.TempDir())
+	outside := filepath.Join(t.TempDir(), "elsewhere.pdf")
+	writeData(t, outside, "not ours")
+
+	if err := pdfform.RecordFiles(cfg, outside); err != nil {
+		t.Fatalf("RecordFiles: %v", err)
+	}
+	m, err := pdfform.LoadManifest(cfg.ManifestFilePath())
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if len(m.Files) != 0 {
+		t.Errorf("manifest should be empty, got %v", m.Files)
+	}
+}
+
+func TestRunJanitor_ForgetsRemovedFiles(t *testing.T) {
+	cfg := pdfform.NewConfig(t.TempDir())
+	cfg.OutputsRetention = pdfform.RetentionPolicy{MaxAge: 24 * time.Hour}
+	old := filepath.Join(cfg.OutputsPath(), "old.pdf")
+	writeAged(t, old, 10, 48*time.Hour)
+	if err := pdfform.RecordFiles(cfg, old); err != nil {
+		t.Fatalf("RecordFiles: %v", err)
+	}
+
+	if _, err := pdfform.RunJanitor(cfg, false); err != nil {
+		t.Fatalf("RunJanitor: %v", err)
+	}
+	report, err := pdfform.VerifyManifest(cfg)
+	if err != nil {
+		t.Fatalf("VerifyManifest: %v", err)
+	}
+	if !report.OK() || report.Checked != 0 {
+		t.Errorf("janitor removals should be forgotten: checked %d, issues %+v", report.Checked, report.Issues)
+	}
+}
@@ -0,0 +1,221 @@
+package pdfform
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// ================================================================
+// Output Metadata
+// ================================================================
+// Some agencies only accept archival submissions: a titled document with
+// matching Info dictionary and XMP metadata, a declared language, and
+// optionally PDF/A. ApplyMetadata stamps all of that onto a filled PDF.
+//
+// The Producer entry is always written by the library that saves the file
+// (pdfcpu, or Ghostscript for PDF/A), so our application name is recorded as
+// Creator / xmp:CreatorTool instead.
+
+// DefaultCreator is recorded as the creating application when Metadata.Creator is empty
+const DefaultCreator = "wellknown pdfform"
+
+// Metadata is the document metadata stamped onto an output PDF
+type Metadata struct {
+	Title    string   `json:"title,omitempty"`
+	Author   string   `json:"author,omitempty"`
+	Subject  string   `json:"subject,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+	Creator  string   `json:"creator,omitempty"`  // Creating application (default: DefaultCreator)
+	CaseID   string   `json:"case_id,omitempty"`  // Stored as a custom CaseID entry and in XMP
+	Language string   `json:"language,omitempty"` // Document language for screen readers, e.g. "en-AU"
+	PDFA     bool     `json:"pdfa,omitempty"`     // Convert to PDF/A-2b with Ghostscript after stamping
+}
+
+// ApplyMetadata stamps meta onto pdfPath in place and converts it to PDF/A when requested
+func ApplyMetadata(pdfPath string, meta Metadata) error {
+	if err := StampMetadata(pdfPath, pdfPath, meta); err != nil {
+		return err
+	}
+	if meta.PDFA {
+		return ConvertToPDFA(pdfPath, pdfPath)
+	}
+	return nil
+}
+
+// StampMetadata writes inputPDF to outputPDF with meta set in the Info dictionary
+// and as an XMP metadata stream. A Title also makes viewers display it instead of
+// the file name, and Language sets the catalog /Lang (both accessibility requirements).
+// outputPDF may equal inputPDF.
+func StampMetadata(inputPDF, outputPDF string, meta Metadata) error {
+	if meta.Creator == "" {
+		meta.Creator = DefaultCreator
+	}
+
+	ctx, err := api.ReadContextFile(inputPDF)
+	if err != nil {
+		return fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	// Info dictionary (Producer and dates are set by pdfcpu on write)
+	info := map[string]string{"Creator": meta.Creator}
+	for key, value := range map[string]string{
+		"Title":    meta.Title,
+		"Author":   meta.Author,
+		"Subject":  meta.Subject,
+		"Keywords": strings.Join(meta.Keywords, ", "),
+		"CaseID":   meta.CaseID,
+	} {
+		if value != "" {
+			info[key] = value
+		}
+	}
+	if err := pdfcpu.PropertiesAdd(ctx, info); err != nil {
+		return fmt.Errorf("failed to set document info: %w", err)
+	}
+
+	root, err := ctx.Catalog()
+	if err != nil {
+		return fmt.Errorf("failed to read PDF catalog: %w", err)
+	}
+
+	// XMP metadata stream (uncompressed, as PDF/A requires)
+	sd := types.StreamDict{Dict: types.NewDict(), Content: []byte(buildXMP(meta, time.Now()))}
+	sd.InsertName("Type", "Metadata")
+	sd.InsertName("Subtype", "XML")
+	if err := sd.Encode(); err != nil {
+		return fmt.Errorf("failed to encode XMP metadata: %w", err)
+	}
+	ref, err := ctx.IndRefForNewObject(sd)
+	if err != nil {
+		return fmt.Errorf("failed to add XMP metadata: %w", err)
+	}
+	root.Update("Metadata", *ref)
+
+	if meta.Language != "" {
+		root.Update("Lang", types.StringLiteral(meta.Language))
+	}
+	if meta.Title != "" {
+		prefs, err := ctx.DereferenceDict(root["ViewerPreferences"])
+		if err != nil || prefs == nil {
+			prefs = types.NewDict()
+			root.Update("ViewerPreferences", prefs)
+		}
+		prefs.Update("DisplayDocTitle", types.Boolean(true))
+	}
+
+	tmp := outputPDF + ".tmp"
+	if err := api.WriteContextFile(ctx, tmp); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	if err := os.Rename(tmp, outputPDF); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return nil
+}
+
+// buildXMP renders the XMP packet for meta. pdf:Producer matches what pdfcpu
+// writes to the Info dictionary, so the two stay consistent.
+func buildXMP(meta Metadata, now time.Time) string {
+	date := now.Format(time.RFC3339)
+
+	var b strings.Builder
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.WriteString(" <rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	b.WriteString("  <rdf:Description rdf:about=\"\"\n")
+	b.WriteString("    xmlns:dc=\"http://purl.org/dc/elements/1.1/\"\n")
+	b.WriteString("    xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"\n")
+	b.WriteString("    xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\"\n")
+	b.WriteString("    xmlns:wk=\"https://github.com/joeblew999/wellknown/ns/pdfform/1.0/\">\n")
+	b.WriteString("   <dc:format>application/pdf</dc:format>\n")
+	if meta.Title != "" {
+		fmt.Fprintf(&b, "   <dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", xmlEscape(meta.Title))
+	}
+	if meta.Author != "" {
+		fmt.Fprintf(&b, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmlEscape(meta.Author))
+	}
+	if meta.Subject != "" {
+		fmt.Fprintf(&b, "   <dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", xmlEscape(meta.Subject))
+	}
+	if meta.Language != "" {
+		fmt.Fprintf(&b, "   <dc:language><rdf:Bag><rdf:li>%s</rdf:li></rdf:Bag></dc:language>\n", xmlEscape(meta.Language))
+	}
+	if len(meta.Keywords) > 0 {
+		fmt.Fprintf(&b, "   <pdf:Keywords>%s</pdf:Keywords>\n", xmlEscape(strings.Join(meta.Keywords, ", ")))
+	}
+	fmt.Fprintf(&b, "   <pdf:Producer>%s</pdf:Producer>\n", xmlEscape("pdfcpu "+model.VersionStr))
+	fmt.Fprintf(&b, "   <xmp:CreatorTool>%s</xmp:CreatorTool>\n", xmlEscape(meta.Creator))
+	fmt.Fprintf(&b, "   <xmp:CreateDate>%s</xmp:CreateDate>\n", date)
+	fmt.Fprintf(&b, "   <xmp:ModifyDate>%s</xmp:ModifyDate>\n", date)
+	fmt.Fprintf(&b, "   <xmp:MetadataDate>%s</xmp:MetadataDate>\n", date)
+	if meta.CaseID != "" {
+		fmt.Fprintf(&b, "   <wk:CaseID>%s</wk:CaseID>\n", xmlEscape(meta.CaseID))
+	}
+	b.WriteString("  </rdf:Description>\n")
+	b.WriteString(" </rdf:RDF>\n")
+	b.WriteString("</x:xmpmeta>\n")
+	b.WriteString("<?xpacket end=\"w\"?>")
+	return b.String()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// ================================================================
+// PDF/A (Ghostscript)
+// ================================================================
+
+// GhostscriptAvailable reports whether the gs binary can be found
+func GhostscriptAvailable() bool {
+	_, err := exec.LookPath(ghostscriptBin())
+	return err == nil
+}
+
+func ghostscriptBin() string {
+	if bin := os.Getenv("GS_BIN"); bin != "" {
+		return bin
+	}
+	return "gs"
+}
+
+// ConvertToPDFA rewrites inputPDF as PDF/A-2b using Ghostscript (outputPDF may
+// equal inputPDF). Ghostscript regenerates the XMP from the Info dictionary, so
+// stamp metadata first. PDF/A forbids encryption: protect copies, not the archive file.
+func ConvertToPDFA(inputPDF, outputPDF string) error {
+	if !GhostscriptAvailable() {
+		return fmt.Errorf("PDF/A conversion requires Ghostscript (%s not found; set GS_BIN)", ghostscriptBin())
+	}
+
+	tmp := outputPDF + ".pdfa.tmp"
+	cmd := exec.Command(ghostscriptBin(),
+		"-dPDFA=2", "-dBATCH", "-dNOPAUSE", "-dQUIET",
+		"-dPDFACompatibilityPolicy=1",
+		"-sColorConversionStrategy=RGB",
+		"-sDEVICE=pdfwrite",
+		"-sOutputFile="+tmp,
+		inputPDF,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("ghostscript PDF/A conversion failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	if err := os.Rename(tmp, outputPDF); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write PDF/A: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package pdfform_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+func TestStampMetadata(t *testing.T) {
+	input := createConformanceForm(t)
+	output := filepath.Join(t.TempDir(), "stamped.pdf")
+
+	meta := pdfform.Metadata{
+		Title:    "Transfer of Registration",
+		Author:   "Jane Doe",
+		Keywords: []string{"vicroads", "transfer"},
+		CaseID:   "case-42",
+		Language: "en-AU",
+	}
+	if err := pdfform.StampMetadata(input, output, meta); err != nil {
+		t.Fatalf("StampMetadata failed: %v", err)
+	}
+
+	ctx, err := api.ReadContextFile(output)
+	if err != nil {
+		t.Fatalf("failed to read stamped PDF: %v", err)
+	}
+
+	// Info dictionary
+	info, err := ctx.DereferenceDict(*ctx.Info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"Title":   meta.Title,
+		"Author":  meta.Author,
+		"CaseID":  meta.CaseID,
+		"Creator": pdfform.DefaultCreator,
+	} {
+		got, err := types.StringOrHexLiteral(info[key])
+		if err != nil || got == nil || *got != want {
+			t.Errorf("Info %s = %v, want %q (err %v)", key, got, want, err)
+		}
+	}
+
+	// Catalog: XMP stream, language and title display
+	root, err := ctx.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd, _, err := ctx.DereferenceStreamDict(root["Metadata"])
+	if err != nil || sd == nil {
+		t.Fatalf("no XMP metadata stream: %v", err)
+	}
+	if err := sd.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	xmp := string(sd.Content)
+	for _, want := range []string{"Transfer of Registration", "<wk:CaseID>case-42</wk:CaseID>", "vicroads, transfer", "en-AU"} {
+		if !strings.Contains(xmp, want) {
+			t.Errorf("XMP missing %q", want)
+		}
+	}
+	if lang, _ := types.StringOrHexLiteral(root["Lang"]); lang == nil || *lang != "en-AU" {
+		t.Errorf("Lang = %v, want en-AU", lang)
+	}
+	prefs, _ := ctx.DereferenceDict(root["ViewerPreferences"])
+	if b := prefs.BooleanEntry("DisplayDocTitle"); b == nil || !*b {
+		t.Error("expected ViewerPreferences DisplayDocTitle true")
+	}
+
+	// Form fields survive stamping
+	fields, err := pdfform.ListFormFields(output)
+	if err != nil || len(fields) != 2 {
+		t.Errorf("stamped PDF has %d fields (err %v), want 2", len(fields), err)
+	}
+}
+
+func TestFill_PDFAWithProtectRejected(t *testing.T) {
+	_, err := pdfform.Fill(pdfform.FillOptions{
+		DataPath: "unused.json",
+		Metadata: &pdfform.Metadata{PDFA: true},
+		Protect:  &pdfform.Protection{UserPassword: "secret"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "PDF/A") {
+		t.Errorf("err = %v, want PDF/A protection error", err)
+	}
+}
+
+func TestConvertToPDFA(t *testing.T) {
+	if !pdfform.GhostscriptAvailable() {
+		t.Skip("Ghostscript not installed")
+	}
+	input := createConformanceForm(t)
+	output := filepath.Join(t.TempDir(), "archive.pdf")
+	if err := pdfform.StampMetadata(input, output, pdfform.Metadata{Title: "Archive"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pdfform.ConvertToPDFA(output, output); err != nil {
+		t.Fatalf("ConvertToPDFA failed: %v", err)
+	}
+	if _, err := api.ReadContextFile(output); err != nil {
+		t.Errorf("PDF/A output unreadable: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package pdfform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// ================================================================
+// Password-Protected PDFs
+// ================================================================
+// Some council forms are encrypted. Inspect and Fill decrypt them on the fly
+// into a temp file (the input is never modified), and Fill can re-protect its
+// output with new passwords. Owner-only protected PDFs (no open password,
+// editing restricted) decrypt without any password.
+
+// ErrPasswordRequired is returned when a PDF needs a password that wasn't given (or was wrong)
+var ErrPasswordRequired = errors.New("PDF is password-protected: provide the correct password")
+
+// Passwords unlocks an encrypted input PDF
+type Passwords struct {
+	User  string `json:"user,omitempty"`  // Open password
+	Owner string `json:"owner,omitempty"` // Permissions password
+}
+
+// IsZero reports whether no password is set
+func (p Passwords) IsZero() bool {
+	return p.User == "" && p.Owner == ""
+}
+
+// Protection re-protects an output PDF (AES-256)
+type Protection struct {
+	UserPassword  string // Password required to open the PDF (may be empty for owner-only protection)
+	OwnerPassword string // Password required to change permissions (default: UserPassword)
+}
+
+func (p Passwords) configuration() *model.Configuration {
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = p.User
+	conf.OwnerPW = p.Owner
+	return conf
+}
+
+// IsEncryptedPDF reports whether inputPDF is encrypted
+func IsEncryptedPDF(inputPDF string) (bool, error) {
+	f, err := os.Open(inputPDF)
+	if err != nil {
+		return false, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer f.Close()
+
+	ctx, err := api.ReadContext(f, model.NewDefaultConfiguration())
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	return ctx.Encrypt != nil, nil
+}
+
+// DecryptPDF writes a decrypted copy of inputPDF to outputPDF
+func DecryptPDF(inputPDF, outputPDF string, pw Passwords) error {
+	if err := api.DecryptFile(inputPDF, outputPDF, pw.configuration()); err != nil {
+		if errors.Is(err, pdfcpu.ErrWrongPassword) {
+			return ErrPasswordRequired
+		}
+		return fmt.Errorf("failed to decrypt PDF: %w", err)
+	}
+	return nil
+}
+
+// ProtectPDF encrypts inputPDF with AES-256 and writes it to outputPDF
+// (outputPDF may equal inputPDF to protect in place)
+func ProtectPDF(inputPDF, outputPDF string, p Protection) error {
+	owner := p.OwnerPassword
+	if owner == "" {
+		owner = p.UserPassword
+	}
+	if owner == "" {
+		return fmt.Errorf("a user or owner password is required to protect a PDF")
+	}
+
+	conf := model.NewAESConfiguration(p.UserPassword, owner, 256)
+	if err := api.EncryptFile(inputPDF, outputPDF, conf); err != nil {
+		return fmt.Errorf("failed to protect PDF: %w", err)
+	}
+	return nil
+}
+
+// withDecryptedPDF calls fn with a readable path for inputPDF: inputPDF itself
+// when it isn't encrypted, otherwise a decrypted temp copy removed afterwards
+func withDecryptedPDF(inputPDF string, pw Passwords, fn func(path string) error) error {
+	encrypted, err := IsEncryptedPDF(inputPDF)
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return fn(inputPDF)
+	}
+
+	tmp, err := os.CreateTemp("", "pdfform-decrypted-*.pdf")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := DecryptPDF(inputPDF, tmp.Name(), pw); err != nil {
+		return err
+	}
+	return fn(tmp.Name())
+}
@@ -0,0 +1,112 @@
+package pdfform_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+// createProtectedForm returns the conformance form encrypted with the given open password
+func createProtectedForm(t *testing.T, userPW string) string {
+	t.Helper()
+	input := createConformanceForm(t)
+	protected := filepath.Join(t.TempDir(), "protected.pdf")
+	if err := pdfform.ProtectPDF(input, protected, pdfform.Protection{UserPassword: userPW, OwnerPassword: "owner"}); err != nil {
+		t.Fatalf("ProtectPDF failed: %v", err)
+	}
+	return protected
+}
+
+func TestIsEncryptedPDF(t *testing.T) {
+	plain := createConformanceForm(t)
+	if encrypted, err := pdfform.IsEncryptedPDF(plain); err != nil || encrypted {
+		t.Errorf("plain form: encrypted=%v err=%v, want false", encrypted, err)
+	}
+
+	for _, userPW := range []string{"secret", ""} {
+		protected := createProtectedForm(t, userPW)
+		if encrypted, err := pdfform.IsEncryptedPDF(protected); err != nil || !encrypted {
+			t.Errorf("protected form (user %q): encrypted=%v err=%v, want true", userPW, encrypted, err)
+		}
+	}
+}
+
+func TestInspect_Password(t *testing.T) {
+	protected := createProtectedForm(t, "secret")
+
+	_, err := pdfform.Inspect(pdfform.InspectOptions{PDFPath: protected, OutputDir: t.TempDir()})
+	if !errors.Is(err, pdfform.ErrPasswordRequired) {
+		t.Fatalf("Inspect without password: err = %v, want ErrPasswordRequired", err)
+	}
+
+	result, err := pdfform.Inspect(pdfform.InspectOptions{
+		PDFPath:   protected,
+		OutputDir: t.TempDir(),
+		Password:  pdfform.Passwords{User: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("Inspect with password failed: %v", err)
+	}
+	if result.FieldCount != 2 {
+		t.Errorf("FieldCount = %d, want 2", result.FieldCount)
+	}
+}
+
+func TestInspect_OwnerOnlyProtection(t *testing.T) {
+	// No open password: decrypted on the fly without any password
+	protected := createProtectedForm(t, "")
+
+	result, err := pdfform.Inspect(pdfform.InspectOptions{PDFPath: protected, OutputDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if result.FieldCount != 2 {
+		t.Errorf("FieldCount = %d, want 2", result.FieldCount)
+	}
+}
+
+func TestFill_PasswordAndProtect(t *testing.T) {
+	protected := createProtectedForm(t, "secret")
+	dir := t.TempDir()
+
+	data, _ := json.Marshal(pdfform.FormData{
+		PdfURL: protected,
+		Fields: map[string]string{"first_name": "Jane", "last_name": "Doe"},
+	})
+	dataPath := filepath.Join(dir, "data.json")
+	if err := os.WriteFile(dataPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := pdfform.Fill(pdfform.FillOptions{
+		DataPath:  dataPath,
+		OutputDir: dir,
+		Password:  pdfform.Passwords{User: "secret"},
+		Protect:   &pdfform.Protection{UserPassword: "new-secret"},
+	})
+	if err != nil {
+		t.Fatalf("Fill failed: %v", err)
+	}
+	if !result.Protected {
+		t.Error("Expected Protected=true")
+	}
+
+	// The input is untouched and the output opens only with the new password
+	if _, err := pdfform.ListFormFieldsWithPasswords(protected, pdfform.Passwords{User: "secret"}); err != nil {
+		t.Errorf("input no longer opens with its password: %v", err)
+	}
+	if _, err := pdfform.ListFormFieldsWithPasswords(result.OutputPath, pdfform.Passwords{User: "secret"}); !errors.Is(err, pdfform.ErrPasswordRequired) {
+		t.Errorf("output with old password: err = %v, want ErrPasswordRequired", err)
+	}
+	fields, err := pdfform.ListFormFieldsWithPasswords(result.OutputPath, pdfform.Passwords{User: "new-secret"})
+	if err != nil {
+		t.Fatalf("output with new password: %v", err)
+	}
+	if len(fields) != 2 {
+		t.Errorf("output has %d fields, want 2", len(fields))
+	}
+}
@@ -20,8 +20,10 @@ import (
 // FormData represents the JSON structure with optional PDF URL and form field data
 type FormData struct {
 	PdfURL     string            `json:"pdf_url,omitempty"`
+	Engine     string            `json:"engine,omitempty"` // PDF engine for this form (default: EngineAuto)
 	Provenance *Provenance       `json:"provenance,omitempty"`
 	Fields     map[string]string `json:"fields"`
+	FieldDefs  []TemplateField   `json:"field_defs,omitempty"` // Set by the template editor
 }
 
 // isURL checks if a string is a valid HTTP/HTTPS URL
@@ -34,9 +36,10 @@ func isURL(str string) bool {
 	return u.Scheme == "http" || u.Scheme == "https"
 }
 
-// DownloadPDF downloads a PDF from a URL to a local file
+// DownloadPDF downloads a PDF from a URL to a local file, politely (see
+// DownloadClient)
 func DownloadPDF(pdfURL, outputPath string) error {
-	resp, err := http.Get(pdfURL)
+	resp, err := DownloadClient().Get(pdfURL)
 	if err != nil {
 		return fmt.Errorf("failed to download PDF: %w", err)
 	}
@@ -114,19 +117,8 @@ func FillPDFBenoitkugler(inputPDF string, fields map[string]string, outputPDF st
 
 // FillPDFWithFallback tries to fill a PDF using pdfcpu first, then falls back to benoitkugler
 func FillPDFWithFallback(inputPDF string, fields map[string]string, outputPDF string) error {
-	// Create temporary JSON for pdfcpu
-	tempJSON := filepath.Join(os.TempDir(), "form_fields.json")
-	fieldsJSON, err := json.Marshal(fields)
-	if err != nil {
-		return fmt.Errorf("failed to marshal fields: %w", err)
-	}
-	if err := os.WriteFile(tempJSON, fieldsJSON, 0644); err != nil {
-		return fmt.Errorf("failed to write temp JSON: %w", err)
-	}
-	defer os.Remove(tempJSON)
-
 	// Try pdfcpu first
-	err = FillPDF(inputPDF, tempJSON, outputPDF)
+	err := PdfcpuEngine{}.Fill(inputPDF, fields, outputPDF)
 	if err == nil {
 		return nil // Success with pdfcpu
 	}
@@ -138,6 +130,12 @@ func FillPDFWithFallback(inputPDF string, fields map[string]string, outputPDF st
 
 // FillPDFFromJSON fills a PDF using structured JSON data (with optional PDF URL or local path)
 func FillPDFFromJSON(jsonFile, outputPDF string) (inputPDF string, err error) {
+	return FillPDFFromJSONWithPasswords(jsonFile, outputPDF, Passwords{})
+}
+
+// FillPDFFromJSONWithPasswords is FillPDFFromJSON for a password-protected input PDF.
+// Encrypted inputs are decrypted to a temp file before filling; the output is unencrypted.
+func FillPDFFromJSONWithPasswords(jsonFile, outputPDF string, pw Passwords) (inputPDF string, err error) {
 	// Read and parse JSON
 	data, err := os.ReadFile(jsonFile)
 	if err != nil {
@@ -169,20 +167,23 @@ func FillPDFFromJSON(jsonFile, outputPDF string) (inputPDF string, err error) {
 		return "", fmt.Errorf("pdf_url is required in JSON data")
 	}
 
-	// Create a temporary JSON file with just the fields for pdfcpu
-	tempJSON := filepath.Join(os.TempDir(), "form_data.json")
-	fieldsJSON, err := json.Marshal(formData.Fields)
+	// Fill the PDF with the form's engine (default: pdfcpu with benoitkugler fallback)
+	engine, err := GetEngine(formData.Engine)
 	if err != nil {
-		return inputPDF, fmt.Errorf("failed to marshal fields: %w", err)
+		return inputPDF, err
 	}
-	if err := os.WriteFile(tempJSON, fieldsJSON, 0644); err != nil {
-		return inputPDF, fmt.Errorf("failed to write temp JSON: %w", err)
+	fields, err := formData.ResolveFields()
+	if err != nil {
+		return inputPDF, err
 	}
-	defer os.Remove(tempJSON)
-
-	// Fill the PDF using fallback approach
-	if err := FillPDFWithFallback(inputPDF, formData.Fields, outputPDF); err != nil {
-		return inputPDF, fmt.Errorf("failed to fill PDF: %w", err)
+	err = withDecryptedPDF(inputPDF, pw, func(path string) error {
+		if err := engine.Fill(path, fields, outputPDF); err != nil {
+			return fmt.Errorf("failed to fill PDF (%s engine): %w", engine.Name(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return inputPDF, err
 	}
 
 	return inputPDF, nil
@@ -222,10 +223,26 @@ func ListFormFields(inputPDF string) ([]form.Field, error) {
 	return fields, nil
 }
 
+// ListFormFieldsWithPasswords is ListFormFields for a password-protected PDF
+func ListFormFieldsWithPasswords(inputPDF string, pw Passwords) ([]form.Field, error) {
+	var fields []form.Field
+	err := withDecryptedPDF(inputPDF, pw, func(path string) error {
+		var err error
+		fields, err = ListFormFields(path)
+		return err
+	})
+	return fields, err
+}
+
 // ExportFormFieldsToJSON extracts form fields and exports them as a JSON template
 // If provenance metadata exists, it will be included in the template
 func ExportFormFieldsToJSON(inputPDF, outputJSON string) error {
-	fields, err := ListFormFields(inputPDF)
+	return ExportFormFieldsToJSONWithPasswords(inputPDF, outputJSON, Passwords{})
+}
+
+// ExportFormFieldsToJSONWithPasswords is ExportFormFieldsToJSON for a password-protected PDF
+func ExportFormFieldsToJSONWithPasswords(inputPDF, outputJSON string, pw Passwords) error {
+	fields, err := ListFormFieldsWithPasswords(inputPDF, pw)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,265 @@
+package pdfform
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ================================================================
+// Download Politeness
+// ================================================================
+// State government sites are small and quick to block scrapers. Every
+// request the package makes to them (DownloadPDF, the download cache, catalog
+// verification) goes through one shared PoliteTransport, which
+//
+//   - identifies the tool with a User-Agent
+//   - spaces requests to the same host at least HostInterval apart, across
+//     all goroutines, so a catalog refresh or batch download trickles in
+//   - retries 429, 5xx gateway errors and network failures with exponential
+//     backoff, honouring Retry-After (which also pauses the whole host)
+//
+// Loopback hosts (local mirrors, tests) are not spaced out.
+
+// Politeness defaults
+const (
+	DefaultUserAgent    = "wellknown-pdfform/1.0 (+https://github.com/joeblew999/wellknown)"
+	DefaultHostInterval = time.Second
+	DefaultMaxRetries   = 3
+	DefaultBaseBackoff  = time.Second
+	DefaultMaxBackoff   = 2 * time.Minute
+)
+
+// PolitenessPolicy configures how the package treats remote sites. Zero
+// values mean the defaults; a negative HostInterval or MaxRetries disables
+// spacing or retries.
+type PolitenessPolicy struct {
+	UserAgent    string        // Sent when a request has none (default: DefaultUserAgent)
+	HostInterval time.Duration // Minimum gap between requests to one host (default: 1s)
+	MaxRetries   int           // Retries after a retryable failure (default: 3)
+	BaseBackoff  time.Duration // First retry delay, doubled on each retry (default: 1s)
+	MaxBackoff   time.Duration // Longest delay; a longer Retry-After is not waited for (default: 2m)
+}
+
+// withDefaults returns p with zero values replaced by the defaults
+func (p PolitenessPolicy) withDefaults() PolitenessPolicy {
+	if p.UserAgent == "" {
+		p.UserAgent = DefaultUserAgent
+	}
+	if p.HostInterval == 0 {
+		p.HostInterval = DefaultHostInterval
+	}
+	if p.MaxRetries == 0 {
+		p.MaxRetries = DefaultMaxRetries
+	}
+	if p.BaseBackoff <= 0 {
+		p.BaseBackoff = DefaultBaseBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	return p
+}
+
+// PoliteTransport is an http.RoundTripper that applies a PolitenessPolicy.
+// Share one transport between clients so the per-host spacing holds across
+// them.
+type PoliteTransport struct {
+	Base   http.RoundTripper // Underlying transport (default: http.DefaultTransport)
+	Policy PolitenessPolicy
+
+	mu   sync.Mutex
+	next map[string]time.Time // Earliest time of the next request per host
+}
+
+// NewPoliteTransport creates a transport applying policy over
+// http.DefaultTransport
+func NewPoliteTransport(policy PolitenessPolicy) *PoliteTransport {
+	return &PoliteTransport{Policy: policy}
+}
+
+// RoundTrip sends req, waiting for the host's turn and retrying retryable
+// failures. Requests with a body are only retried when it can be replayed.
+func (t *PoliteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := t.Policy.withDefaults()
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx := req.Context()
+	host := strings.ToLower(req.URL.Hostname())
+	canRetry := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(ctx, host, policy.HostInterval); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req.Clone(ctx)
+		if attemptReq.Header.Get("User-Agent") == "" {
+			attemptReq.Header.Set("User-Agent", policy.UserAgent)
+		}
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := base.RoundTrip(attemptReq)
+		if !canRetry || attempt >= policy.MaxRetries || !retryable(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := backoff(policy, attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if after > policy.MaxBackoff {
+					// The site wants a longer break than we are willing to wait
+					return resp, nil
+				}
+				delay = after
+				t.pause(host, after)
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// wait blocks until it is host's turn. Turns are handed out in order, so
+// concurrent requests to one host are spaced interval apart.
+func (t *PoliteTransport) wait(ctx context.Context, host string, interval time.Duration) error {
+	if interval <= 0 || isLoopbackHost(host) {
+		return nil
+	}
+
+	t.mu.Lock()
+	if t.next == nil {
+		t.next = make(map[string]time.Time)
+	}
+	now := time.Now()
+	slot := t.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	t.next[host] = slot.Add(interval)
+	t.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// pause holds every request to host for d, after a Retry-After
+func (t *PoliteTransport) pause(host string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.next == nil {
+		t.next = make(map[string]time.Time)
+	}
+	if until := time.Now().Add(d); until.After(t.next[host]) {
+		t.next[host] = until
+	}
+}
+
+// retryable reports whether a response or error is worth retrying
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before retry attempt+1
+func backoff(policy PolitenessPolicy, attempt int) time.Duration {
+	delay := policy.BaseBackoff
+	for i := 0; i < attempt && delay < policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+	return delay
+}
+
+// retryAfter parses a Retry-After header: delay seconds or an HTTP date
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := when.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback address
+func isLoopbackHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+var (
+	downloadMu        sync.Mutex
+	downloadClient    *http.Client
+	downloadClientFor PolitenessPolicy
+)
+
+// DownloadClient returns the shared client for requests to remote sites,
+// applying the default configuration's Politeness (see SetDefaultConfig).
+// The client is rebuilt when the policy changes.
+func DownloadClient() *http.Client {
+	policy := GetDefaultConfig().Politeness
+
+	downloadMu.Lock()
+	defer downloadMu.Unlock()
+	if downloadClient == nil || policy != downloadClientFor {
+		downloadClient = &http.Client{Transport: NewPoliteTransport(policy)}
+		downloadClientFor = policy
+	}
+	return downloadClient
+}
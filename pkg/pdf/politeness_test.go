@@ -0,0 +1,208 @@
+package pdfform
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// roundTripFunc is an http.RoundTripper backed by a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// reply builds a response with status and headers (name, value pairs)
+func reply(status int, headers ...string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(""))}
+	for i := 0; i+1 < len(headers); i += 2 {
+		resp.Header.Set(headers[i], headers[i+1])
+	}
+	return resp
+}
+
+func TestPoliteTransport_RetriesWithUserAgent(t *testing.T) {
+	var attempts []string
+	transport := &PoliteTransport{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts = append(attempts, req.Header.Get("User-Agent"))
+			if len(attempts) < 3 {
+				return reply(http.StatusServiceUnavailable), nil
+			}
+			return reply(http.StatusOK), nil
+		}),
+		Policy: PolitenessPolicy{HostInterval: -1, BaseBackoff: time.Millisecond},
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get("https://forms.example.gov.au/f.pdf")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(attempts) != 3 {
+		t.Fatalf("got HTTP %d after %d attempts, want 200 after 3", resp.StatusCode, len(attempts))
+	}
+	for i, ua := range attempts {
+		if ua != DefaultUserAgent {
+			t.Errorf("attempt %d User-Agent = %q", i, ua)
+		}
+	}
+}
+
+func TestPoliteTransport_GivesUpAndSkipsNonRetryable(t *testing.T) {
+	attempts := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if req.URL.Path == "/missing.pdf" {
+			return reply(http.StatusNotFound), nil
+		}
+		return reply(http.StatusBadGateway), nil
+	})
+	client := &http.Client{Transport: &PoliteTransport{
+		Base:   base,
+		Policy: PolitenessPolicy{UserAgent: "custom/1.0", HostInterval: -1, MaxRetries: 2, BaseBackoff: time.Millisecond},
+	}}
+
+	resp, err := client.Get("https://forms.example.gov.au/down.pdf")
+	if err != nil || resp.StatusCode != http.StatusBadGateway || attempts != 3 {
+		t.Errorf("got %v, HTTP %d after %d attempts, want 502 after 3", err, resp.StatusCode, attempts)
+	}
+
+	attempts = 0
+	resp, err = client.Get("https://forms.example.gov.au/missing.pdf")
+	if err != nil || resp.StatusCode != http.StatusNotFound || attempts != 1 {
+		t.Errorf("404 should not be retried: %v, HTTP %d after %d attempts", err, resp.StatusCode, attempts)
+	}
+
+	attempts = 0
+	resp, err = client.Post("https://forms.example.gov.au/down.pdf", "text/plain", io.LimitReader(strings.NewReader("x"), 1))
+	if err != nil || attempts != 1 {
+		t.Errorf("a body that cannot be replayed should not be retried: %v after %d attempts", err, attempts)
+	}
+}
+
+func TestPoliteTransport_RetryAfter(t *testing.T) {
+	var times []time.Time
+	transport := &PoliteTransport{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			times = append(times, time.Now())
+			if len(times) == 1 {
+				return reply(http.StatusTooManyRequests, "Retry-After", "1"), nil
+			}
+			return reply(http.StatusOK), nil
+		}),
+		Policy: PolitenessPolicy{HostInterval: -1, BaseBackoff: time.Millisecond},
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get("https://forms.example.gov.au/f.pdf")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Get: %v", err)
+	}
+	if gap := times[1].Sub(times[0]); gap < 900*time.Millisecond {
+		t.Errorf("retried after %v, want Retry-After's 1s", gap)
+	}
+
+	// A Retry-After longer than MaxBackoff is returned, not waited for
+	times = nil
+	transport.Base = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		times = append(times, time.Now())
+		return reply(http.StatusTooManyRequests, "Retry-After", "3600"), nil
+	})
+	resp, err = (&http.Client{Transport: transport}).Get("https://forms.example.gov.au/f.pdf")
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests || len(times) != 1 {
+		t.Errorf("got %v, HTTP %d after %d attempts, want 429 after 1", err, resp.StatusCode, len(times))
+	}
+}
+
+func TestPoliteTransport_SpacesRequestsPerHost(t *testing.T) {
+	var mu sync.Mutex
+	times := make(map[string][]time.Time)
+	transport := &PoliteTransport{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			times[req.URL.Host] = append(times[req.URL.Host], time.Now())
+			mu.Unlock()
+			return reply(http.StatusOK), nil
+		}),
+		Policy: PolitenessPolicy{HostInterval: 50 * time.Millisecond},
+	}
+	client := &http.Client{Transport: transport}
+
+	var wg sync.WaitGroup
+	for _, host := range []string{"a.example.gov.au", "a.example.gov.au", "a.example.gov.au", "b.example.gov.au", "127.0.0.1:8080", "127.0.0.1:8080"} {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			if _, err := client.Get("https://" + host + "/f.pdf"); err != nil {
+				t.Errorf("Get %s: %v", host, err)
+			}
+		}(host)
+	}
+	wg.Wait()
+
+	a := times["a.example.gov.au"]
+	if len(a) != 3 {
+		t.Fatalf("a.example.gov.au got %d requests", len(a))
+	}
+	if span := latest(a).Sub(earliest(a)); span < 90*time.Millisecond {
+		t.Errorf("3 requests to one host spanned %v, want at least 2 intervals", span)
+	}
+	if local := times["127.0.0.1:8080"]; latest(local).Sub(earliest(local)) > 40*time.Millisecond {
+		t.Error("loopback requests should not be spaced out")
+	}
+}
+
+func earliest(ts []time.Time) time.Time {
+	min := ts[0]
+	for _, t := range ts {
+		if t.Before(min) {
+			min = t
+		}
+	}
+	return min
+}
+
+func latest(ts []time.Time) time.Time {
+	max := ts[0]
+	for _, t := range ts {
+		if t.After(max) {
+			max = t
+		}
+	}
+	return max
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Hour).Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := PolitenessPolicy{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, w := range want {
+		if got := backoff(policy, attempt); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, w)
+		}
+	}
+}
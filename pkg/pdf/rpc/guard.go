@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/pdf/web/httputil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// GuardOptions returns server options applying cfg to every call as
+// httputil.Guard does to the HTTP API: a per-client rate limit (checked
+// first, so guessing tokens is limited too), then "authorization: Bearer
+// <token>" metadata when cfg.APIToken is set. CORS does not apply to gRPC.
+func GuardOptions(cfg httputil.GuardConfig) []grpc.ServerOption {
+	var limiter *httputil.Limiter
+	if cfg.RateLimitRPS > 0 {
+		limiter = httputil.NewLimiter(cfg.RateLimitRPS)
+	}
+	check := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if limiter != nil {
+			var remote string
+			if p, ok := peer.FromContext(ctx); ok {
+				remote = p.Addr.String()
+			}
+			proxy := http.Header{
+				"Fly-Client-Ip":   md.Get("fly-client-ip"),
+				"X-Forwarded-For": md.Get("x-forwarded-for"),
+			}
+			client := httputil.ClientAddr(remote, proxy, cfg.TrustProxy)
+			if allowed, wait := limiter.Allow(client); !allowed {
+				grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(wait.Seconds())))))
+				return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+			}
+		}
+		if cfg.APIToken != "" && !httputil.ValidToken(strings.Join(md.Get("authorization"), ""), cfg.APIToken) {
+			return status.Error(codes.Unauthenticated, "unauthorized")
+		}
+		return nil
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := check(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := check(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
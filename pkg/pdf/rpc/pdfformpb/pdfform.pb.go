@@ -0,0 +1,1408 @@
+// gRPC interface to the pdfform commands layer, served next to the HTTP API
+// by `pdfform serve --grpc-port`. Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: rpc/pdfformpb/pdfform.proto
+
+package pdfformpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Form struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	State           string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	FormName        string                 `protobuf:"bytes,2,opt,name=form_name,json=formName,proto3" json:"form_name,omitempty"`
+	FormCode        string                 `protobuf:"bytes,3,opt,name=form_code,json=formCode,proto3" json:"form_code,omitempty"`
+	Description     string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Format          string                 `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`
+	DirectPdfUrl    string                 `protobuf:"bytes,6,opt,name=direct_pdf_url,json=directPdfUrl,proto3" json:"direct_pdf_url,omitempty"`
+	InfoUrl         string                 `protobuf:"bytes,7,opt,name=info_url,json=infoUrl,proto3" json:"info_url,omitempty"`
+	OnlineAvailable bool                   `protobuf:"varint,8,opt,name=online_available,json=onlineAvailable,proto3" json:"online_available,omitempty"`
+	Notes           string                 `protobuf:"bytes,9,opt,name=notes,proto3" json:"notes,omitempty"`
+	MirrorUrls      []string               `protobuf:"bytes,10,rep,name=mirror_urls,json=mirrorUrls,proto3" json:"mirror_urls,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Form) Reset() {
+	*x = Form{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Form) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Form) ProtoMessage() {}
+
+func (x *Form) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Form.ProtoReflect.Descriptor instead.
+func (*Form) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Form) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Form) GetFormName() string {
+	if x != nil {
+		return x.FormName
+	}
+	return ""
+}
+
+func (x *Form) GetFormCode() string {
+	if x != nil {
+		return x.FormCode
+	}
+	return ""
+}
+
+func (x *Form) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Form) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *Form) GetDirectPdfUrl() string {
+	if x != nil {
+		return x.DirectPdfUrl
+	}
+	return ""
+}
+
+func (x *Form) GetInfoUrl() string {
+	if x != nil {
+		return x.InfoUrl
+	}
+	return ""
+}
+
+func (x *Form) GetOnlineAvailable() bool {
+	if x != nil {
+		return x.OnlineAvailable
+	}
+	return false
+}
+
+func (x *Form) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Form) GetMirrorUrls() []string {
+	if x != nil {
+		return x.MirrorUrls
+	}
+	return nil
+}
+
+type BrowseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty lists the states
+	State         string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BrowseRequest) Reset() {
+	*x = BrowseRequest{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BrowseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrowseRequest) ProtoMessage() {}
+
+func (x *BrowseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrowseRequest.ProtoReflect.Descriptor instead.
+func (*BrowseRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BrowseRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type BrowseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	States        []string               `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+	Forms         []*Form                `protobuf:"bytes,2,rep,name=forms,proto3" json:"forms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BrowseResponse) Reset() {
+	*x = BrowseResponse{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BrowseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrowseResponse) ProtoMessage() {}
+
+func (x *BrowseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrowseResponse.ProtoReflect.Descriptor instead.
+func (*BrowseResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BrowseResponse) GetStates() []string {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *BrowseResponse) GetForms() []*Form {
+	if x != nil {
+		return x.Forms
+	}
+	return nil
+}
+
+// Progress is one step of a streaming operation (the commands layer events)
+type Progress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         string                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`         // e.g. "download.progress"
+	Stage         string                 `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`         // e.g. "downloading"
+	Progress      float64                `protobuf:"fixed64,3,opt,name=progress,proto3" json:"progress,omitempty"` // 0.0 - 1.0
+	Time          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Progress) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *Progress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Progress) GetProgress() float64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *Progress) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type DownloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FormCode      string                 `protobuf:"bytes,1,opt,name=form_code,json=formCode,proto3" json:"form_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadRequest) Reset() {
+	*x = DownloadRequest{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadRequest) ProtoMessage() {}
+
+func (x *DownloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadRequest.ProtoReflect.Descriptor instead.
+func (*DownloadRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DownloadRequest) GetFormCode() string {
+	if x != nil {
+		return x.FormCode
+	}
+	return ""
+}
+
+type DownloadResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PdfPath       string                 `protobuf:"bytes,1,opt,name=pdf_path,json=pdfPath,proto3" json:"pdf_path,omitempty"`
+	Form          *Form                  `protobuf:"bytes,2,opt,name=form,proto3" json:"form,omitempty"`
+	MetadataPath  string                 `protobuf:"bytes,3,opt,name=metadata_path,json=metadataPath,proto3" json:"metadata_path,omitempty"`
+	CacheStatus   string                 `protobuf:"bytes,4,opt,name=cache_status,json=cacheStatus,proto3" json:"cache_status,omitempty"` // hit, revalidated, miss, refreshed (empty when not cached)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadResult) Reset() {
+	*x = DownloadResult{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadResult) ProtoMessage() {}
+
+func (x *DownloadResult) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadResult.ProtoReflect.Descriptor instead.
+func (*DownloadResult) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DownloadResult) GetPdfPath() string {
+	if x != nil {
+		return x.PdfPath
+	}
+	return ""
+}
+
+func (x *DownloadResult) GetForm() *Form {
+	if x != nil {
+		return x.Form
+	}
+	return nil
+}
+
+func (x *DownloadResult) GetMetadataPath() string {
+	if x != nil {
+		return x.MetadataPath
+	}
+	return ""
+}
+
+func (x *DownloadResult) GetCacheStatus() string {
+	if x != nil {
+		return x.CacheStatus
+	}
+	return ""
+}
+
+type DownloadEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*DownloadEvent_Progress
+	//	*DownloadEvent_Result
+	Event         isDownloadEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadEvent) Reset() {
+	*x = DownloadEvent{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadEvent) ProtoMessage() {}
+
+func (x *DownloadEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadEvent.ProtoReflect.Descriptor instead.
+func (*DownloadEvent) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DownloadEvent) GetEvent() isDownloadEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *DownloadEvent) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Event.(*DownloadEvent_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *DownloadEvent) GetResult() *DownloadResult {
+	if x != nil {
+		if x, ok := x.Event.(*DownloadEvent_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isDownloadEvent_Event interface {
+	isDownloadEvent_Event()
+}
+
+type DownloadEvent_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type DownloadEvent_Result struct {
+	Result *DownloadResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"` // Last message of the stream
+}
+
+func (*DownloadEvent_Progress) isDownloadEvent_Event() {}
+
+func (*DownloadEvent_Result) isDownloadEvent_Event() {}
+
+// Passwords unlock an encrypted PDF
+type Passwords struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Owner         string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Passwords) Reset() {
+	*x = Passwords{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Passwords) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Passwords) ProtoMessage() {}
+
+func (x *Passwords) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Passwords.ProtoReflect.Descriptor instead.
+func (*Passwords) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Passwords) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Passwords) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+type InspectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PdfPath       string                 `protobuf:"bytes,1,opt,name=pdf_path,json=pdfPath,proto3" json:"pdf_path,omitempty"` // Relative paths are resolved in the downloads directory
+	Password      *Passwords             `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InspectRequest) Reset() {
+	*x = InspectRequest{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InspectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectRequest) ProtoMessage() {}
+
+func (x *InspectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectRequest.ProtoReflect.Descriptor instead.
+func (*InspectRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *InspectRequest) GetPdfPath() string {
+	if x != nil {
+		return x.PdfPath
+	}
+	return ""
+}
+
+func (x *InspectRequest) GetPassword() *Passwords {
+	if x != nil {
+		return x.Password
+	}
+	return nil
+}
+
+type InspectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplatePath  string                 `protobuf:"bytes,1,opt,name=template_path,json=templatePath,proto3" json:"template_path,omitempty"`
+	FieldCount    int32                  `protobuf:"varint,2,opt,name=field_count,json=fieldCount,proto3" json:"field_count,omitempty"`
+	Fields        []string               `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InspectResponse) Reset() {
+	*x = InspectResponse{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InspectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectResponse) ProtoMessage() {}
+
+func (x *InspectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectResponse.ProtoReflect.Descriptor instead.
+func (*InspectResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *InspectResponse) GetTemplatePath() string {
+	if x != nil {
+		return x.TemplatePath
+	}
+	return ""
+}
+
+func (x *InspectResponse) GetFieldCount() int32 {
+	if x != nil {
+		return x.FieldCount
+	}
+	return 0
+}
+
+func (x *InspectResponse) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type FillRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CaseId          string                 `protobuf:"bytes,1,opt,name=case_id,json=caseId,proto3" json:"case_id,omitempty"`
+	Flatten         bool                   `protobuf:"varint,2,opt,name=flatten,proto3" json:"flatten,omitempty"`
+	Password        *Passwords             `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	ProtectPassword string                 `protobuf:"bytes,4,opt,name=protect_password,json=protectPassword,proto3" json:"protect_password,omitempty"` // Re-protect the output with this password
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FillRequest) Reset() {
+	*x = FillRequest{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FillRequest) ProtoMessage() {}
+
+func (x *FillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FillRequest.ProtoReflect.Descriptor instead.
+func (*FillRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FillRequest) GetCaseId() string {
+	if x != nil {
+		return x.CaseId
+	}
+	return ""
+}
+
+func (x *FillRequest) GetFlatten() bool {
+	if x != nil {
+		return x.Flatten
+	}
+	return false
+}
+
+func (x *FillRequest) GetPassword() *Passwords {
+	if x != nil {
+		return x.Password
+	}
+	return nil
+}
+
+func (x *FillRequest) GetProtectPassword() string {
+	if x != nil {
+		return x.ProtectPassword
+	}
+	return ""
+}
+
+type FillResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OutputPath    string                 `protobuf:"bytes,1,opt,name=output_path,json=outputPath,proto3" json:"output_path,omitempty"`
+	InputPdf      string                 `protobuf:"bytes,2,opt,name=input_pdf,json=inputPdf,proto3" json:"input_pdf,omitempty"`
+	Flattened     bool                   `protobuf:"varint,3,opt,name=flattened,proto3" json:"flattened,omitempty"`
+	Stamped       bool                   `protobuf:"varint,4,opt,name=stamped,proto3" json:"stamped,omitempty"`
+	Pdfa          bool                   `protobuf:"varint,5,opt,name=pdfa,proto3" json:"pdfa,omitempty"`
+	Protected     bool                   `protobuf:"varint,6,opt,name=protected,proto3" json:"protected,omitempty"`
+	Warnings      []string               `protobuf:"bytes,7,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FillResult) Reset() {
+	*x = FillResult{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FillResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FillResult) ProtoMessage() {}
+
+func (x *FillResult) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FillResult.ProtoReflect.Descriptor instead.
+func (*FillResult) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FillResult) GetOutputPath() string {
+	if x != nil {
+		return x.OutputPath
+	}
+	return ""
+}
+
+func (x *FillResult) GetInputPdf() string {
+	if x != nil {
+		return x.InputPdf
+	}
+	return ""
+}
+
+func (x *FillResult) GetFlattened() bool {
+	if x != nil {
+		return x.Flattened
+	}
+	return false
+}
+
+func (x *FillResult) GetStamped() bool {
+	if x != nil {
+		return x.Stamped
+	}
+	return false
+}
+
+func (x *FillResult) GetPdfa() bool {
+	if x != nil {
+		return x.Pdfa
+	}
+	return false
+}
+
+func (x *FillResult) GetProtected() bool {
+	if x != nil {
+		return x.Protected
+	}
+	return false
+}
+
+func (x *FillResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type FillEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*FillEvent_Progress
+	//	*FillEvent_Result
+	Event         isFillEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FillEvent) Reset() {
+	*x = FillEvent{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FillEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FillEvent) ProtoMessage() {}
+
+func (x *FillEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FillEvent.ProtoReflect.Descriptor instead.
+func (*FillEvent) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FillEvent) GetEvent() isFillEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *FillEvent) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Event.(*FillEvent_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *FillEvent) GetResult() *FillResult {
+	if x != nil {
+		if x, ok := x.Event.(*FillEvent_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isFillEvent_Event interface {
+	isFillEvent_Event()
+}
+
+type FillEvent_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type FillEvent_Result struct {
+	Result *FillResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"` // Last message of the stream
+}
+
+func (*FillEvent_Progress) isFillEvent_Event() {}
+
+func (*FillEvent_Result) isFillEvent_Event() {}
+
+type Case struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CaseId        string                 `protobuf:"bytes,1,opt,name=case_id,json=caseId,proto3" json:"case_id,omitempty"`
+	CaseName      string                 `protobuf:"bytes,2,opt,name=case_name,json=caseName,proto3" json:"case_name,omitempty"`
+	FormCode      string                 `protobuf:"bytes,3,opt,name=form_code,json=formCode,proto3" json:"form_code,omitempty"`
+	Path          string                 `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Revision      int32                  `protobuf:"varint,7,opt,name=revision,proto3" json:"revision,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,8,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Case) Reset() {
+	*x = Case{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Case) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Case) ProtoMessage() {}
+
+func (x *Case) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Case.ProtoReflect.Descriptor instead.
+func (*Case) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Case) GetCaseId() string {
+	if x != nil {
+		return x.CaseId
+	}
+	return ""
+}
+
+func (x *Case) GetCaseName() string {
+	if x != nil {
+		return x.CaseName
+	}
+	return ""
+}
+
+func (x *Case) GetFormCode() string {
+	if x != nil {
+		return x.FormCode
+	}
+	return ""
+}
+
+func (x *Case) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Case) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Case) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Case) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *Case) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ListCasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entity        string                 `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCasesRequest) Reset() {
+	*x = ListCasesRequest{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCasesRequest) ProtoMessage() {}
+
+func (x *ListCasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCasesRequest.ProtoReflect.Descriptor instead.
+func (*ListCasesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListCasesRequest) GetEntity() string {
+	if x != nil {
+		return x.Entity
+	}
+	return ""
+}
+
+type ListCasesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cases         []*Case                `protobuf:"bytes,1,rep,name=cases,proto3" json:"cases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCasesResponse) Reset() {
+	*x = ListCasesResponse{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCasesResponse) ProtoMessage() {}
+
+func (x *ListCasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCasesResponse.ProtoReflect.Descriptor instead.
+func (*ListCasesResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListCasesResponse) GetCases() []*Case {
+	if x != nil {
+		return x.Cases
+	}
+	return nil
+}
+
+type CreateCaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FormCode      string                 `protobuf:"bytes,1,opt,name=form_code,json=formCode,proto3" json:"form_code,omitempty"`
+	CaseName      string                 `protobuf:"bytes,2,opt,name=case_name,json=caseName,proto3" json:"case_name,omitempty"`
+	EntityName    string                 `protobuf:"bytes,3,opt,name=entity_name,json=entityName,proto3" json:"entity_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCaseRequest) Reset() {
+	*x = CreateCaseRequest{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCaseRequest) ProtoMessage() {}
+
+func (x *CreateCaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCaseRequest.ProtoReflect.Descriptor instead.
+func (*CreateCaseRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateCaseRequest) GetFormCode() string {
+	if x != nil {
+		return x.FormCode
+	}
+	return ""
+}
+
+func (x *CreateCaseRequest) GetCaseName() string {
+	if x != nil {
+		return x.CaseName
+	}
+	return ""
+}
+
+func (x *CreateCaseRequest) GetEntityName() string {
+	if x != nil {
+		return x.EntityName
+	}
+	return ""
+}
+
+type GetCaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CaseId        string                 `protobuf:"bytes,1,opt,name=case_id,json=caseId,proto3" json:"case_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCaseRequest) Reset() {
+	*x = GetCaseRequest{}
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCaseRequest) ProtoMessage() {}
+
+func (x *GetCaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_pdfformpb_pdfform_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCaseRequest.ProtoReflect.Descriptor instead.
+func (*GetCaseRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_pdfformpb_pdfform_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetCaseRequest) GetCaseId() string {
+	if x != nil {
+		return x.CaseId
+	}
+	return ""
+}
+
+var File_rpc_pdfformpb_pdfform_proto protoreflect.FileDescriptor
+
+const file_rpc_pdfformpb_pdfform_proto_rawDesc = "" +
+	"\n" +
+	"\x1brpc/pdfformpb/pdfform.proto\x12\n" +
+	"pdfform.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb3\x02\n" +
+	"\x04Form\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x1b\n" +
+	"\tform_name\x18\x02 \x01(\tR\bformName\x12\x1b\n" +
+	"\tform_code\x18\x03 \x01(\tR\bformCode\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06format\x18\x05 \x01(\tR\x06format\x12$\n" +
+	"\x0edirect_pdf_url\x18\x06 \x01(\tR\fdirectPdfUrl\x12\x19\n" +
+	"\binfo_url\x18\a \x01(\tR\ainfoUrl\x12)\n" +
+	"\x10online_available\x18\b \x01(\bR\x0fonlineAvailable\x12\x14\n" +
+	"\x05notes\x18\t \x01(\tR\x05notes\x12\x1f\n" +
+	"\vmirror_urls\x18\n" +
+	" \x03(\tR\n" +
+	"mirrorUrls\"%\n" +
+	"\rBrowseRequest\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\"P\n" +
+	"\x0eBrowseResponse\x12\x16\n" +
+	"\x06states\x18\x01 \x03(\tR\x06states\x12&\n" +
+	"\x05forms\x18\x02 \x03(\v2\x10.pdfform.v1.FormR\x05forms\"\x82\x01\n" +
+	"\bProgress\x12\x14\n" +
+	"\x05event\x18\x01 \x01(\tR\x05event\x12\x14\n" +
+	"\x05stage\x18\x02 \x01(\tR\x05stage\x12\x1a\n" +
+	"\bprogress\x18\x03 \x01(\x01R\bprogress\x12.\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\".\n" +
+	"\x0fDownloadRequest\x12\x1b\n" +
+	"\tform_code\x18\x01 \x01(\tR\bformCode\"\x99\x01\n" +
+	"\x0eDownloadResult\x12\x19\n" +
+	"\bpdf_path\x18\x01 \x01(\tR\apdfPath\x12$\n" +
+	"\x04form\x18\x02 \x01(\v2\x10.pdfform.v1.FormR\x04form\x12#\n" +
+	"\rmetadata_path\x18\x03 \x01(\tR\fmetadataPath\x12!\n" +
+	"\fcache_status\x18\x04 \x01(\tR\vcacheStatus\"\x82\x01\n" +
+	"\rDownloadEvent\x122\n" +
+	"\bprogress\x18\x01 \x01(\v2\x14.pdfform.v1.ProgressH\x00R\bprogress\x124\n" +
+	"\x06result\x18\x02 \x01(\v2\x1a.pdfform.v1.DownloadResultH\x00R\x06resultB\a\n" +
+	"\x05event\"5\n" +
+	"\tPasswords\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\"^\n" +
+	"\x0eInspectRequest\x12\x19\n" +
+	"\bpdf_path\x18\x01 \x01(\tR\apdfPath\x121\n" +
+	"\bpassword\x18\x02 \x01(\v2\x15.pdfform.v1.PasswordsR\bpassword\"o\n" +
+	"\x0fInspectResponse\x12#\n" +
+	"\rtemplate_path\x18\x01 \x01(\tR\ftemplatePath\x12\x1f\n" +
+	"\vfield_count\x18\x02 \x01(\x05R\n" +
+	"fieldCount\x12\x16\n" +
+	"\x06fields\x18\x03 \x03(\tR\x06fields\"\x9e\x01\n" +
+	"\vFillRequest\x12\x17\n" +
+	"\acase_id\x18\x01 \x01(\tR\x06caseId\x12\x18\n" +
+	"\aflatten\x18\x02 \x01(\bR\aflatten\x121\n" +
+	"\bpassword\x18\x03 \x01(\v2\x15.pdfform.v1.PasswordsR\bpassword\x12)\n" +
+	"\x10protect_password\x18\x04 \x01(\tR\x0fprotectPassword\"\xd0\x01\n" +
+	"\n" +
+	"FillResult\x12\x1f\n" +
+	"\voutput_path\x18\x01 \x01(\tR\n" +
+	"outputPath\x12\x1b\n" +
+	"\tinput_pdf\x18\x02 \x01(\tR\binputPdf\x12\x1c\n" +
+	"\tflattened\x18\x03 \x01(\bR\tflattened\x12\x18\n" +
+	"\astamped\x18\x04 \x01(\bR\astamped\x12\x12\n" +
+	"\x04pdfa\x18\x05 \x01(\bR\x04pdfa\x12\x1c\n" +
+	"\tprotected\x18\x06 \x01(\bR\tprotected\x12\x1a\n" +
+	"\bwarnings\x18\a \x03(\tR\bwarnings\"z\n" +
+	"\tFillEvent\x122\n" +
+	"\bprogress\x18\x01 \x01(\v2\x14.pdfform.v1.ProgressH\x00R\bprogress\x120\n" +
+	"\x06result\x18\x02 \x01(\v2\x16.pdfform.v1.FillResultH\x00R\x06resultB\a\n" +
+	"\x05event\"\xf0\x02\n" +
+	"\x04Case\x12\x17\n" +
+	"\acase_id\x18\x01 \x01(\tR\x06caseId\x12\x1b\n" +
+	"\tcase_name\x18\x02 \x01(\tR\bcaseName\x12\x1b\n" +
+	"\tform_code\x18\x03 \x01(\tR\bformCode\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\brevision\x18\a \x01(\x05R\brevision\x124\n" +
+	"\x06fields\x18\b \x03(\v2\x1c.pdfform.v1.Case.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"*\n" +
+	"\x10ListCasesRequest\x12\x16\n" +
+	"\x06entity\x18\x01 \x01(\tR\x06entity\";\n" +
+	"\x11ListCasesResponse\x12&\n" +
+	"\x05cases\x18\x01 \x03(\v2\x10.pdfform.v1.CaseR\x05cases\"n\n" +
+	"\x11CreateCaseRequest\x12\x1b\n" +
+	"\tform_code\x18\x01 \x01(\tR\bformCode\x12\x1b\n" +
+	"\tcase_name\x18\x02 \x01(\tR\bcaseName\x12\x1f\n" +
+	"\ventity_name\x18\x03 \x01(\tR\n" +
+	"entityName\")\n" +
+	"\x0eGetCaseRequest\x12\x17\n" +
+	"\acase_id\x18\x01 \x01(\tR\x06caseId2\xd0\x03\n" +
+	"\aPDFForm\x12?\n" +
+	"\x06Browse\x12\x19.pdfform.v1.BrowseRequest\x1a\x1a.pdfform.v1.BrowseResponse\x12D\n" +
+	"\bDownload\x12\x1b.pdfform.v1.DownloadRequest\x1a\x19.pdfform.v1.DownloadEvent0\x01\x12B\n" +
+	"\aInspect\x12\x1a.pdfform.v1.InspectRequest\x1a\x1b.pdfform.v1.InspectResponse\x128\n" +
+	"\x04Fill\x12\x17.pdfform.v1.FillRequest\x1a\x15.pdfform.v1.FillEvent0\x01\x12H\n" +
+	"\tListCases\x12\x1c.pdfform.v1.ListCasesRequest\x1a\x1d.pdfform.v1.ListCasesResponse\x12=\n" +
+	"\n" +
+	"CreateCase\x12\x1d.pdfform.v1.CreateCaseRequest\x1a\x10.pdfform.v1.Case\x127\n" +
+	"\aGetCase\x12\x1a.pdfform.v1.GetCaseRequest\x1a\x10.pdfform.v1.CaseB7Z5github.com/joeblew999/wellknown/pkg/pdf/rpc/pdfformpbb\x06proto3"
+
+var (
+	file_rpc_pdfformpb_pdfform_proto_rawDescOnce sync.Once
+	file_rpc_pdfformpb_pdfform_proto_rawDescData []byte
+)
+
+func file_rpc_pdfformpb_pdfform_proto_rawDescGZIP() []byte {
+	file_rpc_pdfformpb_pdfform_proto_rawDescOnce.Do(func() {
+		file_rpc_pdfformpb_pdfform_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_pdfformpb_pdfform_proto_rawDesc), len(file_rpc_pdfformpb_pdfform_proto_rawDesc)))
+	})
+	return file_rpc_pdfformpb_pdfform_proto_rawDescData
+}
+
+var file_rpc_pdfformpb_pdfform_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_rpc_pdfformpb_pdfform_proto_goTypes = []any{
+	(*Form)(nil),                  // 0: pdfform.v1.Form
+	(*BrowseRequest)(nil),         // 1: pdfform.v1.BrowseRequest
+	(*BrowseResponse)(nil),        // 2: pdfform.v1.BrowseResponse
+	(*Progress)(nil),              // 3: pdfform.v1.Progress
+	(*DownloadRequest)(nil),       // 4: pdfform.v1.DownloadRequest
+	(*DownloadResult)(nil),        // 5: pdfform.v1.DownloadResult
+	(*DownloadEvent)(nil),         // 6: pdfform.v1.DownloadEvent
+	(*Passwords)(nil),             // 7: pdfform.v1.Passwords
+	(*InspectRequest)(nil),        // 8: pdfform.v1.InspectRequest
+	(*InspectResponse)(nil),       // 9: pdfform.v1.InspectResponse
+	(*FillRequest)(nil),           // 10: pdfform.v1.FillRequest
+	(*FillResult)(nil),            // 11: pdfform.v1.FillResult
+	(*FillEvent)(nil),             // 12: pdfform.v1.FillEvent
+	(*Case)(nil),                  // 13: pdfform.v1.Case
+	(*ListCasesRequest)(nil),      // 14: pdfform.v1.ListCasesRequest
+	(*ListCasesResponse)(nil),     // 15: pdfform.v1.ListCasesResponse
+	(*CreateCaseRequest)(nil),     // 16: pdfform.v1.CreateCaseRequest
+	(*GetCaseRequest)(nil),        // 17: pdfform.v1.GetCaseRequest
+	nil,                           // 18: pdfform.v1.Case.FieldsEntry
+	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
+}
+var file_rpc_pdfformpb_pdfform_proto_depIdxs = []int32{
+	0,  // 0: pdfform.v1.BrowseResponse.forms:type_name -> pdfform.v1.Form
+	19, // 1: pdfform.v1.Progress.time:type_name -> google.protobuf.Timestamp
+	0,  // 2: pdfform.v1.DownloadResult.form:type_name -> pdfform.v1.Form
+	3,  // 3: pdfform.v1.DownloadEvent.progress:type_name -> pdfform.v1.Progress
+	5,  // 4: pdfform.v1.DownloadEvent.result:type_name -> pdfform.v1.DownloadResult
+	7,  // 5: pdfform.v1.InspectRequest.password:type_name -> pdfform.v1.Passwords
+	7,  // 6: pdfform.v1.FillRequest.password:type_name -> pdfform.v1.Passwords
+	3,  // 7: pdfform.v1.FillEvent.progress:type_name -> pdfform.v1.Progress
+	11, // 8: pdfform.v1.FillEvent.result:type_name -> pdfform.v1.FillResult
+	19, // 9: pdfform.v1.Case.created_at:type_name -> google.protobuf.Timestamp
+	19, // 10: pdfform.v1.Case.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 11: pdfform.v1.Case.fields:type_name -> pdfform.v1.Case.FieldsEntry
+	13, // 12: pdfform.v1.ListCasesResponse.cases:type_name -> pdfform.v1.Case
+	1,  // 13: pdfform.v1.PDFForm.Browse:input_type -> pdfform.v1.BrowseRequest
+	4,  // 14: pdfform.v1.PDFForm.Download:input_type -> pdfform.v1.DownloadRequest
+	8,  // 15: pdfform.v1.PDFForm.Inspect:input_type -> pdfform.v1.InspectRequest
+	10, // 16: pdfform.v1.PDFForm.Fill:input_type -> pdfform.v1.FillRequest
+	14, // 17: pdfform.v1.PDFForm.ListCases:input_type -> pdfform.v1.ListCasesRequest
+	16, // 18: pdfform.v1.PDFForm.CreateCase:input_type -> pdfform.v1.CreateCaseRequest
+	17, // 19: pdfform.v1.PDFForm.GetCase:input_type -> pdfform.v1.GetCaseRequest
+	2,  // 20: pdfform.v1.PDFForm.Browse:output_type -> pdfform.v1.BrowseResponse
+	6,  // 21: pdfform.v1.PDFForm.Download:output_type -> pdfform.v1.DownloadEvent
+	9,  // 22: pdfform.v1.PDFForm.Inspect:output_type -> pdfform.v1.InspectResponse
+	12, // 23: pdfform.v1.PDFForm.Fill:output_type -> pdfform.v1.FillEvent
+	15, // 24: pdfform.v1.PDFForm.ListCases:output_type -> pdfform.v1.ListCasesResponse
+	13, // 25: pdfform.v1.PDFForm.CreateCase:output_type -> pdfform.v1.Case
+	13, // 26: pdfform.v1.PDFForm.GetCase:output_type -> pdfform.v1.Case
+	20, // [20:27] is the sub-list for method output_type
+	13, // [13:20] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_rpc_pdfformpb_pdfform_proto_init() }
+func file_rpc_pdfformpb_pdfform_proto_init() {
+	if File_rpc_pdfformpb_pdfform_proto != nil {
+		return
+	}
+	file_rpc_pdfformpb_pdfform_proto_msgTypes[6].OneofWrappers = []any{
+		(*DownloadEvent_Progress)(nil),
+		(*DownloadEvent_Result)(nil),
+	}
+	file_rpc_pdfformpb_pdfform_proto_msgTypes[12].OneofWrappers = []any{
+		(*FillEvent_Progress)(nil),
+		(*FillEvent_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_pdfformpb_pdfform_proto_rawDesc), len(file_rpc_pdfformpb_pdfform_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_pdfformpb_pdfform_proto_goTypes,
+		DependencyIndexes: file_rpc_pdfformpb_pdfform_proto_depIdxs,
+		MessageInfos:      file_rpc_pdfformpb_pdfform_proto_msgTypes,
+	}.Build()
+	File_rpc_pdfformpb_pdfform_proto = out.File
+	file_rpc_pdfformpb_pdfform_proto_goTypes = nil
+	file_rpc_pdfformpb_pdfform_proto_depIdxs = nil
+}
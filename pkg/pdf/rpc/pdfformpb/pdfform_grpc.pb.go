@@ -0,0 +1,379 @@
+// gRPC interface to the pdfform commands layer, served next to the HTTP API
+// by `pdfform serve --grpc-port`. Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: rpc/pdfformpb/pdfform.proto
+
+package pdfformpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PDFForm_Browse_FullMethodName     = "/pdfform.v1.PDFForm/Browse"
+	PDFForm_Download_FullMethodName   = "/pdfform.v1.PDFForm/Download"
+	PDFForm_Inspect_FullMethodName    = "/pdfform.v1.PDFForm/Inspect"
+	PDFForm_Fill_FullMethodName       = "/pdfform.v1.PDFForm/Fill"
+	PDFForm_ListCases_FullMethodName  = "/pdfform.v1.PDFForm/ListCases"
+	PDFForm_CreateCase_FullMethodName = "/pdfform.v1.PDFForm/CreateCase"
+	PDFForm_GetCase_FullMethodName    = "/pdfform.v1.PDFForm/GetCase"
+)
+
+// PDFFormClient is the client API for PDFForm service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PDFForm browses the forms catalog, downloads and inspects forms, and fills
+// them from cases. Paths are relative to the server's data directory.
+type PDFFormClient interface {
+	// Browse lists the states, or the forms of one state
+	Browse(ctx context.Context, in *BrowseRequest, opts ...grpc.CallOption) (*BrowseResponse, error)
+	// Download fetches a form PDF, streaming progress and then the result
+	Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadEvent], error)
+	// Inspect extracts the fields of a downloaded PDF into a JSON template
+	Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error)
+	// Fill fills the PDF of a case, streaming progress and then the result
+	Fill(ctx context.Context, in *FillRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FillEvent], error)
+	// ListCases lists the cases, optionally of one entity
+	ListCases(ctx context.Context, in *ListCasesRequest, opts ...grpc.CallOption) (*ListCasesResponse, error)
+	// CreateCase creates a case for a form
+	CreateCase(ctx context.Context, in *CreateCaseRequest, opts ...grpc.CallOption) (*Case, error)
+	// GetCase returns a case by ID
+	GetCase(ctx context.Context, in *GetCaseRequest, opts ...grpc.CallOption) (*Case, error)
+}
+
+type pDFFormClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPDFFormClient(cc grpc.ClientConnInterface) PDFFormClient {
+	return &pDFFormClient{cc}
+}
+
+func (c *pDFFormClient) Browse(ctx context.Context, in *BrowseRequest, opts ...grpc.CallOption) (*BrowseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BrowseResponse)
+	err := c.cc.Invoke(ctx, PDFForm_Browse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pDFFormClient) Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PDFForm_ServiceDesc.Streams[0], PDFForm_Download_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadRequest, DownloadEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFForm_DownloadClient = grpc.ServerStreamingClient[DownloadEvent]
+
+func (c *pDFFormClient) Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InspectResponse)
+	err := c.cc.Invoke(ctx, PDFForm_Inspect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pDFFormClient) Fill(ctx context.Context, in *FillRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FillEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PDFForm_ServiceDesc.Streams[1], PDFForm_Fill_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FillRequest, FillEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFForm_FillClient = grpc.ServerStreamingClient[FillEvent]
+
+func (c *pDFFormClient) ListCases(ctx context.Context, in *ListCasesRequest, opts ...grpc.CallOption) (*ListCasesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCasesResponse)
+	err := c.cc.Invoke(ctx, PDFForm_ListCases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pDFFormClient) CreateCase(ctx context.Context, in *CreateCaseRequest, opts ...grpc.CallOption) (*Case, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Case)
+	err := c.cc.Invoke(ctx, PDFForm_CreateCase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pDFFormClient) GetCase(ctx context.Context, in *GetCaseRequest, opts ...grpc.CallOption) (*Case, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Case)
+	err := c.cc.Invoke(ctx, PDFForm_GetCase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PDFFormServer is the server API for PDFForm service.
+// All implementations must embed UnimplementedPDFFormServer
+// for forward compatibility.
+//
+// PDFForm browses the forms catalog, downloads and inspects forms, and fills
+// them from cases. Paths are relative to the server's data directory.
+type PDFFormServer interface {
+	// Browse lists the states, or the forms of one state
+	Browse(context.Context, *BrowseRequest) (*BrowseResponse, error)
+	// Download fetches a form PDF, streaming progress and then the result
+	Download(*DownloadRequest, grpc.ServerStreamingServer[DownloadEvent]) error
+	// Inspect extracts the fields of a downloaded PDF into a JSON template
+	Inspect(context.Context, *InspectRequest) (*InspectResponse, error)
+	// Fill fills the PDF of a case, streaming progress and then the result
+	Fill(*FillRequest, grpc.ServerStreamingServer[FillEvent]) error
+	// ListCases lists the cases, optionally of one entity
+	ListCases(context.Context, *ListCasesRequest) (*ListCasesResponse, error)
+	// CreateCase creates a case for a form
+	CreateCase(context.Context, *CreateCaseRequest) (*Case, error)
+	// GetCase returns a case by ID
+	GetCase(context.Context, *GetCaseRequest) (*Case, error)
+	mustEmbedUnimplementedPDFFormServer()
+}
+
+// UnimplementedPDFFormServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPDFFormServer struct{}
+
+func (UnimplementedPDFFormServer) Browse(context.Context, *BrowseRequest) (*BrowseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Browse not implemented")
+}
+func (UnimplementedPDFFormServer) Download(*DownloadRequest, grpc.ServerStreamingServer[DownloadEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedPDFFormServer) Inspect(context.Context, *InspectRequest) (*InspectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inspect not implemented")
+}
+func (UnimplementedPDFFormServer) Fill(*FillRequest, grpc.ServerStreamingServer[FillEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Fill not implemented")
+}
+func (UnimplementedPDFFormServer) ListCases(context.Context, *ListCasesRequest) (*ListCasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCases not implemented")
+}
+func (UnimplementedPDFFormServer) CreateCase(context.Context, *CreateCaseRequest) (*Case, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCase not implemented")
+}
+func (UnimplementedPDFFormServer) GetCase(context.Context, *GetCaseRequest) (*Case, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCase not implemented")
+}
+func (UnimplementedPDFFormServer) mustEmbedUnimplementedPDFFormServer() {}
+func (UnimplementedPDFFormServer) testEmbeddedByValue()                 {}
+
+// UnsafePDFFormServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PDFFormServer will
+// result in compilation errors.
+type UnsafePDFFormServer interface {
+	mustEmbedUnimplementedPDFFormServer()
+}
+
+func RegisterPDFFormServer(s grpc.ServiceRegistrar, srv PDFFormServer) {
+	// If the following call pancis, it indicates UnimplementedPDFFormServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PDFForm_ServiceDesc, srv)
+}
+
+func _PDFForm_Browse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrowseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PDFFormServer).Browse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PDFForm_Browse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PDFFormServer).Browse(ctx, req.(*BrowseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PDFForm_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PDFFormServer).Download(m, &grpc.GenericServerStream[DownloadRequest, DownloadEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFForm_DownloadServer = grpc.ServerStreamingServer[DownloadEvent]
+
+func _PDFForm_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PDFFormServer).Inspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PDFForm_Inspect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PDFFormServer).Inspect(ctx, req.(*InspectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PDFForm_Fill_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FillRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PDFFormServer).Fill(m, &grpc.GenericServerStream[FillRequest, FillEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFForm_FillServer = grpc.ServerStreamingServer[FillEvent]
+
+func _PDFForm_ListCases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PDFFormServer).ListCases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PDFForm_ListCases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PDFFormServer).ListCases(ctx, req.(*ListCasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PDFForm_CreateCase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PDFFormServer).CreateCase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PDFForm_CreateCase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PDFFormServer).CreateCase(ctx, req.(*CreateCaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PDFForm_GetCase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PDFFormServer).GetCase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PDFForm_GetCase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PDFFormServer).GetCase(ctx, req.(*GetCaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PDFForm_ServiceDesc is the grpc.ServiceDesc for PDFForm service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PDFForm_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pdfform.v1.PDFForm",
+	HandlerType: (*PDFFormServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Browse",
+			Handler:    _PDFForm_Browse_Handler,
+		},
+		{
+			MethodName: "Inspect",
+			Handler:    _PDFForm_Inspect_Handler,
+		},
+		{
+			MethodName: "ListCases",
+			Handler:    _PDFForm_ListCases_Handler,
+		},
+		{
+			MethodName: "CreateCase",
+			Handler:    _PDFForm_CreateCase_Handler,
+		},
+		{
+			MethodName: "GetCase",
+			Handler:    _PDFForm_GetCase_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Download",
+			Handler:       _PDFForm_Download_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Fill",
+			Handler:       _PDFForm_Fill_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/pdfformpb/pdfform.proto",
+}
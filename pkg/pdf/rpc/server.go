@@ -0,0 +1,370 @@
+// Package rpc serves the commands layer over gRPC (see pdfformpb/pdfform.proto),
+// for pipelines that are not written in Go. It mirrors the HTTP API in
+// web/api: same operations, same data directory, same PDF_API_TOKEN and rate
+// limit (GuardOptions), and Download and Fill stream the commands layer's
+// progress events before their result.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"path/filepath"
+	"strings"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+	"github.com/joeblew999/wellknown/pkg/pdf/commands"
+	"github.com/joeblew999/wellknown/pkg/pdf/rpc/pdfformpb"
+	"github.com/joeblew999/wellknown/pkg/pdf/web/httputil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements pdfformpb.PDFFormServer on top of the commands layer
+type Server struct {
+	pdfformpb.UnimplementedPDFFormServer
+	config *pdfform.Config
+}
+
+// NewServer creates a gRPC service using config's paths
+func NewServer(config *pdfform.Config) *Server {
+	return &Server{config: config}
+}
+
+// NewGRPCServer returns a grpc.Server with the service and server reflection
+// (for grpcurl and similar tools) registered
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	gs := grpc.NewServer(opts...)
+	pdfformpb.RegisterPDFFormServer(gs, s)
+	reflection.Register(gs)
+	return gs
+}
+
+// ListenAndServe serves the service on port of every interface, over TLS
+// with the server's certificates when https is true. Calls are guarded as the
+// HTTP API is (httputil.GuardConfigFromEnv).
+func ListenAndServe(port int, config *pdfform.Config, https bool) error {
+	opts := GuardOptions(httputil.GuardConfigFromEnv())
+	if https {
+		creds, err := credentials.NewServerTLSFromFile(config.CertFilePath(), config.KeyFilePath())
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificates: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %d: %w", port, err)
+	}
+	return NewServer(config).NewGRPCServer(opts...).Serve(lis)
+}
+
+// Browse lists the states, or the forms of one state
+func (s *Server) Browse(ctx context.Context, req *pdfformpb.BrowseRequest) (*pdfformpb.BrowseResponse, error) {
+	result, err := commands.Browse(commands.BrowseOptions{
+		CatalogPath: s.config.CatalogFilePath(),
+		State:       req.GetState(),
+	})
+	if err != nil {
+		return nil, statusError("Browse", err)
+	}
+
+	resp := &pdfformpb.BrowseResponse{States: result.States}
+	for i := range result.Forms {
+		resp.Forms = append(resp.Forms, formToPB(&result.Forms[i]))
+	}
+	return resp, nil
+}
+
+// Download fetches a form PDF, streaming download.* progress
+func (s *Server) Download(req *pdfformpb.DownloadRequest, stream pdfformpb.PDFForm_DownloadServer) error {
+	if req.GetFormCode() == "" {
+		return status.Error(codes.InvalidArgument, "form_code is required")
+	}
+
+	var result *commands.DownloadResult
+	err := runWithProgress(stream.Context(), "download.*", "form_code", req.GetFormCode(),
+		func() (err error) {
+			result, err = commands.Download(commands.DownloadOptions{
+				CatalogPath: s.config.CatalogFilePath(),
+				FormCode:    req.GetFormCode(),
+				OutputDir:   s.config.DownloadsPath(),
+				CacheDir:    s.config.CachePath(),
+			})
+			return err
+		},
+		func(p *pdfformpb.Progress) error {
+			return stream.Send(&pdfformpb.DownloadEvent{Event: &pdfformpb.DownloadEvent_Progress{Progress: p}})
+		})
+	if err != nil {
+		return statusError("Download", err)
+	}
+
+	return stream.Send(&pdfformpb.DownloadEvent{Event: &pdfformpb.DownloadEvent_Result{Result: &pdfformpb.DownloadResult{
+		PdfPath:      result.PDFPath,
+		Form:         formToPB(result.Form),
+		MetadataPath: result.Metadata,
+		CacheStatus:  string(result.CacheStatus),
+	}}})
+}
+
+// Inspect extracts the fields of a PDF into a JSON template
+func (s *Server) Inspect(ctx context.Context, req *pdfformpb.InspectRequest) (*pdfformpb.InspectResponse, error) {
+	if req.GetPdfPath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "pdf_path is required")
+	}
+
+	pdfPath := req.GetPdfPath()
+	// If relative path, make it relative to downloads dir
+	if !filepath.IsAbs(pdfPath) {
+		pdfPath = filepath.Join(s.config.DownloadsPath(), pdfPath)
+	}
+	if !s.config.InDataDir(pdfPath) {
+		return nil, status.Error(codes.PermissionDenied, "pdf_path must be inside the data directory")
+	}
+
+	result, err := commands.Inspect(commands.InspectOptions{
+		PDFPath:   pdfPath,
+		OutputDir: s.config.TemplatesPath(),
+		Password:  passwordsFromPB(req.GetPassword()),
+	})
+	if err != nil {
+		return nil, statusError("Inspect", err)
+	}
+
+	return &pdfformpb.InspectResponse{
+		TemplatePath: result.TemplatePath,
+		FieldCount:   int32(result.FieldCount),
+		Fields:       result.Fields,
+	}, nil
+}
+
+// Fill fills the PDF of a case, streaming fill.* progress
+func (s *Server) Fill(req *pdfformpb.FillRequest, stream pdfformpb.PDFForm_FillServer) error {
+	casePath, err := s.findCase(req.GetCaseId())
+	if err != nil {
+		return err
+	}
+
+	var protect *pdfform.Protection
+	if req.GetProtectPassword() != "" {
+		protect = &pdfform.Protection{UserPassword: req.GetProtectPassword()}
+	}
+
+	var result *commands.FillResult
+	err = runWithProgress(stream.Context(), "fill.*", "case_path", casePath,
+		func() (err error) {
+			result, err = commands.FillFromCaseWithOptions(commands.FillFromCaseOptions{
+				CasePath:  casePath,
+				OutputDir: s.config.OutputsPath(),
+				Flatten:   req.GetFlatten(),
+				Password:  passwordsFromPB(req.GetPassword()),
+				Protect:   protect,
+
+				CatalogPath: s.config.CatalogFilePath(),
+			})
+			return err
+		},
+		func(p *pdfformpb.Progress) error {
+			return stream.Send(&pdfformpb.FillEvent{Event: &pdfformpb.FillEvent_Progress{Progress: p}})
+		})
+	if err != nil {
+		return statusError("Fill", err)
+	}
+
+	return stream.Send(&pdfformpb.FillEvent{Event: &pdfformpb.FillEvent_Result{Result: &pdfformpb.FillResult{
+		OutputPath: result.OutputPath,
+		InputPdf:   result.InputPDF,
+		Flattened:  result.Flattened,
+		Stamped:    result.Stamped,
+		Pdfa:       result.PDFA,
+		Protected:  result.Protected,
+		Warnings:   result.Warnings,
+	}}})
+}
+
+// ListCases lists the cases, optionally of one entity
+func (s *Server) ListCases(ctx context.Context, req *pdfformpb.ListCasesRequest) (*pdfformpb.ListCasesResponse, error) {
+	paths, err := commands.ListCases(s.config.DataDir, req.GetEntity())
+	if err != nil {
+		return nil, statusError("ListCases", err)
+	}
+
+	resp := &pdfformpb.ListCasesResponse{}
+	for _, casePath := range paths {
+		c, err := commands.LoadCase(casePath)
+		if err != nil {
+			log.Printf("Failed to load case %s: %v", casePath, err)
+			continue
+		}
+		resp.Cases = append(resp.Cases, caseToPB(c, casePath, false))
+	}
+	return resp, nil
+}
+
+// CreateCase creates a case for a form
+func (s *Server) CreateCase(ctx context.Context, req *pdfformpb.CreateCaseRequest) (*pdfformpb.Case, error) {
+	switch {
+	case req.GetFormCode() == "":
+		return nil, status.Error(codes.InvalidArgument, "form_code is required")
+	case req.GetCaseName() == "":
+		return nil, status.Error(codes.InvalidArgument, "case_name is required")
+	case req.GetEntityName() == "":
+		return nil, status.Error(codes.InvalidArgument, "entity_name is required")
+	}
+
+	c, casePath, err := commands.CreateCaseWithOptions(commands.CreateCaseOptions{
+		FormCode:    req.GetFormCode(),
+		CaseName:    req.GetCaseName(),
+		EntityName:  req.GetEntityName(),
+		DataDir:     s.config.DataDir,
+		CatalogPath: s.config.CatalogFilePath(),
+		PDFPath:     filepath.Join(s.config.DownloadsPath(), strings.ToLower(req.GetFormCode())+".pdf"),
+	})
+	if err != nil {
+		return nil, statusError("CreateCase", err)
+	}
+	return caseToPB(c, casePath, true), nil
+}
+
+// GetCase returns a case with its fields
+func (s *Server) GetCase(ctx context.Context, req *pdfformpb.GetCaseRequest) (*pdfformpb.Case, error) {
+	casePath, err := s.findCase(req.GetCaseId())
+	if err != nil {
+		return nil, err
+	}
+	c, err := commands.LoadCase(casePath)
+	if err != nil {
+		return nil, statusError("GetCase", err)
+	}
+	return caseToPB(c, casePath, true), nil
+}
+
+// findCase returns the path of case id, or a gRPC status error
+func (s *Server) findCase(id string) (string, error) {
+	if id == "" {
+		return "", status.Error(codes.InvalidArgument, "case_id is required")
+	}
+	casePath, err := commands.FindCaseByID(id, s.config.DataDir)
+	if err != nil {
+		return "", statusError("FindCase", err)
+	}
+	if casePath == "" {
+		return "", status.Error(codes.NotFound, "Case not found")
+	}
+	return casePath, nil
+}
+
+// runWithProgress runs fn while forwarding the events matching pattern whose
+// data[key] is value to send, then returns fn's error
+func runWithProgress(ctx context.Context, pattern, key, value string, fn func() error, send func(*pdfformpb.Progress) error) error {
+	events := commands.Subscribe(pattern)
+	defer commands.DefaultEventBus.Unsubscribe(events)
+
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+
+	forward := func(e *commands.Event) error {
+		if v, _ := e.Data[key].(string); v != value {
+			return nil
+		}
+		stage, _ := e.Data["stage"].(string)
+		progress, _ := e.Data["progress"].(float64)
+		return send(&pdfformpb.Progress{
+			Event:    string(e.Type),
+			Stage:    stage,
+			Progress: progress,
+			Time:     timestamppb.New(e.Timestamp),
+		})
+	}
+
+	for {
+		select {
+		case e := <-events:
+			if err := forward(e); err != nil {
+				<-done // Let the command finish; the client is gone
+				return err
+			}
+		case err := <-done:
+			// Drain what was published before the command returned
+			for {
+				select {
+				case e := <-events:
+					if sendErr := forward(e); sendErr != nil {
+						return sendErr
+					}
+				default:
+					return err
+				}
+			}
+		case <-ctx.Done():
+			<-done
+			return ctx.Err()
+		}
+	}
+}
+
+// statusError logs err and converts it to a gRPC status
+func statusError(op string, err error) error {
+	switch {
+	case errors.Is(err, pdfform.ErrPasswordRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, pdfform.ErrCaseConflict), errors.Is(err, pdfform.ErrCaseLocked):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	log.Printf("%s error: %v", op, err)
+	return status.Error(codes.Internal, err.Error())
+}
+
+func formToPB(f *pdfform.TransferForm) *pdfformpb.Form {
+	if f == nil {
+		return nil
+	}
+	return &pdfformpb.Form{
+		State:           f.State,
+		FormName:        f.FormName,
+		FormCode:        f.FormCode,
+		Description:     f.Description,
+		Format:          f.Format,
+		DirectPdfUrl:    f.DirectPDFURL,
+		InfoUrl:         f.InfoURL,
+		OnlineAvailable: f.OnlineAvailable,
+		Notes:           f.Notes,
+		MirrorUrls:      f.MirrorURLs,
+	}
+}
+
+// caseToPB converts a case; fields are only included when withFields is set
+func caseToPB(c *pdfform.Case, casePath string, withFields bool) *pdfformpb.Case {
+	pc := &pdfformpb.Case{
+		CaseId:    c.Metadata.CaseID,
+		CaseName:  c.Metadata.CaseName,
+		FormCode:  c.FormReference.FormCode,
+		Path:      casePath,
+		CreatedAt: timestamppb.New(c.Metadata.CreatedAt),
+		Revision:  int32(c.Metadata.Revision),
+	}
+	if !c.Metadata.UpdatedAt.IsZero() {
+		pc.UpdatedAt = timestamppb.New(c.Metadata.UpdatedAt)
+	}
+	if withFields {
+		pc.Fields = c.Fields
+	}
+	return pc
+}
+
+func passwordsFromPB(p *pdfformpb.Passwords) pdfform.Passwords {
+	return pdfform.Passwords{User: p.GetUser(), Owner: p.GetOwner()}
+}
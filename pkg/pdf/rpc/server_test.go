@@ -0,0 +1,365 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+	"github.com/joeblew999/wellknown/pkg/pdf/commands"
+	"github.com/joeblew999/wellknown/pkg/pdf/rpc/pdfformpb"
+	"github.com/joeblew999/wellknown/pkg/pdf/web/httputil"
+)
+
+// testFormJSON describes a one-page form with two text fields (pdfcpu create syntax)
+const testFormJSON = `{
+	"paper": "A4P",
+	"origin": "LowerLeft",
+	"fonts": {"input": {"name": "Helvetica", "size": 12}},
+	"pages": {
+		"1": {
+			"content": {
+				"textfield": [
+					{"id": "first_name", "value": "", "pos": [100, 700], "width": 200, "font": {"name": "$input"}},
+					{"id": "last_name", "value": "", "pos": [100, 650], "width": 200, "font": {"name": "$input"}}
+				]
+			}
+		}
+	}
+}`
+
+// startServer serves config's data over an in-memory connection
+func startServer(t *testing.T, config *pdfform.Config, opts ...grpc.ServerOption) (pdfformpb.PDFFormClient, *grpc.Server) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := NewServer(config).NewGRPCServer(opts...)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pdfformpb.NewPDFFormClient(conn), gs
+}
+
+// createCase writes a fillable PDF and a case filling it; protected
+// encrypts the PDF with an open password
+func createCase(t *testing.T, config *pdfform.Config, protected bool) *pdfform.Case {
+	t.Helper()
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "form.json")
+	pdfPath := filepath.Join(dir, "form.pdf")
+	if err := os.WriteFile(jsonPath, []byte(testFormJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.CreateFile("", jsonPath, pdfPath, model.NewDefaultConfiguration()); err != nil {
+		t.Skipf("Skipping: cannot generate test form: %v", err)
+	}
+	if protected {
+		encrypted := filepath.Join(dir, "protected.pdf")
+		if err := pdfform.ProtectPDF(pdfPath, encrypted, pdfform.Protection{UserPassword: "secret", OwnerPassword: "owner"}); err != nil {
+			t.Fatal(err)
+		}
+		pdfPath = encrypted
+	}
+
+	templatePath := filepath.Join(dir, "template.json")
+	template, _ := json.Marshal(pdfform.FormData{PdfURL: pdfPath, Fields: map[string]string{}})
+	if err := os.WriteFile(templatePath, template, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, casePath, err := pdfform.CreateCase("TEST1", "Grpc Test", "tester", config.DataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.FormReference.TemplatePath = templatePath
+	c.Fields = map[string]string{"first_name": "Jane", "last_name": "Doe"}
+	if err := pdfform.SaveCase(c, casePath); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestFill_StreamsProgress(t *testing.T) {
+	config := pdfform.NewConfig(t.TempDir())
+	c := createCase(t, config, false)
+	client, _ := startServer(t, config)
+
+	stream, err := client.Fill(context.Background(), &pdfformpb.FillRequest{CaseId: c.Metadata.CaseID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	var result *pdfformpb.FillResult
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if p := e.GetProgress(); p != nil {
+			events = append(events, p.GetEvent())
+		}
+		if r := e.GetResult(); r != nil {
+			result = r
+		}
+	}
+
+	if fmt.Sprint(events) != "[fill.started fill.completed]" {
+		t.Errorf("progress events = %v", events)
+	}
+	if result == nil {
+		t.Fatal("no result")
+	}
+	if _, err := os.Stat(result.GetOutputPath()); err != nil {
+		t.Errorf("output not written: %v", err)
+	}
+}
+
+func TestFill_PasswordRequired(t *testing.T) {
+	config := pdfform.NewConfig(t.TempDir())
+	c := createCase(t, config, true)
+	client, _ := startServer(t, config)
+
+	stream, err := client.Fill(context.Background(), &pdfformpb.FillRequest{CaseId: c.Metadata.CaseID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+	}
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("err = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestFill_ClientCancel(t *testing.T) {
+	config := pdfform.NewConfig(t.TempDir())
+	c := createCase(t, config, false)
+	client, gs := startServer(t, config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Fill(ctx, &pdfformpb.FillRequest{CaseId: c.Metadata.CaseID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("err = %v, want Canceled", err)
+	}
+
+	// GracefulStop waits for handlers: the Fill handler has returned
+	stopped := make(chan struct{})
+	go func() { gs.GracefulStop(); close(stopped) }()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Fill handler still running after the client cancelled")
+	}
+}
+
+func TestRunWithProgress_FiltersEvents(t *testing.T) {
+	// Fill filters on case_path, Download on form_code
+	for _, tt := range []struct {
+		pattern, key      string
+		started, progress commands.EventType
+		other             commands.EventType // Matches neither pattern
+	}{
+		{"fill.*", "case_path", commands.EventFillStarted, commands.EventFillProgress, commands.EventDownloadStarted},
+		{"download.*", "form_code", commands.EventDownloadStarted, commands.EventDownloadProgress, commands.EventFillStarted},
+	} {
+		var got []string
+		err := runWithProgress(context.Background(), tt.pattern, tt.key, "mine",
+			func() error {
+				commands.Emit(tt.started, map[string]interface{}{tt.key: "mine"})
+				commands.Emit(tt.started, map[string]interface{}{tt.key: "other"})
+				commands.Emit(tt.other, map[string]interface{}{tt.key: "mine"})
+				commands.Emit(tt.progress, map[string]interface{}{tt.key: "mine", "stage": "fetch", "progress": 0.5})
+				return nil
+			},
+			func(p *pdfformpb.Progress) error {
+				got = append(got, fmt.Sprintf("%s %s %.1f", p.GetEvent(), p.GetStage(), p.GetProgress()))
+				return nil
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("[%s  0.0 %s fetch 0.5]", tt.started, tt.progress); fmt.Sprint(got) != want {
+			t.Errorf("%s: forwarded %q, want %s", tt.pattern, got, want)
+		}
+	}
+}
+
+func TestRunWithProgress_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	returned := make(chan error, 1)
+	go func() {
+		returned <- runWithProgress(ctx, "fill.*", "case_path", "slow.json",
+			func() error {
+				<-release
+				close(finished)
+				return nil
+			},
+			func(*pdfformpb.Progress) error { return nil })
+	}()
+
+	cancel()
+	select {
+	case err := <-returned:
+		t.Fatalf("returned (%v) before the command finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-returned:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runWithProgress did not return after the command finished")
+	}
+	<-finished
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{fmt.Errorf("inspect: %w", pdfform.ErrPasswordRequired), codes.FailedPrecondition},
+		{fmt.Errorf("save: %w", pdfform.ErrCaseConflict), codes.Aborted},
+		{fmt.Errorf("save: %w", pdfform.ErrCaseLocked), codes.Aborted},
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{status.Error(codes.NotFound, "Case not found"), codes.NotFound},
+		{errors.New("disk full"), codes.Internal},
+	}
+	for _, tt := range tests {
+		if got := status.Code(statusError("Test", tt.err)); got != tt.want {
+			t.Errorf("statusError(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestGuard_Token(t *testing.T) {
+	config := pdfform.NewConfig(t.TempDir())
+	client, _ := startServer(t, config, GuardOptions(httputil.GuardConfig{APIToken: "s3cret"})...)
+
+	for _, tt := range []struct {
+		auth string
+		want codes.Code
+	}{
+		{"", codes.Unauthenticated},
+		{"Bearer wrong", codes.Unauthenticated},
+		{"s3cret", codes.Unauthenticated},
+		{"Bearer s3cret", codes.OK},
+	} {
+		ctx := context.Background()
+		if tt.auth != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.auth)
+		}
+		if _, err := client.ListCases(ctx, &pdfformpb.ListCasesRequest{}); status.Code(err) != tt.want {
+			t.Errorf("ListCases with %q: err = %v, want %s", tt.auth, err, tt.want)
+		}
+
+		// Streams are guarded too
+		stream, err := client.Fill(ctx, &pdfformpb.FillRequest{CaseId: "NOPE"})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		want := tt.want
+		if want == codes.OK {
+			want = codes.NotFound // Past the guard
+		}
+		if status.Code(err) != want {
+			t.Errorf("Fill with %q: err = %v, want %s", tt.auth, err, want)
+		}
+	}
+}
+
+func TestGuard_RateLimit(t *testing.T) {
+	config := pdfform.NewConfig(t.TempDir())
+	client, _ := startServer(t, config, GuardOptions(httputil.GuardConfig{RateLimitRPS: 0.01})...) // Burst of 1
+
+	if _, err := client.ListCases(context.Background(), &pdfformpb.ListCasesRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	var header metadata.MD
+	_, err := client.ListCases(context.Background(), &pdfformpb.ListCasesRequest{}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("err = %v, want ResourceExhausted", err)
+	}
+	if got := header.Get("retry-after"); len(got) != 1 || got[0] == "0" {
+		t.Errorf("retry-after = %v", got)
+	}
+}
+
+func TestGuard_RateLimitBehindProxy(t *testing.T) {
+	config := pdfform.NewConfig(t.TempDir())
+	client, _ := startServer(t, config, GuardOptions(httputil.GuardConfig{RateLimitRPS: 0.01, TrustProxy: true})...)
+
+	call := func(forwardedFor string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-forwarded-for", forwardedFor)
+		_, err := client.ListCases(ctx, &pdfformpb.ListCasesRequest{})
+		return err
+	}
+	if err := call("6.6.6.6, 203.0.113.9"); err != nil {
+		t.Fatal(err)
+	}
+	// A new spoofed leading entry is still the client the proxy saw
+	if err := call("7.7.7.7, 203.0.113.9"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("err = %v, want ResourceExhausted", err)
+	}
+	if err := call("203.0.113.10"); err != nil {
+		t.Errorf("another client: %v", err)
+	}
+}
+
+func TestInspect_OutsideDataDir(t *testing.T) {
+	config := pdfform.NewConfig(t.TempDir())
+	client, _ := startServer(t, config)
+
+	for _, path := range []string{"/etc/passwd", "../../outside.pdf", filepath.Join(t.TempDir(), "form.pdf")} {
+		_, err := client.Inspect(context.Background(), &pdfformpb.InspectRequest{PdfPath: path})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Inspect(%s): err = %v, want PermissionDenied", path, err)
+		}
+	}
+	// Inside, but missing: past the check
+	_, err := client.Inspect(context.Background(), &pdfformpb.InspectRequest{PdfPath: "missing.pdf"})
+	if code := status.Code(err); code == codes.PermissionDenied || code == codes.OK {
+		t.Errorf("Inspect(missing.pdf): err = %v", err)
+	}
+}
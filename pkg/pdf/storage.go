@@ -0,0 +1,99 @@
+package pdfform
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Storage stores user-supplied files (e.g., uploaded PDFs).
+// Save returns a local path, since the PDF engines operate on files.
+type Storage interface {
+	Save(name string, r io.Reader) (path string, err error)
+	Open(name string) (io.ReadCloser, error)
+	Delete(name string) error
+	List() ([]string, error)
+}
+
+// LocalStorage stores files in a directory on disk
+type LocalStorage struct {
+	Dir string
+}
+
+// NewLocalStorage creates a LocalStorage rooted at dir
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{Dir: dir}
+}
+
+// path resolves name inside Dir, rejecting names that would escape it
+func (s *LocalStorage) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid file name: %q", name)
+	}
+	return filepath.Join(s.Dir, name), nil
+}
+
+// Save writes r to Dir/name, replacing any existing file
+func (s *LocalStorage) Save(name string, r io.Reader) (string, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	return path, nil
+}
+
+// Open opens Dir/name for reading
+func (s *LocalStorage) Open(name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete removes Dir/name
+func (s *LocalStorage) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// List returns the stored file names, sorted
+func (s *LocalStorage) List() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,139 @@
+package pdfform
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MaxUploadSize is the default limit for uploaded PDFs (20 MB)
+const MaxUploadSize = 20 << 20
+
+// Upload validation errors
+var (
+	ErrUploadTooLarge = errors.New("uploaded file is too large")
+	ErrNotPDF         = errors.New("uploaded file is not a PDF")
+	ErrNoFormFields   = errors.New("PDF has no fillable form fields")
+)
+
+var (
+	pdfMagic          = []byte("%PDF-")
+	unsafeUploadChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+)
+
+// UploadOptions contains options for uploading a PDF form
+type UploadOptions struct {
+	Filename     string    // Original file name (used for the stored name)
+	Reader       io.Reader // PDF content
+	Storage      Storage   // Where the PDF is stored
+	TemplatesDir string    // Where the inspected JSON template is written
+	MaxSize      int64     // Size limit in bytes (default: MaxUploadSize)
+}
+
+// UploadResult contains the results of uploading a PDF form
+type UploadResult struct {
+	StoredName   string   `json:"stored_name"`
+	PDFPath      string   `json:"pdf_path"`
+	TemplatePath string   `json:"template_path"`
+	FieldCount   int      `json:"field_count"`
+	Fields       []string `json:"fields"`
+}
+
+// UploadPDF validates and stores a user-supplied fillable PDF, then inspects it.
+// The generated template's pdf_url points at the stored PDF, so it can be
+// passed straight to Fill. PDFs without form fields are rejected and removed.
+func UploadPDF(opts UploadOptions) (*UploadResult, error) {
+	if opts.Storage == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	if opts.MaxSize == 0 {
+		opts.MaxSize = MaxUploadSize
+	}
+
+	// Read one byte past the limit to detect oversize uploads
+	data, err := io.ReadAll(io.LimitReader(opts.Reader, opts.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > opts.MaxSize {
+		return nil, fmt.Errorf("%w (limit %d MB)", ErrUploadTooLarge, opts.MaxSize>>20)
+	}
+	if !bytes.HasPrefix(data, pdfMagic) {
+		return nil, ErrNotPDF
+	}
+
+	name := uploadName(opts.Filename, data)
+	pdfPath, err := opts.Storage.Save(name, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store upload: %w", err)
+	}
+
+	inspected, err := Inspect(InspectOptions{
+		PDFPath:   pdfPath,
+		OutputDir: opts.TemplatesDir,
+	})
+	if err == nil && inspected.FieldCount == 0 {
+		os.Remove(inspected.TemplatePath)
+		err = ErrNoFormFields
+	}
+	if err != nil {
+		opts.Storage.Delete(name)
+		return nil, err
+	}
+
+	if err := setTemplatePDF(inspected.TemplatePath, pdfPath); err != nil {
+		return nil, err
+	}
+
+	return &UploadResult{
+		StoredName:   name,
+		PDFPath:      pdfPath,
+		TemplatePath: inspected.TemplatePath,
+		FieldCount:   inspected.FieldCount,
+		Fields:       inspected.Fields,
+	}, nil
+}
+
+// uploadName builds a safe, collision-free name: <sanitized base>_<hash8>.pdf
+func uploadName(filename string, data []byte) string {
+	base := strings.ToLower(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)))
+	base = strings.Trim(unsafeUploadChars.ReplaceAllString(base, "_"), "_")
+	if base == "" {
+		base = "upload"
+	}
+	if len(base) > 64 {
+		base = base[:64]
+	}
+	sum := sha256.Sum256(data)
+	return base + "_" + hex.EncodeToString(sum[:4]) + ".pdf"
+}
+
+// setTemplatePDF points a template's pdf_url at the given PDF
+func setTemplatePDF(templatePath, pdfPath string) error {
+	raw, err := os.ReadFile(templatePath)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+	var tmpl FormData
+	if err := json.Unmarshal(raw, &tmpl); err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	tmpl.PdfURL = pdfPath
+
+	out, err := json.MarshalIndent(tmpl, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+	if err := os.WriteFile(templatePath, out, 0644); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	return nil
+}
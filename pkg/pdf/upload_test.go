@@ -0,0 +1,78 @@
+package pdfform_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+)
+
+func TestUploadPDF(t *testing.T) {
+	input := createConformanceForm(t)
+	data, err := os.ReadFile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage := pdfform.NewLocalStorage(t.TempDir())
+	result, err := pdfform.UploadPDF(pdfform.UploadOptions{
+		Filename:     "../My Form (2024).PDF",
+		Reader:       bytes.NewReader(data),
+		Storage:      storage,
+		TemplatesDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("UploadPDF failed: %v", err)
+	}
+
+	if !strings.HasPrefix(result.StoredName, "my_form_2024_") || !strings.HasSuffix(result.StoredName, ".pdf") {
+		t.Errorf("unexpected stored name: %s", result.StoredName)
+	}
+	if result.FieldCount != 2 {
+		t.Errorf("FieldCount = %d, want 2", result.FieldCount)
+	}
+
+	// The template points at the stored PDF so it can be filled directly
+	raw, err := os.ReadFile(result.TemplatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tmpl pdfform.FormData
+	if err := json.Unmarshal(raw, &tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.PdfURL != result.PDFPath {
+		t.Errorf("template pdf_url = %q, want %q", tmpl.PdfURL, result.PDFPath)
+	}
+}
+
+func TestUploadPDF_Validation(t *testing.T) {
+	storage := pdfform.NewLocalStorage(t.TempDir())
+
+	_, err := pdfform.UploadPDF(pdfform.UploadOptions{
+		Filename: "notes.pdf",
+		Reader:   strings.NewReader("hello, not a pdf"),
+		Storage:  storage,
+	})
+	if !errors.Is(err, pdfform.ErrNotPDF) {
+		t.Errorf("expected ErrNotPDF, got %v", err)
+	}
+
+	_, err = pdfform.UploadPDF(pdfform.UploadOptions{
+		Filename: "big.pdf",
+		Reader:   strings.NewReader("%PDF-" + strings.Repeat("x", 100)),
+		Storage:  storage,
+		MaxSize:  50,
+	})
+	if !errors.Is(err, pdfform.ErrUploadTooLarge) {
+		t.Errorf("expected ErrUploadTooLarge, got %v", err)
+	}
+
+	if names, _ := storage.List(); len(names) != 0 {
+		t.Errorf("rejected uploads were stored: %v", names)
+	}
+}
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"path/filepath"
+	"strings"
+	"time"
 
 	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
 	"github.com/joeblew999/wellknown/pkg/pdf/commands"
@@ -67,6 +72,7 @@ func (h *Handler) HandleDownload(w http.ResponseWriter, r *http.Request) {
 		CatalogPath: h.config.CatalogFilePath(),
 		FormCode:    req.FormCode,
 		OutputDir:   outputDir,
+		CacheDir:    h.config.CachePath(),
 	})
 
 	if err != nil {
@@ -78,6 +84,41 @@ func (h *Handler) HandleDownload(w http.ResponseWriter, r *http.Request) {
 	httputil.RespondJSONOK(w, result)
 }
 
+// HandleUpload handles a multipart PDF upload (field "file"), stores it,
+// inspects it, and returns the template to fill (JSON only)
+func (h *Handler) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	if !httputil.ValidateMethod(w, r, "POST") {
+		return
+	}
+
+	// Allow some headroom over the PDF limit for multipart overhead
+	r.Body = http.MaxBytesReader(w, r.Body, pdfform.MaxUploadSize+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		status := httputil.UploadErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			status = http.StatusBadRequest // missing or malformed multipart body
+		}
+		httputil.RespondError(w, status, fmt.Sprintf("file is required: %v", err))
+		return
+	}
+	defer file.Close()
+
+	result, err := commands.Upload(commands.UploadOptions{
+		Filename:     header.Filename,
+		Reader:       file,
+		Storage:      pdfform.NewLocalStorage(h.config.UploadsPath()),
+		TemplatesDir: h.config.TemplatesPath(),
+	})
+	if err != nil {
+		log.Printf("Upload error: %v", err)
+		httputil.RespondError(w, httputil.UploadErrorStatus(err), err.Error())
+		return
+	}
+
+	httputil.RespondJSONOK(w, result)
+}
+
 // HandleInspect handles the inspect API request (JSON only)
 func (h *Handler) HandleInspect(w http.ResponseWriter, r *http.Request) {
 	if !httputil.ValidateMethod(w, r, "POST") {
@@ -86,7 +127,8 @@ func (h *Handler) HandleInspect(w http.ResponseWriter, r *http.Request) {
 
 	// Parse JSON request body
 	var req struct {
-		PDFPath string `json:"pdf_path"`
+		PDFPath  string            `json:"pdf_path"`
+		Password pdfform.Passwords `json:"password"` // For encrypted PDFs
 	}
 	if err := httputil.DecodeJSONBody(w, r, &req); err != nil {
 		return
@@ -102,14 +144,23 @@ func (h *Handler) HandleInspect(w http.ResponseWriter, r *http.Request) {
 	if !filepath.IsAbs(pdfPath) {
 		pdfPath = filepath.Join(h.config.DownloadsPath(), pdfPath)
 	}
+	if !h.config.InDataDir(pdfPath) {
+		httputil.RespondError(w, http.StatusForbidden, "pdf_path must be inside the data directory")
+		return
+	}
 
 	outputDir := h.config.TemplatesPath()
 
 	result, err := commands.Inspect(commands.InspectOptions{
 		PDFPath:   pdfPath,
 		OutputDir: outputDir,
+		Password:  req.Password,
 	})
 
+	if errors.Is(err, pdfform.ErrPasswordRequired) {
+		httputil.RespondError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 	if err != nil {
 		log.Printf("Inspect error: %v", err)
 		httputil.RespondInternalError(w, err)
@@ -127,8 +178,11 @@ func (h *Handler) HandleFill(w http.ResponseWriter, r *http.Request) {
 
 	// Parse JSON request body
 	var req struct {
-		CaseID  string `json:"case_id"`
-		Flatten bool   `json:"flatten"`
+		CaseID          string            `json:"case_id"`
+		Flatten         bool              `json:"flatten"`
+		Password        pdfform.Passwords `json:"password"`         // For an encrypted input PDF
+		ProtectPassword string            `json:"protect_password"` // Re-protect the output with this password
+		Metadata        *pdfform.Metadata `json:"metadata"`         // Stamp title, author, etc. (case_id defaults to the case)
 	}
 	if err := httputil.DecodeJSONBody(w, r, &req); err != nil {
 		return
@@ -153,7 +207,25 @@ func (h *Handler) HandleFill(w http.ResponseWriter, r *http.Request) {
 
 	outputDir := h.config.OutputsPath()
 
-	result, err := commands.FillFromCase(casePath, outputDir, req.Flatten)
+	var protect *pdfform.Protection
+	if req.ProtectPassword != "" {
+		protect = &pdfform.Protection{UserPassword: req.ProtectPassword}
+	}
+
+	result, err := commands.FillFromCaseWithOptions(commands.FillFromCaseOptions{
+		CasePath:  casePath,
+		OutputDir: outputDir,
+		Flatten:   req.Flatten,
+		Password:  req.Password,
+		Metadata:  req.Metadata,
+		Protect:   protect,
+
+		CatalogPath: h.config.CatalogFilePath(),
+	})
+	if errors.Is(err, pdfform.ErrPasswordRequired) {
+		httputil.RespondError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 	if err != nil {
 		log.Printf("Fill error: %v", err)
 		httputil.RespondInternalError(w, err)
@@ -231,7 +303,14 @@ func (h *Handler) HandleCreateCase(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, casePath, err := commands.CreateCase(req.FormCode, req.CaseName, req.EntityName, h.config.DataDir)
+	c, casePath, err := commands.CreateCaseWithOptions(commands.CreateCaseOptions{
+		FormCode:    req.FormCode,
+		CaseName:    req.CaseName,
+		EntityName:  req.EntityName,
+		DataDir:     h.config.DataDir,
+		CatalogPath: h.config.CatalogFilePath(),
+		PDFPath:     filepath.Join(h.config.DownloadsPath(), strings.ToLower(req.FormCode)+".pdf"),
+	})
 	if err != nil {
 		log.Printf("Create case error: %v", err)
 		httputil.RespondInternalError(w, err)
@@ -278,13 +357,73 @@ func (h *Handler) HandleLoadCase(w http.ResponseWriter, r *http.Request) {
 	httputil.RespondJSONOK(w, c)
 }
 
+// HandleEvents streams command events as Server-Sent Events ("event: <type>",
+// JSON data), optionally only those matching ?pattern= (e.g. "download.*").
+// Headers are flushed once subscribed, so a client that starts an operation
+// after connecting sees all of its events.
+func (h *Handler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	if !httputil.ValidateMethod(w, r, "GET") {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		httputil.RespondError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	pattern := r.URL.Query().Get("pattern")
+	if pattern == "" {
+		pattern = "*"
+	}
+	events := commands.Subscribe(pattern)
+	defer commands.Unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Keep connection alive with periodic heartbeat
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			msg := struct {
+				Type      commands.EventType     `json:"type"`
+				Timestamp time.Time              `json:"timestamp"`
+				Data      map[string]interface{} `json:"data"`
+				Error     string                 `json:"error,omitempty"`
+			}{Type: event.Type, Timestamp: event.Timestamp, Data: event.Data}
+			if event.Error != nil {
+				msg.Error = event.Error.Error()
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				log.Printf("Events error: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprintf(w, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
 // RegisterRoutes registers all API routes on the given mux
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/browse", h.HandleBrowse)
 	mux.HandleFunc("/api/download", h.HandleDownload)
+	mux.HandleFunc("/api/upload", h.HandleUpload)
 	mux.HandleFunc("/api/inspect", h.HandleInspect)
 	mux.HandleFunc("/api/fill", h.HandleFill)
 	mux.HandleFunc("/api/cases/list", h.HandleListCases)
 	mux.HandleFunc("/api/cases/create", h.HandleCreateCase)
 	mux.HandleFunc("/api/cases/load", h.HandleLoadCase)
+	mux.HandleFunc("/api/events", h.HandleEvents)
 }
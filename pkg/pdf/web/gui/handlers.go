@@ -3,13 +3,20 @@ package gui
 import (
 	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io/fs"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
 
 	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+	"github.com/joeblew999/wellknown/pkg/pdf/commands"
+	"github.com/joeblew999/wellknown/pkg/pdf/web/httputil"
 )
 
 //go:embed templates/*.html
@@ -95,26 +102,158 @@ func (h *Handler) HandleDownload(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, buf.String())
 }
 
-// HandleInspect renders the inspect fields page
+// HandleInspect renders the inspect fields page, listing templates that can be edited
 func (h *Handler) HandleInspect(w http.ResponseWriter, r *http.Request) {
-	html, err := renderTemplate("inspect.html")
+	ids, err := pdfform.ListTemplateIDs(h.config.TemplatesPath())
+	if err != nil {
+		log.Printf("⚠️  Could not list templates: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = templates.ExecuteTemplate(&buf, "inspect.html", map[string]interface{}{
+		"Title":     "3️⃣ Inspect Fields",
+		"Templates": ids,
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "text/html")
-	fmt.Fprint(w, html)
+	fmt.Fprint(w, buf.String())
 }
 
 // HandleFill renders the fill form page
+// An optional ?dataPath= pre-fills the data path (used after an upload)
 func (h *Handler) HandleFill(w http.ResponseWriter, r *http.Request) {
-	html, err := renderTemplate("fill.html")
+	dataPath := r.URL.Query().Get("dataPath")
+	if dataPath == "" {
+		dataPath = "cases/"
+	}
+	dataPathJSON, _ := json.Marshal(dataPath)
+
+	var buf bytes.Buffer
+	err := templates.ExecuteTemplate(&buf, "fill.html", map[string]interface{}{
+		"Title":        "4️⃣ Fill Form",
+		"DataPathJSON": string(dataPathJSON),
+		"Uploaded":     r.URL.Query().Get("uploaded"),
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "text/html")
-	fmt.Fprint(w, html)
+	fmt.Fprint(w, buf.String())
+}
+
+// HandleCaseHistory renders the timeline of a case's history for /cases/history?casePath=
+func (h *Handler) HandleCaseHistory(w http.ResponseWriter, r *http.Request) {
+	casePath, ok := httputil.GetRequiredFormValue(w, r, "casePath")
+	if !ok {
+		return
+	}
+
+	// Only cases inside the data directory can be shown
+	rel, err := filepath.Rel(h.config.DataDir, casePath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		httputil.RespondBadRequest(w, "casePath must be inside the data directory")
+		return
+	}
+
+	c, err := commands.LoadCase(casePath)
+	if err != nil {
+		httputil.RespondNotFound(w, "Case not found")
+		return
+	}
+
+	// Newest first
+	history := make([]pdfform.CaseHistoryEntry, len(c.History))
+	for i, entry := range c.History {
+		history[len(c.History)-1-i] = entry
+	}
+
+	var buf bytes.Buffer
+	err = templates.ExecuteTemplate(&buf, "case_history.html", map[string]interface{}{
+		"Title":    "🕘 Case History",
+		"Case":     c,
+		"CasePath": casePath,
+		"History":  history,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprint(w, buf.String())
+}
+
+// requestActor identifies the client of a GUI request in case history entries
+func requestActor(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "gui@" + host
+}
+
+// HandleUpload renders the upload-your-own-PDF page
+func (h *Handler) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	h.renderUpload(w, http.StatusOK, "")
+}
+
+// HandleUploadAction stores an uploaded PDF, inspects it, and redirects to the
+// fill step with the generated template selected
+func (h *Handler) HandleUploadAction(w http.ResponseWriter, r *http.Request) {
+	if !httputil.ValidateMethod(w, r, "POST") {
+		return
+	}
+
+	// Allow some headroom over the PDF limit for multipart overhead
+	r.Body = http.MaxBytesReader(w, r.Body, pdfform.MaxUploadSize+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		status := httputil.UploadErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			status = http.StatusBadRequest
+		}
+		h.renderUpload(w, status, fmt.Sprintf("please choose a PDF file (%v)", err))
+		return
+	}
+	defer file.Close()
+
+	result, err := commands.Upload(commands.UploadOptions{
+		Filename:     header.Filename,
+		Reader:       file,
+		Storage:      pdfform.NewLocalStorage(h.config.UploadsPath()),
+		TemplatesDir: h.config.TemplatesPath(),
+	})
+	if err != nil {
+		log.Printf("❌ Upload failed for %s: %v", header.Filename, err)
+		h.renderUpload(w, httputil.UploadErrorStatus(err), err.Error())
+		return
+	}
+	log.Printf("✅ Upload completed for %s (%d fields)", header.Filename, result.FieldCount)
+
+	q := url.Values{}
+	q.Set("dataPath", result.TemplatePath)
+	q.Set("uploaded", header.Filename)
+	http.Redirect(w, r, "/4-fill?"+q.Encode(), http.StatusSeeOther)
+}
+
+// renderUpload renders upload.html with an optional error message
+func (h *Handler) renderUpload(w http.ResponseWriter, status int, errMsg string) {
+	var buf bytes.Buffer
+	err := templates.ExecuteTemplate(&buf, "upload.html", map[string]interface{}{
+		"Title":     "📤 Upload PDF",
+		"MaxSizeMB": pdfform.MaxUploadSize >> 20,
+		"Error":     errMsg,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	fmt.Fprint(w, buf.String())
 }
 
 // HandleTest renders the test page
@@ -163,20 +302,27 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/1-browse", h.HandleBrowse)
 	mux.HandleFunc("/2-download", h.HandleDownload)
 	mux.HandleFunc("/3-inspect", h.HandleInspect)
+	mux.HandleFunc("/3-inspect/{id}/edit", h.HandleTemplateEdit)     // Template editor
+	mux.HandleFunc("/3-inspect/{id}/schema", h.HandleTemplateSchema) // Template JSON Schema
 	mux.HandleFunc("/4-fill", h.HandleFill)
 	mux.HandleFunc("/5-test", h.HandleTest)
+	mux.HandleFunc("/upload", h.HandleUpload)
+	mux.HandleFunc("/cases/history", h.HandleCaseHistory) // Case history timeline
 
 	// GUI-specific API endpoints (use /gui/ prefix to avoid conflicts with /api/)
 	mux.HandleFunc("/gui/events", h.HandleSSE)                 // SSE event stream
 	mux.HandleFunc("/gui/forms", h.HandleGetForms)             // Get available forms (JSON)
 	mux.HandleFunc("/gui/download-data", h.HandleDownloadData) // Get download fragment (HTML)
 	mux.HandleFunc("/gui/download", h.HandleDownloadAction)    // Trigger download action
+	mux.HandleFunc("/gui/upload", h.HandleUploadAction)        // Upload a PDF, inspect, redirect to fill
 	mux.HandleFunc("/gui/inspect", h.HandleInspectAction)      // Trigger inspect action
 	mux.HandleFunc("/gui/fill", h.HandleFillAction)            // Trigger fill action
+	mux.HandleFunc("/gui/deliver", h.HandleDeliverAction)      // Email a filled PDF
 
 	// Case management endpoints
 	mux.HandleFunc("/gui/cases/list", h.HandleListCases)   // List all cases (JSON)
 	mux.HandleFunc("/gui/cases/create", h.HandleCreateCase) // Create new case
 	mux.HandleFunc("/gui/cases/load", h.HandleLoadCase)     // Load case data (JSON)
 	mux.HandleFunc("/gui/cases/save", h.HandleSaveCase)     // Save case data
+	mux.HandleFunc("/gui/cases/export", h.HandleExportCase) // Download case bundle ZIP
 }
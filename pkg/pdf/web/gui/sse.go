@@ -1,13 +1,17 @@
 package gui
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"path/filepath"
+	"strings"
 	"time"
 
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
 	"github.com/joeblew999/wellknown/pkg/pdf/commands"
 	"github.com/joeblew999/wellknown/pkg/pdf/web/httputil"
 	"github.com/starfederation/datastar-go/datastar"
@@ -169,6 +173,32 @@ func (h *Handler) eventToSignals(event *commands.Event) map[string]interface{} {
 			"outputPath": "",
 		}
 
+	// Delivery events
+	case commands.EventDeliveryStarted:
+		pdfPath := getStringFromData(event.Data, "pdf_path")
+		return map[string]interface{}{
+			"sending": true,
+			"status":  fmt.Sprintf("Sending %s...", filepath.Base(pdfPath)),
+			"error":   "",
+		}
+	case commands.EventDeliveryCompleted:
+		file := getStringFromData(event.Data, "file")
+		to, _ := event.Data["to"].([]string)
+		return map[string]interface{}{
+			"sending": false,
+			"status":  fmt.Sprintf("Sent %s to %s", file, strings.Join(to, ", ")),
+			"error":   "",
+		}
+	case commands.EventDeliveryError:
+		errorMsg := ""
+		if event.Error != nil {
+			errorMsg = event.Error.Error()
+		}
+		return map[string]interface{}{
+			"sending": false,
+			"error":   fmt.Sprintf("Send failed: %s", errorMsg),
+		}
+
 	// Case events
 	case commands.EventCaseCreated:
 		caseID := getStringFromData(event.Data, "case_id")
@@ -287,6 +317,7 @@ func (h *Handler) HandleDownloadAction(w http.ResponseWriter, r *http.Request) {
 			CatalogPath: catalogPath,
 			FormCode:    formCode,
 			OutputDir:   outputDir,
+			CacheDir:    h.config.CachePath(),
 		}
 		_, err := commands.Download(opts)
 		if err != nil {
@@ -430,14 +461,30 @@ func (h *Handler) HandleFillAction(w http.ResponseWriter, r *http.Request) {
 	// Get output directory from config
 	outputDir := h.config.DownloadsPath()
 
+	// Case files fill from the case (recorded in its history); other paths are form data
+	actor := requestActor(r)
+	isCase := false
+	if c, err := pdfform.LoadCase(dataPath); err == nil && c.Metadata.CaseID != "" {
+		isCase = true
+	}
+
 	// Execute fill asynchronously - events will update UI via SSE
 	go func() {
-		opts := commands.FillOptions{
-			DataPath:  dataPath,
-			OutputDir: outputDir,
-			Flatten:   flatten,
+		var err error
+		if isCase {
+			_, err = commands.FillFromCaseWithOptions(commands.FillFromCaseOptions{
+				CasePath:  dataPath,
+				OutputDir: outputDir,
+				Flatten:   flatten,
+				Actor:     actor,
+			})
+		} else {
+			_, err = commands.Fill(commands.FillOptions{
+				DataPath:  dataPath,
+				OutputDir: outputDir,
+				Flatten:   flatten,
+			})
 		}
-		_, err := commands.Fill(opts)
 		if err != nil {
 			log.Printf("❌ Fill failed for %s: %v", dataPath, err)
 		} else {
@@ -450,6 +497,45 @@ func (h *Handler) HandleFillAction(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, "Fill started")
 }
 
+// HandleDeliverAction emails a filled PDF ("Send" button on the fill page)
+// Triggers delivery asynchronously - UI updates come via SSE from event system
+func (h *Handler) HandleDeliverAction(w http.ResponseWriter, r *http.Request) {
+	if !httputil.ValidateMethod(w, r, "POST") {
+		return
+	}
+
+	outputPath, ok := httputil.GetRequiredFormValue(w, r, "outputPath")
+	if !ok {
+		return
+	}
+
+	// Only files produced by this server can be sent
+	rel, err := filepath.Rel(h.config.DataDir, outputPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		httputil.RespondBadRequest(w, "outputPath must be inside the data directory")
+		return
+	}
+
+	// Optional recipients; default is PDF_DELIVERY_TO
+	var to []string
+	for _, addr := range strings.Split(r.FormValue("deliverTo"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+
+	go func() {
+		if _, err := commands.Deliver(pdfform.DeliveryOptions{PDFPath: outputPath, To: to}); err != nil {
+			log.Printf("❌ Delivery failed for %s: %v", outputPath, err)
+		} else {
+			log.Printf("✅ Delivered %s", outputPath)
+		}
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprint(w, "Delivery started")
+}
+
 // HandleListCases returns list of available cases for selection
 func (h *Handler) HandleListCases(w http.ResponseWriter, r *http.Request) {
 	if !httputil.ValidateMethod(w, r, "GET") {
@@ -505,7 +591,13 @@ func (h *Handler) HandleCreateCase(w http.ResponseWriter, r *http.Request) {
 	sse.MarshalAndPatchSignals(signals)
 
 	// Call commands to create case (returns 3 values)
-	caseObj, casePath, err := commands.CreateCase(formCode, caseName, entityName, casesDir)
+	caseObj, casePath, err := commands.CreateCaseWithOptions(commands.CreateCaseOptions{
+		FormCode:    formCode,
+		CaseName:    caseName,
+		EntityName:  entityName,
+		DataDir:     casesDir,
+		CatalogPath: h.config.CatalogFilePath(),
+	})
 	if err != nil {
 		log.Printf("❌ Failed to create case %s: %v", caseName, err)
 		// Send error signal
@@ -556,7 +648,48 @@ func (h *Handler) HandleLoadCase(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// HandleSaveCase saves changes to an existing test case
+// HandleExportCase downloads a case bundle ZIP (case data, template, filled PDFs, manifest)
+func (h *Handler) HandleExportCase(w http.ResponseWriter, r *http.Request) {
+	if !httputil.ValidateMethod(w, r, "GET") {
+		return
+	}
+
+	casePath, ok := httputil.GetRequiredFormValue(w, r, "casePath")
+	if !ok {
+		return
+	}
+
+	// Only cases inside the data directory can be exported
+	rel, err := filepath.Rel(h.config.DataDir, casePath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		httputil.RespondBadRequest(w, "casePath must be inside the data directory")
+		return
+	}
+
+	// Build the bundle in memory so errors can still be reported as JSON
+	var buf bytes.Buffer
+	if _, err := pdfform.WriteCaseBundle(&buf, pdfform.ExportCaseOptions{
+		CasePath:   casePath,
+		OutputDirs: []string{h.config.OutputsPath(), h.config.DownloadsPath()},
+		Actor:      requestActor(r),
+	}); err != nil {
+		log.Printf("❌ Failed to export case %s: %v", casePath, err)
+		httputil.RespondInternalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.TrimSuffix(filepath.Base(casePath), ".json")+".zip"))
+	w.Write(buf.Bytes())
+}
+
+// HandleSaveCase saves field changes to an existing test case.
+//
+// The body carries the revision the client loaded and the changed fields. When
+// another session saved in between, nothing is written and a "conflict" signal
+// lists the fields that differ, so the page can ask whether to keep these
+// changes (post again with "overwrite": true, merging them into the latest
+// revision) or reload.
 func (h *Handler) HandleSaveCase(w http.ResponseWriter, r *http.Request) {
 	if !httputil.ValidateMethod(w, r, "POST") {
 		return
@@ -575,30 +708,57 @@ func (h *Handler) HandleSaveCase(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse updates from request body
-	var updates map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+	var req struct {
+		Revision  int               `json:"revision"`
+		Fields    map[string]string `json:"fields"`
+		Overwrite bool              `json:"overwrite"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		httputil.RespondBadRequest(w, "Invalid JSON data")
 		return
 	}
 
-	// TODO: Apply updates to caseObj fields
-	// This requires knowing the Case struct layout
-	// For now, we'll just save the existing case
+	// Save against the revision the client edited, unless it chose to
+	// overwrite: then its fields are applied on top of the latest revision
+	if !req.Overwrite {
+		caseObj.Metadata.Revision = req.Revision
+	}
+	if caseObj.Fields == nil {
+		caseObj.Fields = make(map[string]string)
+	}
+	for name, value := range req.Fields {
+		caseObj.Fields[name] = value
+	}
 
 	// Create SSE connection for this request
 	sse := datastar.NewSSE(w, r)
 
 	// Send "saving" signal immediately
 	signals := map[string]interface{}{
-		"saving": true,
-		"status": "Saving case...",
-		"error":  "",
+		"saving":   true,
+		"conflict": false,
+		"status":   "Saving case...",
+		"error":    "",
 	}
 	sse.MarshalAndPatchSignals(signals)
 
 	// Call commands to save case (returns just error)
-	err = commands.SaveCase(caseObj, casePath)
-	if err != nil {
+	err = commands.SaveCaseWithOptions(commands.SaveCaseOptions{Case: caseObj, CasePath: casePath, Actor: requestActor(r)})
+	var conflict *pdfform.CaseConflictError
+	switch {
+	case errors.As(err, &conflict):
+		log.Printf("⚠️  Save conflict on case %s: %v", casePath, err)
+		// Send merge prompt signals
+		signals = map[string]interface{}{
+			"saving":          false,
+			"conflict":        true,
+			"conflictFields":  conflict.ConflictingFields(req.Fields),
+			"currentRevision": conflict.Current.Metadata.Revision,
+			"status":          "",
+			"error":           "This case was changed in another session. Keep your changes or reload?",
+		}
+		sse.MarshalAndPatchSignals(signals)
+	case err != nil:
 		log.Printf("❌ Failed to save case %s: %v", casePath, err)
 		// Send error signal
 		signals = map[string]interface{}{
@@ -607,13 +767,14 @@ func (h *Handler) HandleSaveCase(w http.ResponseWriter, r *http.Request) {
 			"error":  err.Error(),
 		}
 		sse.MarshalAndPatchSignals(signals)
-	} else {
+	default:
 		log.Printf("✅ Case saved: %s", casePath)
 		// Send success signal
 		signals = map[string]interface{}{
-			"saving": false,
-			"status": fmt.Sprintf("Case saved successfully! Path: %s", casePath),
-			"error":  "",
+			"saving":   false,
+			"revision": caseObj.Metadata.Revision,
+			"status":   fmt.Sprintf("Case saved successfully! Path: %s", casePath),
+			"error":    "",
 		}
 		sse.MarshalAndPatchSignals(signals)
 	}
@@ -0,0 +1,154 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
+	"github.com/joeblew999/wellknown/pkg/pdf/web/httputil"
+)
+
+// HandleTemplateEdit renders (GET) or saves (POST) the template editor for /3-inspect/{id}/edit
+func (h *Handler) HandleTemplateEdit(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	path, err := pdfform.TemplatePathForID(h.config.TemplatesPath(), id)
+	if err != nil {
+		httputil.RespondBadRequest(w, err.Error())
+		return
+	}
+
+	tmpl, err := pdfform.LoadTemplate(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		httputil.RespondNotFound(w, "Template not found")
+		return
+	}
+	if err != nil {
+		httputil.RespondInternalError(w, err)
+		return
+	}
+	tmpl.EnsureFieldDefs()
+	if tmpl.PdfURL != "" {
+		if _, statErr := os.Stat(tmpl.PdfURL); statErr == nil {
+			if err := tmpl.FillFieldTypes(tmpl.PdfURL); err != nil {
+				log.Printf("⚠️  Could not inspect field types for %s: %v", id, err)
+			}
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.renderTemplateEditor(w, http.StatusOK, id, path, tmpl, r.URL.Query().Get("saved") != "", "")
+	case http.MethodPost:
+		if err := applyTemplateForm(tmpl, r); err != nil {
+			h.renderTemplateEditor(w, http.StatusBadRequest, id, path, tmpl, false, err.Error())
+			return
+		}
+		if err := pdfform.SaveTemplate(path, tmpl); err != nil {
+			h.renderTemplateEditor(w, http.StatusInternalServerError, id, path, tmpl, false, err.Error())
+			return
+		}
+		log.Printf("✅ Template saved: %s", path)
+		if n, err := pdfform.RecordTemplateEdit(h.config.DataDir, path, requestActor(r)); err != nil {
+			log.Printf("⚠️  Could not record template edit in case history: %v", err)
+		} else if n > 0 {
+			log.Printf("🕘 Template edit recorded in %d case(s)", n)
+		}
+		http.Redirect(w, r, "/3-inspect/"+id+"/edit?saved=1", http.StatusSeeOther)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleTemplateSchema returns the JSON Schema for /3-inspect/{id}/schema
+func (h *Handler) HandleTemplateSchema(w http.ResponseWriter, r *http.Request) {
+	if !httputil.ValidateMethod(w, r, "GET") {
+		return
+	}
+	id := r.PathValue("id")
+	path, err := pdfform.TemplatePathForID(h.config.TemplatesPath(), id)
+	if err != nil {
+		httputil.RespondBadRequest(w, err.Error())
+		return
+	}
+	tmpl, err := pdfform.LoadTemplate(path)
+	if err != nil {
+		httputil.RespondNotFound(w, "Template not found")
+		return
+	}
+	httputil.RespondJSONOK(w, tmpl.JSONSchema(id))
+}
+
+// applyTemplateForm updates tmpl.FieldDefs from the editor's parallel form arrays
+func applyTemplateForm(tmpl *pdfform.FormData, r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		return fmt.Errorf("invalid form: %w", err)
+	}
+
+	names := r.PostForm["pdf_name"]
+	keys, labels, defaults := r.PostForm["key"], r.PostForm["label"], r.PostForm["default"]
+	types, orders := r.PostForm["type"], r.PostForm["order"]
+	if len(keys) != len(names) || len(labels) != len(names) || len(defaults) != len(names) ||
+		len(types) != len(names) || len(orders) != len(names) {
+		return fmt.Errorf("incomplete form submission")
+	}
+
+	required := make(map[string]bool)
+	for _, name := range r.PostForm["required"] {
+		required[name] = true
+	}
+
+	type row struct {
+		order int
+		field pdfform.TemplateField
+	}
+	rows := make([]row, len(names))
+	for i, name := range names {
+		order, err := strconv.Atoi(orders[i])
+		if err != nil {
+			order = i
+		}
+		rows[i] = row{order, pdfform.TemplateField{
+			PDFName:  name,
+			Key:      keys[i],
+			Label:    labels[i],
+			Type:     types[i],
+			Required: required[name],
+			Default:  defaults[i],
+		}}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].order < rows[j].order })
+
+	tmpl.PdfURL = r.PostForm.Get("pdf_url")
+	tmpl.FieldDefs = tmpl.FieldDefs[:0]
+	for _, row := range rows {
+		tmpl.FieldDefs = append(tmpl.FieldDefs, row.field)
+	}
+	return tmpl.ValidateFieldDefs()
+}
+
+// renderTemplateEditor renders template_edit.html
+func (h *Handler) renderTemplateEditor(w http.ResponseWriter, status int, id, path string, tmpl *pdfform.FormData, saved bool, errMsg string) {
+	var buf bytes.Buffer
+	err := templates.ExecuteTemplate(&buf, "template_edit.html", map[string]interface{}{
+		"Title":    "✏️ Edit Template",
+		"ID":       id,
+		"Path":     path,
+		"Template": tmpl,
+		"Saved":    saved,
+		"Error":    errMsg,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	fmt.Fprint(w, buf.String())
+}
@@ -0,0 +1,218 @@
+package httputil
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ================================================================
+// Public API Guard
+// ================================================================
+// CORS and per-client rate limiting for the JSON API, configured with the
+// same variables as the wellknown guard package (pkg/guard) used by the
+// demo server, env web GUI and PocketBase routes. This module cannot import
+// the root module, so the small implementation lives here. PDF_API_TOKEN
+// additionally requires a bearer token, for servers other services call
+// remotely (see pkg/pdf/client). The gRPC API applies the same configuration
+// (rpc.GuardOptions).
+
+// Environment variables read by GuardConfigFromEnv
+const (
+	EnvCORSOrigins  = "CORS_ORIGINS"   // Comma-separated allowed origins, * for any
+	EnvRateLimitRPS = "RATE_LIMIT_RPS" // Requests per second per client, 0 disables
+	EnvBehindProxy  = "BEHIND_PROXY"   // Trust X-Forwarded-For for client addresses
+	EnvAPIToken     = "PDF_API_TOKEN"  // Bearer token required by the API, empty disables
+)
+
+// DefaultRateLimitRPS is used when RATE_LIMIT_RPS is unset
+const DefaultRateLimitRPS = 10
+
+// GuardConfig configures Guard
+type GuardConfig struct {
+	CORSOrigins  []string // Allowed origins; "*" allows any, empty adds no CORS headers
+	RateLimitRPS float64  // Requests per second per client; 0 disables rate limiting
+	TrustProxy   bool     // Identify clients by Fly-Client-IP or X-Forwarded-For (see ClientAddr)
+	APIToken     string   // Required "Authorization: Bearer" token; empty allows anonymous access
+}
+
+// GuardConfigFromEnv reads CORS_ORIGINS, RATE_LIMIT_RPS, BEHIND_PROXY and PDF_API_TOKEN
+func GuardConfigFromEnv() GuardConfig {
+	cfg := GuardConfig{RateLimitRPS: DefaultRateLimitRPS}
+	for _, o := range strings.Split(os.Getenv(EnvCORSOrigins), ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			cfg.CORSOrigins = append(cfg.CORSOrigins, o)
+		}
+	}
+	if v := os.Getenv(EnvRateLimitRPS); v != "" {
+		if rps, err := strconv.ParseFloat(v, 64); err == nil && rps >= 0 {
+			cfg.RateLimitRPS = rps
+		}
+	}
+	cfg.TrustProxy, _ = strconv.ParseBool(os.Getenv(EnvBehindProxy))
+	cfg.APIToken = os.Getenv(EnvAPIToken)
+	return cfg
+}
+
+// Guard wraps next with CORS (first, so preflights are not limited), rate
+// limiting (before the token check, so guessing tokens is limited too) and
+// bearer token authentication
+func Guard(cfg GuardConfig, next http.Handler) http.Handler {
+	if cfg.APIToken != "" {
+		next = bearerToken(cfg.APIToken, next)
+	}
+	if cfg.RateLimitRPS > 0 {
+		next = rateLimit(cfg, next)
+	}
+	if len(cfg.CORSOrigins) > 0 {
+		next = cors(cfg.CORSOrigins, next)
+	}
+	return next
+}
+
+func cors(origins []string, next http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		allowed[strings.TrimSuffix(o, "/")] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && (allowed["*"] || allowed[origin]) {
+			h := w.Header()
+			if allowed["*"] {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Add("Vary", "Origin")
+			}
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				h.Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ValidToken reports whether an Authorization header value is
+// "Bearer <token>" (compared in constant time)
+func ValidToken(authorization, token string) bool {
+	got, ok := strings.CutPrefix(authorization, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// bearerToken rejects requests without "Authorization: Bearer <token>"
+func bearerToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ValidToken(r.Header.Get("Authorization"), token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Limiter is a token bucket per client (burst: 2x the rate), shared by
+// Guard and the gRPC interceptors (pkg/pdf/rpc)
+type Limiter struct {
+	rps   float64
+	burst float64
+
+	mu      sync.Mutex
+	clients map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a limiter allowing rps requests per second per client
+func NewLimiter(rps float64) *Limiter {
+	return &Limiter{rps: rps, burst: math.Max(1, math.Ceil(2*rps)), clients: make(map[string]*bucket)}
+}
+
+// Allow takes a token from client's bucket; when there is none it reports
+// how long until there will be
+func (l *Limiter) Allow(client string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.clients[client]
+	if !ok {
+		// Drop idle clients so the map does not grow without bound
+		for c, old := range l.clients {
+			if now.Sub(old.last) > 5*time.Minute {
+				delete(l.clients, c)
+			}
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.clients[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rps)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rps * float64(time.Second))
+}
+
+// rateLimit applies a Limiter per client address
+func rateLimit(cfg GuardConfig, next http.Handler) http.Handler {
+	limiter := NewLimiter(cfg.RateLimitRPS)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, wait := limiter.Allow(clientIP(r, cfg.TrustProxy))
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]string{"error": "rate limit exceeded"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func clientIP(r *http.Request, trustProxy bool) string {
+	return ClientAddr(r.RemoteAddr, r.Header, trustProxy)
+}
+
+// ClientAddr identifies a client by the host of remoteAddr. With trustProxy
+// it is the address the proxy saw, from the proxy headers: Fly-Client-IP on
+// Fly.io, else the last X-Forwarded-For entry (the one the proxy appended;
+// earlier entries come from the client and can be spoofed).
+func ClientAddr(remoteAddr string, proxy http.Header, trustProxy bool) string {
+	if trustProxy {
+		if fly := strings.TrimSpace(proxy.Get("Fly-Client-IP")); fly != "" {
+			return fly
+		}
+		entries := strings.Split(strings.Join(proxy.Values("X-Forwarded-For"), ","), ",")
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entry := strings.TrimSpace(entries[i]); entry != "" {
+				return entry
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
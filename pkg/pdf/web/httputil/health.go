@@ -0,0 +1,113 @@
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// ================================================================
+// Health Endpoint
+// ================================================================
+// Serves the same JSON as the wellknown health package (pkg/health) used by
+// the PocketBase server, demo server and env web GUI, so deploy checks and
+// monitors read every binary alike. This module cannot import the root
+// module, so the small implementation lives here; keep the fields in sync.
+
+// HealthCheck reports a dependency's health: nil when healthy. A failing
+// check turns the status to "down" and the response to 503.
+type HealthCheck func() error
+
+// HealthCheckResult mirrors health.CheckResult
+type HealthCheckResult struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// HealthReport mirrors health.Report
+type HealthReport struct {
+	Status        string                       `json:"status"`
+	Service       string                       `json:"service,omitempty"`
+	Timestamp     string                       `json:"timestamp"`
+	Environment   string                       `json:"environment"`
+	Version       string                       `json:"version"`
+	Uptime        string                       `json:"uptime"`
+	GoVersion     string                       `json:"go_version"`
+	NumGoroutines int                          `json:"num_goroutines"`
+	Checks        map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// Health returns the /health handler of a service started now
+func Health(service string, checks map[string]HealthCheck) http.Handler {
+	started := time.Now()
+	version := buildVersion()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := HealthReport{
+			Status:        "ok",
+			Service:       service,
+			Timestamp:     time.Now().UTC().Format(time.RFC3339),
+			Environment:   detectEnvironment(),
+			Version:       version,
+			Uptime:        time.Since(started).Round(time.Second).String(),
+			GoVersion:     runtime.Version(),
+			NumGoroutines: runtime.NumGoroutine(),
+		}
+		if len(checks) > 0 {
+			report.Checks = make(map[string]HealthCheckResult, len(checks))
+		}
+		for name, check := range checks {
+			start := time.Now()
+			result := HealthCheckResult{Status: "ok", Critical: true}
+			if err := check(); err != nil {
+				result.Status, result.Error = "down", err.Error()
+				report.Status = "down"
+			}
+			result.Duration = time.Since(start).Round(time.Millisecond).String()
+			report.Checks[name] = result
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status == "down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// detectEnvironment mirrors env.DetectEnvironment
+func detectEnvironment() string {
+	if os.Getenv("FLY_APP_NAME") != "" {
+		return "fly.io"
+	}
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		return "docker"
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return "kubernetes"
+	}
+	return "local"
+}
+
+// buildVersion mirrors health.BuildVersion
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+	if info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return ""
+}
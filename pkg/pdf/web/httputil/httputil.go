@@ -2,8 +2,11 @@ package httputil
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+
+	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
 )
 
 // ValidateMethod checks if the request method matches expected
@@ -80,3 +83,18 @@ func DecodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) error
 	}
 	return nil
 }
+
+// UploadErrorStatus maps a PDF upload error to an HTTP status code
+func UploadErrorStatus(err error) int {
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.Is(err, pdfform.ErrUploadTooLarge), errors.As(err, &maxBytes):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, pdfform.ErrNotPDF):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, pdfform.ErrNoFormFields):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,12 +1,15 @@
 package web
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"os"
 
 	pdfform "github.com/joeblew999/wellknown/pkg/pdf"
 	"github.com/joeblew999/wellknown/pkg/pdf/web/api"
 	"github.com/joeblew999/wellknown/pkg/pdf/web/gui"
+	"github.com/joeblew999/wellknown/pkg/pdf/web/httputil"
 )
 
 // Server represents the PDF form web server
@@ -38,12 +41,27 @@ func (s *Server) Start() error {
 
 	mux := http.NewServeMux()
 
-	// Register API routes
-	s.apiHandler.RegisterRoutes(mux)
+	// Register API routes (CORS + rate limiting from CORS_ORIGINS / RATE_LIMIT_RPS)
+	apiMux := http.NewServeMux()
+	s.apiHandler.RegisterRoutes(apiMux)
+	mux.Handle("/api/", httputil.Guard(httputil.GuardConfigFromEnv(), apiMux))
 
 	// Register GUI routes
 	s.guiHandler.RegisterRoutes(mux)
 
+	// Health check (same JSON as the other wellknown binaries)
+	mux.Handle("/health", httputil.Health("pdf", map[string]httputil.HealthCheck{
+		"data_dir": func() error {
+			_, err := os.Stat(s.config.DataDir)
+			return err
+		},
+	}))
+
+	// Enforce retention policies while the server runs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pdfform.StartJanitor(ctx, s.config)
+
 	addr := fmt.Sprintf(":%d", s.port)
 
 	if s.https {
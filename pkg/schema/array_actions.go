@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ================================================================
+// Repeatable Groups (Array Add/Remove)
+// ================================================================
+// Array fields render their current items on the server. The add and remove
+// buttons are plain submit buttons named ArrayActionFieldName, so a click
+// posts the form back with the action; the handler applies it with
+// ApplyArrayAction, saves the data in the form session and renders the form
+// again, renumbering the items. No client-side templates are needed.
+//
+//	<button type="submit" name="_array" value="add:attendees" formnovalidate>
+//	<button type="submit" name="_array" value="remove:attendees:1" formnovalidate>
+
+// ArrayActionFieldName is the name of the add/remove item submit buttons
+const ArrayActionFieldName = "_array"
+
+// ApplyArrayAction applies an "add:<field>" or "remove:<field>:<index>"
+// action to the array property field of data (as decoded by FormDataToMap)
+// and returns the field name. Added items are empty ({} for arrays of
+// objects, "" otherwise); removed items close the gap.
+func ApplyArrayAction(data map[string]interface{}, jsonSchema *jsonschema.Schema, action string) (string, error) {
+	parts := strings.Split(action, ":")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("invalid array action %q", action)
+	}
+	field := parts[1]
+	prop, ok := jsonSchema.Properties[field]
+	if !ok || prop.Types == nil || !contains(prop.Types.ToStrings(), "array") {
+		return "", fmt.Errorf("%s is not an array field", field)
+	}
+	items, _ := data[field].([]interface{})
+
+	switch {
+	case parts[0] == "add" && len(parts) == 2:
+		if prop.MaxItems != nil && len(items) >= *prop.MaxItems {
+			return "", fmt.Errorf("%s allows at most %d items", field, *prop.MaxItems)
+		}
+		var item interface{} = ""
+		if itemSchema := arrayItemSchema(prop); itemSchema != nil && itemSchema.Types != nil && contains(itemSchema.Types.ToStrings(), "object") {
+			item = map[string]interface{}{}
+		}
+		data[field] = append(items, item)
+
+	case parts[0] == "remove" && len(parts) == 3:
+		index, err := strconv.Atoi(parts[2])
+		if err != nil || index < 0 || index >= len(items) {
+			return "", fmt.Errorf("invalid %s item index %q", field, parts[2])
+		}
+		data[field] = append(items[:index:index], items[index+1:]...)
+
+	default:
+		return "", fmt.Errorf("invalid array action %q", action)
+	}
+	return field, nil
+}
+
+// arrayItemSchema returns the schema every item of an array property follows
+// ("items" in draft-07, or draft 2020-12's), or nil
+func arrayItemSchema(prop *jsonschema.Schema) *jsonschema.Schema {
+	if itemSchema, ok := prop.Items.(*jsonschema.Schema); ok {
+		return itemSchema
+	}
+	return prop.Items2020
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
@@ -83,6 +84,7 @@ func ExtractMetadata(schemaPath string) (SchemaMetadata, error) {
 			}
 		}
 	}
+	sort.Strings(metadata.OptionalFields) // Stable across runs (map order)
 
 	return metadata, nil
 }
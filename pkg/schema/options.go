@@ -0,0 +1,205 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ================================================================
+// Select Options
+// ================================================================
+// A string property renders as a <select> when its options are known:
+//
+//	"color":    {"type": "string", "enum": ["red", "green"]}                 // value = label
+//	"country":  {"type": "string", "oneOf": [{"const": "AU", "title": "Australia"},
+//	                                          {"const": "NZ", "title": "New Zealand"}]}
+//	"timezone": {"type": "string", "x-optionsSource": "timezones"}           // Go callback
+//	"form":     {"type": "string", "x-optionsSource": "/api/options/forms"}  // URL, loaded by the page
+//
+// oneOf const/title pairs give human-readable labels and still validate the
+// value. x-optionsSource populates the select when the form renders instead
+// of baking the list into the schema: a name registered with
+// RegisterOptionsSource is called on the server; a URL ("/..." or http(s))
+// is fetched by the browser and must return a JSON array of strings or
+// {"value", "label"} objects (HandleOptionsSource serves registered sources
+// that way). Dynamic options are not validated.
+
+// OptionsSourceKeyword is the schema keyword naming a property's dynamic options
+const OptionsSourceKeyword = "x-optionsSource"
+
+// Option is one choice of a select
+type Option struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// OptionsFunc returns the current options of a dynamic source
+type OptionsFunc func() ([]Option, error)
+
+var (
+	optionsSourcesMu sync.RWMutex
+	optionsSources   = map[string]OptionsFunc{
+		"timezones": TimezoneOptions,
+	}
+)
+
+// RegisterOptionsSource makes fn available as "x-optionsSource": name.
+// Registering a name again replaces it.
+func RegisterOptionsSource(name string, fn OptionsFunc) {
+	optionsSourcesMu.Lock()
+	defer optionsSourcesMu.Unlock()
+	optionsSources[name] = fn
+}
+
+// IsURLOptionsSource reports whether source is fetched by the browser rather
+// than a registered callback
+func IsURLOptionsSource(source string) bool {
+	return strings.HasPrefix(source, "/") || strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// optionsSourceExt is the compiled x-optionsSource keyword; it carries the
+// source to the renderer and asserts nothing
+type optionsSourceExt struct {
+	source string
+}
+
+func (optionsSourceExt) Validate(*jsonschema.ValidatorContext, any) {}
+
+// registerOptionsVocabulary teaches the compiler x-optionsSource, so it
+// survives compilation for the renderer
+func registerOptionsVocabulary(c *jsonschema.Compiler) {
+	c.RegisterVocabulary(&jsonschema.Vocabulary{
+		URL: "https://github.com/joeblew999/wellknown/vocab/options",
+		Compile: func(_ *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			v, ok := obj[OptionsSourceKeyword]
+			if !ok {
+				return nil, nil
+			}
+			source, ok := v.(string)
+			if !ok || source == "" {
+				return nil, fmt.Errorf("%s must be a non-empty string", OptionsSourceKeyword)
+			}
+			return optionsSourceExt{source: source}, nil
+		},
+	})
+	c.AssertVocabs()
+}
+
+// OptionsSource returns the property's x-optionsSource, or ""
+func OptionsSource(prop *jsonschema.Schema) string {
+	for _, ext := range prop.Extensions {
+		if e, ok := ext.(optionsSourceExt); ok {
+			return e.source
+		}
+	}
+	return ""
+}
+
+// PropertyOptions returns the options of a select property: its enum values,
+// its oneOf const/title pairs, or the result of its registered options source.
+// ok is false when the property is not a select, or when its source is a URL
+// (the page loads those; see OptionsSource).
+func PropertyOptions(prop *jsonschema.Schema) (options []Option, ok bool, err error) {
+	if prop.Enum != nil && len(prop.Enum.Values) > 0 {
+		for _, v := range prop.Enum.Values {
+			value := fmt.Sprintf("%v", v)
+			options = append(options, Option{Value: value, Label: value})
+		}
+		return options, true, nil
+	}
+
+	if len(prop.OneOf) > 0 {
+		for _, alt := range prop.OneOf {
+			if alt.Const == nil {
+				options = nil
+				break
+			}
+			value := fmt.Sprintf("%v", *alt.Const)
+			label := alt.Title
+			if label == "" {
+				label = value
+			}
+			options = append(options, Option{Value: value, Label: label})
+		}
+		if options != nil {
+			return options, true, nil
+		}
+	}
+
+	source := OptionsSource(prop)
+	if source == "" || IsURLOptionsSource(source) {
+		return nil, false, nil
+	}
+	optionsSourcesMu.RLock()
+	fn, found := optionsSources[source]
+	optionsSourcesMu.RUnlock()
+	if !found {
+		return nil, false, fmt.Errorf("unknown options source %q", source)
+	}
+	options, err = fn()
+	if err != nil {
+		return nil, false, fmt.Errorf("options source %q: %w", source, err)
+	}
+	return options, true, nil
+}
+
+// HandleOptionsSource serves a registered source as JSON, so pages can load it
+// by URL; register it with a {name} wildcard:
+//
+//	mux.HandleFunc("/api/options/{name}", schema.HandleOptionsSource)
+func HandleOptionsSource(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	optionsSourcesMu.RLock()
+	fn, found := optionsSources[name]
+	optionsSourcesMu.RUnlock()
+	if !found {
+		http.Error(w, fmt.Sprintf("unknown options source %q", name), http.StatusNotFound)
+		return
+	}
+	options, err := fn()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(options)
+}
+
+// commonTimezones are the IANA zones offered by the "timezones" source
+var commonTimezones = []string{
+	"UTC",
+	"Africa/Cairo", "Africa/Johannesburg", "Africa/Lagos", "Africa/Nairobi",
+	"America/Anchorage", "America/Bogota", "America/Chicago", "America/Denver",
+	"America/Halifax", "America/Los_Angeles", "America/Mexico_City", "America/New_York",
+	"America/Phoenix", "America/Sao_Paulo", "America/Toronto", "America/Vancouver",
+	"Asia/Bangkok", "Asia/Dubai", "Asia/Hong_Kong", "Asia/Jakarta", "Asia/Kolkata",
+	"Asia/Manila", "Asia/Seoul", "Asia/Shanghai", "Asia/Singapore", "Asia/Tokyo",
+	"Atlantic/Reykjavik",
+	"Australia/Adelaide", "Australia/Brisbane", "Australia/Darwin", "Australia/Hobart",
+	"Australia/Melbourne", "Australia/Perth", "Australia/Sydney",
+	"Europe/Amsterdam", "Europe/Athens", "Europe/Berlin", "Europe/Dublin", "Europe/Istanbul",
+	"Europe/Lisbon", "Europe/London", "Europe/Madrid", "Europe/Moscow", "Europe/Paris",
+	"Europe/Rome", "Europe/Stockholm", "Europe/Warsaw", "Europe/Zurich",
+	"Pacific/Auckland", "Pacific/Fiji", "Pacific/Honolulu",
+}
+
+// TimezoneOptions is the built-in "timezones" source: common IANA zones,
+// labelled with their current UTC offset where the zone database is available
+func TimezoneOptions() ([]Option, error) {
+	now := time.Now()
+	options := make([]Option, 0, len(commonTimezones))
+	for _, name := range commonTimezones {
+		label := name
+		if loc, err := time.LoadLocation(name); err == nil {
+			label = fmt.Sprintf("%s (UTC%s)", name, now.In(loc).Format("-07:00"))
+		}
+		options = append(options, Option{Value: name, Label: label})
+	}
+	return options, nil
+}
@@ -0,0 +1,759 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ================================================================
+// PocketBase Collections
+// ================================================================
+// The JSON Schema that renders a form (GenerateFormHTML), validates it
+// (ValidatorV6) and seeds test data (pkg/testgen) also defines its database
+// table. PocketBaseCollection converts a schema's top-level properties, in
+// schema order, into a PocketBase collection (the dashboard's import/export
+// JSON), and PocketBaseMigration emits a Go migration for
+// pkg/cmd/pocketbase/pb_migrations:
+//
+//	string, minLength, maxLength, pattern  → text (min, max, pattern)
+//	format email / uri / date, date-time   → email / url / date
+//	integer, number, minimum, maximum      → number (onlyInt for integer)
+//	boolean                                → bool
+//	enum, or a oneOf of consts             → select
+//	array of enum                          → select with maxSelect (maxItems)
+//	x-ref "user" (see testgen.XRefKeyword) → relation to the user collection
+//	object, any other array                → json
+//
+// Required properties become required fields, except booleans and numbers
+// that may be 0: PocketBase's required means "not false" / "not zero". An
+// "id" property is skipped (PocketBase has its own), and every collection
+// gets created and updated autodate fields.
+
+// PocketBase field types produced by PocketBaseCollection
+const (
+	PBFieldText     = "text"
+	PBFieldEmail    = "email"
+	PBFieldURL      = "url"
+	PBFieldDate     = "date"
+	PBFieldNumber   = "number"
+	PBFieldBool     = "bool"
+	PBFieldSelect   = "select"
+	PBFieldRelation = "relation"
+	PBFieldJSON     = "json"
+	PBFieldAutodate = "autodate"
+)
+
+// pbMaxSelect is the maxSelect of a multi-relation without maxItems (the
+// PocketBase dashboard's default)
+const pbMaxSelect = 999
+
+// pbNamePattern is PocketBase's rule for collection and field names
+var pbNamePattern = regexp.MustCompile(`^\w+$`)
+
+// PBCollection is a PocketBase base collection definition
+type PBCollection struct {
+	Name   string    `json:"name"`
+	Type   string    `json:"type"` // Always "base"
+	Fields []PBField `json:"fields"`
+	Title  string    `json:"-"` // Schema title, a comment in migrations
+}
+
+// PBField is one field of a PBCollection. Min and Max are a text field's
+// length limits or a number field's range. CollectionID is a relation's
+// target collection name, which PocketBase accepts in place of its ID
+// (PocketBaseMigration resolves it).
+type PBField struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Required     bool     `json:"required,omitempty"`
+	Min          *float64 `json:"min,omitempty"`
+	Max          *float64 `json:"max,omitempty"`
+	Pattern      string   `json:"pattern,omitempty"`
+	OnlyInt      bool     `json:"onlyInt,omitempty"`
+	Values       []string `json:"values,omitempty"`
+	MaxSelect    int      `json:"maxSelect,omitempty"`
+	CollectionID string   `json:"collectionId,omitempty"`
+	OnCreate     bool     `json:"onCreate,omitempty"`
+	OnUpdate     bool     `json:"onUpdate,omitempty"`
+	Comment      string   `json:"-"` // Property title, a comment in migrations
+}
+
+// CollectionName turns a schema namespace ("google/calendar", see
+// testgen.Discover) into a collection name ("google_calendar")
+func CollectionName(namespace string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(namespace) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	name := strings.Trim(b.String(), "_")
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "c_" + name
+	}
+	return name
+}
+
+// pbSchema is the part of a JSON Schema a collection is generated from
+type pbSchema struct {
+	Title       string                     `json:"title"`
+	Required    []string                   `json:"required"`
+	Properties  map[string]json.RawMessage `json:"properties"`
+	Definitions map[string]json.RawMessage `json:"definitions"`
+	Defs        map[string]json.RawMessage `json:"$defs"`
+}
+
+// pbProperty is the part of a property schema a field is generated from
+type pbProperty struct {
+	Ref       string                   `json:"$ref"`
+	Type      interface{}              `json:"type"` // A type name or a list of them
+	Format    string                   `json:"format"`
+	Title     string                   `json:"title"`
+	Enum      []interface{}            `json:"enum"`
+	OneOf     []map[string]interface{} `json:"oneOf"`
+	MinLength *int                     `json:"minLength"`
+	MaxLength *int                     `json:"maxLength"`
+	Pattern   string                   `json:"pattern"`
+	Minimum   *float64                 `json:"minimum"`
+	Maximum   *float64                 `json:"maximum"`
+	MaxItems  *int                     `json:"maxItems"`
+	Items     json.RawMessage          `json:"items"`
+	XRef      string                   `json:"x-ref"` // testgen.XRefKeyword
+}
+
+// PocketBaseCollection converts a JSON Schema document into a definition of
+// the collection name
+func PocketBaseCollection(name string, schemaJSON []byte) (*PBCollection, error) {
+	if !pbNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid collection name %q (letters, digits and _ only)", name)
+	}
+	var s pbSchema
+	if err := json.Unmarshal(schemaJSON, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(schemaJSON, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	order, err := objectKeys(raw["properties"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema properties: %w", err)
+	}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	c := &PBCollection{Name: name, Type: "base", Title: s.Title}
+	names := make(map[string]bool)
+	for _, prop := range order {
+		if prop == "id" || names[prop] {
+			continue
+		}
+		if !pbNamePattern.MatchString(prop) {
+			return nil, fmt.Errorf("property %q cannot be a PocketBase field name (letters, digits and _ only)", prop)
+		}
+		p, err := s.property(s.Properties[prop])
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", prop, err)
+		}
+		field, err := s.field(prop, p, required[prop])
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", prop, err)
+		}
+		c.Fields = append(c.Fields, field)
+		names[prop] = true
+	}
+	if !names["created"] {
+		c.Fields = append(c.Fields, PBField{Name: "created", Type: PBFieldAutodate, OnCreate: true})
+	}
+	if !names["updated"] {
+		c.Fields = append(c.Fields, PBField{Name: "updated", Type: PBFieldAutodate, OnCreate: true, OnUpdate: true})
+	}
+	return c, nil
+}
+
+// property decodes a property schema, following local $refs
+// (#/definitions/x, #/$defs/x)
+func (s *pbSchema) property(raw json.RawMessage) (*pbProperty, error) {
+	for depth := 0; ; depth++ {
+		var p pbProperty
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &p); err != nil {
+				return nil, err
+			}
+		}
+		if p.Ref == "" {
+			return &p, nil
+		}
+		if depth == 10 {
+			return nil, fmt.Errorf("$ref %s: too deeply nested", p.Ref)
+		}
+		var ok bool
+		switch {
+		case strings.HasPrefix(p.Ref, "#/definitions/"):
+			raw, ok = s.Definitions[strings.TrimPrefix(p.Ref, "#/definitions/")]
+		case strings.HasPrefix(p.Ref, "#/$defs/"):
+			raw, ok = s.Defs[strings.TrimPrefix(p.Ref, "#/$defs/")]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unsupported $ref %s", p.Ref)
+		}
+	}
+}
+
+// field maps one property to a PocketBase field
+func (s *pbSchema) field(name string, p *pbProperty, required bool) (PBField, error) {
+	f := PBField{Name: name, Comment: p.Title}
+	typ := p.primaryType()
+
+	// "user.email" copies a field of the referenced record: not a relation
+	if p.XRef != "" && !strings.Contains(p.XRef, ".") {
+		f.Type = PBFieldRelation
+		f.CollectionID = CollectionName(p.XRef)
+		f.Required = required
+		f.MaxSelect = 1
+		if typ == "array" {
+			f.MaxSelect = pbMaxSelect
+			if p.MaxItems != nil && *p.MaxItems > 0 {
+				f.MaxSelect = *p.MaxItems
+			}
+		}
+		return f, nil
+	}
+
+	if values := p.options(); len(values) > 0 {
+		f.Type = PBFieldSelect
+		f.Values = values
+		f.MaxSelect = 1
+		f.Required = required
+		return f, nil
+	}
+
+	switch typ {
+	case "array":
+		items, err := s.property(p.Items)
+		if err != nil {
+			return f, fmt.Errorf("items: %w", err)
+		}
+		if values := items.options(); len(values) > 0 {
+			f.Type = PBFieldSelect
+			f.Values = values
+			f.MaxSelect = len(values)
+			if p.MaxItems != nil && *p.MaxItems > 0 && *p.MaxItems < len(values) {
+				f.MaxSelect = *p.MaxItems
+			}
+			f.Required = required
+			return f, nil
+		}
+		f.Type = PBFieldJSON
+
+	case "string":
+		switch p.Format {
+		case "email":
+			f.Type = PBFieldEmail
+		case "uri", "url", "iri":
+			f.Type = PBFieldURL
+		case "date", "date-time", "datetime-local":
+			f.Type = PBFieldDate
+		default:
+			f.Type = PBFieldText
+			if p.MinLength != nil && *p.MinLength > 0 {
+				f.Min = floatPtr(float64(*p.MinLength))
+			}
+			if p.MaxLength != nil {
+				f.Max = floatPtr(float64(*p.MaxLength))
+			}
+			f.Pattern = p.Pattern
+		}
+
+	case "integer", "number":
+		f.Type = PBFieldNumber
+		f.OnlyInt = typ == "integer"
+		f.Min, f.Max = p.Minimum, p.Maximum
+		// Required numbers reject 0, so only when the schema does too
+		zeroInvalid := (p.Minimum != nil && *p.Minimum > 0) || (p.Maximum != nil && *p.Maximum < 0)
+		f.Required = required && zeroInvalid
+		return f, nil
+
+	case "boolean":
+		// A required bool must be true: never what "required" means in a schema
+		f.Type = PBFieldBool
+		return f, nil
+
+	default:
+		f.Type = PBFieldJSON
+	}
+	f.Required = required
+	return f, nil
+}
+
+// primaryType returns the property's type, the first non-null one of a list
+func (p *pbProperty) primaryType() string {
+	switch t := p.Type.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// options returns the property's enum values, or the consts of a oneOf in
+// which every choice is a const (see FromStruct's options=)
+func (p *pbProperty) options() []string {
+	var values []string
+	for _, v := range p.Enum {
+		if v != nil {
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	if len(values) > 0 || len(p.OneOf) == 0 {
+		return values
+	}
+	for _, choice := range p.OneOf {
+		v, ok := choice["const"]
+		if !ok {
+			return nil
+		}
+		if v != nil {
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	return values
+}
+
+// objectKeys returns the keys of a JSON object in document order
+func objectKeys(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected an object")
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, tok.(string))
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// MigrationFilename returns the file name of a migration created at t, e.g.
+// 1731400000_init_short_links.go
+func MigrationFilename(t time.Time, name string) string {
+	return fmt.Sprintf("%d_%s.go", t.Unix(), CollectionName(name))
+}
+
+// PocketBaseMigration returns the Go source of a migration, for
+// pkg/cmd/pocketbase/pb_migrations, that creates collections and deletes them
+// again on the way down. Relation fields are added once every collection
+// exists, so collections may relate to each other (or themselves); a
+// relation to a collection not in the list is looked up by name, and a
+// short x-ref such as "user" also matches a listed "acme_user".
+func PocketBaseMigration(collections []*PBCollection) ([]byte, error) {
+	if len(collections) == 0 {
+		return nil, fmt.Errorf("no collections")
+	}
+	ordered := relationOrder(collections)
+	w := newMigrationWriter(ordered)
+
+	fmt.Fprintf(&w.b, "\t\t// Up: Create %s collections\n", strings.Join(w.names, ", "))
+	w.b.WriteString("\t\tfunc(txApp core.App) error {\n")
+	if err := w.createCollections(ordered); err != nil {
+		return nil, err
+	}
+	w.b.WriteString("\t\t\treturn nil\n\t\t},\n\n")
+
+	fmt.Fprintf(&w.b, "\t\t// Down: Remove %s collections\n", strings.Join(w.names, ", "))
+	w.b.WriteString("\t\tfunc(txApp core.App) error {\n")
+	w.deleteCollections(w.names)
+	w.b.WriteString("\t\t\treturn nil\n\t\t},\n")
+
+	return w.source("// Code generated from JSON Schema (schema.PocketBaseMigration). DO NOT EDIT.")
+}
+
+// migrationWriter builds the body of a pb_migrations file
+type migrationWriter struct {
+	b         strings.Builder
+	names     []string          // Collections the migration defines (relation targets)
+	vars      map[string]string // Collection name → Go variable
+	used      map[string]bool   // Go variables in use
+	declared  map[string]bool   // Variables declared in the current function
+	usesTypes bool              // The types package is imported
+}
+
+func newMigrationWriter(collections []*PBCollection) *migrationWriter {
+	w := &migrationWriter{vars: make(map[string]string), used: make(map[string]bool), declared: make(map[string]bool)}
+	for _, c := range collections {
+		w.names = append(w.names, c.Name)
+		w.variable(c.Name)
+	}
+	return w
+}
+
+// variable returns the Go variable holding a collection
+func (w *migrationWriter) variable(name string) string {
+	if v, ok := w.vars[name]; ok {
+		return v
+	}
+	base := goIdent(name) + "Collection"
+	v := base
+	for n := 2; w.used[v]; n++ {
+		v = base + strconv.Itoa(n)
+	}
+	w.vars[name], w.used[v] = v, true
+	return v
+}
+
+// lookup declares the variable of an existing collection, once per function
+func (w *migrationWriter) lookup(name string) string {
+	v := w.variable(name)
+	if !w.declared[v] {
+		w.declared[v] = true
+		fmt.Fprintf(&w.b, "\t\t\t%s, err := txApp.FindCollectionByNameOrId(%q)\n\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n", v, name)
+	}
+	return v
+}
+
+// addFields writes v.Fields.Add(...) for fields, resolving relation targets
+// (which must already be declared or exist)
+func (w *migrationWriter) addFields(collection, v string, fields []PBField) error {
+	resolved := make([]PBField, len(fields))
+	for i, f := range fields {
+		if f.Type == PBFieldRelation {
+			target := resolveTarget(f.CollectionID, w.names)
+			f.CollectionID = w.lookup(target) + ".Id"
+		}
+		resolved[i] = f
+	}
+	fmt.Fprintf(&w.b, "\t\t\t%s.Fields.Add(\n", v)
+	for _, f := range resolved {
+		lit, types, err := fieldLiteral(f, f.CollectionID)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", collection, f.Name, err)
+		}
+		w.usesTypes = w.usesTypes || types
+		w.b.WriteString(lit)
+	}
+	w.b.WriteString("\t\t\t)\n")
+	return nil
+}
+
+// save writes txApp.Save(v)
+func (w *migrationWriter) save(v string) {
+	fmt.Fprintf(&w.b, "\t\t\tif err := txApp.Save(%s); err != nil {\n\t\t\t\treturn err\n\t\t\t}\n\n", v)
+}
+
+// createCollections creates collections (in relation order) and then adds
+// their relation fields
+func (w *migrationWriter) createCollections(collections []*PBCollection) error {
+	for _, c := range collections {
+		if !pbNamePattern.MatchString(c.Name) {
+			return fmt.Errorf("invalid collection name %q", c.Name)
+		}
+		v := w.variable(c.Name)
+		comment := c.Name
+		if c.Title != "" {
+			comment = fmt.Sprintf("%s (%s)", c.Name, oneLine(c.Title))
+		}
+		fmt.Fprintf(&w.b, "\t\t\t// Create %s collection\n", comment)
+		fmt.Fprintf(&w.b, "\t\t\t%s := core.NewBaseCollection(%q)\n", v, c.Name)
+		w.declared[v] = true
+		if err := w.addFields(c.Name, v, withoutRelations(c.Fields)); err != nil {
+			return err
+		}
+		w.save(v)
+	}
+
+	// Relations, now that every target exists
+	for _, c := range collections {
+		rels := relations(c.Fields)
+		if len(rels) == 0 {
+			continue
+		}
+		fmt.Fprintf(&w.b, "\t\t\t// Add %s relations\n", c.Name)
+		v := w.variable(c.Name)
+		if err := w.addFields(c.Name, v, rels); err != nil {
+			return err
+		}
+		w.save(v)
+	}
+	return nil
+}
+
+// deleteCollections deletes collections, referencing ones (listed last)
+// first, ignoring those already gone
+func (w *migrationWriter) deleteCollections(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	reversed := make([]string, len(names))
+	for i, n := range names {
+		reversed[len(names)-1-i] = strconv.Quote(n)
+	}
+	fmt.Fprintf(&w.b, "\t\t\tfor _, name := range []string{%s} {\n", strings.Join(reversed, ", "))
+	w.b.WriteString("\t\t\t\tcollection, err := txApp.FindCollectionByNameOrId(name)\n\t\t\t\tif err != nil {\n\t\t\t\t\tcontinue\n\t\t\t\t}\n")
+	w.b.WriteString("\t\t\t\tif err := txApp.Delete(collection); err != nil {\n\t\t\t\t\treturn err\n\t\t\t\t}\n\t\t\t}\n")
+}
+
+// source wraps the written Register arguments into a formatted Go file
+func (w *migrationWriter) source(header string) ([]byte, error) {
+	imports := "\t\"github.com/pocketbase/pocketbase/core\"\n"
+	if w.usesTypes {
+		imports += "\t\"github.com/pocketbase/pocketbase/tools/types\"\n"
+	}
+	src := fmt.Sprintf("%s\n\npackage pb_migrations\n\nimport (\n%s)\n\nfunc init() {\n\tcore.AppMigrations.Register(\n%s\t)\n}\n",
+		header, imports, w.b.String())
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format migration: %w", err)
+	}
+	return formatted, nil
+}
+
+// relations returns the relation fields of fields
+func relations(fields []PBField) []PBField {
+	var rels []PBField
+	for _, f := range fields {
+		if f.Type == PBFieldRelation {
+			rels = append(rels, f)
+		}
+	}
+	return rels
+}
+
+// withoutRelations returns the other fields of fields
+func withoutRelations(fields []PBField) []PBField {
+	var rest []PBField
+	for _, f := range fields {
+		if f.Type != PBFieldRelation {
+			rest = append(rest, f)
+		}
+	}
+	return rest
+}
+
+// fieldLiteral returns the core.*Field composite literal of f and whether it
+// uses the types package. collectionID is the Go expression of a relation's
+// target ID.
+func fieldLiteral(f PBField, collectionID string) (string, bool, error) {
+	typeName := fieldTypeName(f.Type)
+	if typeName == "" {
+		return "", false, fmt.Errorf("unsupported field type %q", f.Type)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\t\t\t\t&core.%s{\n", typeName)
+	fmt.Fprintf(&b, "\t\t\t\tName: %q,", f.Name)
+	if f.Comment != "" {
+		fmt.Fprintf(&b, " // %s", oneLine(f.Comment))
+	}
+	b.WriteString("\n")
+	if f.Required {
+		b.WriteString("\t\t\t\tRequired: true,\n")
+	}
+
+	usesTypes := false
+	switch f.Type {
+	case PBFieldText:
+		if f.Min != nil {
+			fmt.Fprintf(&b, "\t\t\t\tMin: %d,\n", int(*f.Min))
+		}
+		if f.Max != nil {
+			fmt.Fprintf(&b, "\t\t\t\tMax: %d,\n", int(*f.Max))
+		}
+		if f.Pattern != "" {
+			fmt.Fprintf(&b, "\t\t\t\tPattern: %s,\n", goString(f.Pattern))
+		}
+	case PBFieldNumber:
+		if f.Min != nil {
+			fmt.Fprintf(&b, "\t\t\t\tMin: types.Pointer(%s),\n", goFloat(*f.Min))
+			usesTypes = true
+		}
+		if f.Max != nil {
+			fmt.Fprintf(&b, "\t\t\t\tMax: types.Pointer(%s),\n", goFloat(*f.Max))
+			usesTypes = true
+		}
+		if f.OnlyInt {
+			b.WriteString("\t\t\t\tOnlyInt: true,\n")
+		}
+	case PBFieldSelect:
+		quoted := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			quoted[i] = strconv.Quote(v)
+		}
+		fmt.Fprintf(&b, "\t\t\t\tMaxSelect: %d,\n", f.MaxSelect)
+		fmt.Fprintf(&b, "\t\t\t\tValues: []string{%s},\n", strings.Join(quoted, ", "))
+	case PBFieldRelation:
+		fmt.Fprintf(&b, "\t\t\t\tCollectionId: %s,\n", collectionID)
+		if f.MaxSelect > 1 {
+			fmt.Fprintf(&b, "\t\t\t\tMaxSelect: %d,\n", f.MaxSelect)
+		}
+	case PBFieldAutodate:
+		if f.OnCreate {
+			b.WriteString("\t\t\t\tOnCreate: true,\n")
+		}
+		if f.OnUpdate {
+			b.WriteString("\t\t\t\tOnUpdate: true,\n")
+		}
+	}
+	b.WriteString("\t\t\t\t},\n")
+	return b.String(), usesTypes, nil
+}
+
+// fieldTypeName returns the core type of a field type ("" if unsupported)
+func fieldTypeName(fieldType string) string {
+	switch fieldType {
+	case PBFieldText:
+		return "TextField"
+	case PBFieldEmail:
+		return "EmailField"
+	case PBFieldURL:
+		return "URLField"
+	case PBFieldDate:
+		return "DateField"
+	case PBFieldNumber:
+		return "NumberField"
+	case PBFieldBool:
+		return "BoolField"
+	case PBFieldSelect:
+		return "SelectField"
+	case PBFieldRelation:
+		return "RelationField"
+	case PBFieldJSON:
+		return "JSONField"
+	case PBFieldAutodate:
+		return "AutodateField"
+	}
+	return ""
+}
+
+// relationOrder orders collections so relation targets come before the
+// collections relating to them (input order otherwise; cycles keep it)
+func relationOrder(collections []*PBCollection) []*PBCollection {
+	names := make([]string, len(collections))
+	byName := make(map[string]*PBCollection, len(collections))
+	for i, c := range collections {
+		names[i] = c.Name
+		byName[c.Name] = c
+	}
+
+	var ordered []*PBCollection
+	state := make(map[string]int) // 1 visiting, 2 done
+	var visit func(c *PBCollection)
+	visit = func(c *PBCollection) {
+		if state[c.Name] != 0 {
+			return
+		}
+		state[c.Name] = 1
+		for _, f := range c.Fields {
+			if f.Type != PBFieldRelation {
+				continue
+			}
+			if target, ok := byName[resolveTarget(f.CollectionID, names)]; ok {
+				visit(target)
+			}
+		}
+		state[c.Name] = 2
+		ordered = append(ordered, c)
+	}
+	for _, c := range collections {
+		visit(c)
+	}
+	return ordered
+}
+
+// resolveTarget returns the listed collection a relation refers to: the
+// exact name, else the only one ending in "_"+name; otherwise name itself
+func resolveTarget(name string, listed []string) string {
+	match := ""
+	for _, n := range listed {
+		if n == name {
+			return n
+		}
+		if strings.HasSuffix(n, "_"+name) {
+			if match != "" {
+				return name // Ambiguous
+			}
+			match = n
+		}
+	}
+	if match != "" {
+		return match
+	}
+	return name
+}
+
+// goIdent turns a collection name into a lowerCamelCase Go identifier
+func goIdent(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' })
+	var b strings.Builder
+	for i, p := range parts {
+		if i == 0 {
+			b.WriteString(p)
+			continue
+		}
+		b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+	}
+	id := b.String()
+	if id == "" || unicode.IsDigit(rune(id[0])) {
+		id = "c" + id
+	}
+	return id
+}
+
+// goString quotes s as a Go string, as a raw string when that reads better
+// (regular expressions)
+func goString(s string) string {
+	if strings.Contains(s, `\`) && !strings.ContainsAny(s, "`\n\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// goFloat formats v as an untyped float constant
+func goFloat(v float64) string {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return "0.0"
+	}
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+// oneLine flattens text for a // comment
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
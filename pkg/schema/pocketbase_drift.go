@@ -0,0 +1,307 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ================================================================
+// PocketBase Schema Drift
+// ================================================================
+// A schema with an x-collection keyword is the canonical definition of that
+// PocketBase collection:
+//
+//	{"title": "Reminder", "x-collection": "reminders", "properties": {...}}
+//
+// DiffCollection compares the collection PocketBaseCollection derives from
+// it with the live one (the server's startup check and "wellknown schema
+// drift" read it from the database) and reports what the database lacks.
+// Fields the database has beyond the schema are not drift, nor are missing
+// created/updated autodate fields (the schema does not declare them). Missing
+// collections and fields, required flags, select values and multiplicity are
+// fixed by PocketBaseDriftMigration; type changes and relations pointing
+// elsewhere need a hand-written data migration.
+
+// PBCollectionKeyword names the collection a schema is canonical for
+const PBCollectionKeyword = "x-collection"
+
+// Drift kinds reported by DiffCollection
+const (
+	DriftMissingCollection = "missing_collection"
+	DriftMissingField      = "missing_field"
+	DriftType              = "type"
+	DriftRequired          = "required"
+	DriftSelectValues      = "select_values"
+	DriftMaxSelect         = "max_select"
+	DriftRelation          = "relation"
+)
+
+// PBDrift is one difference between a canonical and a live collection
+type PBDrift struct {
+	Collection string   `json:"collection"`
+	Field      string   `json:"field,omitempty"`
+	Kind       string   `json:"kind"`
+	Want       string   `json:"want,omitempty"`   // What the schema defines
+	Got        string   `json:"got,omitempty"`    // What the database has
+	Values     []string `json:"values,omitempty"` // Missing select values
+}
+
+func (d PBDrift) String() string {
+	switch d.Kind {
+	case DriftMissingCollection:
+		return fmt.Sprintf("%s: collection is missing", d.Collection)
+	case DriftMissingField:
+		return fmt.Sprintf("%s.%s: %s field is missing", d.Collection, d.Field, d.Want)
+	case DriftSelectValues:
+		return fmt.Sprintf("%s.%s: select lacks values %s", d.Collection, d.Field, d.Want)
+	}
+	return fmt.Sprintf("%s.%s: %s is %s, schema wants %s", d.Collection, d.Field, d.Kind, d.Got, d.Want)
+}
+
+// Fixable reports whether PocketBaseDriftMigration corrects the drift
+func (d PBDrift) Fixable() bool {
+	return d.Kind != DriftType && d.Kind != DriftRelation
+}
+
+// SchemaCollectionName returns the schema's x-collection ("" when it has
+// none)
+func SchemaCollectionName(schemaJSON []byte) string {
+	var s struct {
+		Collection string `json:"x-collection"`
+	}
+	json.Unmarshal(schemaJSON, &s)
+	return s.Collection
+}
+
+// ParsePBFields reads PocketBase's JSON of a collection's fields
+// (json.Marshal(collection.Fields)), keeping what PBField models. Relation
+// targets stay collection IDs; map them to names before DiffCollection.
+func ParsePBFields(data []byte) ([]PBField, error) {
+	var raw []map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse fields: %w", err)
+	}
+	fields := make([]PBField, len(raw))
+	for i, m := range raw {
+		f := PBField{}
+		f.Name, _ = m["name"].(string)
+		f.Type, _ = m["type"].(string)
+		f.Required, _ = m["required"].(bool)
+		f.Pattern, _ = m["pattern"].(string)
+		f.OnlyInt, _ = m["onlyInt"].(bool)
+		f.CollectionID, _ = m["collectionId"].(string)
+		f.OnCreate, _ = m["onCreate"].(bool)
+		f.OnUpdate, _ = m["onUpdate"].(bool)
+		// Dates have string limits: only numeric ones are kept
+		if v, ok := m["min"].(float64); ok {
+			f.Min = floatPtr(v)
+		}
+		if v, ok := m["max"].(float64); ok {
+			f.Max = floatPtr(v)
+		}
+		if v, ok := m["maxSelect"].(float64); ok {
+			f.MaxSelect = int(v)
+		}
+		if values, ok := m["values"].([]interface{}); ok {
+			for _, v := range values {
+				f.Values = append(f.Values, fmt.Sprint(v))
+			}
+		}
+		fields[i] = f
+	}
+	return fields, nil
+}
+
+// DiffCollection reports how live (nil when the collection does not exist)
+// differs from want
+func DiffCollection(want, live *PBCollection) []PBDrift {
+	if live == nil {
+		return []PBDrift{{Collection: want.Name, Kind: DriftMissingCollection}}
+	}
+	liveFields := make(map[string]PBField, len(live.Fields))
+	for _, f := range live.Fields {
+		liveFields[f.Name] = f
+	}
+
+	var drifts []PBDrift
+	add := func(f PBField, kind, want, got string) {
+		drifts = append(drifts, PBDrift{Collection: live.Name, Field: f.Name, Kind: kind, Want: want, Got: got})
+	}
+	for _, w := range want.Fields {
+		l, ok := liveFields[w.Name]
+		if !ok && w.Type == PBFieldAutodate {
+			continue
+		}
+		if !ok {
+			add(w, DriftMissingField, w.Type, "")
+			continue
+		}
+		if l.Type != w.Type {
+			add(w, DriftType, w.Type, l.Type)
+			continue
+		}
+		if l.Required != w.Required {
+			add(w, DriftRequired, strconv.FormatBool(w.Required), strconv.FormatBool(l.Required))
+		}
+		switch w.Type {
+		case PBFieldSelect:
+			if missing := missingValues(w.Values, l.Values); len(missing) > 0 {
+				add(w, DriftSelectValues, strings.Join(missing, ", "), "")
+				drifts[len(drifts)-1].Values = missing
+			}
+			if multiple(w.MaxSelect) != multiple(l.MaxSelect) {
+				add(w, DriftMaxSelect, strconv.Itoa(w.MaxSelect), strconv.Itoa(l.MaxSelect))
+			}
+		case PBFieldRelation:
+			if l.CollectionID != w.CollectionID && !strings.HasSuffix(l.CollectionID, "_"+w.CollectionID) {
+				add(w, DriftRelation, w.CollectionID, l.CollectionID)
+			} else if multiple(w.MaxSelect) != multiple(l.MaxSelect) {
+				add(w, DriftMaxSelect, strconv.Itoa(w.MaxSelect), strconv.Itoa(l.MaxSelect))
+			}
+		}
+	}
+	return drifts
+}
+
+// multiple reports whether a maxSelect allows several values
+func multiple(maxSelect int) bool {
+	return maxSelect > 1
+}
+
+// missingValues returns the values of want not in got
+func missingValues(want, got []string) []string {
+	have := make(map[string]bool, len(got))
+	for _, v := range got {
+		have[v] = true
+	}
+	var missing []string
+	for _, v := range want {
+		if !have[v] {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// PocketBaseDriftMigration returns the Go source of a migration correcting
+// the fixable drifts between canonical collections and the database: it
+// creates missing collections, adds missing fields, and updates required
+// flags, select values and maxSelect. Unfixable drifts become comments to
+// act on. Down removes what Up added; updated fields are left as they are.
+func PocketBaseDriftMigration(want []*PBCollection, drifts []PBDrift) ([]byte, error) {
+	if len(drifts) == 0 {
+		return nil, fmt.Errorf("no drift to correct")
+	}
+	byName := make(map[string]*PBCollection, len(want))
+	for _, c := range want {
+		byName[c.Name] = c
+	}
+	w := newMigrationWriter(relationOrder(want))
+
+	// Group by collection, in canonical order
+	var create []*PBCollection
+	changes := make(map[string][]PBDrift)
+	var changed []string
+	for _, name := range w.names {
+		for _, d := range drifts {
+			if d.Collection != name {
+				continue
+			}
+			if d.Kind == DriftMissingCollection {
+				create = append(create, byName[name])
+				continue
+			}
+			if len(changes[name]) == 0 {
+				changed = append(changed, name)
+			}
+			changes[name] = append(changes[name], d)
+		}
+	}
+	for _, d := range drifts {
+		if byName[d.Collection] == nil {
+			return nil, fmt.Errorf("drift in %s, which is not a canonical collection", d.Collection)
+		}
+	}
+
+	names := append(collectionNames(create), changed...)
+	fmt.Fprintf(&w.b, "\t\t// Up: Correct schema drift in %s\n", strings.Join(names, ", "))
+	w.b.WriteString("\t\tfunc(txApp core.App) error {\n")
+	if err := w.createCollections(create); err != nil {
+		return nil, err
+	}
+	added := make(map[string][]string)
+	for _, name := range changed {
+		v := w.lookup(name)
+		var fields []PBField
+		for _, d := range changes[name] {
+			f := fieldNamed(byName[name], d.Field)
+			switch d.Kind {
+			case DriftMissingField:
+				fields = append(fields, f)
+				added[name] = append(added[name], f.Name)
+			case DriftRequired:
+				fmt.Fprintf(&w.b, "\t\t\tif f, ok := %s.Fields.GetByName(%q).(*core.%s); ok {\n\t\t\t\tf.Required = %t\n\t\t\t}\n",
+					v, f.Name, fieldTypeName(f.Type), f.Required)
+			case DriftSelectValues:
+				quoted := make([]string, len(d.Values))
+				for i, value := range d.Values {
+					quoted[i] = strconv.Quote(value)
+				}
+				fmt.Fprintf(&w.b, "\t\t\tif f, ok := %s.Fields.GetByName(%q).(*core.SelectField); ok {\n\t\t\t\tf.Values = append(f.Values, %s)\n\t\t\t}\n",
+					v, f.Name, strings.Join(quoted, ", "))
+			case DriftMaxSelect:
+				fmt.Fprintf(&w.b, "\t\t\tif f, ok := %s.Fields.GetByName(%q).(*core.%s); ok {\n\t\t\t\tf.MaxSelect = %d\n\t\t\t}\n",
+					v, f.Name, fieldTypeName(f.Type), f.MaxSelect)
+			default:
+				fmt.Fprintf(&w.b, "\t\t\t// TODO: %s (needs a data migration)\n", oneLine(d.String()))
+			}
+		}
+		if len(fields) > 0 {
+			if err := w.addFields(name, v, fields); err != nil {
+				return nil, err
+			}
+		}
+		w.save(v)
+	}
+	w.b.WriteString("\t\t\treturn nil\n\t\t},\n\n")
+
+	// Down: a fresh function, so collections are looked up again
+	w.declared = make(map[string]bool)
+	fmt.Fprintf(&w.b, "\t\t// Down: Remove what the correction added to %s\n", strings.Join(names, ", "))
+	w.b.WriteString("\t\tfunc(txApp core.App) error {\n")
+	for _, name := range changed {
+		if len(added[name]) == 0 {
+			continue
+		}
+		v := w.lookup(name)
+		for _, field := range added[name] {
+			fmt.Fprintf(&w.b, "\t\t\t%s.Fields.RemoveByName(%q)\n", v, field)
+		}
+		w.save(v)
+	}
+	w.deleteCollections(collectionNames(create))
+	w.b.WriteString("\t\t\treturn nil\n\t\t},\n")
+
+	return w.source("// Generated from JSON Schema drift (schema.PocketBaseDriftMigration).\n// Review before running: it changes existing collections.")
+}
+
+// fieldNamed returns the field of c called name
+func fieldNamed(c *PBCollection, name string) PBField {
+	for _, f := range c.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return PBField{Name: name}
+}
+
+// collectionNames returns the names of collections
+func collectionNames(collections []*PBCollection) []string {
+	names := make([]string, len(collections))
+	for i, c := range collections {
+		names[i] = c.Name
+	}
+	return names
+}
@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// driftWant is the canonical collection the drift tests compare against
+func driftWant(t *testing.T) *PBCollection {
+	t.Helper()
+	c, err := PocketBaseCollection("events", []byte(`{
+		"title": "Event",
+		"x-collection": "events",
+		"properties": {
+			"title":     {"type": "string", "minLength": 1},
+			"status":    {"type": "string", "enum": ["draft", "published", "cancelled"]},
+			"tags":      {"type": "array", "items": {"enum": ["work", "home"]}},
+			"organizer": {"type": "string", "x-ref": "users"},
+			"seats":     {"type": "integer", "minimum": 1}
+		},
+		"required": ["title", "organizer", "seats"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// driftLive returns the live collection matching driftWant, changed by edit
+func driftLive(edit func(fields map[string]*PBField)) *PBCollection {
+	fields := []PBField{
+		{Name: "id", Type: PBFieldText, Required: true},
+		{Name: "title", Type: PBFieldText, Required: true},
+		{Name: "status", Type: PBFieldSelect, Values: []string{"draft", "published", "cancelled"}, MaxSelect: 1},
+		{Name: "tags", Type: PBFieldSelect, Values: []string{"work", "home"}, MaxSelect: 2},
+		{Name: "organizer", Type: PBFieldRelation, Required: true, CollectionID: "users", MaxSelect: 1},
+		{Name: "seats", Type: PBFieldNumber, Required: true},
+		{Name: "notes", Type: PBFieldText},
+	}
+	byName := make(map[string]*PBField, len(fields))
+	for i := range fields {
+		byName[fields[i].Name] = &fields[i]
+	}
+	if edit != nil {
+		edit(byName)
+	}
+	var kept []PBField
+	for _, f := range fields {
+		if byName[f.Name] != nil {
+			kept = append(kept, *byName[f.Name])
+		}
+	}
+	return &PBCollection{Name: "events", Type: "base", Fields: kept}
+}
+
+func TestDiffCollection(t *testing.T) {
+	tests := []struct {
+		name string
+		live *PBCollection
+		want []PBDrift
+	}{
+		{
+			name: "in sync, extra fields and missing autodates ignored",
+			live: driftLive(nil),
+		},
+		{
+			name: "missing collection",
+			live: nil,
+			want: []PBDrift{{Collection: "events", Kind: DriftMissingCollection}},
+		},
+		{
+			name: "missing field",
+			live: driftLive(func(f map[string]*PBField) { delete(f, "seats") }),
+			want: []PBDrift{{Collection: "events", Field: "seats", Kind: DriftMissingField, Want: PBFieldNumber}},
+		},
+		{
+			name: "type mismatch",
+			live: driftLive(func(f map[string]*PBField) { f["seats"].Type = PBFieldText }),
+			want: []PBDrift{{Collection: "events", Field: "seats", Kind: DriftType, Want: PBFieldNumber, Got: PBFieldText}},
+		},
+		{
+			name: "relation target mismatch",
+			live: driftLive(func(f map[string]*PBField) { f["organizer"].CollectionID = "accounts" }),
+			want: []PBDrift{{Collection: "events", Field: "organizer", Kind: DriftRelation, Want: "users", Got: "accounts"}},
+		},
+		{
+			name: "relation to a prefixed collection",
+			live: driftLive(func(f map[string]*PBField) { f["organizer"].CollectionID = "acme_users" }),
+		},
+		{
+			name: "required flag",
+			live: driftLive(func(f map[string]*PBField) { f["title"].Required = false }),
+			want: []PBDrift{{Collection: "events", Field: "title", Kind: DriftRequired, Want: "true", Got: "false"}},
+		},
+		{
+			name: "select values and multiplicity",
+			live: driftLive(func(f map[string]*PBField) {
+				f["status"].Values = []string{"draft"}
+				f["tags"].MaxSelect = 1
+			}),
+			want: []PBDrift{
+				{Collection: "events", Field: "status", Kind: DriftSelectValues, Want: "published, cancelled", Values: []string{"published", "cancelled"}},
+				{Collection: "events", Field: "tags", Kind: DriftMaxSelect, Want: "2", Got: "1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffCollection(driftWant(t), tt.live)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffCollection() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePBFields(t *testing.T) {
+	// As json.Marshal(collection.Fields) writes them: dates have string limits
+	fields, err := ParsePBFields([]byte(`[
+		{"name": "title", "type": "text", "required": true, "min": 1, "max": 0, "pattern": "^\\w+$"},
+		{"name": "start", "type": "date", "min": "", "max": ""},
+		{"name": "seats", "type": "number", "onlyInt": true, "min": 1, "max": null},
+		{"name": "status", "type": "select", "values": ["a", "b"], "maxSelect": 1},
+		{"name": "owner", "type": "relation", "collectionId": "pbc_123", "maxSelect": 1},
+		{"name": "updated", "type": "autodate", "onCreate": true, "onUpdate": true}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PBField{
+		{Name: "title", Type: PBFieldText, Required: true, Min: floatPtr(1), Max: floatPtr(0), Pattern: `^\w+$`},
+		{Name: "start", Type: PBFieldDate},
+		{Name: "seats", Type: PBFieldNumber, OnlyInt: true, Min: floatPtr(1)},
+		{Name: "status", Type: PBFieldSelect, Values: []string{"a", "b"}, MaxSelect: 1},
+		{Name: "owner", Type: PBFieldRelation, CollectionID: "pbc_123", MaxSelect: 1},
+		{Name: "updated", Type: PBFieldAutodate, OnCreate: true, OnUpdate: true},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ParsePBFields() =\n%+v\nwant\n%+v", fields, want)
+	}
+
+	if _, err := ParsePBFields([]byte(`{}`)); err == nil {
+		t.Error("expected an error for a non-array")
+	}
+}
+
+func TestPocketBaseDriftMigration(t *testing.T) {
+	want := driftWant(t)
+	users := &PBCollection{Name: "users", Type: "base", Fields: []PBField{{Name: "email", Type: PBFieldEmail}}}
+	live := driftLive(func(f map[string]*PBField) {
+		delete(f, "seats")
+		f["title"].Required = false
+		f["status"].Values = []string{"draft"}
+		f["organizer"].CollectionID = "accounts"
+	})
+	drifts := append(DiffCollection(want, live), DiffCollection(users, nil)...)
+
+	src, err := PocketBaseDriftMigration([]*PBCollection{want, users}, drifts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(src)
+	for _, s := range []string{
+		`usersCollection := core.NewBaseCollection("users")`,
+		`eventsCollection, err := txApp.FindCollectionByNameOrId("events")`,
+		`&core.NumberField{`,
+		`f.Required = true`,
+		`f.Values = append(f.Values, "published", "cancelled")`,
+		"// TODO: events.organizer: relation is accounts, schema wants users (needs a data migration)",
+		`eventsCollection.Fields.RemoveByName("seats")`,
+		`for _, name := range []string{"users"} {`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("migration lacks %q:\n%s", s, out)
+		}
+	}
+	// The missing collection is created before the fields of events are added
+	if strings.Index(out, `core.NewBaseCollection("users")`) > strings.Index(out, `&core.NumberField{`) {
+		t.Errorf("users should be created first:\n%s", out)
+	}
+
+	if _, err := PocketBaseDriftMigration([]*PBCollection{want}, nil); err == nil {
+		t.Error("expected an error without drift")
+	}
+	if _, err := PocketBaseDriftMigration(nil, drifts); err == nil {
+		t.Error("expected an error for drift in a collection that is not canonical")
+	}
+}
+
+// TestCanonicalSchemas checks the repo's x-collection schemas against the
+// collections their migrations create (pkg/cmd/pocketbase/pb_migrations), as
+// the startup check sees them
+func TestCanonicalSchemas(t *testing.T) {
+	paths, err := filepath.Glob("../pb/schemas/*.schema.json")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no canonical schemas found (%v)", err)
+	}
+	want := make(map[string]*PBCollection)
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := SchemaCollectionName(data)
+		if name == "" {
+			t.Errorf("%s has no %s", p, PBCollectionKeyword)
+			continue
+		}
+		if want[name], err = PocketBaseCollection(name, data); err != nil {
+			t.Fatalf("%s: %v", p, err)
+		}
+	}
+
+	// 1731100000_init_api_keys.go
+	live := map[string]*PBCollection{
+		"api_keys": {Name: "api_keys", Fields: []PBField{
+			{Name: "id", Type: PBFieldText, Required: true},
+			{Name: "name", Type: PBFieldText, Required: true},
+			{Name: "key_hash", Type: PBFieldText, Required: true},
+			{Name: "prefix", Type: PBFieldText},
+			{Name: "tenant", Type: PBFieldText},
+			{Name: "rate_limit", Type: PBFieldNumber},
+			{Name: "enabled", Type: PBFieldBool},
+			{Name: "created", Type: PBFieldAutodate, OnCreate: true},
+		}},
+		"api_key_usage": {Name: "api_key_usage", Fields: []PBField{
+			{Name: "id", Type: PBFieldText, Required: true},
+			{Name: "api_key", Type: PBFieldRelation, Required: true, CollectionID: "api_keys", MaxSelect: 1},
+			{Name: "day", Type: PBFieldText, Required: true},
+			{Name: "requests", Type: PBFieldNumber},
+			{Name: "limited", Type: PBFieldNumber},
+			{Name: "updated", Type: PBFieldAutodate, OnCreate: true, OnUpdate: true},
+		}},
+	}
+	for name, c := range want {
+		l, ok := live[name]
+		if !ok {
+			t.Errorf("%s: no migration-created collection to compare with; add it here", name)
+			continue
+		}
+		if drifts := DiffCollection(c, l); len(drifts) > 0 {
+			t.Errorf("%s drifts from its migration: %v", name, drifts)
+		}
+	}
+
+	// A database missing a field reports it
+	l := *live["api_keys"]
+	l.Fields = l.Fields[:len(l.Fields)-2]
+	if drifts := DiffCollection(want["api_keys"], &l); len(drifts) != 1 || drifts[0].String() != "api_keys.enabled: bool field is missing" {
+		t.Errorf("expected enabled to be reported missing, got %v", drifts)
+	}
+}
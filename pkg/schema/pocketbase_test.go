@@ -0,0 +1,319 @@
+package schema
+
+import (
+	"go/format"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPocketBaseCollection_Fields(t *testing.T) {
+	c, err := PocketBaseCollection("bookings", []byte(`{
+		"title": "Booking",
+		"definitions": {"channel": {"type": "string", "enum": ["email", "sms"]}},
+		"properties": {
+			"id":        {"type": "string"},
+			"title":     {"type": "string", "title": "Title", "minLength": 1, "maxLength": 80, "pattern": "^\\S"},
+			"email":     {"type": "string", "format": "email"},
+			"site":      {"type": "string", "format": "uri"},
+			"day":       {"type": "string", "format": "date"},
+			"guests":    {"type": "integer", "minimum": 1, "maximum": 12},
+			"discount":  {"type": "number", "minimum": 0},
+			"deposit":   {"type": ["number", "null"]},
+			"confirmed": {"type": "boolean"},
+			"status":    {"type": "string", "enum": ["pending", "confirmed", null]},
+			"size":      {"oneOf": [{"const": "s", "title": "Small"}, {"const": "l", "title": "Large"}]},
+			"mixed":     {"oneOf": [{"const": "a"}, {"type": "string"}]},
+			"channels":  {"type": "array", "items": {"$ref": "#/definitions/channel"}},
+			"extras":    {"type": "array", "maxItems": 1, "items": {"enum": ["wifi", "parking", "breakfast"]}},
+			"channel":   {"$ref": "#/definitions/channel"},
+			"owner":     {"type": "string", "x-ref": "user"},
+			"guests_of": {"type": "array", "x-ref": "acme/user", "maxItems": 5},
+			"watchers":  {"type": "array", "x-ref": "user"},
+			"owner_email": {"type": "string", "x-ref": "user.email"},
+			"notes":     {"type": "object"},
+			"tags":      {"type": "array", "items": {"type": "string"}}
+		},
+		"required": ["title", "guests", "discount", "confirmed", "status", "owner", "notes"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []PBField{
+		{Name: "title", Type: PBFieldText, Required: true, Min: floatPtr(1), Max: floatPtr(80), Pattern: `^\S`, Comment: "Title"},
+		{Name: "email", Type: PBFieldEmail},
+		{Name: "site", Type: PBFieldURL},
+		{Name: "day", Type: PBFieldDate},
+		// A range excluding 0 keeps required; one allowing it drops it
+		{Name: "guests", Type: PBFieldNumber, Required: true, OnlyInt: true, Min: floatPtr(1), Max: floatPtr(12)},
+		{Name: "discount", Type: PBFieldNumber, Min: floatPtr(0)},
+		{Name: "deposit", Type: PBFieldNumber},
+		// Required bools would have to be true
+		{Name: "confirmed", Type: PBFieldBool},
+		{Name: "status", Type: PBFieldSelect, Required: true, Values: []string{"pending", "confirmed"}, MaxSelect: 1},
+		{Name: "size", Type: PBFieldSelect, Values: []string{"s", "l"}, MaxSelect: 1},
+		{Name: "mixed", Type: PBFieldJSON},
+		{Name: "channels", Type: PBFieldSelect, Values: []string{"email", "sms"}, MaxSelect: 2},
+		{Name: "extras", Type: PBFieldSelect, Values: []string{"wifi", "parking", "breakfast"}, MaxSelect: 1},
+		{Name: "channel", Type: PBFieldSelect, Values: []string{"email", "sms"}, MaxSelect: 1},
+		{Name: "owner", Type: PBFieldRelation, Required: true, CollectionID: "user", MaxSelect: 1},
+		{Name: "guests_of", Type: PBFieldRelation, CollectionID: "acme_user", MaxSelect: 5},
+		{Name: "watchers", Type: PBFieldRelation, CollectionID: "user", MaxSelect: pbMaxSelect},
+		{Name: "owner_email", Type: PBFieldText},
+		{Name: "notes", Type: PBFieldJSON, Required: true},
+		{Name: "tags", Type: PBFieldJSON},
+		{Name: "created", Type: PBFieldAutodate, OnCreate: true},
+		{Name: "updated", Type: PBFieldAutodate, OnCreate: true, OnUpdate: true},
+	}
+	if len(c.Fields) != len(want) {
+		t.Fatalf("got %d fields, want %d: %+v", len(c.Fields), len(want), c.Fields)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(c.Fields[i], want[i]) {
+			t.Errorf("field %d:\n got %+v\nwant %+v", i, c.Fields[i], want[i])
+		}
+	}
+	if c.Name != "bookings" || c.Type != "base" || c.Title != "Booking" {
+		t.Errorf("collection = %s %s %q", c.Name, c.Type, c.Title)
+	}
+}
+
+func TestPocketBaseCollection_Errors(t *testing.T) {
+	tests := []struct {
+		name, collection, schema, want string
+	}{
+		{"collection name", "my-events", `{}`, "invalid collection name"},
+		{"field name", "events", `{"properties": {"start-time": {"type": "string"}}}`, "cannot be a PocketBase field name"},
+		{"remote $ref", "events", `{"properties": {"a": {"$ref": "other.json#/a"}}}`, "unsupported $ref"},
+		{"$ref cycle", "events", `{"definitions": {"a": {"$ref": "#/definitions/a"}}, "properties": {"a": {"$ref": "#/definitions/a"}}}`, "too deeply nested"},
+		{"invalid JSON", "events", `{`, "failed to parse schema"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PocketBaseCollection(tt.collection, []byte(tt.schema))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectKeys(t *testing.T) {
+	keys, err := objectKeys([]byte(`{"z": 1, "a": {"nested": [1, 2]}, "m": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(keys, ",") != "z,a,m" {
+		t.Errorf("keys = %v, want document order", keys)
+	}
+	if _, err := objectKeys([]byte(`[1]`)); err == nil {
+		t.Error("expected an error for an array")
+	}
+}
+
+func TestCollectionName(t *testing.T) {
+	for namespace, want := range map[string]string{
+		"google/calendar": "google_calendar",
+		"Acme/User-Event": "acme_user_event",
+		"2024/stats":      "c_2024_stats",
+		"café":            "caf",
+	} {
+		if got := CollectionName(namespace); got != want {
+			t.Errorf("CollectionName(%q) = %q, want %q", namespace, got, want)
+		}
+	}
+}
+
+func TestRelationOrder(t *testing.T) {
+	rel := func(target string) PBField { return PBField{Name: target, Type: PBFieldRelation, CollectionID: target} }
+	collections := []*PBCollection{
+		{Name: "acme_event", Fields: []PBField{rel("user"), rel("acme_event")}},
+		{Name: "acme_user", Fields: []PBField{rel("tenant")}},
+		{Name: "tenant"},
+		{Name: "note", Fields: []PBField{rel("users")}}, // Not listed: looked up by name
+	}
+	var names []string
+	for _, c := range relationOrder(collections) {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "tenant,acme_user,acme_event,note" {
+		t.Errorf("relationOrder = %s", got)
+	}
+
+	if got := resolveTarget("user", []string{"acme_user", "globex_user"}); got != "user" {
+		t.Errorf("ambiguous target resolved to %s", got)
+	}
+}
+
+func TestMigrationWriterVariables(t *testing.T) {
+	w := newMigrationWriter([]*PBCollection{{Name: "api_keys"}, {Name: "api__keys"}, {Name: "2fa"}})
+	for name, want := range map[string]string{"api_keys": "apiKeysCollection", "api__keys": "apiKeysCollection2", "2fa": "c2faCollection"} {
+		if got := w.variable(name); got != want {
+			t.Errorf("variable(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+// migrationGolden is PocketBaseMigration's output for the collections of
+// TestPocketBaseMigration
+const migrationGolden = `// Code generated from JSON Schema (schema.PocketBaseMigration). DO NOT EDIT.
+
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func init() {
+	core.AppMigrations.Register(
+		// Up: Create acme_user, acme_event collections
+		func(txApp core.App) error {
+			// Create acme_user (User) collection
+			acmeUserCollection := core.NewBaseCollection("acme_user")
+			acmeUserCollection.Fields.Add(
+				&core.EmailField{
+					Name:     "email",
+					Required: true,
+				},
+				&core.AutodateField{
+					Name:     "created",
+					OnCreate: true,
+				},
+				&core.AutodateField{
+					Name:     "updated",
+					OnCreate: true,
+					OnUpdate: true,
+				},
+			)
+			if err := txApp.Save(acmeUserCollection); err != nil {
+				return err
+			}
+
+			// Create acme_event (Event) collection
+			acmeEventCollection := core.NewBaseCollection("acme_event")
+			acmeEventCollection.Fields.Add(
+				&core.TextField{
+					Name:     "title", // Event Title
+					Required: true,
+					Max:      80,
+					Pattern:  ` + "`^\\S`" + `,
+				},
+				&core.NumberField{
+					Name:    "seats",
+					Min:     types.Pointer(0.0),
+					OnlyInt: true,
+				},
+				&core.SelectField{
+					Name:      "status",
+					MaxSelect: 1,
+					Values:    []string{"draft", "live"},
+				},
+				&core.AutodateField{
+					Name:     "created",
+					OnCreate: true,
+				},
+				&core.AutodateField{
+					Name:     "updated",
+					OnCreate: true,
+					OnUpdate: true,
+				},
+			)
+			if err := txApp.Save(acmeEventCollection); err != nil {
+				return err
+			}
+
+			// Add acme_user relations
+			acmeUserCollection.Fields.Add(
+				&core.RelationField{
+					Name:         "manager",
+					CollectionId: acmeUserCollection.Id,
+				},
+			)
+			if err := txApp.Save(acmeUserCollection); err != nil {
+				return err
+			}
+
+			// Add acme_event relations
+			usersCollection, err := txApp.FindCollectionByNameOrId("users")
+			if err != nil {
+				return err
+			}
+			acmeEventCollection.Fields.Add(
+				&core.RelationField{
+					Name:         "organizer",
+					Required:     true,
+					CollectionId: acmeUserCollection.Id,
+				},
+				&core.RelationField{
+					Name:         "owner",
+					CollectionId: usersCollection.Id,
+				},
+			)
+			if err := txApp.Save(acmeEventCollection); err != nil {
+				return err
+			}
+
+			return nil
+		},
+
+		// Down: Remove acme_user, acme_event collections
+		func(txApp core.App) error {
+			for _, name := range []string{"acme_event", "acme_user"} {
+				collection, err := txApp.FindCollectionByNameOrId(name)
+				if err != nil {
+					continue
+				}
+				if err := txApp.Delete(collection); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
+`
+
+func TestPocketBaseMigration(t *testing.T) {
+	event, err := PocketBaseCollection("acme_event", []byte(`{
+		"title": "Event",
+		"properties": {
+			"title":     {"type": "string", "title": "Event Title", "maxLength": 80, "pattern": "^\\S"},
+			"organizer": {"type": "string", "x-ref": "user"},
+			"owner":     {"type": "string", "x-ref": "users"},
+			"seats":     {"type": "integer", "minimum": 0},
+			"status":    {"enum": ["draft", "live"]}
+		},
+		"required": ["title", "organizer", "seats"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := PocketBaseCollection("acme_user", []byte(`{
+		"title": "User",
+		"properties": {
+			"email":   {"type": "string", "format": "email"},
+			"manager": {"type": "string", "x-ref": "acme_user"}
+		},
+		"required": ["email"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := PocketBaseMigration([]*PBCollection{event, user})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted, err := format.Source(src); err != nil || string(formatted) != string(src) {
+		t.Errorf("migration is not gofmt'ed Go (%v)", err)
+	}
+	if string(src) != migrationGolden {
+		t.Errorf("migration differs from the golden file:\n%s", src)
+	}
+
+	if _, err := PocketBaseMigration(nil); err == nil {
+		t.Error("expected an error without collections")
+	}
+}
@@ -0,0 +1,179 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FromStruct generates a JSON Schema (draft 2020-12) from a Go value's type,
+// so typed function parameters can be validated and rendered as forms.
+//
+// Field names follow encoding/json (the json tag, else the Go name). A field is
+// required when it has a json tag without omitempty, or a `jsonschema:"required"`
+// tag; untagged and pointer fields are optional (as is any field tagged
+// `jsonschema:"optional"`), so plain option structs such as pdfform.FillOptions
+// produce usable forms. The jsonschema tag also accepts title=, description=,
+// format=, enum=a|b|c, options=value:Label|... (oneOf const/title pairs) and
+// optionsSource=name (see OptionsSourceKeyword), comma separated:
+//
+//	type Buyer struct {
+//		Name  string `json:"name" jsonschema:"title=Full name"`
+//		Email string `json:"email,omitempty" jsonschema:"format=email"`
+//		State string `json:"state" jsonschema:"options=VIC:Victoria|QLD:Queensland"`
+//		Zone  string `json:"zone,omitempty" jsonschema:"optionsSource=timezones"`
+//	}
+//
+// time.Time becomes a date-time string. Function and channel fields are skipped.
+func FromStruct(v interface{}) ([]byte, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("cannot generate schema for nil")
+	}
+	s := typeSchema(t, map[reflect.Type]bool{})
+	if s == nil {
+		return nil, fmt.Errorf("cannot generate schema for %s", t)
+	}
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return json.MarshalIndent(s, "", "  ")
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema returns the schema of t (nil for unsupported kinds). visiting
+// guards against recursive types, which become plain objects.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Interface:
+		return map[string]interface{}{} // Any value
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"} // encoding/json encodes []byte as base64
+		}
+		s := map[string]interface{}{"type": "array"}
+		if items := typeSchema(t.Elem(), visiting); items != nil {
+			s["items"] = items
+		}
+		return s
+	case reflect.Map:
+		s := map[string]interface{}{"type": "object"}
+		if values := typeSchema(t.Elem(), visiting); len(values) > 0 {
+			s["additionalProperties"] = values
+		}
+		return s
+	case reflect.Struct:
+		if visiting[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		return structSchema(t, visiting)
+	}
+	return nil // Func, Chan, Complex, UnsafePointer
+}
+
+func structSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonTag, hasJSONTag := field.Tag.Lookup("json")
+		name, opts, _ := strings.Cut(jsonTag, ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		// Embedded structs without a name are flattened, as encoding/json does
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := typeSchema(embedded, visiting)
+				if innerProps, ok := inner["properties"].(map[string]interface{}); ok {
+					for k, v := range innerProps {
+						properties[k] = v
+					}
+					if req, ok := inner["required"].([]string); ok {
+						required = append(required, req...)
+					}
+					continue
+				}
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := typeSchema(field.Type, visiting)
+		if prop == nil {
+			continue
+		}
+
+		isRequired := hasJSONTag && !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer
+		for _, part := range strings.Split(field.Tag.Get("jsonschema"), ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch key {
+			case "required":
+				isRequired = true
+			case "optional":
+				isRequired = false
+			case "title", "description", "format":
+				prop[key] = value
+			case "enum":
+				prop["enum"] = strings.Split(value, "|")
+			case "options":
+				var oneOf []interface{}
+				for _, pair := range strings.Split(value, "|") {
+					v, label, _ := strings.Cut(pair, ":")
+					alt := map[string]interface{}{"const": v}
+					if label != "" {
+						alt["title"] = label
+					}
+					oneOf = append(oneOf, alt)
+				}
+				prop["oneOf"] = oneOf
+			case "optionsSource":
+				prop[OptionsSourceKeyword] = value
+			}
+		}
+
+		properties[name] = prop
+		if isRequired {
+			required = append(required, name)
+		}
+	}
+
+	s := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
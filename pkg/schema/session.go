@@ -0,0 +1,186 @@
+package schema
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ================================================================
+// Form Sessions & CSRF
+// ================================================================
+// A FormSession keeps partially-filled form data and validation errors on
+// the server between requests, keyed by a random session cookie, and holds
+// the CSRF token every generated form must post back.
+
+const (
+	// CSRFFieldName is the hidden form field carrying the CSRF token
+	CSRFFieldName = "_csrf"
+
+	// CSRFHeaderName carries the CSRF token for non-form (fetch) requests
+	CSRFHeaderName = "X-CSRF-Token"
+
+	// DefaultFormSessionCookie is the session cookie name
+	DefaultFormSessionCookie = "wellknown_form_session"
+
+	// DefaultFormSessionTTL is how long an idle session is kept
+	DefaultFormSessionTTL = 30 * time.Minute
+)
+
+// ErrInvalidCSRFToken is returned by VerifyCSRF when the token is missing or wrong
+var ErrInvalidCSRFToken = errors.New("invalid or missing CSRF token")
+
+// formState is the saved state of one form
+type formState struct {
+	data   map[string]interface{}
+	errors ValidationErrors
+}
+
+// FormSession is one visitor's form state
+type FormSession struct {
+	ID        string
+	CSRFToken string
+
+	mu        sync.Mutex
+	forms     map[string]*formState
+	expiresAt time.Time
+}
+
+// SaveForm stores submitted data and its validation errors under formKey (e.g., "google/calendar")
+func (s *FormSession) SaveForm(formKey string, data map[string]interface{}, errs ValidationErrors) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forms[formKey] = &formState{data: data, errors: errs}
+}
+
+// Form returns the saved data and errors for formKey (nil if none)
+func (s *FormSession) Form(formKey string) (map[string]interface{}, ValidationErrors) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.forms[formKey]; ok {
+		return st.data, st.errors
+	}
+	return nil, nil
+}
+
+// ClearForm forgets the saved state for formKey (e.g., after a successful submit)
+func (s *FormSession) ClearForm(formKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.forms, formKey)
+}
+
+// CSRFField returns the hidden input to place inside a <form>
+func (s *FormSession) CSRFField() template.HTML {
+	return template.HTML(`<input type="hidden" name="` + CSRFFieldName + `" value="` + s.CSRFToken + `">`)
+}
+
+// RenderForm generates the form fields for u, pre-filled with the saved data and
+// errors for formKey, with the CSRF field injected
+func (s *FormSession) RenderForm(u *UISchema, jsonSchema *jsonschema.Schema, formKey string) template.HTML {
+	data, errs := s.Form(formKey)
+	return s.CSRFField() + "\n" + u.GenerateFormHTMLWithData(jsonSchema, data, errs)
+}
+
+// FormSessionManager stores sessions in memory
+type FormSessionManager struct {
+	CookieName string        // Session cookie name (default: DefaultFormSessionCookie)
+	TTL        time.Duration // Idle lifetime (default: DefaultFormSessionTTL)
+
+	mu       sync.Mutex
+	sessions map[string]*FormSession
+}
+
+// NewFormSessionManager creates an in-memory session manager with default settings
+func NewFormSessionManager() *FormSessionManager {
+	return &FormSessionManager{
+		CookieName: DefaultFormSessionCookie,
+		TTL:        DefaultFormSessionTTL,
+		sessions:   make(map[string]*FormSession),
+	}
+}
+
+// Get returns the request's session, starting a new one (and setting its
+// cookie) if there is none or it expired. Call before writing the response body.
+func (m *FormSessionManager) Get(w http.ResponseWriter, r *http.Request) *FormSession {
+	if s := m.lookup(r); s != nil {
+		return s
+	}
+
+	s := &FormSession{
+		ID:        rand.Text(),
+		CSRFToken: rand.Text(),
+		forms:     make(map[string]*formState),
+		expiresAt: time.Now().Add(m.TTL),
+	}
+
+	m.mu.Lock()
+	m.pruneLocked()
+	m.sessions[s.ID] = s
+	m.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.CookieName,
+		Value:    s.ID,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return s
+}
+
+// VerifyCSRF checks the token in the posted form (or CSRFHeaderName header)
+// against the request's session. The form must already be parsed.
+func (m *FormSessionManager) VerifyCSRF(r *http.Request) (*FormSession, error) {
+	s := m.lookup(r)
+	if s == nil {
+		return nil, ErrInvalidCSRFToken
+	}
+	token := r.PostFormValue(CSRFFieldName)
+	if token == "" {
+		token = r.Header.Get(CSRFHeaderName)
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.CSRFToken)) != 1 {
+		return nil, ErrInvalidCSRFToken
+	}
+	return s, nil
+}
+
+// lookup returns the live session for the request's cookie, extending its lifetime
+func (m *FormSessionManager) lookup(r *http.Request) *FormSession {
+	cookie, err := r.Cookie(m.CookieName)
+	if err != nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[cookie.Value]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if now.After(s.expiresAt) {
+		delete(m.sessions, s.ID)
+		return nil
+	}
+	s.expiresAt = now.Add(m.TTL)
+	return s
+}
+
+// pruneLocked removes expired sessions (m.mu must be held)
+func (m *FormSessionManager) pruneLocked() {
+	now := time.Now()
+	for id, s := range m.sessions {
+		if now.After(s.expiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+}
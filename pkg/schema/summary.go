@@ -0,0 +1,190 @@
+package schema
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ================================================================
+// Read-Only Summary
+// ================================================================
+// Renders submitted data as definition lists for confirmation and review
+// pages. Unlike the form renderer, all values are HTML-escaped and empty
+// fields are left out, so the output is safe to print or email.
+
+// summarySection is one titled definition list
+type summarySection struct {
+	title  string
+	fields []string
+}
+
+// GenerateSummaryHTML renders data as a single definition list, with fields in
+// name order. Use UISchema.GenerateSummaryHTML to follow a form's layout.
+func GenerateSummaryHTML(jsonSchema *jsonschema.Schema, data map[string]interface{}) template.HTML {
+	names := make([]string, 0, len(jsonSchema.Properties))
+	for name := range jsonSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return renderSummary([]summarySection{{fields: names}}, jsonSchema, data)
+}
+
+// GenerateSummaryHTML renders data as one definition list per section of the
+// layout: each Group and each Label heading starts a new section
+func (u *UISchema) GenerateSummaryHTML(jsonSchema *jsonschema.Schema, data map[string]interface{}) template.HTML {
+	sections := []summarySection{{}}
+	var walk func([]Element)
+	walk = func(elements []Element) {
+		for _, elem := range elements {
+			switch elem.Type {
+			case TypeLabel:
+				sections = append(sections, summarySection{title: elem.Text})
+			case TypeGroup:
+				sections = append(sections, summarySection{title: elem.Title})
+				walk(elem.Elements)
+				sections = append(sections, summarySection{}) // Controls after a group are untitled
+			case TypeControl:
+				if name := u.parseScopeToFieldName(elem.Scope); name != "" {
+					last := &sections[len(sections)-1]
+					last.fields = append(last.fields, name)
+				}
+			default:
+				walk(elem.Elements)
+			}
+		}
+	}
+	walk(u.Elements)
+	return renderSummary(sections, jsonSchema, data)
+}
+
+// renderSummary writes the non-empty sections
+func renderSummary(sections []summarySection, jsonSchema *jsonschema.Schema, data map[string]interface{}) template.HTML {
+	var out strings.Builder
+	out.WriteString(`<div class="schema-summary">` + "\n")
+	for _, section := range sections {
+		var dl strings.Builder
+		for _, name := range section.fields {
+			prop := jsonSchema.Properties[name]
+			value, ok := data[name]
+			if prop == nil || !ok || isEmptyValue(value) {
+				continue
+			}
+			writeSummaryField(&dl, summaryLabel(name, prop), value, prop, "  ")
+		}
+		if dl.Len() == 0 {
+			continue
+		}
+
+		out.WriteString(`<section class="summary-section">` + "\n")
+		if section.title != "" {
+			out.WriteString(`  <h3>` + html.EscapeString(section.title) + `</h3>` + "\n")
+		}
+		out.WriteString("  <dl>\n" + dl.String() + "  </dl>\n")
+		out.WriteString("</section>\n")
+	}
+	out.WriteString("</div>\n")
+	return template.HTML(out.String())
+}
+
+// writeSummaryField writes one <dt>/<dd> pair, nesting objects and arrays
+func writeSummaryField(out *strings.Builder, label string, value interface{}, prop *jsonschema.Schema, indent string) {
+	out.WriteString(indent + `<dt>` + html.EscapeString(label) + `</dt>` + "\n")
+	out.WriteString(indent + `<dd>`)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out.WriteString("\n" + indent + "  <dl>\n")
+		writeSummaryObject(out, v, prop, indent+"    ")
+		out.WriteString(indent + "  </dl>\n" + indent)
+
+	case []interface{}:
+		var itemSchema *jsonschema.Schema
+		if prop != nil {
+			itemSchema, _ = prop.Items.(*jsonschema.Schema)
+		}
+		out.WriteString("\n" + indent + "  <ol>\n")
+		for _, item := range v {
+			out.WriteString(indent + "    <li>")
+			if obj, ok := item.(map[string]interface{}); ok {
+				out.WriteString("<dl>\n")
+				writeSummaryObject(out, obj, itemSchema, indent+"      ")
+				out.WriteString(indent + "    </dl>")
+			} else {
+				out.WriteString(html.EscapeString(formatSummaryValue(item)))
+			}
+			out.WriteString("</li>\n")
+		}
+		out.WriteString(indent + "  </ol>\n" + indent)
+
+	default:
+		out.WriteString(html.EscapeString(formatSummaryValue(v)))
+	}
+	out.WriteString("</dd>\n")
+}
+
+// writeSummaryObject writes an object's non-empty fields in name order
+func writeSummaryObject(out *strings.Builder, obj map[string]interface{}, prop *jsonschema.Schema, indent string) {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if isEmptyValue(obj[name]) {
+			continue
+		}
+		var sub *jsonschema.Schema
+		if prop != nil {
+			sub = prop.Properties[name]
+		}
+		writeSummaryField(out, summaryLabel(name, sub), obj[name], sub, indent)
+	}
+}
+
+// summaryLabel returns the schema title, falling back to the field name
+func summaryLabel(name string, prop *jsonschema.Schema) string {
+	if prop != nil && prop.Title != "" {
+		return prop.Title
+	}
+	return name
+}
+
+// formatSummaryValue formats a scalar for display
+func formatSummaryValue(v interface{}) string {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return "Yes"
+		}
+		return "No"
+	case float64:
+		// Form data arrives as float64; show whole numbers without decimals
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%g", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// isEmptyValue reports whether a value has nothing to show
+func isEmptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -186,7 +188,7 @@ func (u *UISchema) renderControlWithData(elem Element, jsonSchema *jsonschema.Sc
 	}
 
 	// Render input based on type
-	u.renderInputWithData(elem, fieldName, prop, isRequired, fieldValue, html, indent)
+	u.renderInputWithData(elem, fieldName, prop, isRequired, formData[fieldName], fieldValue, html, indent)
 
 	// Render validation error
 	if fieldError != "" {
@@ -208,7 +210,7 @@ func (u *UISchema) parseScopeToFieldName(scope string) string {
 }
 
 // renderInputWithData renders an input field based on schema type with form data
-func (u *UISchema) renderInputWithData(elem Element, fieldName string, prop *jsonschema.Schema, required bool, fieldValue string, html *strings.Builder, indent string) {
+func (u *UISchema) renderInputWithData(elem Element, fieldName string, prop *jsonschema.Schema, required bool, fieldData interface{}, fieldValue string, html *strings.Builder, indent string) {
 	requiredAttr := ""
 	if required {
 		requiredAttr = " required"
@@ -241,18 +243,15 @@ func (u *UISchema) renderInputWithData(elem Element, fieldName string, prop *jso
 
 	switch propType {
 	case "string":
-		// Check for enum (dropdown)
-		if prop.Enum != nil && len(prop.Enum.Values) > 0 {
-			html.WriteString(indent + `  <select id="` + fieldName + `" name="` + fieldName + `"` + requiredAttr + `>` + "\n")
-			html.WriteString(indent + `    <option value="">-- Select --</option>` + "\n")
-			for _, option := range prop.Enum.Values {
-				selected := ""
-				if fmt.Sprintf("%v", option) == fieldValue {
-					selected = " selected"
-				}
-				html.WriteString(indent + fmt.Sprintf(`    <option value="%v"%s>%v</option>`, option, selected, option) + "\n")
+		if IsFileProperty(prop) {
+			// File upload (value is the stored file's ID after submit)
+			accept := ""
+			if prop.ContentMediaType != nil && prop.ContentMediaType.Name != "application/octet-stream" {
+				accept = ` accept="` + prop.ContentMediaType.Name + `"`
 			}
-			html.WriteString(indent + `  </select>` + "\n")
+			html.WriteString(indent + `  <input type="file" id="` + fieldName + `" name="` + fieldName + `"` + requiredAttr + accept + `>` + "\n")
+		} else if renderSelect(fieldName, fieldName, requiredAttr, prop, fieldValue, html, indent+"  ") {
+			// Enum, oneOf labels or options source (dropdown)
 		} else if elem.Options != nil && elem.Options.Multi {
 			// Multi-line text
 			html.WriteString(indent + `  <textarea id="` + fieldName + `" name="` + fieldName + `"` + requiredAttr + placeholder + `>` + fieldValue + `</textarea>` + "\n")
@@ -290,7 +289,8 @@ func (u *UISchema) renderInputWithData(elem Element, fieldName string, prop *jso
 		html.WriteString(indent + `  <input type="number" id="` + fieldName + `" name="` + fieldName + `"` + requiredAttr + min + max + valueAttr + `>` + "\n")
 
 	case "array":
-		u.renderArrayInput(fieldName, prop, html, indent)
+		items, _ := fieldData.([]interface{})
+		u.renderArrayInput(fieldName, prop, items, html, indent)
 
 	case "object":
 		u.renderObjectInput(fieldName, prop, html, indent)
@@ -313,41 +313,49 @@ func (u *UISchema) getSchemaType(prop *jsonschema.Schema) string {
 	return "string"
 }
 
-// renderArrayInput renders an array input with dynamic add/remove
-func (u *UISchema) renderArrayInput(fieldName string, prop *jsonschema.Schema, html *strings.Builder, indent string) {
+// renderArrayInput renders an array's current items with add/remove submit
+// buttons (see ApplyArrayAction)
+func (u *UISchema) renderArrayInput(fieldName string, prop *jsonschema.Schema, items []interface{}, html *strings.Builder, indent string) {
 	title := prop.Title
 	if title == "" {
 		title = fieldName
 	}
 
 	html.WriteString(indent + `  <div class="array-input" data-field-name="` + fieldName + `">` + "\n")
-	html.WriteString(indent + `    <div class="array-items" id="` + fieldName + `-items"></div>` + "\n")
-	html.WriteString(indent + `    <button type="button" class="btn-add-array-item" onclick="addArrayItem('` + fieldName + `')">` + "\n")
-	html.WriteString(indent + `      ➕ Add ` + title + "\n")
-	html.WriteString(indent + `    </button>` + "\n")
-	html.WriteString(indent + `    <template id="` + fieldName + `-template">` + "\n")
-
-	// Render template for array items
-	if prop.Items != nil {
-		itemSchema, ok := prop.Items.(*jsonschema.Schema)
-		if ok {
-			u.renderArrayItemTemplate(fieldName, itemSchema, html, indent+"    ")
+	html.WriteString(indent + `    <div class="array-items" id="` + fieldName + `-items">` + "\n")
+	if itemSchema := arrayItemSchema(prop); itemSchema != nil {
+		for i, item := range items {
+			u.renderArrayItem(fieldName, i, itemSchema, item, html, indent+"      ")
 		}
 	}
+	html.WriteString(indent + `    </div>` + "\n")
 
-	html.WriteString(indent + `    </template>` + "\n")
+	// Hide the add button once maxItems is reached
+	if prop.MaxItems == nil || len(items) < *prop.MaxItems {
+		html.WriteString(indent + `    <button type="submit" class="btn-add-array-item" name="` + ArrayActionFieldName + `" value="add:` + fieldName + `" formnovalidate>` + "\n")
+		html.WriteString(indent + `      ➕ Add ` + title + "\n")
+		html.WriteString(indent + `    </button>` + "\n")
+	}
 	html.WriteString(indent + `  </div>` + "\n")
 }
 
-// renderArrayItemTemplate renders a template for array items
-func (u *UISchema) renderArrayItemTemplate(fieldName string, itemSchema *jsonschema.Schema, html *strings.Builder, indent string) {
-	html.WriteString(indent + `<div class="array-item">` + "\n")
+// renderArrayItem renders item index of an array, pre-filled with its data
+func (u *UISchema) renderArrayItem(fieldName string, index int, itemSchema *jsonschema.Schema, item interface{}, html *strings.Builder, indent string) {
+	itemName := fmt.Sprintf("%s[%d]", fieldName, index)
+	html.WriteString(indent + `<div class="array-item" id="` + fieldName + `-` + strconv.Itoa(index) + `">` + "\n")
 
 	itemType := u.getSchemaType(itemSchema)
 
 	if itemType == "object" && itemSchema.Properties != nil {
 		// Array of objects - render nested fields
-		for propName, propSchema := range itemSchema.Properties {
+		itemData, _ := item.(map[string]interface{})
+		names := make([]string, 0, len(itemSchema.Properties))
+		for name := range itemSchema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, propName := range names {
+			propSchema := itemSchema.Properties[propName]
 			isRequired := contains(itemSchema.Required, propName)
 			label := propSchema.Title
 			if label == "" {
@@ -359,20 +367,28 @@ func (u *UISchema) renderArrayItemTemplate(fieldName string, itemSchema *jsonsch
 				html.WriteString(" *")
 			}
 			html.WriteString(`</label>` + "\n")
-			u.renderSimpleInput(fieldName+"[INDEX]."+propName, propSchema, isRequired, html, indent+"    ")
+			u.renderSimpleInput(itemName+"."+propName, propSchema, isRequired, formValue(itemData[propName]), html, indent+"    ")
 			html.WriteString(indent + `  </div>` + "\n")
 		}
 	} else {
 		// Array of primitives
-		u.renderSimpleInput(fieldName+"[INDEX]", itemSchema, false, html, indent+"  ")
+		u.renderSimpleInput(itemName, itemSchema, false, formValue(item), html, indent+"  ")
 	}
 
-	html.WriteString(indent + `  <button type="button" class="btn-remove-array-item" onclick="removeArrayItem(this)">✖</button>` + "\n")
+	html.WriteString(indent + `  <button type="submit" class="btn-remove-array-item" name="` + ArrayActionFieldName + `" value="remove:` + fieldName + `:` + strconv.Itoa(index) + `" formnovalidate title="Remove">✖</button>` + "\n")
 	html.WriteString(indent + `</div>` + "\n")
 }
 
-// renderSimpleInput renders a simple input (used in array templates)
-func (u *UISchema) renderSimpleInput(fieldName string, prop *jsonschema.Schema, required bool, html *strings.Builder, indent string) {
+// formValue formats a decoded form value for an input's value attribute
+func formValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// renderSimpleInput renders a simple input (used for array items)
+func (u *UISchema) renderSimpleInput(fieldName string, prop *jsonschema.Schema, required bool, value string, html *strings.Builder, indent string) {
 	requiredAttr := ""
 	if required {
 		requiredAttr = " required"
@@ -383,6 +399,10 @@ func (u *UISchema) renderSimpleInput(fieldName string, prop *jsonschema.Schema,
 	if placeholder == "" {
 		placeholder = fieldName
 	}
+	valueAttr := ""
+	if value != "" {
+		valueAttr = ` value="` + template.HTMLEscapeString(value) + `"`
+	}
 
 	switch propType {
 	case "string":
@@ -390,16 +410,25 @@ func (u *UISchema) renderSimpleInput(fieldName string, prop *jsonschema.Schema,
 		if prop.Format != nil {
 			format = prop.Format.Name
 		}
-		if format == "email" {
-			html.WriteString(indent + `<input type="email" name="` + fieldName + `"` + requiredAttr + ` placeholder="` + placeholder + `">` + "\n")
+		if renderSelect("", fieldName, requiredAttr, prop, value, html, indent) {
+			// Dropdown
+		} else if format == "email" {
+			html.WriteString(indent + `<input type="email" name="` + fieldName + `"` + requiredAttr + ` placeholder="` + placeholder + `"` + valueAttr + `>` + "\n")
 		} else {
-			html.WriteString(indent + `<input type="text" name="` + fieldName + `"` + requiredAttr + ` placeholder="` + placeholder + `">` + "\n")
+			html.WriteString(indent + `<input type="text" name="` + fieldName + `"` + requiredAttr + ` placeholder="` + placeholder + `"` + valueAttr + `>` + "\n")
+		}
+
+	case "boolean":
+		checked := ""
+		if value == "true" {
+			checked = " checked"
 		}
+		html.WriteString(indent + `<input type="checkbox" name="` + fieldName + `" value="true"` + checked + `>` + "\n")
 
 	case "integer", "number":
-		html.WriteString(indent + `<input type="number" name="` + fieldName + `"` + requiredAttr + ` placeholder="` + placeholder + `">` + "\n")
+		html.WriteString(indent + `<input type="number" name="` + fieldName + `"` + requiredAttr + ` placeholder="` + placeholder + `"` + valueAttr + `>` + "\n")
 	default:
-		html.WriteString(indent + `<input type="text" name="` + fieldName + `"` + requiredAttr + ` placeholder="` + placeholder + `">` + "\n")
+		html.WriteString(indent + `<input type="text" name="` + fieldName + `"` + requiredAttr + ` placeholder="` + placeholder + `"` + valueAttr + `>` + "\n")
 	}
 }
 
@@ -445,14 +474,7 @@ func (u *UISchema) renderNestedInput(fieldName string, prop *jsonschema.Schema,
 		}
 		if format == "date" {
 			html.WriteString(indent + `<input type="date" name="` + fieldName + `"` + requiredAttr + `>` + "\n")
-		} else if prop.Enum != nil && len(prop.Enum.Values) > 0 {
-			html.WriteString(indent + `<select name="` + fieldName + `"` + requiredAttr + `>` + "\n")
-			html.WriteString(indent + `  <option value="">-- Select --</option>` + "\n")
-			for _, option := range prop.Enum.Values {
-				html.WriteString(indent + fmt.Sprintf(`  <option value="%v">%v</option>`, option, option) + "\n")
-			}
-			html.WriteString(indent + `</select>` + "\n")
-		} else {
+		} else if !renderSelect("", fieldName, requiredAttr, prop, "", html, indent) {
 			html.WriteString(indent + `<input type="text" name="` + fieldName + `"` + requiredAttr + `>` + "\n")
 		}
 	case "integer", "number":
@@ -462,6 +484,42 @@ func (u *UISchema) renderNestedInput(fieldName string, prop *jsonschema.Schema,
 	}
 }
 
+// renderSelect renders prop as a <select> when it has options (enum, oneOf
+// const/title pairs or an x-optionsSource) and reports whether it did. Selects
+// with a URL source are rendered empty with data-options-source for the page
+// to fill; an unknown source falls back to a plain input.
+func renderSelect(id, name, requiredAttr string, prop *jsonschema.Schema, fieldValue string, html *strings.Builder, indent string) bool {
+	options, ok, err := PropertyOptions(prop)
+	if err != nil {
+		html.WriteString(indent + fmt.Sprintf("<!-- %s -->\n", template.HTMLEscapeString(err.Error())))
+		return false
+	}
+	attrs := ""
+	if id != "" {
+		attrs = ` id="` + id + `"`
+	}
+	if !ok {
+		source := OptionsSource(prop)
+		if !IsURLOptionsSource(source) {
+			return false
+		}
+		attrs += ` data-options-source="` + template.HTMLEscapeString(source) + `" data-value="` + template.HTMLEscapeString(fieldValue) + `"`
+	}
+
+	html.WriteString(indent + `<select` + attrs + ` name="` + name + `"` + requiredAttr + `>` + "\n")
+	html.WriteString(indent + `  <option value="">-- Select --</option>` + "\n")
+	for _, option := range options {
+		selected := ""
+		if option.Value == fieldValue {
+			selected = " selected"
+		}
+		html.WriteString(indent + fmt.Sprintf(`  <option value="%s"%s>%s</option>`,
+			template.HTMLEscapeString(option.Value), selected, template.HTMLEscapeString(option.Label)) + "\n")
+	}
+	html.WriteString(indent + `</select>` + "\n")
+	return true
+}
+
 // contains checks if a slice contains a string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,296 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// UI Schema element types
+const (
+	TypeVerticalLayout   = "VerticalLayout"
+	TypeHorizontalLayout = "HorizontalLayout"
+	TypeGroup            = "Group"
+	TypeControl          = "Control"
+	TypeLabel            = "Label"
+)
+
+// ScopePrefix is the JSON pointer prefix for top-level schema properties
+const ScopePrefix = "#/properties/"
+
+// Prop returns the scope for a top-level schema property (e.g., Prop("title") -> "#/properties/title")
+func Prop(name string) string {
+	return ScopePrefix + name
+}
+
+// ================================================================
+// Fluent Builder
+// ================================================================
+// Builds a UISchema in Go instead of hand-written JSON:
+//
+//	ui, err := schema.NewLayout().Vertical().
+//	    Label("📅 Event Details").
+//	    Control("#/properties/title", schema.WithPlaceholder("e.g., Team Meeting")).
+//	    Group("Times",
+//	        schema.Control(schema.Prop("start"), schema.WithLabel("Start Time")),
+//	        schema.Control(schema.Prop("end"), schema.WithLabel("End Time")),
+//	    ).
+//	    Build()
+
+// LayoutBuilder builds a UISchema
+type LayoutBuilder struct {
+	layoutType string
+	elements   []Element
+}
+
+// NewLayout starts a UISchema with a vertical root layout
+func NewLayout() *LayoutBuilder {
+	return &LayoutBuilder{layoutType: TypeVerticalLayout}
+}
+
+// Vertical makes the root layout a VerticalLayout
+func (b *LayoutBuilder) Vertical() *LayoutBuilder {
+	b.layoutType = TypeVerticalLayout
+	return b
+}
+
+// Horizontal makes the root layout a HorizontalLayout
+func (b *LayoutBuilder) Horizontal() *LayoutBuilder {
+	b.layoutType = TypeHorizontalLayout
+	return b
+}
+
+// Control adds a control for scope
+func (b *LayoutBuilder) Control(scope string, opts ...ControlOption) *LayoutBuilder {
+	return b.Add(Control(scope, opts...))
+}
+
+// Label adds a heading
+func (b *LayoutBuilder) Label(text string) *LayoutBuilder {
+	return b.Add(Label(text))
+}
+
+// Group adds a titled group of elements
+func (b *LayoutBuilder) Group(title string, elements ...Element) *LayoutBuilder {
+	return b.Add(Group(title, elements...))
+}
+
+// Row adds a HorizontalLayout of elements
+func (b *LayoutBuilder) Row(elements ...Element) *LayoutBuilder {
+	return b.Add(Horizontal(elements...))
+}
+
+// Add appends pre-built elements
+func (b *LayoutBuilder) Add(elements ...Element) *LayoutBuilder {
+	b.elements = append(b.elements, elements...)
+	return b
+}
+
+// Build returns the UISchema, or an error if any element is malformed
+func (b *LayoutBuilder) Build() (*UISchema, error) {
+	u := &UISchema{Type: b.layoutType, Elements: b.elements}
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// MustBuild is Build that panics on error, for package-level layouts
+func (b *LayoutBuilder) MustBuild() *UISchema {
+	u, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// ================================================================
+// Element Helpers
+// ================================================================
+
+// Control creates a Control element for scope
+func Control(scope string, opts ...ControlOption) Element {
+	elem := Element{Type: TypeControl, Scope: scope}
+	for _, opt := range opts {
+		opt(&elem)
+	}
+	return elem
+}
+
+// Label creates a Label element
+func Label(text string) Element {
+	return Element{Type: TypeLabel, Text: text}
+}
+
+// Group creates a Group element with a title
+func Group(title string, elements ...Element) Element {
+	return Element{Type: TypeGroup, Title: title, Elements: elements}
+}
+
+// Vertical creates a nested VerticalLayout
+func Vertical(elements ...Element) Element {
+	return Element{Type: TypeVerticalLayout, Elements: elements}
+}
+
+// Horizontal creates a nested HorizontalLayout
+func Horizontal(elements ...Element) Element {
+	return Element{Type: TypeHorizontalLayout, Elements: elements}
+}
+
+// ControlOption customises a Control element
+type ControlOption func(*Element)
+
+// WithLabel overrides the label from the schema
+func WithLabel(label string) ControlOption {
+	return func(e *Element) { e.Label = label }
+}
+
+// WithDescription overrides the description from the schema
+func WithDescription(description string) ControlOption {
+	return func(e *Element) { e.Description = description }
+}
+
+// WithPlaceholder sets the input placeholder
+func WithPlaceholder(placeholder string) ControlOption {
+	return func(e *Element) { e.options().Placeholder = placeholder }
+}
+
+// WithFormat overrides the input format (e.g., "date", "email")
+func WithFormat(format string) ControlOption {
+	return func(e *Element) { e.options().Format = format }
+}
+
+// WithSuggestions sets autocomplete suggestions
+func WithSuggestions(suggestions ...string) ControlOption {
+	return func(e *Element) { e.options().Suggestions = suggestions }
+}
+
+// Multiline renders the control as a textarea
+func Multiline() ControlOption {
+	return func(e *Element) { e.options().Multi = true }
+}
+
+// HideLabel hides the control's label
+func HideLabel() ControlOption {
+	return func(e *Element) {
+		show := false
+		e.options().ShowLabel = &show
+	}
+}
+
+// DefaultLayout builds a vertical layout with one control per top-level
+// property of jsonSchema: required properties first, then alphabetically.
+// Use it when a schema has no hand-written uischema.json.
+func DefaultLayout(jsonSchema *jsonschema.Schema) *UISchema {
+	names := make([]string, 0, len(jsonSchema.Properties))
+	for name := range jsonSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := contains(jsonSchema.Required, names[i]), contains(jsonSchema.Required, names[j])
+		if ri != rj {
+			return ri
+		}
+		return names[i] < names[j]
+	})
+
+	b := NewLayout()
+	for _, name := range names {
+		b.Control(Prop(name))
+	}
+	return &UISchema{Type: b.layoutType, Elements: b.elements}
+}
+
+// options returns the element's options, creating them if needed
+func (e *Element) options() *Options {
+	if e.Options == nil {
+		e.Options = &Options{}
+	}
+	return e.Options
+}
+
+// ================================================================
+// Validation & JSON
+// ================================================================
+
+// Validate checks element types and control scopes
+func (u *UISchema) Validate() error {
+	switch u.Type {
+	case TypeVerticalLayout, TypeHorizontalLayout, TypeGroup:
+	default:
+		return fmt.Errorf("invalid UI schema root type %q", u.Type)
+	}
+	var errs []string
+	for i, elem := range u.Elements {
+		validateElement(elem, fmt.Sprintf("elements[%d]", i), &errs)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid UI schema: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateElement appends problems with elem (and its children) to errs
+func validateElement(elem Element, path string, errs *[]string) {
+	switch elem.Type {
+	case TypeVerticalLayout, TypeHorizontalLayout, TypeGroup:
+		if len(elem.Elements) == 0 {
+			*errs = append(*errs, fmt.Sprintf("%s: %s has no elements", path, elem.Type))
+		}
+		for i, child := range elem.Elements {
+			validateElement(child, fmt.Sprintf("%s.elements[%d]", path, i), errs)
+		}
+	case TypeControl:
+		if name := strings.TrimPrefix(elem.Scope, ScopePrefix); name == elem.Scope || name == "" || strings.Contains(name, "/") {
+			*errs = append(*errs, fmt.Sprintf("%s: invalid scope %q (want %s<name>)", path, elem.Scope, ScopePrefix))
+		}
+	case TypeLabel:
+		if elem.Text == "" {
+			*errs = append(*errs, fmt.Sprintf("%s: label has no text", path))
+		}
+	default:
+		*errs = append(*errs, fmt.Sprintf("%s: unknown element type %q", path, elem.Type))
+	}
+}
+
+// Scopes returns the scope of every control, in layout order
+func (u *UISchema) Scopes() []string {
+	var scopes []string
+	var walk func([]Element)
+	walk = func(elements []Element) {
+		for _, elem := range elements {
+			if elem.Type == TypeControl {
+				scopes = append(scopes, elem.Scope)
+			}
+			walk(elem.Elements)
+		}
+	}
+	walk(u.Elements)
+	return scopes
+}
+
+// CheckScopes returns an error if any control refers to a property missing from jsonSchema
+func (u *UISchema) CheckScopes(jsonSchema *jsonschema.Schema) error {
+	var missing []string
+	for _, scope := range u.Scopes() {
+		if _, ok := jsonSchema.Properties[u.parseScopeToFieldName(scope)]; !ok {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("UI schema refers to unknown properties: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// JSON returns the indented UI Schema JSON, as stored in uischema.json
+func (u *UISchema) JSON() (string, error) {
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal UI schema: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,137 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// eventSchema is a small event form
+const eventSchema = `{
+	"type": "object",
+	"properties": {
+		"title": {"type": "string"},
+		"start": {"type": "string", "format": "date-time"},
+		"end":   {"type": "string", "format": "date-time"},
+		"notes": {"type": "string"}
+	},
+	"required": ["title", "start"]
+}`
+
+func eventLayout() *LayoutBuilder {
+	return NewLayout().Vertical().
+		Label("Event").
+		Control(Prop("title"), WithPlaceholder("e.g., Team Meeting")).
+		Group("Times",
+			Control(Prop("start"), WithLabel("Start Time")),
+			Control(Prop("end"), WithLabel("End Time"), WithFormat("date-time")),
+		).
+		Row(Control(Prop("notes"), Multiline(), HideLabel()))
+}
+
+func TestLayoutBuilder_Build(t *testing.T) {
+	ui, err := eventLayout().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ui.Type != TypeVerticalLayout || len(ui.Elements) != 4 {
+		t.Fatalf("ui = %+v", ui)
+	}
+	if got := ui.Elements[1].Options; got == nil || got.Placeholder != "e.g., Team Meeting" {
+		t.Errorf("title options = %+v", got)
+	}
+	notes := ui.Elements[3].Elements[0].Options
+	if notes == nil || !notes.Multi || notes.ShowLabel == nil || *notes.ShowLabel {
+		t.Errorf("notes options = %+v", notes)
+	}
+	if h, err := NewLayout().Horizontal().Control(Prop("title")).Build(); err != nil || h.Type != TypeHorizontalLayout {
+		t.Errorf("Horizontal() = %+v, %v", h, err)
+	}
+}
+
+func TestLayoutBuilder_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *LayoutBuilder
+		wantErr string
+	}{
+		{"unprefixed scope", NewLayout().Control("title"), `elements[0]: invalid scope "title"`},
+		{"empty scope", NewLayout().Control(""), `elements[0]: invalid scope ""`},
+		{"nested scope", NewLayout().Control("#/properties/a/b"), "invalid scope"},
+		{"empty group", NewLayout().Group("Times"), "elements[0]: Group has no elements"},
+		{"empty label", NewLayout().Label(""), "elements[0]: label has no text"},
+		{"unknown type", NewLayout().Add(Element{Type: "Slider"}), `unknown element type "Slider"`},
+		{"nested", NewLayout().Row(Control(Prop("ok")), Vertical(Control("bad"))), `elements[0].elements[1].elements[0]: invalid scope "bad"`},
+	}
+	for _, tt := range tests {
+		ui, err := tt.builder.Build()
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) || ui != nil {
+			t.Errorf("%s: Build() = %v, %v, want %q", tt.name, ui, err, tt.wantErr)
+		}
+	}
+
+	// Every problem is reported
+	_, err := NewLayout().Control("a").Label("").Build()
+	if err == nil || strings.Count(err.Error(), "elements[") != 2 {
+		t.Errorf("err = %v, want both elements", err)
+	}
+
+	if err := (&UISchema{Type: TypeControl}).Validate(); err == nil || !strings.Contains(err.Error(), "root type") {
+		t.Errorf("Validate() of a Control root = %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustBuild did not panic")
+		}
+	}()
+	NewLayout().Control("title").MustBuild()
+}
+
+func TestUISchema_Scopes(t *testing.T) {
+	ui := eventLayout().MustBuild()
+	want := []string{Prop("title"), Prop("start"), Prop("end"), Prop("notes")}
+	if got := ui.Scopes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scopes() = %v, want %v", got, want)
+	}
+
+	compiled, err := NewValidatorV6().CompileSchemaJSON("event", []byte(eventSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ui.CheckScopes(compiled); err != nil {
+		t.Errorf("CheckScopes() = %v", err)
+	}
+	stale := eventLayout().Control(Prop("location")).Group("More", Control(Prop("room"))).MustBuild()
+	err = stale.CheckScopes(compiled)
+	if err == nil || !strings.HasSuffix(err.Error(), "#/properties/location, #/properties/room") {
+		t.Errorf("CheckScopes() = %v, want the unknown scopes", err)
+	}
+
+	// Required first, then alphabetical
+	want = []string{Prop("start"), Prop("title"), Prop("end"), Prop("notes")}
+	if got := DefaultLayout(compiled).Scopes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DefaultLayout scopes = %v, want %v", got, want)
+	}
+}
+
+func TestUISchema_JSONRoundTrip(t *testing.T) {
+	ui := eventLayout().MustBuild()
+	data, err := ui.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(data, "\n  \"elements\": [") || !strings.Contains(data, `"scope": "#/properties/title"`) {
+		t.Errorf("JSON() not indented uischema.json:\n%s", data)
+	}
+	parsed, err := ParseUISchema(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, ui) {
+		t.Errorf("round trip changed the schema:\n%+v\n%+v", parsed, ui)
+	}
+	if err := parsed.Validate(); err != nil {
+		t.Error(err)
+	}
+}
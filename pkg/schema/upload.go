@@ -0,0 +1,260 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ================================================================
+// File Upload Fields
+// ================================================================
+// A string property with "format": "binary" or a "contentMediaType" renders
+// as <input type="file">. On submit, SaveUploadedFiles streams each file into
+// a FileStore and replaces the field's value with the stored file's ID, so the
+// decoded document validates as a string and carries a reference to the file.
+//
+//	"receipt": {"type": "string", "contentMediaType": "application/pdf"}
+
+// FileFormat is the JSON Schema format marking a property as a file upload
+const FileFormat = "binary"
+
+// DefaultMaxUploadSize is the per-file size limit for LocalFileStore
+const DefaultMaxUploadSize = 10 << 20 // 10 MB
+
+// FileMediaTypes are the contentMediaType values recognised on file properties
+var FileMediaTypes = []string{
+	"application/pdf",
+	"application/octet-stream",
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"text/plain",
+	"text/csv",
+}
+
+// ErrFileTooLarge is returned by FileStore.Save when a file exceeds the size limit
+var ErrFileTooLarge = errors.New("file too large")
+
+// registerFileVocabulary teaches the compiler the binary format and file media
+// types, so they survive compilation for the renderer (content is not asserted)
+func registerFileVocabulary(c *jsonschema.Compiler) {
+	c.RegisterFormat(&jsonschema.Format{Name: FileFormat, Validate: func(interface{}) error { return nil }})
+	for _, name := range FileMediaTypes {
+		c.RegisterContentMediaType(&jsonschema.MediaType{Name: name, Validate: func([]byte) error { return nil }})
+	}
+	c.AssertContent()
+}
+
+// IsFileProperty reports whether prop is a file upload field
+func IsFileProperty(prop *jsonschema.Schema) bool {
+	if prop.Format != nil && prop.Format.Name == FileFormat {
+		return true
+	}
+	return prop.ContentMediaType != nil && prop.ContentMediaType.Name != "application/json"
+}
+
+// FileFields returns the names of the schema's top-level file properties
+func FileFields(jsonSchema *jsonschema.Schema) []string {
+	var names []string
+	for name, prop := range jsonSchema.Properties {
+		if IsFileProperty(prop) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// FileRef describes a stored upload
+type FileRef struct {
+	ID          string `json:"id"`           // Store-assigned ID (the value placed in the document)
+	Name        string `json:"name"`         // Original file name
+	ContentType string `json:"content_type"` // Detected or declared media type
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// FileStore stores uploaded files
+type FileStore interface {
+	// Save streams r into the store. Returns ErrFileTooLarge if over the limit.
+	Save(name, contentType string, r io.Reader) (FileRef, error)
+
+	// Open returns the file's contents and reference
+	Open(id string) (io.ReadCloser, *FileRef, error)
+}
+
+// LocalFileStore stores uploads in a directory, with a JSON sidecar per file
+type LocalFileStore struct {
+	Dir     string // Upload directory
+	MaxSize int64  // Per-file limit in bytes (default: DefaultMaxUploadSize)
+}
+
+// NewLocalFileStore creates a store in dir with the default size limit
+func NewLocalFileStore(dir string) *LocalFileStore {
+	return &LocalFileStore{Dir: dir, MaxSize: DefaultMaxUploadSize}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Save streams r to disk, hashing as it goes
+func (s *LocalFileStore) Save(name, contentType string, r io.Reader) (FileRef, error) {
+	maxSize := s.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxUploadSize
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return FileRef{}, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, "upload-*.tmp")
+	if err != nil {
+		return FileRef{}, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, maxSize+1))
+	tmp.Close()
+	if err != nil {
+		return FileRef{}, fmt.Errorf("failed to write upload: %w", err)
+	}
+	if size > maxSize {
+		return FileRef{}, fmt.Errorf("%w: limit is %d bytes", ErrFileTooLarge, maxSize)
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	base := unsafeFileChars.ReplaceAllString(filepath.Base(name), "_")
+	ref := FileRef{
+		ID:          sum[:16] + "-" + base,
+		Name:        filepath.Base(name),
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      sum,
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.Dir, ref.ID)); err != nil {
+		return FileRef{}, fmt.Errorf("failed to store upload: %w", err)
+	}
+	meta, err := json.MarshalIndent(ref, "", "  ")
+	if err != nil {
+		return FileRef{}, fmt.Errorf("failed to marshal file reference: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, ref.ID+".json"), meta, 0644); err != nil {
+		return FileRef{}, fmt.Errorf("failed to write file reference: %w", err)
+	}
+	return ref, nil
+}
+
+// Open returns a stored file by ID
+func (s *LocalFileStore) Open(id string) (io.ReadCloser, *FileRef, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return nil, nil, fmt.Errorf("invalid file ID %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(s.Dir, id+".json"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file reference: %w", err)
+	}
+	var ref FileRef
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse file reference: %w", err)
+	}
+	f, err := os.Open(filepath.Join(s.Dir, id))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return f, &ref, nil
+}
+
+// SaveUploadedFiles stores the files posted for the schema's file properties
+// and sets data[field] to each file's ID. The request must be parsed with
+// ParseMultipartForm. Per-field problems (wrong type, too large) are returned
+// as ValidationErrors; storage failures as error.
+func SaveUploadedFiles(r *http.Request, jsonSchema *jsonschema.Schema, store FileStore, data map[string]interface{}) (map[string]FileRef, ValidationErrors, error) {
+	refs := make(map[string]FileRef)
+	errs := make(ValidationErrors)
+	if r.MultipartForm == nil {
+		return refs, errs, nil
+	}
+
+	for _, field := range FileFields(jsonSchema) {
+		headers := r.MultipartForm.File[field]
+		if len(headers) == 0 || headers[0].Size == 0 {
+			continue // missing required files are reported by Validate
+		}
+		hdr := headers[0]
+
+		f, err := hdr.Open()
+		if err != nil {
+			return refs, errs, fmt.Errorf("failed to open upload %s: %w", field, err)
+		}
+
+		contentType, body, err := sniffContentType(f, hdr.Header.Get("Content-Type"))
+		if err != nil {
+			f.Close()
+			return refs, errs, fmt.Errorf("failed to read upload %s: %w", field, err)
+		}
+		if prop := jsonSchema.Properties[field]; prop.ContentMediaType != nil && !mediaTypeMatches(contentType, prop.ContentMediaType.Name) {
+			f.Close()
+			errs[field] = fmt.Sprintf("expected a %s file, got %s", prop.ContentMediaType.Name, contentType)
+			continue
+		}
+
+		ref, err := store.Save(hdr.Filename, contentType, body)
+		f.Close()
+		if errors.Is(err, ErrFileTooLarge) {
+			errs[field] = err.Error()
+			continue
+		}
+		if err != nil {
+			return refs, errs, err
+		}
+		data[field] = ref.ID
+		refs[field] = ref
+	}
+	return refs, errs, nil
+}
+
+// sniffContentType detects the content type from the first bytes, falling back
+// to the declared type only when detection is inconclusive: any type for
+// unrecognised bytes, a text/* type (e.g. text/csv) for plain text, so a text
+// file can't pass as a PDF or image. Returns a reader that replays the
+// sniffed bytes.
+func sniffContentType(r io.Reader, declared string) (string, io.Reader, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", nil, err
+	}
+	head = head[:n]
+
+	detected := http.DetectContentType(head)
+	if mt, _, err := mime.ParseMediaType(detected); err == nil {
+		detected = mt
+	}
+	if mt, _, err := mime.ParseMediaType(declared); err == nil {
+		switch {
+		case detected == "application/octet-stream":
+			detected = mt
+		case detected == "text/plain" && strings.HasPrefix(mt, "text/"):
+			detected = mt
+		}
+	}
+	return detected, io.MultiReader(strings.NewReader(string(head)), r), nil
+}
+
+// mediaTypeMatches reports whether got satisfies want ("application/octet-stream" accepts anything)
+func mediaTypeMatches(got, want string) bool {
+	return want == "application/octet-stream" || strings.EqualFold(got, want)
+}
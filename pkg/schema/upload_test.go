@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const pdfHead = "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n"
+
+func TestLocalFileStore(t *testing.T) {
+	store := &LocalFileStore{Dir: t.TempDir(), MaxSize: 16}
+
+	ref, err := store.Save("../../etc/pass wd", "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(ref.ID, `/\`) || strings.HasPrefix(ref.ID, ".") || !strings.HasSuffix(ref.ID, "-pass_wd") {
+		t.Errorf("ID = %q, want a flat name ending in the cleaned base name", ref.ID)
+	}
+	if ref.Name != "pass wd" || ref.Size != 5 || len(ref.SHA256) != 64 {
+		t.Errorf("ref = %+v", ref)
+	}
+	if _, err := os.Stat(filepath.Join(store.Dir, ref.ID)); err != nil {
+		t.Errorf("file not stored in the upload directory: %v", err)
+	}
+
+	f, opened, err := store.Open(ref.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(f)
+	f.Close()
+	if string(content) != "hello" || *opened != ref {
+		t.Errorf("Open() = %q, %+v", content, opened)
+	}
+
+	if _, err := store.Save("big.bin", "", strings.NewReader(strings.Repeat("x", 17))); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("err = %v, want ErrFileTooLarge", err)
+	}
+	if _, err := store.Save("exact.bin", "", strings.NewReader(strings.Repeat("x", 16))); err != nil {
+		t.Errorf("a file of exactly MaxSize: %v", err)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(store.Dir, "upload-*.tmp")); len(entries) != 0 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+
+	for _, id := range []string{"", ".", "..", ref.ID + ".json/..", "../" + ref.ID, "sub/" + ref.ID, ".hidden"} {
+		if _, _, err := store.Open(id); err == nil || !strings.Contains(err.Error(), "invalid file ID") {
+			t.Errorf("Open(%q): err = %v, want an invalid ID", id, err)
+		}
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	tests := []struct {
+		content, declared, want string
+	}{
+		{pdfHead, "image/png", "application/pdf"},             // Detected wins
+		{"\x00\x01\x02\x03", "image/webp", "image/webp"},      // Unrecognised: declared trusted
+		{"a,b\n1,2\n", "text/csv; charset=utf-8", "text/csv"}, // Text: a text/* type trusted
+		{"not a pdf", "application/pdf", "text/plain"},        // Text can't claim a binary type
+		{"plain", "", "text/plain"},
+	}
+	for _, tt := range tests {
+		got, body, err := sniffContentType(strings.NewReader(tt.content), tt.declared)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("sniffContentType(%q, %q) = %s, want %s", tt.content, tt.declared, got, tt.want)
+		}
+		if replayed, _ := io.ReadAll(body); string(replayed) != tt.content {
+			t.Errorf("body = %q, want the sniffed bytes replayed", replayed)
+		}
+	}
+}
+
+func TestSaveUploadedFiles(t *testing.T) {
+	// Draft-07, as the repo's schemas: later drafts drop unasserted formats
+	jsonSchema, err := NewValidatorV6().CompileSchemaJSON("test/upload", []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"receipt": {"type": "string", "contentMediaType": "application/pdf"},
+			"scan":    {"type": "string", "contentMediaType": "application/pdf"},
+			"photo":   {"type": "string", "format": "binary"},
+			"big":     {"type": "string", "format": "binary"},
+			"missing": {"type": "string", "format": "binary"},
+			"title":   {"type": "string"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, part := range []struct{ field, filename, contentType, content string }{
+		{"receipt", "../receipt.pdf", "application/pdf", pdfHead},
+		{"scan", "scan.pdf", "application/pdf", "just text"},
+		{"photo", "photo.bin", "image/png", "\x00\x01"},
+		{"big", "big.bin", "", strings.Repeat("x", 64)},
+	} {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="`+part.field+`"; filename="`+part.filename+`"`)
+		if part.contentType != "" {
+			h.Set("Content-Type", part.contentType)
+		}
+		pw, _ := w.CreatePart(h)
+		io.WriteString(pw, part.content)
+	}
+	w.WriteField("title", "Expenses")
+	w.Close()
+
+	r := httptest.NewRequest("POST", "/submit", &buf)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &LocalFileStore{Dir: t.TempDir(), MaxSize: 32}
+	data := map[string]interface{}{"title": "Expenses"}
+	refs, errs, err := SaveUploadedFiles(r, jsonSchema, store, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(refs) != 2 || refs["receipt"].ContentType != "application/pdf" || refs["photo"].ContentType != "image/png" {
+		t.Errorf("refs = %+v", refs)
+	}
+	if data["receipt"] != refs["receipt"].ID || data["photo"] != refs["photo"].ID || data["title"] != "Expenses" {
+		t.Errorf("data = %v", data)
+	}
+	if !strings.HasSuffix(refs["receipt"].ID, "-receipt.pdf") {
+		t.Errorf("receipt ID = %q", refs["receipt"].ID)
+	}
+	if errs["scan"] != "expected a application/pdf file, got text/plain" {
+		t.Errorf("scan error = %q", errs["scan"])
+	}
+	if !strings.Contains(errs["big"], ErrFileTooLarge.Error()) {
+		t.Errorf("big error = %q", errs["big"])
+	}
+	if len(errs) != 2 {
+		t.Errorf("errs = %v, want scan and big only", errs)
+	}
+	if _, ok := data["scan"]; ok {
+		t.Error("rejected upload was placed in the document")
+	}
+}
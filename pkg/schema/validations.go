@@ -0,0 +1,205 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+// ================================================================
+// Cross-Field Validations
+// ================================================================
+// JSON Schema validates each property on its own; x-validations on an object
+// holds named rules comparing two of its properties, reported on the first:
+//
+//	"x-validations": {
+//	  "endAfterStart": {"fields": ["end", "start"], "message": "End time must be after start time"},
+//	  "sameEmail":     {"fields": ["email2", "email"], "op": "=="}
+//	}
+//
+// op defaults from the rule name for the built-in names (FieldRuleOps).
+// Numbers compare numerically, anything else as strings, which orders the
+// ISO dates and datetime-local values the forms use. A rule is skipped while
+// either value is missing or empty (use "required" for that). The rules run
+// wherever the schema is validated, including the browser (see
+// pkg/schema/wasm), so pages and the server report the same errors.
+
+// ValidationsKeyword is the schema keyword holding an object's cross-field rules
+const ValidationsKeyword = "x-validations"
+
+// ValidationOps are the comparison operators of a cross-field rule
+var ValidationOps = []string{"==", "!=", "<", "<=", ">", ">="}
+
+// FieldRuleOps are the built-in rule names, which imply their op
+var FieldRuleOps = map[string]string{
+	"endAfterStart": ">",
+	"equals":        "==",
+	"notEquals":     "!=",
+}
+
+// FieldRule is one x-validations entry
+type FieldRule struct {
+	Name    string // Key in x-validations
+	Field   string // Property the error is reported on (fields[0])
+	Other   string // Property compared against (fields[1])
+	Op      string // One of ValidationOps
+	Message string // Default: "must be <op> <other>"
+}
+
+// validationsExt is the compiled x-validations keyword
+type validationsExt struct {
+	rules []FieldRule
+}
+
+func (e validationsExt) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	for _, rule := range e.rules {
+		a, b := obj[rule.Field], obj[rule.Other]
+		if isEmptyValue(a) || isEmptyValue(b) || compareValues(a, rule.Op, b) {
+			continue
+		}
+		ctx.AddErr(&jsonschema.ValidationError{
+			InstanceLocation: append(append([]string(nil), ctx.ValueLocation()...), rule.Field),
+			ErrorKind:        fieldRuleKind{rule},
+		})
+	}
+}
+
+// fieldRuleKind is the jsonschema.ErrorKind of a failed rule
+type fieldRuleKind struct {
+	rule FieldRule
+}
+
+func (k fieldRuleKind) KeywordPath() []string { return []string{ValidationsKeyword, k.rule.Name} }
+
+func (k fieldRuleKind) LocalizedString(*message.Printer) string { return k.String() }
+
+func (k fieldRuleKind) String() string {
+	if k.rule.Message != "" {
+		return k.rule.Message
+	}
+	return fmt.Sprintf("must be %s %s", k.rule.Op, k.rule.Other)
+}
+
+// registerValidationsVocabulary teaches the compiler x-validations
+func registerValidationsVocabulary(c *jsonschema.Compiler) {
+	c.RegisterVocabulary(&jsonschema.Vocabulary{
+		URL: "https://github.com/joeblew999/wellknown/vocab/validations",
+		Compile: func(_ *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			v, ok := obj[ValidationsKeyword]
+			if !ok {
+				return nil, nil
+			}
+			rules, err := parseFieldRules(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ValidationsKeyword, err)
+			}
+			return validationsExt{rules: rules}, nil
+		},
+	})
+	c.AssertVocabs()
+}
+
+// parseFieldRules reads x-validations, sorted by name so errors are stable
+func parseFieldRules(v any) ([]FieldRule, error) {
+	entries, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("must be an object of named rules")
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := make([]FieldRule, 0, len(entries))
+	for _, name := range names {
+		m, ok := entries[name].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an object", name)
+		}
+		fields, _ := m["fields"].([]any)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s: fields must name two properties", name)
+		}
+		rule := FieldRule{Name: name, Op: FieldRuleOps[name]}
+		rule.Field, _ = fields[0].(string)
+		rule.Other, _ = fields[1].(string)
+		if op, ok := m["op"].(string); ok {
+			rule.Op = op
+		}
+		rule.Message, _ = m["message"].(string)
+		if rule.Field == "" || rule.Other == "" {
+			return nil, fmt.Errorf("%s: fields must name two properties", name)
+		}
+		if !validOp(rule.Op) {
+			return nil, fmt.Errorf("%s: op must be one of %s", name, strings.Join(ValidationOps, " "))
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func validOp(op string) bool {
+	for _, o := range ValidationOps {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// compareValues applies op, numerically when both sides are numbers
+func compareValues(a any, op string, b any) bool {
+	var c int
+	x, xok := toNumber(a)
+	y, yok := toNumber(b)
+	if xok && yok {
+		switch {
+		case x < y:
+			c = -1
+		case x > y:
+			c = 1
+		}
+	} else {
+		c = strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -25,6 +26,9 @@ type ValidatorV6 struct {
 // NewValidatorV6 creates a new validator instance using jsonschema v6
 func NewValidatorV6() *ValidatorV6 {
 	compiler := jsonschema.NewCompiler()
+	registerFileVocabulary(compiler)
+	registerOptionsVocabulary(compiler)
+	registerValidationsVocabulary(compiler)
 
 	return &ValidatorV6{
 		compiler: compiler,
@@ -62,6 +66,30 @@ func (v *ValidatorV6) LoadSchemaFromFile(schemaPath string) (*jsonschema.Schema,
 	return schema, nil
 }
 
+// CompileSchemaJSON compiles an in-memory JSON Schema document. id names the
+// schema in error messages and in the cache (e.g., "flow/buyer/input").
+func (v *ValidatorV6) CompileSchemaJSON(id string, schemaJSON []byte) (*jsonschema.Schema, error) {
+	if cached, ok := v.schemas[id]; ok {
+		return cached, nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", id, err)
+	}
+	url := "mem:///" + strings.TrimPrefix(id, "/") + ".json"
+	if err := v.compiler.AddResource(url, doc); err != nil {
+		return nil, fmt.Errorf("failed to add schema %s: %w", id, err)
+	}
+	schema, err := v.compiler.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %s: %w", id, err)
+	}
+
+	v.schemas[id] = schema
+	return schema, nil
+}
+
 // Validate validates data against a compiled schema
 // Returns ValidationErrors for backwards compatibility
 func (v *ValidatorV6) Validate(data map[string]interface{}, schema *jsonschema.Schema) ValidationErrors {
@@ -139,7 +167,7 @@ func FormDataToMap(formData map[string][]string) map[string]interface{} {
 	result := make(map[string]interface{})
 
 	for key, values := range formData {
-		if len(values) == 0 {
+		if len(values) == 0 || key == CSRFFieldName || key == ArrayActionFieldName {
 			continue
 		}
 
@@ -0,0 +1,49 @@
+//go:build js && wasm
+
+// Command wasm is the schema validator compiled to WebAssembly, so forms
+// validate in the browser with exactly the server's rules: the same JSON
+// Schema compiler, vocabularies (x-validations, file fields, options) and
+// form value coercion (schema.FormDataToMap). Build it with `make schema-wasm`;
+// the demo server serves it to schema forms.
+//
+// It defines one global function:
+//
+//	wellknownValidate(id, schemaJSON, fieldsJSON) -> errorsJSON
+//
+// id names the schema (compiled once per id), fieldsJSON is the form's
+// values as {"name": ["value", ...]}, and errorsJSON is the
+// schema.ValidationErrors object ({} when valid).
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+func main() {
+	validator := schema.NewValidatorV6()
+
+	js.Global().Set("wellknownValidate", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 3 {
+			return errorsJSON(schema.ValidationErrors{"_error": "wellknownValidate(id, schemaJSON, fieldsJSON)"})
+		}
+		compiled, err := validator.CompileSchemaJSON(args[0].String(), []byte(args[1].String()))
+		if err != nil {
+			return errorsJSON(schema.ValidationErrors{"_error": err.Error()})
+		}
+		var fields map[string][]string
+		if err := json.Unmarshal([]byte(args[2].String()), &fields); err != nil {
+			return errorsJSON(schema.ValidationErrors{"_error": err.Error()})
+		}
+		return errorsJSON(validator.Validate(schema.FormDataToMap(fields), compiled))
+	}))
+
+	select {} // Keep the function alive for the page's lifetime
+}
+
+func errorsJSON(errs schema.ValidationErrors) string {
+	data, _ := json.Marshal(errs)
+	return string(data)
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/joeblew999/wellknown/pkg/capability"
+	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/joeblew999/wellknown/pkg/types"
+)
+
+// ================================================================
+// JSON API
+// ================================================================
+// Every platform with a builder is also served at POST /api/{platform}/{app}.
+// The body is the same data the form submits, as JSON; it is validated
+// against the platform's schema.json and the generated URL is returned:
+//
+//	curl -X POST localhost:8080/api/google/calendar \
+//	  -d '{"title":"Team Sync","start":"2025-11-15T14:00","end":"2025-11-15T15:00"}'
+//
+//	{"platform":"google","app":"calendar","url":"https://calendar.google.com/..."}
+//
+// Validation failures return 400 with {"errors": {"field": "message"}}.
+//
+// GET /api/capabilities returns the capability matrix (which fields each
+// builder carries), optionally filtered with ?platform= and ?app=.
+
+// APIPrefix is the route prefix of the JSON API
+const APIPrefix = "/api"
+
+// maxAPIBodySize caps JSON request bodies
+const maxAPIBodySize = 1 << 20
+
+// APIResponse is the body returned by the JSON API
+type APIResponse struct {
+	Platform string            `json:"platform"`
+	App      string            `json:"app"`
+	URL      string            `json:"url,omitempty"`
+	Errors   map[string]string `json:"errors,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// makeAPIHandler creates the JSON API handler for a platform's builder
+func (s *Server) makeAPIHandler(cfg FormConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Request: %s %s", r.Method, r.URL.Path)
+
+		resp := APIResponse{Platform: cfg.Platform, App: cfg.AppType}
+		if r.Method != "POST" {
+			w.Header().Set("Allow", "POST")
+			resp.Error = "method not allowed"
+			writeAPIResponse(w, http.StatusMethodNotAllowed, resp)
+			return
+		}
+
+		var data map[string]interface{}
+		// Plain decoding yields float64 numbers, as FormDataToMap does for forms
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBodySize)).Decode(&data); err != nil || data == nil {
+			resp.Error = "request body must be a JSON object"
+			writeAPIResponse(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		_, compiledSchema, validator, err := schema.LoadSchemasForRendering(cfg.Platform, cfg.AppType)
+		if err != nil {
+			resp.Error = "failed to load schemas: " + err.Error()
+			writeAPIResponse(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		validationErrors := validator.Validate(data, compiledSchema)
+		if cfg.Validate != nil {
+			if verr, ok := cfg.Validate(cfg.Platform, data).(*types.ValidationError); ok {
+				for field, msg := range verr.Fields() {
+					if _, exists := validationErrors[field]; !exists {
+						validationErrors[field] = msg
+					}
+				}
+			}
+		}
+		if len(validationErrors) > 0 {
+			resp.Errors = validationErrors
+			writeAPIResponse(w, http.StatusBadRequest, resp)
+			return
+		}
+
+		url, err := cfg.GenerateURL(data)
+		if err != nil {
+			resp.Error = "failed to generate " + cfg.SuccessLabel + ": " + err.Error()
+			writeAPIResponse(w, http.StatusInternalServerError, resp)
+			return
+		}
+
+		resp.URL = url
+		writeAPIResponse(w, http.StatusOK, resp)
+	}
+}
+
+// writeAPIResponse writes resp as JSON with the given status
+func writeAPIResponse(w http.ResponseWriter, status int, resp APIResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleCapabilities serves the capability matrix as JSON
+func handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	matrix := capability.Default().Filter(r.URL.Query().Get("platform"), r.URL.Query().Get("app"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matrix)
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/capability"
+	"github.com/joeblew999/wellknown/pkg/guard"
+)
+
+func postJSON(t *testing.T, mux *http.ServeMux, path, body string) (int, APIResponse) {
+	t.Helper()
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	var resp APIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("POST %s: invalid JSON response: %v", path, err)
+	}
+	return rec.Code, resp
+}
+
+// TestAPIGeneratesURL ensures the JSON API returns the same links as the forms
+func TestAPIGeneratesURL(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+	body := `{"title":"Team Sync","start":"2025-11-15T14:00","end":"2025-11-15T15:00"}`
+
+	code, resp := postJSON(t, mux, "/api/google/calendar", body)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%+v)", code, resp)
+	}
+	if !strings.HasPrefix(resp.URL, "https://calendar.google.com/") {
+		t.Errorf("unexpected URL: %s", resp.URL)
+	}
+
+	code, resp = postJSON(t, mux, "/api/apple/calendar", body)
+	if code != http.StatusOK || resp.URL == "" {
+		t.Fatalf("apple: expected 200 with URL, got %d (%+v)", code, resp)
+	}
+}
+
+// TestAPIValidationErrors ensures schema and payload errors come back as 400 with field messages
+func TestAPIValidationErrors(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+
+	code, resp := postJSON(t, mux, "/api/google/calendar", `{"start":"2025-11-15T14:00"}`)
+	if code != http.StatusBadRequest || len(resp.Errors) == 0 {
+		t.Fatalf("missing fields: expected 400 with errors, got %d (%+v)", code, resp)
+	}
+
+	code, resp = postJSON(t, mux, "/api/google/calendar", `{"title":"Backwards","start":"2025-11-15T14:00","end":"2025-11-15T13:00"}`)
+	if code != http.StatusBadRequest || resp.Errors["end"] == "" {
+		t.Fatalf("end before start: expected 400 with end error, got %d (%+v)", code, resp)
+	}
+
+	code, _ = postJSON(t, mux, "/api/google/calendar", `not json`)
+	if code != http.StatusBadRequest {
+		t.Errorf("invalid JSON: expected 400, got %d", code)
+	}
+}
+
+// TestAPIGuard ensures WithAPIGuard rate limits the JSON API but not the forms
+func TestAPIGuard(t *testing.T) {
+	srv, err := New("8080", WithAPIGuard(guard.Config{RateLimitRPS: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := srv.GetMux()
+
+	var last int
+	for i := 0; i < 3; i++ {
+		last, _ = postJSON(t, mux, "/api/google/calendar", `{}`)
+	}
+	if last != http.StatusTooManyRequests {
+		t.Errorf("expected 429 after burst, got %d", last)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/google/calendar", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("form page should not be rate limited, got %d", rec.Code)
+	}
+}
+
+// TestAPICapabilities ensures the capability matrix is served and filtered
+func TestAPICapabilities(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/capabilities?platform=google&app=calendar", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var matrix capability.Matrix
+	if err := json.Unmarshal(rec.Body.Bytes(), &matrix); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+	if len(matrix) != 1 || matrix[0].Platform != "google" || matrix[0].App != "calendar" {
+		t.Fatalf("expected only google/calendar, got %+v", matrix)
+	}
+	if got := matrix[0].Field("reminders").Support; got != capability.Partial {
+		t.Errorf("google/calendar reminders: expected partial, got %s", got)
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"log"
+	"net/http"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// ================================================================
+// Browser Validation
+// ================================================================
+// Schema forms validate in the browser with the server's own validator,
+// compiled to WebAssembly (pkg/schema/wasm), so a visitor sees the errors a
+// submission would get - x-validations included - before posting. `make
+// schema-wasm` builds it into pkg/server/wasm, which is embedded. The page
+// fetches the platform's schema.json and runs wellknownValidate on submit;
+// without the build the page keeps its HTML5 constraints. The server
+// validates every submission either way.
+
+// Files built by `make schema-wasm` (GOROOT's wasm_exec.js loads the module)
+const (
+	validatorWASMFile   = "validator.wasm"
+	validatorExecJSFile = "wasm_exec.js"
+)
+
+//go:embed all:wasm
+var wasmFS embed.FS
+
+// validatorWASMBuilt reports whether the validator was built into the binary
+func validatorWASMBuilt() bool {
+	_, err := fs.Stat(wasmFS, "wasm/"+validatorWASMFile)
+	return err == nil
+}
+
+// registerValidatorRoutes serves the validator and its loader under /schema/
+func (s *Server) registerValidatorRoutes() {
+	s.mux.HandleFunc("/schema/"+validatorWASMFile, serveWASMFile(validatorWASMFile, "application/wasm"))
+	s.mux.HandleFunc("/schema/"+validatorExecJSFile, serveWASMFile(validatorExecJSFile, "text/javascript"))
+}
+
+func serveWASMFile(name, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := wasmFS.ReadFile("wasm/" + name)
+		if err != nil {
+			http.Error(w, "browser validator not built (make schema-wasm)", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.Write(data)
+	}
+}
+
+// makeSchemaJSONHandler serves a platform's schema.json, for the browser validator
+func makeSchemaJSONHandler(platform, appType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data := loadPlatformFile(platform, appType, schema.SchemaFilename)
+		if data == nil {
+			log.Printf("schema.json not found for %s/%s", platform, appType)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.Write(data)
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// TestXValidations checks the calendar schemas' endAfterStart rule is enforced
+// by the schema itself, so the browser validator reports it too
+func TestXValidations(t *testing.T) {
+	for _, platform := range []string{"google", "apple"} {
+		_, compiled, v, err := schema.LoadSchemasForRendering(platform, "calendar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs := v.Validate(map[string]interface{}{
+			"title": "Backwards",
+			"start": "2025-11-15T14:00",
+			"end":   "2025-11-15T13:00",
+		}, compiled)
+		if errs["end"] != "End time must be after start time" {
+			t.Errorf("%s: errors = %v, want endAfterStart on end", platform, errs)
+		}
+
+		errs = v.Validate(map[string]interface{}{
+			"title": "Forwards",
+			"start": "2025-11-15T14:00",
+			"end":   "2025-11-15T15:00",
+		}, compiled)
+		if len(errs) != 0 {
+			t.Errorf("%s: unexpected errors %v", platform, errs)
+		}
+	}
+
+	v := schema.NewValidatorV6()
+	compiled, err := v.CompileSchemaJSON("test/numbers", []byte(`{
+		"type": "object",
+		"x-validations": {"range": {"fields": ["max", "min"], "op": ">=", "message": "max below min"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Numbers compare numerically (10 > 9, although "10" < "9")
+	if errs := v.Validate(map[string]interface{}{"min": 9.0, "max": 10.0}, compiled); len(errs) != 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	if errs := v.Validate(map[string]interface{}{"min": 9.0, "max": 8.0}, compiled); errs["max"] != "max below min" {
+		t.Errorf("errors = %v, want max below min", errs)
+	}
+
+	for _, bad := range []string{
+		`{"x-validations": []}`,
+		`{"x-validations": {"custom": {"fields": ["a", "b"]}}}`,
+		`{"x-validations": {"endAfterStart": {"fields": ["a"]}}}`,
+		`{"x-validations": {"equals": {"fields": ["a", "b"], "op": "~="}}}`,
+	} {
+		if _, err := schema.NewValidatorV6().CompileSchemaJSON("test/bad", []byte(bad)); err == nil {
+			t.Errorf("CompileSchemaJSON(%s) should fail", bad)
+		}
+	}
+}
+
+// TestBrowserValidationRoutes checks the schema and validator are served to
+// forms, and forms only opt in when the validator is built in
+func TestBrowserValidationRoutes(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/google/calendar/schema.json", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "x-validations") {
+		t.Fatalf("GET schema.json: %d %.80s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/schema/validator.wasm", nil))
+	built := validatorWASMBuilt()
+	if want := map[bool]int{true: http.StatusOK, false: http.StatusNotFound}[built]; rec.Code != want {
+		t.Errorf("GET validator.wasm = %d, want %d (built: %v)", rec.Code, want, built)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/google/calendar", nil))
+	if got := strings.Contains(rec.Body.String(), `data-schema-url="/google/calendar/schema.json"`); got != built {
+		t.Errorf("form has data-schema-url = %v, want %v", got, built)
+	}
+}
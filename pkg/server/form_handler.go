@@ -0,0 +1,203 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/joeblew999/wellknown/pkg/types"
+)
+
+// URLGenerator is a function that generates a URL/data URI from validated form data
+type URLGenerator func(data map[string]interface{}) (string, error)
+
+// PayloadValidator checks rules the JSON Schema cannot express (e.g., types.ValidateCalendarData)
+type PayloadValidator func(platform string, data map[string]interface{}) error
+
+// FormConfig configures the generic schema form handler for a specific platform
+type FormConfig struct {
+	Platform     string           // "google" or "apple"
+	AppType      string           // "calendar"
+	GenerateURL  URLGenerator     // Function to generate URL/data URI from validated data
+	Validate     PayloadValidator // Optional checks after schema validation
+	SuccessLabel string           // "URL" or "data URI"
+}
+
+// formKey identifies the form's saved state in the visitor's form session
+func (cfg FormConfig) formKey() string {
+	return cfg.Platform + "/" + cfg.AppType
+}
+
+// makeFormHandler creates a handler that renders a platform's schema form and builds its deep link
+func (s *Server) makeFormHandler(cfg FormConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			s.handleFormGET(w, r, cfg)
+			return
+		}
+
+		if r.Method == "POST" {
+			s.handleFormPOST(w, r, cfg)
+			return
+		}
+
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleFormGET renders the schema form
+func (s *Server) handleFormGET(w http.ResponseWriter, r *http.Request, cfg FormConfig) {
+	// Load schemas
+	uiSchemaJSON, compiledSchema, _, err := schema.LoadSchemasForRendering(cfg.Platform, cfg.AppType)
+	if err != nil {
+		http.Error(w, "Failed to load schemas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Parse UI Schema and generate form HTML
+	uiSchema, err := schema.ParseUISchema(uiSchemaJSON)
+	if err != nil {
+		http.Error(w, "Failed to parse UI schema: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Repopulate anything the visitor entered earlier in this session
+	session := s.sessions.Get(w, r)
+	formHTML := session.RenderForm(uiSchema, compiledSchema, cfg.formKey())
+
+	// Render
+	s.render(w, r, PageData{
+		Platform:       cfg.Platform,
+		AppType:        cfg.AppType,
+		CurrentPage:    "custom",
+		TemplateName:   "schema_form",
+		SchemaFormHTML: formHTML,
+
+		BrowserValidation: validatorWASMBuilt(),
+	})
+}
+
+// handleFormPOST handles form submission with validation
+func (s *Server) handleFormPOST(w http.ResponseWriter, r *http.Request, cfg FormConfig) {
+	log.Printf("Request: %s %s", r.Method, r.URL.Path)
+
+	// Parse form (multipart when the schema has file fields)
+	if err := r.ParseMultipartForm(schema.DefaultMaxUploadSize); err != nil && err != http.ErrNotMultipart {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	// Reject submissions that did not come from a form we rendered
+	session, err := s.sessions.VerifyCSRF(r)
+	if err != nil {
+		log.Printf("CSRF check failed: %v", err)
+		http.Error(w, "Form session expired or invalid - reload the page and try again", http.StatusForbidden)
+		return
+	}
+
+	// Load schemas
+	uiSchemaJSON, compiledSchema, validator, err := schema.LoadSchemasForRendering(cfg.Platform, cfg.AppType)
+	if err != nil {
+		http.Error(w, "Failed to load schemas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Convert and validate
+	formData := schema.FormDataToMap(r.Form)
+	_, fileErrors, err := schema.SaveUploadedFiles(r, compiledSchema, s.Uploads, formData)
+	if err != nil {
+		http.Error(w, "Failed to store upload: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Add/remove array item buttons post the form back without submitting it
+	if action := r.Form.Get(schema.ArrayActionFieldName); action != "" {
+		field, err := schema.ApplyArrayAction(formData, compiledSchema, action)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		session.SaveForm(cfg.formKey(), formData, nil)
+		http.Redirect(w, r, s.URLPrefix+"/"+cfg.Platform+"/"+cfg.AppType+"#"+field+"-items", http.StatusSeeOther)
+		return
+	}
+
+	validationErrors := validator.Validate(formData, compiledSchema)
+	for field, msg := range fileErrors {
+		validationErrors[field] = msg
+	}
+
+	// Rules the schema cannot express (time ordering, platform limits)
+	if cfg.Validate != nil {
+		if verr, ok := cfg.Validate(cfg.Platform, formData).(*types.ValidationError); ok {
+			for field, msg := range verr.Fields() {
+				if _, exists := validationErrors[field]; !exists {
+					validationErrors[field] = msg
+				}
+			}
+		}
+	}
+
+	uiSchema, err := schema.ParseUISchema(uiSchemaJSON)
+	if err != nil {
+		http.Error(w, "Failed to parse UI schema: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// If validation failed, re-render form with errors
+	if len(validationErrors) > 0 {
+		log.Printf("Validation errors: %v", validationErrors)
+
+		session.SaveForm(cfg.formKey(), formData, validationErrors)
+		formHTML := session.RenderForm(uiSchema, compiledSchema, cfg.formKey())
+
+		s.render(w, r, PageData{
+			Platform:         cfg.Platform,
+			AppType:          cfg.AppType,
+			CurrentPage:      "custom",
+			TemplateName:     "schema_form",
+			SchemaFormHTML:   formHTML,
+			FormData:         formData,
+			ValidationErrors: validationErrors,
+
+			BrowserValidation: validatorWASMBuilt(),
+		})
+		return
+	}
+
+	// Generate URL
+	url, err := cfg.GenerateURL(formData)
+	if err != nil {
+		http.Error(w, "Failed to generate "+cfg.SuccessLabel+": "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	session.ClearForm(cfg.formKey())
+	log.Printf("SUCCESS! Generated %s %s (length: %d bytes)", cfg.Platform, cfg.SuccessLabel, len(url))
+
+	// Render success
+	s.render(w, r, PageData{
+		Platform:     cfg.Platform,
+		AppType:      cfg.AppType,
+		CurrentPage:  "custom",
+		TemplateName: "success",
+		GeneratedURL: url,
+		ShortURL:     s.shortURL(r, url),
+		SummaryHTML:  uiSchema.GenerateSummaryHTML(compiledSchema, formData),
+	})
+}
+
+// makeExamplesHandler creates a examples handler
+func (s *Server) makeExamplesHandler(platform, appType string, examples interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("Request: %s %s", r.Method, r.URL.Path)
+		s.render(w, r, PageData{
+			Platform:     platform,
+			AppType:      appType,
+			CurrentPage:  "examples",
+			TemplateName: "examples",
+			TestCases:    examples,
+			CSRFField:    s.sessions.Get(w, r).CSRFField(),
+		})
+	}
+}
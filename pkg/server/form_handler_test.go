@@ -0,0 +1,32 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// TestCalendarPOSTRejectsEndBeforeStart ensures payload validation runs after the schema checks
+func TestCalendarPOSTRejectsEndBeforeStart(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+
+	for _, path := range []string{"/google/calendar", "/apple/calendar"} {
+		cookie, token := getFormSession(t, mux, path)
+		values := url.Values{
+			schema.CSRFFieldName: {token},
+			"title":              {"Backwards"},
+			"start":              {"2025-11-15T14:00"},
+			"end":                {"2025-11-15T13:00"},
+		}
+		rec := postForm(mux, path, values, cookie)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("POST %s: expected 200, got %d", path, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "End time must be after start time") {
+			t.Errorf("POST %s: expected end-before-start error", path)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// getFormSession loads the custom form and returns its session cookie and CSRF token
+func getFormSession(t *testing.T, mux *http.ServeMux, path string) (*http.Cookie, string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected 200, got %d", path, rec.Code)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("form page should set a session cookie")
+	}
+	m := regexp.MustCompile(`name="` + schema.CSRFFieldName + `" value="([^"]+)"`).FindStringSubmatch(rec.Body.String())
+	if m == nil {
+		t.Fatal("form page should contain a CSRF field")
+	}
+	return cookies[0], m[1]
+}
+
+// postForm submits values with an optional session cookie
+func postForm(mux *http.ServeMux, path string, values url.Values, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestFormPOSTRequiresCSRF ensures submissions without a valid token are rejected
+func TestFormPOSTRequiresCSRF(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+	values := url.Values{"title": {"Meeting"}, "start": {"2025-11-15T14:00"}, "end": {"2025-11-15T15:00"}}
+
+	if rec := postForm(mux, "/google/calendar", values, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("POST without session: expected 403, got %d", rec.Code)
+	}
+
+	cookie, _ := getFormSession(t, mux, "/google/calendar")
+	values.Set(schema.CSRFFieldName, "wrong")
+	if rec := postForm(mux, "/google/calendar", values, cookie); rec.Code != http.StatusForbidden {
+		t.Errorf("POST with wrong token: expected 403, got %d", rec.Code)
+	}
+}
+
+// TestFormSessionRepopulatesAfterErrors ensures entered values survive a failed validation round-trip
+func TestFormSessionRepopulatesAfterErrors(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+	cookie, token := getFormSession(t, mux, "/google/calendar")
+
+	// Missing start/end fails validation
+	values := url.Values{schema.CSRFFieldName: {token}, "title": {"Half-filled meeting"}}
+	rec := postForm(mux, "/google/calendar", values, cookie)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST: expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "field-error") {
+		t.Error("response should show validation errors")
+	}
+
+	// Reloading the form keeps the entered title
+	req := httptest.NewRequest("GET", "/google/calendar", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `value="Half-filled meeting"`) {
+		t.Error("form should be repopulated from the session")
+	}
+}
+
+// TestFormPOSTShowsSummary ensures the confirmation page summarises the submitted (escaped) data
+func TestFormPOSTShowsSummary(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+	cookie, token := getFormSession(t, mux, "/google/calendar")
+
+	values := url.Values{
+		schema.CSRFFieldName: {token},
+		"title":              {"Review <b>Q4</b>"},
+		"start":              {"2025-11-15T14:00"},
+		"end":                {"2025-11-15T15:00"},
+	}
+	rec := postForm(mux, "/google/calendar", values, cookie)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST: expected 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `class="schema-summary"`) {
+		t.Fatal("success page should contain the summary")
+	}
+	if !strings.Contains(body, "<dd>Review &lt;b&gt;Q4&lt;/b&gt;</dd>") {
+		t.Error("summary should show the escaped title")
+	}
+}
+
+// TestFormArrayActions ensures array items are added and removed by a server round-trip
+func TestFormArrayActions(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+	cookie, token := getFormSession(t, mux, "/apple/calendar")
+
+	getForm := func() string {
+		req := httptest.NewRequest("GET", "/apple/calendar", nil)
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	// Adding an item keeps what was entered and is not validated
+	values := url.Values{
+		schema.CSRFFieldName:        {token},
+		"title":                     {"Planning"},
+		schema.ArrayActionFieldName: {"add:attendees"},
+	}
+	rec := postForm(mux, "/apple/calendar", values, cookie)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("add: expected 303, got %d", rec.Code)
+	}
+	body := getForm()
+	if !strings.Contains(body, `name="attendees[0].email"`) || !strings.Contains(body, `value="Planning"`) {
+		t.Error("form should show the added item and keep the title")
+	}
+	if strings.Contains(body, `<span class="field-error">`) {
+		t.Error("adding an item should not show validation errors")
+	}
+
+	// Removing the first of two items renumbers the rest
+	values = url.Values{
+		schema.CSRFFieldName:        {token},
+		"attendees[0].email":        {"a@example.com"},
+		"attendees[1].email":        {"b@example.com"},
+		schema.ArrayActionFieldName: {"remove:attendees:0"},
+	}
+	if rec := postForm(mux, "/apple/calendar", values, cookie); rec.Code != http.StatusSeeOther {
+		t.Fatalf("remove: expected 303, got %d", rec.Code)
+	}
+	body = getForm()
+	if !regexp.MustCompile(`name="attendees\[0\]\.email"[^>]*value="b@example.com"`).MatchString(body) {
+		t.Error("remaining item should be renumbered to index 0")
+	}
+	if strings.Contains(body, "attendees[1]") || strings.Contains(body, "a@example.com") {
+		t.Error("removed item should be gone")
+	}
+
+	values.Set(schema.ArrayActionFieldName, "remove:title:0")
+	if rec := postForm(mux, "/apple/calendar", values, cookie); rec.Code != http.StatusBadRequest {
+		t.Errorf("action on a non-array field: expected 400, got %d", rec.Code)
+	}
+}
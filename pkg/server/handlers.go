@@ -4,6 +4,8 @@ import (
 	"html/template"
 
 	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/joeblew999/wellknown/pkg/setup/gcp"
+	"github.com/joeblew999/wellknown/pkg/shortlink"
 )
 
 // NavLink represents a single navigation link
@@ -27,15 +29,20 @@ type PageData struct {
 	TemplateName     string            // Template to render within base
 	IsStub           bool              // True for stub/placeholder pages
 	GeneratedURL     string            // Generated deep link URL (for success pages)
+	ShortURL         string            // Short link to GeneratedURL (see package shortlink)
 	Event            interface{}       // Platform-specific event (for form pre-fill) - can be nil
 	Error            string            // Error message (if any)
 	TestCases        interface{}       // Platform-specific examples
 	LocalURL         string            // Desktop URL for QR codes
 	MobileURL        string            // Mobile URL for QR codes
 	SchemaFormHTML   template.HTML     // Dynamically generated form HTML from JSON Schema
+	SummaryHTML      template.HTML     // Read-only summary of submitted data (confirmation pages)
 	FormData         map[string]interface{} // Form data for pre-filling after validation errors
 	ValidationErrors schema.ValidationErrors // Field-level validation errors
+	BrowserValidation bool             // Validate schema forms in the browser (validator.wasm is built in)
 	Navigation       []NavSection      // Server-generated navigation
-	GCPStatus        GCPSetupStatus    // GCP setup status (for tools/gcp-setup page)
+	GCPStatus        gcp.SetupStatus   // GCP setup status (for tools/gcp-setup page)
 	URLPrefix        string            // URL prefix when embedded (e.g., "/demo" in PocketBase)
+	CSRFField        template.HTML     // Hidden CSRF input for forms posting back to the server
+	Links            []*shortlink.Link // Short links and click counts (for the stats page)
 }
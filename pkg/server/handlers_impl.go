@@ -41,35 +41,10 @@ func (s *Server) makeStubHandler(platform, appType string) http.HandlerFunc {
 	}
 }
 
-// registerMapsRoutes registers all Maps routes (stubs for now)
-func (s *Server) registerMapsRoutes() {
-	// Google Maps
-	s.mux.HandleFunc("/google/maps", s.makeStubHandler("google", "maps"))
-	s.mux.HandleFunc("/google/maps/examples", s.makeStubHandler("google", "maps"))
-	s.registry.Register(ServiceConfig{
-		Platform:    "google",
-		AppType:     "maps",
-		Title:       "Google Maps",
-		HasCustom:   true,
-		HasExamples: true,
-	})
-
-	// Apple Maps
-	s.mux.HandleFunc("/apple/maps", s.makeStubHandler("apple", "maps"))
-	s.mux.HandleFunc("/apple/maps/examples", s.makeStubHandler("apple", "maps"))
-	s.registry.Register(ServiceConfig{
-		Platform:    "apple",
-		AppType:     "maps",
-		Title:       "Apple Maps",
-		HasCustom:   true,
-		HasExamples: true,
-	})
-}
-
 // handleAppleCalendarDownload serves .ics file for download
 // This is the CORRECT way to handle Apple Calendar on iOS/macOS
 // Safari cannot handle data:text/calendar URIs - it requires actual file downloads
-func (s *Server) handleAppleCalendarDownload(w http.ResponseWriter, r *http.Request) {
+func handleAppleCalendarDownload(w http.ResponseWriter, r *http.Request) {
 	eventParam := r.URL.Query().Get("event")
 	if eventParam == "" {
 		http.Error(w, "Missing event parameter", http.StatusBadRequest)
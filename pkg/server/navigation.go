@@ -11,9 +11,16 @@ type ServiceConfig struct {
 	HasExamples bool
 }
 
+// ToolConfig represents a page of the Tools navigation section
+type ToolConfig struct {
+	Label string
+	Path  string // Without the URL prefix, e.g. "/tools/stats"
+}
+
 // ServiceRegistry manages registered services (no more global state!)
 type ServiceRegistry struct {
 	services []ServiceConfig
+	tools    []ToolConfig
 }
 
 // NewServiceRegistry creates a new service registry
@@ -28,14 +35,20 @@ func (r *ServiceRegistry) Register(config ServiceConfig) {
 	r.services = append(r.services, config)
 }
 
+// RegisterTool adds a page to the Tools section
+func (r *ServiceRegistry) RegisterTool(tool ToolConfig) {
+	r.tools = append(r.tools, tool)
+}
+
 // GetAll returns all registered services
 func (r *ServiceRegistry) GetAll() []ServiceConfig {
 	return r.services
 }
 
-// Clear removes all registered services (for testing)
+// Clear removes all registered services and tools (for testing)
 func (r *ServiceRegistry) Clear() {
 	r.services = nil
+	r.tools = nil
 }
 
 // GetNavigation returns navigation for the current request path
@@ -79,16 +92,21 @@ func (r *ServiceRegistry) GetNavigationWithPrefix(currentPath string, urlPrefix
 	}
 
 	// Add Tools section at the end
-	sections = append(sections, NavSection{
-		Title: "Tools",
-		Links: []NavLink{
-			{
-				Label:    "GCP OAuth Setup",
-				URL:      fmt.Sprintf("%s/tools/gcp-setup", urlPrefix),
-				IsActive: currentPath == fmt.Sprintf("%s/tools/gcp-setup", urlPrefix),
-			},
-		},
-	})
+	var tools []NavLink
+	for _, tool := range r.tools {
+		toolURL := urlPrefix + tool.Path
+		tools = append(tools, NavLink{
+			Label:    tool.Label,
+			URL:      toolURL,
+			IsActive: currentPath == toolURL,
+		})
+	}
+	if len(tools) > 0 {
+		sections = append(sections, NavSection{
+			Title: "Tools",
+			Links: tools,
+		})
+	}
 
 	return sections
 }
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/guard"
+	"github.com/joeblew999/wellknown/pkg/health"
+	"github.com/joeblew999/wellknown/pkg/setup/gcp"
+	"github.com/joeblew999/wellknown/pkg/shortlink"
+)
+
+// ================================================================
+// Server Options
+// ================================================================
+// New accepts options to compose the demo with other handlers:
+//
+//	srv, err := server.New("8080",
+//	    server.WithMount("/pdf", pdfHandler),
+//	    server.WithMiddleware(server.Recovery, server.Logging, server.Gzip),
+//	    server.WithAPIGuard(guard.ConfigFromRegistry(registry)),
+//	    server.WithCertProvider(pdfform.GetCertPaths),
+//	)
+
+// Option configures a Server in New
+type Option func(*Server)
+
+// CertProvider returns the TLS certificate and key files to serve HTTPS with.
+// certs.Provider (pkg/certs) and pdfform.GetCertPaths (pkg/pdf) have this signature.
+type CertProvider func() (certPath, keyPath string, err error)
+
+// mount is a handler served under a path prefix
+type mount struct {
+	prefix  string
+	handler http.Handler
+}
+
+// WithMount serves h under prefix (e.g., "/pdf"), with the prefix stripped from the request path
+func WithMount(prefix string, h http.Handler) Option {
+	return func(s *Server) {
+		prefix = "/" + strings.Trim(prefix, "/")
+		s.mounts = append(s.mounts, mount{prefix: prefix, handler: h})
+	}
+}
+
+// WithMiddleware appends middleware around every route, including mounts (first is outermost)
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(s *Server) {
+		s.middleware = append(s.middleware, middleware...)
+	}
+}
+
+// WithAPIGuard applies CORS and rate limiting (see package guard) to the JSON API routes
+func WithAPIGuard(cfg guard.Config) Option {
+	return func(s *Server) {
+		s.apiGuard = cfg.Middleware()
+	}
+}
+
+// WithHealth serves /health from c (see package health), e.g. the checker of
+// the application embedding the demo, instead of a checker of its own
+func WithHealth(c *health.Checker) Option {
+	return func(s *Server) {
+		s.health = c
+	}
+}
+
+// WithShortLinks keeps short links in store (e.g., a shortlink.FileStore, or
+// the PocketBase-backed store) instead of memory
+func WithShortLinks(store shortlink.Store) Option {
+	return func(s *Server) {
+		s.Links = store
+	}
+}
+
+// WithGCPSetup mounts the GCP setup wizard, storing its values with store
+// (see package setup/gcp). Without it the wizard is not served.
+func WithGCPSetup(store *gcp.Store) Option {
+	return func(s *Server) {
+		s.gcpSetup = store
+	}
+}
+
+// WithTLS serves HTTPS using the given certificate and key files
+func WithTLS(certPath, keyPath string) Option {
+	return WithCertProvider(func() (string, string, error) {
+		return certPath, keyPath, nil
+	})
+}
+
+// WithCertProvider serves HTTPS using certificates from p, resolved when the server starts
+func WithCertProvider(p CertProvider) Option {
+	return func(s *Server) {
+		s.certs = p
+	}
+}
+
+// registerMounts registers the WithMount handlers on the mux
+func (s *Server) registerMounts() {
+	for _, m := range s.mounts {
+		h := http.StripPrefix(m.prefix, m.handler)
+		s.mux.Handle(m.prefix+"/", h)
+		s.mux.Handle(m.prefix, http.RedirectHandler(m.prefix+"/", http.StatusMovedPermanently))
+	}
+}
+
+// Handler returns the server's mux wrapped in its middleware chain
+func (s *Server) Handler() http.Handler {
+	return Chain(s.mux, s.middleware...)
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+	"github.com/joeblew999/wellknown/pkg/health"
+	"github.com/joeblew999/wellknown/pkg/setup/gcp"
+)
+
+// TestWithMountStripsPrefix ensures mounted handlers see paths relative to their prefix
+func TestWithMountStripsPrefix(t *testing.T) {
+	var gotPath string
+	srv, err := New("8080", WithMount("/tools/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/tools/status", nil))
+	if rec.Code != http.StatusOK || gotPath != "/status" {
+		t.Errorf("expected mounted handler to see /status, got %d %q", rec.Code, gotPath)
+	}
+
+	// Existing routes are untouched
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/google/calendar", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /google/calendar: expected 200, got %d", rec.Code)
+	}
+}
+
+// TestMiddlewareChain ensures middleware wraps routes and mounts in order
+func TestMiddlewareChain(t *testing.T) {
+	panics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	srv, err := New("8080",
+		WithMount("/panic", panics),
+		WithMiddleware(Recovery, CORS("https://example.com"), Gzip),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/panic/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Recovery: expected 500, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest("GET", "/google/calendar", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("CORS: expected allowed origin header, got %q", got)
+	}
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("Gzip: expected gzip content encoding")
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("Gzip: invalid body: %v", err)
+	}
+	if body, _ := io.ReadAll(zr); len(body) == 0 {
+		t.Error("Gzip: expected non-empty page")
+	}
+}
+
+// TestWithHealth ensures /health serves the given checker's report
+func TestWithHealth(t *testing.T) {
+	checker := health.New("test")
+	checker.Register("upstream", func(ctx context.Context) error { return errors.New("unreachable") })
+	srv, err := New("8080", WithHealth(checker))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	var report health.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || report.Status != health.StatusDown || report.Service != "test" {
+		t.Errorf("expected 503 down from the test checker, got %d %+v", rec.Code, report)
+	}
+}
+
+// TestWithGCPSetup ensures the GCP setup wizard is only served when configured
+func TestWithGCPSetup(t *testing.T) {
+	get := func(srv *Server, path string) (int, string) {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	srv, err := New("8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/tools/gcp-setup", "/api/gcp-setup/status"} {
+		if code, _ := get(srv, path); code != http.StatusNotFound {
+			t.Errorf("GET %s without WithGCPSetup: expected 404, got %d", path, code)
+		}
+	}
+	if _, body := get(srv, "/"); strings.Contains(body, "/tools/gcp-setup") || !strings.Contains(body, "/tools/stats") {
+		t.Error("navigation should list Link Stats only")
+	}
+
+	dir := t.TempDir()
+	srv, err = New("8080", WithGCPSetup(&gcp.Store{Environment: env.SecretsLocal.WithBaseDir(dir)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := get(srv, "/tools/gcp-setup"); code != http.StatusOK {
+		t.Errorf("GET /tools/gcp-setup: expected 200, got %d", code)
+	}
+	if _, body := get(srv, "/"); !strings.Contains(body, "/tools/gcp-setup") {
+		t.Error("navigation should link the wizard")
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	applecalendar "github.com/joeblew999/wellknown/pkg/apple/calendar"
+	"github.com/joeblew999/wellknown/pkg/flow"
+	googlecalendar "github.com/joeblew999/wellknown/pkg/google/calendar"
+	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/joeblew999/wellknown/pkg/types"
+)
+
+// ================================================================
+// Platform Registry
+// ================================================================
+// Every wellknown builder shown in the demo is one Platform entry. The
+// server iterates the list to register routes and navigation, so adding a
+// builder (pkg/<platform>/<app> with schema.json, uischema.json and
+// data-examples.json) needs only a new entry here - no routing code.
+
+// Platform describes one platform/app the demo server serves
+type Platform struct {
+	Platform     string                      // URL segment and schema directory (e.g., "google")
+	AppType      string                      // URL segment and schema directory (e.g., "calendar")
+	Title        string                      // Navigation title (e.g., "Google Calendar")
+	Build        URLGenerator                // Deep link builder; nil renders a "coming soon" stub
+	Validate     PayloadValidator            // Optional checks after schema validation
+	SuccessLabel string                      // What Build returns (e.g., "URL", "Download Link")
+	Examples     []types.Example             // Showcase cases (default: loaded from data-examples.json)
+	Routes       map[string]http.HandlerFunc // Extra routes the builder needs (e.g., downloads)
+}
+
+// Path returns the platform's base route (e.g., "/google/calendar")
+func (p Platform) Path() string {
+	return "/" + p.Platform + "/" + p.AppType
+}
+
+// DefaultPlatforms returns the platforms served by the demo, in navigation order
+func DefaultPlatforms() []Platform {
+	return []Platform{
+		{
+			Platform:     "google",
+			AppType:      "calendar",
+			Title:        "Google Calendar",
+			Build:        googlecalendar.GenerateURL,
+			Validate:     types.ValidateCalendarData,
+			SuccessLabel: "URL",
+		},
+		{
+			Platform:     "apple",
+			AppType:      "calendar",
+			Title:        "Apple Calendar",
+			Build:        applecalendar.GenerateDownloadURL,
+			Validate:     types.ValidateCalendarData,
+			SuccessLabel: "Download Link",
+			Routes: map[string]http.HandlerFunc{
+				"/apple/calendar/download": handleAppleCalendarDownload,
+			},
+		},
+		{Platform: "google", AppType: "maps", Title: "Google Maps"},
+		{Platform: "apple", AppType: "maps", Title: "Apple Maps"},
+	}
+}
+
+// RegisterPlatform registers the platform's form, examples, JSON API and extra routes, and adds it to navigation
+func (s *Server) RegisterPlatform(p Platform) {
+	mainPath := p.Path()
+	examplesPath := mainPath + "/examples"
+
+	if p.Build == nil {
+		s.mux.HandleFunc(mainPath, s.makeStubHandler(p.Platform, p.AppType))
+		s.mux.HandleFunc(examplesPath, s.makeStubHandler(p.Platform, p.AppType))
+	} else {
+		cfg := FormConfig{
+			Platform:     p.Platform,
+			AppType:      p.AppType,
+			GenerateURL:  p.Build,
+			Validate:     p.Validate,
+			SuccessLabel: p.SuccessLabel,
+		}
+		s.mux.HandleFunc(mainPath, s.makeFormHandler(cfg))
+		s.mux.HandleFunc(mainPath+"/"+schema.SchemaFilename, makeSchemaJSONHandler(p.Platform, p.AppType))
+		var api http.Handler = s.makeAPIHandler(cfg)
+		if s.apiGuard != nil {
+			api = s.apiGuard(api)
+		}
+		s.mux.Handle(APIPrefix+mainPath, api)
+
+		examples := p.Examples
+		if examples == nil {
+			examples = loadPlatformExamples(p.Platform, p.AppType)
+		}
+		s.mux.HandleFunc(examplesPath, s.makeExamplesHandler(p.Platform, p.AppType, examples))
+	}
+
+	for path, handler := range p.Routes {
+		s.mux.HandleFunc(path, handler)
+	}
+
+	s.registry.Register(ServiceConfig{
+		Platform:    p.Platform,
+		AppType:     p.AppType,
+		Title:       p.Title,
+		HasCustom:   true,
+		HasExamples: true,
+	})
+}
+
+// RegisterFlowFuncs registers each platform's builder as a flow step named
+// "<platform>.<app>" (e.g. "google.calendar"), with the platform's schema.json
+// as its input schema and the built link as its {"result": ...} output
+func RegisterFlowFuncs(reg *flow.Registry, platforms []Platform) error {
+	for _, p := range platforms {
+		if p.Build == nil {
+			continue
+		}
+		var opts []flow.FuncOption
+		if schemaJSON := loadPlatformFile(p.Platform, p.AppType, schema.SchemaFilename); schemaJSON != nil {
+			opts = append(opts, flow.WithInputSchema(string(schemaJSON)))
+		}
+		opts = append(opts, flow.WithFuncDescription("Build a "+p.Title+" "+p.SuccessLabel))
+		if err := reg.Register(p.Platform+"."+p.AppType, p.Build, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadPlatformFile reads pkg/<platform>/<app>/<name> (nil if not found), trying
+// the same locations as loadPlatformExamples
+func loadPlatformFile(platform, appType, name string) []byte {
+	rel := filepath.Join(platform, appType, name)
+	for _, path := range []string{
+		filepath.Join("pkg", rel),
+		filepath.Join("..", "..", "pkg", rel),
+		filepath.Join("..", rel),
+	} {
+		if data, err := os.ReadFile(path); err == nil {
+			return data
+		}
+	}
+	return nil
+}
+
+// loadPlatformExamples loads pkg/<platform>/<app>/data-examples.json, trying the
+// same locations as schema.LoadSchemasForRendering (project root, cmd/server, pkg/server)
+func loadPlatformExamples(platform, appType string) []types.Example {
+	rel := filepath.Join(platform, appType, schema.ExamplesFilename)
+	for _, path := range []string{
+		filepath.Join("pkg", rel),
+		filepath.Join("..", "..", "pkg", rel),
+		filepath.Join("..", rel),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		examples, err := types.LoadExamples(path)
+		if err != nil {
+			log.Printf("⚠️  Failed to load %s: %v", path, err)
+		}
+		return examples
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/flow"
+)
+
+// TestDefaultPlatformsRegistered ensures every default platform serves its form and examples pages
+func TestDefaultPlatformsRegistered(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+
+	for _, p := range DefaultPlatforms() {
+		for _, path := range []string{p.Path(), p.Path() + "/examples"} {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("GET %s: expected 200, got %d", path, rec.Code)
+			}
+		}
+	}
+}
+
+// TestRegisterPlatformAddsRoutesAndNavigation ensures a new Platform needs no routing code
+func TestRegisterPlatformAddsRoutesAndNavigation(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.RegisterPlatform(Platform{
+		Platform: "acme",
+		AppType:  "notes",
+		Title:    "Acme Notes",
+		Routes: map[string]http.HandlerFunc{
+			"/acme/notes/export": func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		},
+	})
+
+	for path, want := range map[string]int{
+		"/acme/notes":          http.StatusOK,
+		"/acme/notes/examples": http.StatusOK,
+		"/acme/notes/export":   http.StatusNoContent,
+	} {
+		rec := httptest.NewRecorder()
+		srv.GetMux().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+
+	found := false
+	for _, svc := range srv.GetRegistry().GetAll() {
+		if svc.Platform == "acme" && svc.AppType == "notes" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected acme/notes in navigation registry")
+	}
+}
+
+// TestRegisterFlowFuncs ensures builders run as flow steps validated by their schema.json
+func TestRegisterFlowFuncs(t *testing.T) {
+	reg := flow.NewRegistry()
+	if err := RegisterFlowFuncs(reg, DefaultPlatforms()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(reg.Funcs()); got != 2 {
+		t.Fatalf("expected 2 funcs (maps builders are stubs), got %d", got)
+	}
+
+	f := flow.New("invite", "Invite")
+	step, err := reg.Step("event", "google.calendar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.MustAddStep(step)
+	runner, err := flow.NewRunner(f, flow.NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := runner.Start(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err = runner.Submit(context.Background(), run.ID, "event", map[string]interface{}{
+		"title": "Standup",
+		"start": "2025-01-06T09:00",
+		"end":   "2025-01-06T09:15",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, _ := run.Steps["event"].Output["result"].(string)
+	if run.Status != flow.StatusCompleted || !strings.HasPrefix(url, "https://calendar.google.com/") {
+		t.Errorf("run %s, output %+v, errors %+v", run.Status, run.Steps["event"].Output, run.Steps["event"].Errors)
+	}
+}
@@ -1,26 +1,37 @@
 package server
 
 import (
-	"fmt"
 	"net/http"
 
-	applecalendar "github.com/joeblew999/wellknown/pkg/apple/calendar"
-	googlecalendar "github.com/joeblew999/wellknown/pkg/google/calendar"
-	"github.com/joeblew999/wellknown/pkg/types"
+	"github.com/joeblew999/wellknown/pkg/schema"
 )
 
 // registerAllRoutes registers all HTTP routes with the server's mux and registry
 // This is called during Server.New() initialization
 func (s *Server) registerAllRoutes() {
-	// Register calendar services
-	s.registerCalendarServices()
-
-	// Maps services (stubs for now)
-	s.registerMapsRoutes()
+	// Platforms (forms, examples and navigation)
+	for _, p := range DefaultPlatforms() {
+		s.RegisterPlatform(p)
+	}
 
 	// Tools
 	s.registerGCPSetupRoutes()
 
+	// Short links and click stats
+	s.registerShortLinkRoutes()
+
+	// Capability matrix (which fields each builder carries)
+	s.mux.HandleFunc(APIPrefix+"/capabilities", handleCapabilities)
+
+	// Dynamic select options (x-optionsSource URLs)
+	s.mux.HandleFunc("/api/options/{name}", schema.HandleOptionsSource)
+
+	// Schema validator compiled to WebAssembly (browser validation)
+	s.registerValidatorRoutes()
+
+	// Health check (status, version, uptime and registered checks)
+	s.health.RegisterRoutes(s.mux)
+
 	// Homepage - shows all available services
 	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" {
@@ -30,72 +41,3 @@ func (s *Server) registerAllRoutes() {
 		http.NotFound(w, r)
 	})
 }
-
-// registerCalendarServices registers all calendar services
-func (s *Server) registerCalendarServices() {
-	// Define all calendar services in ONE place
-	services := []struct {
-		Platform     string
-		AppType      string
-		Title        string
-		SuccessLabel string
-		GenerateURL  CalendarURLGenerator
-		ExtraRoutes  map[string]http.HandlerFunc
-	}{
-		{
-			Platform:     "google",
-			AppType:      "calendar",
-			Title:        "Google Calendar",
-			SuccessLabel: "URL",
-			GenerateURL:  googlecalendar.GenerateURL,
-			ExtraRoutes:  nil,
-		},
-		{
-			Platform:     "apple",
-			AppType:      "calendar",
-			Title:        "Apple Calendar",
-			SuccessLabel: "Download Link",
-			GenerateURL:  applecalendar.GenerateDownloadURL,
-			ExtraRoutes: map[string]http.HandlerFunc{
-				"/apple/calendar/download": s.handleAppleCalendarDownload,
-			},
-		},
-	}
-
-	for _, svc := range services {
-		// Create calendar handler
-		handler := s.makeGenericCalendarHandler(CalendarConfig{
-			Platform:     svc.Platform,
-			AppType:      svc.AppType,
-			SuccessLabel: svc.SuccessLabel,
-			GenerateURL:  svc.GenerateURL,
-		})
-
-		// Load showcase examples from JSON
-		examplesFile := fmt.Sprintf("pkg/%s/%s/data-examples.json", svc.Platform, svc.AppType)
-		examples, _ := types.LoadExamples(examplesFile)
-		examplesHandler := s.makeExamplesHandler(svc.Platform, svc.AppType, examples)
-
-		// Register main handler
-		mainPath := "/" + svc.Platform + "/" + svc.AppType
-		s.mux.HandleFunc(mainPath, handler)
-
-		// Register examples handler
-		examplesPath := mainPath + "/examples"
-		s.mux.HandleFunc(examplesPath, examplesHandler)
-
-		// Register extra routes (if any)
-		for path, extraHandler := range svc.ExtraRoutes {
-			s.mux.HandleFunc(path, extraHandler)
-		}
-
-		// Register for navigation
-		s.registry.Register(ServiceConfig{
-			Platform:    svc.Platform,
-			AppType:     svc.AppType,
-			Title:       svc.Title,
-			HasCustom:   true,
-			HasExamples: true,
-		})
-	}
-}
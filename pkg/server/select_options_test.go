@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+const selectSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "state": {"type": "string", "oneOf": [{"const": "VIC", "title": "Victoria"}, {"const": "QLD", "title": "Queensland"}]},
+    "zone": {"type": "string", "x-optionsSource": "timezones"},
+    "form": {"type": "string", "x-optionsSource": "/api/options/forms"}
+  }
+}`
+
+const selectUISchema = `{
+  "type": "VerticalLayout",
+  "elements": [
+    {"type": "Control", "scope": "#/properties/state"},
+    {"type": "Control", "scope": "#/properties/zone"},
+    {"type": "Control", "scope": "#/properties/form"}
+  ]
+}`
+
+// TestSelectOptions checks oneOf labels, callback sources and URL sources render as selects
+func TestSelectOptions(t *testing.T) {
+	v := schema.NewValidatorV6()
+	compiled, err := v.CompileSchemaJSON("test/select", []byte(selectSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui, err := schema.ParseUISchema(selectUISchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	html := string(ui.GenerateFormHTMLWithData(compiled, map[string]interface{}{"state": "QLD"}, nil))
+	for _, want := range []string{
+		`<option value="QLD" selected>Queensland</option>`,
+		`<option value="Australia/Sydney"`,
+		`data-options-source="/api/options/forms"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("form HTML missing %s", want)
+		}
+	}
+
+	// oneOf consts still validate the value
+	if errs := v.Validate(map[string]interface{}{"state": "NSW"}, compiled); len(errs) == 0 {
+		t.Error("expected a value outside oneOf to fail validation")
+	}
+	if errs := v.Validate(map[string]interface{}{"state": "VIC", "zone": "anything"}, compiled); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+// TestOptionsSourceRoute checks registered sources are served as JSON
+func TestOptionsSourceRoute(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/options/timezones", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var options []schema.Option
+	if err := json.Unmarshal(rec.Body.Bytes(), &options); err != nil {
+		t.Fatal(err)
+	}
+	if len(options) == 0 || options[0].Value != "UTC" {
+		t.Errorf("unexpected timezone options: %v", options)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/api/options/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown source: expected 404, got %d", rec.Code)
+	}
+}
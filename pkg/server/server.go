@@ -8,7 +8,14 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/health"
+	"github.com/joeblew999/wellknown/pkg/schema"
+	"github.com/joeblew999/wellknown/pkg/setup/gcp"
+	"github.com/joeblew999/wellknown/pkg/shortlink"
 )
 
 //go:embed templates/*
@@ -19,19 +26,27 @@ type Server struct {
 	Port      string
 	LocalURL  string
 	MobileURL string
-	URLPrefix string // URL prefix when embedded (e.g., "/demo" in PocketBase)
+	URLPrefix string           // URL prefix when embedded (e.g., "/demo" in PocketBase)
+	Uploads   schema.FileStore // Where file fields are stored (default: local temp directory)
+	Links     shortlink.Store  // Short links for generated URLs (default: in memory)
 
 	// Dependencies (no more globals!)
 	templates *template.Template
 	mux       *http.ServeMux
 	registry  *ServiceRegistry
-
-	// State (no more package-level globals!)
-	gcpSetupStatus GCPSetupStatus
+	sessions  *schema.FormSessionManager
+
+	// Composition (see options.go)
+	mounts     []mount
+	middleware []Middleware
+	certs      CertProvider
+	apiGuard   Middleware
+	health     *health.Checker
+	gcpSetup   *gcp.Store // GCP setup wizard, mounted only when set
 }
 
 // New creates a new Server instance with all dependencies initialized
-func New(port string) (*Server, error) {
+func New(port string, opts ...Option) (*Server, error) {
 	if port == "" {
 		port = "8080"
 	}
@@ -59,10 +74,23 @@ func New(port string) (*Server, error) {
 		templates: tmpl,
 		mux:       http.NewServeMux(),
 		registry:  NewServiceRegistry(),
+		sessions:  schema.NewFormSessionManager(),
+		Uploads:   schema.NewLocalFileStore(filepath.Join(os.TempDir(), "wellknown-uploads")),
+		Links:     shortlink.NewMemoryStore(),
+		health:    health.New("demo"),
+	}
+
+	for _, opt := range opts {
+		opt(server)
+	}
+	if server.certs != nil {
+		server.LocalURL = "https://localhost" + addr
+		server.MobileURL = "https://" + localIP + addr
 	}
 
 	// Register all routes with server's mux and registry
 	server.registerAllRoutes()
+	server.registerMounts()
 
 	return server, nil
 }
@@ -77,7 +105,18 @@ func (s *Server) Start() error {
 	log.Println("")
 	log.Println("💡 Press Ctrl+C to stop")
 
-	if err := http.ListenAndServe(addr, s.mux); err != nil {
+	if s.certs != nil {
+		certPath, keyPath, err := s.certs()
+		if err != nil {
+			return fmt.Errorf("failed to get TLS certificates: %w", err)
+		}
+		if err := http.ListenAndServeTLS(addr, certPath, keyPath, s.Handler()); err != nil {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	}
+
+	if err := http.ListenAndServe(addr, s.Handler()); err != nil {
 		return fmt.Errorf("server failed: %w", err)
 	}
 	return nil
@@ -88,11 +127,16 @@ func (s *Server) GetTemplates() *template.Template {
 	return s.templates
 }
 
-// GetMux returns the server's HTTP mux (for testing)
+// GetMux returns the server's HTTP mux, without middleware (for testing)
 func (s *Server) GetMux() *http.ServeMux {
 	return s.mux
 }
 
+// Health returns the checker behind /health, to register checks on
+func (s *Server) Health() *health.Checker {
+	return s.health
+}
+
 // GetRegistry returns the server's service registry (for handlers)
 func (s *Server) GetRegistry() *ServiceRegistry {
 	return s.registry
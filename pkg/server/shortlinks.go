@@ -0,0 +1,73 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/joeblew999/wellknown/pkg/shortlink"
+)
+
+// ================================================================
+// Short Links & Click Stats
+// ================================================================
+// Every generated URL gets a short link (/s/<code>) on the success page.
+// Visiting it counts a click and redirects to the deep link; /tools/stats
+// lists the links by clicks. Links live in Server.Links (see WithShortLinks).
+
+// ShortLinkPrefix is the route prefix of short links
+const ShortLinkPrefix = "/s/"
+
+// registerShortLinkRoutes registers the redirect and stats routes
+func (s *Server) registerShortLinkRoutes() {
+	s.mux.HandleFunc(ShortLinkPrefix+"{code}", s.handleShortLink)
+	s.mux.HandleFunc("/tools/stats", s.handleLinkStats)
+	s.registry.RegisterTool(ToolConfig{Label: "Link Stats", Path: "/tools/stats"})
+}
+
+// handleShortLink counts a click and redirects to the link's target
+func (s *Server) handleShortLink(w http.ResponseWriter, r *http.Request) {
+	link, err := s.Links.Click(r.PathValue("code"))
+	if errors.Is(err, shortlink.ErrLinkNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to resolve link: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, link.Target, http.StatusFound)
+}
+
+// handleLinkStats renders the short links with their click counts
+func (s *Server) handleLinkStats(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Request: GET %s", r.URL.Path)
+
+	links, err := s.Links.List()
+	if err != nil {
+		http.Error(w, "Failed to list links: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.render(w, r, PageData{
+		Platform:     "tools",
+		AppType:      "stats",
+		CurrentPage:  "stats",
+		TemplateName: "stats",
+		Links:        links,
+	})
+}
+
+// shortURL shortens target and returns the absolute short link ("" if the
+// store failed - the success page then shows only the full URL)
+func (s *Server) shortURL(r *http.Request, target string) string {
+	link, err := s.Links.Shorten(target)
+	if err != nil {
+		log.Printf("⚠️  Failed to shorten link: %v", err)
+		return ""
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + s.URLPrefix + ShortLinkPrefix + link.Code
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// TestShortLinkRoundTrip ensures the success page links a short URL that redirects and counts clicks
+func TestShortLinkRoundTrip(t *testing.T) {
+	mux := setupTestServer(t).GetMux()
+	cookie, token := getFormSession(t, mux, "/google/calendar")
+
+	values := url.Values{
+		schema.CSRFFieldName: {token},
+		"title":              {"Team Sync"},
+		"start":              {"2025-11-15T14:00"},
+		"end":                {"2025-11-15T15:00"},
+	}
+	rec := postForm(mux, "/google/calendar", values, cookie)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST: expected 200, got %d", rec.Code)
+	}
+	m := regexp.MustCompile(`href="http://example\.com(/s/[0-9A-Za-z]+)"`).FindStringSubmatch(rec.Body.String())
+	if m == nil {
+		t.Fatal("success page should show a short link")
+	}
+
+	for i := 0; i < 2; i++ {
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", m[1], nil))
+		if rec.Code != http.StatusFound {
+			t.Fatalf("short link: expected 302, got %d", rec.Code)
+		}
+		if loc := rec.Header().Get("Location"); !strings.HasPrefix(loc, "https://calendar.google.com/") {
+			t.Errorf("short link should redirect to the calendar URL, got %s", loc)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/tools/stats", nil))
+	if !regexp.MustCompile(`<td class="clicks">2</td>`).MatchString(rec.Body.String()) {
+		t.Error("stats page should show 2 clicks")
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/s/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown code: expected 404, got %d", rec.Code)
+	}
+}
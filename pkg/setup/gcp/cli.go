@@ -0,0 +1,160 @@
+package gcp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// ================================================================
+// Terminal Wizard
+// ================================================================
+// RunCLI is the web wizard's steps as prompts: the project ID, then the
+// Console pages to visit (enable APIs, consent screen, OAuth client), then
+// the client ID and secret (hidden). Enter keeps a stored value, so the
+// wizard can be re-run to change one value or to finish a partial setup.
+
+// CLIOptions configures RunCLI
+type CLIOptions struct {
+	Store *Store    // Where values are stored (default: &Store{})
+	Out   io.Writer // Where prompts are written (default: os.Stdout)
+
+	// ReadLine reads one line of input (default: from os.Stdin)
+	ReadLine func() (string, error)
+	// ReadSecret reads the client secret (default: env.TerminalSecretReader
+	// on a terminal, which hides input; else ReadLine)
+	ReadSecret func() (string, error)
+}
+
+// RunCLI runs the setup in the terminal and returns the final status
+func RunCLI(opts CLIOptions) (SetupStatus, error) {
+	if opts.Store == nil {
+		opts.Store = &Store{}
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.ReadLine == nil {
+		opts.ReadLine = lineReader(os.Stdin)
+	}
+	if opts.ReadSecret == nil {
+		opts.ReadSecret = opts.ReadLine
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			opts.ReadSecret = env.TerminalSecretReader(os.Stdin, opts.Out)
+		}
+	}
+	out := opts.Out
+
+	status, err := opts.Store.Status()
+	if err != nil {
+		return status, err
+	}
+	fmt.Fprintf(out, "Google Cloud OAuth setup (values are stored encrypted in %s)\n\n", status.EnvPath)
+
+	// Step 1: project
+	fmt.Fprintf(out, "1. Create a project (or pick an existing one): %s\n", ProjectCreateURL)
+	for {
+		projectID, err := prompt(opts, "   Project ID", status.ProjectID, false)
+		if err != nil {
+			return status, err
+		}
+		if projectID == status.ProjectID && status.ProjectDone {
+			break
+		}
+		if err := ValidateProjectID(projectID); err != nil {
+			fmt.Fprintf(out, "   invalid: %v - try again\n", err)
+			continue
+		}
+		if status, err = opts.Store.SaveProject(projectID); err != nil {
+			return status, err
+		}
+		break
+	}
+
+	// Steps 2-3: Console only
+	fmt.Fprintln(out, "\n2. Enable the APIs:")
+	for _, api := range RequiredAPIs {
+		fmt.Fprintf(out, "   %s\n", EnableAPIURL(status.ProjectID, api))
+	}
+	if err := waitForEnter(opts); err != nil {
+		return status, err
+	}
+	fmt.Fprintf(out, "\n3. Configure the OAuth consent screen: %s\n", ConsentURL(status.ProjectID))
+	if err := waitForEnter(opts); err != nil {
+		return status, err
+	}
+	status.APIDone, status.ConsentDone = true, true
+
+	// Step 4: credentials
+	fmt.Fprintf(out, "\n4. Create an OAuth client (Web application) with redirect URI %s:\n   %s\n",
+		DefaultRedirectURL, CredentialsURL(status.ProjectID))
+	clientID, err := prompt(opts, "   Client ID", status.ClientID, false)
+	if err != nil {
+		return status, err
+	}
+	clientSecret, err := prompt(opts, "   Client secret", status.ClientSecret, true)
+	if err != nil {
+		return status, err
+	}
+	if clientID == "" || clientSecret == "" {
+		fmt.Fprintln(out, "\n⚠️  No OAuth client saved yet; run the setup again once it exists")
+		return status, nil
+	}
+	if clientID != status.ClientID || clientSecret != status.ClientSecret {
+		saved, err := opts.Store.SaveCredentials(clientID, clientSecret)
+		if err != nil {
+			return status, err
+		}
+		saved.APIDone, saved.ConsentDone = true, true
+		status = saved
+	}
+
+	fmt.Fprintf(out, "\n✅ Setup complete: %s\n", status.EnvPath)
+	return status, nil
+}
+
+// prompt reads a value; Enter keeps current. Secrets are never echoed back.
+func prompt(opts CLIOptions, label, current string, secret bool) (string, error) {
+	switch {
+	case current != "" && secret:
+		label += " [set]"
+	case current != "":
+		label += " [" + current + "]"
+	}
+	fmt.Fprintf(opts.Out, "%s: ", label)
+
+	read := opts.ReadLine
+	if secret {
+		read = opts.ReadSecret
+	}
+	value, err := read()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", strings.TrimSpace(label), err)
+	}
+	if value = strings.TrimSpace(value); value == "" {
+		return current, nil
+	}
+	return value, nil
+}
+
+func waitForEnter(opts CLIOptions) error {
+	fmt.Fprint(opts.Out, "   Press Enter when done ")
+	_, err := opts.ReadLine()
+	return err
+}
+
+// lineReader returns a func reading lines from in
+func lineReader(in io.Reader) func() (string, error) {
+	reader := bufio.NewReader(in)
+	return func() (string, error) {
+		line, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+}
@@ -0,0 +1,213 @@
+// Package gcp walks through creating the Google Cloud OAuth client that
+// Google sign-in and the Calendar API need, from a terminal (RunCLI) or the
+// browser (the /tools/gcp-setup wizard of pkg/server, backed by Handler).
+//
+// Progress is not kept in a file of its own: the project ID and OAuth
+// credentials are stored with env.SetSecrets in the encrypted secrets file
+// (.env.secrets.local.age by default), next to the application's other
+// secrets, and SetupStatus is derived from them.
+//
+//	store := &gcp.Store{}
+//	status, err := store.SaveProject("wellknown-dev-123")
+//	status, err = store.SaveCredentials(clientID, clientSecret)
+package gcp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+// Variables stored by the setup
+const (
+	EnvProjectID    = "GCP_PROJECT_ID"
+	EnvClientID     = "GOOGLE_CLIENT_ID"
+	EnvClientSecret = "GOOGLE_CLIENT_SECRET"
+	EnvRedirectURL  = "GOOGLE_REDIRECT_URL"
+)
+
+// DefaultRedirectURL is the OAuth callback of a local PocketBase server,
+// stored with the credentials unless a redirect URL is already set
+const DefaultRedirectURL = "http://localhost:8090/auth/google/callback"
+
+// EnvVars are the registry entries of the stored values (the application
+// registry in pkg/pb defines the same names)
+var EnvVars = []env.EnvVar{
+	{
+		Name:        EnvProjectID,
+		Description: "Google Cloud project the OAuth client belongs to (set by 'wellknown setup gcp')",
+		Secret:      true,
+		Group:       "Google OAuth",
+		Validate:    ValidateProjectID,
+	},
+	{
+		Name:        EnvClientID,
+		Description: "Google OAuth client ID",
+		Secret:      true,
+		Group:       "Google OAuth",
+	},
+	{
+		Name:        EnvClientSecret,
+		Description: "Google OAuth client secret",
+		Secret:      true,
+		Group:       "Google OAuth",
+	},
+	{
+		Name:        EnvRedirectURL,
+		Description: "Google OAuth callback URL",
+		Kind:        env.KindURL,
+		Secret:      true,
+		Group:       "Google OAuth",
+	},
+}
+
+// SetupStatus is the progress of the setup. ProjectDone and CredsDone follow
+// from the stored values; the Console-only steps (APIDone, ConsentDone) are
+// confirmed by the user each session and never stored. The client secret is
+// never serialized: JSON only reports whether one is configured.
+type SetupStatus struct {
+	ProjectID        string `json:"project_id"`
+	ClientID         string `json:"client_id"`
+	ClientSecret     string `json:"-"`
+	SecretConfigured bool   `json:"client_secret_configured"`
+	ProjectDone      bool   `json:"project_done"`
+	APIDone          bool   `json:"api_done"`
+	ConsentDone      bool   `json:"consent_done"`
+	CredsDone        bool   `json:"creds_done"`
+	EnvPath          string `json:"env_path"` // Encrypted secrets file the values are stored in
+}
+
+// Store keeps the setup values in an encrypted secrets file. The zero value
+// uses .env.secrets.local.age and the default age key.
+type Store struct {
+	Registry    *env.Registry    // Registry the values are validated against (default: EnvVars)
+	Environment *env.Environment // Secrets file (default: env.SecretsLocal)
+	KeyPath     string           // Age key (default: env.DefaultAgeKeyPath)
+}
+
+// Status reads the stored values
+func (s *Store) Status() (SetupStatus, error) {
+	e := s.environment()
+	status := SetupStatus{EnvPath: e.FullEncryptedPath()}
+	values, err := env.ReadSecrets(e, s.KeyPath)
+	if err != nil {
+		return status, err
+	}
+	status.ProjectID = values[EnvProjectID]
+	status.ClientID = values[EnvClientID]
+	status.ClientSecret = values[EnvClientSecret]
+	status.SecretConfigured = status.ClientSecret != ""
+	status.ProjectDone = status.ProjectID != ""
+	status.CredsDone = status.ClientID != "" && status.ClientSecret != ""
+	return status, nil
+}
+
+// SaveProject stores the project ID (see ValidateProjectID)
+func (s *Store) SaveProject(projectID string) (SetupStatus, error) {
+	projectID = strings.TrimSpace(projectID)
+	if err := ValidateProjectID(projectID); err != nil {
+		return SetupStatus{}, err
+	}
+	return s.set(map[string]string{EnvProjectID: projectID})
+}
+
+// SaveCredentials stores the OAuth client, and DefaultRedirectURL when no
+// redirect URL is set yet
+func (s *Store) SaveCredentials(clientID, clientSecret string) (SetupStatus, error) {
+	clientID, clientSecret = strings.TrimSpace(clientID), strings.TrimSpace(clientSecret)
+	if clientID == "" || clientSecret == "" {
+		return SetupStatus{}, errors.New("client ID and client secret are required")
+	}
+	values := map[string]string{EnvClientID: clientID, EnvClientSecret: clientSecret}
+	existing, err := env.ReadSecrets(s.environment(), s.KeyPath)
+	if err != nil {
+		return SetupStatus{}, err
+	}
+	if existing[EnvRedirectURL] == "" {
+		values[EnvRedirectURL] = DefaultRedirectURL
+	}
+	return s.set(values)
+}
+
+// Reset clears the stored values; the rest of the secrets file is kept
+func (s *Store) Reset() (SetupStatus, error) {
+	values := make(map[string]string, len(EnvVars))
+	for _, v := range EnvVars {
+		values[v.Name] = ""
+	}
+	return s.set(values)
+}
+
+func (s *Store) set(values map[string]string) (SetupStatus, error) {
+	registry := s.Registry
+	if registry == nil {
+		registry = env.NewRegistry(EnvVars)
+	}
+	if _, err := env.SetSecrets(env.SetSecretsOptions{
+		Registry:    registry,
+		Values:      values,
+		Environment: s.environment(),
+		KeyPath:     s.KeyPath,
+		AppName:     "Wellknown",
+	}); err != nil {
+		return SetupStatus{}, err
+	}
+	return s.Status()
+}
+
+func (s *Store) environment() *env.Environment {
+	if s.Environment == nil {
+		return env.SecretsLocal
+	}
+	return s.Environment
+}
+
+// projectIDPattern: a lowercase letter, then lowercase letters, digits and
+// hyphens, not ending with a hyphen
+var projectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*[a-z0-9]$`)
+
+// ValidateProjectID checks Google Cloud's project ID rules (the same checks
+// the web wizard makes before saving)
+func ValidateProjectID(id string) error {
+	switch {
+	case id == "":
+		return errors.New("project ID is required")
+	case len(id) < 6 || len(id) > 30:
+		return errors.New("project ID must be 6-30 characters long")
+	case id[0] < 'a' || id[0] > 'z':
+		return errors.New("project ID must start with a lowercase letter")
+	case strings.HasSuffix(id, "-"):
+		return errors.New("project ID cannot end with a hyphen")
+	case !projectIDPattern.MatchString(id):
+		return errors.New("project ID can only contain lowercase letters, numbers, and hyphens")
+	}
+	return nil
+}
+
+// consoleURL is the Google Cloud Console, where each step is done
+const consoleURL = "https://console.cloud.google.com"
+
+// ProjectCreateURL opens the Console's new project form
+const ProjectCreateURL = consoleURL + "/projectcreate"
+
+// EnableAPIURL is the Console page enabling api (e.g. "calendar-json") for projectID
+func EnableAPIURL(projectID, api string) string {
+	return fmt.Sprintf("%s/apis/library/%s.googleapis.com?project=%s", consoleURL, api, url.QueryEscape(projectID))
+}
+
+// ConsentURL is the Console's OAuth consent screen for projectID
+func ConsentURL(projectID string) string {
+	return consoleURL + "/apis/credentials/consent?project=" + url.QueryEscape(projectID)
+}
+
+// CredentialsURL is the Console's new OAuth client form for projectID
+func CredentialsURL(projectID string) string {
+	return consoleURL + "/apis/credentials/oauthclient?project=" + url.QueryEscape(projectID)
+}
+
+// RequiredAPIs are enabled in the project: Calendar and OAuth2 (sign-in)
+var RequiredAPIs = []string{"calendar-json", "oauth2"}
@@ -0,0 +1,156 @@
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/wellknown/pkg/env"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.txt")
+	if _, err := env.GenerateAgeKey(env.KeygenOptions{KeyPath: keyPath}); err != nil {
+		t.Fatal(err)
+	}
+	return &Store{Environment: env.SecretsLocal.WithBaseDir(dir), KeyPath: keyPath}
+}
+
+// lines returns answers in order, like a user typing them
+func lines(answers ...string) func() (string, error) {
+	return func() (string, error) {
+		if len(answers) == 0 {
+			return "", errors.New("no more input")
+		}
+		a := answers[0]
+		answers = answers[1:]
+		return a, nil
+	}
+}
+
+func TestValidateProjectID(t *testing.T) {
+	for id, ok := range map[string]bool{
+		"wellknown-dev-123": true,
+		"abc":               false,
+		"My-Project-123":    false,
+		"my-project-":       false,
+		"1project":          false,
+		"my_project":        false,
+	} {
+		if err := ValidateProjectID(id); (err == nil) != ok {
+			t.Errorf("ValidateProjectID(%q) = %v", id, err)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	store := newTestStore(t)
+	h := Handler(store)
+	post := func(path, body string) (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		h.ServeHTTP(rec, r)
+		var resp map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp
+	}
+
+	if code, resp := post("/save-project", `{"project_id": "My-Project"}`); code != http.StatusBadRequest || resp["message"] == nil {
+		t.Errorf("invalid project: %d %v", code, resp)
+	}
+	if code, resp := post("/save-project", `{"project_id": "wellknown-dev-123"}`); code != http.StatusOK || resp["success"] != true {
+		t.Fatalf("save-project: %d %v", code, resp)
+	}
+	if code, _ := post("/save-creds", `{"client_id": "id.apps.googleusercontent.com"}`); code != http.StatusBadRequest {
+		t.Errorf("save-creds without a secret: %d", code)
+	}
+	code, resp := post("/save-creds", `{"client_id": "id.apps.googleusercontent.com", "client_secret": "s3cret"}`)
+	status, _ := resp["status"].(map[string]interface{})
+	if code != http.StatusOK || status["creds_done"] != true || status["project_id"] != "wellknown-dev-123" {
+		t.Fatalf("save-creds: %d %v", code, resp)
+	}
+	if _, ok := status["client_secret"]; ok || status["client_secret_configured"] != true {
+		t.Errorf("save-creds status = %v, want only whether a secret is configured", status)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/status", nil))
+	if strings.Contains(rec.Body.String(), "s3cret") || !strings.Contains(rec.Body.String(), `"client_secret_configured":true`) {
+		t.Errorf("/status = %s, want the secret left out", rec.Body)
+	}
+
+	// A cross-site form post can't send JSON
+	rec = httptest.NewRecorder()
+	form := httptest.NewRequest("POST", "/reset", strings.NewReader("x=1"))
+	form.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.ServeHTTP(rec, form)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("form POST /reset: %d, want 415", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/reset", nil))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("POST /reset without a Content-Type: %d, want 415", rec.Code)
+	}
+
+	// Stored in the encrypted secrets file, with the default redirect URL
+	values, err := env.ReadSecrets(store.Environment, store.KeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[EnvClientSecret] != "s3cret" || values[EnvRedirectURL] != DefaultRedirectURL {
+		t.Errorf("stored values = %v", values)
+	}
+	if store.Environment.Exists() {
+		t.Error("plaintext secrets file must not be written")
+	}
+
+	if code, _ := post("/reset", ``); code != http.StatusOK {
+		t.Fatalf("reset: %d", code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/status", nil))
+	var after SetupStatus
+	json.Unmarshal(rec.Body.Bytes(), &after)
+	if after.ProjectDone || after.CredsDone || after.EnvPath != store.Environment.FullEncryptedPath() {
+		t.Errorf("status after reset = %+v", after)
+	}
+}
+
+func TestRunCLI(t *testing.T) {
+	store := newTestStore(t)
+	var out bytes.Buffer
+	status, err := RunCLI(CLIOptions{
+		Store: store,
+		Out:   &out,
+		// Project ID: invalid, then valid; Enter after the APIs and the consent screen; client ID
+		ReadLine:   lines("Bad_ID", "wellknown-dev-123", "", "", "id.apps.googleusercontent.com"),
+		ReadSecret: lines("s3cret"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.ProjectDone || !status.CredsDone {
+		t.Errorf("status = %+v", status)
+	}
+	if !strings.Contains(out.String(), "invalid: project ID") || !strings.Contains(out.String(), CredentialsURL("wellknown-dev-123")) {
+		t.Errorf("output:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "s3cret") {
+		t.Error("secret echoed to output")
+	}
+
+	// Re-running keeps everything with Enter
+	out.Reset()
+	status, err = RunCLI(CLIOptions{Store: store, Out: &out, ReadLine: lines("", "", "", ""), ReadSecret: lines("")})
+	if err != nil || status.ProjectID != "wellknown-dev-123" || status.ClientSecret != "s3cret" {
+		t.Errorf("second run: %+v, %v", status, err)
+	}
+}
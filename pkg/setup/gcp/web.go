@@ -0,0 +1,116 @@
+package gcp
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ================================================================
+// Web Wizard API
+// ================================================================
+// The wizard page is rendered by pkg/server (templates/gcp_tool.html), which
+// mounts Handler at /api/gcp-setup behind its middleware and API guard:
+//
+//	GET  /status         {"project_id": ..., "client_secret_configured": true, ...}
+//	POST /save-project   {"project_id": "wellknown-dev-123"}
+//	POST /save-creds     {"client_id": ..., "client_secret": ...}
+//	POST /reset
+//
+// Every POST answers {"success": true, "status": SetupStatus}, or
+// {"success": false, "message": ...} with 400 (invalid input) or 500.
+// POSTs must be sent as Content-Type: application/json (else 415), which a
+// cross-site form can't do without a CORS preflight. The client secret is
+// never sent back.
+
+// maxBodySize caps request bodies
+const maxBodySize = 64 << 10
+
+// Handler serves the wizard API for store
+func Handler(store *Store) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		status, err := store.Status()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	})
+	mux.HandleFunc("POST /save-project", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ProjectID string `json:"project_id"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+		if err := ValidateProjectID(strings.TrimSpace(req.ProjectID)); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respond(w)(store.SaveProject(req.ProjectID))
+	})
+	mux.HandleFunc("POST /save-creds", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ClientID     string `json:"client_id"`
+			ClientSecret string `json:"client_secret"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+		if strings.TrimSpace(req.ClientID) == "" || strings.TrimSpace(req.ClientSecret) == "" {
+			writeError(w, http.StatusBadRequest, "client ID and client secret are required")
+			return
+		}
+		respond(w)(store.SaveCredentials(req.ClientID, req.ClientSecret))
+	})
+	mux.HandleFunc("POST /reset", func(w http.ResponseWriter, r *http.Request) {
+		respond(w)(store.Reset())
+	})
+	return requireJSON(mux)
+}
+
+// requireJSON answers 415 to requests other than GET and HEAD that are not
+// sent as JSON
+func requireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decode reads a JSON body into v, answering 400 when it cannot
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "request body must be a JSON object")
+		return false
+	}
+	return true
+}
+
+// respond answers a store update
+func respond(w http.ResponseWriter) func(SetupStatus, error) {
+	return func(status SetupStatus, err error) {
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": true, "status": status})
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]interface{}{"success": false, "message": message})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
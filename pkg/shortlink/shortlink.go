@@ -0,0 +1,217 @@
+// Package shortlink shortens generated deep links and counts their clicks.
+//
+// Calendar URLs and ICS download links are long; a short link
+// (/s/<code>) is easier to paste into a chat or print on a flyer, and its
+// click count shows whether anyone used it. The demo server keeps links in a
+// MemoryStore or FileStore; the PocketBase app uses a store backed by the
+// short_links collection (pkg/pb) behind the same Store interface.
+package shortlink
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CodeLength is the length of generated codes
+const CodeLength = 7
+
+// codeAlphabet is base62, so codes need no escaping in URLs
+const codeAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrLinkNotFound is returned by a Store for an unknown code
+var ErrLinkNotFound = errors.New("short link not found")
+
+// Link is a short link and its click count
+type Link struct {
+	Code      string     `json:"code"`
+	Target    string     `json:"target"`
+	Clicks    int64      `json:"clicks"`
+	CreatedAt time.Time  `json:"created_at"`
+	LastClick *time.Time `json:"last_click,omitempty"`
+}
+
+// Store persists short links
+type Store interface {
+	Shorten(target string) (*Link, error) // Existing link for target, or a new one
+	Resolve(code string) (*Link, error)   // ErrLinkNotFound for unknown codes
+	Click(code string) (*Link, error)     // Counts a visit and returns the link
+	List() ([]*Link, error)               // Most clicked first
+}
+
+// NewCode returns a random CodeLength base62 code
+func NewCode() string {
+	b := make([]byte, CodeLength)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := range b {
+		n, _ := rand.Int(rand.Reader, max)
+		b[i] = codeAlphabet[n.Int64()]
+	}
+	return string(b)
+}
+
+// SortLinks orders links most clicked first, then newest first
+func SortLinks(links []*Link) {
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Clicks != links[j].Clicks {
+			return links[i].Clicks > links[j].Clicks
+		}
+		return links[i].CreatedAt.After(links[j].CreatedAt)
+	})
+}
+
+// ================================================================
+// Stores
+// ================================================================
+
+// MemoryStore keeps links in memory (lost on restart)
+type MemoryStore struct {
+	mu       sync.Mutex
+	links    map[string]*Link  // By code
+	byTarget map[string]string // Target -> code
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{links: make(map[string]*Link), byTarget: make(map[string]string)}
+}
+
+// Shorten returns the link for target, creating it on first use
+func (s *MemoryStore) Shorten(target string) (*Link, error) {
+	if target == "" {
+		return nil, errors.New("nothing to shorten")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if code, ok := s.byTarget[target]; ok {
+		return copyLink(s.links[code]), nil
+	}
+
+	code := NewCode()
+	for s.links[code] != nil {
+		code = NewCode()
+	}
+	link := &Link{Code: code, Target: target, CreatedAt: time.Now().UTC()}
+	s.add(link)
+	return copyLink(link), nil
+}
+
+// Resolve returns the link for code
+func (s *MemoryStore) Resolve(code string) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[code]
+	if !ok {
+		return nil, ErrLinkNotFound
+	}
+	return copyLink(link), nil
+}
+
+// Click counts a visit to code
+func (s *MemoryStore) Click(code string) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[code]
+	if !ok {
+		return nil, ErrLinkNotFound
+	}
+	now := time.Now().UTC()
+	link.Clicks++
+	link.LastClick = &now
+	return copyLink(link), nil
+}
+
+// List returns every link, most clicked first
+func (s *MemoryStore) List() ([]*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links := make([]*Link, 0, len(s.links))
+	for _, link := range s.links {
+		links = append(links, copyLink(link))
+	}
+	SortLinks(links)
+	return links, nil
+}
+
+// add indexes link (caller holds mu)
+func (s *MemoryStore) add(link *Link) {
+	s.links[link.Code] = link
+	s.byTarget[link.Target] = link.Code
+}
+
+func copyLink(link *Link) *Link {
+	c := *link
+	return &c
+}
+
+// FileStore is a MemoryStore written to a JSON file after every change, so
+// links and counts survive restarts of the demo server
+type FileStore struct {
+	*MemoryStore
+	path   string
+	saveMu sync.Mutex // Serialises writes of the file
+}
+
+// NewFileStore loads the links in path (missing is fine: it is created on
+// the first change)
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{MemoryStore: NewMemoryStore(), path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read short links: %w", err)
+	}
+	var links []*Link
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, link := range links {
+		s.add(link)
+	}
+	return s, nil
+}
+
+// Shorten returns the link for target, creating and saving it on first use
+func (s *FileStore) Shorten(target string) (*Link, error) {
+	link, err := s.MemoryStore.Shorten(target)
+	if err != nil {
+		return nil, err
+	}
+	return link, s.save()
+}
+
+// Click counts a visit to code and saves the count
+func (s *FileStore) Click(code string) (*Link, error) {
+	link, err := s.MemoryStore.Click(code)
+	if err != nil {
+		return nil, err
+	}
+	return link, s.save()
+}
+
+// save writes every link to the file
+func (s *FileStore) save() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	links, _ := s.List()
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal short links: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create short links directory: %w", err)
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write short links: %w", err)
+	}
+	return os.Rename(s.path+".tmp", s.path)
+}
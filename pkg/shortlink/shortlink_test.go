@@ -0,0 +1,68 @@
+package shortlink
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+
+	link, err := s.Shorten("https://calendar.google.com/calendar/render?action=TEMPLATE")
+	if err != nil {
+		t.Fatalf("Shorten failed: %v", err)
+	}
+	if len(link.Code) != CodeLength {
+		t.Errorf("expected a %d character code, got %q", CodeLength, link.Code)
+	}
+	again, _ := s.Shorten(link.Target)
+	if again.Code != link.Code {
+		t.Errorf("shortening the same target should reuse %s, got %s", link.Code, again.Code)
+	}
+
+	other, _ := s.Shorten("/apple/calendar/download?event=abc")
+	for i := 0; i < 2; i++ {
+		if _, err := s.Click(other.Code); err != nil {
+			t.Fatalf("Click failed: %v", err)
+		}
+	}
+
+	links, _ := s.List()
+	if len(links) != 2 || links[0].Code != other.Code || links[0].Clicks != 2 || links[0].LastClick == nil {
+		t.Errorf("expected the clicked link first with 2 clicks, got %+v", links[0])
+	}
+	if _, err := s.Resolve("missing"); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected ErrLinkNotFound, got %v", err)
+	}
+	if _, err := s.Shorten(""); err == nil {
+		t.Error("expected an error for an empty target")
+	}
+}
+
+func TestFileStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "links", "links.json")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	link, _ := s.Shorten("https://example.com/event")
+	if _, err := s.Click(link.Code); err != nil {
+		t.Fatalf("Click failed: %v", err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	got, err := reopened.Resolve(link.Code)
+	if err != nil {
+		t.Fatalf("Resolve after reopen failed: %v", err)
+	}
+	if got.Target != link.Target || got.Clicks != 1 {
+		t.Errorf("expected the saved link with 1 click, got %+v", got)
+	}
+	if again, _ := reopened.Shorten(link.Target); again.Code != link.Code {
+		t.Error("reopened store should reuse the saved code")
+	}
+}
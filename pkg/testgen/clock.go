@@ -0,0 +1,66 @@
+package testgen
+
+import (
+	"fmt"
+	"time"
+)
+
+// ================================================================
+// Clock (time travel)
+// ================================================================
+// Everything time-dependent in a generated suite derives from one base time
+// read from GenerateOptions.Clock: the generated_at stamps, the ICS DTSTAMP
+// and UID, and the datetimes synthesized for schema fields (record i falls i
+// days after the base, same wall-clock time in the base's location). The
+// system clock makes events "now"-relative; a FixedClock makes the output
+// byte-for-byte stable and puts records across a DST change or month end:
+//
+//	opts.Clock = testgen.FixedClock(time.Date(2025, 3, 30, 1, 30, 0, 0, london))
+//
+// Hand-written examples (data-examples.json) keep their own datetimes.
+
+// Clock returns the base time of a generation run
+type Clock func() time.Time
+
+// SystemClock is the default Clock, the current time
+var SystemClock Clock = time.Now
+
+// FixedClock always returns t
+func FixedClock(t time.Time) Clock {
+	return func() time.Time { return t }
+}
+
+// ClockLayouts are the layouts ParseClock accepts; times without a zone are
+// in the local time zone
+var ClockLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+// ParseClock parses a base time (see ClockLayouts) into a FixedClock
+func ParseClock(value string) (Clock, error) {
+	for _, layout := range ClockLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return FixedClock(t), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time %q (want RFC 3339, 2006-01-02T15:04 or 2006-01-02)", value)
+}
+
+// now reads the clock, falling back to SystemClock
+func (c Clock) now() time.Time {
+	if c == nil {
+		return SystemClock()
+	}
+	return c()
+}
+
+// sampleTime formats base for a date or time format, ok false for any other
+func sampleTime(format string, base time.Time) (value string, ok bool) {
+	switch format {
+	case "date-time", "datetime-local":
+		return base.Format("2006-01-02T15:04"), true
+	case "date":
+		return base.Format("2006-01-02"), true
+	case "time":
+		return base.Format("15:04"), true
+	}
+	return "", false
+}
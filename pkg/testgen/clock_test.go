@@ -0,0 +1,92 @@
+package testgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFixedClockIsReproducible(t *testing.T) {
+	root := t.TempDir()
+	for rel, content := range map[string]string{
+		"pkg/apple/calendar/schema.json": `{"type": "object", "required": ["title", "start", "end"], "properties": {
+			"title": {"type": "string"},
+			"start": {"type": "string", "format": "datetime-local"},
+			"end":   {"type": "string", "format": "datetime-local"}}}`,
+		"pkg/apple/calendar/data-examples.json": `{"examples": [{"name": "ok", "data": {"title": "Hi", "start": "2025-11-15T14:00", "end": "2025-11-15T15:00"}}]}`,
+		"pkg/acme/shift.schema.json":            `{"type": "object", "properties": {"day": {"type": "string", "format": "date"}, "at": {"type": "string", "format": "datetime-local"}}}`,
+	} {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A month end: records cross into February
+	base := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	generate := func(out string) {
+		t.Helper()
+		if err := Generate(GenerateOptions{OutputDir: out, Root: root, Records: 2, Clock: FixedClock(base)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b := filepath.Join(root, "a"), filepath.Join(root, "b")
+	generate(a)
+	time.Sleep(1100 * time.Millisecond) // ICS stamps have second resolution
+	generate(b)
+
+	for _, rel := range []string{ManifestFilename, "apple/calendar/" + SuiteFilename, "acme/shift/" + SuiteFilename, "acme/shift/" + RecordsFilename} {
+		first, err := os.ReadFile(filepath.Join(a, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, _ := os.ReadFile(filepath.Join(b, filepath.FromSlash(rel)))
+		if !bytes.Equal(first, second) {
+			t.Errorf("%s differs between runs", rel)
+		}
+	}
+
+	data, _ := os.ReadFile(filepath.Join(a, "apple", "calendar", SuiteFilename))
+	var suite TestSuite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		t.Fatal(err)
+	}
+	if suite.GeneratedAt != "2025-01-31T09:00:00Z" || !strings.Contains(suite.TestCases[0].Expected.ICS, "DTSTAMP:20250131T090000Z") {
+		t.Errorf("suite not stamped with the clock: %s\n%s", suite.GeneratedAt, suite.TestCases[0].Expected.ICS)
+	}
+
+	data, _ = os.ReadFile(filepath.Join(a, "acme", "shift", RecordsFilename))
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0]["day"] != "2025-01-31" || records[1]["day"] != "2025-02-01" || records[1]["at"] != "2025-02-01T09:00" {
+		t.Errorf("records = %v, want one day apart from the base", records)
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	for value, want := range map[string]string{
+		"2025-03-30T01:30:00+01:00": "2025-03-30T01:30",
+		"2025-03-30T01:30":          "2025-03-30T01:30",
+		"2025-03-30":                "2025-03-30T00:00",
+	} {
+		clock, err := ParseClock(value)
+		if err != nil {
+			t.Fatalf("ParseClock(%q): %v", value, err)
+		}
+		if got := clock().Format("2006-01-02T15:04"); got != want {
+			t.Errorf("ParseClock(%q) = %s, want %s", value, got, want)
+		}
+	}
+	if _, err := ParseClock("tomorrow"); err == nil {
+		t.Error("ParseClock(tomorrow) should fail")
+	}
+}
@@ -0,0 +1,276 @@
+package testgen
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joeblew999/wellknown/pkg/schema"
+)
+
+// DefaultSchemaGlobs find the platform schemas (pkg/google/calendar/schema.json)
+// and any standalone *.schema.json in the module
+var DefaultSchemaGlobs = []string{"**/" + schema.SchemaFilename, "**/*.schema.json"}
+
+// ManifestFilename is the index of generated suites, written to the output dir
+const ManifestFilename = "index.json"
+
+// SuiteFilename is the name of each generated suite inside its namespace dir
+const SuiteFilename = "tests.json"
+
+// skipDirs are never searched for schemas
+var skipDirs = map[string]bool{"node_modules": true, "vendor": true, "testdata": true}
+
+// DiscoveredSchema is a schema file found by Discover
+type DiscoveredSchema struct {
+	Namespace    string // Output namespace, e.g. "google/calendar"
+	Platform     string // First namespace segment(s), e.g. "google"
+	AppType      string // Last namespace segment, e.g. "calendar"
+	Dir          string // Directory relative to the root (slash-separated)
+	SchemaPath   string // Path of the schema file (root-joined)
+	ExamplesPath string // Path of its examples file (may not exist)
+	UISchema     bool   // A uischema.json sits beside it (the web UI renders a form)
+}
+
+// Discover walks root for files matching any of globs (slash-separated,
+// relative to root, "**" matching any number of directories). Hidden
+// directories, node_modules, vendor, testdata and exclude are skipped.
+func Discover(root string, globs []string, exclude ...string) ([]DiscoveredSchema, error) {
+	if len(globs) == 0 {
+		globs = DefaultSchemaGlobs
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, dir := range exclude {
+		excluded[filepath.Clean(dir)] = true
+	}
+
+	var found []DiscoveredSchema
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			name := d.Name()
+			if rel != "." && (strings.HasPrefix(name, ".") || skipDirs[name] || excluded[filepath.Clean(p)]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, glob := range globs {
+			if matchGlob(glob, rel) {
+				found = append(found, describeSchema(root, rel))
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("schema discovery failed: %w", err)
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Namespace < found[j].Namespace })
+	for i := 1; i < len(found); i++ {
+		if found[i].Namespace == found[i-1].Namespace {
+			return nil, fmt.Errorf("schemas %s and %s share namespace %q", found[i-1].SchemaPath, found[i].SchemaPath, found[i].Namespace)
+		}
+	}
+	return found, nil
+}
+
+// describeSchema derives the namespace and companion files of a schema:
+// dir/schema.json pairs with dir/data-examples.json, dir/name.schema.json
+// with dir/name.examples.json. A leading "pkg/" is dropped from namespaces.
+func describeSchema(root, rel string) DiscoveredSchema {
+	dir, file := path.Split(rel)
+	dir = strings.TrimSuffix(dir, "/")
+
+	namespace := strings.TrimPrefix(dir, "pkg/")
+	if dir == "pkg" || dir == "" {
+		namespace = ""
+	}
+	examples := schema.ExamplesFilename
+	if file != schema.SchemaFilename {
+		name := strings.TrimSuffix(file, ".schema.json")
+		namespace = path.Join(namespace, name)
+		examples = name + ".examples.json"
+	}
+	if namespace == "" {
+		namespace = "root"
+	}
+
+	s := DiscoveredSchema{
+		Namespace:    namespace,
+		Platform:     namespace,
+		Dir:          dir,
+		SchemaPath:   filepath.Join(root, filepath.FromSlash(rel)),
+		ExamplesPath: filepath.Join(root, filepath.FromSlash(path.Join(dir, examples))),
+	}
+	if i := strings.LastIndex(namespace, "/"); i >= 0 {
+		s.Platform, s.AppType = namespace[:i], namespace[i+1:]
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(s.SchemaPath), schema.UISchemaFilename)); err == nil && file == schema.SchemaFilename {
+		s.UISchema = true
+	}
+	return s
+}
+
+// matchGlob reports whether a slash-separated path matches pattern, where
+// "**" matches zero or more path segments and other segments use path.Match
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ================================================================
+// Index Manifest
+// ================================================================
+
+// Manifest indexes the suites of one Generate run for the e2e suite
+type Manifest struct {
+	GeneratedAt string          `json:"generated_at"`
+	Globs       []string        `json:"globs"`
+	Suites      []ManifestEntry `json:"suites"`
+	SeedOrder   []string        `json:"seed_order,omitempty"` // Namespaces in x-ref dependency order (see GenerateRecords)
+}
+
+// ManifestEntry describes one generated suite
+type ManifestEntry struct {
+	Namespace   string `json:"namespace"`
+	Platform    string `json:"platform"`
+	AppType     string `json:"app_type"`
+	Schema      string `json:"schema"`
+	Suite       string `json:"suite"` // Relative to the manifest
+	Tests       int    `json:"tests"`
+	Valid       int    `json:"valid"`
+	Generator   bool   `json:"generator"`         // Expected outputs come from a Go generator
+	Synthesized bool   `json:"synthesized"`       // No examples file: examples were derived from the schema
+	UI          bool   `json:"ui"`                // Served as a form at /{platform}/{app_type}
+	Records     string `json:"records,omitempty"` // Generated records, relative to the manifest
+	Refs        XRefs  `json:"refs,omitempty"`    // x-ref properties and their targets
+}
+
+// saveManifest writes the manifest to dir
+func saveManifest(m *Manifest, dir string) error {
+	return saveJSON(m, filepath.Join(dir, ManifestFilename))
+}
+
+// ================================================================
+// Synthesized Examples
+// ================================================================
+
+// synthesizeExamples derives examples for a schema without an examples file:
+// "minimal" (required fields), "complete" (every field) and, when fields are
+// required, "empty" (which must fail validation). Datetimes are base.
+func synthesizeExamples(schemaPath string, base time.Time) (*ExamplesFile, error) {
+	raw, err := loadSchemaDef(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	minimal := map[string]interface{}{}
+	for _, field := range raw.Required {
+		minimal[field] = sampleValue(raw.Properties[field], base)
+	}
+	complete := map[string]interface{}{}
+	for field, def := range raw.Properties {
+		complete[field] = sampleValue(def, base)
+	}
+
+	examples := &ExamplesFile{Examples: []Example{
+		{Name: "minimal", Description: "Required fields only (synthesized from schema)", Data: minimal},
+		{Name: "complete", Description: "Every field (synthesized from schema)", Data: complete},
+	}}
+	if len(raw.Required) > 0 {
+		examples.Examples = append(examples.Examples, Example{
+			Name: "empty", Description: "No fields: must fail validation (synthesized from schema)", Data: map[string]interface{}{},
+		})
+	}
+	return examples, nil
+}
+
+// sampleValue picks a value for a property: its first example, default,
+// const, enum or oneOf const value, else one made up from its type and format
+// (dates and times from base)
+func sampleValue(def map[string]interface{}, base time.Time) interface{} {
+	if examples, ok := def["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	for _, key := range []string{"default", "const"} {
+		if v, ok := def[key]; ok {
+			return v
+		}
+	}
+	if enum, ok := def["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if oneOf, ok := def["oneOf"].([]interface{}); ok && len(oneOf) > 0 {
+		if alt, ok := oneOf[0].(map[string]interface{}); ok {
+			if v, ok := alt["const"]; ok {
+				return v
+			}
+		}
+	}
+
+	switch def["type"] {
+	case "integer", "number":
+		if min, ok := def["minimum"].(float64); ok {
+			return min
+		}
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	}
+
+	format, _ := def["format"].(string)
+	if value, ok := sampleTime(format, base); ok {
+		return value
+	}
+	switch format {
+	case "email":
+		return "test@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	}
+	value := "Example"
+	if min, ok := def["minLength"].(float64); ok && int(min) > len(value) {
+		value += strings.Repeat("x", int(min)-len(value))
+	}
+	if max, ok := def["maxLength"].(float64); ok && int(max) < len(value) {
+		value = value[:int(max)]
+	}
+	return value
+}
@@ -0,0 +1,77 @@
+package testgen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"**/schema.json", "schema.json", true},
+		{"**/schema.json", "pkg/google/calendar/schema.json", true},
+		{"**/*.schema.json", "pkg/maps/place.schema.json", true},
+		{"pkg/**/schema.json", "pkg/google/calendar/schema.json", true},
+		{"pkg/**/schema.json", "tests/schema.json", false},
+		{"pkg/*/schema.json", "pkg/google/calendar/schema.json", false},
+		{"**/schema.json", "pkg/google/calendar/uischema.json", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateDiscoversSchemas(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	eventSchema := `{"type": "object", "required": ["title"], "properties": {"title": {"type": "string", "minLength": 1}}}`
+	write("pkg/acme/event/schema.json", eventSchema)
+	write("pkg/acme/event/uischema.json", `{}`)
+	write("pkg/acme/event/data-examples.json", `{"examples": [{"name": "ok", "data": {"title": "Hi"}}, {"name": "blank", "data": {"title": ""}}]}`)
+	write("pkg/maps/place.schema.json", `{"type": "object", "required": ["query"], "properties": {"query": {"type": "string", "examples": ["Sydney Opera House"]}, "zoom": {"type": "integer", "minimum": 1}}}`)
+	write("node_modules/pkg/schema.json", eventSchema)
+
+	out := filepath.Join(root, "out")
+	if err := Generate(GenerateOptions{OutputDir: out, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, ManifestFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Suites) != 2 {
+		t.Fatalf("got %d suites, want 2 (node_modules skipped): %+v", len(manifest.Suites), manifest.Suites)
+	}
+
+	event, place := manifest.Suites[0], manifest.Suites[1]
+	if event.Namespace != "acme/event" || event.Platform != "acme" || event.AppType != "event" ||
+		!event.UI || event.Synthesized || event.Tests != 2 || event.Valid != 1 {
+		t.Errorf("event suite: %+v", event)
+	}
+	if place.Namespace != "maps/place" || place.UI || !place.Synthesized || place.Tests != 3 || place.Valid != 2 {
+		t.Errorf("place suite: %+v", place)
+	}
+	if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(place.Suite))); err != nil {
+		t.Errorf("suite file: %v", err)
+	}
+}
@@ -1,10 +1,11 @@
 // Package testgen provides robust schema-driven test data generation for calendar platforms.
 //
 // This package generates Go-verified test expectations for Playwright E2E tests by:
-// 1. Reading data-examples.json files
+// 0. Discovering schemas across the module (schema.json, *.schema.json; see Discover)
+// 1. Reading data-examples.json files (or synthesizing examples from the schema)
 // 2. Running ACTUAL Go generator functions (GenerateURL, GenerateICS, etc.)
 // 3. Capturing expected outputs
-// 4. Writing test suites with expected results
+// 4. Writing test suites with expected results (one dir per schema, indexed by index.json)
 //
 // Key features:
 // ✅ Zero code duplication (generic ProcessPlatform)
@@ -15,8 +16,10 @@ package testgen
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -69,26 +72,43 @@ type PlatformConfig struct {
 	Platform      string
 	AppType       string
 	BasePath      string
-	GeneratorFunc interface{}
+	GeneratorFunc interface{} // nil: validation-only suite
 	ProcessResult func(interface{}, map[string]interface{}) (ExpectedResult, error)
+
+	SchemaPath   string // Default: BasePath/schema.json
+	ExamplesPath string // Default: BasePath/data-examples.json; synthesized from the schema when missing
+	Clock        Clock  // Base time of generated_at and synthesized datetimes (default SystemClock)
 }
 
 // GenerateOptions configures test data generation
 type GenerateOptions struct {
-	OutputDir string
-	Verbose   bool
+	OutputDir   string
+	Verbose     bool
+	Root        string   // Module root searched for schemas (default ".")
+	SchemaGlobs []string // Schema file patterns relative to Root (default DefaultSchemaGlobs)
+	Records     int      // Related records generated per schema (0: none; see GenerateRecords)
+	Clock       Clock    // Base time of everything time-dependent (default SystemClock; see Clock)
 }
 
 // DefaultGenerateOptions returns default generation options
 func DefaultGenerateOptions() GenerateOptions {
 	return GenerateOptions{
-		OutputDir: "tests/e2e/generated",
-		Verbose:   false,
+		OutputDir:   "tests/e2e/generated",
+		Verbose:     false,
+		Root:        ".",
+		SchemaGlobs: DefaultSchemaGlobs,
+		Records:     3,
 	}
 }
 
-// Generate creates test data for all registered platforms
-func Generate(opts GenerateOptions) error {
+// generators are the platforms whose Go generators produce expected outputs,
+// keyed by directory; discovered schemas elsewhere get validation-only suites.
+// Generators that stamp the current time read it from clock.
+func generators(clock Clock) map[string]PlatformConfig {
+	generateICS := func(data map[string]interface{}) ([]byte, error) {
+		return applecal.GenerateICSAt(data, clock.now())
+	}
+
 	// REGISTRY: All platforms in one place (data-driven!)
 	registry := []PlatformConfig{
 		{
@@ -102,35 +122,127 @@ func Generate(opts GenerateOptions) error {
 			Platform:      "apple",
 			AppType:       "calendar",
 			BasePath:      "pkg/apple/calendar",
-			GeneratorFunc: applecal.GenerateICS,
+			GeneratorFunc: generateICS,
 			ProcessResult: processApple,
 		},
 		// Adding Maps? Just add one line here!
 	}
+	byPath := make(map[string]PlatformConfig, len(registry))
+	for _, config := range registry {
+		byPath[config.BasePath] = config
+	}
+	return byPath
+}
+
+// Generate discovers every schema under opts.Root, writes a suite per schema
+// to OutputDir/<namespace>/tests.json and indexes them in OutputDir/index.json
+func Generate(opts GenerateOptions) error {
+	if opts.Root == "" {
+		opts.Root = "."
+	}
+	if len(opts.SchemaGlobs) == 0 {
+		opts.SchemaGlobs = DefaultSchemaGlobs
+	}
+
+	schemas, err := Discover(opts.Root, opts.SchemaGlobs, opts.OutputDir)
+	if err != nil {
+		return err
+	}
+	if len(schemas) == 0 {
+		return fmt.Errorf("no schemas under %s match %v", opts.Root, opts.SchemaGlobs)
+	}
+
+	// One base time for the whole run, so suites and records agree
+	base := opts.Clock.now()
+	clock := FixedClock(base)
+
+	registry := generators(clock)
+	manifest := &Manifest{
+		GeneratedAt: base.Format(time.RFC3339),
+		Globs:       opts.SchemaGlobs,
+	}
 
 	// Process each using GENERIC function
-	for _, config := range registry {
+	for _, found := range schemas {
+		config := PlatformConfig{
+			Platform:     found.Platform,
+			AppType:      found.AppType,
+			BasePath:     filepath.Dir(found.SchemaPath),
+			SchemaPath:   found.SchemaPath,
+			ExamplesPath: found.ExamplesPath,
+			Clock:        clock,
+		}
+		if registered, ok := registry[found.Dir]; ok && filepath.Base(found.SchemaPath) == schema.SchemaFilename {
+			config.Platform, config.AppType = registered.Platform, registered.AppType
+			config.GeneratorFunc, config.ProcessResult = registered.GeneratorFunc, registered.ProcessResult
+		}
+
 		suite, err := ProcessPlatform(config)
 		if err != nil {
-			return fmt.Errorf("%s/%s: %w", config.Platform, config.AppType, err)
+			return fmt.Errorf("%s: %w", found.Namespace, err)
 		}
 
-		outPath := filepath.Join(opts.OutputDir, fmt.Sprintf("%s-%s-tests.json", config.Platform, config.AppType))
+		rel := path.Join(found.Namespace, SuiteFilename)
+		outPath := filepath.Join(opts.OutputDir, filepath.FromSlash(rel))
 		if err := saveSuite(suite, outPath); err != nil {
 			return fmt.Errorf("save failed: %w", err)
 		}
 
+		synthesized, _ := suite.Metadata["synthesized"].(bool)
+		manifest.Suites = append(manifest.Suites, ManifestEntry{
+			Namespace:   found.Namespace,
+			Platform:    suite.Platform,
+			AppType:     suite.AppType,
+			Schema:      path.Join(found.Dir, filepath.Base(found.SchemaPath)),
+			Suite:       rel,
+			Tests:       len(suite.TestCases),
+			Valid:       countValid(suite.TestCases),
+			Generator:   config.GeneratorFunc != nil,
+			Synthesized: synthesized,
+			UI:          found.UISchema,
+		})
+
 		if opts.Verbose {
 			fmt.Printf("✅ %s: %d tests (%d valid) → %s\n",
-				strings.Title(config.Platform),
+				found.Namespace,
 				len(suite.TestCases),
 				countValid(suite.TestCases),
 				outPath)
 			fmt.Printf("   Schema: %s\n", suite.SchemaMetadata.Title)
 			fmt.Printf("   Required: %v\n", suite.SchemaMetadata.RequiredFields)
+			if config.GeneratorFunc == nil {
+				fmt.Printf("   No generator registered: validation only\n")
+			}
+		}
+	}
+
+	if opts.Records > 0 {
+		dataset, err := GenerateRecords(schemas, opts.Records, base)
+		if err != nil {
+			return err
+		}
+		manifest.SeedOrder = dataset.Order
+		for i := range manifest.Suites {
+			entry := &manifest.Suites[i]
+			entry.Records = path.Join(entry.Namespace, RecordsFilename)
+			if len(dataset.Refs[entry.Namespace]) > 0 {
+				entry.Refs = dataset.Refs[entry.Namespace]
+			}
+			if err := saveJSON(dataset.Records[entry.Namespace], filepath.Join(opts.OutputDir, filepath.FromSlash(entry.Records))); err != nil {
+				return fmt.Errorf("save failed: %w", err)
+			}
+		}
+		if opts.Verbose {
+			fmt.Printf("🔗 %d records per schema, seed order: %s\n", opts.Records, strings.Join(dataset.Order, " → "))
 		}
 	}
 
+	if err := saveManifest(manifest, opts.OutputDir); err != nil {
+		return fmt.Errorf("save manifest failed: %w", err)
+	}
+	if opts.Verbose {
+		fmt.Printf("📇 %d suites → %s\n", len(manifest.Suites), filepath.Join(opts.OutputDir, ManifestFilename))
+	}
 	return nil
 }
 
@@ -138,14 +250,28 @@ func Generate(opts GenerateOptions) error {
 // This eliminates 100+ lines of duplication
 func ProcessPlatform(config PlatformConfig) (*TestSuite, error) {
 	// Load examples (using shared constant from pkg/schema)
-	examplesPath := filepath.Join(config.BasePath, schema.ExamplesFilename)
+	examplesPath := config.ExamplesPath
+	if examplesPath == "" {
+		examplesPath = filepath.Join(config.BasePath, schema.ExamplesFilename)
+	}
+	schemaPath := config.SchemaPath
+	if schemaPath == "" {
+		schemaPath = filepath.Join(config.BasePath, schema.SchemaFilename)
+	}
+	base := config.Clock.now()
+
 	examples, err := loadExamples(examplesPath)
+	synthesized := false
+	if errors.Is(err, os.ErrNotExist) {
+		// No hand-written examples: derive some from the schema
+		examples, err = synthesizeExamples(schemaPath, base)
+		synthesized = true
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Schema metadata (using shared constant from pkg/schema)
-	schemaPath := filepath.Join(config.BasePath, schema.SchemaFilename)
+	// Schema metadata
 	schemaMeta, err := schema.ExtractMetadata(schemaPath)
 	if err != nil {
 		return nil, err
@@ -161,14 +287,21 @@ func ProcessPlatform(config PlatformConfig) (*TestSuite, error) {
 	suite := &TestSuite{
 		Platform:       config.Platform,
 		AppType:        config.AppType,
-		GeneratedAt:    time.Now().Format(time.RFC3339),
+		GeneratedAt:    base.Format(time.RFC3339),
 		SourceFile:     examplesPath,
 		SchemaMetadata: schemaMeta,
 		Metadata: map[string]interface{}{
-			"generator_signature": reflect.TypeOf(config.GeneratorFunc).String(),
-			"total_examples":      len(examples.Examples),
+			"schema_file":    schemaPath,
+			"total_examples": len(examples.Examples),
+			"synthesized":    synthesized,
 		},
 	}
+	if synthesized {
+		suite.SourceFile = schemaPath
+	}
+	if config.GeneratorFunc != nil {
+		suite.Metadata["generator_signature"] = reflect.TypeOf(config.GeneratorFunc).String()
+	}
 
 	// Process examples
 	for _, ex := range examples.Examples {
@@ -183,15 +316,17 @@ func ProcessPlatform(config PlatformConfig) (*TestSuite, error) {
 		tc.Validation = validator.ValidateWithDetails(ex.Data, compiled, schemaMeta.RequiredFields)
 
 		// Generate using reflection
-		result, err := callGenerator(config.GeneratorFunc, ex.Data)
-		if err != nil {
-			tc.Expected.Error = err.Error()
-		} else {
-			expected, err := config.ProcessResult(result, ex.Data)
+		if config.GeneratorFunc != nil {
+			result, err := callGenerator(config.GeneratorFunc, ex.Data)
 			if err != nil {
 				tc.Expected.Error = err.Error()
 			} else {
-				tc.Expected = expected
+				expected, err := config.ProcessResult(result, ex.Data)
+				if err != nil {
+					tc.Expected.Error = err.Error()
+				} else {
+					tc.Expected = expected
+				}
 			}
 		}
 
@@ -305,7 +440,11 @@ func countValid(testCases []TestCase) int {
 }
 
 func saveSuite(suite *TestSuite, path string) error {
-	data, err := json.MarshalIndent(suite, "", "  ")
+	return saveJSON(suite, path)
+}
+
+func saveJSON(v interface{}, path string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,252 @@
+package testgen
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ================================================================
+// Relational Records (x-ref)
+// ================================================================
+// Schemas are generated as isolated documents unless a property carries an
+// x-ref hint naming another discovered schema. GenerateRecords then generates
+// the referenced schema first and fills the property from its records, so a
+// dataset such as event → organizer user → tenant seeds PocketBase relations
+// with IDs that line up:
+//
+//	"tenant":      {"type": "string", "x-ref": "tenant"}       // a tenant record's id
+//	"organizer":   {"type": "string", "x-ref": "acme/user"}    // by full namespace
+//	"owner_email": {"type": "string", "x-ref": "user.email"}   // the user's email
+//	"attendees":   {"type": "array",  "x-ref": "user"}         // multi-relation: [id]
+//
+// Targets are namespaces (see Discover) or their last segment when unique.
+// Record IDs are deterministic PocketBase-style IDs, stable across runs.
+
+// XRefKeyword is the schema property keyword naming a referenced schema
+const XRefKeyword = "x-ref"
+
+// RecordsFilename is the name of each schema's generated records
+const RecordsFilename = "records.json"
+
+// idAlphabet and idLength match PocketBase's default record IDs
+const (
+	idAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	idLength   = 15
+)
+
+// Record is one generated record, with an "id"
+type Record map[string]interface{}
+
+// Dataset is a set of related generated records
+type Dataset struct {
+	Order   []string            `json:"order"`   // Namespaces, every schema after those it references (seed in this order)
+	Records map[string][]Record `json:"records"` // By namespace
+	Refs    map[string]XRefs    `json:"refs"`    // By namespace
+}
+
+// XRefs maps a property to the "namespace.field" it references
+type XRefs map[string]string
+
+// schemaDef is the part of a JSON Schema that records are generated from
+type schemaDef struct {
+	Required   []string                          `json:"required"`
+	Properties map[string]map[string]interface{} `json:"properties"`
+}
+
+// loadSchemaDef reads a schema's top-level properties
+func loadSchemaDef(schemaPath string) (*schemaDef, error) {
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	var def schemaDef
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return &def, nil
+}
+
+// RecordID returns the deterministic ID of the n-th record of a namespace
+func RecordID(namespace string, n int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", namespace, n)))
+	id := make([]byte, idLength)
+	for i := range id {
+		id[i] = idAlphabet[int(sum[i])%len(idAlphabet)]
+	}
+	return string(id)
+}
+
+// GenerateRecords generates count records per schema, resolving x-ref
+// properties to records of the referenced schemas. Record i's dates and times
+// are i days after base (see Clock).
+func GenerateRecords(schemas []DiscoveredSchema, count int, base time.Time) (*Dataset, error) {
+	defs := make(map[string]*schemaDef, len(schemas))
+	var namespaces []string
+	for _, s := range schemas {
+		def, err := loadSchemaDef(s.SchemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.Namespace, err)
+		}
+		defs[s.Namespace] = def
+		namespaces = append(namespaces, s.Namespace)
+	}
+	sort.Strings(namespaces)
+
+	dataset := &Dataset{
+		Records: make(map[string][]Record, len(schemas)),
+		Refs:    make(map[string]XRefs, len(schemas)),
+	}
+	for _, ns := range namespaces {
+		refs := XRefs{}
+		for field, prop := range defs[ns].Properties {
+			hint, ok := prop[XRefKeyword].(string)
+			if !ok {
+				continue
+			}
+			target, targetField, _ := strings.Cut(hint, ".")
+			resolved, err := resolveNamespace(target, namespaces)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", ns, field, err)
+			}
+			if targetField == "" {
+				targetField = "id"
+			} else if _, ok := defs[resolved].Properties[targetField]; !ok {
+				return nil, fmt.Errorf("%s.%s: %s has no property %q", ns, field, resolved, targetField)
+			}
+			refs[field] = resolved + "." + targetField
+		}
+		dataset.Refs[ns] = refs
+	}
+
+	order, err := seedOrder(namespaces, dataset.Refs)
+	if err != nil {
+		return nil, err
+	}
+	dataset.Order = order
+
+	for _, ns := range order {
+		records := make([]Record, 0, count)
+		for i := 0; i < count; i++ {
+			record := Record{"id": RecordID(ns, i)}
+			// Plain fields first, so self-references can use them
+			for field, prop := range defs[ns].Properties {
+				if _, isRef := dataset.Refs[ns][field]; !isRef && field != "id" {
+					record[field] = recordValue(prop, i, base)
+				}
+			}
+			for field, ref := range dataset.Refs[ns] {
+				target, targetField, _ := strings.Cut(ref, ".")
+				var value interface{}
+				switch {
+				case target != ns:
+					targets := dataset.Records[target]
+					value = targets[i%len(targets)][targetField]
+				case i == 0:
+					value = record[targetField] // Self-reference: the first record refers to itself
+				default:
+					value = records[i-1][targetField] // ...the others to the previous one
+				}
+				if defs[ns].Properties[field]["type"] == "array" {
+					value = []interface{}{value}
+				}
+				record[field] = value
+			}
+			records = append(records, record)
+		}
+		dataset.Records[ns] = records
+	}
+	return dataset, nil
+}
+
+// resolveNamespace resolves an x-ref target to a discovered namespace
+func resolveNamespace(target string, namespaces []string) (string, error) {
+	var matches []string
+	for _, ns := range namespaces {
+		if ns == target {
+			return ns, nil
+		}
+		if strings.HasSuffix(ns, "/"+target) {
+			matches = append(matches, ns)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", fmt.Errorf("x-ref %q matches no schema", target)
+	default:
+		return "", fmt.Errorf("x-ref %q is ambiguous (%s)", target, strings.Join(matches, ", "))
+	}
+}
+
+// seedOrder sorts namespaces so every schema follows those it references
+func seedOrder(namespaces []string, refs map[string]XRefs) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(namespaces))
+	var order []string
+	var visit func(ns string, path []string) error
+	visit = func(ns string, path []string) error {
+		switch state[ns] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("x-ref cycle: %s → %s", strings.Join(path, " → "), ns)
+		}
+		state[ns] = visiting
+		var deps []string
+		for _, ref := range refs[ns] {
+			if target, _, _ := strings.Cut(ref, "."); target != ns {
+				deps = append(deps, target)
+			}
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(dep, append(path, ns)); err != nil {
+				return err
+			}
+		}
+		state[ns] = done
+		order = append(order, ns)
+		return nil
+	}
+	for _, ns := range namespaces {
+		if err := visit(ns, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// recordValue is sampleValue varied per record, so records differ where the
+// schema allows it (examples cycle; emails and free text are numbered; dates
+// and times advance a day per record)
+func recordValue(prop map[string]interface{}, i int, base time.Time) interface{} {
+	if examples, ok := prop["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[i%len(examples)]
+	}
+	value := sampleValue(prop, base.AddDate(0, 0, i))
+	s, ok := value.(string)
+	if !ok || i == 0 || prop["default"] != nil || prop["const"] != nil || prop["enum"] != nil || prop["oneOf"] != nil {
+		return value
+	}
+	switch prop["format"] {
+	case "email":
+		return fmt.Sprintf("test%d@example.com", i+1)
+	case nil, "":
+		numbered := fmt.Sprintf("%s %d", s, i+1)
+		if max, ok := prop["maxLength"].(float64); ok && len(numbered) > int(max) {
+			return value
+		}
+		return numbered
+	}
+	return value
+}
@@ -0,0 +1,102 @@
+package testgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeSchemas(t *testing.T, schemas map[string]string) []DiscoveredSchema {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range schemas {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	found, err := Discover(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return found
+}
+
+func TestGenerateRecordsResolvesXRefs(t *testing.T) {
+	schemas := writeSchemas(t, map[string]string{
+		"pkg/acme/tenant.schema.json": `{"properties": {"slug": {"type": "string", "examples": ["acme", "globex"]}}}`,
+		"pkg/acme/user.schema.json": `{"properties": {
+			"email":   {"type": "string", "format": "email"},
+			"tenant":  {"type": "string", "x-ref": "tenant"},
+			"manager": {"type": "string", "x-ref": "user"}}}`,
+		"pkg/acme/event.schema.json": `{"properties": {
+			"title":           {"type": "string"},
+			"organizer":       {"type": "string", "x-ref": "acme/user"},
+			"organizer_email": {"type": "string", "x-ref": "user.email"},
+			"attendees":       {"type": "array", "x-ref": "user"}}}`,
+	})
+
+	dataset, err := GenerateRecords(schemas, 3, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(dataset.Order, ","); got != "acme/tenant,acme/user,acme/event" {
+		t.Errorf("order = %s", got)
+	}
+
+	tenants, users, events := dataset.Records["acme/tenant"], dataset.Records["acme/user"], dataset.Records["acme/event"]
+	if len(tenants) != 3 || len(users) != 3 || len(events) != 3 {
+		t.Fatalf("record counts: %d tenants, %d users, %d events", len(tenants), len(users), len(events))
+	}
+	ids := map[interface{}]bool{}
+	for _, tenant := range tenants {
+		ids[tenant["id"]] = true
+	}
+	for i, user := range users {
+		if !ids[user["tenant"]] {
+			t.Errorf("user %d: tenant %v is not a generated tenant", i, user["tenant"])
+		}
+	}
+	if users[0]["manager"] != users[0]["id"] || users[2]["manager"] != users[1]["id"] {
+		t.Errorf("self-references: %v", users)
+	}
+	for i, event := range events {
+		organizer := users[i]
+		if event["organizer"] != organizer["id"] || event["organizer_email"] != organizer["email"] {
+			t.Errorf("event %d does not line up with user %d: %v / %v", i, i, event, organizer)
+		}
+		if attendees, ok := event["attendees"].([]interface{}); !ok || len(attendees) != 1 || attendees[0] != organizer["id"] {
+			t.Errorf("event %d attendees = %v", i, event["attendees"])
+		}
+	}
+
+	if id := RecordID("acme/user", 0); len(id) != idLength || id != users[0]["id"] || id == RecordID("acme/user", 1) {
+		t.Errorf("record IDs must be stable, distinct and %d chars: %s", idLength, id)
+	}
+}
+
+func TestGenerateRecordsErrors(t *testing.T) {
+	tests := map[string]map[string]string{
+		"cycle": {
+			"pkg/a.schema.json": `{"properties": {"b": {"type": "string", "x-ref": "b"}}}`,
+			"pkg/b.schema.json": `{"properties": {"a": {"type": "string", "x-ref": "a"}}}`,
+		},
+		"unknown target": {
+			"pkg/a.schema.json": `{"properties": {"b": {"type": "string", "x-ref": "missing"}}}`,
+		},
+		"unknown field": {
+			"pkg/a.schema.json": `{"properties": {"b": {"type": "string", "x-ref": "b.nope"}}}`,
+			"pkg/b.schema.json": `{"properties": {"name": {"type": "string"}}}`,
+		},
+	}
+	for name, files := range tests {
+		if _, err := GenerateRecords(writeSchemas(t, files), 2, time.Now()); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
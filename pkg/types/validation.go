@@ -0,0 +1,251 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"strings"
+	"time"
+
+	cal "github.com/joeblew999/wellknown/pkg/calendar"
+)
+
+// ================================================================
+// Payload Validation
+// ================================================================
+// JSON Schema checks shapes; these Validate methods check what the schema
+// cannot express (end after start, coordinate ranges) plus per-platform
+// limits, so the demo server and the pb API reject the same input before
+// any URL is generated.
+
+// FieldError is one invalid field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError collects every FieldError found in a payload
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (v *ValidationError) Error() string {
+	msgs := make([]string, len(v.Errors))
+	for i, e := range v.Errors {
+		msgs[i] = e.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Add records an error for field
+func (v *ValidationError) Add(field, format string, args ...interface{}) {
+	v.Errors = append(v.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns v as an error, or nil if nothing was added
+func (v *ValidationError) Err() error {
+	if len(v.Errors) == 0 {
+		return nil
+	}
+	return v
+}
+
+// Fields returns the errors keyed by field (first message wins), matching schema.ValidationErrors
+func (v *ValidationError) Fields() map[string]string {
+	fields := make(map[string]string, len(v.Errors))
+	for _, e := range v.Errors {
+		if _, exists := fields[e.Field]; !exists {
+			fields[e.Field] = e.Message
+		}
+	}
+	return fields
+}
+
+// ================================================================
+// Calendar Events
+// ================================================================
+
+// CalendarLimits are the maximum lengths a platform accepts (0 = unlimited)
+type CalendarLimits struct {
+	Title       int
+	Location    int
+	Description int
+	Attendees   int
+}
+
+// CalendarLimitsByPlatform holds the limits per platform. Google's keep the
+// TEMPLATE URL within browser limits; Apple's ICS has no hard limits, so
+// these only guard against abuse.
+var CalendarLimitsByPlatform = map[string]CalendarLimits{
+	"google": {Title: 200, Location: 200, Description: 1000, Attendees: 20},
+	"apple":  {Title: 255, Location: 255, Description: 5000, Attendees: 50},
+}
+
+// CalendarEvent is the typed form of calendar form data
+type CalendarEvent struct {
+	Title       string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Location    string
+	Description string
+	Attendees   []string // Email addresses
+}
+
+// ParseCalendarEvent reads calendar form data (datetime-local or RFC 3339 times).
+// Fields that cannot be parsed are reported in the returned ValidationError.
+func ParseCalendarEvent(data map[string]interface{}) (CalendarEvent, *ValidationError) {
+	verr := &ValidationError{}
+	event := CalendarEvent{}
+	event.Title, _ = data[cal.FieldTitle].(string)
+	event.Location, _ = data[cal.FieldLocation].(string)
+	event.Description, _ = data[cal.FieldDescription].(string)
+	event.AllDay, _ = data[cal.FieldAllDay].(bool)
+	event.Start = parseEventTime(data, cal.FieldStart, verr)
+	event.End = parseEventTime(data, cal.FieldEnd, verr)
+
+	// Attendees are email strings (Google) or objects with an email (Apple)
+	items, _ := data[cal.FieldAttendees].([]interface{})
+	for _, item := range items {
+		switch a := item.(type) {
+		case string:
+			event.Attendees = append(event.Attendees, a)
+		case map[string]interface{}:
+			email, _ := a["email"].(string)
+			event.Attendees = append(event.Attendees, email)
+		}
+	}
+	return event, verr
+}
+
+// parseEventTime parses one time field, recording missing or malformed values
+func parseEventTime(data map[string]interface{}, field string, verr *ValidationError) time.Time {
+	s, _ := data[field].(string)
+	if s == "" {
+		return time.Time{} // reported as missing by Validate
+	}
+	for _, layout := range []string{cal.DateTimeLocalFormat, time.RFC3339, cal.DateOnlyFormat} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	verr.Add(field, "invalid time %q (expected %s)", s, cal.DateTimeLocalFormat)
+	return time.Time{}
+}
+
+// Validate checks required fields, time ordering, attendee emails and the
+// platform's length limits (unknown platforms skip the limits)
+func (e CalendarEvent) Validate(platform string) error {
+	verr := &ValidationError{}
+	e.validate(platform, verr)
+	return verr.Err()
+}
+
+func (e CalendarEvent) validate(platform string, verr *ValidationError) {
+	if strings.TrimSpace(e.Title) == "" {
+		verr.Add(cal.FieldTitle, "is required")
+	}
+	if e.Start.IsZero() && !hasField(verr, cal.FieldStart) {
+		verr.Add(cal.FieldStart, "is required")
+	}
+	if e.End.IsZero() && !hasField(verr, cal.FieldEnd) {
+		verr.Add(cal.FieldEnd, "is required")
+	}
+	if !e.Start.IsZero() && !e.End.IsZero() && e.End.Before(e.Start) {
+		verr.Add(cal.FieldEnd, "End time must be after start time")
+	}
+
+	for i, email := range e.Attendees {
+		if _, err := mail.ParseAddress(email); err != nil || strings.ContainsAny(email, "<> ") {
+			verr.Add(fmt.Sprintf("%s/%d", cal.FieldAttendees, i), "invalid email address %q", email)
+		}
+	}
+
+	limits, ok := CalendarLimitsByPlatform[platform]
+	if !ok {
+		return
+	}
+	checkLength(verr, cal.FieldTitle, e.Title, limits.Title)
+	checkLength(verr, cal.FieldLocation, e.Location, limits.Location)
+	checkLength(verr, cal.FieldDescription, e.Description, limits.Description)
+	if limits.Attendees > 0 && len(e.Attendees) > limits.Attendees {
+		verr.Add(cal.FieldAttendees, "at most %d attendees allowed on %s", limits.Attendees, platform)
+	}
+}
+
+// ValidateCalendarData parses and validates calendar form data in one step
+func ValidateCalendarData(platform string, data map[string]interface{}) error {
+	event, verr := ParseCalendarEvent(data)
+	event.validate(platform, verr)
+	return verr.Err()
+}
+
+// ================================================================
+// Map Queries
+// ================================================================
+
+// MapQuery is a place search or coordinate for map deep links
+type MapQuery struct {
+	Query     string   // Free-text search (address or place name)
+	Latitude  *float64 // Optional coordinate (requires Longitude)
+	Longitude *float64
+	Zoom      int // Optional zoom level (0 = platform default)
+}
+
+// MapQueryMaxLength is the longest search text accepted by map deep links
+const MapQueryMaxLength = 500
+
+// Validate checks that a query or coordinate is given and coordinates are in range
+func (q MapQuery) Validate() error {
+	verr := &ValidationError{}
+	hasCoords := q.Latitude != nil || q.Longitude != nil
+
+	if strings.TrimSpace(q.Query) == "" && !hasCoords {
+		verr.Add("query", "a search query or coordinates are required")
+	}
+	checkLength(verr, "query", q.Query, MapQueryMaxLength)
+
+	if hasCoords {
+		switch {
+		case q.Latitude == nil:
+			verr.Add("latitude", "is required with longitude")
+		case math.IsNaN(*q.Latitude) || *q.Latitude < -90 || *q.Latitude > 90:
+			verr.Add("latitude", "must be between -90 and 90")
+		}
+		switch {
+		case q.Longitude == nil:
+			verr.Add("longitude", "is required with latitude")
+		case math.IsNaN(*q.Longitude) || *q.Longitude < -180 || *q.Longitude > 180:
+			verr.Add("longitude", "must be between -180 and 180")
+		}
+	}
+	if q.Zoom < 0 || q.Zoom > 21 {
+		verr.Add("zoom", "must be between 0 and 21")
+	}
+	return verr.Err()
+}
+
+// ================================================================
+// Helpers
+// ================================================================
+
+// checkLength records an error if value is longer than max runes (max 0 = unlimited)
+func checkLength(verr *ValidationError, field, value string, max int) {
+	if max > 0 && len([]rune(value)) > max {
+		verr.Add(field, "must be at most %d characters", max)
+	}
+}
+
+// hasField reports whether field already has an error
+func hasField(verr *ValidationError, field string) bool {
+	for _, e := range verr.Errors {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+// Package webpush sends Web Push notifications (RFC 8030) with the standard
+// library only: payloads are encrypted as aes128gcm (RFC 8291) and requests
+// are signed with VAPID (RFC 8292).
+//
+// Usage:
+//
+//	public, private, _ := webpush.GenerateVAPIDKeys() // Once; keep private secret
+//	vapid := webpush.VAPID{PublicKey: public, PrivateKey: private, Subject: "mailto:ops@example.com"}
+//
+//	// sub is the browser's PushSubscription.toJSON()
+//	err := webpush.Send(ctx, http.DefaultClient, sub, []byte(`{"title":"Standup in 10 min"}`), vapid, time.Hour)
+//	if errors.Is(err, webpush.ErrSubscriptionGone) {
+//		// Forget the subscription
+//	}
+//
+// The browser's service worker needs the public key as applicationServerKey.
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	recordSize = 4096 // Single aes128gcm record, the most push services accept
+
+	// MaxPayload is the largest payload Send accepts
+	// (record size minus header, padding delimiter and GCM tag)
+	MaxPayload = recordSize - (16 + 4 + 1 + 65) - 1 - 16
+
+	vapidExpiry = 12 * time.Hour // RFC 8292 allows at most 24h
+)
+
+var (
+	// ErrSubscriptionGone is returned when the push service reports the
+	// subscription expired or was unsubscribed (404/410); it should be deleted
+	ErrSubscriptionGone = errors.New("push subscription no longer valid")
+
+	// ErrPayloadTooLarge is returned for payloads over MaxPayload
+	ErrPayloadTooLarge = errors.New("push payload too large")
+)
+
+// Subscription is a browser push subscription (PushSubscription.toJSON())
+type Subscription struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"` // Client public key, base64url
+		Auth   string `json:"auth"`   // Client auth secret, base64url
+	} `json:"keys"`
+}
+
+// VAPID identifies the application server to push services
+type VAPID struct {
+	PublicKey  string // Uncompressed P-256 point, base64url
+	PrivateKey string // P-256 scalar, base64url
+	Subject    string // mailto: or https: contact
+}
+
+// GenerateVAPIDKeys returns a new VAPID key pair, base64url encoded
+func GenerateVAPIDKeys() (public, private string, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	pub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	priv, err := key.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	return encode(pub), encode(priv), nil
+}
+
+// Encrypt encrypts payload for sub as a single aes128gcm record (RFC 8291)
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+	uaPublic, err := decode(sub.Keys.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	authSecret, err := decode(sub.Keys.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth secret: %w", err)
+	}
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+
+	asKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	asPublic := asKey.PublicKey().Bytes()
+	cek, nonce, err := deriveKeys(asKey, uaKey, authSecret, salt, uaPublic, asPublic)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header: salt | record size | key id length | key id (our public key)
+	body := make([]byte, 0, 16+4+1+len(asPublic)+len(payload)+1+gcm.Overhead())
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, recordSize)
+	body = append(body, byte(len(asPublic)))
+	body = append(body, asPublic...)
+
+	// 0x02 marks the last (only) record
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
+
+// deriveKeys derives the content encryption key and nonce (RFC 8291 section 3.4)
+func deriveKeys(priv *ecdh.PrivateKey, peer *ecdh.PublicKey, authSecret, salt, uaPublic, asPublic []byte) (cek, nonce []byte, err error) {
+	shared, err := priv.ECDH(peer)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	prkKey, err := hkdf.Extract(sha256.New, shared, authSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cek, err = hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16); err != nil {
+		return nil, nil, err
+	}
+	if nonce, err = hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12); err != nil {
+		return nil, nil, err
+	}
+	return cek, nonce, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Send delivers payload to sub. ttl is how long the push service keeps the
+// message while the browser is offline.
+func Send(ctx context.Context, client *http.Client, sub Subscription, payload []byte, vapid VAPID, ttl time.Duration) error {
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+	auth, err := vapid.authorization(sub.Endpoint, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Authorization", auth)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push service returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// authorization returns the VAPID Authorization header for endpoint
+func (v VAPID) authorization(endpoint string, now time.Time) (string, error) {
+	priv, err := decode(v.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), priv)
+	if err != nil {
+		return "", fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint %q", endpoint)
+	}
+
+	header, _ := json.Marshal(map[string]string{"typ": "JWT", "alg": "ES256"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidExpiry).Unix(),
+		"sub": v.Subject,
+	})
+	signingInput := encode(header) + "." + encode(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// JWS ES256 signatures are r | s, each 32 bytes
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	return "vapid t=" + signingInput + "." + encode(sig) + ", k=" + v.PublicKey, nil
+}
+
+// encode is unpadded base64url, as used by the Push API
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decode accepts base64url with or without padding
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,167 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newBrowser returns a subscription for endpoint and the browser's private key
+func newBrowser(t *testing.T, endpoint string) (Subscription, *ecdh.PrivateKey, []byte) {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+	var sub Subscription
+	sub.Endpoint = endpoint
+	sub.Keys.P256dh = encode(key.PublicKey().Bytes())
+	sub.Keys.Auth = encode(auth)
+	return sub, key, auth
+}
+
+// decrypt is the browser side of RFC 8291
+func decrypt(t *testing.T, key *ecdh.PrivateKey, auth, body []byte) []byte {
+	t.Helper()
+	salt := body[:16]
+	if rs := binary.BigEndian.Uint32(body[16:20]); rs != recordSize {
+		t.Fatalf("record size %d", rs)
+	}
+	idLen := int(body[20])
+	asPublic := body[21 : 21+idLen]
+	peer, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cek, nonce, err := deriveKeys(key, peer, auth, salt, key.PublicKey().Bytes(), asPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := newGCM(cek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if plaintext[len(plaintext)-1] != 0x02 {
+		t.Fatal("missing last-record delimiter")
+	}
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	sub, key, auth := newBrowser(t, "https://push.example.com/x")
+	payload := []byte(`{"title":"Standup in 10 min"}`)
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decrypt(t, key, auth, body); !bytes.Equal(got, payload) {
+		t.Errorf("got %q, want %q", got, payload)
+	}
+
+	if _, err := Encrypt(sub, make([]byte, MaxPayload+1)); err != ErrPayloadTooLarge {
+		t.Errorf("oversized payload: got %v", err)
+	}
+}
+
+func TestVAPIDAuthorization(t *testing.T) {
+	public, private, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vapid := VAPID{PublicKey: public, PrivateKey: private, Subject: "mailto:ops@example.com"}
+	now := time.Unix(1700000000, 0)
+	header, err := vapid.authorization("https://push.example.com/send/abc", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, k, ok := strings.Cut(strings.TrimPrefix(header, "vapid t="), ", k=")
+	if !ok || k != public {
+		t.Fatalf("unexpected header %q", header)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d parts", len(parts))
+	}
+
+	claimsJSON, _ := decode(parts[1])
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Aud != "https://push.example.com" || claims.Sub != vapid.Subject || claims.Exp != now.Add(vapidExpiry).Unix() {
+		t.Errorf("claims %+v", claims)
+	}
+
+	pubBytes, _ := decode(public)
+	pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), pubBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, _ := decode(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if len(sig) != 64 || !ecdsa.Verify(pub, digest[:], r, s) {
+		t.Error("signature does not verify with the public key")
+	}
+}
+
+func TestSend(t *testing.T) {
+	public, private, _ := GenerateVAPIDKeys()
+	vapid := VAPID{PublicKey: public, PrivateKey: private, Subject: "mailto:ops@example.com"}
+
+	status := http.StatusCreated
+	var got []byte
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		got, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sub, key, auth := newBrowser(t, srv.URL+"/send/abc")
+	if err := Send(context.Background(), srv.Client(), sub, []byte("hello"), vapid, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if headers.Get("Content-Encoding") != "aes128gcm" || headers.Get("TTL") != "3600" ||
+		!strings.HasPrefix(headers.Get("Authorization"), "vapid t=") {
+		t.Errorf("unexpected headers %v", headers)
+	}
+	if string(decrypt(t, key, auth, got)) != "hello" {
+		t.Error("push service received a payload the browser cannot decrypt")
+	}
+
+	status = http.StatusGone
+	if err := Send(context.Background(), srv.Client(), sub, []byte("hello"), vapid, time.Hour); !errors.Is(err, ErrSubscriptionGone) {
+		t.Errorf("410: got %v, want ErrSubscriptionGone", err)
+	}
+	status = http.StatusBadRequest
+	if err := Send(context.Background(), srv.Client(), sub, []byte("hello"), vapid, time.Hour); err == nil {
+		t.Error("400: expected error")
+	}
+}
@@ -5,7 +5,7 @@
  * - Form validation
  * - State management
  * - API endpoints
- * - .env persistence
+ * - Persistence (encrypted secrets file, read back via the status API)
  * - URL generation
  *
  * We do NOT test:
@@ -15,17 +15,25 @@
  */
 
 import { test, expect, Page } from '@playwright/test';
-import * as fs from 'fs';
-import * as path from 'path';
 
 const WIZARD_URL = 'http://localhost:8080/tools/gcp-setup';
-const ENV_PATH = path.join(__dirname, '../../.env');
+
+// Values are stored encrypted (.env.secrets.local.age), so read them back
+// through the wizard API rather than from disk
+async function storedStatus(page: Page) {
+  const resp = await page.request.get('/api/gcp-setup/status');
+  expect(resp.ok()).toBeTruthy();
+  return resp.json();
+}
 
 test.describe('GCP Setup Wizard - Core Functionality', () => {
 
   test.beforeEach(async ({ page }) => {
-    // Reset .env before each test
-    await page.request.post('/api/gcp-setup/reset');
+    // Reset stored values before each test
+    await page.request.post('/api/gcp-setup/reset', {
+      headers: { 'Content-Type': 'application/json' },
+      data: '{}'
+    });
     await page.goto(WIZARD_URL);
   });
 
@@ -144,7 +152,7 @@ test.describe('GCP Setup Wizard - Core Functionality', () => {
 
   test.describe('State Persistence', () => {
 
-    test('saves project ID to .env file', async ({ page }) => {
+    test('saves project ID', async ({ page }) => {
       await page.fill('#projectId', 'persist-test-123');
       await page.fill('#projectName', 'Persist Test');
       await page.click('button:has-text("Save & Continue")');
@@ -152,12 +160,11 @@ test.describe('GCP Setup Wizard - Core Functionality', () => {
       // Wait for API call to complete
       await page.waitForTimeout(500);
 
-      // Verify .env file was updated
-      const envContent = fs.readFileSync(ENV_PATH, 'utf-8');
-      expect(envContent).toContain('GCP_PROJECT_ID=persist-test-123');
+      // Verify it was stored
+      expect((await storedStatus(page)).project_id).toBe('persist-test-123');
     });
 
-    test('pre-populates form from .env file on page load', async ({ page }) => {
+    test('pre-populates form from stored values on page load', async ({ page }) => {
       // Setup: Save via API
       await page.request.post('/api/gcp-setup/save-project', {
         headers: { 'Content-Type': 'application/json' },
@@ -175,7 +182,7 @@ test.describe('GCP Setup Wizard - Core Functionality', () => {
       await expect(page.locator('#status1')).toContainText('Done');
     });
 
-    test('persists OAuth credentials to .env', async ({ page }) => {
+    test('persists OAuth credentials', async ({ page }) => {
       // Setup steps 1-4 first
       await page.fill('#projectId', 'creds-test');
       await page.fill('#projectName', 'Creds Test');
@@ -194,10 +201,12 @@ test.describe('GCP Setup Wizard - Core Functionality', () => {
       // Wait for save
       await page.waitForTimeout(500);
 
-      // Verify .env file
-      const envContent = fs.readFileSync(ENV_PATH, 'utf-8');
-      expect(envContent).toContain('GOOGLE_CLIENT_ID=test-client-id');
-      expect(envContent).toContain('GOOGLE_CLIENT_SECRET=test-secret-123');
+      // Verify stored values
+      const stored = await storedStatus(page);
+      expect(stored.client_id).toBe('test-client-id.apps.googleusercontent.com');
+      expect(stored.client_secret).toBeUndefined(); // Never sent back
+      expect(stored.client_secret_configured).toBe(true);
+      expect(stored.creds_done).toBe(true);
     });
   });
 
@@ -222,13 +231,10 @@ test.describe('GCP Setup Wizard - Core Functionality', () => {
       await expect(page.locator('#projectName')).toHaveValue('');
       await expect(page.locator('#status1')).toContainText('Pending');
 
-      // Verify .env was cleared (file may not exist after reset)
-      if (fs.existsSync(ENV_PATH)) {
-        const envContent = fs.readFileSync(ENV_PATH, 'utf-8');
-        expect(envContent).not.toContain('GCP_PROJECT_ID=reset-test');
-      }
-      // File deletion is also acceptable
-      // expect(fs.existsSync(ENV_PATH)).toBe(false);
+      // Verify the stored values were cleared
+      const stored = await storedStatus(page);
+      expect(stored.project_id).toBe('');
+      expect(stored.project_done).toBe(false);
     });
   });
 
@@ -5,7 +5,7 @@
  * No hardcoded platform logic - everything driven by Go-generated test data + schema metadata.
  *
  * Architecture:
- * 1. Auto-discovers test suites from tests/e2e/generated/*.json
+ * 1. Auto-discovers test suites from tests/e2e/generated/index.json
  * 2. Uses schema metadata for validation (required fields, types, etc.)
  * 3. Platform-agnostic validation logic
  * 4. Works for Google Calendar, Apple Calendar, Maps, etc. with ZERO code changes!
@@ -63,21 +63,47 @@ interface GoTestSuite {
 // Auto-Discovery: Find all Go-generated test suites
 // ============================================================================
 
+interface ManifestEntry {
+  namespace: string;
+  platform: string;
+  app_type: string;
+  schema: string;
+  suite: string;
+  tests: number;
+  valid: number;
+  generator: boolean;
+  synthesized: boolean;
+  ui: boolean;
+}
+
+interface Manifest {
+  generated_at: string;
+  globs: string[];
+  suites: ManifestEntry[];
+}
+
 function discoverTestSuites(): GoTestSuite[] {
   const generatedDir = path.join(__dirname, 'generated');
+  const manifestPath = path.join(generatedDir, 'index.json');
 
-  if (!fs.existsSync(generatedDir)) {
-    throw new Error(`Generated test directory not found: ${generatedDir}\nRun: make gen-testdata`);
+  if (!fs.existsSync(manifestPath)) {
+    throw new Error(`Generated test index not found: ${manifestPath}\nRun: make gen-testdata`);
   }
 
-  const files = fs.readdirSync(generatedDir).filter(f => f.endsWith('-tests.json'));
+  const manifest: Manifest = JSON.parse(fs.readFileSync(manifestPath, 'utf-8'));
+
+  // Only schemas the web UI renders as a form can be driven through the browser
+  const entries = manifest.suites.filter(entry => entry.ui);
+  manifest.suites
+    .filter(entry => !entry.ui)
+    .forEach(entry => console.log(`⏭️  Skipped: ${entry.namespace} (no form)`));
 
-  if (files.length === 0) {
-    throw new Error('No generated test files found. Run: make gen-testdata');
+  if (entries.length === 0) {
+    throw new Error('No generated suites with a form found. Run: make gen-testdata');
   }
 
-  return files.map(filename => {
-    const filepath = path.join(generatedDir, filename);
+  return entries.map(entry => {
+    const filepath = path.join(generatedDir, entry.suite);
     const suite = JSON.parse(fs.readFileSync(filepath, 'utf-8'));
 
     console.log(`✅ Discovered: ${suite.platform}/${suite.app_type} (${suite.test_cases.length} tests)`);
@@ -81,8 +81,10 @@ export default defineConfig({
   ],
 
   /* Run your local dev server before starting the tests */
+  /* A stale process holding 8080 without answering is killed first (pkg/netutil) */
   webServer: {
-    command: 'make dev',
+    command: 'go run . port kill 8080 && make dev',
+    cwd: '..',
     url: 'http://localhost:8080',
     reuseExistingServer: !process.env.CI,
     timeout: 120 * 1000, // 2 minutes to start server